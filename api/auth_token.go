@@ -371,4 +371,6 @@ type TokenCreateRequest struct {
 	Renewable       *bool             `json:"renewable,omitempty"`
 	Type            string            `json:"type"`
 	EntityAlias     string            `json:"entity_alias"`
+
+	PathRestrictions map[string][]string `json:"path_restrictions,omitempty"`
 }
@@ -38,6 +38,7 @@ const (
 // contain templated fields.)
 var sudoPaths = map[string]*regexp.Regexp{
 	"/auth/token/accessors/":                        regexp.MustCompile(`^/auth/token/accessors/$`),
+	"/auth/token/accessors/stale":                   regexp.MustCompile(`^/auth/token/accessors/stale$`),
 	"/pki/root":                                     regexp.MustCompile(`^/pki/root$`),
 	"/pki/root/sign-self-issued":                    regexp.MustCompile(`^/pki/root/sign-self-issued$`),
 	"/sys/audit":                                    regexp.MustCompile(`^/sys/audit$`),
@@ -50,19 +51,30 @@ var sudoPaths = map[string]*regexp.Regexp{
 	"/sys/config/ui/headers/":                       regexp.MustCompile(`^/sys/config/ui/headers/$`),
 	"/sys/config/ui/headers/{header}":               regexp.MustCompile(`^/sys/config/ui/headers/.+$`),
 	"/sys/leases":                                   regexp.MustCompile(`^/sys/leases$`),
+	"/sys/leases/irrevocable":                       regexp.MustCompile(`^/sys/leases/irrevocable$`),
+	"/sys/leases/irrevocable/resolve":               regexp.MustCompile(`^/sys/leases/irrevocable/resolve$`),
+	"/sys/leases/irrevocable/retry":                 regexp.MustCompile(`^/sys/leases/irrevocable/retry$`),
 	"/sys/leases/lookup/":                           regexp.MustCompile(`^/sys/leases/lookup/$`),
 	"/sys/leases/lookup/{prefix}":                   regexp.MustCompile(`^/sys/leases/lookup/.+$`),
+	"/sys/leases/revoke-filtered":                   regexp.MustCompile(`^/sys/leases/revoke-filtered$`),
 	"/sys/leases/revoke-force/{prefix}":             regexp.MustCompile(`^/sys/leases/revoke-force/.+$`),
 	"/sys/leases/revoke-prefix/{prefix}":            regexp.MustCompile(`^/sys/leases/revoke-prefix/.+$`),
+	"/sys/leases/search":                            regexp.MustCompile(`^/sys/leases/search$`),
 	"/sys/plugins/catalog/{name}":                   regexp.MustCompile(`^/sys/plugins/catalog/[^/]+$`),
 	"/sys/plugins/catalog/{type}":                   regexp.MustCompile(`^/sys/plugins/catalog/[\w-]+$`),
 	"/sys/plugins/catalog/{type}/{name}":            regexp.MustCompile(`^/sys/plugins/catalog/[\w-]+/[^/]+$`),
+	"/sys/plugins/install":                          regexp.MustCompile(`^/sys/plugins/install$`),
+	"/sys/policy/simulate":                          regexp.MustCompile(`^/sys/policy/simulate$`),
 	"/sys/raw":                                      regexp.MustCompile(`^/sys/raw$`),
 	"/sys/raw/{path}":                               regexp.MustCompile(`^/sys/raw/.+$`),
 	"/sys/remount":                                  regexp.MustCompile(`^/sys/remount$`),
 	"/sys/revoke-force/{prefix}":                    regexp.MustCompile(`^/sys/revoke-force/.+$`),
 	"/sys/revoke-prefix/{prefix}":                   regexp.MustCompile(`^/sys/revoke-prefix/.+$`),
 	"/sys/rotate":                                   regexp.MustCompile(`^/sys/rotate$`),
+	"/sys/storage/migration":                        regexp.MustCompile(`^/sys/storage/migration$`),
+	"/sys/storage/migration/copy":                   regexp.MustCompile(`^/sys/storage/migration/copy$`),
+	"/sys/storage/migration/cutover":                regexp.MustCompile(`^/sys/storage/migration/cutover$`),
+	"/sys/storage/migration/verify":                 regexp.MustCompile(`^/sys/storage/migration/verify$`),
 	"/sys/internal/inspect/router/{tag}":            regexp.MustCompile(`^/sys/internal/inspect/router/.+$`),
 
 	// enterprise-only paths
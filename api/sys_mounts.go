@@ -309,13 +309,19 @@ type UserLockoutConfigInput struct {
 	LockoutDuration             string `json:"lockout_duration,omitempty" structs:"lockout_duration" mapstructure:"lockout_duration"`
 	LockoutCounterResetDuration string `json:"lockout_counter_reset_duration,omitempty" structs:"lockout_counter_reset_duration" mapstructure:"lockout_counter_reset_duration"`
 	DisableLockout              *bool  `json:"lockout_disable,omitempty" structs:"lockout_disable" mapstructure:"lockout_disable"`
+	ProgressiveDelay            *bool  `json:"progressive_delay,omitempty" structs:"progressive_delay" mapstructure:"progressive_delay"`
+	ProgressiveDelayBase        string `json:"progressive_delay_base,omitempty" structs:"progressive_delay_base" mapstructure:"progressive_delay_base"`
+	ProgressiveDelayMax         string `json:"progressive_delay_max,omitempty" structs:"progressive_delay_max" mapstructure:"progressive_delay_max"`
 }
 
 type UserLockoutConfigOutput struct {
-	LockoutThreshold    uint  `json:"lockout_threshold,omitempty" structs:"lockout_threshold" mapstructure:"lockout_threshold"`
-	LockoutDuration     int   `json:"lockout_duration,omitempty" structs:"lockout_duration" mapstructure:"lockout_duration"`
-	LockoutCounterReset int   `json:"lockout_counter_reset,omitempty" structs:"lockout_counter_reset" mapstructure:"lockout_counter_reset"`
-	DisableLockout      *bool `json:"disable_lockout,omitempty" structs:"disable_lockout" mapstructure:"disable_lockout"`
+	LockoutThreshold     uint  `json:"lockout_threshold,omitempty" structs:"lockout_threshold" mapstructure:"lockout_threshold"`
+	LockoutDuration      int   `json:"lockout_duration,omitempty" structs:"lockout_duration" mapstructure:"lockout_duration"`
+	LockoutCounterReset  int   `json:"lockout_counter_reset,omitempty" structs:"lockout_counter_reset" mapstructure:"lockout_counter_reset"`
+	DisableLockout       *bool `json:"disable_lockout,omitempty" structs:"disable_lockout" mapstructure:"disable_lockout"`
+	ProgressiveDelay     *bool `json:"progressive_delay,omitempty" structs:"progressive_delay" mapstructure:"progressive_delay"`
+	ProgressiveDelayBase int   `json:"progressive_delay_base,omitempty" structs:"progressive_delay_base" mapstructure:"progressive_delay_base"`
+	ProgressiveDelayMax  int   `json:"progressive_delay_max,omitempty" structs:"progressive_delay_max" mapstructure:"progressive_delay_max"`
 }
 
 type MountMigrationOutput struct {
@@ -146,6 +146,12 @@ type GetPluginResponse struct {
 	SHA256            string   `json:"sha256"`
 	DeprecationStatus string   `json:"deprecation_status,omitempty"`
 	Version           string   `json:"version,omitempty"`
+	MemoryLimit       int64    `json:"memory_limit,omitempty"`
+	CPUQuota          int64    `json:"cpu_quota,omitempty"`
+	UID               int      `json:"uid,omitempty"`
+	GID               int      `json:"gid,omitempty"`
+	RestrictEnv       bool     `json:"restrict_env,omitempty"`
+	SeccompProfile    string   `json:"seccomp_profile,omitempty"`
 }
 
 // GetPlugin wraps GetPluginWithContext using context.Background.
@@ -199,6 +205,25 @@ type RegisterPluginInput struct {
 
 	// Version is the optional version of the plugin being registered
 	Version string `json:"version,omitempty"`
+
+	// MemoryLimit is the optional memory the processes of the plugin may use
+	// together, in bytes or with a unit such as "512MiB".
+	MemoryLimit string `json:"memory_limit,omitempty"`
+
+	// CPUQuota is the optional CPU time the processes of the plugin may use
+	// together, as a percentage of one CPU.
+	CPUQuota int `json:"cpu_quota,omitempty"`
+
+	// UID and GID are the optional user and group the plugin runs as.
+	UID int `json:"uid,omitempty"`
+	GID int `json:"gid,omitempty"`
+
+	// RestrictEnv prevents the plugin from inheriting the environment of Vault.
+	RestrictEnv bool `json:"restrict_env,omitempty"`
+
+	// SeccompProfile is the optional path, relative to the plugin directory,
+	// of a compiled seccomp BPF program to load before running the plugin.
+	SeccompProfile string `json:"seccomp_profile,omitempty"`
 }
 
 // RegisterPlugin wraps RegisterPluginWithContext using context.Background.
@@ -225,6 +250,62 @@ func (c *Sys) RegisterPluginWithContext(ctx context.Context, i *RegisterPluginIn
 	return err
 }
 
+// InstallPluginInput is used as input to the InstallPlugin function.
+type InstallPluginInput struct {
+	// Bundle is the file name of the plugin bundle in the plugin bundle
+	// directory of the server. Required.
+	Bundle string `json:"bundle"`
+}
+
+// InstallPluginResponse is the response of the InstallPlugin function.
+type InstallPluginResponse struct {
+	// Name is the name of the installed plugin.
+	Name string `mapstructure:"name"`
+
+	// Type is the type of the installed plugin.
+	Type string `mapstructure:"type"`
+
+	// Versions are the versions of the plugin installed and registered.
+	Versions []string `mapstructure:"versions"`
+}
+
+// InstallPlugin wraps InstallPluginWithContext using context.Background.
+func (c *Sys) InstallPlugin(i *InstallPluginInput) (*InstallPluginResponse, error) {
+	return c.InstallPluginWithContext(context.Background(), i)
+}
+
+// InstallPluginWithContext installs the versions of a plugin from a signed
+// plugin bundle and registers them in the catalog.
+func (c *Sys) InstallPluginWithContext(ctx context.Context, i *InstallPluginInput) (*InstallPluginResponse, error) {
+	ctx, cancelFunc := c.c.withConfiguredTimeout(ctx)
+	defer cancelFunc()
+
+	req := c.c.NewRequest(http.MethodPut, "/v1/sys/plugins/install")
+	if err := req.SetJSONBody(i); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.c.rawRequestWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	secret, err := ParseSecret(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, errors.New("data from server response is empty")
+	}
+
+	var result InstallPluginResponse
+	if err := mapstructure.Decode(secret.Data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // DeregisterPluginInput is used as input to the DeregisterPlugin function.
 type DeregisterPluginInput struct {
 	// Name is the name of the plugin. Required.
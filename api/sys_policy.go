@@ -132,3 +132,86 @@ type getPoliciesResp struct {
 type listPoliciesResp struct {
 	Policies []string `json:"policies"`
 }
+
+// SimulatePolicyInput describes the subject and the request that should be
+// evaluated by SimulatePolicy. Exactly one of Token, Accessor, EntityID or
+// Policies should be set.
+type SimulatePolicyInput struct {
+	Token      string                 `json:"token,omitempty"`
+	Accessor   string                 `json:"accessor,omitempty"`
+	EntityID   string                 `json:"entity_id,omitempty"`
+	Policies   []string               `json:"policies,omitempty"`
+	Path       string                 `json:"path"`
+	Operation  string                 `json:"operation,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	WrapTTL    string                 `json:"wrap_ttl,omitempty"`
+
+	RemoteAddress string `json:"remote_address,omitempty"`
+}
+
+type SimulatePolicyOutput struct {
+	Allowed          bool                    `mapstructure:"allowed"`
+	Root             bool                    `mapstructure:"root"`
+	SudoRequired     bool                    `mapstructure:"sudo_required"`
+	Sudo             bool                    `mapstructure:"sudo"`
+	MatchedPath      string                  `mapstructure:"matched_path"`
+	DenialReason     string                  `mapstructure:"denial_reason"`
+	DeniedParameter  string                  `mapstructure:"denied_parameter"`
+	Capabilities     []string                `mapstructure:"capabilities"`
+	GrantingPolicies []*SimulatePolicyGrant  `mapstructure:"granting_policies"`
+	Policies         []*SimulatePolicyResult `mapstructure:"policies"`
+}
+
+type SimulatePolicyGrant struct {
+	Name        string `mapstructure:"name"`
+	NamespaceID string `mapstructure:"namespace_id"`
+	Type        string `mapstructure:"type"`
+}
+
+type SimulatePolicyResult struct {
+	Name            string   `mapstructure:"name"`
+	NamespacePath   string   `mapstructure:"namespace_path"`
+	MatchedPath     string   `mapstructure:"matched_path"`
+	Capabilities    []string `mapstructure:"capabilities"`
+	Allowed         bool     `mapstructure:"allowed"`
+	DenialReason    string   `mapstructure:"denial_reason"`
+	DeniedParameter string   `mapstructure:"denied_parameter"`
+}
+
+// SimulatePolicy explains whether the given request would be allowed by the
+// policies of a token, an entity or a set of named policies.
+func (c *Sys) SimulatePolicy(input *SimulatePolicyInput) (*SimulatePolicyOutput, error) {
+	return c.SimulatePolicyWithContext(context.Background(), input)
+}
+
+func (c *Sys) SimulatePolicyWithContext(ctx context.Context, input *SimulatePolicyInput) (*SimulatePolicyOutput, error) {
+	ctx, cancelFunc := c.c.withConfiguredTimeout(ctx)
+	defer cancelFunc()
+
+	r := c.c.NewRequest(http.MethodPost, "/v1/sys/policy/simulate")
+	if err := r.SetJSONBody(input); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.c.rawRequestWithContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	secret, err := ParseSecret(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, errors.New("data from server response is empty")
+	}
+
+	var result SimulatePolicyOutput
+	err = mapstructure.Decode(secret.Data, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -18,9 +18,14 @@ import (
 	"github.com/hashicorp/vault/sdk/helper/locksutil"
 	"github.com/hashicorp/vault/sdk/helper/policyutil"
 	"github.com/hashicorp/vault/sdk/helper/tokenutil"
+	"github.com/hashicorp/vault/sdk/helper/wrapping"
 	"github.com/hashicorp/vault/sdk/logical"
 )
 
+// defaultPushWrapTTL is the default TTL in seconds of the response-wrapping
+// token SecretIDs pushed to trusted orchestrators are returned in
+const defaultPushWrapTTL = 60
+
 // roleStorageEntry stores all the options that are set on an role
 type roleStorageEntry struct {
 	tokenutil.TokenParams
@@ -77,6 +82,12 @@ type roleStorageEntry struct {
 	// SecretIDPrefix is the storage prefix for persisting secret IDs. This
 	// differs based on whether the secret IDs are cluster local or not.
 	SecretIDPrefix string `json:"secret_id_prefix" mapstructure:"secret_id_prefix"`
+
+	// TrustedOrchestratorEntityIDs and TrustedOrchestratorPolicies identify
+	// the callers allowed to push SecretIDs for this role using
+	// 'role/<role_name>/secret-id/push'
+	TrustedOrchestratorEntityIDs []string `json:"trusted_orchestrator_entity_ids" mapstructure:"trusted_orchestrator_entity_ids"`
+	TrustedOrchestratorPolicies  []string `json:"trusted_orchestrator_policies" mapstructure:"trusted_orchestrator_policies"`
 }
 
 // roleIDStorageEntry represents the reverse mapping from RoleID to Role
@@ -101,6 +112,7 @@ type roleIDStorageEntry struct {
 // role/<role_name>/role-id - For fetching the role_id of an role
 // role/<role_name>/secret-id - For issuing a secret_id against an role, also to list the secret_id_accessors
 // role/<role_name>/custom-secret-id - For assigning a custom SecretID against an role
+// role/<role_name>/secret-id/push - For pushing a response-wrapped secret_id as a trusted orchestrator
 // role/<role_name>/secret-id/lookup - For reading the properties of a secret_id
 // role/<role_name>/secret-id/destroy - For deleting a secret_id
 // role/<role_name>/secret-id-accessor/lookup - For reading secret_id using accessor
@@ -167,6 +179,19 @@ to 0, meaning no expiration.`,
 				Description: `If set, the secret IDs generated using this role will be cluster local. This
 can only be set during role creation and once set, it can't be reset later.`,
 			},
+
+			"trusted_orchestrator_entity_ids": {
+				Type: framework.TypeCommaStringSlice,
+				Description: `Comma separated string or list of entity IDs allowed to push SecretIDs for
+this role using 'role/<role_name>/secret-id/push'.`,
+			},
+
+			"trusted_orchestrator_policies": {
+				Type: framework.TypeCommaStringSlice,
+				Description: `Comma separated string or list of policies. Tokens carrying any of these
+policies are allowed to push SecretIDs for this role using
+'role/<role_name>/secret-id/push'.`,
+			},
 		},
 		ExistenceCheck: b.pathRoleExistenceCheck,
 		Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -499,6 +524,55 @@ Overrides secret_id_ttl role option when supplied. May not be longer than role's
 			HelpSynopsis:    strings.TrimSpace(roleHelp["role-secret-id"][0]),
 			HelpDescription: strings.TrimSpace(roleHelp["role-secret-id"][1]),
 		},
+		{
+			Pattern: "role/" + framework.GenericNameRegex("role_name") + "/secret-id/push/?$",
+			Fields: map[string]*framework.FieldSchema{
+				"role_name": {
+					Type:        framework.TypeString,
+					Description: fmt.Sprintf("Name of the role. Must be less than %d bytes.", maxHmacInputLength),
+				},
+				"instance_id": {
+					Type: framework.TypeString,
+					Description: `Identifier of the instance the SecretID is delivered to. It is recorded on
+the SecretID and added to its metadata.`,
+				},
+				"metadata": {
+					Type: framework.TypeString,
+					Description: `Metadata to be tied to the SecretID. This should be a JSON
+formatted string containing the metadata in key value pairs.`,
+				},
+				"cidr_list": {
+					Type: framework.TypeCommaStringSlice,
+					Description: `Comma separated string or list of CIDR blocks the SecretID can be used from.
+Required. If 'secret_id_bound_cidrs' is set on the role, then the list of CIDR
+blocks listed here should be a subset of the CIDR blocks listed on the role.`,
+				},
+				"token_bound_cidrs": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: defTokenFields["token_bound_cidrs"].Description,
+				},
+				"num_uses": {
+					Type: framework.TypeInt,
+					Description: `Number of times this SecretID can be used, after which the SecretID expires.
+Overrides secret_id_num_uses role option when supplied. May not be higher than role's secret_id_num_uses.`,
+				},
+				"ttl": {
+					Type: framework.TypeDurationSecond,
+					Description: `Duration in seconds after which this SecretID expires.
+Overrides secret_id_ttl role option when supplied. May not be longer than role's secret_id_ttl.`,
+				},
+				"wrap_ttl": {
+					Type:        framework.TypeDurationSecond,
+					Default:     defaultPushWrapTTL,
+					Description: "Duration in seconds of the response-wrapping token the SecretID is returned in. Defaults to 60 seconds.",
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.UpdateOperation: b.pathRoleSecretIDPushUpdate,
+			},
+			HelpSynopsis:    strings.TrimSpace(roleHelp["role-secret-id-push"][0]),
+			HelpDescription: strings.TrimSpace(roleHelp["role-secret-id-push"][1]),
+		},
 		{
 			Pattern: "role/" + framework.GenericNameRegex("role_name") + "/secret-id/lookup/?$",
 			Fields: map[string]*framework.FieldSchema{
@@ -992,6 +1066,14 @@ func (b *backend) pathRoleCreateUpdate(ctx context.Context, req *logical.Request
 		role.SecretIDTTL = time.Second * time.Duration(data.Get("secret_id_ttl").(int))
 	}
 
+	if entityIDsRaw, ok := data.GetOk("trusted_orchestrator_entity_ids"); ok {
+		role.TrustedOrchestratorEntityIDs = strutil.RemoveDuplicates(entityIDsRaw.([]string), false)
+	}
+
+	if policiesRaw, ok := data.GetOk("trusted_orchestrator_policies"); ok {
+		role.TrustedOrchestratorPolicies = policyutil.SanitizePolicies(policiesRaw.([]string), policyutil.DoNotAddDefaultPolicy)
+	}
+
 	// handle upgrade cases
 	{
 		if err := tokenutil.UpgradeValue(data, "policies", "token_policies", &role.Policies, &role.TokenPolicies); err != nil {
@@ -1047,6 +1129,12 @@ func (b *backend) pathRoleRead(ctx context.Context, req *logical.Request, data *
 		"secret_id_ttl":         role.SecretIDTTL / time.Second,
 		"local_secret_ids":      false,
 	}
+	if len(role.TrustedOrchestratorEntityIDs) > 0 {
+		respData["trusted_orchestrator_entity_ids"] = role.TrustedOrchestratorEntityIDs
+	}
+	if len(role.TrustedOrchestratorPolicies) > 0 {
+		respData["trusted_orchestrator_policies"] = role.TrustedOrchestratorPolicies
+	}
 	role.PopulateTokenData(respData)
 
 	if role.SecretIDPrefix == secretIDLocalPrefix {
@@ -1230,6 +1318,11 @@ func (entry *secretIDStorageEntry) ToResponseData() map[string]interface{} {
 	if len(entry.TokenBoundCIDRs) == 0 {
 		ret["token_bound_cidrs"] = []string{}
 	}
+	if entry.InstanceID != "" {
+		ret["instance_id"] = entry.InstanceID
+		ret["orchestrator_entity_id"] = entry.OrchestratorEntityID
+		ret["orchestrator_token_accessor"] = entry.OrchestratorTokenAccessor
+	}
 	return ret
 }
 
@@ -2310,14 +2403,41 @@ func (b *backend) pathRoleSecretIDUpdate(ctx context.Context, req *logical.Reque
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate secret_id: %w", err)
 	}
-	return b.handleRoleSecretIDCommon(ctx, req, data, secretID)
+	return b.handleRoleSecretIDCommon(ctx, req, data, secretID, false)
 }
 
 func (b *backend) pathRoleCustomSecretIDUpdate(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	return b.handleRoleSecretIDCommon(ctx, req, data, data.Get("secret_id").(string))
+	return b.handleRoleSecretIDCommon(ctx, req, data, data.Get("secret_id").(string), false)
+}
+
+// pathRoleSecretIDPushUpdate issues a SecretID to a trusted orchestrator on
+// behalf of an instance. The SecretID is stamped with the instance ID and the
+// orchestrator's identity, and is always returned response-wrapped.
+func (b *backend) pathRoleSecretIDPushUpdate(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	secretID, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret_id: %w", err)
+	}
+	return b.handleRoleSecretIDCommon(ctx, req, data, secretID, true)
+}
+
+// isTrustedOrchestrator returns whether the caller is allowed to push
+// SecretIDs for the role, either by its entity or by a token policy.
+func isTrustedOrchestrator(req *logical.Request, role *roleStorageEntry) bool {
+	if req.EntityID != "" && strutil.StrListContains(role.TrustedOrchestratorEntityIDs, req.EntityID) {
+		return true
+	}
+	if te := req.TokenEntry(); te != nil {
+		for _, policy := range te.Policies {
+			if strutil.StrListContains(role.TrustedOrchestratorPolicies, policy) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
-func (b *backend) handleRoleSecretIDCommon(ctx context.Context, req *logical.Request, data *framework.FieldData, secretID string) (*logical.Response, error) {
+func (b *backend) handleRoleSecretIDCommon(ctx context.Context, req *logical.Request, data *framework.FieldData, secretID string, push bool) (*logical.Response, error) {
 	roleName := data.Get("role_name").(string)
 	if roleName == "" {
 		return logical.ErrorResponse("missing role_name"), nil
@@ -2343,6 +2463,29 @@ func (b *backend) handleRoleSecretIDCommon(ctx context.Context, req *logical.Req
 		return logical.ErrorResponse("bind_secret_id is not set on the role"), nil
 	}
 
+	var instanceID string
+	var wrapTTL time.Duration
+	if push {
+		if len(role.TrustedOrchestratorEntityIDs) == 0 && len(role.TrustedOrchestratorPolicies) == 0 {
+			return logical.ErrorResponse("no trusted orchestrator is configured on the role"), logical.ErrPermissionDenied
+		}
+		if !isTrustedOrchestrator(req, role) {
+			return logical.ErrorResponse("caller is not a trusted orchestrator for the role"), logical.ErrPermissionDenied
+		}
+
+		instanceID = data.Get("instance_id").(string)
+		if instanceID == "" {
+			return logical.ErrorResponse("missing instance_id"), nil
+		}
+		if len(data.Get("cidr_list").([]string)) == 0 {
+			return logical.ErrorResponse("missing cidr_list"), nil
+		}
+		wrapTTL = time.Duration(data.Get("wrap_ttl").(int)) * time.Second
+		if wrapTTL <= 0 {
+			return logical.ErrorResponse("wrap_ttl must be positive"), nil
+		}
+	}
+
 	secretIDCIDRs := data.Get("cidr_list").([]string)
 
 	// Validate the list of CIDR blocks
@@ -2420,6 +2563,13 @@ func (b *backend) handleRoleSecretIDCommon(ctx context.Context, req *logical.Req
 		return logical.ErrorResponse(fmt.Sprintf("failed to parse metadata: %v", err)), nil
 	}
 
+	if push {
+		secretIDStorage.Metadata["instance_id"] = instanceID
+		secretIDStorage.InstanceID = instanceID
+		secretIDStorage.OrchestratorEntityID = req.EntityID
+		secretIDStorage.OrchestratorTokenAccessor = req.ClientTokenAccessor
+	}
+
 	if secretIDStorage, err = b.registerSecretIDEntry(ctx, req.Storage, role.name, secretID, role.HMACKey, role.SecretIDPrefix, secretIDStorage); err != nil {
 		return nil, fmt.Errorf("failed to store secret_id: %w", err)
 	}
@@ -2433,6 +2583,16 @@ func (b *backend) handleRoleSecretIDCommon(ctx context.Context, req *logical.Req
 		},
 	}
 
+	if push {
+		resp.Data["instance_id"] = instanceID
+		// The wrapping token can only be unwrapped from where the SecretID
+		// can be used
+		resp.WrapInfo = &wrapping.ResponseWrapInfo{
+			TTL:        wrapTTL,
+			BoundCIDRs: secretIDCIDRs,
+		}
+	}
+
 	return resp, nil
 }
 
@@ -2650,6 +2810,16 @@ the role. This SecretID will behave similarly to the SecretIDs generated by
 the backend. The properties of this SecretID will be based on the options
 set on the role. It will expire after a period defined by the 'ttl' field
 or 'secret_id_ttl' option on the role, and/or the backend mount's maximum TTL value.`,
+	},
+	"role-secret-id-push": {
+		"Push a response-wrapped SecretID to an instance as a trusted orchestrator.",
+		`Only callers whose entity is listed in 'trusted_orchestrator_entity_ids', or
+whose token carries a policy listed in 'trusted_orchestrator_policies', can use
+this endpoint. The SecretID is bound to the given 'cidr_list', records the
+'instance_id' it is delivered to along with the orchestrator's entity ID and
+token accessor, and adds 'instance_id' to its metadata so that tokens issued
+with it can be traced to the instance. The response is always wrapped; the
+orchestrator hands the wrapping token to the instance, which unwraps it.`,
 	},
 	"role-period": {
 		"Updates the value of 'period' on the role",
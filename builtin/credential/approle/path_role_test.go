@@ -2419,3 +2419,122 @@ func TestAppRole_SecretID_WithTTL(t *testing.T) {
 		})
 	}
 }
+
+func TestAppRole_SecretIDPush(t *testing.T) {
+	b, storage := createBackendWithStorage(t)
+
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Path:      "role/web",
+		Operation: logical.CreateOperation,
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"secret_id_bound_cidrs":           "10.0.0.0/8",
+			"trusted_orchestrator_entity_ids": "orchestrator-entity",
+			"trusted_orchestrator_policies":   "Deployer",
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	resp, err = b.HandleRequest(context.Background(), &logical.Request{
+		Path:      "role/web",
+		Operation: logical.ReadOperation,
+		Storage:   storage,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+	if diff := deep.Equal(resp.Data["trusted_orchestrator_entity_ids"], []string{"orchestrator-entity"}); diff != nil {
+		t.Fatal(diff)
+	}
+	if diff := deep.Equal(resp.Data["trusted_orchestrator_policies"], []string{"deployer"}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	pushReq := func(entityID string, policies []string, data map[string]interface{}) *logical.Request {
+		req := &logical.Request{
+			Path:                "role/web/secret-id/push",
+			Operation:           logical.UpdateOperation,
+			Storage:             storage,
+			EntityID:            entityID,
+			ClientTokenAccessor: "orchestrator-accessor",
+			Data:                data,
+		}
+		req.SetTokenEntry(&logical.TokenEntry{Policies: policies})
+		return req
+	}
+	pushData := map[string]interface{}{
+		"instance_id": "i-0123456789",
+		"cidr_list":   "10.1.2.3/32",
+		"metadata":    `{"deployment": "web-42"}`,
+	}
+
+	// Callers that are neither a trusted entity nor carry a trusted policy
+	// are rejected
+	resp, err = b.HandleRequest(context.Background(), pushReq("other-entity", []string{"default"}, pushData))
+	if err != logical.ErrPermissionDenied {
+		t.Fatalf("expected permission denied, got err:%v resp:%#v", err, resp)
+	}
+
+	// The instance ID and CIDR binding are required
+	resp, err = b.HandleRequest(context.Background(), pushReq("orchestrator-entity", nil, map[string]interface{}{
+		"cidr_list": "10.1.2.3/32",
+	}))
+	if err != nil || resp == nil || !resp.IsError() {
+		t.Fatalf("expected missing instance_id error, got err:%v resp:%#v", err, resp)
+	}
+	resp, err = b.HandleRequest(context.Background(), pushReq("orchestrator-entity", nil, map[string]interface{}{
+		"instance_id": "i-0123456789",
+	}))
+	if err != nil || resp == nil || !resp.IsError() {
+		t.Fatalf("expected missing cidr_list error, got err:%v resp:%#v", err, resp)
+	}
+
+	// A trusted policy is enough to push
+	resp, err = b.HandleRequest(context.Background(), pushReq("", []string{"default", "deployer"}, pushData))
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	// As is a trusted entity; the response is always wrapped
+	resp, err = b.HandleRequest(context.Background(), pushReq("orchestrator-entity", []string{"default"}, pushData))
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+	if resp.WrapInfo == nil || resp.WrapInfo.TTL != 60*time.Second {
+		t.Fatalf("expected response to be wrapped for 60s, got %#v", resp.WrapInfo)
+	}
+	// The wrapping token is bound to the same CIDRs as the SecretID
+	if !reflect.DeepEqual(resp.WrapInfo.BoundCIDRs, []string{"10.1.2.3/32"}) {
+		t.Fatalf("expected the wrapping token to be bound to the cidr_list, got %#v", resp.WrapInfo.BoundCIDRs)
+	}
+	secretID := resp.Data["secret_id"].(string)
+
+	resp, err = b.HandleRequest(context.Background(), &logical.Request{
+		Path:      "role/web/secret-id/lookup",
+		Operation: logical.UpdateOperation,
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"secret_id": secretID,
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+	expected := map[string]interface{}{
+		"instance_id":                 "i-0123456789",
+		"orchestrator_entity_id":      "orchestrator-entity",
+		"orchestrator_token_accessor": "orchestrator-accessor",
+		"cidr_list":                   []string{"10.1.2.3/32"},
+		"metadata": map[string]string{
+			"deployment":  "web-42",
+			"instance_id": "i-0123456789",
+		},
+	}
+	for k, v := range expected {
+		if diff := deep.Equal(resp.Data[k], v); diff != nil {
+			t.Fatalf("%s: %v", k, diff)
+		}
+	}
+}
@@ -56,6 +56,12 @@ type secretIDStorageEntry struct {
 	// restrictions on the usage of the token generated by this SecretID
 	TokenBoundCIDRs []string `json:"token_cidr_list" mapstructure:"token_bound_cidrs"`
 
+	// InstanceID is the instance a trusted orchestrator pushed this
+	// SecretID to, with the orchestrator's entity ID and token accessor
+	InstanceID                string `json:"instance_id,omitempty" mapstructure:"instance_id"`
+	OrchestratorEntityID      string `json:"orchestrator_entity_id,omitempty" mapstructure:"orchestrator_entity_id"`
+	OrchestratorTokenAccessor string `json:"orchestrator_token_accessor,omitempty" mapstructure:"orchestrator_token_accessor"`
+
 	// This is a deprecated field
 	SecretIDNumUsesDeprecated int `json:"SecretIDNumUses" mapstructure:"SecretIDNumUses"`
 }
@@ -3,19 +3,24 @@ package cert
 import (
 	"context"
 	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/ocsp"
 	"github.com/hashicorp/vault/sdk/logical"
+	"golang.org/x/sync/singleflight"
 )
 
 func Factory(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
@@ -60,6 +65,7 @@ func Backend() *backend {
 	}
 
 	b.crlUpdateMutex = &sync.RWMutex{}
+	b.roleCRLs = map[string]*fetchedCRL{}
 	return &b
 }
 
@@ -72,6 +78,50 @@ type backend struct {
 	ocspClientMutex sync.RWMutex
 	ocspClient      *ocsp.Client
 	configUpdated   atomic.Bool
+
+	// roleCRLs caches CRLs fetched on behalf of cert roles, keyed by URL.
+	// Unlike the CRLs managed via crls/, these are never persisted, and they
+	// are dropped once no role references them and no login has used them
+	// within their refresh interval.
+	roleCRLs     map[string]*fetchedCRL
+	roleCRLMutex sync.Mutex
+
+	// roleCRLFetches deduplicates concurrent downloads of the same role CRL,
+	// keyed by URL.
+	roleCRLFetches singleflight.Group
+}
+
+const (
+	// crlFetchTimeout bounds how long a single CRL download may take.
+	crlFetchTimeout = 30 * time.Second
+
+	// maxCRLSize is the largest CRL body we're willing to download.
+	maxCRLSize = 32 * 1024 * 1024
+
+	// defaultCRLRefreshInterval is used for roles which fetch CRLs but don't
+	// specify crl_refresh_interval.
+	defaultCRLRefreshInterval = time.Hour
+)
+
+// fetchedCRL is a CRL retrieved from one of a role's crl_urls or from a
+// client certificate's CRL distribution points.
+type fetchedCRL struct {
+	crl             *x509.RevocationList
+	fetchedAt       time.Time
+	refreshInterval time.Duration
+	lastUsed        time.Time
+}
+
+// expired reports whether the CRL is past its NextUpdate time and so
+// shouldn't be relied upon any longer.
+func (f *fetchedCRL) expired(now time.Time) bool {
+	return !f.crl.NextUpdate.IsZero() && now.After(f.crl.NextUpdate)
+}
+
+// stale reports whether the CRL should be fetched again, either because it
+// has expired or because the refresh interval has elapsed.
+func (f *fetchedCRL) stale(now time.Time, refreshInterval time.Duration) bool {
+	return f.expired(now) || now.After(f.fetchedAt.Add(refreshInterval))
 }
 
 func (b *backend) invalidate(_ context.Context, key string) {
@@ -100,29 +150,208 @@ func (b *backend) updatedConfig(config *config) error {
 }
 
 func (b *backend) fetchCRL(ctx context.Context, storage logical.Storage, name string, crl *CRLInfo) error {
-	response, err := http.Get(crl.CDP.Url)
+	body, err := downloadCRL(ctx, crl.CDP.Url)
 	if err != nil {
 		return err
 	}
-	if response.StatusCode == http.StatusOK {
-		body, err := io.ReadAll(response.Body)
+	certList, err := x509.ParseCRL(body)
+	if err != nil {
+		return err
+	}
+	crl.CDP.ValidUntil = certList.TBSCertList.NextUpdate
+	return b.setCRL(ctx, storage, certList, name, crl.CDP)
+}
+
+// downloadCRL retrieves the raw CRL published at the given URL.
+func downloadCRL(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, crlFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := cleanhttp.DefaultClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response code %d fetching CRL from %s", response.StatusCode, url)
+	}
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxCRLSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxCRLSize {
+		return nil, fmt.Errorf("CRL from %s exceeds the maximum size of %d bytes", url, maxCRLSize)
+	}
+	return body, nil
+}
+
+// parseRevocationList parses a CRL which may be either DER or PEM encoded.
+func parseRevocationList(raw []byte) (*x509.RevocationList, error) {
+	if block, _ := pem.Decode(raw); block != nil && block.Type == "X509 CRL" {
+		raw = block.Bytes
+	}
+	return x509.ParseRevocationList(raw)
+}
+
+// roleCRL returns the CRL published at url, fetching it if it hasn't been
+// retrieved yet or the cached copy is stale. If a refresh fails, a cached
+// copy that hasn't yet expired is returned instead.
+func (b *backend) roleCRL(ctx context.Context, url string, refreshInterval time.Duration) (*x509.RevocationList, error) {
+	b.roleCRLMutex.Lock()
+	cached := b.roleCRLs[url]
+	b.roleCRLMutex.Unlock()
+
+	now := time.Now()
+	defer b.markRoleCRLUsed(url, now)
+	if cached != nil && !cached.stale(now, refreshInterval) {
+		return cached.crl, nil
+	}
+
+	crl, err := b.refreshRoleCRL(ctx, url, refreshInterval)
+	if err != nil {
+		if cached != nil && !cached.expired(now) {
+			b.Logger().Warn("failed to refresh CRL, using cached copy", "url", url, "error", err)
+			return cached.crl, nil
+		}
+		return nil, err
+	}
+	return crl, nil
+}
+
+// markRoleCRLUsed records that a login used the cached CRL of url.
+func (b *backend) markRoleCRLUsed(url string, now time.Time) {
+	b.roleCRLMutex.Lock()
+	defer b.roleCRLMutex.Unlock()
+	if cached := b.roleCRLs[url]; cached != nil && cached.lastUsed.Before(now) {
+		cached.lastUsed = now
+	}
+}
+
+// validateCRLURL checks that a CRL URL can be fetched by the backend. Only
+// http and https URLs are accepted, as distribution points may also use
+// schemes such as ldap which aren't supported.
+func validateCRLURL(crlURL string) error {
+	u, err := url.Parse(crlURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q, must be http or https", u.Scheme)
+	}
+	if u.Host == "" {
+		return errors.New("missing host")
+	}
+	return nil
+}
+
+// refreshRoleCRL fetches the CRL published at url and caches it. Concurrent
+// refreshes of the same URL share a single download.
+func (b *backend) refreshRoleCRL(ctx context.Context, url string, refreshInterval time.Duration) (*x509.RevocationList, error) {
+	if err := validateCRLURL(url); err != nil {
+		return nil, fmt.Errorf("invalid CRL url %q: %w", url, err)
+	}
+
+	crl, err, _ := b.roleCRLFetches.Do(url, func() (interface{}, error) {
+		body, err := downloadCRL(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		crl, err := parseRevocationList(body)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("failed to parse CRL from %s: %w", url, err)
+		}
+
+		b.roleCRLMutex.Lock()
+		defer b.roleCRLMutex.Unlock()
+		fetched := &fetchedCRL{
+			crl:             crl,
+			fetchedAt:       time.Now(),
+			refreshInterval: refreshInterval,
+		}
+		if cached := b.roleCRLs[url]; cached != nil {
+			fetched.lastUsed = cached.lastUsed
+		}
+		b.roleCRLs[url] = fetched
+		return crl, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return crl.(*x509.RevocationList), nil
+}
+
+// updateRoleCRLs refreshes any cached role CRLs which have gone stale, so
+// that logins don't usually have to wait on a download. CRLs whose URL is not
+// in the crl_urls of any role and that no login used within their refresh
+// interval are dropped instead, so that deleted roles and distribution points
+// seen only in past logins are not fetched forever.
+func (b *backend) updateRoleCRLs(ctx context.Context, s logical.Storage) error {
+	b.roleCRLMutex.Lock()
+	empty := len(b.roleCRLs) == 0
+	b.roleCRLMutex.Unlock()
+	if empty {
+		return nil
+	}
+
+	configured, err := b.configuredRoleCRLURLs(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	stale := map[string]time.Duration{}
+	b.roleCRLMutex.Lock()
+	for url, cached := range b.roleCRLs {
+		if _, ok := configured[url]; !ok && now.After(cached.lastUsed.Add(cached.refreshInterval)) {
+			delete(b.roleCRLs, url)
+			continue
+		}
+		if cached.stale(now, cached.refreshInterval) {
+			stale[url] = cached.refreshInterval
+		}
+	}
+	b.roleCRLMutex.Unlock()
+
+	var errs *multierror.Error
+	for url, refreshInterval := range stale {
+		if _, err := b.refreshRoleCRL(ctx, url, refreshInterval); err != nil {
+			errs = multierror.Append(errs, err)
 		}
-		certList, err := x509.ParseCRL(body)
+	}
+	return errs.ErrorOrNil()
+}
+
+// configuredRoleCRLURLs returns the set of the crl_urls of all the roles.
+func (b *backend) configuredRoleCRLURLs(ctx context.Context, s logical.Storage) (map[string]struct{}, error) {
+	names, err := s.List(ctx, "cert/")
+	if err != nil {
+		return nil, err
+	}
+
+	urls := map[string]struct{}{}
+	for _, name := range names {
+		cert, err := b.Cert(ctx, s, name)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if cert == nil {
+			continue
+		}
+		for _, url := range cert.CrlUrls {
+			urls[url] = struct{}{}
 		}
-		crl.CDP.ValidUntil = certList.TBSCertList.NextUpdate
-		return b.setCRL(ctx, storage, certList, name, crl.CDP)
 	}
-	return fmt.Errorf("unexpected response code %d fetching CRL from %s", response.StatusCode, crl.CDP.Url)
+	return urls, nil
 }
 
 func (b *backend) updateCRLs(ctx context.Context, req *logical.Request) error {
-	b.crlUpdateMutex.Lock()
-	defer b.crlUpdateMutex.Unlock()
 	var errs *multierror.Error
+	b.crlUpdateMutex.Lock()
 	for name, crl := range b.crls {
 		if crl.CDP != nil && time.Now().After(crl.CDP.ValidUntil) {
 			if err := b.fetchCRL(ctx, req.Storage, name, &crl); err != nil {
@@ -130,6 +359,11 @@ func (b *backend) updateCRLs(ctx context.Context, req *logical.Request) error {
 			}
 		}
 	}
+	b.crlUpdateMutex.Unlock()
+
+	if err := b.updateRoleCRLs(ctx, req.Storage); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	return errs.ErrorOrNil()
 }
 
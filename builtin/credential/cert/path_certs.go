@@ -74,6 +74,26 @@ from the AuthorityInformationAccess extension on the certificate being inspected
 				Default:     false,
 				Description: "If set to true, rather than accepting the first successful OCSP response, query all servers and consider the certificate valid only if all servers agree.",
 			},
+			"crl_urls": {
+				Type: framework.TypeCommaStringSlice,
+				Description: `A comma-separated list of http or https URLs from which to fetch CRLs that client certificates
+are checked against at login.  Fetched CRLs are cached and refreshed periodically.`,
+			},
+			"crl_use_distribution_points": {
+				Type:        framework.TypeBool,
+				Default:     false,
+				Description: "If set to true, client certificates are also checked against CRLs fetched from the URLs in their CRL Distribution Points extension.",
+			},
+			"crl_refresh_interval": {
+				Type:        framework.TypeDurationSecond,
+				Default:     int(defaultCRLRefreshInterval.Seconds()),
+				Description: "How often CRLs fetched for this certificate are refreshed. A CRL is always refreshed once past its next update time.",
+			},
+			"crl_fail_open": {
+				Type:        framework.TypeBool,
+				Default:     false,
+				Description: "If set to true, if a fetched CRL cannot be used to determine a certificate's revocation status, login will proceed rather than failing.  If false, failing to get a usable CRL fails the request.",
+			},
 			"allowed_names": {
 				Type: framework.TypeCommaStringSlice,
 				Description: `A comma-separated list of names.
@@ -278,6 +298,15 @@ func (b *backend) pathCertRead(ctx context.Context, req *logical.Request, d *fra
 		"allowed_organizational_units": cert.AllowedOrganizationalUnits,
 		"required_extensions":          cert.RequiredExtensions,
 		"allowed_metadata_extensions":  cert.AllowedMetadataExtensions,
+		"ocsp_ca_certificates":         cert.OcspCaCertificates,
+		"ocsp_enabled":                 cert.OcspEnabled,
+		"ocsp_servers_override":        cert.OcspServersOverride,
+		"ocsp_fail_open":               cert.OcspFailOpen,
+		"ocsp_query_all_servers":       cert.OcspQueryAllServers,
+		"crl_urls":                     cert.CrlUrls,
+		"crl_use_distribution_points":  cert.CrlUseDistributionPoints,
+		"crl_refresh_interval":         int64(cert.CrlRefreshInterval.Seconds()),
+		"crl_fail_open":                cert.CrlFailOpen,
 	}
 	cert.PopulateTokenData(data)
 
@@ -335,6 +364,20 @@ func (b *backend) pathCertWrite(ctx context.Context, req *logical.Request, d *fr
 	if ocspQueryAll, ok := d.GetOk("ocsp_query_all_servers"); ok {
 		cert.OcspQueryAllServers = ocspQueryAll.(bool)
 	}
+	if crlURLsRaw, ok := d.GetOk("crl_urls"); ok {
+		cert.CrlUrls = crlURLsRaw.([]string)
+	}
+	if crlUseDistributionPointsRaw, ok := d.GetOk("crl_use_distribution_points"); ok {
+		cert.CrlUseDistributionPoints = crlUseDistributionPointsRaw.(bool)
+	}
+	if crlRefreshIntervalRaw, ok := d.GetOk("crl_refresh_interval"); ok {
+		cert.CrlRefreshInterval = time.Duration(crlRefreshIntervalRaw.(int)) * time.Second
+	} else if cert.CrlRefreshInterval == 0 {
+		cert.CrlRefreshInterval = time.Duration(d.Get("crl_refresh_interval").(int)) * time.Second
+	}
+	if crlFailOpenRaw, ok := d.GetOk("crl_fail_open"); ok {
+		cert.CrlFailOpen = crlFailOpenRaw.(bool)
+	}
 	if displayNameRaw, ok := d.GetOk("display_name"); ok {
 		cert.DisplayName = displayNameRaw.(string)
 	}
@@ -416,6 +459,14 @@ func (b *backend) pathCertWrite(ctx context.Context, req *logical.Request, d *fr
 	if cert.TokenMaxTTL != 0 && cert.TokenTTL > cert.TokenMaxTTL {
 		return logical.ErrorResponse("ttl should be shorter than max_ttl"), nil
 	}
+	if cert.CrlRefreshInterval <= 0 {
+		return logical.ErrorResponse("crl_refresh_interval must be greater than zero"), nil
+	}
+	for _, crlURL := range cert.CrlUrls {
+		if err := validateCRLURL(crlURL); err != nil {
+			return logical.ErrorResponse("invalid CRL url %q: %v", crlURL, err), nil
+		}
+	}
 	if cert.TokenPeriod > systemMaxTTL {
 		resp.AddWarning(fmt.Sprintf("Given period of %d seconds is greater than the backend's maximum TTL of %d seconds", cert.TokenPeriod/time.Second, systemMaxTTL/time.Second))
 	}
@@ -485,6 +536,11 @@ type CertEntry struct {
 	OcspServersOverride []string
 	OcspFailOpen        bool
 	OcspQueryAllServers bool
+
+	CrlUrls                  []string
+	CrlUseDistributionPoints bool
+	CrlRefreshInterval       time.Duration
+	CrlFailOpen              bool
 }
 
 const pathCertHelpSyn = `
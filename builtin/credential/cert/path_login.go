@@ -12,11 +12,13 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/vault/sdk/helper/ocsp"
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/certutil"
 	"github.com/hashicorp/vault/sdk/helper/policyutil"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
 	"github.com/hashicorp/vault/sdk/logical"
 
 	"github.com/hashicorp/vault/sdk/helper/cidrutil"
@@ -336,6 +338,9 @@ func (b *backend) matchesConstraints(ctx context.Context, clientCert *x509.Certi
 		}
 		soFar = soFar && ocspGood
 	}
+	if soFar {
+		soFar = b.checkForCertInRoleCRLs(ctx, clientCert, trustedChain, config.Entry)
+	}
 	return soFar, nil
 }
 
@@ -607,6 +612,70 @@ func (b *backend) checkForCertInOCSP(ctx context.Context, clientCert *x509.Certi
 	return true, nil
 }
 
+// checkForCertInRoleCRLs checks the client certificate against the CRLs the
+// role fetches, either from its configured URLs or from the certificate's CRL
+// distribution points. Only CRLs issued and signed by the certificate's issuer
+// are considered. It returns false if the certificate has been revoked, or if
+// no usable CRL could be found and the role doesn't fail open.
+func (b *backend) checkForCertInRoleCRLs(ctx context.Context, clientCert *x509.Certificate, chain []*x509.Certificate, entry *CertEntry) bool {
+	urls := entry.CrlUrls
+	if entry.CrlUseDistributionPoints {
+		urls = append(append([]string{}, urls...), clientCert.CRLDistributionPoints...)
+	}
+	if len(urls) == 0 {
+		return true
+	}
+
+	refreshInterval := entry.CrlRefreshInterval
+	if refreshInterval <= 0 {
+		refreshInterval = defaultCRLRefreshInterval
+	}
+
+	var issuer *x509.Certificate
+	for _, cert := range chain {
+		if cert.Equal(clientCert) {
+			continue
+		}
+		if bytes.Equal(cert.RawSubject, clientCert.RawIssuer) && clientCert.CheckSignatureFrom(cert) == nil {
+			issuer = cert
+			break
+		}
+	}
+
+	var checked bool
+	var errs *multierror.Error
+	if issuer == nil {
+		errs = multierror.Append(errs, errors.New("issuer of client certificate not found in chain"))
+	} else {
+		for _, url := range strutil.RemoveDuplicatesStable(urls, false) {
+			crl, err := b.roleCRL(ctx, url, refreshInterval)
+			if err != nil {
+				errs = multierror.Append(errs, err)
+				continue
+			}
+			if !bytes.Equal(crl.RawIssuer, clientCert.RawIssuer) {
+				continue
+			}
+			if err := crl.CheckSignatureFrom(issuer); err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("CRL from %s has an invalid signature: %w", url, err))
+				continue
+			}
+			checked = true
+			for _, revoked := range crl.RevokedCertificates {
+				if revoked.SerialNumber.Cmp(clientCert.SerialNumber) == 0 {
+					return false
+				}
+			}
+		}
+	}
+	if checked {
+		return true
+	}
+
+	b.Logger().Warn("unable to determine certificate revocation status from CRLs", "name", entry.Name, "fail_open", entry.CrlFailOpen, "error", errs.ErrorOrNil())
+	return entry.CrlFailOpen
+}
+
 func (b *backend) checkForChainInCRLs(chain []*x509.Certificate) bool {
 	badChain := false
 	for _, cert := range chain {
@@ -2,6 +2,8 @@ package cert
 
 import (
 	"context"
+	"crypto"
+	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -11,9 +13,12 @@ import (
 	mathrand "math/rand"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -353,6 +358,314 @@ func TestCert_RoleResolveOCSP(t *testing.T) {
 	}
 }
 
+// crlResponder serves a CRL which tests can swap out as they go.
+type crlResponder struct {
+	l   sync.Mutex
+	crl []byte
+}
+
+func (r *crlResponder) set(crl []byte) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.crl = crl
+}
+
+func (r *crlResponder) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	w.Write(r.crl)
+}
+
+// generateTestCRLCert issues a client certificate whose CRL distribution point
+// is crlURL, returning the connection state, CA certificate and CA key.
+func generateTestCRLCert(t *testing.T, crlURL string) (*x509.Certificate, tls.ConnectionState, []byte, crypto.Signer) {
+	t.Helper()
+	certTemplate := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName: "example.com",
+		},
+		DNSNames:    []string{"example.com"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth,
+			x509.ExtKeyUsageClientAuth,
+		},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageKeyAgreement,
+		SerialNumber:          big.NewInt(mathrand.Int63()),
+		NotBefore:             time.Now().Add(-30 * time.Second),
+		NotAfter:              time.Now().Add(262980 * time.Hour),
+		CRLDistributionPoints: []string{crlURL},
+	}
+	tempDir, connState, err := generateTestCertAndConnState(t, certTemplate)
+	if tempDir != "" {
+		defer os.RemoveAll(tempDir)
+	}
+	if err != nil {
+		t.Fatalf("error testing connection state: %v", err)
+	}
+	ca, err := ioutil.ReadFile(filepath.Join(tempDir, "ca_cert.pem"))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	pkf, err := ioutil.ReadFile(filepath.Join(tempDir, "ca_key.pem"))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	pk, err := certutil.ParsePEMBundle(string(pkf))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	return certTemplate, connState, ca, pk.PrivateKey
+}
+
+func createTestCRL(t *testing.T, ca []byte, key crypto.Signer, nextUpdate time.Duration, revoked ...*big.Int) []byte {
+	t.Helper()
+	template := &x509.RevocationList{
+		Number:     big.NewInt(mathrand.Int63()),
+		ThisUpdate: time.Now(),
+		NextUpdate: time.Now().Add(nextUpdate),
+	}
+	for _, serial := range revoked {
+		template.RevokedCertificates = append(template.RevokedCertificates, pkix.RevokedCertificate{
+			SerialNumber:   serial,
+			RevocationTime: time.Now(),
+		})
+	}
+	crl, err := x509.CreateRevocationList(rand.Reader, template, parsePEM(ca)[0], key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crl
+}
+
+func TestCert_RoleCRLs(t *testing.T) {
+	responder := &crlResponder{}
+	crlServer := httptest.NewServer(responder)
+	defer crlServer.Close()
+
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachableURL := unreachable.URL
+	unreachable.Close()
+
+	certTemplate, connState, ca, caKey := generateTestCRLCert(t, crlServer.URL)
+
+	cases := []struct {
+		name        string
+		params      map[string]interface{}
+		revoked     bool
+		errExpected bool
+	}{
+		{"urlGoodCert", map[string]interface{}{"crl_urls": crlServer.URL}, false, false},
+		{"urlRevokedCert", map[string]interface{}{"crl_urls": crlServer.URL}, true, true},
+		{"cdpGoodCert", map[string]interface{}{"crl_use_distribution_points": true}, false, false},
+		{"cdpRevokedCert", map[string]interface{}{"crl_use_distribution_points": true}, true, true},
+		{"failFalseUnreachable", map[string]interface{}{"crl_urls": unreachableURL}, false, true},
+		{"failTrueUnreachable", map[string]interface{}{"crl_urls": unreachableURL, "crl_fail_open": true}, false, false},
+		{"failTrueRevokedCert", map[string]interface{}{"crl_urls": []string{unreachableURL, crlServer.URL}, "crl_fail_open": true}, true, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.revoked {
+				responder.set(createTestCRL(t, ca, caKey, time.Hour, certTemplate.SerialNumber))
+			} else {
+				responder.set(createTestCRL(t, ca, caKey, time.Hour, big.NewInt(1)))
+			}
+
+			var loginStep logicaltest.TestStep
+			if c.errExpected {
+				loginStep = testAccStepLoginWithNameInvalid(t, connState, "web")
+			} else {
+				loginStep = testAccStepLoginWithName(t, connState, "web")
+			}
+			logicaltest.Test(t, logicaltest.TestCase{
+				CredentialBackend: testFactory(t),
+				Steps: []logicaltest.TestStep{
+					testAccStepCertWithExtraParams(t, "web", ca, "foo", allowed{dns: "example.com"}, false, c.params),
+					loginStep,
+				},
+			})
+		})
+	}
+}
+
+func TestCert_RoleCRLRefresh(t *testing.T) {
+	responder := &crlResponder{}
+	crlServer := httptest.NewServer(responder)
+	defer crlServer.Close()
+
+	certTemplate, connState, ca, caKey := generateTestCRLCert(t, crlServer.URL)
+	responder.set(createTestCRL(t, ca, caKey, 100*time.Millisecond))
+
+	b := testFactory(t)
+	logicaltest.Test(t, logicaltest.TestCase{
+		CredentialBackend: b,
+		Steps: []logicaltest.TestStep{
+			testAccStepCertWithExtraParams(t, "web", ca, "foo", allowed{dns: "example.com"}, false,
+				map[string]interface{}{"crl_use_distribution_points": true}),
+			testAccStepLoginWithName(t, connState, "web"),
+		},
+	})
+
+	// Revoke the certificate and wait for the cached CRL to expire so the
+	// periodic function picks up the new one.
+	responder.set(createTestCRL(t, ca, caKey, time.Hour, certTemplate.SerialNumber))
+	time.Sleep(150 * time.Millisecond)
+
+	if err := b.(*backend).updateCRLs(context.Background(), &logical.Request{Storage: &logical.InmemStorage{}}); err != nil {
+		t.Fatal(err)
+	}
+	b.(*backend).roleCRLMutex.Lock()
+	cached := b.(*backend).roleCRLs[crlServer.URL]
+	b.(*backend).roleCRLMutex.Unlock()
+	if cached == nil || len(cached.crl.RevokedCertificates) != 1 {
+		t.Fatalf("expected refreshed CRL with one revoked certificate, got %#v", cached)
+	}
+
+	// Reuses the backend, and thus its cached CRL, from the first run.
+	logicaltest.Test(t, logicaltest.TestCase{
+		CredentialBackend: b,
+		Steps: []logicaltest.TestStep{
+			testAccStepLoginWithNameInvalid(t, connState, "web"),
+		},
+	})
+}
+
+func TestCert_RoleCRLPrune(t *testing.T) {
+	_, _, ca, caKey := generateTestCRLCert(t, "http://127.0.0.1/cdp")
+	crl, err := x509.ParseRevocationList(createTestCRL(t, ca, caKey, 24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	storage := &logical.InmemStorage{}
+	b := testFactory(t).(*backend)
+	resp, err := b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "certs/web",
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"certificate": string(ca),
+			"crl_urls":    "http://127.0.0.1/configured",
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v, resp: %#v", err, resp)
+	}
+
+	now := time.Now()
+	cached := func(lastUsed time.Time) *fetchedCRL {
+		return &fetchedCRL{crl: crl, fetchedAt: now, refreshInterval: time.Hour, lastUsed: lastUsed}
+	}
+	b.roleCRLs = map[string]*fetchedCRL{
+		"http://127.0.0.1/configured": cached(time.Time{}),
+		"http://127.0.0.1/recent":     cached(now.Add(-time.Minute)),
+		"http://127.0.0.1/unused":     cached(now.Add(-2 * time.Hour)),
+	}
+
+	// CRLs that are neither configured on a role nor recently used are dropped
+	if err := b.updateCRLs(ctx, &logical.Request{Storage: storage}); err != nil {
+		t.Fatal(err)
+	}
+	for url, expected := range map[string]bool{
+		"http://127.0.0.1/configured": true,
+		"http://127.0.0.1/recent":     true,
+		"http://127.0.0.1/unused":     false,
+	} {
+		if _, ok := b.roleCRLs[url]; ok != expected {
+			t.Fatalf("expected %s to be cached: %t", url, expected)
+		}
+	}
+
+	// Deleting the role stops its CRLs from being refreshed once unused
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.DeleteOperation,
+		Path:      "certs/web",
+		Storage:   storage,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v, resp: %#v", err, resp)
+	}
+	if err := b.updateCRLs(ctx, &logical.Request{Storage: storage}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.roleCRLs["http://127.0.0.1/configured"]; ok {
+		t.Fatal("expected the CRL of the deleted role to be dropped")
+	}
+}
+
+func TestCert_RoleCRLURLScheme(t *testing.T) {
+	_, _, ca, _ := generateTestCRLCert(t, "http://127.0.0.1/crl")
+
+	for _, crlURL := range []string{"ldap://example.com/cn=crl", "file:///etc/passwd", "http:///crl"} {
+		logicaltest.Test(t, logicaltest.TestCase{
+			CredentialBackend: testFactory(t),
+			Steps: []logicaltest.TestStep{
+				{
+					Operation: logical.UpdateOperation,
+					Path:      "certs/web",
+					ErrorOk:   true,
+					Data: map[string]interface{}{
+						"certificate": string(ca),
+						"crl_urls":    crlURL,
+					},
+					Check: func(resp *logical.Response) error {
+						if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "invalid CRL url") {
+							return fmt.Errorf("expected crl_urls %q to be rejected, got %#v", crlURL, resp)
+						}
+						return nil
+					},
+				},
+			},
+		})
+	}
+}
+
+func TestCert_RoleCRLConcurrentRefresh(t *testing.T) {
+	_, _, ca, caKey := generateTestCRLCert(t, "http://127.0.0.1/crl")
+	crl := createTestCRL(t, ca, caKey, time.Hour)
+
+	var requests int32
+	release := make(chan struct{})
+	crlServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		w.Write(crl)
+	}))
+	defer crlServer.Close()
+
+	b := testFactory(t).(*backend)
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := b.refreshRoleCRL(context.Background(), crlServer.URL, time.Hour)
+			errs <- err
+		}()
+	}
+
+	// Let every refresh join the first download before it completes
+	for atomic.LoadInt32(&requests) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("expected a single CRL download, got %d", n)
+	}
+}
+
 func serialFromBigInt(serial *big.Int) string {
 	return strings.TrimSpace(certutil.GetHexFormatted(serial.Bytes(), ":"))
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/hashicorp/vault/sdk/framework"
@@ -38,6 +40,7 @@ func Backend() *backend {
 
 		Paths: []*framework.Path{
 			pathConfig(&b),
+			pathGroupMembers(&b),
 			pathGroups(&b),
 			pathGroupsList(&b),
 			pathUsers(&b),
@@ -46,14 +49,77 @@ func Backend() *backend {
 		},
 
 		AuthRenew:   b.pathLoginRenew,
+		Invalidate:  b.invalidate,
 		BackendType: logical.TypeCredential,
 	}
 
+	b.groupCache = make(map[string]*cachedGroups)
 	return &b
 }
 
 type backend struct {
 	*framework.Backend
+
+	// groupCache holds the LDAP groups resolved for each user DN, when
+	// group_cache_ttl is configured.
+	groupCache     map[string]*cachedGroups
+	groupCacheLock sync.Mutex
+}
+
+type cachedGroups struct {
+	groups    []string
+	expiresAt time.Time
+}
+
+func (b *backend) invalidate(_ context.Context, key string) {
+	if key == "config" {
+		b.flushGroupCache()
+	}
+}
+
+// cachedLdapGroups returns the LDAP groups previously resolved for userDN, if
+// they haven't yet expired.
+func (b *backend) cachedLdapGroups(userDN string) ([]string, bool) {
+	b.groupCacheLock.Lock()
+	defer b.groupCacheLock.Unlock()
+
+	entry, ok := b.groupCache[userDN]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(b.groupCache, userDN)
+		return nil, false
+	}
+	return entry.groups, true
+}
+
+func (b *backend) cacheLdapGroups(userDN string, groups []string, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	b.groupCacheLock.Lock()
+	defer b.groupCacheLock.Unlock()
+
+	// Drop expired entries as we go, so that users who stop logging in
+	// don't linger in the cache.
+	now := time.Now()
+	for dn, entry := range b.groupCache {
+		if now.After(entry.expiresAt) {
+			delete(b.groupCache, dn)
+		}
+	}
+	b.groupCache[userDN] = &cachedGroups{
+		groups:    groups,
+		expiresAt: now.Add(ttl),
+	}
+}
+
+func (b *backend) flushGroupCache() {
+	b.groupCacheLock.Lock()
+	defer b.groupCacheLock.Unlock()
+	b.groupCache = make(map[string]*cachedGroups)
 }
 
 func (b *backend) Login(ctx context.Context, req *logical.Request, username string, password string, usernameAsAlias bool) (string, []string, *logical.Response, []string, error) {
@@ -137,12 +203,20 @@ func (b *backend) Login(ctx context.Context, req *logical.Request, username stri
 		defer c.Close() // Defer closing of this connection as the deferal above closes the other defined connection
 	}
 
-	ldapGroups, err := ldapClient.GetLdapGroups(cfg.ConfigEntry, c, userDN, username)
-	if err != nil {
-		return "", nil, logical.ErrorResponse(err.Error()), nil, nil
-	}
-	if b.Logger().IsDebug() {
-		b.Logger().Debug("groups fetched from server", "num_server_groups", len(ldapGroups), "server_groups", ldapGroups)
+	ldapGroups, ok := b.cachedLdapGroups(userDN)
+	if ok {
+		if b.Logger().IsDebug() {
+			b.Logger().Debug("groups fetched from cache", "num_server_groups", len(ldapGroups), "server_groups", ldapGroups)
+		}
+	} else {
+		ldapGroups, err = ldapClient.GetLdapGroups(cfg.ConfigEntry, c, userDN, username)
+		if err != nil {
+			return "", nil, logical.ErrorResponse(err.Error()), nil, nil
+		}
+		if b.Logger().IsDebug() {
+			b.Logger().Debug("groups fetched from server", "num_server_groups", len(ldapGroups), "server_groups", ldapGroups)
+		}
+		b.cacheLdapGroups(userDN, ldapGroups, cfg.GroupCacheTTL)
 	}
 
 	ldapResponse := &logical.Response{
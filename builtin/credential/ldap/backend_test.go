@@ -381,6 +381,159 @@ func TestLdapAuthBackend_UserPolicies(t *testing.T) {
 	}
 }
 
+func TestLdapAuthBackend_GroupCache(t *testing.T) {
+	b, storage := createBackendWithStorage(t)
+
+	b.cacheLdapGroups("cn=fry,ou=people,dc=example", []string{"ship_crew"}, time.Hour)
+	b.cacheLdapGroups("cn=leela,ou=people,dc=example", []string{"ship_crew"}, 0)
+	b.cacheLdapGroups("cn=bender,ou=people,dc=example", []string{"ship_crew"}, time.Millisecond)
+
+	groups, ok := b.cachedLdapGroups("cn=fry,ou=people,dc=example")
+	if !ok || !reflect.DeepEqual(groups, []string{"ship_crew"}) {
+		t.Fatalf("expected cached groups, got %v", groups)
+	}
+	if _, ok := b.cachedLdapGroups("cn=leela,ou=people,dc=example"); ok {
+		t.Fatal("groups should not be cached with a zero TTL")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := b.cachedLdapGroups("cn=bender,ou=people,dc=example"); ok {
+		t.Fatal("expired groups should not be returned")
+	}
+
+	// Writing the config flushes the cache
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "config",
+		Data: map[string]interface{}{
+			"url":                     "ldap://127.0.0.1",
+			"group_cache_ttl":         "5m",
+			"nested_groups":           true,
+			"nested_groups_max_depth": 3,
+		},
+		Storage: storage,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+	if _, ok := b.cachedLdapGroups("cn=fry,ou=people,dc=example"); ok {
+		t.Fatal("expected the cache to be flushed on config write")
+	}
+
+	resp, err = b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "config",
+		Storage:   storage,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+	if resp.Data["group_cache_ttl"] != int64(300) {
+		t.Fatalf("bad: group_cache_ttl: %#v", resp.Data["group_cache_ttl"])
+	}
+	if resp.Data["nested_groups"] != true || resp.Data["nested_groups_max_depth"] != 3 {
+		t.Fatalf("bad: nested groups config: %#v", resp.Data)
+	}
+}
+
+func TestLdapAuthBackend_GroupMembersAndCache(t *testing.T) {
+	var resp *logical.Response
+	var err error
+	b, storage := createBackendWithStorage(t)
+
+	cleanup, cfg := ldap.PrepareTestContainer(t, "latest")
+	defer cleanup()
+	configReq := &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "config",
+		Data: map[string]interface{}{
+			"url":             cfg.Url,
+			"userattr":        cfg.UserAttr,
+			"userdn":          cfg.UserDN,
+			"groupdn":         cfg.GroupDN,
+			"groupattr":       cfg.GroupAttr,
+			"binddn":          cfg.BindDN,
+			"bindpassword":    cfg.BindPassword,
+			"group_cache_ttl": "1h",
+		},
+		Storage: storage,
+	}
+	resp, err = b.HandleRequest(context.Background(), configReq)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	groupReq := &logical.Request{
+		Operation: logical.UpdateOperation,
+		Data: map[string]interface{}{
+			"policies": "grouppolicy",
+		},
+		Path:    "groups/admin_staff",
+		Storage: storage,
+	}
+	resp, err = b.HandleRequest(context.Background(), groupReq)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	userReq := &logical.Request{
+		Operation: logical.UpdateOperation,
+		Data: map[string]interface{}{
+			"groups": "admin_staff",
+		},
+		Path:    "users/zoidberg",
+		Storage: storage,
+	}
+	resp, err = b.HandleRequest(context.Background(), userReq)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	membersReq := &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "groups/admin_staff/members",
+		Storage:   storage,
+	}
+	resp, err = b.HandleRequest(context.Background(), membersReq)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+	if !strutil.StrListContains(resp.Data["ldap_members"].([]string), "hermes conrad") {
+		t.Fatalf("bad: ldap_members: %#v", resp.Data["ldap_members"])
+	}
+	if !reflect.DeepEqual(resp.Data["local_members"], []string{"zoidberg"}) {
+		t.Fatalf("bad: local_members: %#v", resp.Data["local_members"])
+	}
+	if !reflect.DeepEqual(resp.Data["policies"], []string{"grouppolicy"}) {
+		t.Fatalf("bad: policies: %#v", resp.Data["policies"])
+	}
+
+	loginReq := &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "login/hermes conrad",
+		Data: map[string]interface{}{
+			"password": "hermes",
+		},
+		Storage:    storage,
+		Connection: &logical.Connection{},
+	}
+	resp, err = b.HandleRequest(context.Background(), loginReq)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	groups, ok := b.cachedLdapGroups("cn=Hermes Conrad,ou=people,dc=planetexpress,dc=com")
+	if !ok || !strutil.StrListContains(groups, "admin_staff") {
+		t.Fatalf("expected groups to be cached after login, got %v", groups)
+	}
+
+	// A wrong password must still be rejected when groups are cached
+	loginReq.Data["password"] = "wrong"
+	resp, err = b.HandleRequest(context.Background(), loginReq)
+	if err == nil {
+		t.Fatalf("expected login failure, got resp:%#v", resp)
+	}
+}
+
 /*
 * Acceptance test for LDAP Auth Method
 *
@@ -3,6 +3,7 @@ package ldap
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/consts"
@@ -30,6 +31,11 @@ func pathConfig(b *backend) *framework.Path {
 		},
 	}
 
+	p.Fields["group_cache_ttl"] = &framework.FieldSchema{
+		Type:        framework.TypeDurationSecond,
+		Description: "How long the LDAP groups resolved for a user are cached and reused by later logins and renewals. The user is still authenticated against the LDAP server each time. If unset or 0, groups are not cached.",
+	}
+
 	tokenutil.AddTokenFields(p.Fields)
 	p.Fields["token_policies"].Description += ". This will apply to all tokens generated by this auth method, in addition to any configured for specific users/groups."
 	return p
@@ -112,6 +118,7 @@ func (b *backend) pathConfigRead(ctx context.Context, req *logical.Request, d *f
 
 	data := cfg.PasswordlessMap()
 	cfg.PopulateTokenData(data)
+	data["group_cache_ttl"] = int64(cfg.GroupCacheTTL.Seconds())
 
 	resp := &logical.Response{
 		Data: data,
@@ -178,6 +185,13 @@ func (b *backend) pathConfigWrite(ctx context.Context, req *logical.Request, d *
 		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
 	}
 
+	if groupCacheTTLRaw, ok := d.GetOk("group_cache_ttl"); ok {
+		cfg.GroupCacheTTL = time.Duration(groupCacheTTLRaw.(int)) * time.Second
+	}
+	if cfg.GroupCacheTTL < 0 {
+		return logical.ErrorResponse("group_cache_ttl must not be negative"), nil
+	}
+
 	entry, err := logical.StorageEntryJSON("config", cfg)
 	if err != nil {
 		return nil, err
@@ -185,6 +199,7 @@ func (b *backend) pathConfigWrite(ctx context.Context, req *logical.Request, d *
 	if err := req.Storage.Put(ctx, entry); err != nil {
 		return nil, err
 	}
+	b.flushGroupCache()
 
 	if warnings := b.checkConfigUserFilter(cfg); len(warnings) > 0 {
 		return &logical.Response{
@@ -218,6 +233,8 @@ func (b *backend) getConfigFieldData() (*framework.FieldData, error) {
 type ldapConfigEntry struct {
 	tokenutil.TokenParams
 	*ldaputil.ConfigEntry
+
+	GroupCacheTTL time.Duration `json:"group_cache_ttl"`
 }
 
 const pathConfigHelpSyn = `
@@ -2,9 +2,12 @@ package ldap
 
 import (
 	"context"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/ldaputil"
 	"github.com/hashicorp/vault/sdk/helper/policyutil"
 	"github.com/hashicorp/vault/sdk/logical"
 )
@@ -56,6 +59,25 @@ func pathGroups(b *backend) *framework.Path {
 	}
 }
 
+func pathGroupMembers(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `groups/(?P<name>.+)/members$`,
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the LDAP group.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: b.pathGroupMembersRead,
+		},
+
+		HelpSynopsis:    pathGroupMembersHelpSyn,
+		HelpDescription: pathGroupMembersHelpDesc,
+	}
+}
+
 func (b *backend) Group(ctx context.Context, s logical.Storage, n string) (*GroupEntry, error) {
 	entry, err := s.Get(ctx, "group/"+n)
 	if err != nil {
@@ -111,6 +133,98 @@ func (b *backend) pathGroupRead(ctx context.Context, req *logical.Request, d *fr
 	}, nil
 }
 
+func (b *backend) pathGroupMembersRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	groupname := d.Get("name").(string)
+
+	cfg, err := b.Config(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return logical.ErrorResponse("ldap backend not configured"), nil
+	}
+	cs := *cfg.CaseSensitiveNames
+	canonicalGroupname := groupname
+	if !cs {
+		canonicalGroupname = strings.ToLower(groupname)
+	}
+
+	ldapClient := ldaputil.Client{
+		Logger: b.Logger(),
+		LDAP:   ldaputil.NewLDAP(),
+	}
+
+	c, err := ldapClient.DialLDAP(cfg.ConfigEntry)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+	if c == nil {
+		return logical.ErrorResponse("invalid connection returned from LDAP dial"), nil
+	}
+	defer c.Close()
+
+	if cfg.BindDN != "" && cfg.BindPassword != "" {
+		if err := c.Bind(cfg.BindDN, cfg.BindPassword); err != nil {
+			if b.Logger().IsDebug() {
+				b.Logger().Debug("error while attempting to bind with the BindDN User", "error", err)
+			}
+			return logical.ErrorResponse("ldap operation failed: failed to bind with the BindDN user"), nil
+		}
+	}
+
+	ldapMembers, err := ldapClient.GetLdapGroupMembers(cfg.ConfigEntry, c, groupname)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+	if !cs {
+		ldapMembers = strutil.RemoveDuplicates(ldapMembers, true)
+	}
+
+	// Users configured locally with the group are members as well
+	localMembers := []string{}
+	usernames, err := logical.CollectKeysWithPrefix(ctx, req.Storage, "user/")
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range usernames {
+		username := strings.TrimPrefix(key, "user/")
+		user, err := b.User(ctx, req.Storage, username)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			continue
+		}
+		// Mirror login, where group names are only compared case-sensitively
+		// if configured to do so
+		member := strutil.StrListContainsCaseInsensitive(user.Groups, groupname)
+		if cs {
+			member = strutil.StrListContains(user.Groups, groupname)
+		}
+		if member {
+			localMembers = append(localMembers, username)
+		}
+	}
+	sort.Strings(localMembers)
+
+	policies := []string{}
+	group, err := b.Group(ctx, req.Storage, canonicalGroupname)
+	if err != nil {
+		return nil, err
+	}
+	if group != nil {
+		policies = group.Policies
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"ldap_members":  ldapMembers,
+			"local_members": localMembers,
+			"policies":      policies,
+		},
+	}, nil
+}
+
 func (b *backend) pathGroupWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	groupname := d.Get("name").(string)
 
@@ -167,3 +281,17 @@ Deleting a group will not revoke auth for prior authenticated users in that
 group. To do this, do a revoke on "login/<username>" for
 the usernames you want revoked.
 `
+
+const pathGroupMembersHelpSyn = `
+Resolve the current members of a group.
+`
+
+const pathGroupMembersHelpDesc = `
+This endpoint looks up the group in the LDAP server and returns its current
+members, along with any users configured locally with the group and the
+policies associated to it. If nested groups are enabled in the config,
+members of groups nested within the group are returned as well.
+
+This can be used to audit which users would be granted the group's policies
+on login.
+`
@@ -126,6 +126,10 @@ func (b *backend) Login(ctx context.Context, req *logical.Request, username, pas
 	rsp, err := shim.Do(authReq, &result)
 	if err != nil {
 		if oe, ok := err.(*okta.Error); ok {
+			// E0000004 is returned for an invalid username or password
+			if oe.ErrorCode == "E0000004" {
+				return nil, logical.ErrorResponse("Okta auth failed: %v (code=%v)", err, oe.ErrorCode), nil, logical.ErrInvalidCredentials
+			}
 			return nil, logical.ErrorResponse("Okta auth failed: %v (code=%v)", err, oe.ErrorCode), nil, nil
 		}
 		return nil, logical.ErrorResponse(fmt.Sprintf("Okta auth failed: %v", err)), nil, nil
@@ -88,6 +88,11 @@ func (b *backend) pathLogin(ctx context.Context, req *logical.Request, d *framew
 	defer b.verifyCache.Delete(nonce)
 
 	policies, resp, groupNames, err := b.Login(ctx, req, username, password, totp, nonce, preferredProvider)
+	// Rejected credentials are reported with the error response, so that
+	// failed logins can be counted
+	if err == logical.ErrInvalidCredentials {
+		return resp, err
+	}
 	// Handle an internal error
 	if err != nil {
 		return nil, err
@@ -23,6 +23,7 @@ func Backend() *backend {
 		PathsSpecial: &logical.Paths{
 			Unauthenticated: []string{
 				"login/*",
+				"users/+/password/change",
 			},
 		},
 
@@ -31,6 +32,7 @@ func Backend() *backend {
 			pathUsersList(&b),
 			pathUserPolicies(&b),
 			pathUserPassword(&b),
+			pathUserPasswordChange(&b),
 			pathLogin(&b),
 		},
 
@@ -52,5 +54,6 @@ are supported.
 
 The username/password combination is configured using the "users/"
 endpoints by a user with root access. Authentication is then done
-by supplying the two fields for "login".
+by supplying the two fields for "login". Users can change their own
+password with the "users/<username>/password/change" endpoint.
 `
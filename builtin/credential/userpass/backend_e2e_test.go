@@ -0,0 +1,165 @@
+package userpass
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	vaulthttp "github.com/hashicorp/vault/http"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault"
+)
+
+func TestBackend_E2E_PasswordPolicyAndChange(t *testing.T) {
+	cluster := vault.NewTestCluster(t, &vault.CoreConfig{
+		CredentialBackends: map[string]logical.Factory{
+			"userpass": Factory,
+		},
+	}, &vault.TestClusterOptions{
+		HandlerFunc: vaulthttp.Handler,
+	})
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	client := cluster.Cores[0].Client
+
+	if err := client.Sys().EnableAuthWithOptions("userpass", &api.EnableAuthOptions{Type: "userpass"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.Logical().Write("sys/policies/password/strong", map[string]interface{}{
+		"policy": `
+length = 12
+rule "charset" {
+	charset = "0123456789"
+	min-chars = 1
+}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Logical().Write("auth/userpass/users/alice", map[string]interface{}{
+		"password":        "no-digits-in-here",
+		"password_policy": "strong",
+	})
+	if err == nil || !strings.Contains(err.Error(), `password does not satisfy password policy "strong"`) {
+		t.Fatalf("expected password policy error, got: %v", err)
+	}
+
+	_, err = client.Logical().Write("auth/userpass/users/alice", map[string]interface{}{
+		"password":         "initial-password-1",
+		"password_policy":  "strong",
+		"password_history": 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Users change their own password without a token
+	userClient, err := client.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	userClient.ClearToken()
+
+	_, err = userClient.Logical().Write("auth/userpass/users/alice/password/change", map[string]interface{}{
+		"old_password": "initial-password-1",
+		"new_password": "initial-password-1",
+	})
+	if err == nil || !strings.Contains(err.Error(), "can't be reused") {
+		t.Fatalf("expected password history error, got: %v", err)
+	}
+
+	_, err = userClient.Logical().Write("auth/userpass/users/alice/password/change", map[string]interface{}{
+		"old_password": "initial-password-1",
+		"new_password": "changed-password-2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := userClient.Logical().Write("auth/userpass/login/alice", map[string]interface{}{
+		"password": "changed-password-2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		t.Fatalf("expected a token, got: %#v", secret)
+	}
+
+	secret, err = client.Logical().Read("auth/userpass/users/alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.Data["password_last_changed"] == nil || secret.Data["password_age"] == nil {
+		t.Fatalf("expected password age to be reported, got: %#v", secret.Data)
+	}
+}
+
+func TestBackend_E2E_PasswordChangeLoginDelay(t *testing.T) {
+	cluster := vault.NewTestCluster(t, &vault.CoreConfig{
+		CredentialBackends: map[string]logical.Factory{
+			"userpass": Factory,
+		},
+	}, &vault.TestClusterOptions{
+		HandlerFunc: vaulthttp.Handler,
+	})
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	client := cluster.Cores[0].Client
+	vault.TestWaitActive(t, cluster.Cores[0].Core)
+
+	if err := client.Sys().EnableAuthWithOptions("userpass", &api.EnableAuthOptions{Type: "userpass"}); err != nil {
+		t.Fatal(err)
+	}
+	progressiveDelay := true
+	err := client.Sys().TuneMount("auth/userpass", api.MountConfigInput{
+		UserLockoutConfig: &api.UserLockoutConfigInput{
+			LockoutThreshold:     "2",
+			ProgressiveDelay:     &progressiveDelay,
+			ProgressiveDelayBase: "1m",
+			ProgressiveDelayMax:  "1m",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Logical().Write("auth/userpass/users/alice", map[string]interface{}{
+		"password": "initial-password",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	userClient, err := client.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	userClient.ClearToken()
+
+	// Guessing the current password counts as failed logins of the user
+	for i := 0; i < 2; i++ {
+		_, err = userClient.Logical().Write("auth/userpass/users/alice/password/change", map[string]interface{}{
+			"old_password": "guess",
+			"new_password": "changed-password",
+		})
+		if err == nil || strings.Contains(err.Error(), "too many failed login attempts") {
+			t.Fatalf("expected invalid credentials, got: %v", err)
+		}
+	}
+
+	_, err = userClient.Logical().Write("auth/userpass/users/alice/password/change", map[string]interface{}{
+		"old_password": "initial-password",
+		"new_password": "changed-password",
+	})
+	if err == nil || !strings.Contains(err.Error(), "too many failed login attempts") {
+		t.Fatalf("expected the password change to be delayed, got: %v", err)
+	}
+	_, err = userClient.Logical().Write("auth/userpass/login/alice", map[string]interface{}{
+		"password": "initial-password",
+	})
+	if err == nil || !strings.Contains(err.Error(), "too many failed login attempts") {
+		t.Fatalf("expected the login to be delayed, got: %v", err)
+	}
+}
@@ -414,3 +414,295 @@ func TestBackend_UserUpgrade(t *testing.T) {
 		t.Fatal(diff)
 	}
 }
+
+func testPasswordBackend(t *testing.T) (*backend, logical.Storage) {
+	t.Helper()
+	storage := &logical.InmemStorage{}
+
+	config := logical.TestBackendConfig()
+	config.StorageView = storage
+	config.System.(*logical.StaticSystemView).SetPasswordValidator("strong", func(password string) error {
+		if len(password) < 12 {
+			return fmt.Errorf("password must be at least 12 characters long")
+		}
+		return nil
+	})
+
+	b := Backend()
+	if err := b.Setup(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+	return b, storage
+}
+
+func testPasswordRequest(b *backend, storage logical.Storage, operation logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+	return b.HandleRequest(context.Background(), &logical.Request{
+		Path:       path,
+		Operation:  operation,
+		Storage:    storage,
+		Data:       data,
+		Connection: &logical.Connection{RemoteAddr: "127.0.0.1"},
+	})
+}
+
+func TestBackend_passwordPolicy(t *testing.T) {
+	b, storage := testPasswordBackend(t)
+
+	resp, err := testPasswordRequest(b, storage, logical.CreateOperation, "users/web", map[string]interface{}{
+		"password":        "short",
+		"password_policy": "strong",
+	})
+	if err != logical.ErrInvalidRequest || !resp.IsError() {
+		t.Fatalf("expected error, got resp: %#v, err: %v", resp, err)
+	}
+
+	resp, err = testPasswordRequest(b, storage, logical.CreateOperation, "users/web", map[string]interface{}{
+		"password":        "a-long-enough-password",
+		"password_policy": "strong",
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+	}
+
+	resp, err = testPasswordRequest(b, storage, logical.UpdateOperation, "users/web/password", map[string]interface{}{
+		"password": "short",
+	})
+	if err != logical.ErrInvalidRequest || !resp.IsError() {
+		t.Fatalf("expected error, got resp: %#v, err: %v", resp, err)
+	}
+
+	resp, err = testPasswordRequest(b, storage, logical.UpdateOperation, "users/web", map[string]interface{}{
+		"password":        "a-long-enough-password",
+		"password_policy": "unknown",
+	})
+	if err != logical.ErrInvalidRequest || !resp.IsError() {
+		t.Fatalf("expected error, got resp: %#v, err: %v", resp, err)
+	}
+
+	resp, err = testPasswordRequest(b, storage, logical.ReadOperation, "users/web", nil)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+	}
+	if resp.Data["password_policy"] != "strong" {
+		t.Fatalf("bad: password_policy: %v", resp.Data["password_policy"])
+	}
+}
+
+func TestBackend_passwordHistory(t *testing.T) {
+	b, storage := testPasswordBackend(t)
+
+	resp, err := testPasswordRequest(b, storage, logical.CreateOperation, "users/web", map[string]interface{}{
+		"password":         "password1",
+		"password_history": 3,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+	}
+
+	setPassword := func(password string) (*logical.Response, error) {
+		return testPasswordRequest(b, storage, logical.UpdateOperation, "users/web/password", map[string]interface{}{
+			"password": password,
+		})
+	}
+
+	for _, password := range []string{"password2", "password3"} {
+		if resp, err := setPassword(password); err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+		}
+	}
+
+	// The current and the two previous passwords can't be reused
+	for _, password := range []string{"password1", "password2", "password3"} {
+		if resp, err := setPassword(password); err != logical.ErrInvalidRequest || !resp.IsError() {
+			t.Fatalf("expected error reusing %q, got resp: %#v, err: %v", password, resp, err)
+		}
+	}
+
+	if resp, err := setPassword("password4"); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+	}
+	if resp, err := setPassword("password1"); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+	}
+
+	// Lowering the history drops the previous passwords no longer needed
+	resp, err = testPasswordRequest(b, storage, logical.UpdateOperation, "users/web", map[string]interface{}{
+		"password_history": 1,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+	}
+	userEntry, err := b.user(context.Background(), storage, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(userEntry.PreviousPasswordHashes) != 0 {
+		t.Fatalf("expected no previous password hashes, got %d", len(userEntry.PreviousPasswordHashes))
+	}
+	if resp, err := setPassword("password4"); err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+	}
+
+	resp, err = testPasswordRequest(b, storage, logical.UpdateOperation, "users/web", map[string]interface{}{
+		"password_history": maxPasswordHistory + 1,
+	})
+	if err != logical.ErrInvalidRequest || !resp.IsError() {
+		t.Fatalf("expected error, got resp: %#v, err: %v", resp, err)
+	}
+}
+
+func TestBackend_passwordChangeAndExpiry(t *testing.T) {
+	b, storage := testPasswordBackend(t)
+	ctx := context.Background()
+
+	resp, err := testPasswordRequest(b, storage, logical.CreateOperation, "users/web", map[string]interface{}{
+		"password":         "old-password",
+		"password_policy":  "strong",
+		"password_max_age": "24h",
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+	}
+
+	resp, err = testPasswordRequest(b, storage, logical.ReadOperation, "users/web", nil)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+	}
+	if resp.Data["password_max_age"].(int64) != 86400 {
+		t.Fatalf("bad: password_max_age: %v", resp.Data["password_max_age"])
+	}
+	lastChanged, err := time.Parse(time.RFC3339, resp.Data["password_last_changed"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiration, err := time.Parse(time.RFC3339, resp.Data["password_expiration"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expiration.Sub(lastChanged) != 24*time.Hour {
+		t.Fatalf("bad: password_expiration: %v", expiration)
+	}
+
+	// Age the password past its maximum age
+	userEntry, err := b.user(ctx, storage, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userEntry.PasswordLastChanged = time.Now().Add(-25 * time.Hour)
+	if err := b.setUser(ctx, storage, "web", userEntry); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err = testPasswordRequest(b, storage, logical.UpdateOperation, "login/web", map[string]interface{}{
+		"password": "old-password",
+	})
+	if err != logical.ErrPermissionDenied || !resp.IsError() {
+		t.Fatalf("expected expired password error, got resp: %#v, err: %v", resp, err)
+	}
+
+	resp, err = testPasswordRequest(b, storage, logical.UpdateOperation, "users/web/password/change", map[string]interface{}{
+		"old_password": "wrong-password",
+		"new_password": "a-new-long-password",
+	})
+	if err != logical.ErrInvalidCredentials || !resp.IsError() {
+		t.Fatalf("expected error, got resp: %#v, err: %v", resp, err)
+	}
+
+	// The new password must satisfy the password policy
+	resp, err = testPasswordRequest(b, storage, logical.UpdateOperation, "users/web/password/change", map[string]interface{}{
+		"old_password": "old-password",
+		"new_password": "short",
+	})
+	if err != logical.ErrInvalidRequest || !resp.IsError() {
+		t.Fatalf("expected error, got resp: %#v, err: %v", resp, err)
+	}
+
+	resp, err = testPasswordRequest(b, storage, logical.UpdateOperation, "users/web/password/change", map[string]interface{}{
+		"old_password": "old-password",
+		"new_password": "a-new-long-password",
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+	}
+
+	resp, err = testPasswordRequest(b, storage, logical.UpdateOperation, "login/web", map[string]interface{}{
+		"password": "a-new-long-password",
+	})
+	if err != nil || resp == nil || resp.IsError() || resp.Auth == nil {
+		t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+	}
+
+	resp, err = testPasswordRequest(b, storage, logical.UpdateOperation, "users/unknown/password/change", map[string]interface{}{
+		"old_password": "old-password",
+		"new_password": "a-new-long-password",
+	})
+	if err != logical.ErrInvalidCredentials || !resp.IsError() {
+		t.Fatalf("expected error, got resp: %#v, err: %v", resp, err)
+	}
+}
+
+func TestBackend_passwordChangeBoundCIDRs(t *testing.T) {
+	b, storage := testPasswordBackend(t)
+
+	resp, err := testPasswordRequest(b, storage, logical.CreateOperation, "users/web", map[string]interface{}{
+		"password":          "old-password",
+		"token_bound_cidrs": "127.0.0.1/32",
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+	}
+
+	changeReq := func(remoteAddr string) *logical.Request {
+		return &logical.Request{
+			Path:      "users/web/password/change",
+			Operation: logical.UpdateOperation,
+			Storage:   storage,
+			Data: map[string]interface{}{
+				"old_password": "old-password",
+				"new_password": "new-password",
+			},
+			Connection: &logical.Connection{RemoteAddr: remoteAddr},
+		}
+	}
+
+	// The password can't be changed from outside of the bound CIDRs
+	resp, err = b.HandleRequest(context.Background(), changeReq("10.0.0.1"))
+	if err != logical.ErrPermissionDenied {
+		t.Fatalf("expected permission denied, got resp: %#v, err: %v", resp, err)
+	}
+	resp, err = testPasswordRequest(b, storage, logical.UpdateOperation, "login/web", map[string]interface{}{
+		"password": "old-password",
+	})
+	if err != nil || resp == nil || resp.IsError() || resp.Auth == nil {
+		t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+	}
+
+	resp, err = b.HandleRequest(context.Background(), changeReq("127.0.0.1"))
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v\n", resp, err)
+	}
+
+	// Logins from outside of the bound CIDRs don't learn that the password
+	// has expired
+	userEntry, err := b.user(context.Background(), storage, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userEntry.PasswordMaxAge = time.Hour
+	userEntry.PasswordLastChanged = time.Now().Add(-2 * time.Hour)
+	if err := b.setUser(context.Background(), storage, "web", userEntry); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = b.HandleRequest(context.Background(), &logical.Request{
+		Path:      "login/web",
+		Operation: logical.UpdateOperation,
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"password": "new-password",
+		},
+		Connection: &logical.Connection{RemoteAddr: "10.0.0.1"},
+	})
+	if err != logical.ErrPermissionDenied || resp != nil {
+		t.Fatalf("expected permission denied without a response, got resp: %#v, err: %v", resp, err)
+	}
+}
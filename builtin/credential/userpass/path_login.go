@@ -5,6 +5,7 @@ import (
 	"crypto/subtle"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/cidrutil"
@@ -126,6 +127,10 @@ func (b *backend) pathLogin(ctx context.Context, req *logical.Request, d *framew
 		}
 	}
 
+	if user.passwordExpired(time.Now()) {
+		return logical.ErrorResponse("password has expired and must be changed"), logical.ErrPermissionDenied
+	}
+
 	auth := &logical.Auth{
 		Metadata: map[string]string{
 			"username": username,
@@ -2,14 +2,22 @@ package userpass
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
+	"strings"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/cidrutil"
 	"github.com/hashicorp/vault/sdk/logical"
 )
 
+// maxPasswordHistory bounds the number of password hashes compared on every
+// password change, since each comparison is deliberately expensive.
+const maxPasswordHistory = 24
+
 func pathUserPassword(b *backend) *framework.Path {
 	return &framework.Path{
 		Pattern: "users/" + framework.GenericNameRegex("username") + "/password$",
@@ -34,6 +42,45 @@ func pathUserPassword(b *backend) *framework.Path {
 	}
 }
 
+func pathUserPasswordChange(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "users/" + framework.GenericNameRegex("username") + "/password/change$",
+		Fields: map[string]*framework.FieldSchema{
+			"username": {
+				Type:        framework.TypeString,
+				Description: "Username for this user.",
+			},
+
+			"old_password": {
+				Type:        framework.TypeString,
+				Description: "Current password of this user.",
+				DisplayAttrs: &framework.DisplayAttributes{
+					Sensitive: true,
+				},
+			},
+
+			"new_password": {
+				Type:        framework.TypeString,
+				Description: "New password for this user.",
+				DisplayAttrs: &framework.DisplayAttributes{
+					Sensitive: true,
+				},
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation: b.pathUserPasswordChange,
+			// The lookahead lets failed attempts count against the user like
+			// failed logins do, so the endpoint can't be used to guess the
+			// password without being delayed.
+			logical.AliasLookaheadOperation: b.pathLoginAliasLookahead,
+		},
+
+		HelpSynopsis:    pathUserPasswordChangeHelpSyn,
+		HelpDescription: pathUserPasswordChangeHelpDesc,
+	}
+}
+
 func (b *backend) pathUserPasswordUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	username := d.Get("username").(string)
 
@@ -45,10 +92,56 @@ func (b *backend) pathUserPasswordUpdate(ctx context.Context, req *logical.Reque
 		return nil, fmt.Errorf("username does not exist")
 	}
 
-	userErr, intErr := b.updateUserPassword(req, d, userEntry)
+	userErr, intErr := b.updateUserPassword(ctx, req, d, userEntry)
 	if intErr != nil {
+		return nil, intErr
+	}
+	if userErr != nil {
+		return logical.ErrorResponse(userErr.Error()), logical.ErrInvalidRequest
+	}
+
+	return nil, b.setUser(ctx, req.Storage, username, userEntry)
+}
+
+func (b *backend) pathUserPasswordChange(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	username := strings.ToLower(d.Get("username").(string))
+
+	oldPassword := d.Get("old_password").(string)
+	if oldPassword == "" {
+		return logical.ErrorResponse("missing old_password"), logical.ErrInvalidRequest
+	}
+	newPassword := d.Get("new_password").(string)
+	if newPassword == "" {
+		return logical.ErrorResponse("missing new_password"), logical.ErrInvalidRequest
+	}
+
+	userEntry, err := b.user(ctx, req.Storage, username)
+	if err != nil {
 		return nil, err
 	}
+
+	// Check for a CIDR match, so that the password can only be confirmed and
+	// changed from where the user is allowed to log in.
+	if userEntry != nil && len(userEntry.TokenBoundCIDRs) > 0 {
+		if req.Connection == nil {
+			b.Logger().Warn("token bound CIDRs found but no connection information available for validation")
+			return nil, logical.ErrPermissionDenied
+		}
+		if !cidrutil.RemoteAddrIsOk(req.Connection.RemoteAddr, userEntry.TokenBoundCIDRs) {
+			return nil, logical.ErrPermissionDenied
+		}
+	}
+
+	// An expired password can still be used here, as changing it is the only
+	// way for the user to log in again
+	if userEntry == nil || !userEntry.passwordMatches(oldPassword) {
+		return logical.ErrorResponse("invalid username or password"), logical.ErrInvalidCredentials
+	}
+
+	userErr, intErr := b.setUserPassword(ctx, userEntry, newPassword)
+	if intErr != nil {
+		return nil, intErr
+	}
 	if userErr != nil {
 		return logical.ErrorResponse(userErr.Error()), logical.ErrInvalidRequest
 	}
@@ -56,20 +149,64 @@ func (b *backend) pathUserPasswordUpdate(ctx context.Context, req *logical.Reque
 	return nil, b.setUser(ctx, req.Storage, username, userEntry)
 }
 
-func (b *backend) updateUserPassword(req *logical.Request, d *framework.FieldData, userEntry *UserEntry) (error, error) {
+func (b *backend) updateUserPassword(ctx context.Context, req *logical.Request, d *framework.FieldData, userEntry *UserEntry) (error, error) {
 	password := d.Get("password").(string)
 	if password == "" {
 		return fmt.Errorf("missing password"), nil
 	}
+	return b.setUserPassword(ctx, userEntry, password)
+}
+
+// setUserPassword checks the password against the password policy and history
+// of the user before storing its hash.
+func (b *backend) setUserPassword(ctx context.Context, userEntry *UserEntry, password string) (error, error) {
+	if userEntry.PasswordPolicy != "" {
+		validator, ok := b.System().(logical.PasswordPolicyValidator)
+		if !ok {
+			return nil, fmt.Errorf("password policies are not supported by the system view")
+		}
+		if err := validator.ValidatePasswordAgainstPolicy(ctx, userEntry.PasswordPolicy, password); err != nil {
+			return err, nil
+		}
+	}
+
+	if userEntry.PasswordHistory > 0 {
+		if userEntry.passwordMatches(password) {
+			return fmt.Errorf("password was used recently and can't be reused"), nil
+		}
+		for _, hash := range userEntry.PreviousPasswordHashes {
+			if bcrypt.CompareHashAndPassword(hash, []byte(password)) == nil {
+				return fmt.Errorf("password was used recently and can't be reused"), nil
+			}
+		}
+	}
+
 	// Generate a hash of the password
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
 	}
+
+	if userEntry.PasswordHistory > 1 && userEntry.PasswordHash != nil {
+		userEntry.PreviousPasswordHashes = append([][]byte{userEntry.PasswordHash}, userEntry.PreviousPasswordHashes...)
+	}
+	userEntry.trimPasswordHistory()
+
 	userEntry.PasswordHash = hash
+	userEntry.Password = ""
+	userEntry.PasswordLastChanged = time.Now().UTC()
 	return nil, nil
 }
 
+// passwordMatches returns true if the password is the current password of the
+// user.
+func (u *UserEntry) passwordMatches(password string) bool {
+	if u.PasswordHash == nil {
+		return u.Password != "" && subtle.ConstantTimeCompare([]byte(u.Password), []byte(password)) == 1
+	}
+	return bcrypt.CompareHashAndPassword(u.PasswordHash, []byte(password)) == nil
+}
+
 const pathUserPasswordHelpSyn = `
 Reset user's password.
 `
@@ -77,3 +214,14 @@ Reset user's password.
 const pathUserPasswordHelpDesc = `
 This endpoint allows resetting the user's password.
 `
+
+const pathUserPasswordChangeHelpSyn = `
+Change user's password using the current password.
+`
+
+const pathUserPasswordChangeHelpDesc = `
+This endpoint allows users to change their own password by providing their
+current password, without a token. It can be used when the password has
+expired. The new password must satisfy the password policy of the user and
+must not have been used recently if a password history is configured.
+`
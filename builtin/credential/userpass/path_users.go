@@ -46,6 +46,21 @@ func pathUsers(b *backend) *framework.Path {
 				},
 			},
 
+			"password_policy": {
+				Type:        framework.TypeString,
+				Description: "Name of the password policy new passwords of this user must satisfy.",
+			},
+
+			"password_history": {
+				Type:        framework.TypeInt,
+				Description: fmt.Sprintf("Number of recent passwords, including the current one, that can't be reused. Can be at most %d.", maxPasswordHistory),
+			},
+
+			"password_max_age": {
+				Type:        framework.TypeDurationSecond,
+				Description: "Duration after which the password expires and must be changed before logging in again. If not set, the password does not expire.",
+			},
+
 			"policies": {
 				Type:        framework.TypeCommaStringSlice,
 				Description: tokenutil.DeprecationText("token_policies"),
@@ -187,6 +202,20 @@ func (b *backend) pathUserRead(ctx context.Context, req *logical.Request, d *fra
 		data["bound_cidrs"] = user.BoundCIDRs
 	}
 
+	data["password_policy"] = user.PasswordPolicy
+	data["password_history"] = user.PasswordHistory
+	data["password_max_age"] = int64(user.PasswordMaxAge.Seconds())
+
+	// Users whose password was set before the change time was tracked don't
+	// have an age
+	if !user.PasswordLastChanged.IsZero() {
+		data["password_last_changed"] = user.PasswordLastChanged.Format(time.RFC3339)
+		data["password_age"] = int64(time.Since(user.PasswordLastChanged).Seconds())
+		if user.PasswordMaxAge > 0 {
+			data["password_expiration"] = user.PasswordLastChanged.Add(user.PasswordMaxAge).Format(time.RFC3339)
+		}
+	}
+
 	return &logical.Response{
 		Data: data,
 	}, nil
@@ -207,8 +236,29 @@ func (b *backend) userCreateUpdate(ctx context.Context, req *logical.Request, d
 		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
 	}
 
+	// The password settings are applied first, so that a password set in the
+	// same request is checked against them
+	if passwordPolicy, ok := d.GetOk("password_policy"); ok {
+		userEntry.PasswordPolicy = passwordPolicy.(string)
+	}
+	if passwordHistory, ok := d.GetOk("password_history"); ok {
+		history := passwordHistory.(int)
+		if history < 0 || history > maxPasswordHistory {
+			return logical.ErrorResponse("password_history must be between 0 and %d", maxPasswordHistory), logical.ErrInvalidRequest
+		}
+		userEntry.PasswordHistory = history
+		userEntry.trimPasswordHistory()
+	}
+	if passwordMaxAge, ok := d.GetOk("password_max_age"); ok {
+		maxAge := time.Duration(passwordMaxAge.(int)) * time.Second
+		if maxAge < 0 {
+			return logical.ErrorResponse("password_max_age must not be negative"), logical.ErrInvalidRequest
+		}
+		userEntry.PasswordMaxAge = maxAge
+	}
+
 	if _, ok := d.GetOk("password"); ok {
-		userErr, intErr := b.updateUserPassword(req, d, userEntry)
+		userErr, intErr := b.updateUserPassword(ctx, req, d, userEntry)
 		if intErr != nil {
 			return nil, intErr
 		}
@@ -267,6 +317,48 @@ type UserEntry struct {
 	MaxTTL time.Duration
 
 	BoundCIDRs []*sockaddr.SockAddrMarshaler
+
+	// PasswordPolicy is the name of the password policy new passwords must
+	// satisfy
+	PasswordPolicy string
+
+	// PasswordHistory is the number of recent passwords, including the
+	// current one, that can't be reused
+	PasswordHistory int
+
+	// PreviousPasswordHashes are the bcrypt hashes of the passwords preceding
+	// the current one, most recent first
+	PreviousPasswordHashes [][]byte
+
+	// PasswordMaxAge is the duration after which the password expires
+	PasswordMaxAge time.Duration
+
+	// PasswordLastChanged is the time the password was last set
+	PasswordLastChanged time.Time
+}
+
+// passwordExpired returns true if the password is older than the maximum
+// password age.
+func (u *UserEntry) passwordExpired(now time.Time) bool {
+	if u.PasswordMaxAge <= 0 || u.PasswordLastChanged.IsZero() {
+		return false
+	}
+	return now.After(u.PasswordLastChanged.Add(u.PasswordMaxAge))
+}
+
+// trimPasswordHistory drops the previous password hashes no longer needed to
+// enforce the password history.
+func (u *UserEntry) trimPasswordHistory() {
+	keep := u.PasswordHistory - 1
+	if keep < 0 {
+		keep = 0
+	}
+	if len(u.PreviousPasswordHashes) > keep {
+		u.PreviousPasswordHashes = u.PreviousPasswordHashes[:keep]
+	}
+	if len(u.PreviousPasswordHashes) == 0 {
+		u.PreviousPasswordHashes = nil
+	}
 }
 
 const pathUserHelpSyn = `
@@ -36,6 +36,9 @@ type AuthTuneCommand struct {
 	flagUserLockoutDuration             time.Duration
 	flagUserLockoutCounterResetDuration time.Duration
 	flagUserLockoutDisable              bool
+	flagUserLockoutProgressiveDelay     bool
+	flagUserLockoutProgressiveDelayBase time.Duration
+	flagUserLockoutProgressiveDelayMax  time.Duration
 }
 
 func (c *AuthTuneCommand) Synopsis() string {
@@ -184,6 +187,35 @@ func (c *AuthTuneCommand) Flags() *FlagSets {
 			"or a previously configured value for the auth method.",
 	})
 
+	f.BoolVar(&BoolVar{
+		Name:    flagNameUserLockoutProgressiveDelay,
+		Target:  &c.flagUserLockoutProgressiveDelay,
+		Default: false,
+		Usage: "Delay login attempts exponentially once the user lockout threshold is " +
+			"reached instead of locking the user out. If unspecified, this defaults to " +
+			"the Vault server's globally configured progressive delay setting, " +
+			"or a previously configured value for the auth method.",
+	})
+
+	f.DurationVar(&DurationVar{
+		Name:       flagNameUserLockoutProgressiveDelayBase,
+		Target:     &c.flagUserLockoutProgressiveDelayBase,
+		Completion: complete.PredictAnything,
+		Usage: "The delay imposed once the user lockout threshold is reached; it doubles " +
+			"with every further failed login. If unspecified, this defaults to the Vault " +
+			"server's globally configured progressive delay base, or a previously " +
+			"configured value for the auth method.",
+	})
+
+	f.DurationVar(&DurationVar{
+		Name:       flagNameUserLockoutProgressiveDelayMax,
+		Target:     &c.flagUserLockoutProgressiveDelayMax,
+		Completion: complete.PredictAnything,
+		Usage: "The maximum delay between login attempts. If unspecified, this defaults " +
+			"to the Vault server's globally configured progressive delay maximum, or a " +
+			"previously configured value for the auth method.",
+	})
+
 	f.StringVar(&StringVar{
 		Name:    flagNamePluginVersion,
 		Target:  &c.flagPluginVersion,
@@ -270,7 +302,8 @@ func (c *AuthTuneCommand) Run(args []string) int {
 			mountConfigInput.TokenType = c.flagTokenType
 		}
 		switch fl.Name {
-		case flagNameUserLockoutThreshold, flagNameUserLockoutDuration, flagNameUserLockoutCounterResetDuration, flagNameUserLockoutDisable:
+		case flagNameUserLockoutThreshold, flagNameUserLockoutDuration, flagNameUserLockoutCounterResetDuration, flagNameUserLockoutDisable,
+			flagNameUserLockoutProgressiveDelay, flagNameUserLockoutProgressiveDelayBase, flagNameUserLockoutProgressiveDelayMax:
 			if mountConfigInput.UserLockoutConfig == nil {
 				mountConfigInput.UserLockoutConfig = &api.UserLockoutConfigInput{}
 			}
@@ -287,6 +320,15 @@ func (c *AuthTuneCommand) Run(args []string) int {
 		if fl.Name == flagNameUserLockoutDisable {
 			mountConfigInput.UserLockoutConfig.DisableLockout = &c.flagUserLockoutDisable
 		}
+		if fl.Name == flagNameUserLockoutProgressiveDelay {
+			mountConfigInput.UserLockoutConfig.ProgressiveDelay = &c.flagUserLockoutProgressiveDelay
+		}
+		if fl.Name == flagNameUserLockoutProgressiveDelayBase {
+			mountConfigInput.UserLockoutConfig.ProgressiveDelayBase = ttlToAPI(c.flagUserLockoutProgressiveDelayBase)
+		}
+		if fl.Name == flagNameUserLockoutProgressiveDelayMax {
+			mountConfigInput.UserLockoutConfig.ProgressiveDelayMax = ttlToAPI(c.flagUserLockoutProgressiveDelayMax)
+		}
 
 		if fl.Name == flagNamePluginVersion {
 			mountConfigInput.PluginVersion = c.flagPluginVersion
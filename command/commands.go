@@ -139,6 +139,12 @@ const (
 	flagNameUserLockoutCounterResetDuration = "user-lockout-counter-reset-duration"
 	// flagNameUserLockoutDisable is the flag name used for tuning the auth mount disable lockout parameter
 	flagNameUserLockoutDisable = "user-lockout-disable"
+	// flagNameUserLockoutProgressiveDelay is the flag name used for tuning the auth mount progressive delay parameter
+	flagNameUserLockoutProgressiveDelay = "user-lockout-progressive-delay"
+	// flagNameUserLockoutProgressiveDelayBase is the flag name used for tuning the auth mount progressive delay base parameter
+	flagNameUserLockoutProgressiveDelayBase = "user-lockout-progressive-delay-base"
+	// flagNameUserLockoutProgressiveDelayMax is the flag name used for tuning the auth mount progressive delay max parameter
+	flagNameUserLockoutProgressiveDelayMax = "user-lockout-progressive-delay-max"
 	// flagNameDisableRedirects is used to prevent the client from honoring a single redirect as a response to a request
 	flagNameDisableRedirects = "disable-redirects"
 	// flagNameCombineLogs is used to specify whether log output should be combined and sent to stdout
@@ -431,6 +437,26 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				ShutdownCh:       MakeShutdownCh(),
 			}, nil
 		},
+		"operator migrate copy": func() (cli.Command, error) {
+			return &OperatorMigrateCopyCommand{
+				BaseCommand: getBaseCommand(),
+			}, nil
+		},
+		"operator migrate cutover": func() (cli.Command, error) {
+			return &OperatorMigrateCutoverCommand{
+				BaseCommand: getBaseCommand(),
+			}, nil
+		},
+		"operator migrate status": func() (cli.Command, error) {
+			return &OperatorMigrateStatusCommand{
+				BaseCommand: getBaseCommand(),
+			}, nil
+		},
+		"operator migrate verify": func() (cli.Command, error) {
+			return &OperatorMigrateVerifyCommand{
+				BaseCommand: getBaseCommand(),
+			}, nil
+		},
 		"operator raft": func() (cli.Command, error) {
 			return &OperatorRaftCommand{
 				BaseCommand: getBaseCommand(),
@@ -471,6 +497,16 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				BaseCommand: getBaseCommand(),
 			}, nil
 		},
+		"operator raft snapshot extract": func() (cli.Command, error) {
+			return &OperatorRaftSnapshotExtractCommand{
+				BaseCommand: getBaseCommand(),
+			}, nil
+		},
+		"operator raft snapshot inspect": func() (cli.Command, error) {
+			return &OperatorRaftSnapshotInspectCommand{
+				BaseCommand: getBaseCommand(),
+			}, nil
+		},
 		"operator raft snapshot restore": func() (cli.Command, error) {
 			return &OperatorRaftSnapshotRestoreCommand{
 				BaseCommand: getBaseCommand(),
@@ -546,6 +582,11 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				BaseCommand: getBaseCommand(),
 			}, nil
 		},
+		"plugin install": func() (cli.Command, error) {
+			return &PluginInstallCommand{
+				BaseCommand: getBaseCommand(),
+			}, nil
+		},
 		"plugin register": func() (cli.Command, error) {
 			return &PluginRegisterCommand{
 				BaseCommand: getBaseCommand(),
@@ -561,6 +602,11 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				BaseCommand: getBaseCommand(),
 			}, nil
 		},
+		"plugin sandbox": func() (cli.Command, error) {
+			return &PluginSandboxCommand{
+				BaseCommand: getBaseCommand(),
+			}, nil
+		},
 		"policy": func() (cli.Command, error) {
 			return &PolicyCommand{
 				BaseCommand: getBaseCommand(),
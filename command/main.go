@@ -219,7 +219,7 @@ func RunCustom(args []string, runOpts *RunOptions) int {
 
 	initCommands(ui, serverCmdUi, runOpts)
 
-	hiddenCommands := []string{"version"}
+	hiddenCommands := []string{"version", "plugin sandbox"}
 
 	cli := &cli.CLI{
 		Name:     "vault",
@@ -65,6 +65,10 @@ Usage: vault operator migrate [options]
 
       $ vault operator migrate -config=migrate.hcl
 
+  To migrate without stopping Vault, add a "storage_destination" stanza to the
+  server configuration of the active node and use the "copy", "verify" and
+  "cutover" subcommands.
+
   For more information, please see the documentation.
 
 ` + c.Flags().Help()
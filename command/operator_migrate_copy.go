@@ -0,0 +1,83 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var (
+	_ cli.Command             = (*OperatorMigrateCopyCommand)(nil)
+	_ cli.CommandAutocomplete = (*OperatorMigrateCopyCommand)(nil)
+)
+
+type OperatorMigrateCopyCommand struct {
+	*BaseCommand
+}
+
+func (c *OperatorMigrateCopyCommand) Synopsis() string {
+	return "Starts copying the storage of an online storage migration"
+}
+
+func (c *OperatorMigrateCopyCommand) Help() string {
+	helpText := `
+Usage: vault operator migrate copy [options]
+
+  Starts copying the keys of the source storage to the destination storage of
+  an online storage migration. The copy runs in the background on the active
+  node while it keeps serving requests; writes made meanwhile are applied to
+  both storage. Keys of the destination storage that don't exist in the source
+  storage are removed once all keys are copied.
+
+  The copy is canceled if the active node seals or steps down, and must then
+  be started again.
+
+      $ vault operator migrate copy
+
+` + c.Flags().Help()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorMigrateCopyCommand) Flags() *FlagSets {
+	return c.flagSet(FlagSetHTTP)
+}
+
+func (c *OperatorMigrateCopyCommand) AutocompleteArgs() complete.Predictor {
+	return nil
+}
+
+func (c *OperatorMigrateCopyCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *OperatorMigrateCopyCommand) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = f.Args()
+	if len(args) > 0 {
+		c.UI.Error(fmt.Sprintf("Too many arguments (expected 0, got %d)", len(args)))
+		return 1
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 2
+	}
+
+	if _, err := client.Logical().Write("sys/storage/migration/copy", nil); err != nil {
+		c.UI.Error(fmt.Sprintf("Error starting storage migration copy: %s", err))
+		return 2
+	}
+
+	c.UI.Output("Success! Started copying the source storage. Use \"vault operator migrate status\" to follow its progress.")
+	return 0
+}
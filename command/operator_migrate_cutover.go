@@ -0,0 +1,82 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var (
+	_ cli.Command             = (*OperatorMigrateCutoverCommand)(nil)
+	_ cli.CommandAutocomplete = (*OperatorMigrateCutoverCommand)(nil)
+)
+
+type OperatorMigrateCutoverCommand struct {
+	*BaseCommand
+}
+
+func (c *OperatorMigrateCutoverCommand) Synopsis() string {
+	return "Completes an online storage migration"
+}
+
+func (c *OperatorMigrateCutoverCommand) Help() string {
+	helpText := `
+Usage: vault operator migrate cutover [options]
+
+  Completes an online storage migration that has been copied and verified.
+  The storage of the active node becomes read-only, and the source storage is
+  locked so that servers can no longer start on it. The active node must then
+  be restarted with the destination storage as its "storage" stanza.
+
+  The cutover is refused if writes to the destination storage failed since
+  the last verification.
+
+      $ vault operator migrate cutover
+
+` + c.Flags().Help()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorMigrateCutoverCommand) Flags() *FlagSets {
+	return c.flagSet(FlagSetHTTP)
+}
+
+func (c *OperatorMigrateCutoverCommand) AutocompleteArgs() complete.Predictor {
+	return nil
+}
+
+func (c *OperatorMigrateCutoverCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *OperatorMigrateCutoverCommand) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = f.Args()
+	if len(args) > 0 {
+		c.UI.Error(fmt.Sprintf("Too many arguments (expected 0, got %d)", len(args)))
+		return 1
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 2
+	}
+
+	if _, err := client.Logical().Write("sys/storage/migration/cutover", nil); err != nil {
+		c.UI.Error(fmt.Sprintf("Error completing storage migration cutover: %s", err))
+		return 2
+	}
+
+	c.UI.Output("Success! Storage migration cutover completed. Restart the server with the destination storage.")
+	return 0
+}
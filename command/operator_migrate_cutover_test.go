@@ -0,0 +1,136 @@
+package command
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/sdk/physical/inmem"
+	"github.com/hashicorp/vault/vault"
+	"github.com/mitchellh/cli"
+)
+
+func testOperatorMigrateOnlineCommands(tb testing.TB) (*cli.MockUi, map[string]cli.Command) {
+	tb.Helper()
+
+	ui := cli.NewMockUi()
+	base := &BaseCommand{UI: ui}
+	return ui, map[string]cli.Command{
+		"status":  &OperatorMigrateStatusCommand{BaseCommand: base},
+		"copy":    &OperatorMigrateCopyCommand{BaseCommand: base},
+		"verify":  &OperatorMigrateVerifyCommand{BaseCommand: base},
+		"cutover": &OperatorMigrateCutoverCommand{BaseCommand: base},
+	}
+}
+
+func TestOperatorMigrateOnlineCommands_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("validations", func(t *testing.T) {
+		t.Parallel()
+
+		_, cmds := testOperatorMigrateOnlineCommands(t)
+		for name, cmd := range cmds {
+			if code := cmd.Run([]string{"foo"}); code != 1 {
+				t.Errorf("%s: expected 1, got %d", name, code)
+			}
+		}
+	})
+
+	t.Run("not_configured", func(t *testing.T) {
+		t.Parallel()
+
+		client, closer := testVaultServer(t)
+		defer closer()
+
+		ui, cmds := testOperatorMigrateOnlineCommands(t)
+		cmds["status"].(*OperatorMigrateStatusCommand).client = client
+		if code := cmds["status"].Run(nil); code != 2 {
+			t.Fatalf("expected 2, got %d", code)
+		}
+		if !strings.Contains(ui.ErrorWriter.String(), "No storage migration status found") {
+			t.Fatalf("bad output: %s", ui.ErrorWriter.String())
+		}
+	})
+
+	t.Run("copy_verify_cutover", func(t *testing.T) {
+		t.Parallel()
+
+		dest, err := inmem.NewInmem(nil, hclog.NewNullLogger())
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, _, closer := testVaultServerCoreConfig(t, &vault.CoreConfig{
+			StorageMigrationDestination: dest,
+		})
+		defer closer()
+
+		// Cutting over before the verification is refused
+		ui, cmds := testOperatorMigrateOnlineCommands(t)
+		cmds["cutover"].(*OperatorMigrateCutoverCommand).client = client
+		if code := cmds["cutover"].Run(nil); code != 2 {
+			t.Fatalf("expected 2, got %d", code)
+		}
+
+		status := func() map[string]interface{} {
+			ui, cmds := testOperatorMigrateOnlineCommands(t)
+			cmd := cmds["status"].(*OperatorMigrateStatusCommand)
+			cmd.client = client
+			cmd.UI = &VaultUI{Ui: ui, format: "json"}
+			if code := cmd.Run(nil); code != 0 {
+				t.Fatalf("expected 0, got %d: %s", code, ui.ErrorWriter.String())
+			}
+			var secret struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			if err := json.Unmarshal(ui.OutputWriter.Bytes(), &secret); err != nil {
+				t.Fatal(err)
+			}
+			return secret.Data
+		}
+
+		for _, op := range []string{"copy", "verify"} {
+			ui, cmds = testOperatorMigrateOnlineCommands(t)
+			switch cmd := cmds[op].(type) {
+			case *OperatorMigrateCopyCommand:
+				cmd.client = client
+			case *OperatorMigrateVerifyCommand:
+				cmd.client = client
+			}
+			if code := cmds[op].Run(nil); code != 0 {
+				t.Fatalf("%s: expected 0, got %d: %s", op, code, ui.ErrorWriter.String())
+			}
+
+			deadline := time.Now().Add(10 * time.Second)
+			for {
+				state := status()["state"]
+				if state != "copying" && state != "verifying" {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("%s did not complete", op)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+
+		if data := status(); data["state"] != "verified" {
+			t.Fatalf("expected verified state, got: %v", data)
+		}
+
+		ui, cmds = testOperatorMigrateOnlineCommands(t)
+		cmds["cutover"].(*OperatorMigrateCutoverCommand).client = client
+		if code := cmds["cutover"].Run(nil); code != 0 {
+			t.Fatalf("expected 0, got %d: %s", code, ui.ErrorWriter.String())
+		}
+		if !strings.Contains(ui.OutputWriter.String(), "Success! Storage migration cutover completed") {
+			t.Fatalf("bad output: %s", ui.OutputWriter.String())
+		}
+
+		if data := status(); data["state"] != "cutover" {
+			t.Fatalf("expected cutover state, got: %v", data)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var (
+	_ cli.Command             = (*OperatorMigrateStatusCommand)(nil)
+	_ cli.CommandAutocomplete = (*OperatorMigrateStatusCommand)(nil)
+)
+
+type OperatorMigrateStatusCommand struct {
+	*BaseCommand
+}
+
+func (c *OperatorMigrateStatusCommand) Synopsis() string {
+	return "Returns the status of an online storage migration"
+}
+
+func (c *OperatorMigrateStatusCommand) Help() string {
+	helpText := `
+Usage: vault operator migrate status [options]
+
+  Returns the status of the online storage migration of the active node, which
+  must be configured with a "storage_destination" stanza. The status reports
+  the progress of the last copy or verification and the number of writes that
+  failed on the destination storage.
+
+      $ vault operator migrate status
+
+` + c.Flags().Help()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorMigrateStatusCommand) Flags() *FlagSets {
+	return c.flagSet(FlagSetHTTP | FlagSetOutputFormat)
+}
+
+func (c *OperatorMigrateStatusCommand) AutocompleteArgs() complete.Predictor {
+	return nil
+}
+
+func (c *OperatorMigrateStatusCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *OperatorMigrateStatusCommand) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = f.Args()
+	if len(args) > 0 {
+		c.UI.Error(fmt.Sprintf("Too many arguments (expected 0, got %d)", len(args)))
+		return 1
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 2
+	}
+
+	secret, err := client.Logical().Read("sys/storage/migration")
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading storage migration status: %s", err))
+		return 2
+	}
+	if secret == nil {
+		c.UI.Error("No storage migration status found")
+		return 2
+	}
+
+	return OutputSecret(c.UI, secret)
+}
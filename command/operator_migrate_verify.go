@@ -0,0 +1,81 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var (
+	_ cli.Command             = (*OperatorMigrateVerifyCommand)(nil)
+	_ cli.CommandAutocomplete = (*OperatorMigrateVerifyCommand)(nil)
+)
+
+type OperatorMigrateVerifyCommand struct {
+	*BaseCommand
+}
+
+func (c *OperatorMigrateVerifyCommand) Synopsis() string {
+	return "Starts verifying the storage of an online storage migration"
+}
+
+func (c *OperatorMigrateVerifyCommand) Help() string {
+	helpText := `
+Usage: vault operator migrate verify [options]
+
+  Starts comparing all the keys of the source and destination storage of an
+  online storage migration. The verification runs in the background on the
+  active node. The keys that are missing, extra or different in the
+  destination storage are reported by "vault operator migrate status".
+
+  A successful verification is required for the cutover.
+
+      $ vault operator migrate verify
+
+` + c.Flags().Help()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorMigrateVerifyCommand) Flags() *FlagSets {
+	return c.flagSet(FlagSetHTTP)
+}
+
+func (c *OperatorMigrateVerifyCommand) AutocompleteArgs() complete.Predictor {
+	return nil
+}
+
+func (c *OperatorMigrateVerifyCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *OperatorMigrateVerifyCommand) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = f.Args()
+	if len(args) > 0 {
+		c.UI.Error(fmt.Sprintf("Too many arguments (expected 0, got %d)", len(args)))
+		return 1
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 2
+	}
+
+	if _, err := client.Logical().Write("sys/storage/migration/verify", nil); err != nil {
+		c.UI.Error(fmt.Sprintf("Error starting storage migration verification: %s", err))
+		return 2
+	}
+
+	c.UI.Output("Success! Started verifying the destination storage. Use \"vault operator migrate status\" to follow its progress.")
+	return 0
+}
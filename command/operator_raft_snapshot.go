@@ -24,6 +24,10 @@ Usage: vault operator raft snapshot <subcommand> [options] [args]
   functionality of the integrated Raft storage backend. Here are a few examples of
   the Raft snapshot operator commands:
 
+  Reports the contents of a snapshot file:
+
+      $ vault operator raft snapshot inspect raft.snap
+
   Installs the provided snapshot, returning the cluster to the state defined in it:
 
       $ vault operator raft snapshot restore raft.snap
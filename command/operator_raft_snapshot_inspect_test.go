@@ -0,0 +1,191 @@
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/hashicorp/vault/helper/testhelpers/teststorage"
+	vaulthttp "github.com/hashicorp/vault/http"
+	"github.com/hashicorp/vault/vault"
+	"github.com/mitchellh/cli"
+)
+
+func testOperatorRaftSnapshotInspectCommand(tb testing.TB) (*cli.MockUi, *OperatorRaftSnapshotInspectCommand) {
+	tb.Helper()
+
+	ui := cli.NewMockUi()
+	return ui, &OperatorRaftSnapshotInspectCommand{
+		BaseCommand: &BaseCommand{
+			UI: ui,
+		},
+	}
+}
+
+func testOperatorRaftSnapshotExtractCommand(tb testing.TB) (*cli.MockUi, *OperatorRaftSnapshotExtractCommand) {
+	tb.Helper()
+
+	ui := cli.NewMockUi()
+	return ui, &OperatorRaftSnapshotExtractCommand{
+		BaseCommand: &BaseCommand{
+			UI: ui,
+		},
+	}
+}
+
+// testRaftSnapshotFile saves a snapshot of a raft cluster with a kv mount to a
+// file and returns the path of the file and the UUID of the kv mount
+func testRaftSnapshotFile(t *testing.T) (string, string) {
+	t.Helper()
+
+	opts := &vault.TestClusterOptions{
+		HandlerFunc: vaulthttp.Handler,
+		NumCores:    1,
+	}
+	teststorage.RaftBackendSetup(nil, opts)
+	client, _, closer := testVaultServerCoreConfigWithOpts(t, &vault.CoreConfig{}, opts)
+	defer closer()
+
+	if err := client.Sys().Mount("kv", &api.MountInput{Type: "kv"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Logical().Write("kv/foo", map[string]interface{}{"bar": "baz"}); err != nil {
+		t.Fatal(err)
+	}
+	mounts, err := client.Sys().ListMounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "raft.snap")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := client.Sys().RaftSnapshot(f); err != nil {
+		t.Fatal(err)
+	}
+
+	return path, mounts["kv/"].UUID
+}
+
+func TestOperatorRaftSnapshotInspectCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("validations", func(t *testing.T) {
+		t.Parallel()
+
+		cases := []struct {
+			name string
+			args []string
+			out  string
+			code int
+		}{
+			{"no_args", nil, "Incorrect arguments", 1},
+			{"too_many_args", []string{"foo", "bar"}, "Incorrect arguments", 1},
+			{"bad_depth", []string{"-depth=0", "foo"}, "Depth must be at least 1", 1},
+			{"missing_file", []string{"/nonexistent/raft.snap"}, "Error opening snapshot file", 2},
+		}
+
+		for _, tc := range cases {
+			ui, cmd := testOperatorRaftSnapshotInspectCommand(t)
+			code := cmd.Run(tc.args)
+			if code != tc.code {
+				t.Errorf("%s: expected %d to be %d", tc.name, code, tc.code)
+			}
+			combined := ui.OutputWriter.String() + ui.ErrorWriter.String()
+			if !strings.Contains(combined, tc.out) {
+				t.Errorf("%s: expected %q to contain %q", tc.name, combined, tc.out)
+			}
+		}
+	})
+
+	t.Run("inspect_and_extract", func(t *testing.T) {
+		t.Parallel()
+
+		snapFile, kvUUID := testRaftSnapshotFile(t)
+		kvPrefix := "logical/" + kvUUID + "/"
+
+		ui, cmd := testOperatorRaftSnapshotInspectCommand(t)
+		cmd.UI = &VaultUI{Ui: ui, format: "json"}
+		if code := cmd.Run([]string{snapFile}); code != 0 {
+			t.Fatalf("expected 0, got %d: %s", code, ui.ErrorWriter.String())
+		}
+
+		var inspection raftSnapshotInspection
+		if err := json.Unmarshal(ui.OutputWriter.Bytes(), &inspection); err != nil {
+			t.Fatal(err)
+		}
+		if inspection.Index == 0 || inspection.TotalKeys == 0 {
+			t.Fatalf("bad inspection: %#v", inspection)
+		}
+
+		var kvMount *raftSnapshotMountStats
+		for _, mount := range inspection.Mounts {
+			if mount.UUID == kvUUID {
+				kvMount = mount
+			}
+		}
+		if kvMount == nil || kvMount.Type != "secret" || kvMount.Prefix != kvPrefix || kvMount.Keys == 0 {
+			t.Fatalf("expected kv mount to be reported, got: %#v", inspection.Mounts)
+		}
+
+		outFile := filepath.Join(t.TempDir(), "kv.snap")
+		extractUI, extractCmd := testOperatorRaftSnapshotExtractCommand(t)
+		if code := extractCmd.Run([]string{"-prefix=" + kvPrefix, snapFile, outFile}); code != 0 {
+			t.Fatalf("expected 0, got %d: %s", code, extractUI.ErrorWriter.String())
+		}
+
+		// Extracting to an existing file is refused
+		extractUI, extractCmd = testOperatorRaftSnapshotExtractCommand(t)
+		if code := extractCmd.Run([]string{"-prefix=" + kvPrefix, snapFile, outFile}); code != 2 {
+			t.Fatalf("expected 2, got %d", code)
+		}
+
+		ui, cmd = testOperatorRaftSnapshotInspectCommand(t)
+		cmd.UI = &VaultUI{Ui: ui, format: "json"}
+		if code := cmd.Run([]string{"-depth=1", outFile}); code != 0 {
+			t.Fatalf("expected 0, got %d: %s", code, ui.ErrorWriter.String())
+		}
+
+		var extracted raftSnapshotInspection
+		if err := json.Unmarshal(ui.OutputWriter.Bytes(), &extracted); err != nil {
+			t.Fatal(err)
+		}
+		if extracted.Index != inspection.Index {
+			t.Fatalf("expected index %d, got %d", inspection.Index, extracted.Index)
+		}
+		if len(extracted.Mounts) != 1 || extracted.Mounts[0].Keys != kvMount.Keys {
+			t.Fatalf("expected only the kv mount, got: %#v", extracted.Mounts)
+		}
+		for _, prefix := range extracted.Prefixes {
+			if prefix.Prefix != "core/" && prefix.Prefix != "logical/" {
+				t.Fatalf("unexpected prefix %q", prefix.Prefix)
+			}
+		}
+	})
+}
+
+func TestRaftSnapshotKeyPrefix(t *testing.T) {
+	cases := []struct {
+		key    string
+		depth  int
+		prefix string
+	}{
+		{"core/mounts", 1, "core/"},
+		{"core/mounts", 2, "core/mounts"},
+		{"logical/1234/foo/bar", 2, "logical/1234/"},
+		{"logical/1234/foo/bar", 3, "logical/1234/foo/"},
+		{"barrier/keyring", 5, "barrier/keyring"},
+	}
+
+	for _, tc := range cases {
+		if prefix := raftSnapshotKeyPrefix(tc.key, tc.depth); prefix != tc.prefix {
+			t.Errorf("%s at depth %d: expected %q, got %q", tc.key, tc.depth, tc.prefix, prefix)
+		}
+	}
+}
@@ -33,6 +33,10 @@ Usage: vault plugin <subcommand> [options] [args]
 
       $ vault plugin register -sha256=d3f0a8b... auth my-custom-plugin
 
+  Install and register the versions of a plugin from a signed plugin bundle:
+
+      $ vault plugin install my-custom-plugin.tar.gz
+
   Get information about a plugin in the catalog listed under a particular type:
 
       $ vault plugin info auth my-custom-plugin
@@ -129,6 +129,14 @@ func (c *PluginInfoCommand) Run(args []string) int {
 		"deprecation_status": resp.DeprecationStatus,
 		"version":            resp.Version,
 	}
+	if resp.MemoryLimit != 0 || resp.CPUQuota != 0 || resp.UID != 0 || resp.GID != 0 || resp.RestrictEnv || resp.SeccompProfile != "" {
+		data["memory_limit"] = resp.MemoryLimit
+		data["cpu_quota"] = resp.CPUQuota
+		data["uid"] = resp.UID
+		data["gid"] = resp.GID
+		data["restrict_env"] = resp.RestrictEnv
+		data["seccomp_profile"] = resp.SeccompProfile
+	}
 
 	if c.flagField != "" {
 		return PrintRawField(c.UI, data, c.flagField)
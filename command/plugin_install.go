@@ -0,0 +1,93 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var (
+	_ cli.Command             = (*PluginInstallCommand)(nil)
+	_ cli.CommandAutocomplete = (*PluginInstallCommand)(nil)
+)
+
+type PluginInstallCommand struct {
+	*BaseCommand
+}
+
+func (c *PluginInstallCommand) Synopsis() string {
+	return "Installs a plugin from a signed plugin bundle"
+}
+
+func (c *PluginInstallCommand) Help() string {
+	helpText := `
+Usage: vault plugin install [options] BUNDLE
+
+  Installs a plugin from a signed plugin bundle. The bundle must exist in
+  Vault's configured plugin bundle directory, and its manifest must be signed
+  by one of the configured trusted keys. The binary of each version of the
+  plugin for the platform of Vault is unpacked into the plugin directory and
+  registered in the catalog. The binaries are only unpacked on the node
+  handling the request, so the bundle must be installed on every node unless
+  the plugin directory is shared.
+
+  Install the plugin of the bundle my-custom-plugin.tar.gz:
+
+      $ vault plugin install my-custom-plugin.tar.gz
+
+` + c.Flags().Help()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *PluginInstallCommand) Flags() *FlagSets {
+	return c.flagSet(FlagSetHTTP)
+}
+
+func (c *PluginInstallCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *PluginInstallCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *PluginInstallCommand) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = f.Args()
+	switch {
+	case len(args) < 1:
+		c.UI.Error(fmt.Sprintf("Not enough arguments (expected 1, got %d)", len(args)))
+		return 1
+	case len(args) > 1:
+		c.UI.Error(fmt.Sprintf("Too many arguments (expected 1, got %d)", len(args)))
+		return 1
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 2
+	}
+
+	bundle := strings.TrimSpace(args[0])
+	resp, err := client.Sys().InstallPlugin(&api.InstallPluginInput{
+		Bundle: bundle,
+	})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error installing plugin bundle %s: %s", bundle, err))
+		return 2
+	}
+
+	c.UI.Output(fmt.Sprintf("Success! Installed %s plugin %s versions: %s", resp.Type, resp.Name, strings.Join(resp.Versions, ", ")))
+	return 0
+}
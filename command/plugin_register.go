@@ -18,10 +18,16 @@ var (
 type PluginRegisterCommand struct {
 	*BaseCommand
 
-	flagArgs    []string
-	flagCommand string
-	flagSHA256  string
-	flagVersion string
+	flagArgs           []string
+	flagCommand        string
+	flagSHA256         string
+	flagVersion        string
+	flagMemoryLimit    string
+	flagCPUQuota       int
+	flagUID            int
+	flagGID            int
+	flagRestrictEnv    bool
+	flagSeccompProfile string
 }
 
 func (c *PluginRegisterCommand) Synopsis() string {
@@ -48,6 +54,18 @@ Usage: vault plugin register [options] TYPE NAME
           -args=--with-glibc,--with-cgo \
           auth my-custom-plugin
 
+  Register a plugin limited to 512MiB of memory and half a CPU, running as its
+  own user:
+
+      $ vault plugin register \
+          -sha256=d3f0a8b... \
+          -memory-limit=512MiB \
+          -cpu-quota=50 \
+          -uid=1500 \
+          -gid=1500 \
+          -restrict-env \
+          database my-custom-plugin
+
 ` + c.Flags().Help()
 
 	return strings.TrimSpace(helpText)
@@ -88,6 +106,56 @@ func (c *PluginRegisterCommand) Flags() *FlagSets {
 		Usage:      "Semantic version of the plugin. Optional.",
 	})
 
+	f = set.NewFlagSet("Runtime Options")
+
+	f.StringVar(&StringVar{
+		Name:       "memory-limit",
+		Target:     &c.flagMemoryLimit,
+		Completion: complete.PredictAnything,
+		Usage: "Memory the processes of the plugin may use together, in bytes or " +
+			"with a unit such as \"512MiB\". Requires plugin_cgroup_parent in the " +
+			"server configuration.",
+	})
+
+	f.IntVar(&IntVar{
+		Name:       "cpu-quota",
+		Target:     &c.flagCPUQuota,
+		Completion: complete.PredictAnything,
+		Usage: "CPU time the processes of the plugin may use together, as a " +
+			"percentage of one CPU. Requires plugin_cgroup_parent in the server " +
+			"configuration.",
+	})
+
+	f.IntVar(&IntVar{
+		Name:       "uid",
+		Target:     &c.flagUID,
+		Completion: complete.PredictAnything,
+		Usage:      "User ID the plugin runs as. Requires -gid to be set.",
+	})
+
+	f.IntVar(&IntVar{
+		Name:       "gid",
+		Target:     &c.flagGID,
+		Completion: complete.PredictAnything,
+		Usage:      "Group ID the plugin runs as.",
+	})
+
+	f.BoolVar(&BoolVar{
+		Name:    "restrict-env",
+		Target:  &c.flagRestrictEnv,
+		Default: false,
+		Usage: "Prevent the plugin from inheriting the environment of the Vault " +
+			"server.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:       "seccomp-profile",
+		Target:     &c.flagSeccompProfile,
+		Completion: complete.PredictAnything,
+		Usage: "Path, relative to the plugin directory, of a compiled seccomp " +
+			"BPF program to load before running the plugin.",
+	})
+
 	return set
 }
 
@@ -154,6 +222,13 @@ func (c *PluginRegisterCommand) Run(args []string) int {
 		Command: command,
 		SHA256:  c.flagSHA256,
 		Version: c.flagVersion,
+
+		MemoryLimit:    c.flagMemoryLimit,
+		CPUQuota:       c.flagCPUQuota,
+		UID:            c.flagUID,
+		GID:            c.flagGID,
+		RestrictEnv:    c.flagRestrictEnv,
+		SeccompProfile: c.flagSeccompProfile,
 	}); err != nil {
 		c.UI.Error(fmt.Sprintf("Error registering plugin %s: %s", pluginName, err))
 		return 2
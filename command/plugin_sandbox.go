@@ -0,0 +1,63 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/vault/sdk/helper/pluginutil"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var (
+	_ cli.Command             = (*PluginSandboxCommand)(nil)
+	_ cli.CommandAutocomplete = (*PluginSandboxCommand)(nil)
+)
+
+// PluginSandboxCommand is the launcher Vault runs external plugins with a
+// runtime configuration through. It is not meant to be run by users.
+type PluginSandboxCommand struct {
+	*BaseCommand
+}
+
+func (c *PluginSandboxCommand) Synopsis() string {
+	return "Runs a plugin with its runtime configuration"
+}
+
+func (c *PluginSandboxCommand) Help() string {
+	helpText := `
+Usage: vault plugin sandbox
+
+  Runs an external plugin with the resource limits and sandboxing of its
+  runtime configuration. This command is run by the Vault server to start
+  plugins and is not meant to be run directly.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *PluginSandboxCommand) Flags() *FlagSets {
+	return nil
+}
+
+func (c *PluginSandboxCommand) AutocompleteArgs() complete.Predictor {
+	return nil
+}
+
+func (c *PluginSandboxCommand) AutocompleteFlags() complete.Flags {
+	return nil
+}
+
+func (c *PluginSandboxCommand) Run(args []string) int {
+	if len(args) > 0 {
+		c.UI.Error(fmt.Sprintf("Too many arguments (expected 0, got %d)", len(args)))
+		return 1
+	}
+
+	// Only returns on error, the plugin replaces this process otherwise
+	if err := pluginutil.ExecSandboxed(); err != nil {
+		c.UI.Error(fmt.Sprintf("Error running plugin: %s", err))
+		return 1
+	}
+	return 0
+}
@@ -42,8 +42,10 @@ import (
 	vaulthttp "github.com/hashicorp/vault/http"
 	"github.com/hashicorp/vault/internalshared/configutil"
 	"github.com/hashicorp/vault/internalshared/listenerutil"
+	"github.com/hashicorp/vault/physical/raft"
 	"github.com/hashicorp/vault/sdk/helper/consts"
 	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/helper/pluginutil"
 	"github.com/hashicorp/vault/sdk/helper/strutil"
 	"github.com/hashicorp/vault/sdk/helper/useragent"
 	"github.com/hashicorp/vault/sdk/logical"
@@ -790,6 +792,66 @@ func (c *ServerCommand) setupStorage(config *server.Config) (physical.Backend, e
 	return backend, nil
 }
 
+// setupStorageDestination initializes the destination of an online storage
+// migration. Raft storage is started as a single node cluster, which the other
+// nodes join once the server is restarted on it after the cutover.
+func (c *ServerCommand) setupStorageDestination(config *server.Config) (physical.Backend, error) {
+	destination := config.StorageDestination
+	if config.Storage.Type == storageTypeRaft {
+		return nil, errors.New("Online storage migration from raft storage is not supported")
+	}
+
+	factory, exists := c.PhysicalBackends[destination.Type]
+	if !exists {
+		return nil, fmt.Errorf("Unknown storage destination type %s", destination.Type)
+	}
+
+	namedStorageLogger := c.logger.Named("storage_destination." + destination.Type)
+	c.allLoggers = append(c.allLoggers, namedStorageLogger)
+	backend, err := factory(destination.Config, namedStorageLogger)
+	if err != nil {
+		return nil, fmt.Errorf("Error initializing storage destination of type %s: %w", destination.Type, err)
+	}
+
+	raftStorage, ok := backend.(*raft.RaftBackend)
+	if !ok {
+		return backend, nil
+	}
+
+	if len(destination.ClusterAddr) == 0 {
+		return nil, errors.New("Cluster address must be set in the storage destination when migrating to raft storage")
+	}
+	parsedClusterAddr, err := url.Parse(destination.ClusterAddr)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing storage destination cluster address: %w", err)
+	}
+
+	// The raft storage has state if the server was restarted during the
+	// migration
+	hasState, err := raftStorage.HasState()
+	if err != nil {
+		return nil, fmt.Errorf("Error checking storage destination state: %w", err)
+	}
+	if !hasState {
+		if err := raftStorage.Bootstrap([]raft.Peer{
+			{
+				ID:      raftStorage.NodeID(),
+				Address: parsedClusterAddr.Host,
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("Could not bootstrap storage destination: %w", err)
+		}
+	}
+
+	if err := raftStorage.SetupCluster(context.Background(), raft.SetupOpts{
+		StartAsLeader: true,
+	}); err != nil {
+		return nil, fmt.Errorf("Could not start storage destination: %w", err)
+	}
+
+	return backend, nil
+}
+
 func beginServiceRegistration(c *ServerCommand, config *server.Config) (sr.ServiceRegistration, error) {
 	sdFactory, ok := c.ServiceRegistrations[config.ServiceRegistration.Type]
 	if !ok {
@@ -1154,6 +1216,19 @@ func (c *ServerCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Initialize the destination of an online storage migration, if there is one
+	var storageDestination physical.Backend
+	if config.StorageDestination != nil {
+		storageDestination, err = c.setupStorageDestination(config)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		if raftStorage, ok := storageDestination.(*raft.RaftBackend); ok {
+			defer raftStorage.TeardownCluster(nil)
+		}
+	}
+
 	// Initialize the Service Discovery, if there is one
 	var configSR sr.ServiceRegistration
 	if config.ServiceRegistration != nil {
@@ -1204,6 +1279,7 @@ func (c *ServerCommand) Run(args []string) int {
 	}
 
 	coreConfig := createCoreConfig(c, config, backend, configSR, barrierSeal, unwrapSeal, metricsHelper, metricSink, secureRandomReader)
+	coreConfig.StorageMigrationDestination = storageDestination
 	if c.flagDevThreeNode {
 		return c.enableThreeNodeDevCluster(&coreConfig, info, infoKeys, c.flagDevListenAddr, os.Getenv("VAULT_DEV_TEMP_DIR"))
 	}
@@ -1305,6 +1381,11 @@ func (c *ServerCommand) Run(args []string) int {
 		infoKeys = append(infoKeys, "api address")
 	}
 
+	if config.StorageDestination != nil {
+		info["storage destination"] = config.StorageDestination.Type
+		infoKeys = append(infoKeys, "storage destination")
+	}
+
 	if config.HAStorage != nil {
 		info["HA storage"] = config.HAStorage.Type
 		infoKeys = append(infoKeys, "HA storage")
@@ -2574,6 +2655,24 @@ func runUnseal(c *ServerCommand, core *vault.Core, ctx context.Context) {
 	}
 }
 
+// pluginLauncher returns the launcher of external plugins with a runtime
+// configuration, which is the hidden "vault plugin sandbox" command.
+func (c *ServerCommand) pluginLauncher(config *server.Config) *pluginutil.PluginLauncher {
+	launcher := &pluginutil.PluginLauncher{
+		Args:         []string{"plugin", "sandbox"},
+		CgroupParent: config.PluginCgroupParent,
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		c.logger.Warn("could not determine the path of the vault binary, plugins with a runtime configuration will fail to start", "error", err)
+		return launcher
+	}
+	launcher.Command = exe
+
+	return launcher
+}
+
 func createCoreConfig(c *ServerCommand, config *server.Config, backend physical.Backend, configSR sr.ServiceRegistration, barrierSeal, unwrapSeal vault.Seal,
 	metricsHelper *metricsutil.MetricsHelper, metricSink *metricsutil.ClusterMetricSink, secureRandomReader io.Reader,
 ) vault.CoreConfig {
@@ -2600,6 +2699,9 @@ func createCoreConfig(c *ServerCommand, config *server.Config, backend physical.
 		PluginDirectory:                config.PluginDirectory,
 		PluginFileUid:                  config.PluginFileUid,
 		PluginFilePermissions:          config.PluginFilePermissions,
+		PluginBundleDirectory:          config.PluginBundleDirectory,
+		PluginBundleTrustedKeys:        config.PluginBundleTrustedKeys,
+		PluginLauncher:                 c.pluginLauncher(config),
 		EnableUI:                       config.EnableUI,
 		EnableRaw:                      config.EnableRawEndpoint,
 		DisableSealWrap:                config.DisableSealWrap,
@@ -43,6 +43,9 @@ type Config struct {
 	Storage   *Storage `hcl:"-"`
 	HAStorage *Storage `hcl:"-"`
 
+	// StorageDestination is the destination of an online storage migration
+	StorageDestination *Storage `hcl:"-"`
+
 	ServiceRegistration *ServiceRegistration `hcl:"-"`
 
 	CacheSize                int         `hcl:"cache_size"`
@@ -68,6 +71,12 @@ type Config struct {
 	PluginFilePermissions    int         `hcl:"-"`
 	PluginFilePermissionsRaw interface{} `hcl:"plugin_file_permissions,alias:PluginFilePermissions"`
 
+	PluginBundleDirectory string `hcl:"plugin_bundle_directory"`
+
+	PluginBundleTrustedKeys []string `hcl:"plugin_bundle_trusted_keys"`
+
+	PluginCgroupParent string `hcl:"plugin_cgroup_parent"`
+
 	EnableRawEndpoint    bool        `hcl:"-"`
 	EnableRawEndpointRaw interface{} `hcl:"raw_storage_endpoint,alias:EnableRawEndpoint"`
 
@@ -270,6 +279,11 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.HAStorage = c2.HAStorage
 	}
 
+	result.StorageDestination = c.StorageDestination
+	if c2.StorageDestination != nil {
+		result.StorageDestination = c2.StorageDestination
+	}
+
 	result.ServiceRegistration = c.ServiceRegistration
 	if c2.ServiceRegistration != nil {
 		result.ServiceRegistration = c2.ServiceRegistration
@@ -356,6 +370,21 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.PluginFilePermissionsRaw = c2.PluginFilePermissionsRaw
 	}
 
+	result.PluginBundleDirectory = c.PluginBundleDirectory
+	if c2.PluginBundleDirectory != "" {
+		result.PluginBundleDirectory = c2.PluginBundleDirectory
+	}
+
+	result.PluginBundleTrustedKeys = c.PluginBundleTrustedKeys
+	if len(c2.PluginBundleTrustedKeys) > 0 {
+		result.PluginBundleTrustedKeys = c2.PluginBundleTrustedKeys
+	}
+
+	result.PluginCgroupParent = c.PluginCgroupParent
+	if c2.PluginCgroupParent != "" {
+		result.PluginCgroupParent = c2.PluginCgroupParent
+	}
+
 	result.DisablePerformanceStandby = c.DisablePerformanceStandby
 	if c2.DisablePerformanceStandby {
 		result.DisablePerformanceStandby = c2.DisablePerformanceStandby
@@ -670,6 +699,13 @@ func ParseConfig(d, source string) (*Config, error) {
 		}
 	}
 
+	if o := list.Filter("storage_destination"); len(o.Items) > 0 {
+		delete(result.UnusedKeys, "storage_destination")
+		if err := parseStorageDestination(result, o, "storage_destination"); err != nil {
+			return nil, fmt.Errorf("error parsing 'storage_destination': %w", err)
+		}
+	}
+
 	// Parse service discovery
 	if o := list.Filter("service_registration"); len(o.Items) > 0 {
 		delete(result.UnusedKeys, "service_registration")
@@ -851,6 +887,19 @@ func ParseStorage(result *Config, list *ast.ObjectList, name string) error {
 	return nil
 }
 
+// parseStorageDestination reuses the storage parsing for the destination of
+// an online storage migration. The top-level addresses are not applied, since
+// the destination storage is not used for clustering until the cutover.
+func parseStorageDestination(result *Config, list *ast.ObjectList, name string) error {
+	tmpConfig := new(Config)
+	if err := ParseStorage(tmpConfig, list, name); err != nil {
+		return err
+	}
+
+	result.StorageDestination = tmpConfig.Storage
+	return nil
+}
+
 func parseHAStorage(result *Config, list *ast.ObjectList, name string) error {
 	if len(list.Items) > 1 {
 		return fmt.Errorf("only one %q block is permitted", name)
@@ -992,6 +1041,12 @@ func (c *Config) Sanitized() map[string]interface{} {
 
 		"plugin_file_permissions": c.PluginFilePermissions,
 
+		"plugin_bundle_directory": c.PluginBundleDirectory,
+
+		"plugin_bundle_trusted_keys": c.PluginBundleTrustedKeys,
+
+		"plugin_cgroup_parent": c.PluginCgroupParent,
+
 		"raw_storage_endpoint": c.EnableRawEndpoint,
 
 		"api_addr":           c.APIAddr,
@@ -1036,6 +1091,14 @@ func (c *Config) Sanitized() map[string]interface{} {
 		result["ha_storage"] = sanitizedHAStorage
 	}
 
+	// Sanitize storage destination stanza
+	if c.StorageDestination != nil {
+		result["storage_destination"] = map[string]interface{}{
+			"type":         c.StorageDestination.Type,
+			"cluster_addr": c.StorageDestination.ClusterAddr,
+		}
+	}
+
 	// Sanitize service_registration stanza
 	if c.ServiceRegistration != nil {
 		sanitizedServiceRegistration := map[string]interface{}{
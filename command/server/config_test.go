@@ -48,6 +48,10 @@ func TestConfigRaftRetryJoin(t *testing.T) {
 	testConfigRaftRetryJoin(t)
 }
 
+func TestParseStorageDestination(t *testing.T) {
+	testParseStorageDestination(t)
+}
+
 func TestParseSeals(t *testing.T) {
 	testParseSeals(t)
 }
@@ -737,6 +737,9 @@ func testConfig_Sanitized(t *testing.T) {
 		"disable_performance_standby":         false,
 		"plugin_file_uid":                     0,
 		"plugin_file_permissions":             0,
+		"plugin_bundle_directory":             "",
+		"plugin_bundle_trusted_keys":          []string(nil),
+		"plugin_cgroup_parent":                "",
 		"disable_printable_check":             false,
 		"disable_sealwrap":                    true,
 		"raw_storage_endpoint":                true,
@@ -906,6 +909,10 @@ func testParseUserLockouts(t *testing.T) {
 	  }
 	  user_lockout "ldap" {
 		disable_lockout = "true"
+	 }
+	  user_lockout "okta" {
+		progressive_delay = "true"
+		progressive_delay_max = "10m"
 	 }`))
 
 	config := Config{
@@ -923,25 +930,44 @@ func testParseUserLockouts(t *testing.T) {
 		SharedConfig: &configutil.SharedConfig{
 			UserLockouts: []*configutil.UserLockout{
 				{
-					Type:                "all",
-					LockoutThreshold:    5,
-					LockoutDuration:     2400000000000,
-					LockoutCounterReset: 2700000000000,
-					DisableLockout:      false,
+					Type:                 "all",
+					LockoutThreshold:     5,
+					LockoutDuration:      2400000000000,
+					LockoutCounterReset:  2700000000000,
+					DisableLockout:       false,
+					ProgressiveDelay:     false,
+					ProgressiveDelayBase: time.Second,
+					ProgressiveDelayMax:  5 * time.Minute,
 				},
 				{
-					Type:                "userpass",
-					LockoutThreshold:    100,
-					LockoutDuration:     1200000000000,
-					LockoutCounterReset: 2700000000000,
-					DisableLockout:      false,
+					Type:                 "userpass",
+					LockoutThreshold:     100,
+					LockoutDuration:      1200000000000,
+					LockoutCounterReset:  2700000000000,
+					DisableLockout:       false,
+					ProgressiveDelay:     false,
+					ProgressiveDelayBase: time.Second,
+					ProgressiveDelayMax:  5 * time.Minute,
 				},
 				{
-					Type:                "ldap",
-					LockoutThreshold:    5,
-					LockoutDuration:     2400000000000,
-					LockoutCounterReset: 2700000000000,
-					DisableLockout:      true,
+					Type:                 "ldap",
+					LockoutThreshold:     5,
+					LockoutDuration:      2400000000000,
+					LockoutCounterReset:  2700000000000,
+					DisableLockout:       true,
+					ProgressiveDelay:     false,
+					ProgressiveDelayBase: time.Second,
+					ProgressiveDelayMax:  5 * time.Minute,
+				},
+				{
+					Type:                 "okta",
+					LockoutThreshold:     5,
+					LockoutDuration:      2400000000000,
+					LockoutCounterReset:  2700000000000,
+					DisableLockout:       false,
+					ProgressiveDelay:     true,
+					ProgressiveDelayBase: time.Second,
+					ProgressiveDelayMax:  10 * time.Minute,
 				},
 			},
 		},
@@ -1036,6 +1062,48 @@ ha_storage "consul" {
 	}
 }
 
+func testParseStorageDestination(t *testing.T) {
+	config, err := ParseConfig(`
+cluster_addr = "https://127.0.0.1:8201"
+
+storage "consul" {
+	path = "vault/"
+}
+
+storage_destination "raft" {
+	path         = "/var/lib/vault/raft"
+	node_id      = "vault-1"
+	cluster_addr = "https://127.0.0.1:8301"
+}
+`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Storage{
+		Type:        "raft",
+		ClusterAddr: "https://127.0.0.1:8301",
+		Config: map[string]string{
+			"path":    "/var/lib/vault/raft",
+			"node_id": "vault-1",
+		},
+	}
+	if diff := deep.Equal(config.StorageDestination, expected); diff != nil {
+		t.Fatal(diff)
+	}
+	if config.Storage.ClusterAddr != "https://127.0.0.1:8201" {
+		t.Fatalf("bad storage cluster address: %q", config.Storage.ClusterAddr)
+	}
+
+	if _, err := ParseConfig(`
+storage "inmem" {}
+storage_destination "inmem" {}
+storage_destination "file" {}
+`, ""); err == nil {
+		t.Fatal("expected error with multiple storage_destination blocks")
+	}
+}
+
 func testParseSeals(t *testing.T) {
 	config, err := LoadConfigFile("./test-fixtures/config_seals.hcl")
 	if err != nil {
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	"github.com/hashicorp/go-secure-stdlib/reloadutil"
+	"github.com/hashicorp/vault/internalshared/configutil"
+	"github.com/hashicorp/vault/internalshared/listenerutil"
+	"github.com/mitchellh/cli"
+)
+
+func unixListenerFactory(l *configutil.Listener, _ io.Writer, ui cli.Ui) (net.Listener, map[string]string, reloadutil.ReloadFunc, error) {
+	addr := l.Address
+	if addr == "" {
+		return nil, nil, nil, errors.New("address is required for unix listeners")
+	}
+
+	var unixSocketsConfig *listenerutil.UnixSocketsConfig
+	if l.SocketMode != "" || l.SocketUser != "" || l.SocketGroup != "" {
+		unixSocketsConfig = &listenerutil.UnixSocketsConfig{
+			Mode:  l.SocketMode,
+			User:  l.SocketUser,
+			Group: l.SocketGroup,
+		}
+	}
+
+	ln, err := listenerutil.UnixSocketListener(addr, unixSocketsConfig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	props := map[string]string{"addr": addr}
+
+	if unixSocketsConfig != nil {
+		if l.SocketMode != "" {
+			props["socket_mode"] = l.SocketMode
+		}
+		if l.SocketUser != "" {
+			props["socket_user"] = l.SocketUser
+		}
+		if l.SocketGroup != "" {
+			props["socket_group"] = l.SocketGroup
+		}
+	}
+
+	// Every connection gets a client address, mapped from the credentials of
+	// the peer if socket_peer_addresses is set, and the default peer address
+	// otherwise
+	var mapping *listenerutil.PeerAddressMapping
+	if len(l.SocketPeerAddresses) > 0 {
+		mapping, err = listenerutil.ParsePeerAddressMapping(l.SocketPeerAddresses)
+		if err != nil {
+			ln.Close()
+			return nil, nil, nil, fmt.Errorf("error parsing socket_peer_addresses: %w", err)
+		}
+
+		// Peer credential props
+		props["socket_peer_addresses"] = strconv.Itoa(len(l.SocketPeerAddresses))
+		props["socket_peer_reject_not_mapped"] = strconv.FormatBool(l.SocketPeerRejectNotMapped)
+	}
+
+	peerLn, err := listenerutil.NewPeerAddressListener(ln, mapping, l.SocketPeerRejectNotMapped)
+	if err != nil {
+		ln.Close()
+		return nil, nil, nil, err
+	}
+	ln = peerLn
+
+	tlsConfig, reloadFunc, err := listenerutil.TLSConfig(l, props, ui)
+	if err != nil {
+		ln.Close()
+		return nil, nil, nil, err
+	}
+	if tlsConfig != nil {
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+
+	return ln, props, reloadFunc, nil
+}
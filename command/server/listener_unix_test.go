@@ -0,0 +1,216 @@
+package server
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/internalshared/configutil"
+	"github.com/mitchellh/cli"
+)
+
+func TestUnixListener(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "vault.sock")
+	ln, props, _, err := unixListenerFactory(&configutil.Listener{
+		Type:       "unix",
+		Address:    socket,
+		SocketMode: "0600",
+		TLSDisable: true,
+	}, nil, cli.NewMockUi())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer ln.Close()
+
+	if props["addr"] != socket || props["socket_mode"] != "0600" {
+		t.Fatalf("bad props: %v", props)
+	}
+
+	info, err := os.Stat(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	serverCh := make(chan net.Conn, 1)
+	go func() {
+		server, err := ln.Accept()
+		if err != nil {
+			t.Errorf("err: %s", err)
+		}
+		serverCh <- server
+	}()
+
+	client, err := net.Dial("unix", socket)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer client.Close()
+
+	server := <-serverCh
+	if server == nil {
+		t.Fatal("server did not accept the connection")
+	}
+	defer server.Close()
+
+	// Connections get the default peer address when there is no mapping
+	if addr := server.RemoteAddr().String(); addr != "127.0.0.1:0" {
+		t.Fatalf("expected the default peer address, got %q", addr)
+	}
+
+	if _, err := client.Write([]byte("foo")); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	buf := make([]byte, 3)
+	if _, err := io.ReadFull(server, buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(buf) != "foo" {
+		t.Fatalf("bad: %q", buf)
+	}
+}
+
+func TestUnixListener_noAddress(t *testing.T) {
+	_, _, _, err := unixListenerFactory(&configutil.Listener{
+		Type:       "unix",
+		TLSDisable: true,
+	}, nil, cli.NewMockUi())
+	if err == nil {
+		t.Fatal("expected error without address")
+	}
+}
+
+func TestUnixListener_peerAddresses(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("unix socket peer credentials are only supported on linux")
+	}
+
+	socket := filepath.Join(t.TempDir(), "vault.sock")
+	ln, props, _, err := unixListenerFactory(&configutil.Listener{
+		Type:    "unix",
+		Address: socket,
+		SocketPeerAddresses: map[string]string{
+			"uid:" + strconv.Itoa(os.Getuid()): "10.1.2.3",
+		},
+		TLSDisable: true,
+	}, nil, cli.NewMockUi())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer ln.Close()
+
+	if props["socket_peer_addresses"] != "1" {
+		t.Fatalf("bad props: %v", props)
+	}
+
+	connFn := func(lnReal net.Listener) (net.Conn, error) {
+		return net.Dial("unix", socket)
+	}
+
+	testListenerImpl(t, ln, connFn, "", 0, "10.1.2.3", false)
+}
+
+func TestUnixListener_peerNotMapped(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("unix socket peer credentials are only supported on linux")
+	}
+
+	socket := filepath.Join(t.TempDir(), "vault.sock")
+	ln, _, _, err := unixListenerFactory(&configutil.Listener{
+		Type:    "unix",
+		Address: socket,
+		SocketPeerAddresses: map[string]string{
+			"uid:" + strconv.Itoa(os.Getuid()+1): "10.1.2.3",
+			"default":                            "10.1.2.4",
+		},
+		TLSDisable: true,
+	}, nil, cli.NewMockUi())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer ln.Close()
+
+	connFn := func(lnReal net.Listener) (net.Conn, error) {
+		return net.Dial("unix", socket)
+	}
+
+	// Peers that are not mapped get the default address
+	testListenerImpl(t, ln, connFn, "", 0, "10.1.2.4", false)
+}
+
+func TestUnixListener_peerRejectNotMapped(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("unix socket peer credentials are only supported on linux")
+	}
+
+	socket := filepath.Join(t.TempDir(), "vault.sock")
+	ln, _, _, err := unixListenerFactory(&configutil.Listener{
+		Type:    "unix",
+		Address: socket,
+		SocketPeerAddresses: map[string]string{
+			"uid:" + strconv.Itoa(os.Getuid()+1): "10.1.2.3",
+		},
+		SocketPeerRejectNotMapped: true,
+		TLSDisable:                true,
+	}, nil, cli.NewMockUi())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	acceptCh := make(chan net.Conn, 1)
+	go func() {
+		// Accept only returns once the listener is closed since the only
+		// connection is rejected
+		server, _ := ln.Accept()
+		acceptCh <- server
+	}()
+
+	client, err := net.Dial("unix", socket)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer client.Close()
+
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := client.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("expected connection to be closed, got: %v", err)
+	}
+
+	ln.Close()
+	if server := <-acceptCh; server != nil {
+		t.Fatal("expected rejected connection not to be accepted")
+	}
+}
+
+func TestUnixListenerConfig(t *testing.T) {
+	config, err := LoadConfigFile("./test-fixtures/unix_listener.hcl")
+	if err != nil {
+		t.Fatalf("Error encountered when loading config %+v", err)
+	}
+
+	l := config.Listeners[0]
+	if l.Type != "unix" || l.Address != "/run/vault/vault.sock" {
+		t.Fatalf("bad listener: %#v", l)
+	}
+	if l.SocketMode != "0660" || l.SocketUser != "vault" || l.SocketGroup != "vault-clients" {
+		t.Fatalf("bad socket settings: %#v", l)
+	}
+	if !l.SocketPeerRejectNotMapped {
+		t.Fatal("expected socket_peer_reject_not_mapped to be set")
+	}
+	expected := map[string]string{
+		"uid:vault-agent": "10.255.0.1",
+		"gid:1001":        "10.255.0.2",
+	}
+	if !reflect.DeepEqual(l.SocketPeerAddresses, expected) {
+		t.Fatalf("expected %v, got %v", expected, l.SocketPeerAddresses)
+	}
+}
@@ -0,0 +1,14 @@
+storage "inmem" {}
+listener "unix" {
+  address                       = "/run/vault/vault.sock"
+  socket_mode                   = "0660"
+  socket_user                   = "vault"
+  socket_group                  = "vault-clients"
+  socket_peer_reject_not_mapped = true
+  socket_peer_addresses = {
+    "uid:vault-agent" = "10.255.0.1"
+    "gid:1001"        = "10.255.0.2"
+  }
+  tls_disable = true
+}
+disable_mlock = true
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/hashicorp/vault/api"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
@@ -32,6 +33,8 @@ type TokenCreateCommand struct {
 	flagMetadata        map[string]string
 	flagPolicies        []string
 	flagEntityAlias     string
+
+	flagPathRestrictions map[string]string
 }
 
 func (c *TokenCreateCommand) Synopsis() string {
@@ -189,6 +192,16 @@ func (c *TokenCreateCommand) Flags() *FlagSets {
 			"the entity will not be inherited from the parent.",
 	})
 
+	f.StringMapVar(&StringMapVar{
+		Name:       "path-restriction",
+		Target:     &c.flagPathRestrictions,
+		Completion: complete.PredictAnything,
+		Usage: "Request path and comma separated capabilities to restrict the " +
+			"token to, in the form path=capability1,capability2, on top of its " +
+			"policies. This can be specified multiple times to allow multiple " +
+			"paths. Children of the token inherit its path restrictions.",
+	})
+
 	return set
 }
 
@@ -240,6 +253,13 @@ func (c *TokenCreateCommand) Run(args []string) int {
 		EntityAlias:     c.flagEntityAlias,
 	}
 
+	if len(c.flagPathRestrictions) > 0 {
+		tcr.PathRestrictions = make(map[string][]string, len(c.flagPathRestrictions))
+		for path, capabilities := range c.flagPathRestrictions {
+			tcr.PathRestrictions[path] = strutil.ParseDedupAndSortStrings(capabilities, ",")
+		}
+	}
+
 	var secret *api.Secret
 	if c.flagRole != "" {
 		secret, err = client.Auth().Token().CreateWithRole(tcr, c.flagRole)
@@ -217,6 +217,31 @@ func randomRunes(rng io.Reader, charset []rune, length int) (candidate []rune, e
 	return runes, nil
 }
 
+// Validate that the provided string adheres to the rules of the generator. This allows a password policy to be
+// used to check strings it didn't generate, such as user-chosen passwords, in which case the length is treated as a
+// minimum rather than an exact length.
+func (g *StringGenerator) Validate(str string) (err error) {
+	merr := &multierror.Error{}
+
+	value := []rune(str)
+	if len(value) < g.Length {
+		merr = multierror.Append(merr, fmt.Errorf("must be at least %d characters long", g.Length))
+	}
+
+	for _, rule := range g.Rules {
+		if rule.Pass(value) {
+			continue
+		}
+		switch r := rule.(type) {
+		case CharsetRule:
+			merr = multierror.Append(merr, fmt.Errorf("must contain at least %d of the characters %q", r.MinChars, string(r.Charset)))
+		default:
+			merr = multierror.Append(merr, fmt.Errorf("does not satisfy the %s rule", rule.Type()))
+		}
+	}
+	return merr.ErrorOrNil()
+}
+
 // validateConfig of the generator to ensure that we can successfully generate a string.
 func (g *StringGenerator) validateConfig() (err error) {
 	merr := &multierror.Error{}
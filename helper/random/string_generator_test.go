@@ -632,6 +632,66 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestStringGenerator_Validate(t *testing.T) {
+	generator := &StringGenerator{
+		Length: 8,
+		Rules: []Rule{
+			CharsetRule{
+				Charset:  LowercaseRuneset,
+				MinChars: 1,
+			},
+			CharsetRule{
+				Charset:  NumericRuneset,
+				MinChars: 2,
+			},
+		},
+	}
+
+	type testCase struct {
+		value     string
+		expectErr bool
+	}
+
+	tests := map[string]testCase{
+		"satisfies rules": {
+			value:     "abcdef12",
+			expectErr: false,
+		},
+		"longer than length": {
+			value:     "abcdefghijkl12",
+			expectErr: false,
+		},
+		"characters outside of the charsets": {
+			value:     "ABC-def-12",
+			expectErr: false,
+		},
+		"too short": {
+			value:     "abc12",
+			expectErr: true,
+		},
+		"missing charset": {
+			value:     "abcdefgh1",
+			expectErr: true,
+		},
+		"empty": {
+			value:     "",
+			expectErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := generator.Validate(test.value)
+			if test.expectErr && err == nil {
+				t.Fatalf("err expected, got nil")
+			}
+			if !test.expectErr && err != nil {
+				t.Fatalf("no error expected, got: %s", err)
+			}
+		})
+	}
+}
+
 type testNonCharsetRule struct {
 	String string `mapstructure:"string" json:"string"`
 }
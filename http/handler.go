@@ -17,6 +17,7 @@ import (
 	"net/url"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/hashicorp/go-sockaddr"
 	"github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/vault/helper/namespace"
@@ -44,6 +46,26 @@ const (
 	// wrap in; has no effect if the wrap TTL is not set
 	WrapFormatHeaderName = "X-Vault-Wrap-Format"
 
+	// WrapUsesHeaderName is the name of the header containing the number of
+	// times the wrapped response can be unwrapped; has no effect if the wrap
+	// TTL is not set
+	WrapUsesHeaderName = "X-Vault-Wrap-Uses"
+
+	// WrapBoundEntityIDHeaderName is the name of the header containing the ID
+	// of the only entity allowed to unwrap the wrapped response; has no effect
+	// if the wrap TTL is not set
+	WrapBoundEntityIDHeaderName = "X-Vault-Wrap-Bound-Entity-ID"
+
+	// WrapBoundAppRoleRoleHeaderName is the name of the header containing the
+	// name of the only AppRole role allowed to unwrap the wrapped response; has
+	// no effect if the wrap TTL is not set
+	WrapBoundAppRoleRoleHeaderName = "X-Vault-Wrap-Bound-AppRole-Role"
+
+	// WrapBoundCIDRsHeaderName is the name of the header containing the comma
+	// separated CIDRs the wrapped response can be unwrapped from; has no effect
+	// if the wrap TTL is not set
+	WrapBoundCIDRsHeaderName = "X-Vault-Wrap-Bound-CIDRs"
+
 	// NoRequestForwardingHeaderName is the name of the header telling Vault
 	// not to use request forwarding
 	NoRequestForwardingHeaderName = "X-Vault-No-Request-Forwarding"
@@ -223,7 +245,7 @@ func handler(props *vault.HandlerProperties) http.Handler {
 	// Wrap the handler in another handler to trigger all help paths.
 	helpWrappedHandler := wrapHelpHandler(mux, core)
 	corsWrappedHandler := wrapCORSHandler(helpWrappedHandler, core)
-	quotaWrappedHandler := rateLimitQuotaWrapping(corsWrappedHandler, core)
+	quotaWrappedHandler := quotaWrapping(corsWrappedHandler, core)
 	genericWrappedHandler := genericWrapping(core, quotaWrappedHandler, props)
 
 	// Wrap the handler with PrintablePathCheckHandler to check for non-printable
@@ -1107,6 +1129,28 @@ func requestWrapInfo(r *http.Request, req *logical.Request) (*logical.Request, e
 		req.WrapInfo.Format = "jwt"
 	}
 
+	if wrapUses := r.Header.Get(WrapUsesHeaderName); wrapUses != "" {
+		uses, err := strconv.Atoi(wrapUses)
+		if err != nil {
+			return req, fmt.Errorf("invalid wrap uses: %w", err)
+		}
+		if uses < 1 {
+			return req, fmt.Errorf("requested wrap uses must be at least 1")
+		}
+		req.WrapInfo.Uses = uses
+	}
+
+	req.WrapInfo.BoundEntityID = strings.TrimSpace(r.Header.Get(WrapBoundEntityIDHeaderName))
+	req.WrapInfo.BoundAppRoleRole = strings.TrimSpace(r.Header.Get(WrapBoundAppRoleRoleHeaderName))
+
+	if wrapBoundCIDRs := r.Header.Get(WrapBoundCIDRsHeaderName); wrapBoundCIDRs != "" {
+		cidrs := strutil.ParseDedupAndSortStrings(wrapBoundCIDRs, ",")
+		if _, err := parseutil.ParseAddrs(cidrs); err != nil {
+			return req, fmt.Errorf("invalid wrap bound CIDRs: %w", err)
+		}
+		req.WrapInfo.BoundCIDRs = cidrs
+	}
+
 	return req, nil
 }
 
@@ -39,7 +39,14 @@ func (b *bufferedReader) Close() error {
 	return b.rOrig.Close()
 }
 
-const MergePatchContentTypeHeader = "application/merge-patch+json"
+const (
+	MergePatchContentTypeHeader = "application/merge-patch+json"
+	SCIMContentTypeHeader       = "application/scim+json"
+
+	// scimPathPrefix is the prefix of the SCIM endpoints of the identity
+	// store, the only ones accepting SCIM PATCH requests
+	scimPathPrefix = "identity/scim/v2/"
+)
 
 func buildLogicalRequestNoAuth(perfStandby bool, w http.ResponseWriter, r *http.Request) (*logical.Request, io.ReadCloser, int, error) {
 	ns, err := namespace.FromContext(r.Context())
@@ -155,7 +162,10 @@ func buildLogicalRequestNoAuth(perfStandby bool, w http.ResponseWriter, r *http.
 			return nil, nil, status, err
 		}
 
-		if contentType != MergePatchContentTypeHeader {
+		switch {
+		case contentType == MergePatchContentTypeHeader:
+		case contentType == SCIMContentTypeHeader && strings.HasPrefix(path, scimPathPrefix):
+		default:
 			return nil, nil, http.StatusUnsupportedMediaType, fmt.Errorf("PATCH requires Content-Type of %s, provided %s", MergePatchContentTypeHeader, contentType)
 		}
 
@@ -268,7 +278,7 @@ func buildLogicalRequest(core *vault.Core, w http.ResponseWriter, r *http.Reques
 
 	req, err = requestWrapInfo(r, req)
 	if err != nil {
-		return nil, nil, http.StatusBadRequest, fmt.Errorf("error parsing X-Vault-Wrap-TTL header: %w", err)
+		return nil, nil, http.StatusBadRequest, fmt.Errorf("error parsing X-Vault-Wrap headers: %w", err)
 	}
 
 	err = parseMFAHeader(req)
@@ -186,7 +186,9 @@ func TestLogical_StandbyRedirect(t *testing.T) {
 	actualDataMap := actual["data"].(map[string]interface{})
 	delete(actualDataMap, "creation_time")
 	delete(actualDataMap, "accessor")
+	delete(actualDataMap, "last_used_time")
 	actual["data"] = actualDataMap
+	expected["data"].(map[string]interface{})["use_count"] = actualDataMap["use_count"]
 	expected["request_id"] = actual["request_id"]
 	delete(actual, "lease_id")
 	if diff := deep.Equal(actual, expected); diff != nil {
@@ -759,3 +761,67 @@ func TestLogical_ErrRelativePath(t *testing.T) {
 		t.Errorf("expected response for write to include %q", logical.ErrRelativePath.Error())
 	}
 }
+
+func TestLogical_SCIMPatch(t *testing.T) {
+	core, _, token := vault.TestCoreUnsealed(t)
+	ln, addr := TestServer(t, core)
+	defer ln.Close()
+	TestServerAuth(t, addr, token)
+
+	resp := testHttpPost(t, token, addr+"/v1/identity/scim/v2/Users", map[string]interface{}{
+		"userName": "alice",
+	})
+	testResponseStatus(t, resp, http.StatusCreated)
+	testResponseHeader(t, resp, map[string]string{"Content-Type": SCIMContentTypeHeader})
+	var user map[string]interface{}
+	testResponseBody(t, resp, &user)
+
+	patch := func(contentType string) *http.Response {
+		body, err := json.Marshal(map[string]interface{}{
+			"Operations": []interface{}{
+				map[string]interface{}{"op": "replace", "path": "active", "value": false},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := http.NewRequest("PATCH", addr+"/v1/identity/scim/v2/Users/"+user["id"].(string), bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set(consts.AuthHeaderName, token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	// Plain JSON is neither a merge patch nor a SCIM PatchOp
+	resp = patch("application/json")
+	testResponseStatus(t, resp, http.StatusUnsupportedMediaType)
+
+	resp = patch(SCIMContentTypeHeader)
+	testResponseStatus(t, resp, http.StatusOK)
+	testResponseBody(t, resp, &user)
+	if user["active"] != false {
+		t.Fatalf("expected user to be deactivated: %#v", user)
+	}
+
+	resp = testHttpDelete(t, token, addr+"/v1/identity/scim/v2/Users/"+user["id"].(string))
+	testResponseStatus(t, resp, http.StatusNoContent)
+
+	// Other paths only accept merge patches
+	req, err := http.NewRequest("PATCH", addr+"/v1/secret/foo", bytes.NewReader([]byte(`{"bar": "baz"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", SCIMContentTypeHeader)
+	req.Header.Set(consts.AuthHeaderName, token)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testResponseStatus(t, resp, http.StatusUnsupportedMediaType)
+}
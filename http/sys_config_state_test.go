@@ -45,6 +45,9 @@ func TestSysConfigState_Sanitized(t *testing.T) {
 		"log_level":                           "",
 		"max_lease_ttl":                       json.Number("0"),
 		"pid_file":                            "",
+		"plugin_bundle_directory":             "",
+		"plugin_bundle_trusted_keys":          nil,
+		"plugin_cgroup_parent":                "",
 		"plugin_directory":                    "",
 		"plugin_file_uid":                     json.Number("0"),
 		"plugin_file_permissions":             json.Number("0"),
@@ -360,6 +360,8 @@ func TestSysGenerateRoot_Update_OTP(t *testing.T) {
 
 	expected["creation_time"] = actual["data"].(map[string]interface{})["creation_time"]
 	expected["accessor"] = actual["data"].(map[string]interface{})["accessor"]
+	expected["use_count"] = actual["data"].(map[string]interface{})["use_count"]
+	delete(actual["data"].(map[string]interface{}), "last_used_time")
 
 	if !reflect.DeepEqual(actual["data"], expected) {
 		t.Fatalf("\nexpected: %#v\nactual: %#v", expected, actual["data"])
@@ -463,6 +465,8 @@ func TestSysGenerateRoot_Update_PGP(t *testing.T) {
 
 	expected["creation_time"] = actual["data"].(map[string]interface{})["creation_time"]
 	expected["accessor"] = actual["data"].(map[string]interface{})["accessor"]
+	expected["use_count"] = actual["data"].(map[string]interface{})["use_count"]
+	delete(actual["data"].(map[string]interface{}), "last_used_time")
 
 	if diff := deep.Equal(actual["data"], expected); diff != nil {
 		t.Fatal(diff)
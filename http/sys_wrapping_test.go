@@ -367,3 +367,176 @@ func TestHTTP_Wrapping(t *testing.T) {
 		t.Fatalf("secret data did not match expected: %#v", secret.Data)
 	}
 }
+
+// Test wrapping tokens with multiple uses or bound to a recipient
+func TestHTTP_Wrapping_UsesAndBindings(t *testing.T) {
+	cluster := vault.NewTestCluster(t, &vault.CoreConfig{}, &vault.TestClusterOptions{
+		HandlerFunc: Handler,
+	})
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	core := cluster.Cores[0].Core
+	vault.TestWaitActive(t, core)
+
+	client := cluster.Cores[0].Client
+	client.SetToken(cluster.RootToken)
+
+	wrap := func(headers map[string]string) string {
+		t.Helper()
+		wrapClient, err := client.Clone()
+		if err != nil {
+			t.Fatal(err)
+		}
+		wrapClient.SetToken(cluster.RootToken)
+		wrapClient.AddHeader(WrapTTLHeaderName, "5m")
+		for k, v := range headers {
+			wrapClient.AddHeader(k, v)
+		}
+		secret, err := wrapClient.Logical().Write("sys/wrapping/wrap", map[string]interface{}{
+			"zip": "zap",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if secret == nil || secret.WrapInfo == nil {
+			t.Fatal("secret or wrap info is nil")
+		}
+		return secret.WrapInfo.Token
+	}
+	lookup := func(token string) map[string]interface{} {
+		t.Helper()
+		secret, err := client.Logical().Write("sys/wrapping/lookup", map[string]interface{}{
+			"token": token,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return secret.Data
+	}
+	firstPartyUnwrap := func(token string) (*api.Secret, error) {
+		unwrapClient, err := client.Clone()
+		if err != nil {
+			t.Fatal(err)
+		}
+		unwrapClient.SetToken(token)
+		return unwrapClient.Logical().Unwrap("")
+	}
+	expectData := func(secret *api.Secret) {
+		t.Helper()
+		if !reflect.DeepEqual(secret.Data, map[string]interface{}{"zip": "zap"}) {
+			t.Fatalf("secret data did not match expected: %#v", secret.Data)
+		}
+	}
+
+	// Multi-use tokens can be unwrapped as many times as requested
+	token := wrap(map[string]string{WrapUsesHeaderName: "3"})
+	secret, err := client.Logical().Unwrap(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectData(secret)
+	data := lookup(token)
+	if data["max_uses"] != json.Number("3") || data["remaining_uses"] != json.Number("2") {
+		t.Fatalf("bad: %#v", data)
+	}
+	secret, err = firstPartyUnwrap(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectData(secret)
+	secret, err = client.Logical().Unwrap(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectData(secret)
+	if _, err := client.Logical().Unwrap(token); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// Invalid uses are rejected
+	wrapClient, err := client.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapClient.SetToken(cluster.RootToken)
+	wrapClient.AddHeader(WrapTTLHeaderName, "5m")
+	wrapClient.AddHeader(WrapUsesHeaderName, "0")
+	if _, err := wrapClient.Logical().Write("sys/wrapping/wrap", map[string]interface{}{"zip": "zap"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// Tokens bound to CIDRs can only be unwrapped from them
+	token = wrap(map[string]string{WrapBoundCIDRsHeaderName: "10.0.0.0/8"})
+	if _, err := client.Logical().Unwrap(token); err == nil {
+		t.Fatal("expected error")
+	}
+	token = wrap(map[string]string{WrapBoundCIDRsHeaderName: "10.0.0.0/8,127.0.0.1/32"})
+	secret, err = firstPartyUnwrap(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectData(secret)
+
+	// Tokens bound to an entity can only be unwrapped with a token of the
+	// entity, and misuse attempts don't use them
+	if _, err := client.Logical().Write("auth/token/roles/bound", map[string]interface{}{
+		"allowed_entity_aliases": "recipient",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	secret, err = client.Logical().Write("auth/token/create/bound", map[string]interface{}{
+		"entity_alias": "recipient",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipientToken := secret.Auth.ClientToken
+	entityID := secret.Auth.EntityID
+
+	token = wrap(map[string]string{WrapBoundEntityIDHeaderName: entityID})
+	if _, err := client.Logical().Unwrap(token); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := firstPartyUnwrap(token); err == nil {
+		t.Fatal("expected error")
+	}
+	cubbyClient, err := client.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cubbyClient.SetToken(token)
+	if _, err := cubbyClient.Logical().Read("cubbyhole/response"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	data = lookup(token)
+	if data["bound_entity_id"] != entityID {
+		t.Fatalf("bad: %#v", data)
+	}
+	attempts, ok := data["misuse_attempts"].([]interface{})
+	if !ok || len(attempts) != 2 {
+		t.Fatalf("bad: %#v", data)
+	}
+	if attempt := attempts[0].(map[string]interface{}); attempt["reason"] == "" || attempt["remote_address"] == "" {
+		t.Fatalf("bad: %#v", attempt)
+	}
+
+	// Bound tokens can't be rewrapped
+	if _, err := client.Logical().Write("sys/wrapping/rewrap", map[string]interface{}{
+		"token": token,
+	}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	recipientClient, err := client.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipientClient.SetToken(recipientToken)
+	secret, err = recipientClient.Logical().Unwrap(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectData(secret)
+}
@@ -34,7 +34,7 @@ var (
 	adjustResponse = func(core *vault.Core, w http.ResponseWriter, req *logical.Request) {}
 )
 
-func rateLimitQuotaWrapping(handler http.Handler, core *vault.Core) http.Handler {
+func quotaWrapping(handler http.Handler, core *vault.Core) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ns, err := namespace.FromContext(r.Context())
 		if err != nil {
@@ -60,14 +60,19 @@ func rateLimitQuotaWrapping(handler http.Handler, core *vault.Core) http.Handler
 		}
 		r.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
 
-		quotaResp, err := core.ApplyRateLimitQuota(r.Context(), &quotas.Request{
-			Type:          quotas.TypeRateLimit,
+		quotaReq := &quotas.Request{
 			Path:          path,
 			MountPath:     mountPath,
 			Role:          core.DetermineRoleFromLoginRequestFromBytes(mountPath, bodyBytes, r.Context()),
 			NamespacePath: ns.Path,
 			ClientAddress: parseRemoteIPAddress(r),
-		})
+			RequestSize:   int64(len(bodyBytes)),
+		}
+		if token, _ := getTokenFromReq(r); token != "" {
+			core.PopulateQuotaClientIdentity(r.Context(), quotaReq, token)
+		}
+
+		quotaResp, err := core.ApplyRateLimitQuota(r.Context(), quotaReq)
 		if err != nil {
 			core.Logger().Error("failed to apply quota", "path", path, "error", err)
 			respondError(w, http.StatusUnprocessableEntity, err)
@@ -82,36 +87,71 @@ func rateLimitQuotaWrapping(handler http.Handler, core *vault.Core) http.Handler
 
 		if !quotaResp.Allowed {
 			quotaErr := fmt.Errorf("request path %q: %w", path, quotas.ErrRateLimitQuotaExceeded)
-			respondError(w, http.StatusTooManyRequests, quotaErr)
+			respondQuotaRejection(core, w, r, path, http.StatusTooManyRequests, quotaErr)
+			return
+		}
 
-			if core.Logger().IsTrace() {
-				core.Logger().Trace("request rejected due to rate limit quota violation", "request_path", path)
-			}
+		quotaResp, err = core.ApplyRequestSizeQuota(r.Context(), quotaReq)
+		if err != nil {
+			core.Logger().Error("failed to apply quota", "path", path, "error", err)
+			respondError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
 
-			if core.RateLimitAuditLoggingEnabled() {
-				req, _, status, err := buildLogicalRequestNoAuth(core.PerfStandby(), w, r)
-				if err != nil || status != 0 {
-					respondError(w, status, err)
-					return
-				}
-
-				err = core.AuditLogger().AuditRequest(r.Context(), &logical.LogInput{
-					Request:  req,
-					OuterErr: quotaErr,
-				})
-				if err != nil {
-					core.Logger().Warn("failed to audit log request rejection caused by rate limit quota violation", "error", err)
-				}
+		if !quotaResp.Allowed {
+			for h, v := range quotaResp.Headers {
+				w.Header().Set(h, v)
 			}
+			quotaErr := fmt.Errorf("request path %q: %w", path, quotas.ErrRequestSizeQuotaExceeded)
+			respondQuotaRejection(core, w, r, path, http.StatusRequestEntityTooLarge, quotaErr)
+			return
+		}
+
+		quotaResp, err = core.ApplyConcurrencyQuota(r.Context(), quotaReq)
+		if err != nil {
+			core.Logger().Error("failed to apply quota", "path", path, "error", err)
+			respondError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
 
+		if !quotaResp.Allowed {
+			quotaErr := fmt.Errorf("request path %q: %w", path, quotas.ErrConcurrencyQuotaExceeded)
+			respondQuotaRejection(core, w, r, path, http.StatusTooManyRequests, quotaErr)
 			return
 		}
+		defer core.ReleaseConcurrencyQuota(quotaResp.Access)
 
 		handler.ServeHTTP(w, r)
 		return
 	})
 }
 
+// respondQuotaRejection responds to a request that was rejected by a quota
+// rule and, if enabled, audit logs the rejection.
+func respondQuotaRejection(core *vault.Core, w http.ResponseWriter, r *http.Request, path string, status int, quotaErr error) {
+	respondError(w, status, quotaErr)
+
+	if core.Logger().IsTrace() {
+		core.Logger().Trace("request rejected due to quota violation", "request_path", path, "error", quotaErr)
+	}
+
+	if core.RateLimitAuditLoggingEnabled() {
+		req, _, status, err := buildLogicalRequestNoAuth(core.PerfStandby(), w, r)
+		if err != nil || status != 0 {
+			respondError(w, status, err)
+			return
+		}
+
+		err = core.AuditLogger().AuditRequest(r.Context(), &logical.LogInput{
+			Request:  req,
+			OuterErr: quotaErr,
+		})
+		if err != nil {
+			core.Logger().Warn("failed to audit log request rejection caused by quota violation", "error", err)
+		}
+	}
+}
+
 func parseRemoteIPAddress(r *http.Request) string {
 	ip, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
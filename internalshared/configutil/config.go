@@ -192,11 +192,14 @@ func (c *SharedConfig) Sanitized() map[string]interface{} {
 		var sanitizedUserLockouts []interface{}
 		for _, userlockout := range c.UserLockouts {
 			cleanUserLockout := map[string]interface{}{
-				"type":                  userlockout.Type,
-				"lockout_threshold":     userlockout.LockoutThreshold,
-				"lockout_duration":      userlockout.LockoutDuration,
-				"lockout_counter_reset": userlockout.LockoutCounterReset,
-				"disable_lockout":       userlockout.DisableLockout,
+				"type":                   userlockout.Type,
+				"lockout_threshold":      userlockout.LockoutThreshold,
+				"lockout_duration":       userlockout.LockoutDuration,
+				"lockout_counter_reset":  userlockout.LockoutCounterReset,
+				"disable_lockout":        userlockout.DisableLockout,
+				"progressive_delay":      userlockout.ProgressiveDelay,
+				"progressive_delay_base": userlockout.ProgressiveDelayBase,
+				"progressive_delay_max":  userlockout.ProgressiveDelayMax,
 			}
 			sanitizedUserLockouts = append(sanitizedUserLockouts, cleanUserLockout)
 		}
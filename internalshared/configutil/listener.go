@@ -95,6 +95,10 @@ type Listener struct {
 	SocketUser  string `hcl:"socket_user"`
 	SocketGroup string `hcl:"socket_group"`
 
+	SocketPeerAddresses          map[string]string `hcl:"socket_peer_addresses"`
+	SocketPeerRejectNotMapped    bool              `hcl:"-"`
+	SocketPeerRejectNotMappedRaw interface{}       `hcl:"socket_peer_reject_not_mapped"`
+
 	AgentAPI *AgentAPI `hcl:"agent_api"`
 
 	Telemetry              ListenerTelemetry              `hcl:"telemetry"`
@@ -347,6 +351,25 @@ func ParseListeners(result *SharedConfig, list *ast.ObjectList) error {
 			}
 		}
 
+		// Unix socket peer credentials config
+		{
+			if l.SocketPeerRejectNotMappedRaw != nil {
+				if l.SocketPeerRejectNotMapped, err = parseutil.ParseBool(l.SocketPeerRejectNotMappedRaw); err != nil {
+					return multierror.Prefix(fmt.Errorf("invalid value for socket_peer_reject_not_mapped: %w", err), fmt.Sprintf("listeners.%d", i))
+				}
+
+				l.SocketPeerRejectNotMappedRaw = nil
+			}
+
+			if (len(l.SocketPeerAddresses) > 0 || l.SocketPeerRejectNotMapped) && l.Type != "unix" {
+				return multierror.Prefix(fmt.Errorf("socket_peer_addresses and socket_peer_reject_not_mapped are only supported by unix listeners"), fmt.Sprintf("listeners.%d", i))
+			}
+
+			if l.SocketPeerRejectNotMapped && len(l.SocketPeerAddresses) == 0 {
+				return multierror.Prefix(fmt.Errorf("socket_peer_reject_not_mapped requires socket_peer_addresses to be set"), fmt.Sprintf("listeners.%d", i))
+			}
+		}
+
 		// Telemetry
 		{
 			if l.Telemetry.UnauthenticatedMetricsAccessRaw != nil {
@@ -18,6 +18,9 @@ const (
 	UserLockoutDurationDefault     = 15 * time.Minute
 	UserLockoutCounterResetDefault = 15 * time.Minute
 	DisableUserLockoutDefault      = false
+	ProgressiveDelayDefault        = false
+	ProgressiveDelayBaseDefault    = 1 * time.Second
+	ProgressiveDelayMaxDefault     = 5 * time.Minute
 )
 
 type UserLockout struct {
@@ -30,6 +33,16 @@ type UserLockout struct {
 	LockoutCounterResetRaw interface{}   `hcl:"lockout_counter_reset"`
 	DisableLockout         bool          `hcl:"-"`
 	DisableLockoutRaw      interface{}   `hcl:"disable_lockout"`
+
+	// ProgressiveDelay replaces the hard lockout with exponentially
+	// increasing delays between login attempts once the lockout threshold
+	// is reached.
+	ProgressiveDelay        bool          `hcl:"-"`
+	ProgressiveDelayRaw     interface{}   `hcl:"progressive_delay"`
+	ProgressiveDelayBase    time.Duration `hcl:"-"`
+	ProgressiveDelayBaseRaw interface{}   `hcl:"progressive_delay_base"`
+	ProgressiveDelayMax     time.Duration `hcl:"-"`
+	ProgressiveDelayMaxRaw  interface{}   `hcl:"progressive_delay_max"`
 }
 
 func ParseUserLockouts(result *SharedConfig, list *ast.ObjectList) error {
@@ -53,10 +66,10 @@ func ParseUserLockouts(result *SharedConfig, list *ast.ObjectList) error {
 			}
 
 			userLockoutConfig.Type = strings.ToLower(userLockoutConfig.Type)
-			// Supported auth methods for user lockout configuration: ldap, approle, userpass
+			// Supported auth methods for user lockout configuration: ldap, approle, userpass, okta
 			// "all" is used to apply the configuration to all supported auth methods
 			switch userLockoutConfig.Type {
-			case "all", "ldap", "approle", "userpass":
+			case "all", "ldap", "approle", "userpass", "okta":
 				result.found(userLockoutConfig.Type, userLockoutConfig.Type)
 			default:
 				return multierror.Prefix(fmt.Errorf("unsupported auth type %q", userLockoutConfig.Type), fmt.Sprintf("user_lockouts.%d:", i))
@@ -99,6 +112,35 @@ func ParseUserLockouts(result *SharedConfig, list *ast.ObjectList) error {
 					return multierror.Prefix(fmt.Errorf("invalid value for disable_lockout: %w", err), fmt.Sprintf("user_lockouts.%d", i))
 				}
 			}
+
+			if userLockoutConfig.ProgressiveDelayRaw != nil {
+				if userLockoutConfig.ProgressiveDelay, err = parseutil.ParseBool(userLockoutConfig.ProgressiveDelayRaw); err != nil {
+					return multierror.Prefix(fmt.Errorf("invalid value for progressive_delay: %w", err), fmt.Sprintf("user_lockouts.%d", i))
+				}
+			}
+
+			if userLockoutConfig.ProgressiveDelayBaseRaw != nil {
+				if userLockoutConfig.ProgressiveDelayBase, err = parseutil.ParseDurationSecond(userLockoutConfig.ProgressiveDelayBaseRaw); err != nil {
+					return multierror.Prefix(fmt.Errorf("error parsing progressive_delay_base: %w", err), fmt.Sprintf("user_lockouts.%d", i))
+				}
+				if userLockoutConfig.ProgressiveDelayBase <= 0 {
+					return multierror.Prefix(errors.New("progressive_delay_base must be positive"), fmt.Sprintf("user_lockouts.%d", i))
+				}
+			}
+
+			if userLockoutConfig.ProgressiveDelayMaxRaw != nil {
+				if userLockoutConfig.ProgressiveDelayMax, err = parseutil.ParseDurationSecond(userLockoutConfig.ProgressiveDelayMaxRaw); err != nil {
+					return multierror.Prefix(fmt.Errorf("error parsing progressive_delay_max: %w", err), fmt.Sprintf("user_lockouts.%d", i))
+				}
+				if userLockoutConfig.ProgressiveDelayMax <= 0 {
+					return multierror.Prefix(errors.New("progressive_delay_max must be positive"), fmt.Sprintf("user_lockouts.%d", i))
+				}
+			}
+
+			if userLockoutConfig.ProgressiveDelayBaseRaw != nil && userLockoutConfig.ProgressiveDelayMaxRaw != nil &&
+				userLockoutConfig.ProgressiveDelayMax < userLockoutConfig.ProgressiveDelayBase {
+				return multierror.Prefix(errors.New("progressive_delay_max cannot be less than progressive_delay_base"), fmt.Sprintf("user_lockouts.%d", i))
+			}
 		}
 		userLockoutsMap[userLockoutConfig.Type] = &userLockoutConfig
 	}
@@ -137,6 +179,15 @@ func setUserLockoutValueAllInMap(userLockoutAll *UserLockout) *UserLockout {
 	if userLockoutAll.DisableLockoutRaw == nil {
 		userLockoutAll.DisableLockout = DisableUserLockoutDefault
 	}
+	if userLockoutAll.ProgressiveDelayRaw == nil {
+		userLockoutAll.ProgressiveDelay = ProgressiveDelayDefault
+	}
+	if userLockoutAll.ProgressiveDelayBaseRaw == nil {
+		userLockoutAll.ProgressiveDelayBase = ProgressiveDelayBaseDefault
+	}
+	if userLockoutAll.ProgressiveDelayMaxRaw == nil {
+		userLockoutAll.ProgressiveDelayMax = ProgressiveDelayMaxDefault
+	}
 	return setNilValuesForRawUserLockoutFields(userLockoutAll)
 }
 
@@ -170,6 +221,15 @@ func setMissingUserLockoutValuesInMap(userLockoutsMap map[string]*UserLockout) m
 		if userLockoutAuth.DisableLockoutRaw == nil {
 			userLockoutAuth.DisableLockout = userLockoutsMap["all"].DisableLockout
 		}
+		if userLockoutAuth.ProgressiveDelayRaw == nil {
+			userLockoutAuth.ProgressiveDelay = userLockoutsMap["all"].ProgressiveDelay
+		}
+		if userLockoutAuth.ProgressiveDelayBaseRaw == nil {
+			userLockoutAuth.ProgressiveDelayBase = userLockoutsMap["all"].ProgressiveDelayBase
+		}
+		if userLockoutAuth.ProgressiveDelayMaxRaw == nil {
+			userLockoutAuth.ProgressiveDelayMax = userLockoutsMap["all"].ProgressiveDelayMax
+		}
 		userLockoutAuth = setNilValuesForRawUserLockoutFields(userLockoutAuth)
 		userLockoutsMap[userLockoutAuth.Type] = userLockoutAuth
 	}
@@ -182,5 +242,8 @@ func setNilValuesForRawUserLockoutFields(userLockout *UserLockout) *UserLockout
 	userLockout.LockoutDurationRaw = nil
 	userLockout.LockoutCounterResetRaw = nil
 	userLockout.DisableLockoutRaw = nil
+	userLockout.ProgressiveDelayRaw = nil
+	userLockout.ProgressiveDelayBaseRaw = nil
+	userLockout.ProgressiveDelayMaxRaw = nil
 	return userLockout
 }
@@ -18,6 +18,9 @@ func TestParseUserLockout(t *testing.T) {
 		expectedConfigall.LockoutDuration = UserLockoutDurationDefault
 		expectedConfigall.LockoutCounterReset = UserLockoutCounterResetDefault
 		expectedConfigall.DisableLockout = DisableUserLockoutDefault
+		expectedConfigall.ProgressiveDelay = ProgressiveDelayDefault
+		expectedConfigall.ProgressiveDelayBase = ProgressiveDelayBaseDefault
+		expectedConfigall.ProgressiveDelayMax = ProgressiveDelayMaxDefault
 		expectedConfig["all"] = expectedConfigall
 
 		outputConfig := setMissingUserLockoutValuesInMap(inputConfig)
@@ -49,12 +52,18 @@ func TestParseUserLockout(t *testing.T) {
 		expectedConfigall.LockoutDuration = UserLockoutDurationDefault
 		expectedConfigall.LockoutCounterReset = 20 * time.Minute
 		expectedConfigall.DisableLockout = DisableUserLockoutDefault
+		expectedConfigall.ProgressiveDelay = ProgressiveDelayDefault
+		expectedConfigall.ProgressiveDelayBase = ProgressiveDelayBaseDefault
+		expectedConfigall.ProgressiveDelayMax = ProgressiveDelayMaxDefault
 		// expected values for userpass
 		expectedConfigUserpass.Type = "userpass"
 		expectedConfigUserpass.LockoutThreshold = UserLockoutThresholdDefault
 		expectedConfigUserpass.LockoutDuration = 10 * time.Minute
 		expectedConfigUserpass.LockoutCounterReset = 20 * time.Minute
 		expectedConfigUserpass.DisableLockout = DisableUserLockoutDefault
+		expectedConfigUserpass.ProgressiveDelay = ProgressiveDelayDefault
+		expectedConfigUserpass.ProgressiveDelayBase = ProgressiveDelayBaseDefault
+		expectedConfigUserpass.ProgressiveDelayMax = ProgressiveDelayMaxDefault
 		expectedConfig["all"] = expectedConfigall
 		expectedConfig["userpass"] = expectedConfigUserpass
 
@@ -66,4 +75,36 @@ func TestParseUserLockout(t *testing.T) {
 			t.Errorf("user lockout config: expected %#v\nactual %#v", expectedConfig["userpass"], outputConfig["userpass"])
 		}
 	})
+	t.Run("progressive delay for okta inherits base and max from all", func(t *testing.T) {
+		t.Parallel()
+		inputConfig := make(map[string]*UserLockout)
+		configAll := &UserLockout{}
+		configAll.Type = "all"
+		configAll.ProgressiveDelayMax = 2 * time.Minute
+		configAll.ProgressiveDelayMaxRaw = "2m"
+		inputConfig["all"] = configAll
+		configOkta := &UserLockout{}
+		configOkta.Type = "okta"
+		configOkta.ProgressiveDelay = true
+		configOkta.ProgressiveDelayRaw = "true"
+		inputConfig["okta"] = configOkta
+
+		expectedConfigOkta := &UserLockout{}
+		expectedConfigOkta.Type = "okta"
+		expectedConfigOkta.LockoutThreshold = UserLockoutThresholdDefault
+		expectedConfigOkta.LockoutDuration = UserLockoutDurationDefault
+		expectedConfigOkta.LockoutCounterReset = UserLockoutCounterResetDefault
+		expectedConfigOkta.DisableLockout = DisableUserLockoutDefault
+		expectedConfigOkta.ProgressiveDelay = true
+		expectedConfigOkta.ProgressiveDelayBase = ProgressiveDelayBaseDefault
+		expectedConfigOkta.ProgressiveDelayMax = 2 * time.Minute
+
+		outputConfig := setMissingUserLockoutValuesInMap(inputConfig)
+		if outputConfig["all"].ProgressiveDelay {
+			t.Errorf("user lockout config: expected progressive delay to be disabled for all")
+		}
+		if !reflect.DeepEqual(expectedConfigOkta, outputConfig["okta"]) {
+			t.Errorf("user lockout config: expected %#v\nactual %#v", expectedConfigOkta, outputConfig["okta"])
+		}
+	})
 }
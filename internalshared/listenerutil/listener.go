@@ -60,6 +60,7 @@ func UnixSocketListener(path string, unixSocketsConfig *UnixSocketsConfig) (net.
 	if unixSocketsConfig != nil {
 		err = setFilePermissions(path, unixSocketsConfig.User, unixSocketsConfig.Group, unixSocketsConfig.Mode)
 		if err != nil {
+			ln.Close()
 			return nil, fmt.Errorf("failed to set file system permissions on the socket file: %s", err)
 		}
 	}
@@ -220,7 +221,7 @@ GROUP:
 		// Try looking up the user by name
 		g, err := osuser.LookupGroup(group)
 		if err != nil {
-			return fmt.Errorf("failed to look up group %q: %v", user, err)
+			return fmt.Errorf("failed to look up group %q: %v", group, err)
 		}
 		gid, _ = strconv.Atoi(g.Gid)
 	}
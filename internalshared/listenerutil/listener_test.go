@@ -85,3 +85,62 @@ func TestUnixSocketListener(t *testing.T) {
 		}
 	})
 }
+
+func TestParsePeerAddressMapping(t *testing.T) {
+	u, err := osuser.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uid, _ := strconv.Atoi(u.Uid)
+
+	mapping, err := ParsePeerAddressMapping(map[string]string{
+		"uid:" + u.Username: "10.0.0.1",
+		"gid:100":           "fd00::1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ip := mapping.UIDs[uint32(uid)]; ip == nil || ip.String() != "10.0.0.1" {
+		t.Fatalf("bad uid mapping: %v", mapping.UIDs)
+	}
+	if ip := mapping.GIDs[100]; ip == nil || ip.String() != "fd00::1" {
+		t.Fatalf("bad gid mapping: %v", mapping.GIDs)
+	}
+
+	// The user takes precedence over the group
+	if ip := mapping.address(&PeerCredentials{UID: uint32(uid), GID: 100}); ip.String() != "10.0.0.1" {
+		t.Fatalf("expected uid mapping, got %v", ip)
+	}
+	if ip := mapping.address(&PeerCredentials{UID: uint32(uid) + 1, GID: 100}); ip.String() != "fd00::1" {
+		t.Fatalf("expected gid mapping, got %v", ip)
+	}
+	if ip := mapping.address(&PeerCredentials{UID: uint32(uid) + 1, GID: 101}); ip != nil {
+		t.Fatalf("expected no mapping, got %v", ip)
+	}
+	if !mapping.Default.Equal(DefaultPeerAddress) {
+		t.Fatalf("expected the default peer address, got %v", mapping.Default)
+	}
+
+	mapping, err = ParsePeerAddressMapping(map[string]string{
+		"default": "10.0.0.2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapping.Default.String() != "10.0.0.2" || mapping.mapsCredentials() {
+		t.Fatalf("bad default mapping: %#v", mapping)
+	}
+
+	for _, raw := range []map[string]string{
+		{"uid:1000": "not-an-ip"},
+		{"uid:1000": "10.0.0.0/8"},
+		{"pid:1000": "10.0.0.1"},
+		{"uid:": "10.0.0.1"},
+		{"1000": "10.0.0.1"},
+		{"default": "not-an-ip"},
+	} {
+		if _, err := ParsePeerAddressMapping(raw); err == nil {
+			t.Fatalf("expected error parsing %v", raw)
+		}
+	}
+}
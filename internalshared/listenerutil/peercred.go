@@ -0,0 +1,219 @@
+package listenerutil
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	osuser "os/user"
+	"strconv"
+	"strings"
+)
+
+// ErrPeerCredentialsUnsupported is returned when the credentials of the peer
+// of a unix domain socket can't be retrieved on the current platform
+var ErrPeerCredentialsUnsupported = errors.New("unix socket peer credentials are not supported on this platform")
+
+// PeerCredentials are the credentials of the process on the other end of a
+// unix domain socket connection
+type PeerCredentials struct {
+	PID int32
+	UID uint32
+	GID uint32
+}
+
+// PeerAddressDefaultKey is the socket_peer_addresses key setting the client
+// address of peers that are not mapped by their user or group.
+const PeerAddressDefaultKey = "default"
+
+// DefaultPeerAddress is the client address of unix domain socket peers that
+// are not mapped, unless another default is configured.
+var DefaultPeerAddress = net.IPv4(127, 0, 0, 1)
+
+// PeerAddressMapping maps the credentials of the peer of a unix domain socket
+// connection to the client address used for the connection. A mapping on the
+// user ID of the peer takes precedence over a mapping on its group ID, and
+// peers that are not mapped use the default address.
+type PeerAddressMapping struct {
+	UIDs    map[uint32]net.IP
+	GIDs    map[uint32]net.IP
+	Default net.IP
+}
+
+// ParsePeerAddressMapping parses the socket_peer_addresses listener option,
+// which maps "uid:<user>" and "gid:<group>" keys to IP addresses. Users and
+// groups can be given by ID or by name. The "default" key sets the address of
+// the peers that are not mapped, which is DefaultPeerAddress otherwise.
+func ParsePeerAddressMapping(raw map[string]string) (*PeerAddressMapping, error) {
+	mapping := &PeerAddressMapping{
+		UIDs:    make(map[uint32]net.IP),
+		GIDs:    make(map[uint32]net.IP),
+		Default: DefaultPeerAddress,
+	}
+
+	for key, value := range raw {
+		ip := net.ParseIP(strings.TrimSpace(value))
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address %q for peer %q", value, key)
+		}
+
+		if key == PeerAddressDefaultKey {
+			mapping.Default = ip
+			continue
+		}
+
+		kind, name, ok := strings.Cut(key, ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid peer %q, expected \"uid:<user>\", \"gid:<group>\" or %q", key, PeerAddressDefaultKey)
+		}
+
+		switch kind {
+		case "uid":
+			uid, err := lookupUID(name)
+			if err != nil {
+				return nil, err
+			}
+			mapping.UIDs[uid] = ip
+		case "gid":
+			gid, err := lookupGID(name)
+			if err != nil {
+				return nil, err
+			}
+			mapping.GIDs[gid] = ip
+		default:
+			return nil, fmt.Errorf("invalid peer %q, expected \"uid:<user>\", \"gid:<group>\" or %q", key, PeerAddressDefaultKey)
+		}
+	}
+
+	return mapping, nil
+}
+
+func lookupUID(user string) (uint32, error) {
+	if uid, err := strconv.ParseUint(user, 10, 32); err == nil {
+		return uint32(uid), nil
+	}
+
+	u, err := osuser.Lookup(user)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up user %q: %w", user, err)
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("unsupported uid %q for user %q", u.Uid, user)
+	}
+	return uint32(uid), nil
+}
+
+func lookupGID(group string) (uint32, error) {
+	if gid, err := strconv.ParseUint(group, 10, 32); err == nil {
+		return uint32(gid), nil
+	}
+
+	g, err := osuser.LookupGroup(group)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up group %q: %w", group, err)
+	}
+	gid, err := strconv.ParseUint(g.Gid, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("unsupported gid %q for group %q", g.Gid, group)
+	}
+	return uint32(gid), nil
+}
+
+// mapsCredentials returns whether the mapping needs the credentials of the
+// peers, which is when it maps any user or group
+func (m *PeerAddressMapping) mapsCredentials() bool {
+	return len(m.UIDs) > 0 || len(m.GIDs) > 0
+}
+
+// address returns the client address mapped from the peer credentials, or nil
+// if the peer is not mapped
+func (m *PeerAddressMapping) address(creds *PeerCredentials) net.IP {
+	if ip, ok := m.UIDs[creds.UID]; ok {
+		return ip
+	}
+	if ip, ok := m.GIDs[creds.GID]; ok {
+		return ip
+	}
+	return nil
+}
+
+// peerAddressListener is an implementation of net.Listener for unix domain
+// sockets that sets the remote address of the accepted connections to the
+// client address mapped from the credentials of the peer process. This plays
+// the same role as X-Forwarded-For does for TCP listeners, so that client
+// address based features, such as token CIDR bindings, can be used with
+// local clients. Peers that are not mapped get the default address of the
+// mapping, as the rest of Vault expects every client to have an IP address.
+type peerAddressListener struct {
+	net.Listener
+	mapping         *PeerAddressMapping
+	rejectNotMapped bool
+}
+
+// NewPeerAddressListener wraps a unix domain socket listener to map the
+// credentials of connecting processes to client addresses. If rejectNotMapped
+// is set, connections from peers that are not mapped are closed, otherwise
+// they are accepted with the default address of the mapping. A nil mapping
+// gives every peer DefaultPeerAddress.
+func NewPeerAddressListener(ln net.Listener, mapping *PeerAddressMapping, rejectNotMapped bool) (net.Listener, error) {
+	if mapping == nil {
+		mapping = &PeerAddressMapping{Default: DefaultPeerAddress}
+	}
+	if mapping.Default == nil {
+		return nil, errors.New("missing default peer address")
+	}
+	if rejectNotMapped && !mapping.mapsCredentials() {
+		return nil, errors.New("rejecting peers that are not mapped requires a user or group mapping")
+	}
+	if mapping.mapsCredentials() && !peerCredentialsSupported {
+		return nil, ErrPeerCredentialsUnsupported
+	}
+
+	return &peerAddressListener{
+		Listener:        ln,
+		mapping:         mapping,
+		rejectNotMapped: rejectNotMapped,
+	}, nil
+}
+
+func (l *peerAddressListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		var ip net.IP
+		if l.mapping.mapsCredentials() {
+			if creds, err := UnixPeerCredentials(conn); err == nil {
+				ip = l.mapping.address(creds)
+			}
+		}
+
+		if ip == nil {
+			if l.rejectNotMapped {
+				conn.Close()
+				continue
+			}
+			ip = l.mapping.Default
+		}
+
+		return &peerAddressConn{
+			Conn: conn,
+			remoteAddr: &net.TCPAddr{
+				IP: ip,
+			},
+		}, nil
+	}
+}
+
+// peerAddressConn is a connection with a remote address mapped from the peer
+// credentials
+type peerAddressConn struct {
+	net.Conn
+	remoteAddr net.Addr
+}
+
+func (c *peerAddressConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
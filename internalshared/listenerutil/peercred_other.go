@@ -0,0 +1,13 @@
+//go:build !linux
+
+package listenerutil
+
+import "net"
+
+const peerCredentialsSupported = false
+
+// UnixPeerCredentials returns the credentials of the process on the other end
+// of a unix domain socket connection
+func UnixPeerCredentials(_ net.Conn) (*PeerCredentials, error) {
+	return nil, ErrPeerCredentialsUnsupported
+}
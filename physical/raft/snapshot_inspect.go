@@ -0,0 +1,165 @@
+package raft
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/hashicorp/raft"
+	snapshot "github.com/hashicorp/raft-snapshot"
+	"github.com/hashicorp/vault/sdk/plugin/pb"
+)
+
+// ReadSnapshot reads a snapshot archive, as written by Snapshot, and calls fn
+// for each storage entry it contains. The integrity of the archive is checked
+// once all the entries have been read, so the entries passed to fn should not
+// be trusted until ReadSnapshot returns without error. The sealed hashes of the
+// archive are not verified since that requires access to the seal.
+func ReadSnapshot(in io.Reader, fn func(*pb.StorageEntry) error) (*raft.SnapshotMeta, error) {
+	reader, writer := io.Pipe()
+
+	var metadata *raft.SnapshotMeta
+	parseErrCh := make(chan error, 1)
+	go func() {
+		var err error
+		metadata, err = snapshot.Parse(in, writer)
+		writer.CloseWithError(err)
+		parseErrCh <- err
+	}()
+
+	protoReader := NewDelimitedReader(reader, math.MaxInt32)
+	readErr := func() error {
+		for {
+			entry := new(pb.StorageEntry)
+			if err := protoReader.ReadMsg(entry); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+	}()
+
+	// Unblock the parser if we stopped reading early
+	reader.CloseWithError(readErr)
+	parseErr := <-parseErrCh
+
+	// A failure to parse the archive is passed through the pipe, so only
+	// report the read error if it is what interrupted parsing
+	if readErr != nil && readErr != parseErr {
+		return nil, fmt.Errorf("failed to read snapshot data: %w", readErr)
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	return metadata, nil
+}
+
+// FilterSnapshot reads a snapshot archive and writes a new archive to out that
+// only contains the storage entries for which keep returns true. The raft
+// metadata of the original snapshot is preserved apart from its size. Since
+// the new archive can't be sealed without access to the seal, it must be
+// restored with the force option.
+func FilterSnapshot(in io.Reader, out io.Writer, keep func(key string) bool) (*raft.SnapshotMeta, error) {
+	// The size of the state must be known before it is added to the archive,
+	// so buffer the filtered entries in a temporary file
+	state, err := os.CreateTemp("", "vault-raft-snapshot-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(state.Name())
+	defer state.Close()
+
+	protoWriter := NewDelimitedWriter(state)
+	metadata, err := ReadSnapshot(in, func(entry *pb.StorageEntry) error {
+		if !keep(entry.Key) {
+			return nil
+		}
+		return protoWriter.WriteMsg(entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := state.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := state.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	metadata.Size = size
+
+	compressor := gzip.NewWriter(out)
+	if err := writeSnapshotArchive(compressor, metadata, state); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := compressor.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress snapshot file: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// writeSnapshotArchive writes the tar archive of a snapshot in the format read
+// by the raft-snapshot library, without the sealed hashes
+func writeSnapshotArchive(out io.Writer, metadata *raft.SnapshotMeta, state io.Reader) error {
+	now := time.Now()
+	archive := tar.NewWriter(out)
+
+	var metaBuffer bytes.Buffer
+	if err := json.NewEncoder(&metaBuffer).Encode(metadata); err != nil {
+		return fmt.Errorf("failed to encode snapshot metadata: %w", err)
+	}
+	metaHash := sha256.Sum256(metaBuffer.Bytes())
+	if err := archive.WriteHeader(&tar.Header{
+		Name:    "meta.json",
+		Mode:    0o600,
+		Size:    int64(metaBuffer.Len()),
+		ModTime: now,
+	}); err != nil {
+		return err
+	}
+	if _, err := io.Copy(archive, &metaBuffer); err != nil {
+		return err
+	}
+
+	stateHash := sha256.New()
+	if err := archive.WriteHeader(&tar.Header{
+		Name:    "state.bin",
+		Mode:    0o600,
+		Size:    metadata.Size,
+		ModTime: now,
+	}); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(archive, io.TeeReader(state, stateHash), metadata.Size); err != nil {
+		return err
+	}
+
+	sums := fmt.Sprintf("%x  %s\n%x  %s\n", metaHash[:], "meta.json", stateHash.Sum(nil), "state.bin")
+	if err := archive.WriteHeader(&tar.Header{
+		Name:    "SHA256SUMS",
+		Mode:    0o600,
+		Size:    int64(len(sums)),
+		ModTime: now,
+	}); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(archive, sums); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
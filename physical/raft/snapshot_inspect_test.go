@@ -0,0 +1,121 @@
+package raft
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	snapshot "github.com/hashicorp/raft-snapshot"
+	"github.com/hashicorp/vault/sdk/physical"
+	"github.com/hashicorp/vault/sdk/plugin/pb"
+)
+
+func TestRaft_Snapshot_ReadAndFilter(t *testing.T) {
+	raft, dir := getRaft(t, true, false)
+	defer os.RemoveAll(dir)
+
+	for _, prefix := range []string{"core/", "logical/a/", "logical/b/"} {
+		for i := 0; i < 10; i++ {
+			err := raft.Put(context.Background(), &physical.Entry{
+				Key:   fmt.Sprintf("%skey-%d", prefix, i),
+				Value: []byte(fmt.Sprintf("value-%d", i)),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var snap bytes.Buffer
+	if err := raft.Snapshot(&snap, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := make(map[string]string)
+	metadata, err := ReadSnapshot(bytes.NewReader(snap.Bytes()), func(entry *pb.StorageEntry) error {
+		keys[entry.Key] = string(entry.Value)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.Index == 0 || metadata.Term == 0 {
+		t.Fatalf("bad metadata: %#v", metadata)
+	}
+	if len(keys) != 30 || keys["logical/b/key-3"] != "value-3" {
+		t.Fatalf("bad keys: %v", keys)
+	}
+
+	// Errors from the callback are returned
+	_, err = ReadSnapshot(bytes.NewReader(snap.Bytes()), func(entry *pb.StorageEntry) error {
+		return fmt.Errorf("stop")
+	})
+	if err == nil || !strings.Contains(err.Error(), "stop") {
+		t.Fatalf("expected callback error, got: %v", err)
+	}
+
+	// Corrupted snapshots are rejected
+	if _, err := ReadSnapshot(bytes.NewReader(snap.Bytes()[:snap.Len()/2]), func(*pb.StorageEntry) error { return nil }); err == nil {
+		t.Fatal("expected error reading truncated snapshot")
+	}
+
+	var filtered bytes.Buffer
+	filteredMeta, err := FilterSnapshot(bytes.NewReader(snap.Bytes()), &filtered, func(key string) bool {
+		return strings.HasPrefix(key, "core/") || strings.HasPrefix(key, "logical/a/")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filteredMeta.Index != metadata.Index || filteredMeta.Term != metadata.Term {
+		t.Fatalf("expected metadata to be preserved, got: %#v", filteredMeta)
+	}
+
+	// The filtered snapshot must pass the integrity checks of the snapshot
+	// library used on restore
+	verifiedMeta, err := snapshot.Verify(bytes.NewReader(filtered.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verifiedMeta.Size != filteredMeta.Size {
+		t.Fatalf("expected size %d, got %d", filteredMeta.Size, verifiedMeta.Size)
+	}
+
+	keys = make(map[string]string)
+	if _, err := ReadSnapshot(bytes.NewReader(filtered.Bytes()), func(entry *pb.StorageEntry) error {
+		keys[entry.Key] = string(entry.Value)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 20 {
+		t.Fatalf("expected 20 keys, got: %v", keys)
+	}
+	for key := range keys {
+		if strings.HasPrefix(key, "logical/b/") {
+			t.Fatalf("unexpected key %q", key)
+		}
+	}
+
+	// The filtered snapshot can be restored without access to the seal
+	snapFile, cleanup, restoreMeta, err := raft.WriteSnapshotToTemp(io.NopCloser(bytes.NewReader(filtered.Bytes())), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	if err := raft.RestoreSnapshot(context.Background(), restoreMeta, snapFile); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := raft.Get(context.Background(), "logical/a/key-1")
+	if err != nil || entry == nil {
+		t.Fatalf("expected restored key, got %v, err: %v", entry, err)
+	}
+	entry, err = raft.Get(context.Background(), "logical/b/key-1")
+	if err != nil || entry != nil {
+		t.Fatalf("expected filtered key to be removed, got %v, err: %v", entry, err)
+	}
+}
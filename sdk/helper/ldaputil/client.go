@@ -9,6 +9,7 @@ import (
 	"math"
 	"net"
 	"net/url"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
@@ -493,6 +494,129 @@ func (c *Client) performLdapTokenGroupsSearch(cfg *ConfigEntry, conn Connection,
 	return groupEntries, nil
 }
 
+// ldapMatchingRuleInChain is the OID of Active Directory's
+// LDAP_MATCHING_RULE_IN_CHAIN, which matches through the whole chain of
+// ancestry of a DN-valued attribute.
+const ldapMatchingRuleInChain = "1.2.840.113556.1.4.1941"
+
+// groupMemberAttributes are the DN-valued attributes followed when resolving
+// nested group membership.
+var groupMemberAttributes = []string{"member", "uniqueMember"}
+
+// groupObjectClasses are the object classes which identify an entry as a group
+// when resolving group members.
+var groupObjectClasses = []string{"group", "groupOfNames", "groupOfUniqueNames", "posixGroup"}
+
+// groupMemberSearchBatchSize bounds the number of group members read by a
+// single search, to keep the search filter to a reasonable size.
+const groupMemberSearchBatchSize = 100
+
+func (c *Client) searchGroups(cfg *ConfigEntry, conn Connection, filter string, attributes []string) ([]*ldap.Entry, error) {
+	if c.Logger.IsDebug() {
+		c.Logger.Debug("searching", "groupdn", cfg.GroupDN, "rendered_query", filter)
+	}
+
+	req := &ldap.SearchRequest{
+		BaseDN:     cfg.GroupDN,
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     filter,
+		Attributes: attributes,
+		SizeLimit:  math.MaxInt32,
+	}
+
+	var result *ldap.SearchResult
+	var err error
+	if paging, ok := conn.(PagingConnection); ok {
+		result, err = paging.SearchWithPaging(req, math.MaxInt32)
+	} else {
+		result, err = conn.Search(req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("LDAP search failed: %w", err)
+	}
+
+	return result.Entries, nil
+}
+
+// groupDNs returns the DNs of the groups described by the given entries, as
+// returned by a group search. An entry whose cfg.GroupAttr values are DNs (such
+// as memberOf) describes those groups, otherwise it is a group itself.
+func groupDNs(cfg *ConfigEntry, entries []*ldap.Entry) []string {
+	var dns []string
+	for _, e := range entries {
+		var found bool
+		for _, val := range e.GetAttributeValues(cfg.GroupAttr) {
+			if dn, err := ldap.ParseDN(val); err == nil && len(dn.RDNs) > 0 {
+				dns = append(dns, val)
+				found = true
+			}
+		}
+		if !found {
+			dns = append(dns, e.DN)
+		}
+	}
+	return dns
+}
+
+/*
+ * performLdapNestedGroupsSearch returns the groups in which the given groups are nested, directly or indirectly.
+ *
+ * If cfg.NestedGroupsMaxDepth is 0, the nesting is resolved by the server: a single search is made for groups
+ * which have the user as a member through LDAP_MATCHING_RULE_IN_CHAIN. This is only supported by Active Directory.
+ *
+ * Otherwise, each level of nesting is resolved by searching cfg.GroupDN for the groups which have any of the
+ * groups found at the previous level as a member, up to cfg.NestedGroupsMaxDepth levels.
+ */
+func (c *Client) performLdapNestedGroupsSearch(cfg *ConfigEntry, conn Connection, userDN string, entries []*ldap.Entry) ([]*ldap.Entry, error) {
+	if cfg.GroupDN == "" {
+		c.Logger.Warn("groupdn is empty, will not query server for nested groups")
+		return nil, nil
+	}
+
+	if cfg.NestedGroupsMaxDepth == 0 {
+		filter := fmt.Sprintf("(member:%s:=%s)", ldapMatchingRuleInChain, ldap.EscapeFilter(userDN))
+		return c.searchGroups(cfg, conn, filter, []string{cfg.GroupAttr})
+	}
+
+	seen := make(map[string]bool)
+	var frontier []string
+	for _, dn := range groupDNs(cfg, entries) {
+		if !seen[strings.ToLower(dn)] {
+			seen[strings.ToLower(dn)] = true
+			frontier = append(frontier, dn)
+		}
+	}
+
+	var nested []*ldap.Entry
+	for depth := 0; depth < cfg.NestedGroupsMaxDepth && len(frontier) > 0; depth++ {
+		var filter strings.Builder
+		filter.WriteString("(|")
+		for _, dn := range frontier {
+			for _, attr := range groupMemberAttributes {
+				fmt.Fprintf(&filter, "(%s=%s)", attr, ldap.EscapeFilter(dn))
+			}
+		}
+		filter.WriteString(")")
+
+		results, err := c.searchGroups(cfg, conn, filter.String(), []string{cfg.GroupAttr})
+		if err != nil {
+			return nil, err
+		}
+
+		frontier = nil
+		for _, e := range results {
+			if seen[strings.ToLower(e.DN)] {
+				continue
+			}
+			seen[strings.ToLower(e.DN)] = true
+			nested = append(nested, e)
+			frontier = append(frontier, e.DN)
+		}
+	}
+
+	return nested, nil
+}
+
 /*
  * getLdapGroups queries LDAP and returns a slice describing the set of groups the authenticated user is a member of.
  *
@@ -510,6 +634,8 @@ func (c *Client) performLdapTokenGroupsSearch(cfg *ConfigEntry, conn Connection,
  *   cfg.GroupDN     = "OU=Groups,DC=myorg,DC=com"
  *   cfg.GroupAttr   = "cn"
  *
+ * If cfg.NestedGroups is true, the groups in which those groups are nested are returned as well.
+ *
  * NOTE - If cfg.GroupFilter is empty, no query is performed and an empty result slice is returned.
  *
  */
@@ -524,6 +650,11 @@ func (c *Client) GetLdapGroups(cfg *ConfigEntry, conn Connection, userDN string,
 		} else {
 			entries, err = c.performLdapFilterGroupsSearch(cfg, conn, userDN, username)
 		}
+		if err == nil && cfg.NestedGroups {
+			var nested []*ldap.Entry
+			nested, err = c.performLdapNestedGroupsSearch(cfg, conn, userDN, entries)
+			entries = append(entries, nested...)
+		}
 	}
 	if err != nil {
 		return nil, err
@@ -560,6 +691,213 @@ func (c *Client) GetLdapGroups(cfg *ConfigEntry, conn Connection, userDN string,
 	return ldapGroups, nil
 }
 
+/*
+ * GetLdapGroupMembers queries LDAP and returns the usernames of the members of the named group.
+ *
+ * The group is looked up by cfg.GroupAttr under cfg.GroupDN. Members listed by DN through the member or
+ * uniqueMember attributes are resolved to usernames using cfg.UserAttr, while those listed by memberUid are
+ * returned as-is. Member DNs are resolved with one search per parent entry and nesting level, rather than
+ * one per member. If cfg.NestedGroups is true, members of groups nested in the group are returned as well,
+ * up to cfg.NestedGroupsMaxDepth levels deep, or without limit if that is 0.
+ *
+ * NOTE - If cfg.GroupDN is empty, no query is performed and an empty result slice is returned.
+ */
+func (c *Client) GetLdapGroupMembers(cfg *ConfigEntry, conn Connection, groupName string) ([]string, error) {
+	if cfg.GroupDN == "" {
+		c.Logger.Warn("groupdn is empty, will not query server")
+		return make([]string, 0), nil
+	}
+
+	memberAttrs := append([]string{"memberUid"}, groupMemberAttributes...)
+	filter := fmt.Sprintf("(&(%s=%s)(|(objectClass=%s)))", ldap.EscapeFilter(cfg.GroupAttr), ldap.EscapeFilter(groupName), strings.Join(groupObjectClasses, ")(objectClass="))
+	groups, err := c.searchGroups(cfg, conn, filter, memberAttrs)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[string]bool)
+	seen := make(map[string]bool)
+	for _, g := range groups {
+		seen[strings.ToLower(g.DN)] = true
+	}
+
+	for depth := 0; len(groups) > 0; depth++ {
+		var memberDNs []string
+		for _, g := range groups {
+			for _, uid := range g.GetAttributeValues("memberUid") {
+				members[uid] = true
+			}
+
+			for _, attr := range groupMemberAttributes {
+				for _, dn := range g.GetAttributeValues(attr) {
+					if seen[strings.ToLower(dn)] {
+						continue
+					}
+					seen[strings.ToLower(dn)] = true
+					memberDNs = append(memberDNs, dn)
+				}
+			}
+		}
+
+		entries := c.searchMemberEntries(conn, memberDNs, append([]string{"objectClass", cfg.UserAttr}, memberAttrs...))
+
+		var next []*ldap.Entry
+		for _, dn := range memberDNs {
+			member, ok := entries[strings.ToLower(dn)]
+			if !ok {
+				c.Logger.Warn("unable to read group member", "groupname", groupName, "dn", dn)
+				continue
+			}
+
+			if !isGroupEntry(member) {
+				if name := member.GetAttributeValue(cfg.UserAttr); name != "" {
+					members[name] = true
+				} else {
+					members[dn] = true
+				}
+				continue
+			}
+			if cfg.NestedGroups && (cfg.NestedGroupsMaxDepth == 0 || depth < cfg.NestedGroupsMaxDepth) {
+				next = append(next, member)
+			}
+		}
+		groups = next
+	}
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// searchMemberEntries reads the entries with the given DNs, keyed by their
+// lowercased DN. Entries sharing a parent are read together by searching the
+// parent's direct children for their RDNs, in batches of
+// groupMemberSearchBatchSize. DNs which can't be read are left out.
+func (c *Client) searchMemberEntries(conn Connection, dns []string, attributes []string) map[string]*ldap.Entry {
+	type child struct {
+		dn  string
+		rdn *ldap.RelativeDN
+	}
+	var parents []string
+	children := make(map[string][]child)
+	for _, dn := range dns {
+		parsed, err := ldap.ParseDN(dn)
+		if err != nil || len(parsed.RDNs) == 0 {
+			c.Logger.Warn("invalid group member DN", "dn", dn, "error", err)
+			continue
+		}
+		parent := parentDN(dn)
+		key := strings.ToLower(parent)
+		if _, ok := children[key]; !ok {
+			parents = append(parents, parent)
+		}
+		children[key] = append(children[key], child{dn: dn, rdn: parsed.RDNs[0]})
+	}
+
+	entries := make(map[string]*ldap.Entry)
+	for _, parent := range parents {
+		pending := children[strings.ToLower(parent)]
+		for len(pending) > 0 {
+			batch := pending
+			if len(batch) > groupMemberSearchBatchSize {
+				batch = batch[:groupMemberSearchBatchSize]
+			}
+			pending = pending[len(batch):]
+
+			var filter strings.Builder
+			byRDN := make(map[string]string, len(batch))
+			filter.WriteString("(|")
+			for _, ch := range batch {
+				filter.WriteString(rdnFilter(ch.rdn))
+				byRDN[rdnKey(ch.rdn)] = ch.dn
+			}
+			filter.WriteString(")")
+
+			req := &ldap.SearchRequest{
+				BaseDN:     parent,
+				Scope:      ldap.ScopeSingleLevel,
+				Filter:     filter.String(),
+				Attributes: attributes,
+				SizeLimit:  math.MaxInt32,
+			}
+			var result *ldap.SearchResult
+			var err error
+			if paging, ok := conn.(PagingConnection); ok {
+				result, err = paging.SearchWithPaging(req, math.MaxInt32)
+			} else {
+				result, err = conn.Search(req)
+			}
+			if err != nil {
+				c.Logger.Warn("unable to read group members", "parent", parent, "error", err)
+				continue
+			}
+
+			for _, e := range result.Entries {
+				parsed, err := ldap.ParseDN(e.DN)
+				if err != nil || len(parsed.RDNs) == 0 {
+					continue
+				}
+				if dn, ok := byRDN[rdnKey(parsed.RDNs[0])]; ok {
+					entries[strings.ToLower(dn)] = e
+				}
+			}
+		}
+	}
+
+	return entries
+}
+
+// parentDN returns the DN of the parent of the entry with the given DN, by
+// dropping its first RDN.
+func parentDN(dn string) string {
+	for i := 0; i < len(dn); i++ {
+		switch dn[i] {
+		case '\\':
+			i++
+		case ',':
+			return strings.TrimSpace(dn[i+1:])
+		}
+	}
+	return ""
+}
+
+// rdnKey returns a case-insensitive representation of the RDN, used to match
+// search results with the DNs they were searched for.
+func rdnKey(rdn *ldap.RelativeDN) string {
+	parts := make([]string, 0, len(rdn.Attributes))
+	for _, attr := range rdn.Attributes {
+		parts = append(parts, strings.ToLower(attr.Type)+"="+strings.ToLower(attr.Value))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "+")
+}
+
+// rdnFilter returns a filter matching the entries with the given RDN.
+func rdnFilter(rdn *ldap.RelativeDN) string {
+	var filter strings.Builder
+	filter.WriteString("(&")
+	for _, attr := range rdn.Attributes {
+		fmt.Fprintf(&filter, "(%s=%s)", ldap.EscapeFilter(attr.Type), ldap.EscapeFilter(attr.Value))
+	}
+	filter.WriteString(")")
+	return filter.String()
+}
+
+func isGroupEntry(e *ldap.Entry) bool {
+	for _, class := range e.GetAttributeValues("objectClass") {
+		for _, groupClass := range groupObjectClasses {
+			if strings.EqualFold(class, groupClass) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // EscapeLDAPValue is exported because a plugin uses it outside this package.
 func EscapeLDAPValue(input string) string {
 	if input == "" {
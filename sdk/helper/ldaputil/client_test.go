@@ -1,8 +1,16 @@
 package ldaputil
 
 import (
+	"crypto/tls"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-ldap/ldap/v3"
 	"github.com/hashicorp/go-hclog"
 )
 
@@ -85,3 +93,237 @@ func TestSIDBytesToString(t *testing.T) {
 		}
 	}
 }
+
+// fakeDirectory is a Connection over a handful of users and groups. It only
+// understands the filters used when resolving groups and group members.
+type fakeDirectory struct {
+	entries  map[string]map[string][]string
+	inChain  map[string][]string
+	searches int
+}
+
+var (
+	memberFilterRe    = regexp.MustCompile(`\((?:member|uniqueMember)=([^)]*)\)`)
+	groupLookupRe     = regexp.MustCompile(`^\(&\(([^=]+)=([^)]*)\)`)
+	assertionFilterRe = regexp.MustCompile(`\(([^&|=()]+)=([^)]*)\)`)
+)
+
+func (f *fakeDirectory) entry(dn string, attrs []string) *ldap.Entry {
+	attributes := map[string][]string{}
+	for _, attr := range attrs {
+		if vals, ok := f.entries[dn][attr]; ok {
+			attributes[attr] = vals
+		}
+	}
+	return ldap.NewEntry(dn, attributes)
+}
+
+func (f *fakeDirectory) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	f.searches++
+	result := &ldap.SearchResult{}
+	switch {
+	case req.Scope == ldap.ScopeSingleLevel:
+		// Only single-attribute RDNs are used by the tests
+		for _, m := range assertionFilterRe.FindAllStringSubmatch(req.Filter, -1) {
+			dn := m[1] + "=" + m[2] + "," + req.BaseDN
+			if _, ok := f.entries[dn]; ok {
+				result.Entries = append(result.Entries, f.entry(dn, req.Attributes))
+			}
+		}
+	case strings.HasPrefix(req.Filter, "(member:"+ldapMatchingRuleInChain+":="):
+		userDN := strings.TrimSuffix(strings.TrimPrefix(req.Filter, "(member:"+ldapMatchingRuleInChain+":="), ")")
+		for _, dn := range f.inChain[userDN] {
+			result.Entries = append(result.Entries, f.entry(dn, req.Attributes))
+		}
+	case groupLookupRe.MatchString(req.Filter):
+		m := groupLookupRe.FindStringSubmatch(req.Filter)
+		for dn, attrs := range f.entries {
+			if len(attrs[m[1]]) > 0 && attrs[m[1]][0] == m[2] && isGroupEntry(f.entry(dn, []string{"objectClass"})) {
+				result.Entries = append(result.Entries, f.entry(dn, req.Attributes))
+			}
+		}
+	default:
+		wanted := map[string]bool{}
+		for _, m := range memberFilterRe.FindAllStringSubmatch(req.Filter, -1) {
+			wanted[m[1]] = true
+		}
+		for dn, attrs := range f.entries {
+			for _, member := range append(attrs["member"], attrs["uniqueMember"]...) {
+				if wanted[member] {
+					result.Entries = append(result.Entries, f.entry(dn, req.Attributes))
+					break
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeDirectory) Bind(username, password string) error           { return nil }
+func (f *fakeDirectory) Close()                                         {}
+func (f *fakeDirectory) Add(addRequest *ldap.AddRequest) error          { return nil }
+func (f *fakeDirectory) Modify(modifyRequest *ldap.ModifyRequest) error { return nil }
+func (f *fakeDirectory) Del(delRequest *ldap.DelRequest) error          { return nil }
+func (f *fakeDirectory) StartTLS(config *tls.Config) error              { return nil }
+func (f *fakeDirectory) SetTimeout(timeout time.Duration)               {}
+func (f *fakeDirectory) UnauthenticatedBind(username string) error      { return nil }
+
+func testNestedDirectory() *fakeDirectory {
+	group := func(cn string, members ...string) map[string][]string {
+		return map[string][]string{"cn": {cn}, "objectClass": {"groupOfNames"}, "member": members}
+	}
+	user := func(cn string) map[string][]string {
+		return map[string][]string{"cn": {cn}, "objectClass": {"person"}}
+	}
+	return &fakeDirectory{
+		entries: map[string]map[string][]string{
+			"cn=fry,ou=people,dc=example":    user("fry"),
+			"cn=bender,ou=people,dc=example": user("bender"),
+			"cn=leela,ou=people,dc=example":  user("leela"),
+			"cn=crew,ou=groups,dc=example":   group("crew", "cn=fry,ou=people,dc=example", "cn=bender,ou=people,dc=example"),
+			"cn=ship,ou=groups,dc=example":   group("ship", "cn=crew,ou=groups,dc=example", "cn=leela,ou=people,dc=example", "cn=world,ou=groups,dc=example"),
+			"cn=company,ou=groups,dc=example": {
+				"cn":          {"company"},
+				"objectClass": {"posixGroup"},
+				"description": {"Planet Express"},
+				"member":      {"cn=ship,ou=groups,dc=example"},
+				"memberUid":   {"farnsworth"},
+			},
+			// ship is a member of company, which is a member of world, which is in turn a
+			// member of ship, to check cycles are handled.
+			"cn=world,ou=groups,dc=example": group("world", "cn=company,ou=groups,dc=example"),
+		},
+		inChain: map[string][]string{
+			"cn=fry,ou=people,dc=example": {
+				"cn=crew,ou=groups,dc=example",
+				"cn=ship,ou=groups,dc=example",
+				"cn=company,ou=groups,dc=example",
+				"cn=world,ou=groups,dc=example",
+			},
+		},
+	}
+}
+
+func TestGetLdapGroups_Nested(t *testing.T) {
+	ldapClient := Client{
+		Logger: hclog.NewNullLogger(),
+		LDAP:   NewLDAP(),
+	}
+	dir := testNestedDirectory()
+
+	testcases := map[string]struct {
+		nested   bool
+		maxDepth int
+		expected []string
+	}{
+		"not nested":    {false, 0, []string{"crew"}},
+		"in chain":      {true, 0, []string{"company", "crew", "ship", "world"}},
+		"max depth one": {true, 1, []string{"crew", "ship"}},
+		"max depth two": {true, 2, []string{"company", "crew", "ship"}},
+		"max depth ten": {true, 10, []string{"company", "crew", "ship", "world"}},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			cfg := &ConfigEntry{
+				GroupDN:              "dc=example",
+				GroupFilter:          "(member={{.UserDN}})",
+				GroupAttr:            "cn",
+				NestedGroups:         tc.nested,
+				NestedGroupsMaxDepth: tc.maxDepth,
+			}
+			groups, err := ldapClient.GetLdapGroups(cfg, dir, "cn=fry,ou=people,dc=example", "fry")
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Strings(groups)
+			if !reflect.DeepEqual(groups, tc.expected) {
+				t.Fatalf("expected groups %v, got %v", tc.expected, groups)
+			}
+		})
+	}
+}
+
+func TestGetLdapGroupMembers(t *testing.T) {
+	ldapClient := Client{
+		Logger: hclog.NewNullLogger(),
+		LDAP:   NewLDAP(),
+	}
+	dir := testNestedDirectory()
+
+	testcases := map[string]struct {
+		group     string
+		groupAttr string
+		nested    bool
+		maxDepth  int
+		expected  []string
+	}{
+		"direct":         {"company", "cn", false, 0, []string{"farnsworth"}},
+		"nested":         {"company", "cn", true, 0, []string{"bender", "farnsworth", "fry", "leela"}},
+		"nested depth 1": {"company", "cn", true, 1, []string{"farnsworth", "leela"}},
+		"cycle":          {"world", "cn", true, 0, []string{"bender", "farnsworth", "fry", "leela"}},
+		"no such group":  {"fry", "cn", true, 0, []string{}},
+		"group attr":     {"Planet Express", "description", false, 0, []string{"farnsworth"}},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			cfg := &ConfigEntry{
+				GroupDN:              "dc=example",
+				GroupAttr:            tc.groupAttr,
+				UserAttr:             "cn",
+				NestedGroups:         tc.nested,
+				NestedGroupsMaxDepth: tc.maxDepth,
+			}
+			members, err := ldapClient.GetLdapGroupMembers(cfg, dir, tc.group)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(members, tc.expected) {
+				t.Fatalf("expected members %v, got %v", tc.expected, members)
+			}
+		})
+	}
+}
+
+func TestGetLdapGroupMembers_Batched(t *testing.T) {
+	ldapClient := Client{
+		Logger: hclog.NewNullLogger(),
+		LDAP:   NewLDAP(),
+	}
+
+	dir := &fakeDirectory{
+		entries: map[string]map[string][]string{},
+	}
+	var members, expected []string
+	for i := 0; i < 250; i++ {
+		name := fmt.Sprintf("user%03d", i)
+		dn := "cn=" + name + ",ou=people,dc=example"
+		dir.entries[dn] = map[string][]string{"cn": {name}, "objectClass": {"person"}}
+		members = append(members, dn)
+		expected = append(expected, name)
+	}
+	dir.entries["cn=staff,ou=groups,dc=example"] = map[string][]string{
+		"cn":          {"staff"},
+		"objectClass": {"groupOfNames"},
+		"member":      members,
+	}
+
+	cfg := &ConfigEntry{
+		GroupDN:   "dc=example",
+		GroupAttr: "cn",
+		UserAttr:  "cn",
+	}
+	names, err := ldapClient.GetLdapGroupMembers(cfg, dir, "staff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected members %v, got %v", expected, names)
+	}
+
+	// One search for the group, and one for each batch of members
+	if dir.searches != 4 {
+		t.Fatalf("expected 4 searches, got %d", dir.searches)
+	}
+}
@@ -216,6 +216,18 @@ Default: ({{.UserAttr}}={{.Username}})`,
 			Description: "If true, use the Active Directory tokenGroups constructed attribute of the user to find the group memberships. This will find all security groups including nested ones.",
 		},
 
+		"nested_groups": {
+			Type:        framework.TypeBool,
+			Default:     false,
+			Description: "If true, also resolve the groups that the user's groups are themselves members of. Not used when use_token_groups is set, as tokenGroups already includes nested groups.",
+		},
+
+		"nested_groups_max_depth": {
+			Type:        framework.TypeInt,
+			Default:     0,
+			Description: "How many levels of group nesting to resolve when nested_groups is set. If 0, nesting is resolved by the server using the Active Directory LDAP_MATCHING_RULE_IN_CHAIN matching rule, without a depth limit. Otherwise nesting is resolved by Vault, following the member attribute of groups in groupdn.",
+		},
+
 		"use_pre111_group_cn_behavior": {
 			Type:        framework.TypeBool,
 			Description: "In Vault 1.1.1 a fix for handling group CN values of different cases unfortunately introduced a regression that could cause previously defined groups to not be found due to a change in the resulting name. If set true, the pre-1.1.1 behavior for matching group CNs will be used. This is only needed in some upgrade scenarios for backwards compatibility. It is enabled by default if the config is upgraded but disabled by default on new configurations.",
@@ -388,6 +400,18 @@ func NewConfigEntry(existing *ConfigEntry, d *framework.FieldData) (*ConfigEntry
 		cfg.UseTokenGroups = d.Get("use_token_groups").(bool)
 	}
 
+	if _, ok := d.Raw["nested_groups"]; ok || !hadExisting {
+		cfg.NestedGroups = d.Get("nested_groups").(bool)
+	}
+
+	if _, ok := d.Raw["nested_groups_max_depth"]; ok || !hadExisting {
+		maxDepth := d.Get("nested_groups_max_depth").(int)
+		if maxDepth < 0 {
+			return nil, errors.New("'nested_groups_max_depth' must not be negative")
+		}
+		cfg.NestedGroupsMaxDepth = maxDepth
+	}
+
 	if _, ok := d.Raw["request_timeout"]; ok || !hadExisting {
 		cfg.RequestTimeout = d.Get("request_timeout").(int)
 	}
@@ -416,6 +440,8 @@ type ConfigEntry struct {
 	TLSMinVersion            string `json:"tls_min_version"`
 	TLSMaxVersion            string `json:"tls_max_version"`
 	UseTokenGroups           bool   `json:"use_token_groups"`
+	NestedGroups             bool   `json:"nested_groups"`
+	NestedGroupsMaxDepth     int    `json:"nested_groups_max_depth"`
 	UsePre111GroupCNBehavior *bool  `json:"use_pre111_group_cn_behavior"`
 	RequestTimeout           int    `json:"request_timeout"`
 
@@ -436,26 +462,28 @@ func (c *ConfigEntry) Map() map[string]interface{} {
 
 func (c *ConfigEntry) PasswordlessMap() map[string]interface{} {
 	m := map[string]interface{}{
-		"url":                    c.Url,
-		"userdn":                 c.UserDN,
-		"groupdn":                c.GroupDN,
-		"groupfilter":            c.GroupFilter,
-		"groupattr":              c.GroupAttr,
-		"userfilter":             c.UserFilter,
-		"upndomain":              c.UPNDomain,
-		"userattr":               c.UserAttr,
-		"certificate":            c.Certificate,
-		"insecure_tls":           c.InsecureTLS,
-		"starttls":               c.StartTLS,
-		"binddn":                 c.BindDN,
-		"deny_null_bind":         c.DenyNullBind,
-		"discoverdn":             c.DiscoverDN,
-		"tls_min_version":        c.TLSMinVersion,
-		"tls_max_version":        c.TLSMaxVersion,
-		"use_token_groups":       c.UseTokenGroups,
-		"anonymous_group_search": c.AnonymousGroupSearch,
-		"request_timeout":        c.RequestTimeout,
-		"username_as_alias":      c.UsernameAsAlias,
+		"url":                     c.Url,
+		"userdn":                  c.UserDN,
+		"groupdn":                 c.GroupDN,
+		"groupfilter":             c.GroupFilter,
+		"groupattr":               c.GroupAttr,
+		"userfilter":              c.UserFilter,
+		"upndomain":               c.UPNDomain,
+		"userattr":                c.UserAttr,
+		"certificate":             c.Certificate,
+		"insecure_tls":            c.InsecureTLS,
+		"starttls":                c.StartTLS,
+		"binddn":                  c.BindDN,
+		"deny_null_bind":          c.DenyNullBind,
+		"discoverdn":              c.DiscoverDN,
+		"tls_min_version":         c.TLSMinVersion,
+		"tls_max_version":         c.TLSMaxVersion,
+		"use_token_groups":        c.UseTokenGroups,
+		"nested_groups":           c.NestedGroups,
+		"nested_groups_max_depth": c.NestedGroupsMaxDepth,
+		"anonymous_group_search":  c.AnonymousGroupSearch,
+		"request_timeout":         c.RequestTimeout,
+		"username_as_alias":       c.UsernameAsAlias,
 	}
 	if c.CaseSensitiveNames != nil {
 		m["case_sensitive_names"] = *c.CaseSensitiveNames
@@ -165,6 +165,8 @@ var jsonConfigDefault = []byte(`
   "tls_min_version": "tls12",
   "tls_max_version": "tls12",
   "use_token_groups": false,
+  "nested_groups": false,
+  "nested_groups_max_depth": 0,
   "use_pre111_group_cn_behavior": null,
   "username_as_alias": false,
   "request_timeout": 90,
@@ -4,6 +4,8 @@ import (
 	"context"
 	"crypto/sha256"
 	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
 
@@ -35,6 +37,12 @@ type runConfig struct {
 	// Initialized with what's in PluginRunner.Env, but can be added to
 	env []string
 
+	// Provided by PluginRunner, used to run the plugin with resource limits
+	// and sandboxing
+	runtime    *PluginRuntimeConfig
+	launcher   *PluginLauncher
+	cgroupName string
+
 	PluginClientConfig
 }
 
@@ -87,6 +95,17 @@ func (rc runConfig) makeConfig(ctx context.Context) (*plugin.ClientConfig, error
 		Hash:     sha256.New(),
 	}
 
+	if rc.runtime.Sandboxed() {
+		var err error
+		cmd, err = rc.sandboxCmd(cmd)
+		if err != nil {
+			return nil, err
+		}
+
+		// The launcher checks the checksum of the plugin before running it
+		secureConfig = nil
+	}
+
 	clientConfig := &plugin.ClientConfig{
 		HandshakeConfig:  rc.HandshakeConfig,
 		VersionedPlugins: rc.PluginSets,
@@ -103,6 +122,52 @@ func (rc runConfig) makeConfig(ctx context.Context) (*plugin.ClientConfig, error
 	return clientConfig, nil
 }
 
+// sandboxCmd returns the command running the plugin command cmd through the
+// plugin launcher.
+func (rc runConfig) sandboxCmd(cmd *exec.Cmd) (*exec.Cmd, error) {
+	if err := rc.runtime.Validate(); err != nil {
+		return nil, err
+	}
+	if rc.launcher == nil || rc.launcher.Command == "" {
+		return nil, errors.New("plugin runtime configuration requires a plugin launcher")
+	}
+
+	cgroup, err := setupPluginCgroup(rc.launcher.CgroupParent, rc.cgroupName, rc.runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := sandboxSpec{
+		Command:        rc.command,
+		Args:           rc.args,
+		Sha256:         rc.sha256,
+		Cgroup:         cgroup,
+		UID:            rc.runtime.UID,
+		GID:            rc.runtime.GID,
+		SeccompProfile: rc.runtime.SeccompProfile,
+		RestrictEnv:    rc.runtime.RestrictEnv,
+	}
+	if spec.RestrictEnv {
+		// Keep the variables go-plugin adds to the environment of the plugin
+		spec.Env = cmd.Env
+		spec.PassEnv = []string{
+			rc.HandshakeConfig.MagicCookieKey,
+			"PLUGIN_MIN_PORT",
+			"PLUGIN_MAX_PORT",
+			"PLUGIN_PROTOCOL_VERSIONS",
+			"PLUGIN_CLIENT_CERT",
+		}
+	}
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	sandboxed := exec.Command(rc.launcher.Command, rc.launcher.Args...)
+	sandboxed.Env = append(cmd.Env, fmt.Sprintf("%s=%s", PluginSandboxEnv, specJSON))
+	return sandboxed, nil
+}
+
 func (rc runConfig) run(ctx context.Context) (*plugin.Client, error) {
 	clientConfig, err := rc.makeConfig(ctx)
 	if err != nil {
@@ -169,6 +234,10 @@ func (r *PluginRunner) RunConfig(ctx context.Context, opts ...RunOpt) (*plugin.C
 		args:    r.Args,
 		sha256:  r.Sha256,
 		env:     r.Env,
+
+		runtime:    r.Runtime,
+		launcher:   r.Launcher,
+		cgroupName: pluginCgroupName(r.Name, r.Type, r.Version),
 	}
 
 	for _, opt := range opts {
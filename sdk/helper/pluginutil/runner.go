@@ -55,6 +55,8 @@ type PluginRunner struct {
 	Sha256         []byte                      `json:"sha256" structs:"sha256"`
 	Builtin        bool                        `json:"builtin" structs:"builtin"`
 	BuiltinFactory func() (interface{}, error) `json:"-" structs:"-"`
+	Runtime        *PluginRuntimeConfig        `json:"runtime,omitempty" structs:"runtime"`
+	Launcher       *PluginLauncher             `json:"-" structs:"-"`
 }
 
 // Run takes a wrapper RunnerUtil instance along with the go-plugin parameters and
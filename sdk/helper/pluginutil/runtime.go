@@ -0,0 +1,167 @@
+package pluginutil
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/vault/sdk/helper/consts"
+)
+
+const (
+	// PluginSandboxEnv is the ENV name used to pass the sandbox configuration
+	// of a plugin to the plugin launcher.
+	PluginSandboxEnv = "VAULT_PLUGIN_SANDBOX"
+
+	// cgroupCPUPeriod is the period, in microseconds, CPU quotas are enforced
+	// over.
+	cgroupCPUPeriod = 100000
+)
+
+var (
+	// ErrSandboxUnsupported is returned when launching a plugin with a runtime
+	// configuration on a platform without support for it.
+	ErrSandboxUnsupported = errors.New("plugin runtime configuration is only supported on Linux")
+
+	cgroupNameRe = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+)
+
+// PluginRuntimeConfig holds the resource limits and the sandboxing applied to
+// the process of an external plugin.
+type PluginRuntimeConfig struct {
+	// MemoryLimit is the memory, in bytes, the processes of the plugin may use
+	// together.
+	MemoryLimit int64 `json:"memory_limit,omitempty" structs:"memory_limit"`
+
+	// CPUQuota is the CPU time the processes of the plugin may use together, as
+	// a percentage of one CPU.
+	CPUQuota int64 `json:"cpu_quota,omitempty" structs:"cpu_quota"`
+
+	// UID and GID are the user and group the plugin runs as.
+	UID int `json:"uid,omitempty" structs:"uid"`
+	GID int `json:"gid,omitempty" structs:"gid"`
+
+	// RestrictEnv prevents the plugin from inheriting the environment of
+	// Vault, so that it only gets the variables of its catalog entry and the
+	// ones needed to talk to Vault.
+	RestrictEnv bool `json:"restrict_env,omitempty" structs:"restrict_env"`
+
+	// SeccompProfile is the path of a compiled seccomp BPF program loaded
+	// before running the plugin. It is relative to the plugin directory in the
+	// catalog.
+	SeccompProfile string `json:"seccomp_profile,omitempty" structs:"seccomp_profile"`
+}
+
+// Validate returns an error if the runtime configuration is invalid.
+func (r *PluginRuntimeConfig) Validate() error {
+	if r == nil {
+		return nil
+	}
+
+	switch {
+	case r.MemoryLimit < 0:
+		return errors.New("memory limit must not be negative")
+	case r.CPUQuota < 0:
+		return errors.New("CPU quota must not be negative")
+	case r.UID < 0 || r.GID < 0:
+		return errors.New("uid and gid must not be negative")
+	case r.UID > 0 && r.GID <= 0:
+		return errors.New("gid must be set when uid is set")
+	case strings.Contains(r.SeccompProfile, ".."):
+		return consts.ErrPathContainsParentReferences
+	}
+
+	if r.Sandboxed() && !sandboxSupported {
+		return ErrSandboxUnsupported
+	}
+
+	return nil
+}
+
+// Sandboxed returns whether the plugin must be run through the plugin
+// launcher.
+func (r *PluginRuntimeConfig) Sandboxed() bool {
+	return r != nil && (r.MemoryLimit > 0 || r.CPUQuota > 0 || r.UID > 0 || r.GID > 0 || r.RestrictEnv || r.SeccompProfile != "")
+}
+
+// hasResourceLimits returns whether the plugin must run in a cgroup.
+func (r *PluginRuntimeConfig) hasResourceLimits() bool {
+	return r != nil && (r.MemoryLimit > 0 || r.CPUQuota > 0)
+}
+
+// PluginLauncher is the command used to run plugins with a runtime
+// configuration. The launcher reads a sandboxSpec from PluginSandboxEnv and
+// calls ExecSandboxed.
+type PluginLauncher struct {
+	Command string
+	Args    []string
+
+	// CgroupParent is the cgroup v2 directory under which a cgroup is created
+	// for each plugin with resource limits.
+	CgroupParent string
+}
+
+// sandboxSpec is passed by Vault to the plugin launcher.
+type sandboxSpec struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	Sha256  []byte   `json:"sha256"`
+
+	// Cgroup is the cgroup directory the launcher moves itself to.
+	Cgroup string `json:"cgroup,omitempty"`
+
+	UID            int    `json:"uid,omitempty"`
+	GID            int    `json:"gid,omitempty"`
+	SeccompProfile string `json:"seccomp_profile,omitempty"`
+
+	// RestrictEnv makes the launcher run the plugin with Env, and the
+	// variables of its own environment listed in PassEnv.
+	RestrictEnv bool     `json:"restrict_env,omitempty"`
+	Env         []string `json:"env,omitempty"`
+	PassEnv     []string `json:"pass_env,omitempty"`
+}
+
+// pluginCgroupName returns the name of the cgroup of a plugin.
+func pluginCgroupName(name string, pluginType consts.PluginType, version string) string {
+	parts := []string{pluginType.String(), name}
+	if version != "" {
+		parts = append(parts, version)
+	}
+	return cgroupNameRe.ReplaceAllString(strings.Join(parts, "-"), "_")
+}
+
+// filterEnv returns env followed by the variables of environ named in pass.
+func filterEnv(environ, env, pass []string) []string {
+	allowed := make(map[string]struct{}, len(pass))
+	for _, name := range pass {
+		allowed[name] = struct{}{}
+	}
+
+	ret := append([]string{}, env...)
+	for _, kv := range environ {
+		name := strings.SplitN(kv, "=", 2)[0]
+		if _, ok := allowed[name]; ok {
+			ret = append(ret, kv)
+		}
+	}
+	return ret
+}
+
+// removeEnv returns environ without the variable name.
+func removeEnv(environ []string, name string) []string {
+	ret := make([]string, 0, len(environ))
+	for _, kv := range environ {
+		if !strings.HasPrefix(kv, name+"=") {
+			ret = append(ret, kv)
+		}
+	}
+	return ret
+}
+
+func cgroupCPUMax(quota int64) string {
+	if quota <= 0 {
+		return fmt.Sprintf("max %d", cgroupCPUPeriod)
+	}
+	return fmt.Sprintf("%d %d", quota*cgroupCPUPeriod/100, cgroupCPUPeriod)
+}
@@ -0,0 +1,40 @@
+package pluginutil
+
+import (
+	"testing"
+
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginRuntimeConfig_Validate(t *testing.T) {
+	var nilConfig *PluginRuntimeConfig
+	require.NoError(t, nilConfig.Validate())
+	require.False(t, nilConfig.Sandboxed())
+	require.False(t, (&PluginRuntimeConfig{}).Sandboxed())
+
+	for name, config := range map[string]*PluginRuntimeConfig{
+		"negative memory limit": {MemoryLimit: -1},
+		"negative cpu quota":    {CPUQuota: -1},
+		"negative uid":          {UID: -1},
+		"uid without gid":       {UID: 1000},
+		"parent reference":      {SeccompProfile: "../profile.bpf"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Error(t, config.Validate())
+		})
+	}
+}
+
+func TestPluginCgroupName(t *testing.T) {
+	require.Equal(t, "secret-my-plugin", pluginCgroupName("my-plugin", consts.PluginTypeSecrets, ""))
+	require.Equal(t, "auth-my_plugin-v1.0.0_beta", pluginCgroupName("my/plugin", consts.PluginTypeCredential, "v1.0.0+beta"))
+}
+
+func TestFilterEnv(t *testing.T) {
+	environ := []string{"HOME=/root", "PLUGIN_MIN_PORT=10000", "VAULT_TOKEN=secret", "PLUGIN_MAX_PORT=25000"}
+	env := filterEnv(environ, []string{"FOO=bar"}, []string{"PLUGIN_MIN_PORT", "PLUGIN_MAX_PORT"})
+	require.Equal(t, []string{"FOO=bar", "PLUGIN_MIN_PORT=10000", "PLUGIN_MAX_PORT=25000"}, env)
+
+	require.Equal(t, []string{"HOME=/root"}, removeEnv([]string{"HOME=/root", PluginSandboxEnv + "={}"}, PluginSandboxEnv))
+}
@@ -0,0 +1,230 @@
+//go:build linux
+
+package pluginutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/hashicorp/vault/sdk/helper/strutil"
+)
+
+const (
+	sandboxSupported = true
+
+	prSetNoNewPrivs   = 38
+	seccompModeFilter = 2
+	bpfMaxInstrs      = 4096
+)
+
+// setupPluginCgroup creates the cgroup of a plugin under parent and applies
+// the resource limits of its runtime configuration. It returns the empty
+// string if the plugin has no resource limits.
+func setupPluginCgroup(parent, name string, r *PluginRuntimeConfig) (string, error) {
+	if !r.hasResourceLimits() {
+		return "", nil
+	}
+	if parent == "" {
+		return "", errors.New("plugin cgroup parent is not configured")
+	}
+
+	if err := enableCgroupControllers(parent, "cpu", "memory"); err != nil {
+		return "", fmt.Errorf("error enabling cgroup controllers: %w", err)
+	}
+
+	dir := filepath.Join(parent, name)
+	if err := os.Mkdir(dir, 0o755); err != nil && !os.IsExist(err) {
+		return "", fmt.Errorf("error creating plugin cgroup: %w", err)
+	}
+
+	memoryMax := "max"
+	if r.MemoryLimit > 0 {
+		memoryMax = strconv.FormatInt(r.MemoryLimit, 10)
+	}
+	if err := writeCgroupFile(dir, "memory.max", memoryMax); err != nil {
+		return "", err
+	}
+	// Don't let the plugin swap instead of hitting its memory limit
+	if r.MemoryLimit > 0 {
+		if _, err := os.Stat(filepath.Join(dir, "memory.swap.max")); err == nil {
+			if err := writeCgroupFile(dir, "memory.swap.max", "0"); err != nil {
+				return "", err
+			}
+		}
+	}
+	if err := writeCgroupFile(dir, "cpu.max", cgroupCPUMax(r.CPUQuota)); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+func enableCgroupControllers(parent string, controllers ...string) error {
+	subtreeControl := filepath.Join(parent, "cgroup.subtree_control")
+	contents, err := os.ReadFile(subtreeControl)
+	if err != nil {
+		return err
+	}
+
+	enabled := strings.Fields(string(contents))
+	var missing []string
+	for _, controller := range controllers {
+		if !strutil.StrListContains(enabled, controller) {
+			missing = append(missing, "+"+controller)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return os.WriteFile(subtreeControl, []byte(strings.Join(missing, " ")), 0o644)
+}
+
+func writeCgroupFile(dir, name, value string) error {
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(value), 0o644); err != nil {
+		return fmt.Errorf("error setting %s of plugin cgroup: %w", name, err)
+	}
+	return nil
+}
+
+// ExecSandboxed replaces the plugin launcher with the plugin described by
+// PluginSandboxEnv, after moving to the cgroup of the plugin, dropping
+// privileges and loading its seccomp profile. It only returns on error.
+func ExecSandboxed() error {
+	var spec sandboxSpec
+	if err := json.Unmarshal([]byte(os.Getenv(PluginSandboxEnv)), &spec); err != nil {
+		return fmt.Errorf("error decoding plugin sandbox configuration: %w", err)
+	}
+	if spec.Command == "" {
+		return errors.New("missing plugin command")
+	}
+
+	env := removeEnv(os.Environ(), PluginSandboxEnv)
+	if spec.RestrictEnv {
+		env = filterEnv(env, spec.Env, spec.PassEnv)
+	}
+
+	// Privileges and seccomp filters are set on the thread that execs the
+	// plugin.
+	runtime.LockOSThread()
+
+	if err := checkPluginSha256(spec.Command, spec.Sha256); err != nil {
+		return err
+	}
+
+	var filter []syscall.SockFilter
+	if spec.SeccompProfile != "" {
+		var err error
+		filter, err = readSeccompProfile(spec.SeccompProfile)
+		if err != nil {
+			return err
+		}
+	}
+
+	if spec.Cgroup != "" {
+		if err := os.WriteFile(filepath.Join(spec.Cgroup, "cgroup.procs"), []byte(strconv.Itoa(os.Getpid())), 0o644); err != nil {
+			return fmt.Errorf("error moving plugin to its cgroup: %w", err)
+		}
+	}
+
+	if err := dropPluginPrivileges(spec.UID, spec.GID); err != nil {
+		return err
+	}
+
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prSetNoNewPrivs, 1, 0); errno != 0 {
+		return fmt.Errorf("error setting no_new_privs: %w", errno)
+	}
+	if len(filter) > 0 {
+		prog := syscall.SockFprog{
+			Len:    uint16(len(filter)),
+			Filter: &filter[0],
+		}
+		if _, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, syscall.PR_SET_SECCOMP, seccompModeFilter, uintptr(unsafe.Pointer(&prog))); errno != 0 {
+			return fmt.Errorf("error loading seccomp profile: %w", errno)
+		}
+	}
+
+	return syscall.Exec(spec.Command, append([]string{spec.Command}, spec.Args...), env)
+}
+
+// The syscalls used to drop the privileges of the plugin, replaced in tests.
+var (
+	setgroups = syscall.Setgroups
+	setgid    = syscall.Setgid
+	setuid    = syscall.Setuid
+)
+
+// dropPluginPrivileges switches to the uid and gid of the plugin. The
+// supplementary groups of Vault are always dropped before the uid changes, so
+// that a plugin never keeps the groups of the user Vault runs as.
+func dropPluginPrivileges(uid, gid int) error {
+	if uid <= 0 && gid <= 0 {
+		return nil
+	}
+
+	groups := []int{}
+	if gid > 0 {
+		groups = []int{gid}
+	}
+	if err := setgroups(groups); err != nil {
+		return fmt.Errorf("error setting plugin groups: %w", err)
+	}
+	if gid > 0 {
+		if err := setgid(gid); err != nil {
+			return fmt.Errorf("error setting plugin gid: %w", err)
+		}
+	}
+	if uid > 0 {
+		if err := setuid(uid); err != nil {
+			return fmt.Errorf("error setting plugin uid: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func checkPluginSha256(command string, sum []byte) error {
+	f, err := os.Open(command)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return err
+	}
+	if !bytes.Equal(hash.Sum(nil), sum) {
+		return errors.New("checksums did not match")
+	}
+	return nil
+}
+
+// readSeccompProfile reads a seccomp profile, a BPF program in the format
+// written by libseccomp's seccomp_export_bpf.
+func readSeccompProfile(path string) ([]syscall.SockFilter, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading seccomp profile: %w", err)
+	}
+
+	size := int(unsafe.Sizeof(syscall.SockFilter{}))
+	if len(contents) == 0 || len(contents)%size != 0 || len(contents)/size > bpfMaxInstrs {
+		return nil, fmt.Errorf("invalid seccomp profile %q", path)
+	}
+
+	filter := make([]syscall.SockFilter, len(contents)/size)
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&filter[0])), len(contents)), contents)
+	return filter, nil
+}
@@ -0,0 +1,190 @@
+//go:build linux
+
+package pluginutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupPluginCgroup(t *testing.T) {
+	parent := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte("cpu io\n"), 0o644))
+
+	readFile := func(name string) string {
+		t.Helper()
+		contents, err := os.ReadFile(name)
+		require.NoError(t, err)
+		return string(contents)
+	}
+
+	// Plugins without resource limits don't get a cgroup
+	cgroup, err := setupPluginCgroup(parent, "secret-my-plugin", &PluginRuntimeConfig{UID: 1000, GID: 1000})
+	require.NoError(t, err)
+	require.Empty(t, cgroup)
+
+	cgroup, err = setupPluginCgroup(parent, "secret-my-plugin", &PluginRuntimeConfig{
+		MemoryLimit: 256 * 1024 * 1024,
+		CPUQuota:    50,
+	})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(parent, "secret-my-plugin"), cgroup)
+	require.Equal(t, "+memory", readFile(filepath.Join(parent, "cgroup.subtree_control")))
+	require.Equal(t, "268435456", readFile(filepath.Join(cgroup, "memory.max")))
+	require.Equal(t, "50000 100000", readFile(filepath.Join(cgroup, "cpu.max")))
+
+	// Existing cgroups are updated
+	require.NoError(t, os.WriteFile(filepath.Join(cgroup, "memory.swap.max"), []byte("max"), 0o644))
+	_, err = setupPluginCgroup(parent, "secret-my-plugin", &PluginRuntimeConfig{MemoryLimit: 1024})
+	require.NoError(t, err)
+	require.Equal(t, "1024", readFile(filepath.Join(cgroup, "memory.max")))
+	require.Equal(t, "0", readFile(filepath.Join(cgroup, "memory.swap.max")))
+	require.Equal(t, "max 100000", readFile(filepath.Join(cgroup, "cpu.max")))
+
+	_, err = setupPluginCgroup("", "secret-my-plugin", &PluginRuntimeConfig{MemoryLimit: 1024})
+	require.Error(t, err)
+}
+
+func TestMakeConfig_Sandboxed(t *testing.T) {
+	parent := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte("cpu memory"), 0o644))
+
+	runner := &PluginRunner{
+		Name:    "my-plugin",
+		Type:    consts.PluginTypeDatabase,
+		Version: "v1.0.0",
+		Command: "/plugins/my-plugin",
+		Args:    []string{"-flag"},
+		Env:     []string{"FOO=bar"},
+		Sha256:  []byte("some_sha256"),
+		Runtime: &PluginRuntimeConfig{
+			MemoryLimit:    1024,
+			UID:            1000,
+			GID:            1000,
+			RestrictEnv:    true,
+			SeccompProfile: "/plugins/profile.bpf",
+		},
+		Launcher: &PluginLauncher{
+			Command:      "/bin/vault",
+			Args:         []string{"plugin", "sandbox"},
+			CgroupParent: parent,
+		},
+	}
+
+	rc := runConfig{
+		command:    runner.Command,
+		args:       runner.Args,
+		sha256:     runner.Sha256,
+		env:        runner.Env,
+		runtime:    runner.Runtime,
+		launcher:   runner.Launcher,
+		cgroupName: pluginCgroupName(runner.Name, runner.Type, runner.Version),
+		PluginClientConfig: PluginClientConfig{
+			HandshakeConfig: plugin.HandshakeConfig{
+				ProtocolVersion:  1,
+				MagicCookieKey:   "magic_cookie_key",
+				MagicCookieValue: "magic_cookie_value",
+			},
+			Logger:         hclog.NewNullLogger(),
+			IsMetadataMode: true,
+			AutoMTLS:       true,
+		},
+	}
+
+	config, err := rc.makeConfig(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, config.SecureConfig)
+	require.Equal(t, []string{"/bin/vault", "plugin", "sandbox"}, config.Cmd.Args)
+
+	var specJSON string
+	for _, kv := range config.Cmd.Env {
+		if strings.HasPrefix(kv, PluginSandboxEnv+"=") {
+			specJSON = strings.TrimPrefix(kv, PluginSandboxEnv+"=")
+		}
+	}
+	var spec sandboxSpec
+	require.NoError(t, json.Unmarshal([]byte(specJSON), &spec))
+	require.Equal(t, "/plugins/my-plugin", spec.Command)
+	require.Equal(t, []string{"-flag"}, spec.Args)
+	require.Equal(t, []byte("some_sha256"), spec.Sha256)
+	require.Equal(t, filepath.Join(parent, "database-my-plugin-v1.0.0"), spec.Cgroup)
+	require.Equal(t, 1000, spec.UID)
+	require.Equal(t, 1000, spec.GID)
+	require.Equal(t, "/plugins/profile.bpf", spec.SeccompProfile)
+	require.True(t, spec.RestrictEnv)
+	require.Contains(t, spec.Env, "FOO=bar")
+	require.Contains(t, spec.PassEnv, "magic_cookie_key")
+
+	// A launcher is required
+	rc.launcher = nil
+	_, err = rc.makeConfig(context.Background())
+	require.Error(t, err)
+}
+
+func TestReadSeccompProfile(t *testing.T) {
+	dir := t.TempDir()
+
+	// RET ALLOW
+	profile := filepath.Join(dir, "allow.bpf")
+	require.NoError(t, os.WriteFile(profile, []byte{0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0x7f}, 0o644))
+	filter, err := readSeccompProfile(profile)
+	require.NoError(t, err)
+	require.Len(t, filter, 1)
+	require.Equal(t, uint16(0x06), filter[0].Code)
+	require.Equal(t, uint32(0x7fff0000), filter[0].K)
+
+	require.NoError(t, os.WriteFile(profile, []byte{0x06, 0x00}, 0o644))
+	_, err = readSeccompProfile(profile)
+	require.Error(t, err)
+}
+
+func TestDropPluginPrivileges(t *testing.T) {
+	var calls []string
+	setgroups = func(gids []int) error {
+		calls = append(calls, fmt.Sprintf("setgroups %v", gids))
+		return nil
+	}
+	setgid = func(gid int) error {
+		calls = append(calls, fmt.Sprintf("setgid %d", gid))
+		return nil
+	}
+	setuid = func(uid int) error {
+		calls = append(calls, fmt.Sprintf("setuid %d", uid))
+		return nil
+	}
+	t.Cleanup(func() {
+		setgroups = syscall.Setgroups
+		setgid = syscall.Setgid
+		setuid = syscall.Setuid
+	})
+
+	require.NoError(t, dropPluginPrivileges(0, 0))
+	require.Empty(t, calls)
+
+	require.NoError(t, dropPluginPrivileges(1000, 1001))
+	require.Equal(t, []string{"setgroups [1001]", "setgid 1001", "setuid 1000"}, calls)
+
+	// The supplementary groups are dropped even without a gid
+	calls = nil
+	require.NoError(t, dropPluginPrivileges(1000, 0))
+	require.Equal(t, []string{"setgroups []", "setuid 1000"}, calls)
+
+	// The uid is not changed if the groups can't be dropped
+	calls = nil
+	setgroups = func([]int) error {
+		return syscall.EPERM
+	}
+	require.Error(t, dropPluginPrivileges(1000, 0))
+	require.Empty(t, calls)
+}
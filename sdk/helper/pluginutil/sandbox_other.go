@@ -0,0 +1,14 @@
+//go:build !linux
+
+package pluginutil
+
+const sandboxSupported = false
+
+func setupPluginCgroup(string, string, *PluginRuntimeConfig) (string, error) {
+	return "", ErrSandboxUnsupported
+}
+
+// ExecSandboxed is only supported on Linux.
+func ExecSandboxed() error {
+	return ErrSandboxUnsupported
+}
@@ -34,4 +34,8 @@ type ResponseWrapInfo struct {
 
 	// Controls seal wrapping behavior downstream for specific use cases
 	SealWrap bool `json:"seal_wrap" structs:"seal_wrap" mapstructure:"seal_wrap" sentinel:""`
+
+	// BoundCIDRs restricts the addresses the response can be unwrapped from.
+	// This doesn't get returned, it's only internal.
+	BoundCIDRs []string `json:"bound_cidrs,omitempty" structs:"bound_cidrs" mapstructure:"bound_cidrs" sentinel:""`
 }
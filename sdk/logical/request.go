@@ -24,6 +24,21 @@ type RequestWrapInfo struct {
 	// A flag to conforming backends that data for a given request should be
 	// seal wrapped
 	SealWrap bool `json:"seal_wrap" structs:"seal_wrap" mapstructure:"seal_wrap" sentinel:""`
+
+	// The number of times the wrapped response can be unwrapped before the
+	// wrapping token is revoked; if not specified it can be unwrapped once
+	Uses int `json:"uses" structs:"uses" mapstructure:"uses" sentinel:""`
+
+	// If set, the wrapped response can only be unwrapped by a token of the
+	// given entity
+	BoundEntityID string `json:"bound_entity_id" structs:"bound_entity_id" mapstructure:"bound_entity_id" sentinel:""`
+
+	// If set, the wrapped response can only be unwrapped by a token issued by
+	// a login against the given AppRole role
+	BoundAppRoleRole string `json:"bound_approle_role" structs:"bound_approle_role" mapstructure:"bound_approle_role" sentinel:""`
+
+	// If set, the wrapped response can only be unwrapped from the given CIDRs
+	BoundCIDRs []string `json:"bound_cidrs" structs:"bound_cidrs" mapstructure:"bound_cidrs" sentinel:""`
 }
 
 func (r *RequestWrapInfo) SentinelGet(key string) (interface{}, error) {
@@ -98,8 +98,21 @@ type ExtendedSystemView interface {
 	ForwardGenericRequest(context.Context, *Request) (*Response, error)
 }
 
+// PasswordPolicyValidator is implemented by system views that can check a
+// password against a password policy. It is kept out of SystemView so that
+// existing implementations of SystemView keep satisfying it; backends should
+// check for it with a type assertion.
+type PasswordPolicyValidator interface {
+	// ValidatePasswordAgainstPolicy checks the password against the rules of
+	// the policy referenced. It returns an error describing the first rule the
+	// password does not satisfy, or if the policy does not exist.
+	ValidatePasswordAgainstPolicy(ctx context.Context, policyName string, password string) error
+}
+
 type PasswordGenerator func() (password string, err error)
 
+type PasswordValidator func(password string) error
+
 type StaticSystemView struct {
 	DefaultLeaseTTLVal  time.Duration
 	MaxLeaseTTLVal      time.Duration
@@ -116,6 +129,7 @@ type StaticSystemView struct {
 	VaultVersion        string
 	PluginEnvironment   *PluginEnvironment
 	PasswordPolicies    map[string]PasswordGenerator
+	PasswordValidators  map[string]PasswordValidator
 }
 
 type noopAuditor struct{}
@@ -228,6 +242,30 @@ func (d *StaticSystemView) SetPasswordPolicy(name string, generator PasswordGene
 	d.PasswordPolicies[name] = generator
 }
 
+func (d StaticSystemView) ValidatePasswordAgainstPolicy(ctx context.Context, policyName string, password string) error {
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("context timed out")
+	default:
+	}
+
+	if d.PasswordValidators == nil {
+		return fmt.Errorf("password policy not found")
+	}
+	validator, exists := d.PasswordValidators[policyName]
+	if !exists {
+		return fmt.Errorf("password policy not found")
+	}
+	return validator(password)
+}
+
+func (d *StaticSystemView) SetPasswordValidator(name string, validator PasswordValidator) {
+	if d.PasswordValidators == nil {
+		d.PasswordValidators = map[string]PasswordValidator{}
+	}
+	d.PasswordValidators[name] = validator
+}
+
 func (d *StaticSystemView) DeletePasswordPolicy(name string) (existed bool) {
 	_, existed = d.PasswordPolicies[name]
 	delete(d.PasswordPolicies, name)
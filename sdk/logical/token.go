@@ -109,6 +109,10 @@ type TokenEntry struct {
 	// InlinePolicy specifies ACL rules to be applied to this token entry.
 	InlinePolicy string `json:"inline_policy" mapstructure:"inline_policy" structs:"inline_policy"`
 
+	// PathRestrictions limits the token to the given capabilities on the
+	// given request paths, on top of what its policies allow.
+	PathRestrictions map[string][]string `json:"path_restrictions" mapstructure:"path_restrictions" structs:"path_restrictions"`
+
 	// Used for audit trails, this is something like "auth/user/login"
 	Path string `json:"path" mapstructure:"path" structs:"path"`
 
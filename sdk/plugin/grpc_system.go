@@ -186,6 +186,21 @@ func (s *gRPCSystemViewClient) GeneratePasswordFromPolicy(ctx context.Context, p
 	return resp.Password, nil
 }
 
+func (s *gRPCSystemViewClient) ValidatePasswordAgainstPolicy(ctx context.Context, policyName string, password string) error {
+	req := &pb.ValidatePasswordAgainstPolicyRequest{
+		PolicyName: policyName,
+		Password:   password,
+	}
+	resp, err := s.client.ValidatePasswordAgainstPolicy(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.Err != "" {
+		return errors.New(resp.Err)
+	}
+	return nil
+}
+
 type gRPCSystemViewServer struct {
 	pb.UnimplementedSystemViewServer
 
@@ -318,3 +333,19 @@ func (s *gRPCSystemViewServer) GeneratePasswordFromPolicy(ctx context.Context, r
 	}
 	return resp, nil
 }
+
+func (s *gRPCSystemViewServer) ValidatePasswordAgainstPolicy(ctx context.Context, req *pb.ValidatePasswordAgainstPolicyRequest) (*pb.ValidatePasswordAgainstPolicyReply, error) {
+	if req.PolicyName == "" {
+		return &pb.ValidatePasswordAgainstPolicyReply{}, status.Errorf(codes.InvalidArgument, "no password policy specified")
+	}
+
+	validator, ok := s.impl.(logical.PasswordPolicyValidator)
+	if !ok {
+		return &pb.ValidatePasswordAgainstPolicyReply{}, status.Errorf(codes.Unimplemented, "password policy validation is not supported")
+	}
+
+	err := validator.ValidatePasswordAgainstPolicy(ctx, req.PolicyName, req.Password)
+	return &pb.ValidatePasswordAgainstPolicyReply{
+		Err: pb.ErrToString(err),
+	}, nil
+}
@@ -2,6 +2,7 @@ package plugin
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -271,3 +272,43 @@ func TestSystem_GRPC_GeneratePasswordFromPolicy(t *testing.T) {
 		t.Fatalf("Actual password: %s\nExpected password: %s", password, expectedPassword)
 	}
 }
+
+func TestSystem_GRPC_ValidatePasswordAgainstPolicy(t *testing.T) {
+	policyName := "testpolicy"
+	sys := &logical.StaticSystemView{
+		PasswordValidators: map[string]logical.PasswordValidator{
+			policyName: func(password string) error {
+				if len(password) < 8 {
+					return fmt.Errorf("password is too short")
+				}
+				return nil
+			},
+		},
+	}
+
+	client, server := plugin.TestGRPCConn(t, func(s *grpc.Server) {
+		pb.RegisterSystemViewServer(s, &gRPCSystemViewServer{
+			impl: sys,
+		})
+	})
+	defer server.Stop()
+	defer client.Close()
+
+	testSystemView := newGRPCSystemView(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := testSystemView.ValidatePasswordAgainstPolicy(ctx, policyName, "longenough"); err != nil {
+		t.Fatalf("no error expected, got: %s", err)
+	}
+
+	err := testSystemView.ValidatePasswordAgainstPolicy(ctx, policyName, "short")
+	if err == nil || err.Error() != "password is too short" {
+		t.Fatalf("expected validation error, got: %v", err)
+	}
+
+	if err := testSystemView.ValidatePasswordAgainstPolicy(ctx, "unknown", "longenough"); err == nil {
+		t.Fatalf("err expected, got nil")
+	}
+}
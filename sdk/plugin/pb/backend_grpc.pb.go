@@ -642,6 +642,9 @@ type SystemViewClient interface {
 	GroupsForEntity(ctx context.Context, in *EntityInfoArgs, opts ...grpc.CallOption) (*GroupsForEntityReply, error)
 	// GeneratePasswordFromPolicy generates a password from an existing password policy
 	GeneratePasswordFromPolicy(ctx context.Context, in *GeneratePasswordFromPolicyRequest, opts ...grpc.CallOption) (*GeneratePasswordFromPolicyReply, error)
+	// ValidatePasswordAgainstPolicy checks a password against the rules of an
+	// existing password policy
+	ValidatePasswordAgainstPolicy(ctx context.Context, in *ValidatePasswordAgainstPolicyRequest, opts ...grpc.CallOption) (*ValidatePasswordAgainstPolicyReply, error)
 }
 
 type systemViewClient struct {
@@ -760,6 +763,15 @@ func (c *systemViewClient) GeneratePasswordFromPolicy(ctx context.Context, in *G
 	return out, nil
 }
 
+func (c *systemViewClient) ValidatePasswordAgainstPolicy(ctx context.Context, in *ValidatePasswordAgainstPolicyRequest, opts ...grpc.CallOption) (*ValidatePasswordAgainstPolicyReply, error) {
+	out := new(ValidatePasswordAgainstPolicyReply)
+	err := c.cc.Invoke(ctx, "/pb.SystemView/ValidatePasswordAgainstPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SystemViewServer is the server API for SystemView service.
 // All implementations must embed UnimplementedSystemViewServer
 // for forward compatibility
@@ -802,6 +814,9 @@ type SystemViewServer interface {
 	GroupsForEntity(context.Context, *EntityInfoArgs) (*GroupsForEntityReply, error)
 	// GeneratePasswordFromPolicy generates a password from an existing password policy
 	GeneratePasswordFromPolicy(context.Context, *GeneratePasswordFromPolicyRequest) (*GeneratePasswordFromPolicyReply, error)
+	// ValidatePasswordAgainstPolicy checks a password against the rules of an
+	// existing password policy
+	ValidatePasswordAgainstPolicy(context.Context, *ValidatePasswordAgainstPolicyRequest) (*ValidatePasswordAgainstPolicyReply, error)
 	mustEmbedUnimplementedSystemViewServer()
 }
 
@@ -845,6 +860,9 @@ func (UnimplementedSystemViewServer) GroupsForEntity(context.Context, *EntityInf
 func (UnimplementedSystemViewServer) GeneratePasswordFromPolicy(context.Context, *GeneratePasswordFromPolicyRequest) (*GeneratePasswordFromPolicyReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GeneratePasswordFromPolicy not implemented")
 }
+func (UnimplementedSystemViewServer) ValidatePasswordAgainstPolicy(context.Context, *ValidatePasswordAgainstPolicyRequest) (*ValidatePasswordAgainstPolicyReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidatePasswordAgainstPolicy not implemented")
+}
 func (UnimplementedSystemViewServer) mustEmbedUnimplementedSystemViewServer() {}
 
 // UnsafeSystemViewServer may be embedded to opt out of forward compatibility for this service.
@@ -1074,6 +1092,24 @@ func _SystemView_GeneratePasswordFromPolicy_Handler(srv interface{}, ctx context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SystemView_ValidatePasswordAgainstPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidatePasswordAgainstPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemViewServer).ValidatePasswordAgainstPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.SystemView/ValidatePasswordAgainstPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemViewServer).ValidatePasswordAgainstPolicy(ctx, req.(*ValidatePasswordAgainstPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SystemView_ServiceDesc is the grpc.ServiceDesc for SystemView service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1129,6 +1165,10 @@ var SystemView_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GeneratePasswordFromPolicy",
 			Handler:    _SystemView_GeneratePasswordFromPolicy_Handler,
 		},
+		{
+			MethodName: "ValidatePasswordAgainstPolicy",
+			Handler:    _SystemView_ValidatePasswordAgainstPolicy_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "sdk/plugin/pb/backend.proto",
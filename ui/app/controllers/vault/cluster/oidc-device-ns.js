@@ -0,0 +1,4 @@
+import VaultClusterOidcDeviceController from './oidc-device';
+
+// Use same params as the base oidc-device route
+export default class VaultClusterOidcDeviceNsController extends VaultClusterOidcDeviceController {}
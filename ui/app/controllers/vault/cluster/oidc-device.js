@@ -0,0 +1,55 @@
+import Controller from '@ember/controller';
+import { inject as service } from '@ember/service';
+import { action } from '@ember/object';
+import { tracked } from '@glimmer/tracking';
+
+export default class VaultClusterOidcDeviceController extends Controller {
+  @service store;
+
+  queryParams = ['user_code'];
+  user_code = null;
+
+  @tracked userCodeInput = '';
+  @tracked isSaving = false;
+  @tracked result = null;
+  @tracked errorMessage = null;
+
+  @action
+  lookup(evt) {
+    evt.preventDefault();
+    this.result = null;
+    this.errorMessage = null;
+    this.set('user_code', this.userCodeInput.trim());
+  }
+
+  @action
+  approve(evt) {
+    evt.preventDefault();
+    return this.verify(false);
+  }
+
+  @action
+  deny(evt) {
+    evt.preventDefault();
+    return this.verify(true);
+  }
+
+  async verify(deny) {
+    const { provider_name, namespace, user_code } = this.model;
+    this.isSaving = true;
+    this.errorMessage = null;
+    try {
+      await this.store
+        .adapterFor('application')
+        .ajax(`/v1/identity/oidc/provider/${provider_name}/device`, 'POST', {
+          namespace,
+          data: { user_code, deny },
+        });
+      this.result = deny ? 'denied' : 'approved';
+    } catch (e) {
+      this.errorMessage = e.errors?.join(' ') || 'The device could not be verified.';
+    } finally {
+      this.isSaving = false;
+    }
+  }
+}
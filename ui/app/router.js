@@ -11,6 +11,8 @@ Router.map(function () {
     this.route('cluster', { path: '/:cluster_name' }, function () {
       this.route('oidc-provider-ns', { path: '/*namespace/identity/oidc/provider/:provider_name/authorize' });
       this.route('oidc-provider', { path: '/identity/oidc/provider/:provider_name/authorize' });
+      this.route('oidc-device-ns', { path: '/*namespace/identity/oidc/provider/:provider_name/device' });
+      this.route('oidc-device', { path: '/identity/oidc/provider/:provider_name/device' });
       this.route('oidc-callback', { path: '/auth/*auth_path/oidc/callback' });
       this.route('auth');
       this.route('redirect');
@@ -0,0 +1,6 @@
+import VaultClusterOidcDeviceRoute from './oidc-device';
+
+export default class VaultClusterOidcDeviceNsRoute extends VaultClusterOidcDeviceRoute {
+  // Render the same template as the base oidc-device route
+  templateName = 'vault/cluster/oidc-device';
+}
@@ -0,0 +1,70 @@
+import Route from '@ember/routing/route';
+import { inject as service } from '@ember/service';
+
+const AUTH = 'vault.cluster.auth';
+const DEVICE = 'vault.cluster.oidc-device';
+const NS_DEVICE = 'vault.cluster.oidc-device-ns';
+
+export default class VaultClusterOidcDeviceRoute extends Route {
+  @service auth;
+  @service router;
+  @service store;
+
+  queryParams = {
+    user_code: {
+      refreshModel: true,
+    },
+  };
+
+  beforeModel(transition) {
+    const qp = transition.to.queryParams;
+    // remove redirect_to if carried over from auth
+    qp.redirect_to = null;
+    if (!this.auth.get('currentTokenName')) {
+      return this._redirectToAuth({ ...transition.to.params, qp });
+    }
+  }
+
+  _redirectToAuth({ provider_name, namespace = null, qp, logout = false }) {
+    const { cluster_name } = this.paramsFor('vault.cluster');
+    let url = namespace
+      ? this.router.urlFor(NS_DEVICE, cluster_name, namespace, provider_name, { queryParams: qp })
+      : this.router.urlFor(DEVICE, cluster_name, provider_name, { queryParams: qp });
+    // transitionTo (as used in auth-form) expects the url without the rootURL
+    url = url.replace(/^(\/?ui)/, '');
+    if (logout) {
+      this.auth.deleteCurrentToken();
+    }
+    // o param can be anything, as long as it's present the auth page will change
+    const queryParams = {
+      redirect_to: url,
+      o: provider_name,
+    };
+    if (namespace) {
+      queryParams.namespace = namespace;
+    }
+    return this.transitionTo(AUTH, cluster_name, { queryParams });
+  }
+
+  async model(params) {
+    const { provider_name, namespace, user_code } = params;
+    const model = { provider_name, namespace, user_code };
+    if (!user_code) {
+      return model;
+    }
+    try {
+      const response = await this.store
+        .adapterFor('application')
+        .ajax(`/v1/identity/oidc/provider/${provider_name}/device`, 'GET', {
+          namespace,
+          data: { user_code },
+        });
+      return { ...model, request: response.data };
+    } catch (e) {
+      if (e.errors?.includes('permission denied')) {
+        return this._redirectToAuth({ provider_name, namespace, qp: { user_code }, logout: true });
+      }
+      return { ...model, error: e.errors?.join(' ') || 'The user code is invalid or has expired.' };
+    }
+  }
+}
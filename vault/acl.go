@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/armon/go-radix"
 	"github.com/hashicorp/go-multierror"
@@ -32,6 +33,11 @@ type ACL struct {
 
 	// Stores policies that are actually RGPs for later fetching
 	rgpPolicies []*Policy
+
+	// restrictions, if set, further limits the operations allowed by the
+	// policies to the ones it also allows, e.g. the path restrictions of a
+	// token
+	restrictions *ACL
 }
 
 type PolicyCheckOpts struct {
@@ -56,8 +62,41 @@ type ACLResults struct {
 	ControlGroup       *ControlGroup
 	CapabilitiesBitmap uint32
 	GrantingPolicies   []logical.PolicyInfo
+
+	// MatchedPath is the policy path rule that matched the request path, if
+	// any. Prefix rules are suffixed with a glob.
+	MatchedPath string
+
+	// DenialReason describes why the operation was not allowed. It is empty
+	// if the operation was allowed.
+	DenialReason ACLDenialReason
+
+	// DeniedParameter is the request parameter that caused the operation to be
+	// denied, if the denial was due to a parameter constraint.
+	DeniedParameter string
+
+	// UnmetConditionalCapabilitiesBitmap holds the capabilities granted by
+	// path rules whose conditions were not met by the request.
+	UnmetConditionalCapabilitiesBitmap uint32
 }
 
+// ACLDenialReason describes why an ACL check did not allow an operation
+type ACLDenialReason string
+
+const (
+	ACLDenialNoMatchingPath      ACLDenialReason = "no policy path rule matches the request path"
+	ACLDenialExplicitDeny        ACLDenialReason = "the matching path rule explicitly denies access"
+	ACLDenialMissingCapability   ACLDenialReason = "the matching path rule does not grant the capability required by the operation"
+	ACLDenialUnsupportedOp       ACLDenialReason = "the operation is not subject to policy capabilities"
+	ACLDenialWrappingTTL         ACLDenialReason = "the response wrapping TTL does not satisfy the matching path rule"
+	ACLDenialRequiredParameter   ACLDenialReason = "a parameter required by the matching path rule is missing"
+	ACLDenialDeniedParameter     ACLDenialReason = "a parameter is denied by the matching path rule"
+	ACLDenialParameterNotAllowed ACLDenialReason = "a parameter is not allowed by the matching path rule"
+	ACLDenialSudoRequired        ACLDenialReason = "the path requires the sudo capability"
+	ACLDenialConditionsNotMet    ACLDenialReason = "the request does not meet the conditions of the matching path rule"
+	ACLDenialPathRestriction     ACLDenialReason = "the path restrictions of the token do not allow the operation"
+)
+
 type SentinelResults struct {
 	GrantingPolicies []logical.PolicyInfo
 }
@@ -133,6 +172,19 @@ func NewACL(ctx context.Context, policies []*Policy) (*ACL, error) {
 					return nil, fmt.Errorf("error cloning ACL permissions: %w", err)
 				}
 
+				// Keep the capabilities of a rule with conditions apart so
+				// that they are only granted when the conditions are met
+				if clonedPerms.Conditions != nil {
+					clonedPerms.ConditionalGrants = []*ConditionalGrant{newConditionalGrant(policy, clonedPerms)}
+					clonedPerms.CapabilitiesBitmap = 0
+					clonedPerms.Conditions = nil
+					clonedPerms.MinWrappingTTL = 0
+					clonedPerms.MaxWrappingTTL = 0
+					clonedPerms.AllowedParameters = nil
+					clonedPerms.DeniedParameters = nil
+					clonedPerms.RequiredParameters = nil
+				}
+
 				// Store this policy name as the policy that permits these
 				// capabilities
 				clonedPerms.GrantingPoliciesMap = addGrantingPoliciesToMap(nil, policy, clonedPerms.CapabilitiesBitmap)
@@ -159,8 +211,12 @@ func NewACL(ctx context.Context, policies []*Policy) (*ACL, error) {
 				existingPerms.CapabilitiesBitmap = DenyCapabilityInt
 				existingPerms.AllowedParameters = nil
 				existingPerms.DeniedParameters = nil
+				existingPerms.ConditionalGrants = nil
 				goto INSERT
 
+			case pc.Permissions.Conditions != nil:
+				existingPerms.ConditionalGrants = append(existingPerms.ConditionalGrants, newConditionalGrant(policy, pc.Permissions))
+
 			default:
 				// Insert the capabilities in this new policy into the existing
 				// value
@@ -168,81 +224,11 @@ func NewACL(ctx context.Context, policies []*Policy) (*ACL, error) {
 				existingPerms.GrantingPoliciesMap = addGrantingPoliciesToMap(existingPerms.GrantingPoliciesMap, policy, pc.Permissions.CapabilitiesBitmap)
 			}
 
-			// Note: In these stanzas, we're preferring minimum lifetimes. So
-			// we take the lesser of two specified max values, or we take the
-			// lesser of two specified min values, the idea being, allowing
-			// token lifetime to be minimum possible.
-			//
-			// If we have an existing max, and we either don't have a current
-			// max, or the current is greater than the previous, use the
-			// existing.
-			if pc.Permissions.MaxWrappingTTL > 0 &&
-				(existingPerms.MaxWrappingTTL == 0 ||
-					pc.Permissions.MaxWrappingTTL < existingPerms.MaxWrappingTTL) {
-				existingPerms.MaxWrappingTTL = pc.Permissions.MaxWrappingTTL
-			}
-			// If we have an existing min, and we either don't have a current
-			// min, or the current is greater than the previous, use the
-			// existing
-			if pc.Permissions.MinWrappingTTL > 0 &&
-				(existingPerms.MinWrappingTTL == 0 ||
-					pc.Permissions.MinWrappingTTL < existingPerms.MinWrappingTTL) {
-				existingPerms.MinWrappingTTL = pc.Permissions.MinWrappingTTL
-			}
-
-			if len(pc.Permissions.AllowedParameters) > 0 {
-				if existingPerms.AllowedParameters == nil {
-					clonedAllowed, err := copystructure.Copy(pc.Permissions.AllowedParameters)
-					if err != nil {
-						return nil, err
-					}
-					existingPerms.AllowedParameters = clonedAllowed.(map[string][]interface{})
-				} else {
-					for key, value := range pc.Permissions.AllowedParameters {
-						pcValue, ok := existingPerms.AllowedParameters[key]
-						// If an empty array exist it should overwrite any other
-						// value.
-						if len(value) == 0 || (ok && len(pcValue) == 0) {
-							existingPerms.AllowedParameters[key] = []interface{}{}
-						} else {
-							// Merge the two maps, appending values on key conflict.
-							existingPerms.AllowedParameters[key] = append(value, existingPerms.AllowedParameters[key]...)
-						}
-					}
-				}
-			}
-
-			if len(pc.Permissions.DeniedParameters) > 0 {
-				if existingPerms.DeniedParameters == nil {
-					clonedDenied, err := copystructure.Copy(pc.Permissions.DeniedParameters)
-					if err != nil {
-						return nil, err
-					}
-					existingPerms.DeniedParameters = clonedDenied.(map[string][]interface{})
-				} else {
-					for key, value := range pc.Permissions.DeniedParameters {
-						pcValue, ok := existingPerms.DeniedParameters[key]
-						// If an empty array exist it should overwrite any other
-						// value.
-						if len(value) == 0 || (ok && len(pcValue) == 0) {
-							existingPerms.DeniedParameters[key] = []interface{}{}
-						} else {
-							// Merge the two maps, appending values on key conflict.
-							existingPerms.DeniedParameters[key] = append(value, existingPerms.DeniedParameters[key]...)
-						}
-					}
-				}
-			}
-
-			if len(pc.Permissions.RequiredParameters) > 0 {
-				if len(existingPerms.RequiredParameters) == 0 {
-					existingPerms.RequiredParameters = pc.Permissions.RequiredParameters
-				} else {
-					for _, v := range pc.Permissions.RequiredParameters {
-						if !strutil.StrListContains(existingPerms.RequiredParameters, v) {
-							existingPerms.RequiredParameters = append(existingPerms.RequiredParameters, v)
-						}
-					}
+			// The constraints of a rule with conditions are kept with its
+			// grant, so that they don't apply when the conditions are not met
+			if pc.Permissions.Conditions == nil {
+				if err := existingPerms.mergeConstraints(pc.Permissions); err != nil {
+					return nil, err
 				}
 			}
 
@@ -283,6 +269,119 @@ func NewACL(ctx context.Context, policies []*Policy) (*ACL, error) {
 	return a, nil
 }
 
+// mergeConstraints merges the wrapping TTL and parameter constraints of the
+// other permissions into p
+func (p *ACLPermissions) mergeConstraints(other *ACLPermissions) error {
+	// Note: In these stanzas, we're preferring minimum lifetimes. So
+	// we take the lesser of two specified max values, or we take the
+	// lesser of two specified min values, the idea being, allowing
+	// token lifetime to be minimum possible.
+	//
+	// If we have an existing max, and we either don't have a current
+	// max, or the current is greater than the previous, use the
+	// existing.
+	if other.MaxWrappingTTL > 0 &&
+		(p.MaxWrappingTTL == 0 ||
+			other.MaxWrappingTTL < p.MaxWrappingTTL) {
+		p.MaxWrappingTTL = other.MaxWrappingTTL
+	}
+	// If we have an existing min, and we either don't have a current
+	// min, or the current is greater than the previous, use the
+	// existing
+	if other.MinWrappingTTL > 0 &&
+		(p.MinWrappingTTL == 0 ||
+			other.MinWrappingTTL < p.MinWrappingTTL) {
+		p.MinWrappingTTL = other.MinWrappingTTL
+	}
+
+	if len(other.AllowedParameters) > 0 {
+		if p.AllowedParameters == nil {
+			clonedAllowed, err := copystructure.Copy(other.AllowedParameters)
+			if err != nil {
+				return err
+			}
+			p.AllowedParameters = clonedAllowed.(map[string][]interface{})
+		} else {
+			for key, value := range other.AllowedParameters {
+				pcValue, ok := p.AllowedParameters[key]
+				// If an empty array exist it should overwrite any other
+				// value.
+				if len(value) == 0 || (ok && len(pcValue) == 0) {
+					p.AllowedParameters[key] = []interface{}{}
+				} else {
+					// Merge the two maps, appending values on key conflict.
+					// Copy the values so that other is never modified.
+					p.AllowedParameters[key] = append(append([]interface{}{}, value...), p.AllowedParameters[key]...)
+				}
+			}
+		}
+	}
+
+	if len(other.DeniedParameters) > 0 {
+		if p.DeniedParameters == nil {
+			clonedDenied, err := copystructure.Copy(other.DeniedParameters)
+			if err != nil {
+				return err
+			}
+			p.DeniedParameters = clonedDenied.(map[string][]interface{})
+		} else {
+			for key, value := range other.DeniedParameters {
+				pcValue, ok := p.DeniedParameters[key]
+				// If an empty array exist it should overwrite any other
+				// value.
+				if len(value) == 0 || (ok && len(pcValue) == 0) {
+					p.DeniedParameters[key] = []interface{}{}
+				} else {
+					// Merge the two maps, appending values on key conflict.
+					// Copy the values so that other is never modified.
+					p.DeniedParameters[key] = append(append([]interface{}{}, value...), p.DeniedParameters[key]...)
+				}
+			}
+		}
+	}
+
+	if len(other.RequiredParameters) > 0 {
+		if len(p.RequiredParameters) == 0 {
+			p.RequiredParameters = append([]string(nil), other.RequiredParameters...)
+		} else {
+			// Copy the values, as the slice may be shared with a clone
+			required := append([]string(nil), p.RequiredParameters...)
+			for _, v := range other.RequiredParameters {
+				if !strutil.StrListContains(required, v) {
+					required = append(required, v)
+				}
+			}
+			p.RequiredParameters = required
+		}
+	}
+
+	return nil
+}
+
+// newConditionalGrant returns the grant of the capabilities of a path rule with
+// conditions by the given policy
+func newConditionalGrant(policy *Policy, perms *ACLPermissions) *ConditionalGrant {
+	return &ConditionalGrant{
+		CapabilitiesBitmap: perms.CapabilitiesBitmap,
+		Conditions:         perms.Conditions,
+		Constraints: &ACLPermissions{
+			MinWrappingTTL:     perms.MinWrappingTTL,
+			MaxWrappingTTL:     perms.MaxWrappingTTL,
+			AllowedParameters:  perms.AllowedParameters,
+			DeniedParameters:   perms.DeniedParameters,
+			RequiredParameters: perms.RequiredParameters,
+		},
+		Policy: logical.PolicyInfo{
+			Name:        policy.Name,
+			NamespaceId: policy.namespace.ID,
+			Type:        "acl",
+		},
+	}
+}
+
+// Capabilities returns the capabilities granted on the given path. This
+// includes capabilities granted subject to conditions, whether or not they are
+// currently met.
 func (a *ACL) Capabilities(ctx context.Context, path string) (pathCapabilities []string) {
 	req := &logical.Request{
 		Path: path,
@@ -296,7 +395,7 @@ func (a *ACL) Capabilities(ctx context.Context, path string) (pathCapabilities [
 		return []string{RootCapability}
 	}
 
-	capabilities := res.CapabilitiesBitmap
+	capabilities := res.CapabilitiesBitmap | res.UnmetConditionalCapabilitiesBitmap
 
 	if capabilities&SudoCapabilityInt > 0 {
 		pathCapabilities = append(pathCapabilities, SudoCapability)
@@ -329,7 +428,34 @@ func (a *ACL) Capabilities(ctx context.Context, path string) (pathCapabilities [
 }
 
 // AllowOperation is used to check if the given operation is permitted.
-func (a *ACL) AllowOperation(ctx context.Context, req *logical.Request, capCheckOnly bool) (ret *ACLResults) {
+func (a *ACL) AllowOperation(ctx context.Context, req *logical.Request, capCheckOnly bool) *ACLResults {
+	ret := a.allowOperation(ctx, req, capCheckOnly)
+	if a.restrictions == nil || req.Operation == logical.HelpOperation {
+		return ret
+	}
+
+	return ret.restrict(a.restrictions.allowOperation(ctx, req, capCheckOnly))
+}
+
+// restrict returns the intersection of the results with the results of the
+// restrictions of the ACL: the operation is only allowed, and capabilities
+// only granted, if both allow them.
+func (r *ACLResults) restrict(restrictions *ACLResults) *ACLResults {
+	granted := r.CapabilitiesBitmap | r.UnmetConditionalCapabilitiesBitmap
+	restricted := restrictions.CapabilitiesBitmap | restrictions.UnmetConditionalCapabilitiesBitmap
+	capabilities := r.CapabilitiesBitmap & restrictions.CapabilitiesBitmap
+	r.UnmetConditionalCapabilitiesBitmap = (granted & restricted) &^ capabilities
+	r.CapabilitiesBitmap = capabilities | (r.CapabilitiesBitmap|restrictions.CapabilitiesBitmap)&DenyCapabilityInt
+	r.RootPrivs = r.RootPrivs && restrictions.RootPrivs
+
+	if r.Allowed && !restrictions.Allowed {
+		r.Allowed = false
+		r.DenialReason = ACLDenialPathRestriction
+	}
+	return r
+}
+
+func (a *ACL) allowOperation(ctx context.Context, req *logical.Request, capCheckOnly bool) (ret *ACLResults) {
 	ret = new(ACLResults)
 
 	// Fast-path root
@@ -376,6 +502,7 @@ func (a *ACL) AllowOperation(ctx context.Context, req *logical.Request, capCheck
 	if ok {
 		permissions = raw.(*ACLPermissions)
 		capabilities = permissions.CapabilitiesBitmap
+		ret.MatchedPath = path
 		goto CHECK
 	}
 	if op == logical.ListOperation {
@@ -383,11 +510,12 @@ func (a *ACL) AllowOperation(ctx context.Context, req *logical.Request, capCheck
 		if ok {
 			permissions = raw.(*ACLPermissions)
 			capabilities = permissions.CapabilitiesBitmap
+			ret.MatchedPath = strings.TrimSuffix(path, "/")
 			goto CHECK
 		}
 	}
 
-	permissions = a.CheckAllowedFromNonExactPaths(path, false)
+	ret.MatchedPath, permissions = a.checkAllowedFromNonExactPaths(path, false)
 	if permissions != nil {
 		capabilities = permissions.CapabilitiesBitmap
 		goto CHECK
@@ -395,9 +523,25 @@ func (a *ACL) AllowOperation(ctx context.Context, req *logical.Request, capCheck
 
 	// No exact, prefix, or segment wildcard paths found, return without
 	// setting allowed
+	ret.DenialReason = ACLDenialNoMatchingPath
 	return
 
 CHECK:
+	// Add the capabilities of the path rules whose conditions are met by the
+	// request
+	var metConditionalGrants []*ConditionalGrant
+	if len(permissions.ConditionalGrants) > 0 {
+		now := time.Now()
+		for _, grant := range permissions.ConditionalGrants {
+			if grant.Conditions.satisfied(now, req) {
+				capabilities |= grant.CapabilitiesBitmap
+				metConditionalGrants = append(metConditionalGrants, grant)
+			} else {
+				ret.UnmetConditionalCapabilitiesBitmap |= grant.CapabilitiesBitmap
+			}
+		}
+	}
+
 	// Check if the minimum permissions are met
 	// If "deny" has been explicitly set, only deny will be in the map, so we
 	// only need to check for the existence of other values
@@ -410,54 +554,79 @@ CHECK:
 		return ret
 	}
 
+	// Apply the constraints of the path rules whose conditions are met on top
+	// of the ones of the path rules without conditions
+	if len(metConditionalGrants) > 0 {
+		permissions, err = permissions.Clone()
+		if err != nil {
+			return
+		}
+		for _, grant := range metConditionalGrants {
+			if err := permissions.mergeConstraints(grant.Constraints); err != nil {
+				return
+			}
+		}
+	}
+
 	ret.MFAMethods = permissions.MFAMethods
 	ret.ControlGroup = permissions.ControlGroup
 
-	var grantingPolicies []logical.PolicyInfo
-	operationAllowed := false
+	var requiredCapability uint32
 	switch op {
 	case logical.ReadOperation:
-		operationAllowed = capabilities&ReadCapabilityInt > 0
-		grantingPolicies = permissions.GrantingPoliciesMap[ReadCapabilityInt]
+		requiredCapability = ReadCapabilityInt
 	case logical.ListOperation:
-		operationAllowed = capabilities&ListCapabilityInt > 0
-		grantingPolicies = permissions.GrantingPoliciesMap[ListCapabilityInt]
+		requiredCapability = ListCapabilityInt
 	case logical.UpdateOperation:
-		operationAllowed = capabilities&UpdateCapabilityInt > 0
-		grantingPolicies = permissions.GrantingPoliciesMap[UpdateCapabilityInt]
+		requiredCapability = UpdateCapabilityInt
 	case logical.DeleteOperation:
-		operationAllowed = capabilities&DeleteCapabilityInt > 0
-		grantingPolicies = permissions.GrantingPoliciesMap[DeleteCapabilityInt]
+		requiredCapability = DeleteCapabilityInt
 	case logical.CreateOperation:
-		operationAllowed = capabilities&CreateCapabilityInt > 0
-		grantingPolicies = permissions.GrantingPoliciesMap[CreateCapabilityInt]
+		requiredCapability = CreateCapabilityInt
 	case logical.PatchOperation:
-		operationAllowed = capabilities&PatchCapabilityInt > 0
-		grantingPolicies = permissions.GrantingPoliciesMap[PatchCapabilityInt]
+		requiredCapability = PatchCapabilityInt
 
 	// These three re-use UpdateCapabilityInt since that's the most appropriate
 	// capability/operation mapping
 	case logical.RevokeOperation, logical.RenewOperation, logical.RollbackOperation:
-		operationAllowed = capabilities&UpdateCapabilityInt > 0
-		grantingPolicies = permissions.GrantingPoliciesMap[UpdateCapabilityInt]
+		requiredCapability = UpdateCapabilityInt
 
 	default:
+		ret.DenialReason = ACLDenialUnsupportedOp
 		return
 	}
 
-	if !operationAllowed {
+	if capabilities&requiredCapability == 0 {
+		switch {
+		case capabilities&DenyCapabilityInt > 0:
+			ret.DenialReason = ACLDenialExplicitDeny
+		case ret.UnmetConditionalCapabilitiesBitmap&requiredCapability > 0:
+			ret.DenialReason = ACLDenialConditionsNotMet
+		default:
+			ret.DenialReason = ACLDenialMissingCapability
+		}
 		return
 	}
 
+	grantingPolicies := permissions.GrantingPoliciesMap[requiredCapability]
+	for _, grant := range metConditionalGrants {
+		if grant.CapabilitiesBitmap&requiredCapability > 0 {
+			// Copy to avoid modifying the slice held by the permissions
+			grantingPolicies = append(grantingPolicies[:len(grantingPolicies):len(grantingPolicies)], grant.Policy)
+		}
+	}
+
 	ret.GrantingPolicies = grantingPolicies
 
 	if permissions.MaxWrappingTTL > 0 {
 		if req.WrapInfo == nil || req.WrapInfo.TTL > permissions.MaxWrappingTTL {
+			ret.DenialReason = ACLDenialWrappingTTL
 			return
 		}
 	}
 	if permissions.MinWrappingTTL > 0 {
 		if req.WrapInfo == nil || req.WrapInfo.TTL < permissions.MinWrappingTTL {
+			ret.DenialReason = ACLDenialWrappingTTL
 			return
 		}
 	}
@@ -466,6 +635,7 @@ CHECK:
 	if permissions.MinWrappingTTL != 0 &&
 		permissions.MaxWrappingTTL != 0 &&
 		permissions.MaxWrappingTTL < permissions.MinWrappingTTL {
+		ret.DenialReason = ACLDenialWrappingTTL
 		return
 	}
 
@@ -474,6 +644,8 @@ CHECK:
 	if op == logical.ReadOperation || op == logical.UpdateOperation || op == logical.CreateOperation || op == logical.PatchOperation {
 		for _, parameter := range permissions.RequiredParameters {
 			if _, ok := req.Data[strings.ToLower(parameter)]; !ok {
+				ret.DenialReason = ACLDenialRequiredParameter
+				ret.DeniedParameter = parameter
 				return
 			}
 		}
@@ -490,6 +662,8 @@ CHECK:
 
 		// Check if all parameters have been denied
 		if _, ok := permissions.DeniedParameters["*"]; ok {
+			ret.DenialReason = ACLDenialDeniedParameter
+			ret.DeniedParameter = "*"
 			return
 		}
 
@@ -498,6 +672,8 @@ CHECK:
 			if valueSlice, ok := permissions.DeniedParameters[strings.ToLower(parameter)]; ok {
 				// If the value exists in denied values slice, deny
 				if valueInParameterList(value, valueSlice) {
+					ret.DenialReason = ACLDenialDeniedParameter
+					ret.DeniedParameter = parameter
 					return
 				}
 			}
@@ -520,12 +696,16 @@ CHECK:
 			valueSlice, ok := permissions.AllowedParameters[strings.ToLower(parameter)]
 			// Requested parameter is not in allowed list
 			if !ok && !allowedAll {
+				ret.DenialReason = ACLDenialParameterNotAllowed
+				ret.DeniedParameter = parameter
 				return
 			}
 
 			// If the value doesn't exists in the allowed values slice,
 			// deny
 			if ok && !valueInParameterList(value, valueSlice) {
+				ret.DenialReason = ACLDenialParameterNotAllowed
+				ret.DeniedParameter = parameter
 				return
 			}
 		}
@@ -540,6 +720,7 @@ type wcPathDescr struct {
 	wildcards     int
 	isPrefix      bool
 	wcPath        string
+	rulePath      string
 	perms         *ACLPermissions
 }
 
@@ -549,6 +730,14 @@ type wcPathDescr struct {
 // of permissions from some allowed path underneath the mount (for use in mount
 // access checks), or nil indicating no non-deny permissions were found.
 func (a *ACL) CheckAllowedFromNonExactPaths(path string, bareMount bool) *ACLPermissions {
+	_, permissions := a.checkAllowedFromNonExactPaths(path, bareMount)
+	return permissions
+}
+
+// checkAllowedFromNonExactPaths is like CheckAllowedFromNonExactPaths but also
+// returns the policy path rule that matched, with a trailing glob for prefix
+// rules.
+func (a *ACL) checkAllowedFromNonExactPaths(path string, bareMount bool) (string, *ACLPermissions) {
 	wcPathDescrs := make([]wcPathDescr, 0, len(a.segmentWildcardPaths)+1)
 
 	less := func(i, j int) bool {
@@ -612,19 +801,20 @@ func (a *ACL) CheckAllowedFromNonExactPaths(path string, bareMount bool) *ACLPer
 		prefix, raw, ok := a.prefixRules.LongestPrefix(path)
 		if ok {
 			if len(a.segmentWildcardPaths) == 0 {
-				return raw.(*ACLPermissions)
+				return prefix + "*", raw.(*ACLPermissions)
 			}
 			wcPathDescrs = append(wcPathDescrs, wcPathDescr{
 				firstWCOrGlob: len(prefix),
 				wcPath:        prefix,
 				isPrefix:      true,
 				perms:         raw.(*ACLPermissions),
+				rulePath:      prefix + "*",
 			})
 		}
 	}
 
 	if len(a.segmentWildcardPaths) == 0 {
-		return nil
+		return "", nil
 	}
 
 	pathParts := strings.Split(path, "/")
@@ -679,26 +869,28 @@ SWCPATH:
 				// check permissions. If they're defined but not deny, success.
 				if strings.HasPrefix(joinedPath, path) {
 					permissions := a.segmentWildcardPaths[fullWCPath].(*ACLPermissions)
-					if permissions.CapabilitiesBitmap&DenyCapabilityInt == 0 && permissions.CapabilitiesBitmap > 0 {
-						return permissions
+					if permissions.CapabilitiesBitmap&DenyCapabilityInt == 0 && permissions.allCapabilitiesBitmap() > 0 {
+						return fullWCPath, permissions
 					}
 				}
 				continue SWCPATH
 			}
 		}
 		pd.perms = a.segmentWildcardPaths[fullWCPath].(*ACLPermissions)
+		pd.rulePath = fullWCPath
 		wcPathDescrs = append(wcPathDescrs, pd)
 	}
 
 	if bareMount || len(wcPathDescrs) == 0 {
-		return nil
+		return "", nil
 	}
 
 	// We don't do this in the bare mount check because we don't care about
 	// priority, we only care about any capability at all.
 	sort.Slice(wcPathDescrs, less)
 
-	return wcPathDescrs[len(wcPathDescrs)-1].perms
+	matched := wcPathDescrs[len(wcPathDescrs)-1]
+	return matched.rulePath, matched.perms
 }
 
 func (c *Core) performPolicyChecks(ctx context.Context, acl *ACL, te *logical.TokenEntry, req *logical.Request, inEntity *identity.Entity, opts *PolicyCheckOpts) *AuthResults {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 	"testing"
 	"time"
@@ -532,6 +533,261 @@ func testACLAllowOperation(t *testing.T, ns *namespace.Namespace) {
 	}
 }
 
+func TestACL_AllowOperation_Explanation(t *testing.T) {
+	policy, err := ParseACLPolicy(namespace.RootNamespace, permissionsPolicy)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	ctx := namespace.RootContext(nil)
+	acl, err := NewACL(ctx, []*Policy{policy})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	type tcase struct {
+		path            string
+		operation       logical.Operation
+		parameters      []string
+		matchedPath     string
+		denialReason    ACLDenialReason
+		deniedParameter string
+	}
+
+	tcases := []tcase{
+		{"dev/ops", logical.UpdateOperation, []string{"zip"}, "dev/*", "", ""},
+		{"dev/ops", logical.UpdateOperation, []string{"zap"}, "dev/*", ACLDenialParameterNotAllowed, "zap"},
+		{"dev/ops", logical.PatchOperation, nil, "dev/*", ACLDenialMissingCapability, ""},
+		{"foo/bar", logical.UpdateOperation, nil, "foo/bar", ACLDenialWrappingTTL, ""},
+		{"tree/fort", logical.UpdateOperation, []string{"foo"}, "tree/fort", ACLDenialDeniedParameter, "foo"},
+		{"fruit/apple", logical.UpdateOperation, []string{"pear"}, "fruit/apple", ACLDenialDeniedParameter, "*"},
+		{"var/req", logical.UpdateOperation, nil, "var/req", ACLDenialRequiredParameter, "foo"},
+		{"nope/nope", logical.ReadOperation, nil, "", ACLDenialNoMatchingPath, ""},
+	}
+
+	for _, tc := range tcases {
+		request := &logical.Request{
+			Path:      tc.path,
+			Operation: tc.operation,
+			Data:      make(map[string]interface{}),
+		}
+		for _, parameter := range tc.parameters {
+			request.Data[parameter] = ""
+		}
+
+		authResults := acl.AllowOperation(ctx, request, false)
+		if authResults.Allowed != (tc.denialReason == "") {
+			t.Fatalf("bad: case %#v: allowed %v", tc, authResults.Allowed)
+		}
+		if authResults.MatchedPath != tc.matchedPath {
+			t.Fatalf("bad: case %#v: matched path %q", tc, authResults.MatchedPath)
+		}
+		if authResults.DenialReason != tc.denialReason {
+			t.Fatalf("bad: case %#v: denial reason %q", tc, authResults.DenialReason)
+		}
+		if authResults.DeniedParameter != tc.deniedParameter {
+			t.Fatalf("bad: case %#v: denied parameter %q", tc, authResults.DeniedParameter)
+		}
+	}
+
+	// Explicit deny and segment wildcard paths
+	policy, err = ParseACLPolicy(namespace.RootNamespace, `
+name = "explain"
+path "secret/+/config" {
+	capabilities = ["read"]
+}
+path "secret/admin/config" {
+	capabilities = ["deny"]
+}
+`)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	acl, err = NewACL(ctx, []*Policy{policy})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	authResults := acl.AllowOperation(ctx, &logical.Request{Path: "secret/dev/config", Operation: logical.ReadOperation}, false)
+	if !authResults.Allowed || authResults.MatchedPath != "secret/+/config" {
+		t.Fatalf("bad: %#v", authResults)
+	}
+	authResults = acl.AllowOperation(ctx, &logical.Request{Path: "secret/admin/config", Operation: logical.ReadOperation}, false)
+	if authResults.Allowed || authResults.DenialReason != ACLDenialExplicitDeny || authResults.MatchedPath != "secret/admin/config" {
+		t.Fatalf("bad: %#v", authResults)
+	}
+}
+
+func TestACL_AllowOperation_Conditions(t *testing.T) {
+	ctx := namespace.RootContext(nil)
+
+	conditional, err := ParseACLPolicy(namespace.RootNamespace, `
+path "secret/*" {
+	capabilities = ["read", "update"]
+	conditions {
+		allowed_cidrs  = ["10.0.0.0/8"]
+		token_metadata = {
+			team = "ops"
+		}
+	}
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conditional.Name = "break-glass"
+
+	unconditional, err := ParseACLPolicy(namespace.RootNamespace, `
+path "secret/*" {
+	capabilities = ["list"]
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unconditional.Name = "lister"
+
+	// Merging with an unconditional rule must not make the conditional
+	// capabilities unconditional, whatever the order of the policies
+	for _, policies := range [][]*Policy{{conditional, unconditional}, {unconditional, conditional}} {
+		acl, err := NewACL(ctx, policies)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		newRequest := func(op logical.Operation, remoteAddr, team string) *logical.Request {
+			req := &logical.Request{
+				Path:      "secret/foo",
+				Operation: op,
+				Connection: &logical.Connection{
+					RemoteAddr: remoteAddr,
+				},
+			}
+			req.SetTokenEntry(&logical.TokenEntry{Meta: map[string]string{"team": team}})
+			return req
+		}
+
+		res := acl.AllowOperation(ctx, newRequest(logical.ReadOperation, "10.1.2.3", "ops"), false)
+		if !res.Allowed {
+			t.Fatalf("expected read to be allowed: %#v", res)
+		}
+		if len(res.GrantingPolicies) != 1 || res.GrantingPolicies[0].Name != "break-glass" {
+			t.Fatalf("bad: granting policies: %#v", res.GrantingPolicies)
+		}
+
+		res = acl.AllowOperation(ctx, newRequest(logical.ReadOperation, "192.168.1.1", "ops"), false)
+		if res.Allowed || res.DenialReason != ACLDenialConditionsNotMet {
+			t.Fatalf("expected read from outside the cidr to be denied: %#v", res)
+		}
+
+		res = acl.AllowOperation(ctx, newRequest(logical.UpdateOperation, "10.1.2.3", "dev"), false)
+		if res.Allowed || res.DenialReason != ACLDenialConditionsNotMet {
+			t.Fatalf("expected update with mismatching metadata to be denied: %#v", res)
+		}
+
+		res = acl.AllowOperation(ctx, newRequest(logical.ListOperation, "192.168.1.1", "dev"), false)
+		if !res.Allowed {
+			t.Fatalf("expected unconditional list to be allowed: %#v", res)
+		}
+		if len(res.GrantingPolicies) != 1 || res.GrantingPolicies[0].Name != "lister" {
+			t.Fatalf("bad: granting policies: %#v", res.GrantingPolicies)
+		}
+
+		// Capabilities report conditional capabilities regardless of the
+		// conditions being met
+		capabilities := acl.Capabilities(ctx, "secret/foo")
+		sort.Strings(capabilities)
+		if !reflect.DeepEqual(capabilities, []string{"list", "read", "update"}) {
+			t.Fatalf("bad: capabilities: %v", capabilities)
+		}
+	}
+
+	// An explicit deny overrides conditional capabilities
+	deny, err := ParseACLPolicy(namespace.RootNamespace, `
+path "secret/*" {
+	capabilities = ["deny"]
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acl, err := NewACL(ctx, []*Policy{conditional, deny})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &logical.Request{
+		Path:      "secret/foo",
+		Operation: logical.ReadOperation,
+		Connection: &logical.Connection{
+			RemoteAddr: "10.1.2.3",
+		},
+	}
+	req.SetTokenEntry(&logical.TokenEntry{Meta: map[string]string{"team": "ops"}})
+	if res := acl.AllowOperation(ctx, req, false); res.Allowed || res.DenialReason != ACLDenialExplicitDeny {
+		t.Fatalf("expected explicit deny: %#v", res)
+	}
+
+	// The parameter and wrapping constraints of a conditional rule only apply
+	// when its conditions are met
+	constrained, err := ParseACLPolicy(namespace.RootNamespace, `
+path "secret/*" {
+	capabilities       = ["update"]
+	allowed_parameters = {
+		"value" = []
+	}
+	max_wrapping_ttl = 300
+	conditions {
+		allowed_cidrs = ["10.0.0.0/8"]
+	}
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer, err := ParseACLPolicy(namespace.RootNamespace, `
+path "secret/*" {
+	capabilities = ["update"]
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, policies := range [][]*Policy{{constrained, writer}, {writer, constrained}} {
+		acl, err := NewACL(ctx, policies)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		newRequest := func(remoteAddr string) *logical.Request {
+			return &logical.Request{
+				Path:      "secret/foo",
+				Operation: logical.UpdateOperation,
+				Data: map[string]interface{}{
+					"other": "bar",
+				},
+				Connection: &logical.Connection{
+					RemoteAddr: remoteAddr,
+				},
+			}
+		}
+
+		if res := acl.AllowOperation(ctx, newRequest("192.168.1.1"), false); !res.Allowed {
+			t.Fatalf("expected unmet conditional constraints not to apply: %#v", res)
+		}
+
+		res := acl.AllowOperation(ctx, newRequest("10.1.2.3"), false)
+		if res.Allowed || res.DenialReason != ACLDenialWrappingTTL {
+			t.Fatalf("expected met conditional constraints to apply: %#v", res)
+		}
+
+		req := newRequest("10.1.2.3")
+		req.WrapInfo = &logical.RequestWrapInfo{TTL: time.Minute}
+		res = acl.AllowOperation(ctx, req, false)
+		if res.Allowed || res.DenialReason != ACLDenialParameterNotAllowed || res.DeniedParameter != "other" {
+			t.Fatalf("expected met conditional constraints to apply: %#v", res)
+		}
+	}
+}
+
 func TestACL_ValuePermissions(t *testing.T) {
 	t.Run("root-ns", func(t *testing.T) {
 		t.Parallel()
@@ -80,6 +80,9 @@ func (c *Core) Capabilities(ctx context.Context, token, path string) ([]string,
 	if err != nil {
 		return nil, err
 	}
+	if err := c.tokenStore.applyTokenPathRestrictions(ctx, acl, tokenNS, te); err != nil {
+		return nil, err
+	}
 
 	capabilities := acl.Capabilities(ctx, path)
 	sort.Strings(capabilities)
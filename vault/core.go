@@ -2,6 +2,7 @@ package vault
 
 import (
 	"context"
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/rand"
 	"crypto/subtle"
@@ -47,6 +48,7 @@ import (
 	"github.com/hashicorp/vault/sdk/helper/consts"
 	"github.com/hashicorp/vault/sdk/helper/jsonutil"
 	"github.com/hashicorp/vault/sdk/helper/logging"
+	"github.com/hashicorp/vault/sdk/helper/pluginutil"
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/hashicorp/vault/sdk/physical"
 	"github.com/hashicorp/vault/sdk/version"
@@ -517,6 +519,15 @@ type Core struct {
 	// pluginFilePermissions is the permissions of the plugin files and directory
 	pluginFilePermissions int
 
+	// pluginBundleDirectory is the location vault will look for plugin bundles
+	pluginBundleDirectory string
+
+	// pluginBundleTrustedKeys are the keys plugin bundles must be signed with
+	pluginBundleTrustedKeys []crypto.PublicKey
+
+	// pluginLauncher runs external plugins with a runtime configuration
+	pluginLauncher *pluginutil.PluginLauncher
+
 	// pluginCatalog is used to manage plugin configurations
 	pluginCatalog *PluginCatalog
 
@@ -525,6 +536,19 @@ type Core struct {
 	// and login counter, last failed login time as value
 	userFailedLoginInfo map[FailedLoginUser]*FailedLoginInfo
 
+	// The sourceFailedLoginInfo map has the same information keyed by
+	// client address and mount accessor. It is used for progressive login
+	// delays.
+	sourceFailedLoginInfo map[FailedLoginSource]*FailedLoginInfo
+
+	// userFailedLoginInfoLock protects userFailedLoginInfo and
+	// sourceFailedLoginInfo
+	userFailedLoginInfoLock sync.RWMutex
+
+	// failedLoginInfoLastPruned is when stale entries were last removed
+	// from the failed login maps, guarded by userFailedLoginInfoLock
+	failedLoginInfoLastPruned time.Time
+
 	enableMlock bool
 
 	// This can be used to trigger operations to stop running when Vault is
@@ -581,6 +605,11 @@ type Core struct {
 	raftFollowerStates *raft.FollowerStates
 	// Stop channel for raft TLS rotations
 	raftTLSRotationStopCh chan struct{}
+	// raftAutoSnapshots takes the automated raft snapshots on the active node
+	raftAutoSnapshots *raftAutoSnapshotManager
+	// storageMigration runs the online storage migration, if a migration
+	// destination is configured
+	storageMigration *storageMigrationManager
 	// Stores the pending peers we are waiting to give answers
 	pendingRaftPeers *sync.Map
 
@@ -676,6 +705,11 @@ type CoreConfig struct {
 
 	StorageType string
 
+	// StorageMigrationDestination is the destination of an online storage
+	// migration. If set, writes are applied to both Physical and the
+	// destination.
+	StorageMigrationDestination physical.Backend
+
 	// May be nil, which disables HA operations
 	HAPhysical physical.HABackend
 
@@ -732,6 +766,17 @@ type CoreConfig struct {
 
 	PluginFilePermissions int
 
+	// PluginBundleDirectory is the directory plugin bundles are installed from
+	PluginBundleDirectory string
+
+	// PluginBundleTrustedKeys are the paths of the PEM encoded public keys
+	// plugin bundles must be signed with
+	PluginBundleTrustedKeys []string
+
+	// PluginLauncher is the command running external plugins with a runtime
+	// configuration
+	PluginLauncher *pluginutil.PluginLauncher
+
 	DisableSealWrap bool
 
 	RawConfig *server.Config
@@ -836,6 +881,17 @@ func CreateCore(conf *CoreConfig) (*Core, error) {
 		conf.Logger = logging.NewVaultLogger(log.Trace)
 	}
 
+	var storageMigration *storageMigrationManager
+	if conf.StorageMigrationDestination != nil {
+		if _, ok := conf.Physical.(*raft.RaftBackend); ok {
+			return nil, fmt.Errorf("online storage migration from raft storage is not supported")
+		}
+		migrationLogger := conf.Logger.Named("storage.migration")
+		migrationBackend := newStorageMigrationBackend(conf.Physical, conf.StorageMigrationDestination, migrationLogger)
+		storageMigration = newStorageMigrationManager(migrationBackend, migrationLogger)
+		conf.Physical = migrationBackend.backend()
+	}
+
 	// Make a default metric sink if not provided
 	if conf.MetricSink == nil {
 		conf.MetricSink = metricsutil.BlackholeSink()
@@ -932,6 +988,8 @@ func CreateCore(conf *CoreConfig) (*Core, error) {
 		disableSSCTokens:               conf.DisableSSCTokens,
 		effectiveSDKVersion:            effectiveSDKVersion,
 		userFailedLoginInfo:            make(map[FailedLoginUser]*FailedLoginInfo),
+		sourceFailedLoginInfo:          make(map[FailedLoginSource]*FailedLoginInfo),
+		storageMigration:               storageMigration,
 	}
 
 	c.standbyStopCh.Store(make(chan struct{}))
@@ -1080,6 +1138,18 @@ func NewCore(conf *CoreConfig) (*Core, error) {
 		c.pluginFilePermissions = conf.PluginFilePermissions
 	}
 
+	if conf.PluginBundleDirectory != "" {
+		c.pluginBundleDirectory, err = filepath.Abs(conf.PluginBundleDirectory)
+		if err != nil {
+			return nil, fmt.Errorf("core setup failed, could not verify plugin bundle directory: %w", err)
+		}
+	}
+	c.pluginBundleTrustedKeys, err = loadPluginBundleTrustedKeys(conf.PluginBundleTrustedKeys)
+	if err != nil {
+		return nil, fmt.Errorf("core setup failed, could not load plugin bundle trusted keys: %w", err)
+	}
+	c.pluginLauncher = conf.PluginLauncher
+
 	createSecondaries(c, conf)
 
 	if conf.HAPhysical != nil && conf.HAPhysical.HAEnabled() {
@@ -2380,6 +2450,8 @@ func (c *Core) preSeal() error {
 
 	c.stopRaftActiveNode()
 
+	c.stopStorageMigration()
+
 	c.clusterParamsLock.Lock()
 	if err := stopReplication(c); err != nil {
 		result = multierror.Append(result, fmt.Errorf("error stopping replication: %w", err))
@@ -3078,6 +3150,102 @@ func (c *Core) ApplyRateLimitQuota(ctx context.Context, req *quotas.Request) (qu
 	return resp, nil
 }
 
+// PopulateQuotaClientIdentity sets the entity ID and token accessor of the
+// quota request from the given client token, if the rate limit quota that
+// applies to the request identifies clients by their token. Tokens that cannot
+// be resolved are ignored, in which case the quota falls back to identifying
+// the client by its address. The token entry is only used to identify the
+// client, the request looks the token up again when it is handled.
+func (c *Core) PopulateQuotaClientIdentity(ctx context.Context, req *quotas.Request, token string) {
+	if c.quotaManager == nil || token == "" {
+		return
+	}
+
+	rlReq := *req
+	rlReq.Type = quotas.TypeRateLimit
+	quota, err := c.quotaManager.QueryQuota(&rlReq)
+	if err != nil || quota == nil {
+		return
+	}
+	rlq, ok := quota.(*quotas.RateLimitQuota)
+	if !ok || !rlq.ClientKey.RequiresToken() {
+		return
+	}
+
+	c.stateLock.RLock()
+	defer c.stateLock.RUnlock()
+
+	if IsSSCToken(token) {
+		// The token is only used to bucket the request, so skip the SSC token
+		// checks which will be performed when the request is handled.
+		token, err = c.DecodeSSCToken(token)
+		if err != nil {
+			return
+		}
+	}
+
+	te, err := c.LookupToken(ctx, token)
+	if err != nil || te == nil {
+		return
+	}
+
+	req.EntityID = te.EntityID
+	req.ClientTokenAccessor = te.Accessor
+}
+
+// ApplyConcurrencyQuota checks the request against all the applicable
+// concurrency quota rules. If the request is allowed, the Access of the returned
+// response must be passed to ReleaseConcurrencyQuota once the request has been
+// processed. Paths that are exempt from rate limiting are also exempt from
+// concurrency limiting.
+func (c *Core) ApplyConcurrencyQuota(ctx context.Context, req *quotas.Request) (quotas.Response, error) {
+	req.Type = quotas.TypeConcurrency
+
+	resp := quotas.Response{
+		Allowed: true,
+	}
+
+	if c.quotaManager != nil {
+		if c.quotaManager.RateLimitPathExempt(req.Path) {
+			return resp, nil
+		}
+
+		return c.quotaManager.ApplyQuota(ctx, req)
+	}
+
+	return resp, nil
+}
+
+// ReleaseConcurrencyQuota frees up the in-flight request slot held by a request
+// that was allowed by ApplyConcurrencyQuota.
+func (c *Core) ReleaseConcurrencyQuota(access quotas.Access) {
+	if c.quotaManager != nil && access != nil {
+		c.quotaManager.ReleaseConcurrencyQuota(access)
+	}
+}
+
+// ApplyRequestSizeQuota checks the request against all the applicable request
+// size quota rules. Paths that are exempt from rate limiting are also exempt
+// from request size limiting.
+func (c *Core) ApplyRequestSizeQuota(ctx context.Context, req *quotas.Request) (quotas.Response, error) {
+	req.Type = quotas.TypeRequestSize
+
+	resp := quotas.Response{
+		Allowed: true,
+		Headers: make(map[string]string),
+	}
+
+	if c.quotaManager != nil {
+		if c.quotaManager.RateLimitPathExempt(req.Path) {
+			return resp, nil
+		}
+
+		return c.quotaManager.ApplyQuota(ctx, req)
+	}
+
+	return resp, nil
+}
+
 // RateLimitAuditLoggingEnabled returns if the quota configuration allows audit
 // logging of request rejections due to rate limiting quota rule violations.
 func (c *Core) RateLimitAuditLoggingEnabled() bool {
@@ -3191,6 +3359,10 @@ type MFACachedAuthResponse struct {
 	RequestConnRemoteAddr string
 	TimeOfStorage         time.Time
 	RequestID             string
+
+	// WebAuthnChallenges holds the outstanding WebAuthn challenge of each
+	// method, keyed by method ID. A challenge is only valid for one assertion.
+	WebAuthnChallenges map[string]string
 }
 
 func (c *Core) setupCachedMFAResponseAuth() {
@@ -202,6 +202,16 @@ func (c *Core) leaseExpiryGaugeCollector(ctx context.Context) ([]metricsutil.Gau
 	return e.leaseAggregationMetrics(ctx, metricsConsts)
 }
 
+func (c *Core) irrevocableLeaseGaugeCollector(ctx context.Context) ([]metricsutil.GaugeLabelValues, error) {
+	c.stateLock.RLock()
+	e := c.expiration
+	c.stateLock.RUnlock()
+	if e == nil {
+		return []metricsutil.GaugeLabelValues{}, errors.New("nil expiration manager")
+	}
+	return e.irrevocableLeaseGaugeCollector(ctx)
+}
+
 func (c *Core) tokenGaugeMethodCollector(ctx context.Context) ([]metricsutil.GaugeLabelValues, error) {
 	if c.IsDRSecondary() {
 		// there is no expiration manager on DR Secondaries
@@ -262,6 +272,12 @@ func (c *Core) emitMetricsActiveNode(stopCh chan struct{}) {
 			c.leaseExpiryGaugeCollector,
 			"",
 		},
+		{
+			[]string{"expire", "irrevocable_leases", "by_mount"},
+			[]metrics.Label{{Name: "gauge", Value: "irrevocable_leases_by_mount"}},
+			c.irrevocableLeaseGaugeCollector,
+			"",
+		},
 		{
 			[]string{"token", "count", "by_auth"},
 			[]metrics.Label{{"gauge", "token_by_auth"}},
@@ -25,6 +25,10 @@ var StdAllowedHeaders = []string{
 	"X-Vault-No-Request-Forwarding",
 	"X-Vault-Wrap-Format",
 	"X-Vault-Wrap-TTL",
+	"X-Vault-Wrap-Uses",
+	"X-Vault-Wrap-Bound-Entity-ID",
+	"X-Vault-Wrap-Bound-AppRole-Role",
+	"X-Vault-Wrap-Bound-CIDRs",
 	"X-Vault-Policy-Override",
 	"Authorization",
 	consts.AuthHeaderName,
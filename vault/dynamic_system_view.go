@@ -2,7 +2,9 @@ package vault
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/hashicorp/vault/helper/identity"
@@ -120,6 +122,10 @@ func (e extendedSystemViewImpl) SudoPrivilege(ctx context.Context, path string,
 		e.core.logger.Error("failed to retrieve ACL for token's policies", "token_policies", te.Policies, "error", err)
 		return false
 	}
+	if err := e.core.tokenStore.applyTokenPathRestrictions(ctx, acl, tokenNS, te); err != nil {
+		e.core.logger.Error("failed to apply the token's path restrictions", "error", err)
+		return false
+	}
 
 	// The operation type isn't important here as this is run from a path the
 	// user has already been given access to; we only care about whether they
@@ -129,6 +135,8 @@ func (e extendedSystemViewImpl) SudoPrivilege(ctx context.Context, path string,
 	req := new(logical.Request)
 	req.Operation = logical.ReadOperation
 	req.Path = path
+	// Path rule conditions on token metadata are evaluated against the token
+	req.SetTokenEntry(te)
 	authResults := acl.AllowOperation(namespace.RootContext(ctx), req, true)
 	return authResults.RootPrivs
 }
@@ -251,14 +259,23 @@ func (d dynamicSystemView) LookupPluginVersion(ctx context.Context, name string,
 	if err != nil {
 		return nil, err
 	}
+	errContext := name
+	if version != "" {
+		errContext += fmt.Sprintf(", version=%s", version)
+	}
 	if r == nil {
-		errContext := name
-		if version != "" {
-			errContext += fmt.Sprintf(", version=%s", version)
-		}
 		return nil, fmt.Errorf("%w: %s", ErrPluginNotFound, errContext)
 	}
 
+	// The catalog is replicated but the plugin directory isn't, so a plugin
+	// registered on another node, for instance by installing a plugin bundle,
+	// may be missing on this one
+	if !r.Builtin {
+		if _, err := os.Stat(r.Command); errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("binary of plugin %s is missing from the plugin directory of this node; plugins must be installed on every node", errContext)
+		}
+	}
+
 	return r, nil
 }
 
@@ -393,21 +410,48 @@ func (d dynamicSystemView) GeneratePasswordFromPolicy(ctx context.Context, polic
 		defer cancel()
 	}
 
+	passPolicy, err := d.passwordPolicy(ctx, policyName)
+	if err != nil {
+		return "", err
+	}
+
+	return passPolicy.Generate(ctx, nil)
+}
+
+func (d dynamicSystemView) ValidatePasswordAgainstPolicy(ctx context.Context, policyName string, password string) error {
+	if policyName == "" {
+		return fmt.Errorf("missing password policy name")
+	}
+
+	passPolicy, err := d.passwordPolicy(ctx, policyName)
+	if err != nil {
+		return err
+	}
+
+	if err := passPolicy.Validate(password); err != nil {
+		return fmt.Errorf("password does not satisfy password policy %q: %w", policyName, err)
+	}
+	return nil
+}
+
+// passwordPolicy retrieves and parses the named password policy in the
+// namespace of the mount
+func (d dynamicSystemView) passwordPolicy(ctx context.Context, policyName string) (random.StringGenerator, error) {
 	ctx = namespace.ContextWithNamespace(ctx, d.mountEntry.Namespace())
 
 	policyCfg, err := d.retrievePasswordPolicy(ctx, policyName)
 	if err != nil {
-		return "", fmt.Errorf("failed to retrieve password policy: %w", err)
+		return random.StringGenerator{}, fmt.Errorf("failed to retrieve password policy: %w", err)
 	}
 
 	if policyCfg == nil {
-		return "", fmt.Errorf("no password policy found")
+		return random.StringGenerator{}, fmt.Errorf("no password policy found")
 	}
 
 	passPolicy, err := random.ParsePolicy(policyCfg.HCLPolicy)
 	if err != nil {
-		return "", fmt.Errorf("stored password policy is invalid: %w", err)
+		return random.StringGenerator{}, fmt.Errorf("stored password policy is invalid: %w", err)
 	}
 
-	return passPolicy.Generate(ctx, nil)
+	return passPolicy, nil
 }
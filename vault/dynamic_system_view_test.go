@@ -236,6 +236,66 @@ func TestDynamicSystemView_GeneratePasswordFromPolicy_successful(t *testing.T) {
 	}
 }
 
+func TestDynamicSystemView_ValidatePasswordAgainstPolicy(t *testing.T) {
+	coreConfig := &CoreConfig{
+		DisableMlock:       true,
+		DisableCache:       true,
+		Logger:             log.NewNullLogger(),
+		CredentialBackends: map[string]logical.Factory{},
+	}
+
+	cluster := NewTestCluster(t, coreConfig, &TestClusterOptions{})
+
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	core := cluster.Cores[0].Core
+	TestWaitActive(t, core)
+
+	rawPolicy := `
+length = 12
+rule "charset" {
+	charset = "abcdefghijklmnopqrstuvwxyz"
+	min-chars = 1
+}
+rule "charset" {
+	charset = "0123456789"
+	min-chars = 2
+}`
+
+	req := logical.TestRequest(t, logical.CreateOperation, fmt.Sprintf("sys/policies/password/%s", testPolicyName))
+	req.ClientToken = cluster.RootToken
+	req.Data["policy"] = base64.StdEncoding.EncodeToString([]byte(rawPolicy))
+
+	_, err := core.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	ctx := namespace.RootContext(context.Background())
+	dsv := TestDynamicSystemView(cluster.Cores[0].Core, nil)
+
+	// Passwords generated from the policy must satisfy it
+	password, err := dsv.GeneratePasswordFromPolicy(ctx, testPolicyName)
+	if err != nil {
+		t.Fatalf("no error expected, but got: %s", err)
+	}
+	if err := dsv.ValidatePasswordAgainstPolicy(ctx, testPolicyName, password); err != nil {
+		t.Fatalf("no error expected, but got: %s", err)
+	}
+
+	// The length of the policy is a minimum length
+	if err := dsv.ValidatePasswordAgainstPolicy(ctx, testPolicyName, password+"-suffix"); err != nil {
+		t.Fatalf("no error expected, but got: %s", err)
+	}
+
+	for _, invalid := range []string{"abc12", "abcdefghijklmnopqrstuvwxyz1", "ABCDEFGHIJKLMNOPQRSTUVWXYZ12"} {
+		if err := dsv.ValidatePasswordAgainstPolicy(ctx, testPolicyName, invalid); err == nil {
+			t.Fatalf("err expected for %q, got nil", invalid)
+		}
+	}
+}
+
 func TestDynamicSystemView_GeneratePasswordFromPolicy_failed(t *testing.T) {
 	type testCase struct {
 		policyName string
@@ -288,6 +348,11 @@ func TestDynamicSystemView_GeneratePasswordFromPolicy_failed(t *testing.T) {
 			if actualPassword != "" {
 				t.Fatalf("no password expected, got %s", actualPassword)
 			}
+
+			err = dsv.ValidatePasswordAgainstPolicy(ctx, test.policyName, "password")
+			if err == nil {
+				t.Fatalf("err expected, got nil")
+			}
 		})
 	}
 }
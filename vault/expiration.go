@@ -123,6 +123,13 @@ type ExpirationManager struct {
 	// This value is protected by pendingLock
 	irrevocableLeaseCount int
 
+	// Track the revocation attempts of irrevocable leases scheduled by
+	// operators
+	irrevocableRetries sync.Map
+
+	// Track the progress of the last revocation of leases matching a filter
+	filteredRevocation leaseRevocationTracker
+
 	// The uniquePolicies map holds policy sets, so they can
 	// be deduplicated. It is periodically emptied to prevent
 	// unbounded growth.
@@ -870,6 +877,12 @@ func (m *ExpirationManager) Stop() error {
 	m.irrevocableLeaseCount = 0
 	m.pendingLock.Unlock()
 
+	m.irrevocableRetries.Range(func(key, value interface{}) bool {
+		value.(*irrevocableRetry).cancel()
+		m.irrevocableRetries.Delete(key)
+		return true
+	})
+
 	if m.inRestoreMode() {
 		for {
 			if !m.inRestoreMode() {
@@ -946,7 +959,7 @@ func (m *ExpirationManager) attemptIrrevocableLeasesRevoke() {
 
 			ctxWithNS := namespace.ContextWithNamespace(m.core.activeContext, leaseNS)
 			ctxWithNSAndTimeout, _ := context.WithTimeout(ctxWithNS, time.Minute)
-			if err := m.revokeCommon(ctxWithNSAndTimeout, leaseID, false, false); err != nil {
+			if err := m.retryIrrevocableLease(ctxWithNSAndTimeout, leaseID); err != nil {
 				// on failure, force some delay to mitigate resource spike while
 				// this is running. if revocations succeed, we are okay with
 				// the higher resource consumption.
@@ -997,13 +1010,33 @@ func (m *ExpirationManager) revokeCommon(ctx context.Context, leaseID string, fo
 		}
 	}
 
+	if err := m.removeRevokedEntry(ctx, le); err != nil {
+		return err
+	}
+
+	if m.logger.IsInfo() && !skipToken && m.logLeaseExpirations {
+		m.logger.Info("revoked lease", "lease_id", leaseID)
+	}
+	if m.logger.IsWarn() && !skipToken && le.isIncorrectlyNonExpiring() {
+		var accessor string
+		if le.Auth != nil {
+			accessor = le.Auth.Accessor
+		}
+		m.logger.Warn("finished revoking incorrectly non-expiring lease", "leaseID", le.LeaseID, "accessor", accessor)
+	}
+	return nil
+}
+
+// removeRevokedEntry deletes the entry of a revoked lease and its secondary
+// index, and stops tracking the lease. The lease lock must be held.
+func (m *ExpirationManager) removeRevokedEntry(ctx context.Context, le *leaseEntry) error {
 	// Delete the entry
 	if err := m.deleteEntry(ctx, le); err != nil {
 		return err
 	}
 
 	// Lease has been removed, also remove the in-memory lock.
-	m.deleteLockForLease(leaseID)
+	m.deleteLockForLease(le.LeaseID)
 
 	// Delete the secondary index, but only if it's a leased secret (not auth)
 	if le.Secret != nil {
@@ -1030,25 +1063,19 @@ func (m *ExpirationManager) revokeCommon(ctx context.Context, leaseID string, fo
 
 	// Clear the expiration handler
 	m.pendingLock.Lock()
-	m.removeFromPending(ctx, leaseID, true)
-	m.nonexpiring.Delete(leaseID)
+	m.removeFromPending(ctx, le.LeaseID, true)
+	m.nonexpiring.Delete(le.LeaseID)
 
 	if _, ok := m.irrevocable.Load(le.LeaseID); ok {
-		m.irrevocable.Delete(leaseID)
+		m.irrevocable.Delete(le.LeaseID)
 		m.irrevocableLeaseCount--
 	}
 	m.pendingLock.Unlock()
 
-	if m.logger.IsInfo() && !skipToken && m.logLeaseExpirations {
-		m.logger.Info("revoked lease", "lease_id", leaseID)
-	}
-	if m.logger.IsWarn() && !skipToken && le.isIncorrectlyNonExpiring() {
-		var accessor string
-		if le.Auth != nil {
-			accessor = le.Auth.Accessor
-		}
-		m.logger.Warn("finished revoking incorrectly non-expiring lease", "leaseID", le.LeaseID, "accessor", accessor)
-	}
+	// Stop the scheduled revocation attempts of the lease, if it was
+	// irrevocable
+	m.cancelIrrevocableRetry(le.LeaseID)
+
 	return nil
 }
 
@@ -1749,6 +1776,7 @@ func (m *ExpirationManager) inMemoryLeaseInfo(le *leaseEntry) *leaseEntry {
 	}
 	if le.isIrrevocable() {
 		ret.RevokeErr = le.RevokeErr
+		ret.RevokeErrHistory = le.RevokeErrHistory
 	}
 	ret.LoginRole = le.LoginRole
 	return ret
@@ -2527,19 +2555,9 @@ func (m *ExpirationManager) markLeaseIrrevocable(ctx context.Context, le *leaseE
 		return
 	}
 
-	var errStr string
-	if err != nil {
-		errStr = err.Error()
-	}
-	if len(errStr) == 0 {
-		errStr = genericIrrevocableErrorMessage
-	}
-	if len(errStr) > maxIrrevocableErrorLength {
-		errStr = errStr[:maxIrrevocableErrorLength]
-	}
-
-	le.RevokeErr = errStr
+	le.appendRevokeErr(irrevocableErrorString(err), time.Now())
 	m.persistEntry(ctx, le)
+	m.core.metricSink.IncrCounterWithLabels([]string{"expire", "irrevocable", "marked"}, 1, m.irrevocableMountLabels(ctx, le.LeaseID))
 
 	m.irrevocable.Store(le.LeaseID, m.inMemoryLeaseInfo(le))
 	m.irrevocableLeaseCount++
@@ -2733,8 +2751,12 @@ type leaseEntry struct {
 	// RevokeErr tracks if a lease has failed revocation in a way that is
 	// unlikely to be automatically resolved. The first time this happens,
 	// RevokeErr will be set, thus marking this leaseEntry as irrevocable. From
-	// there, it must be manually removed (force revoked).
+	// there, it must be manually removed (force revoked, retried or resolved).
 	RevokeErr string `json:"revokeErr"`
+
+	// RevokeErrHistory holds the last revocation errors of an irrevocable
+	// lease, including RevokeErr.
+	RevokeErrHistory []*leaseRevokeError `json:"revoke_err_history,omitempty"`
 }
 
 // encode is used to JSON encode the lease entry
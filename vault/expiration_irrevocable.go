@@ -0,0 +1,372 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/vault/helper/metricsutil"
+	"github.com/hashicorp/vault/helper/namespace"
+)
+
+const (
+	// maxIrrevocableErrorHistory limits the number of revocation errors kept
+	// for each irrevocable lease
+	maxIrrevocableErrorHistory = 10
+
+	// maxIrrevocableRetryBackoff caps the delay between the scheduled
+	// revocation attempts of an irrevocable lease
+	maxIrrevocableRetryBackoff = 24 * time.Hour
+
+	// irrevocableMountNotFound is the mount point label of the irrevocable
+	// leases whose mount doesn't exist anymore
+	irrevocableMountNotFound = "mount-not-found"
+)
+
+// ErrLeaseNotIrrevocable is returned when an operation reserved to
+// irrevocable leases is attempted on a lease that isn't irrevocable
+var ErrLeaseNotIrrevocable = errors.New("lease is not irrevocable")
+
+// leaseRevokeError is a failed revocation attempt of an irrevocable lease
+type leaseRevokeError struct {
+	Time  time.Time `json:"time"`
+	Error string    `json:"error"`
+}
+
+// irrevocableRetry is the schedule of the revocation attempts of an
+// irrevocable lease requested by an operator
+type irrevocableRetry struct {
+	l            sync.Mutex
+	timer        *time.Timer
+	attemptsLeft int
+	backoff      time.Duration
+	nextAttempt  time.Time
+	canceled     bool
+}
+
+// irrevocableErrorString truncates the revocation error of an irrevocable
+// lease to be stored along with the lease
+func irrevocableErrorString(err error) string {
+	var errStr string
+	if err != nil {
+		errStr = err.Error()
+	}
+	if len(errStr) == 0 {
+		errStr = genericIrrevocableErrorMessage
+	}
+	if len(errStr) > maxIrrevocableErrorLength {
+		errStr = errStr[:maxIrrevocableErrorLength]
+	}
+	return errStr
+}
+
+// appendRevokeErr records a failed revocation in the error history of the
+// lease and sets it as the current revocation error
+func (le *leaseEntry) appendRevokeErr(errStr string, now time.Time) {
+	le.RevokeErr = errStr
+	le.RevokeErrHistory = append(le.RevokeErrHistory, &leaseRevokeError{
+		Time:  now,
+		Error: errStr,
+	})
+	if len(le.RevokeErrHistory) > maxIrrevocableErrorHistory {
+		le.RevokeErrHistory = le.RevokeErrHistory[len(le.RevokeErrHistory)-maxIrrevocableErrorHistory:]
+	}
+}
+
+// irrevocableMountLabels returns the metric labels of the mount of a lease
+func (m *ExpirationManager) irrevocableMountLabels(ctx context.Context, leaseID string) []metrics.Label {
+	ns, err := m.getNamespaceFromLeaseID(ctx, leaseID)
+	if err != nil {
+		ns = namespace.RootNamespace
+	}
+	mountPoint := m.core.router.MatchingMount(namespace.ContextWithNamespace(ctx, ns), leaseID)
+	if mountPoint == "" {
+		mountPoint = irrevocableMountNotFound
+	}
+	return []metrics.Label{
+		metricsutil.NamespaceLabel(ns),
+		{Name: "mount_point", Value: mountPoint},
+	}
+}
+
+// retryIrrevocableLease attempts once to revoke an irrevocable lease. On
+// failure, the error is added to the error history of the lease. If the lease
+// is not irrevocable anymore, for instance because it was revoked or resolved
+// in the meantime, ErrLeaseNotIrrevocable is returned.
+func (m *ExpirationManager) retryIrrevocableLease(ctx context.Context, leaseID string) error {
+	leaseLock := m.lockForLeaseID(leaseID)
+	leaseLock.Lock()
+	defer leaseLock.Unlock()
+
+	le, err := m.loadEntry(ctx, leaseID)
+	if err != nil {
+		return err
+	}
+	if le == nil || !le.isIrrevocable() {
+		return ErrLeaseNotIrrevocable
+	}
+
+	labels := m.irrevocableMountLabels(ctx, leaseID)
+
+	revokeErr := m.revokeEntry(ctx, le)
+	if revokeErr == nil {
+		revokeErr = m.removeRevokedEntry(ctx, le)
+	}
+	if revokeErr == nil {
+		m.core.metricSink.IncrCounterWithLabels([]string{"expire", "irrevocable", "revoked"}, 1, labels)
+		m.logger.Info("revoked irrevocable lease", "lease_id", leaseID)
+		return nil
+	}
+	m.core.metricSink.IncrCounterWithLabels([]string{"expire", "irrevocable", "revoke_failed"}, 1, labels)
+
+	le.appendRevokeErr(irrevocableErrorString(revokeErr), time.Now())
+	if err := m.persistEntry(ctx, le); err != nil {
+		m.logger.Warn("failed to record revocation error of irrevocable lease", "lease_id", leaseID, "error", err)
+		return revokeErr
+	}
+
+	m.pendingLock.Lock()
+	if _, ok := m.irrevocable.Load(leaseID); ok {
+		m.irrevocable.Store(leaseID, m.inMemoryLeaseInfo(le))
+	}
+	m.pendingLock.Unlock()
+
+	return revokeErr
+}
+
+// RetryIrrevocableLease attempts to revoke an irrevocable lease now, then,
+// if the attempt fails, up to attempts-1 more times, doubling the backoff
+// between each attempt. It returns the error of the first attempt.
+func (m *ExpirationManager) RetryIrrevocableLease(ctx context.Context, leaseID string, attempts int, backoff time.Duration) error {
+	if attempts < 1 {
+		return errors.New("at least one attempt is required")
+	}
+	if attempts > 1 && backoff <= 0 {
+		return errors.New("backoff must be positive to schedule more than one attempt")
+	}
+
+	if _, ok := m.irrevocable.Load(leaseID); !ok {
+		return ErrLeaseNotIrrevocable
+	}
+
+	m.cancelIrrevocableRetry(leaseID)
+
+	err := m.retryIrrevocableLease(ctx, leaseID)
+	if err == nil || attempts == 1 || errors.Is(err, ErrLeaseNotIrrevocable) {
+		return err
+	}
+
+	retry := &irrevocableRetry{
+		attemptsLeft: attempts - 1,
+		backoff:      backoff,
+	}
+	retry.l.Lock()
+	defer retry.l.Unlock()
+	m.irrevocableRetries.Store(leaseID, retry)
+	m.scheduleIrrevocableRetry(leaseID, retry)
+
+	return err
+}
+
+// scheduleIrrevocableRetry schedules the next attempt of the retry, which
+// must be locked
+func (m *ExpirationManager) scheduleIrrevocableRetry(leaseID string, retry *irrevocableRetry) {
+	retry.nextAttempt = time.Now().Add(retry.backoff)
+	retry.timer = time.AfterFunc(retry.backoff, func() {
+		select {
+		case <-m.quitCh:
+			return
+		default:
+		}
+
+		leaseNS, err := m.getNamespaceFromLeaseID(m.quitContext, leaseID)
+		if err == nil {
+			revokeCtx, cancel := context.WithTimeout(namespace.ContextWithNamespace(m.quitContext, leaseNS), DefaultMaxRequestDuration)
+			m.coreStateLock.RLock()
+			err = m.retryIrrevocableLease(revokeCtx, leaseID)
+			m.coreStateLock.RUnlock()
+			cancel()
+		}
+
+		retry.l.Lock()
+		defer retry.l.Unlock()
+
+		if retry.canceled {
+			return
+		}
+		retry.attemptsLeft--
+		if err == nil || retry.attemptsLeft <= 0 || errors.Is(err, ErrLeaseNotIrrevocable) {
+			if current, ok := m.irrevocableRetries.Load(leaseID); ok && current == retry {
+				m.irrevocableRetries.Delete(leaseID)
+			}
+			if err != nil && !errors.Is(err, ErrLeaseNotIrrevocable) {
+				m.logger.Warn("scheduled revocation attempts of irrevocable lease exhausted", "lease_id", leaseID, "error", err)
+			}
+			return
+		}
+
+		retry.backoff *= 2
+		if retry.backoff > maxIrrevocableRetryBackoff {
+			retry.backoff = maxIrrevocableRetryBackoff
+		}
+		m.scheduleIrrevocableRetry(leaseID, retry)
+	})
+}
+
+// cancelIrrevocableRetry stops the scheduled revocation attempts of a lease
+func (m *ExpirationManager) cancelIrrevocableRetry(leaseID string) {
+	raw, ok := m.irrevocableRetries.LoadAndDelete(leaseID)
+	if !ok {
+		return
+	}
+	raw.(*irrevocableRetry).cancel()
+}
+
+func (r *irrevocableRetry) cancel() {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.canceled = true
+	r.timer.Stop()
+}
+
+// ResolveIrrevocableLease removes an irrevocable lease without revoking it
+// in its backend, once an operator has cleaned up the secret externally.
+func (m *ExpirationManager) ResolveIrrevocableLease(ctx context.Context, leaseID string) error {
+	m.cancelIrrevocableRetry(leaseID)
+
+	leaseLock := m.lockForLeaseID(leaseID)
+	leaseLock.Lock()
+	defer leaseLock.Unlock()
+
+	le, err := m.loadEntry(ctx, leaseID)
+	if err != nil {
+		return err
+	}
+	if le == nil || !le.isIrrevocable() {
+		return ErrLeaseNotIrrevocable
+	}
+
+	labels := m.irrevocableMountLabels(ctx, leaseID)
+	if err := m.removeRevokedEntry(ctx, le); err != nil {
+		return err
+	}
+
+	m.core.metricSink.IncrCounterWithLabels([]string{"expire", "irrevocable", "resolved"}, 1, labels)
+	m.logger.Info("irrevocable lease marked as resolved", "lease_id", leaseID)
+	return nil
+}
+
+// irrevocableLeaseDetails is an irrevocable lease along with its revocation
+// errors and scheduled revocation attempts
+type irrevocableLeaseDetails struct {
+	LeaseID      string              `json:"lease_id"`
+	MountID      string              `json:"mount_id"`
+	ErrMsg       string              `json:"error"`
+	ErrorHistory []*leaseRevokeError `json:"error_history"`
+	ExpireTime   time.Time           `json:"expire_time"`
+	AttemptsLeft int                 `json:"scheduled_attempts_left"`
+	NextAttempt  *time.Time          `json:"next_attempt"`
+}
+
+// listIrrevocableLeaseDetails returns the irrevocable leases of the namespace
+// of the context, and its children if requested, sorted by lease ID. Up to
+// limit leases with an ID greater than after are returned, along with whether
+// more leases exist.
+func (m *ExpirationManager) listIrrevocableLeaseDetails(ctx context.Context, includeChildNamespaces bool, after string, limit int) ([]*irrevocableLeaseDetails, bool, error) {
+	requestNS, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	matchingLeases := make([]*irrevocableLeaseDetails, 0)
+	m.irrevocable.Range(func(k, v interface{}) bool {
+		leaseID := k.(string)
+		if leaseID <= after {
+			return true
+		}
+
+		leaseNS, err := m.getNamespaceFromLeaseID(ctx, leaseID)
+		if err != nil {
+			m.logger.Warn("could not get lease namespace from ID", "error", err)
+			return true
+		}
+		if leaseNS != requestNS && !(includeChildNamespaces && leaseNS.HasParent(requestNS)) {
+			return true
+		}
+
+		leaseInfo := v.(*leaseEntry)
+		errorHistory := leaseInfo.RevokeErrHistory
+		if errorHistory == nil {
+			errorHistory = []*leaseRevokeError{}
+		}
+		lease := &irrevocableLeaseDetails{
+			LeaseID:      leaseID,
+			MountID:      m.getLeaseMountAccessor(ctx, leaseID),
+			ErrMsg:       leaseInfo.RevokeErr,
+			ErrorHistory: errorHistory,
+			ExpireTime:   leaseInfo.ExpireTime,
+		}
+		if raw, ok := m.irrevocableRetries.Load(leaseID); ok {
+			retry := raw.(*irrevocableRetry)
+			retry.l.Lock()
+			nextAttempt := retry.nextAttempt
+			lease.AttemptsLeft = retry.attemptsLeft
+			lease.NextAttempt = &nextAttempt
+			retry.l.Unlock()
+		}
+
+		matchingLeases = append(matchingLeases, lease)
+		return true
+	})
+
+	sort.Slice(matchingLeases, func(i, j int) bool {
+		return matchingLeases[i].LeaseID < matchingLeases[j].LeaseID
+	})
+
+	if len(matchingLeases) > limit {
+		return matchingLeases[:limit], true, nil
+	}
+	return matchingLeases, false, nil
+}
+
+// irrevocableLeaseGaugeCollector counts the irrevocable leases per mount
+func (m *ExpirationManager) irrevocableLeaseGaugeCollector(ctx context.Context) ([]metricsutil.GaugeLabelValues, error) {
+	type mountKey struct {
+		namespace  string
+		mountPoint string
+	}
+	counts := make(map[mountKey]int)
+	labels := make(map[mountKey][]metrics.Label)
+
+	m.irrevocable.Range(func(k, _ interface{}) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		default:
+		}
+
+		mountLabels := m.irrevocableMountLabels(ctx, k.(string))
+		key := mountKey{namespace: mountLabels[0].Value, mountPoint: mountLabels[1].Value}
+		counts[key]++
+		labels[key] = mountLabels
+		return true
+	})
+
+	select {
+	case <-ctx.Done():
+		return []metricsutil.GaugeLabelValues{}, nil
+	default:
+	}
+
+	values := make([]metricsutil.GaugeLabelValues, 0, len(counts))
+	for key, count := range counts {
+		values = append(values, metricsutil.GaugeLabelValues{
+			Labels: labels[key],
+			Value:  float32(count),
+		})
+	}
+	return values, nil
+}
@@ -0,0 +1,197 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
+)
+
+// testIrrevocableLeaseSetup mounts a backend at irrevocable/ which fails the
+// revocations while the returned flag is set, and returns the number of
+// revocation requests it received
+func testIrrevocableLeaseSetup(t *testing.T, c *Core) (*int32, *int32) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		return !c.expiration.inRestoreMode()
+	}, 10*time.Second, 50*time.Millisecond, "expiration manager is still in restore mode")
+
+	var fail, revocations int32
+	noop := &NoopBackend{
+		RequestHandler: func(ctx context.Context, req *logical.Request) (*logical.Response, error) {
+			if req.Operation != logical.RevokeOperation {
+				return nil, nil
+			}
+			atomic.AddInt32(&revocations, 1)
+			if atomic.LoadInt32(&fail) == 1 {
+				return nil, errors.New("backend unavailable")
+			}
+			return nil, nil
+		},
+	}
+	view := NewBarrierView(c.barrier, "logical/")
+	meUUID, err := uuid.GenerateUUID()
+	require.NoError(t, err)
+	err = c.router.Mount(noop, "irrevocable/", &MountEntry{Path: "irrevocable/", Type: "noop", UUID: meUUID, Accessor: "noop-accessor", namespace: namespace.RootNamespace}, view)
+	require.NoError(t, err)
+
+	return &fail, &revocations
+}
+
+func testMarkOneLeaseIrrevocable(t *testing.T, ctx context.Context, exp *ExpirationManager) string {
+	t.Helper()
+
+	leaseID := registerOneLease(t, ctx, exp)
+	le, err := exp.loadEntry(ctx, leaseID)
+	require.NoError(t, err)
+
+	exp.pendingLock.Lock()
+	exp.markLeaseIrrevocable(ctx, le, fmt.Errorf("test irrevocable error"))
+	exp.pendingLock.Unlock()
+
+	return leaseID
+}
+
+func TestExpiration_IrrevocableErrorHistory(t *testing.T) {
+	le := &leaseEntry{}
+	for i := 0; i < maxIrrevocableErrorHistory+5; i++ {
+		le.appendRevokeErr(fmt.Sprintf("error %d", i), time.Now())
+	}
+
+	require.Len(t, le.RevokeErrHistory, maxIrrevocableErrorHistory)
+	require.Equal(t, "error 5", le.RevokeErrHistory[0].Error)
+	require.Equal(t, fmt.Sprintf("error %d", maxIrrevocableErrorHistory+4), le.RevokeErr)
+}
+
+func TestExpiration_RetryIrrevocableLease(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	exp := c.expiration
+	ctx := namespace.RootContext(nil)
+	fail, revocations := testIrrevocableLeaseSetup(t, c)
+
+	leaseID := testMarkOneLeaseIrrevocable(t, ctx, exp)
+	atomic.StoreInt32(fail, 1)
+
+	// The first attempt fails, and two more are scheduled
+	require.Error(t, exp.RetryIrrevocableLease(ctx, leaseID, 3, 10*time.Millisecond))
+	require.Eventually(t, func() bool {
+		_, ok := exp.irrevocableRetries.Load(leaseID)
+		return !ok
+	}, 10*time.Second, 10*time.Millisecond)
+	require.EqualValues(t, 3, atomic.LoadInt32(revocations))
+
+	le, err := exp.loadEntry(ctx, leaseID)
+	require.NoError(t, err)
+	require.True(t, le.isIrrevocable())
+	require.Len(t, le.RevokeErrHistory, 4)
+	require.Equal(t, "test irrevocable error", le.RevokeErrHistory[0].Error)
+	require.Contains(t, le.RevokeErr, "backend unavailable")
+
+	info, ok := exp.irrevocable.Load(leaseID)
+	require.True(t, ok)
+	require.Len(t, info.(*leaseEntry).RevokeErrHistory, 4)
+
+	// Leases that aren't irrevocable can't be retried
+	require.ErrorIs(t, exp.RetryIrrevocableLease(ctx, registerOneLease(t, ctx, exp), 1, 0), ErrLeaseNotIrrevocable)
+
+	atomic.StoreInt32(fail, 0)
+	require.NoError(t, exp.RetryIrrevocableLease(ctx, leaseID, 1, 0))
+	le, err = exp.loadEntry(ctx, leaseID)
+	require.NoError(t, err)
+	require.Nil(t, le)
+	_, ok = exp.irrevocable.Load(leaseID)
+	require.False(t, ok)
+
+	// Force revoking a lease cancels its scheduled attempts
+	leaseID = testMarkOneLeaseIrrevocable(t, ctx, exp)
+	atomic.StoreInt32(fail, 1)
+	require.Error(t, exp.RetryIrrevocableLease(ctx, leaseID, 3, time.Hour))
+	_, ok = exp.irrevocableRetries.Load(leaseID)
+	require.True(t, ok)
+
+	require.NoError(t, exp.RevokeForce(ctx, leaseID))
+	_, ok = exp.irrevocableRetries.Load(leaseID)
+	require.False(t, ok)
+
+	// A lease that is gone is not reported as revoked by a retry
+	require.ErrorIs(t, exp.retryIrrevocableLease(ctx, leaseID), ErrLeaseNotIrrevocable)
+}
+
+func TestSystemBackend_IrrevocableLeases(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	exp := c.expiration
+	ctx := namespace.RootContext(nil)
+	fail, revocations := testIrrevocableLeaseSetup(t, c)
+
+	leaseIDs := []string{
+		testMarkOneLeaseIrrevocable(t, ctx, exp),
+		testMarkOneLeaseIrrevocable(t, ctx, exp),
+	}
+
+	req := logical.TestRequest(t, logical.ReadOperation, "sys/leases/irrevocable")
+	req.ClientToken = root
+	req.Data["limit"] = 1
+	resp, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	leases := resp.Data["leases"].([]*irrevocableLeaseDetails)
+	require.Len(t, leases, 1)
+	require.Equal(t, "test irrevocable error", leases[0].ErrMsg)
+	require.Len(t, leases[0].ErrorHistory, 1)
+	require.Equal(t, "noop-accessor", leases[0].MountID)
+	require.NotEmpty(t, resp.Data["next_after"])
+
+	req.Data["after"] = resp.Data["next_after"]
+	resp, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Data["leases"], 1)
+	require.NotContains(t, resp.Data, "next_after")
+
+	// A failed retry is reported and recorded
+	atomic.StoreInt32(fail, 1)
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/leases/irrevocable/retry")
+	req.ClientToken = root
+	req.Data["lease_id"] = leaseIDs[0]
+	resp, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, false, resp.Data["revoked"])
+	require.Contains(t, resp.Data["error"], "backend unavailable")
+
+	atomic.StoreInt32(fail, 0)
+	resp, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, true, resp.Data["revoked"])
+
+	// Resolving a lease doesn't call the backend
+	revocationsBefore := atomic.LoadInt32(revocations)
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/leases/irrevocable/resolve")
+	req.ClientToken = root
+	req.Data["lease_id"] = leaseIDs[1]
+	_, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, revocationsBefore, atomic.LoadInt32(revocations))
+
+	le, err := exp.loadEntry(ctx, leaseIDs[1])
+	require.NoError(t, err)
+	require.Nil(t, le)
+
+	_, err = c.HandleRequest(ctx, req)
+	require.Error(t, err)
+
+	req = logical.TestRequest(t, logical.ReadOperation, "sys/leases/irrevocable")
+	req.ClientToken = root
+	resp, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Empty(t, resp.Data["leases"])
+
+	exp.pendingLock.RLock()
+	defer exp.pendingLock.RUnlock()
+	require.Zero(t, exp.irrevocableLeaseCount)
+}
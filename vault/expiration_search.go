@@ -0,0 +1,351 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/helper/namespace"
+)
+
+const (
+	// DefaultLeaseSearchLimit is the number of leases returned by a lease
+	// search when no limit is given
+	DefaultLeaseSearchLimit = 1000
+
+	// MaxLeaseSearchLimit is the maximum number of leases returned by a single
+	// lease search
+	MaxLeaseSearchLimit = 10000
+
+	leaseRevocationStateRunning   = "running"
+	leaseRevocationStateCompleted = "completed"
+	leaseRevocationStateFailed    = "failed"
+	leaseRevocationStateCanceled  = "canceled"
+
+	// leaseRevocationMaxReportedFailures limits the number of lease IDs that
+	// failed to be revoked reported in the status of a filtered revocation
+	leaseRevocationMaxReportedFailures = 100
+)
+
+// leaseSearchFilter holds the criteria a lease must match to be returned by a
+// lease search or revoked by a filtered revocation. Zero values don't filter.
+type leaseSearchFilter struct {
+	Prefix                 string
+	EntityID               string
+	Role                   string
+	IssuedAfter            time.Time
+	IssuedBefore           time.Time
+	MinTTL                 time.Duration
+	MaxTTL                 time.Duration
+	IncludeChildNamespaces bool
+}
+
+// leaseSearchResult is the information returned for each matching lease
+type leaseSearchResult struct {
+	LeaseID    string
+	IssueTime  time.Time
+	ExpireTime time.Time
+	Role       string
+	EntityID   string
+}
+
+// leaseRevocationStatus is the progress of the last filtered revocation
+type leaseRevocationStatus struct {
+	State          string
+	StartTime      time.Time
+	EndTime        time.Time
+	LeasesMatched  int
+	LeasesRevoked  int
+	LeasesFailed   int
+	LastError      string
+	Filter         leaseSearchFilter
+	RequestNSPath  string
+	FailedLeaseIDs []string
+}
+
+// leaseRevocationTracker keeps the status of the filtered revocations running
+// in the background, by namespace ID. Only one filtered revocation can run at
+// a time in a namespace.
+type leaseRevocationTracker struct {
+	l        sync.Mutex
+	statuses map[string]*leaseRevocationStatus
+}
+
+// matchesCached reports whether the lease matches the criteria of the filter
+// that are available in memory. The entity is checked separately since it
+// requires loading the lease from storage.
+func (f *leaseSearchFilter) matchesCached(leaseID string, le *leaseEntry, now time.Time) bool {
+	if f.Prefix != "" && !strings.HasPrefix(leaseID, f.Prefix) {
+		return false
+	}
+	if f.Role != "" && le.LoginRole != f.Role {
+		return false
+	}
+	if !f.IssuedAfter.IsZero() && !le.IssueTime.After(f.IssuedAfter) {
+		return false
+	}
+	if !f.IssuedBefore.IsZero() && !le.IssueTime.Before(f.IssuedBefore) {
+		return false
+	}
+	if f.MinTTL > 0 || f.MaxTTL > 0 {
+		// Non-expiring leases have an infinite remaining TTL
+		if le.ExpireTime.IsZero() {
+			return f.MaxTTL == 0
+		}
+		ttl := le.ExpireTime.Sub(now)
+		if f.MinTTL > 0 && ttl < f.MinTTL {
+			return false
+		}
+		if f.MaxTTL > 0 && ttl > f.MaxTTL {
+			return false
+		}
+	}
+	return true
+}
+
+// leaseEntityID returns the ID of the entity the lease was issued to: the
+// entity of the token for auth leases, or the entity of the token that
+// requested the secret otherwise.
+func (m *ExpirationManager) leaseEntityID(ctx context.Context, leaseID string) (string, error) {
+	le, err := m.loadEntry(ctx, leaseID)
+	if err != nil {
+		return "", err
+	}
+	if le == nil {
+		return "", nil
+	}
+	if le.Auth != nil {
+		return le.Auth.EntityID, nil
+	}
+	if le.ClientToken == "" {
+		return "", nil
+	}
+
+	te, err := m.tokenStore.Lookup(ctx, le.ClientToken)
+	if err != nil || te == nil {
+		// The token may have been revoked since, in which case the lease is
+		// being revoked too
+		return "", nil
+	}
+	return te.EntityID, nil
+}
+
+// filterLeases returns the leases in the namespace of the context, and its
+// children if requested, that match the cached criteria of the filter, sorted
+// by lease ID.
+func (m *ExpirationManager) filterLeases(ctx context.Context, filter *leaseSearchFilter) ([]*leaseSearchResult, error) {
+	if m.inRestoreMode() {
+		return nil, ErrInRestoreMode
+	}
+
+	requestNS, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	matchingLeases := make([]*leaseSearchResult, 0)
+	callback := func(k, v interface{}) bool {
+		leaseID := k.(string)
+		le := v.(pendingInfo).cachedLeaseInfo
+		if le == nil || !filter.matchesCached(leaseID, le, now) {
+			return true
+		}
+
+		leaseNS, err := m.getNamespaceFromLeaseID(ctx, leaseID)
+		if err != nil {
+			m.logger.Warn("could not get lease namespace from ID", "error", err)
+			return true
+		}
+		if leaseNS != requestNS && !(filter.IncludeChildNamespaces && leaseNS.HasParent(requestNS)) {
+			return true
+		}
+
+		matchingLeases = append(matchingLeases, &leaseSearchResult{
+			LeaseID:    leaseID,
+			IssueTime:  le.IssueTime,
+			ExpireTime: le.ExpireTime,
+			Role:       le.LoginRole,
+		})
+		return true
+	}
+
+	m.pending.Range(callback)
+	m.nonexpiring.Range(callback)
+
+	sort.Slice(matchingLeases, func(i, j int) bool {
+		return matchingLeases[i].LeaseID < matchingLeases[j].LeaseID
+	})
+
+	return matchingLeases, nil
+}
+
+// searchLeases returns up to limit leases matching the filter with a lease ID
+// greater than after, sorted by lease ID. The returned boolean is true if
+// more leases match.
+func (m *ExpirationManager) searchLeases(ctx context.Context, filter *leaseSearchFilter, after string, limit int) ([]*leaseSearchResult, bool, error) {
+	candidates, err := m.filterLeases(ctx, filter)
+	if err != nil {
+		return nil, false, err
+	}
+
+	start := sort.Search(len(candidates), func(i int) bool {
+		return candidates[i].LeaseID > after
+	})
+
+	results := make([]*leaseSearchResult, 0)
+	for _, lease := range candidates[start:] {
+		entityID, err := m.leaseEntityID(ctx, lease.LeaseID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to load lease %q: %w", lease.LeaseID, err)
+		}
+		if filter.EntityID != "" && entityID != filter.EntityID {
+			continue
+		}
+		if len(results) >= limit {
+			return results, true, nil
+		}
+		lease.EntityID = entityID
+		results = append(results, lease)
+	}
+
+	return results, false, nil
+}
+
+// RevokeFiltered starts revoking the leases matching the filter in the
+// background. The progress is reported by FilteredRevocationStatus in the
+// namespace of the context.
+func (m *ExpirationManager) RevokeFiltered(ctx context.Context, filter *leaseSearchFilter) (*leaseRevocationStatus, error) {
+	requestNS, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.filteredRevocation.l.Lock()
+	defer m.filteredRevocation.l.Unlock()
+
+	if previous := m.filteredRevocation.statuses[requestNS.ID]; previous != nil && previous.State == leaseRevocationStateRunning {
+		return nil, errors.New("a filtered revocation is already in progress")
+	}
+
+	candidates, err := m.filterLeases(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	leaseIDs := make([]string, 0, len(candidates))
+	for _, lease := range candidates {
+		if filter.EntityID != "" {
+			entityID, err := m.leaseEntityID(ctx, lease.LeaseID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load lease %q: %w", lease.LeaseID, err)
+			}
+			if entityID != filter.EntityID {
+				continue
+			}
+		}
+		leaseIDs = append(leaseIDs, lease.LeaseID)
+	}
+
+	status := &leaseRevocationStatus{
+		State:         leaseRevocationStateRunning,
+		StartTime:     time.Now(),
+		LeasesMatched: len(leaseIDs),
+		Filter:        *filter,
+		RequestNSPath: requestNS.Path,
+	}
+	if m.filteredRevocation.statuses == nil {
+		m.filteredRevocation.statuses = make(map[string]*leaseRevocationStatus)
+	}
+	m.filteredRevocation.statuses[requestNS.ID] = status
+
+	m.logger.Info("starting filtered revocation of leases", "namespace", requestNS.Path, "leases", len(leaseIDs))
+	go m.revokeFiltered(status, leaseIDs)
+
+	return status.clone(), nil
+}
+
+// revokeFiltered revokes the given leases one at a time, updating the status
+// of the filtered revocation as it goes
+func (m *ExpirationManager) revokeFiltered(status *leaseRevocationStatus, leaseIDs []string) {
+	update := func(f func(*leaseRevocationStatus)) {
+		m.filteredRevocation.l.Lock()
+		defer m.filteredRevocation.l.Unlock()
+		f(status)
+	}
+
+	for _, leaseID := range leaseIDs {
+		select {
+		case <-m.quitCh:
+			update(func(s *leaseRevocationStatus) {
+				s.State = leaseRevocationStateCanceled
+				s.EndTime = time.Now()
+			})
+			m.logger.Info("shutting down, stopping filtered revocation of leases")
+			return
+		default:
+		}
+
+		leaseNS, err := m.getNamespaceFromLeaseID(m.quitContext, leaseID)
+		if err == nil {
+			revokeCtx, cancel := context.WithTimeout(namespace.ContextWithNamespace(m.quitContext, leaseNS), DefaultMaxRequestDuration)
+			m.coreStateLock.RLock()
+			err = m.Revoke(revokeCtx, leaseID)
+			m.coreStateLock.RUnlock()
+			cancel()
+		}
+
+		update(func(s *leaseRevocationStatus) {
+			if err != nil {
+				s.LeasesFailed++
+				s.LastError = fmt.Sprintf("failed to revoke %q: %v", leaseID, err)
+				if len(s.FailedLeaseIDs) < leaseRevocationMaxReportedFailures {
+					s.FailedLeaseIDs = append(s.FailedLeaseIDs, leaseID)
+				}
+				return
+			}
+			s.LeasesRevoked++
+		})
+		if err != nil {
+			m.logger.Error("failed to revoke lease", "lease_id", leaseID, "error", err)
+		}
+	}
+
+	update(func(s *leaseRevocationStatus) {
+		s.State = leaseRevocationStateCompleted
+		if s.LeasesFailed > 0 {
+			s.State = leaseRevocationStateFailed
+		}
+		s.EndTime = time.Now()
+	})
+	m.logger.Info("finished filtered revocation of leases", "namespace", status.RequestNSPath)
+}
+
+// FilteredRevocationStatus returns the status of the last filtered revocation
+// started in the namespace of the context, or nil if none was started since
+// the leases were restored
+func (m *ExpirationManager) FilteredRevocationStatus(ctx context.Context) (*leaseRevocationStatus, error) {
+	requestNS, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.filteredRevocation.l.Lock()
+	defer m.filteredRevocation.l.Unlock()
+
+	status := m.filteredRevocation.statuses[requestNS.ID]
+	if status == nil {
+		return nil, nil
+	}
+	return status.clone(), nil
+}
+
+func (s *leaseRevocationStatus) clone() *leaseRevocationStatus {
+	ret := *s
+	ret.FailedLeaseIDs = append([]string(nil), s.FailedLeaseIDs...)
+	return &ret
+}
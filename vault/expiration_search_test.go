@@ -0,0 +1,191 @@
+package vault
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
+)
+
+// testRegisterSearchLeases registers secret leases for two tokens, one of
+// which belongs to an entity, and returns the IDs of the leases by token
+func testRegisterSearchLeases(t *testing.T, c *Core) map[string][]string {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		return !c.expiration.inRestoreMode()
+	}, 10*time.Second, 50*time.Millisecond, "expiration manager is still in restore mode")
+
+	noop := &NoopBackend{}
+	view := NewBarrierView(c.barrier, "logical/")
+	meUUID, err := uuid.GenerateUUID()
+	require.NoError(t, err)
+	err = c.router.Mount(noop, "prod/", &MountEntry{Path: "prod/", Type: "noop", UUID: meUUID, Accessor: "noop-accessor", namespace: namespace.RootNamespace}, view)
+	require.NoError(t, err)
+
+	ctx := namespace.RootContext(nil)
+	testMakeTokenDirectly(t, c.tokenStore, &logical.TokenEntry{
+		ID:       "entity-token",
+		EntityID: "entity-1",
+		Path:     "auth/token/create",
+		Policies: []string{"default"},
+		TTL:      time.Hour,
+	})
+	testMakeTokenDirectly(t, c.tokenStore, &logical.TokenEntry{
+		ID:       "other-token",
+		Path:     "auth/token/create",
+		Policies: []string{"default"},
+		TTL:      time.Hour,
+	})
+
+	leases := make(map[string][]string)
+	for i, token := range []string{"entity-token", "entity-token", "other-token", "other-token", "other-token"} {
+		path := "prod/aws/creds"
+		ttl := time.Hour
+		role := ""
+		if i%2 == 1 {
+			path = "prod/db/creds"
+			ttl = 10 * time.Minute
+			role = "role1"
+		}
+
+		req := &logical.Request{
+			Operation:   logical.ReadOperation,
+			Path:        path,
+			ClientToken: token,
+		}
+		req.SetTokenEntry(&logical.TokenEntry{ID: token, NamespaceID: namespace.RootNamespaceID})
+		resp := &logical.Response{
+			Secret: &logical.Secret{
+				LeaseOptions: logical.LeaseOptions{
+					TTL: ttl,
+				},
+			},
+			Data: map[string]interface{}{
+				"secret": fmt.Sprintf("secret-%d", i),
+			},
+		}
+		id, err := c.expiration.Register(ctx, req, resp, role)
+		require.NoError(t, err)
+		leases[token] = append(leases[token], id)
+	}
+
+	return leases
+}
+
+func TestSystemBackend_LeaseSearch(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	leases := testRegisterSearchLeases(t, c)
+
+	search := func(data map[string]interface{}) *logical.Response {
+		t.Helper()
+		req := logical.TestRequest(t, logical.ReadOperation, "sys/leases/search")
+		req.ClientToken = root
+		req.Data = data
+		resp, err := c.HandleRequest(ctx, req)
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		return resp
+	}
+
+	resp := search(map[string]interface{}{"entity_id": "entity-1", "prefix": "prod/"})
+	require.ElementsMatch(t, leases["entity-token"], resp.Data["keys"])
+	keyInfo := resp.Data["key_info"].(map[string]interface{})
+	for _, leaseID := range leases["entity-token"] {
+		require.Equal(t, "entity-1", keyInfo[leaseID].(map[string]interface{})["entity_id"])
+	}
+
+	resp = search(map[string]interface{}{"role": "role1"})
+	require.Len(t, resp.Data["keys"], 2)
+	for _, leaseID := range resp.Data["keys"].([]string) {
+		require.Regexp(t, "^prod/db/creds/", leaseID)
+	}
+
+	resp = search(map[string]interface{}{"prefix": "prod/", "ttl_max": "30m"})
+	require.Len(t, resp.Data["keys"], 2)
+	resp = search(map[string]interface{}{"prefix": "prod/", "ttl_min": "30m"})
+	require.Len(t, resp.Data["keys"], 3)
+
+	resp = search(map[string]interface{}{"prefix": "prod/", "issued_before": time.Now().Add(-time.Hour).Format(time.RFC3339)})
+	require.Empty(t, resp.Data["keys"])
+
+	// Page through the leases two at a time
+	var found []string
+	after := ""
+	for {
+		resp = search(map[string]interface{}{"prefix": "prod/", "limit": 2, "after": after})
+		found = append(found, resp.Data["keys"].([]string)...)
+		next, ok := resp.Data["next_after"]
+		if !ok {
+			break
+		}
+		after = next.(string)
+	}
+	require.ElementsMatch(t, append(leases["entity-token"], leases["other-token"]...), found)
+	require.IsIncreasing(t, found)
+
+	req := logical.TestRequest(t, logical.ReadOperation, "sys/leases/search")
+	req.ClientToken = root
+	req.Data["ttl_min"] = "1h"
+	req.Data["ttl_max"] = "1m"
+	_, err := c.HandleRequest(ctx, req)
+	require.Error(t, err)
+}
+
+func TestSystemBackend_LeaseRevokeFiltered(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	leases := testRegisterSearchLeases(t, c)
+
+	// A filter is required
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/leases/revoke-filtered")
+	req.ClientToken = root
+	_, err := c.HandleRequest(ctx, req)
+	require.Error(t, err)
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/leases/revoke-filtered")
+	req.ClientToken = root
+	req.Data["entity_id"] = "entity-1"
+	req.Data["prefix"] = "prod/"
+	resp, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusAccepted, resp.Data[logical.HTTPStatusCode])
+
+	require.Eventually(t, func() bool {
+		req := logical.TestRequest(t, logical.ReadOperation, "sys/leases/revoke-filtered")
+		req.ClientToken = root
+		resp, err := c.HandleRequest(ctx, req)
+		require.NoError(t, err)
+		return resp.Data["state"] == leaseRevocationStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	status, err := c.expiration.FilteredRevocationStatus(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, status.LeasesMatched)
+	require.Equal(t, 2, status.LeasesRevoked)
+	require.Zero(t, status.LeasesFailed)
+
+	// The status is only visible in the namespace of the revocation
+	otherNS := &namespace.Namespace{ID: "other", Path: "other/"}
+	status, err = c.expiration.FilteredRevocationStatus(namespace.ContextWithNamespace(ctx, otherNS))
+	require.NoError(t, err)
+	require.Nil(t, status)
+
+	for token, leaseIDs := range leases {
+		for _, leaseID := range leaseIDs {
+			le, err := c.expiration.loadEntry(ctx, leaseID)
+			require.NoError(t, err)
+			if token == "entity-token" {
+				require.Nil(t, le, "expected lease %q to be revoked", leaseID)
+			} else {
+				require.NotNil(t, le, "expected lease %q not to be revoked", leaseID)
+			}
+		}
+	}
+}
@@ -1,11 +1,13 @@
 package approle
 
 import (
+	"net/http"
 	"testing"
 
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/vault/api"
 	credAppRole "github.com/hashicorp/vault/builtin/credential/approle"
+	credUserpass "github.com/hashicorp/vault/builtin/credential/userpass"
 	vaulthttp "github.com/hashicorp/vault/http"
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/hashicorp/vault/vault"
@@ -116,3 +118,143 @@ func TestApproleSecretId_NotWrapped(t *testing.T) {
 		t.Fatalf("WrappedAccessor unexpectedly set")
 	}
 }
+
+func TestApproleSecretId_WrappedBoundCIDRs(t *testing.T) {
+	coreConfig := &vault.CoreConfig{
+		DisableMlock: true,
+		DisableCache: true,
+		Logger:       log.NewNullLogger(),
+		CredentialBackends: map[string]logical.Factory{
+			"approle": credAppRole.Factory,
+		},
+	}
+
+	cluster := vault.NewTestCluster(t, coreConfig, &vault.TestClusterOptions{
+		HandlerFunc: vaulthttp.Handler,
+	})
+
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	cores := cluster.Cores
+
+	vault.TestWaitActive(t, cores[0].Core)
+
+	client := cores[0].Client
+	client.SetToken(cluster.RootToken)
+
+	err := client.Sys().EnableAuthWithOptions("approle", &api.EnableAuthOptions{
+		Type: "approle",
+	})
+	require.NoError(t, err)
+
+	_, err = client.Logical().Write("auth/approle/role/test-role-1", map[string]interface{}{
+		"name": "test-role-1",
+	})
+	require.NoError(t, err)
+
+	wrapClient, err := client.Clone()
+	require.NoError(t, err)
+	wrapClient.SetToken(cluster.RootToken)
+	wrapClient.SetWrappingLookupFunc(func(operation, path string) string {
+		return "5m"
+	})
+	// The test client connects from the loopback address
+	wrapClient.AddHeader(vaulthttp.WrapBoundCIDRsHeaderName, "10.0.0.0/8")
+
+	resp, err := wrapClient.Logical().Write("auth/approle/role/test-role-1/secret-id", map[string]interface{}{})
+	require.NoError(t, err)
+	require.NotNil(t, resp.WrapInfo)
+
+	// Unwrapping from outside of the bound CIDRs fails
+	_, err = client.Logical().Unwrap(resp.WrapInfo.Token)
+	require.Error(t, err)
+
+	wrapClient.SetHeaders(http.Header{
+		vaulthttp.WrapBoundCIDRsHeaderName: []string{"127.0.0.1/32"},
+	})
+	resp, err = wrapClient.Logical().Write("auth/approle/role/test-role-1/secret-id", map[string]interface{}{})
+	require.NoError(t, err)
+	require.NotNil(t, resp.WrapInfo)
+
+	unwrapped, err := client.Logical().Unwrap(resp.WrapInfo.Token)
+	require.NoError(t, err)
+	require.Equal(t, resp.WrapInfo.WrappedAccessor, unwrapped.Data["secret_id_accessor"])
+}
+
+func TestApproleSecretId_PushedBoundCIDRs(t *testing.T) {
+	coreConfig := &vault.CoreConfig{
+		DisableMlock: true,
+		DisableCache: true,
+		Logger:       log.NewNullLogger(),
+		CredentialBackends: map[string]logical.Factory{
+			"approle":  credAppRole.Factory,
+			"userpass": credUserpass.Factory,
+		},
+	}
+
+	cluster := vault.NewTestCluster(t, coreConfig, &vault.TestClusterOptions{
+		HandlerFunc: vaulthttp.Handler,
+	})
+
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	cores := cluster.Cores
+
+	vault.TestWaitActive(t, cores[0].Core)
+
+	client := cores[0].Client
+	client.SetToken(cluster.RootToken)
+
+	err := client.Sys().EnableAuthWithOptions("approle", &api.EnableAuthOptions{
+		Type: "approle",
+	})
+	require.NoError(t, err)
+
+	// The orchestrator logs in with userpass, so that it has an entity
+	err = client.Sys().EnableAuthWithOptions("userpass", &api.EnableAuthOptions{
+		Type: "userpass",
+	})
+	require.NoError(t, err)
+	err = client.Sys().PutPolicy("deployer", `path "auth/approle/role/test-role-1/secret-id/push" { capabilities = ["update"] }`)
+	require.NoError(t, err)
+	_, err = client.Logical().Write("auth/userpass/users/orchestrator", map[string]interface{}{
+		"password": "password",
+		"policies": "deployer",
+	})
+	require.NoError(t, err)
+	secret, err := client.Logical().Write("auth/userpass/login/orchestrator", map[string]interface{}{
+		"password": "password",
+	})
+	require.NoError(t, err)
+	orchestrator, err := client.Clone()
+	require.NoError(t, err)
+	orchestrator.SetToken(secret.Auth.ClientToken)
+
+	_, err = client.Logical().Write("auth/approle/role/test-role-1", map[string]interface{}{
+		"trusted_orchestrator_entity_ids": secret.Auth.EntityID,
+	})
+	require.NoError(t, err)
+
+	push := func(cidrList string) *api.Secret {
+		resp, err := orchestrator.Logical().Write("auth/approle/role/test-role-1/secret-id/push", map[string]interface{}{
+			"instance_id": "i-0123456789",
+			"cidr_list":   cidrList,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp.WrapInfo)
+		return resp
+	}
+
+	// The wrapping token is bound to the cidr_list of the SecretID, and the
+	// test client connects from the loopback address
+	resp := push("10.0.0.0/8")
+	_, err = client.Logical().Unwrap(resp.WrapInfo.Token)
+	require.Error(t, err)
+
+	resp = push("127.0.0.1/32")
+	unwrapped, err := client.Logical().Unwrap(resp.WrapInfo.Token)
+	require.NoError(t, err)
+	require.Equal(t, resp.WrapInfo.WrappedAccessor, unwrapped.Data["secret_id_accessor"])
+}
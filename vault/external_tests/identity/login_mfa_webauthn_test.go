@@ -0,0 +1,449 @@
+package identity
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/hashicorp/vault/api"
+	upAuth "github.com/hashicorp/vault/api/auth/userpass"
+	"github.com/hashicorp/vault/builtin/credential/userpass"
+	"github.com/hashicorp/vault/helper/testhelpers"
+	vaulthttp "github.com/hashicorp/vault/http"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testWebAuthnRPID   = "vault.example.com"
+	testWebAuthnOrigin = "https://vault.example.com:8200"
+)
+
+// softAuthenticator is a software stand-in for a WebAuthn authenticator. It
+// holds a single ES256 credential and optionally signs its attestations with
+// an attestation certificate.
+type softAuthenticator struct {
+	t            *testing.T
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	signCount    uint32
+	origin       string
+
+	attestationKey  *ecdsa.PrivateKey
+	attestationCert []byte
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	credentialID := make([]byte, 16)
+	_, err = rand.Read(credentialID)
+	require.NoError(t, err)
+
+	return &softAuthenticator{
+		t:            t,
+		key:          key,
+		credentialID: credentialID,
+		origin:       testWebAuthnOrigin,
+	}
+}
+
+func (a *softAuthenticator) clientData(typ, challenge string) []byte {
+	clientData, err := json.Marshal(map[string]interface{}{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    a.origin,
+	})
+	require.NoError(a.t, err)
+	return clientData
+}
+
+func (a *softAuthenticator) authData(rpID string, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	flags := byte(0x01 | 0x04)
+	if attested {
+		flags |= 0x40
+	}
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if !attested {
+		return data
+	}
+
+	data = append(data, make([]byte, 16)...)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+	data = append(data, a.credentialID...)
+	publicKey, err := cbor.Marshal(map[int]interface{}{
+		1:  2,
+		3:  -7,
+		-1: 1,
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(a.t, err)
+	return append(data, publicKey...)
+}
+
+func (a *softAuthenticator) sign(key *ecdsa.PrivateKey, authData, clientData []byte) []byte {
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(a.t, err)
+	return sig
+}
+
+// create returns the JSON-serialized credential for the creation options
+// returned by the register endpoint.
+func (a *softAuthenticator) create(options map[string]interface{}) string {
+	rp := options["rp"].(map[string]interface{})
+	clientData := a.clientData("webauthn.create", options["challenge"].(string))
+	authData := a.authData(rp["id"].(string), true)
+
+	format := "none"
+	attStmt := map[string]interface{}{}
+	if a.attestationKey != nil {
+		format = "packed"
+		attStmt = map[string]interface{}{
+			"alg": -7,
+			"sig": a.sign(a.attestationKey, authData, clientData),
+			"x5c": [][]byte{a.attestationCert},
+		}
+	}
+	attestationObject, err := cbor.Marshal(map[string]interface{}{
+		"fmt":      format,
+		"attStmt":  attStmt,
+		"authData": authData,
+	})
+	require.NoError(a.t, err)
+
+	return a.credentialJSON(map[string]interface{}{
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+		"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+	})
+}
+
+// get returns the JSON-serialized assertion for the options returned by the
+// sys/mfa/webauthn/options endpoint.
+func (a *softAuthenticator) get(options map[string]interface{}) string {
+	a.signCount++
+	clientData := a.clientData("webauthn.get", options["challenge"].(string))
+	authData := a.authData(options["rpId"].(string), false)
+
+	return a.credentialJSON(map[string]interface{}{
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+		"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+		"signature":         base64.RawURLEncoding.EncodeToString(a.sign(a.key, authData, clientData)),
+	})
+}
+
+func (a *softAuthenticator) credentialJSON(response map[string]interface{}) string {
+	id := base64.RawURLEncoding.EncodeToString(a.credentialID)
+	credential, err := json.Marshal(map[string]interface{}{
+		"id":       id,
+		"rawId":    id,
+		"type":     "public-key",
+		"response": response,
+	})
+	require.NoError(a.t, err)
+	return string(credential)
+}
+
+func setupWebAuthnTestCluster(t *testing.T) (*vault.TestCluster, *api.Client, string) {
+	t.Helper()
+	cluster := vault.NewTestCluster(t, &vault.CoreConfig{
+		CredentialBackends: map[string]logical.Factory{
+			"userpass": userpass.Factory,
+		},
+	}, &vault.TestClusterOptions{
+		HandlerFunc: vaulthttp.Handler,
+	})
+	cluster.Start()
+
+	client := cluster.Cores[0].Client
+	mountAccessor := testhelpers.SetupUserpassMountAccessor(t, client)
+
+	// Registration isn't part of the default policy, so users are granted
+	// access to it explicitly
+	err := client.Sys().PutPolicy("webauthn-register", `path "identity/mfa/method/webauthn/register" { capabilities = ["update"] }`)
+	require.NoError(t, err)
+
+	return cluster, client, mountAccessor
+}
+
+// createWebAuthnUser creates a userpass user whose entity may register
+// WebAuthn credentials.
+func createWebAuthnUser(t *testing.T, client *api.Client, mountAccessor, username string) string {
+	t.Helper()
+	_, entityID, _ := testhelpers.CreateEntityAndAlias(t, client, mountAccessor, "entity-"+username, username)
+	_, err := client.Logical().Write("identity/entity/id/"+entityID, map[string]interface{}{
+		"policies": "webauthn-register",
+	})
+	require.NoError(t, err)
+	return entityID
+}
+
+// registerWebAuthn logs in as the user and registers the authenticator's
+// credential with the method.
+func registerWebAuthn(t *testing.T, client *api.Client, username, methodID string, authenticator *softAuthenticator) (*api.Secret, error) {
+	t.Helper()
+	userClient, err := client.Clone()
+	require.NoError(t, err)
+	secret, err := userClient.Logical().Write("auth/userpass/login/"+username, map[string]interface{}{
+		"password": "testpassword",
+	})
+	require.NoError(t, err)
+	userClient.SetToken(secret.Auth.ClientToken)
+
+	secret, err = userClient.Logical().Write("identity/mfa/method/webauthn/register", map[string]interface{}{
+		"method_id": methodID,
+	})
+	require.NoError(t, err)
+	options := secret.Data["options"].(map[string]interface{})
+	require.Equal(t, testWebAuthnRPID, options["rp"].(map[string]interface{})["id"])
+
+	return userClient.Logical().Write("identity/mfa/method/webauthn/register", map[string]interface{}{
+		"method_id":  methodID,
+		"credential": authenticator.create(options),
+		"name":       "soft key",
+	})
+}
+
+// webAuthnLogin logs in as the user and returns the MFA requirement and the
+// assertion options of the method. Since a successful validation replaces the
+// token of the client, a dedicated client should be used.
+func webAuthnLogin(t *testing.T, client *api.Client, username, methodID string) (*api.Secret, map[string]interface{}) {
+	t.Helper()
+	upMethod, err := upAuth.NewUserpassAuth(username, &upAuth.Password{FromString: "testpassword"})
+	require.NoError(t, err)
+	mfaSecret, err := client.Auth().MFALogin(context.Background(), upMethod)
+	require.NoError(t, err)
+	require.NotNil(t, mfaSecret.Auth.MFARequirement)
+
+	// The options endpoint is used before the login completes, so it is
+	// requested without a token
+	anonClient, err := client.Clone()
+	require.NoError(t, err)
+	anonClient.ClearToken()
+	secret, err := anonClient.Logical().Write("sys/mfa/webauthn/options", map[string]interface{}{
+		"mfa_request_id": mfaSecret.Auth.MFARequirement.MFARequestID,
+		"method_id":      methodID,
+	})
+	require.NoError(t, err)
+
+	return mfaSecret, secret.Data["options"].(map[string]interface{})
+}
+
+func TestLoginMFA_WebAuthn(t *testing.T) {
+	cluster, client, mountAccessor := setupWebAuthnTestCluster(t)
+	defer cluster.Cleanup()
+
+	entityID := createWebAuthnUser(t, client, mountAccessor, "testuser1")
+
+	secret, err := client.Logical().Write("identity/mfa/method/webauthn", map[string]interface{}{
+		"rp_id":             testWebAuthnRPID,
+		"allowed_origins":   testWebAuthnOrigin,
+		"user_verification": "required",
+	})
+	require.NoError(t, err)
+	methodID := secret.Data["method_id"].(string)
+
+	secret, err = client.Logical().Read("identity/mfa/method/webauthn/" + methodID)
+	require.NoError(t, err)
+	require.Equal(t, "none", secret.Data["attestation"])
+	require.Equal(t, "Vault", secret.Data["rp_name"])
+
+	authenticator := newSoftAuthenticator(t)
+
+	// A credential created for another origin is rejected
+	authenticator.origin = "https://evil.example.com"
+	_, err = registerWebAuthn(t, client, "testuser1", methodID, authenticator)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not allowed")
+	authenticator.origin = testWebAuthnOrigin
+
+	secret, err = registerWebAuthn(t, client, "testuser1", methodID, authenticator)
+	require.NoError(t, err)
+	require.Equal(t, base64.RawURLEncoding.EncodeToString(authenticator.credentialID), secret.Data["credential_id"])
+
+	testhelpers.SetupMFALoginEnforcement(t, client, map[string]interface{}{
+		"name":              "webauthn",
+		"auth_method_types": []string{"userpass"},
+		"mfa_method_ids":    []string{methodID},
+	})
+
+	loginClient, err := client.Clone()
+	require.NoError(t, err)
+
+	mfaSecret, options := webAuthnLogin(t, loginClient, "testuser1", methodID)
+	require.Equal(t, testWebAuthnRPID, options["rpId"])
+	allowCredentials := options["allowCredentials"].([]interface{})
+	require.Len(t, allowCredentials, 1)
+
+	assertion := authenticator.get(options)
+	secret, err = loginClient.Auth().MFAValidate(context.Background(), mfaSecret, map[string]interface{}{
+		methodID: []string{assertion},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, secret.Auth.ClientToken)
+	require.Equal(t, entityID, secret.Auth.EntityID)
+
+	// The assertion is bound to the MFA request, so it can't be replayed for
+	// another login
+	mfaSecret, _ = webAuthnLogin(t, loginClient, "testuser1", methodID)
+	_, err = loginClient.Auth().MFAValidate(context.Background(), mfaSecret, map[string]interface{}{
+		methodID: []string{assertion},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "challenge does not match")
+
+	// The challenge is random and can only be used for a single attempt
+	mfaSecret, options = webAuthnLogin(t, loginClient, "testuser1", methodID)
+	require.NotEqual(t, base64.RawURLEncoding.EncodeToString([]byte(mfaSecret.Auth.MFARequirement.MFARequestID)), options["challenge"])
+	_, err = loginClient.Auth().MFAValidate(context.Background(), mfaSecret, map[string]interface{}{
+		methodID: []string{"{}"},
+	})
+	require.Error(t, err)
+	_, err = loginClient.Auth().MFAValidate(context.Background(), mfaSecret, map[string]interface{}{
+		methodID: []string{assertion},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no WebAuthn challenge")
+
+	// A signature counter that doesn't increase indicates a cloned
+	// authenticator
+	mfaSecret, options = webAuthnLogin(t, loginClient, "testuser1", methodID)
+	authenticator.signCount--
+	_, err = loginClient.Auth().MFAValidate(context.Background(), mfaSecret, map[string]interface{}{
+		methodID: []string{authenticator.get(options)},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "signature counter")
+
+	// Another key can't be used for the registered credential
+	mfaSecret, options = webAuthnLogin(t, loginClient, "testuser1", methodID)
+	authenticator.signCount += 5
+	registeredKey := authenticator.key
+	authenticator.key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, err = loginClient.Auth().MFAValidate(context.Background(), mfaSecret, map[string]interface{}{
+		methodID: []string{authenticator.get(options)},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid signature")
+	authenticator.key = registeredKey
+
+	// Removing the credential leaves the entity unable to satisfy the MFA
+	_, err = client.Logical().Write("identity/mfa/method/webauthn/admin-destroy", map[string]interface{}{
+		"method_id":     methodID,
+		"entity_id":     entityID,
+		"credential_id": base64.RawURLEncoding.EncodeToString(authenticator.credentialID),
+	})
+	require.NoError(t, err)
+	mfaSecret, options = webAuthnLogin(t, loginClient, "testuser1", methodID)
+	require.Empty(t, options["allowCredentials"])
+	_, err = loginClient.Auth().MFAValidate(context.Background(), mfaSecret, map[string]interface{}{
+		methodID: []string{authenticator.get(options)},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not registered")
+}
+
+func TestLoginMFA_WebAuthn_DirectAttestation(t *testing.T) {
+	cluster, client, mountAccessor := setupWebAuthnTestCluster(t)
+	defer cluster.Cleanup()
+
+	createWebAuthnUser(t, client, mountAccessor, "testuser1")
+
+	// Create an attestation CA and an attestation certificate issued by it
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Attestation CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	attestationKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	attestationDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "Test Authenticator Attestation"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, caCert, &attestationKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	_, err = client.Logical().Write("identity/mfa/method/webauthn", map[string]interface{}{
+		"rp_id":           testWebAuthnRPID,
+		"allowed_origins": testWebAuthnOrigin,
+		"attestation":     "direct",
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "attestation_ca_pem must be set")
+
+	secret, err := client.Logical().Write("identity/mfa/method/webauthn", map[string]interface{}{
+		"rp_id":              testWebAuthnRPID,
+		"allowed_origins":    testWebAuthnOrigin,
+		"attestation":        "direct",
+		"attestation_ca_pem": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})),
+	})
+	require.NoError(t, err)
+	methodID := secret.Data["method_id"].(string)
+
+	// Authenticators without a trusted attestation are rejected
+	_, err = registerWebAuthn(t, client, "testuser1", methodID, newSoftAuthenticator(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "does not contain a certificate")
+
+	untrustedKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	untrustedTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "Untrusted Attestation"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	untrustedDER, err := x509.CreateCertificate(rand.Reader, untrustedTemplate, untrustedTemplate, &untrustedKey.PublicKey, untrustedKey)
+	require.NoError(t, err)
+	authenticator := newSoftAuthenticator(t)
+	authenticator.attestationKey = untrustedKey
+	authenticator.attestationCert = untrustedDER
+	_, err = registerWebAuthn(t, client, "testuser1", methodID, authenticator)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not trusted")
+
+	authenticator.attestationKey = attestationKey
+	authenticator.attestationCert = attestationDER
+	secret, err = registerWebAuthn(t, client, "testuser1", methodID, authenticator)
+	require.NoError(t, err)
+	require.Equal(t, "packed", secret.Data["attestation_format"])
+
+	// The same credential can't be registered twice
+	_, err = registerWebAuthn(t, client, "testuser1", methodID, authenticator)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "already registered"))
+}
@@ -2,6 +2,7 @@ package identity
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -947,6 +948,168 @@ func TestOIDC_Auth_Code_Flow_Public_CAP_Client(t *testing.T) {
 	}
 }
 
+// TestOIDC_Client_Credentials_Revoke_Introspect tests that clients can use the
+// client credentials grant, and the revocation and introspection endpoints,
+// without a Vault token.
+func TestOIDC_Client_Credentials_Revoke_Introspect(t *testing.T) {
+	cluster := setupOIDCTestCluster(t, 2)
+	defer cluster.Cleanup()
+	active := cluster.Cores[0].Client
+	standby := cluster.Cores[1].Client
+
+	_, err := active.Logical().Write("identity/oidc/client/machine", map[string]interface{}{
+		"key":                      "default",
+		"allow_client_credentials": true,
+	})
+	require.NoError(t, err)
+	resp, err := active.Logical().Read("identity/oidc/client/machine")
+	require.NoError(t, err)
+	clientID := resp.Data["client_id"].(string)
+	clientSecret := resp.Data["client_secret"].(string)
+
+	_, err = active.Logical().Write("identity/oidc/provider/test-provider", map[string]interface{}{
+		"allowed_client_ids": []string{clientID},
+	})
+	require.NoError(t, err)
+
+	clientRequest := func(client *api.Client, endpoint string, body map[string]interface{}, v interface{}) {
+		t.Helper()
+		client, err := client.Clone()
+		require.NoError(t, err)
+		client.ClearToken()
+
+		req := client.NewRequest(http.MethodPost, "/v1/identity/oidc/provider/test-provider/"+endpoint)
+		req.Headers.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(clientID+":"+clientSecret)))
+		require.NoError(t, req.SetJSONBody(body))
+		r, err := client.RawRequest(req)
+		require.NoError(t, err)
+		defer r.Body.Close()
+		require.Equal(t, http.StatusOK, r.StatusCode)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(v))
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+	}
+	clientRequest(active, "token", map[string]interface{}{
+		"grant_type": "client_credentials",
+	}, &tokenResp)
+	require.NotEmpty(t, tokenResp.AccessToken)
+
+	var introspectResp struct {
+		Active   bool   `json:"active"`
+		ClientID string `json:"client_id"`
+	}
+	clientRequest(standby, "introspect", map[string]interface{}{
+		"token": tokenResp.AccessToken,
+	}, &introspectResp)
+	require.True(t, introspectResp.Active)
+	require.Equal(t, clientID, introspectResp.ClientID)
+
+	var revokeResp map[string]interface{}
+	clientRequest(standby, "revoke", map[string]interface{}{
+		"token": tokenResp.AccessToken,
+	}, &revokeResp)
+
+	introspectResp.Active = true
+	clientRequest(active, "introspect", map[string]interface{}{
+		"token": tokenResp.AccessToken,
+	}, &introspectResp)
+	require.False(t, introspectResp.Active)
+}
+
+// TestOIDC_Device_Authorization_Flow tests the device authorization grant
+// through the HTTP API. The device and token endpoints are requested without
+// a Vault token, and the end-user approves the request on a standby.
+func TestOIDC_Device_Authorization_Flow(t *testing.T) {
+	cluster := setupOIDCTestCluster(t, 2)
+	defer cluster.Cleanup()
+	active := cluster.Cores[0].Client
+	standby := cluster.Cores[1].Client
+
+	// Enable userpass auth and create a user
+	err := active.Sys().EnableAuthWithOptions("userpass", &api.EnableAuthOptions{
+		Type: "userpass",
+	})
+	require.NoError(t, err)
+	_, err = active.Logical().Write("auth/userpass/users/end-user", map[string]interface{}{
+		"password": testPassword,
+	})
+	require.NoError(t, err)
+
+	_, err = active.Logical().Write("identity/oidc/client/device", map[string]interface{}{
+		"assignments": []string{"allow_all"},
+	})
+	require.NoError(t, err)
+	resp, err := active.Logical().Read("identity/oidc/client/device")
+	require.NoError(t, err)
+	clientID := resp.Data["client_id"].(string)
+	clientSecret := resp.Data["client_secret"].(string)
+
+	deviceRequest := func(client *api.Client, endpoint string, body map[string]interface{}, v interface{}) int {
+		t.Helper()
+		client, err := client.Clone()
+		require.NoError(t, err)
+		client.ClearToken()
+
+		req := client.NewRequest(http.MethodPost, "/v1/identity/oidc/provider/default/"+endpoint)
+		req.Headers.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(clientID+":"+clientSecret)))
+		require.NoError(t, req.SetJSONBody(body))
+		r, _ := client.RawRequest(req)
+		require.NotNil(t, r)
+		defer r.Body.Close()
+		require.NoError(t, json.NewDecoder(r.Body).Decode(v))
+		return r.StatusCode
+	}
+
+	var deviceResp struct {
+		DeviceCode string `json:"device_code"`
+		UserCode   string `json:"user_code"`
+	}
+	status := deviceRequest(standby, "device_authorization", map[string]interface{}{
+		"scope": "openid",
+	}, &deviceResp)
+	require.Equal(t, http.StatusOK, status)
+	require.NotEmpty(t, deviceResp.DeviceCode)
+	require.NotEmpty(t, deviceResp.UserCode)
+
+	tokenReq := map[string]interface{}{
+		"grant_type":  "urn:ietf:params:oauth:grant-type:device_code",
+		"device_code": deviceResp.DeviceCode,
+	}
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	status = deviceRequest(active, "token", tokenReq, &errResp)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "authorization_pending", errResp.Error)
+
+	// Approve the request as the end-user
+	resp, err = active.Logical().Write("auth/userpass/login/end-user", map[string]interface{}{
+		"password": testPassword,
+	})
+	require.NoError(t, err)
+	endUser, err := standby.Clone()
+	require.NoError(t, err)
+	endUser.SetToken(resp.Auth.ClientToken)
+	_, err = endUser.Logical().Write("identity/oidc/provider/default/device", map[string]interface{}{
+		"user_code": deviceResp.UserCode,
+	})
+	require.NoError(t, err)
+
+	// Wait out the polling interval to avoid a slow_down error
+	time.Sleep(5 * time.Second)
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		IDToken     string `json:"id_token"`
+	}
+	status = deviceRequest(active, "token", tokenReq, &tokenResp)
+	require.Equal(t, http.StatusOK, status)
+	require.NotEmpty(t, tokenResp.AccessToken)
+	require.NotEmpty(t, tokenResp.IDToken)
+}
+
 func setupOIDCTestCluster(t *testing.T, numCores int) *vault.TestCluster {
 	t.Helper()
 
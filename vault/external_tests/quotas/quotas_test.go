@@ -2,6 +2,7 @@ package quotas
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/hashicorp/vault/builtin/logical/pki"
 	"github.com/hashicorp/vault/helper/testhelpers/teststorage"
 	"github.com/hashicorp/vault/vault"
+	"github.com/hashicorp/vault/vault/quotas"
 	"go.uber.org/atomic"
 )
 
@@ -505,3 +507,167 @@ func TestQuotas_RateLimitQuota(t *testing.T) {
 		t.Fatalf("unexpected number of failed requests: %d", numFail)
 	}
 }
+
+func TestQuotas_RequestSizeQuota(t *testing.T) {
+	conf, opts := teststorage.ClusterSetup(coreConfig, nil, nil)
+	opts.NoDefaultQuotas = true
+	cluster := vault.NewTestCluster(t, conf, opts)
+	cluster.Start()
+	defer cluster.Cleanup()
+	core := cluster.Cores[0].Core
+	client := cluster.Cores[0].Client
+	vault.TestWaitActive(t, core)
+
+	err := client.Sys().Mount("kv", &api.MountInput{
+		Type: "kv",
+	})
+	require.NoError(t, err)
+
+	_, err = client.Logical().Write("sys/quotas/request-size/kv-rsq", map[string]interface{}{
+		"path":             "kv/",
+		"max_request_size": 512,
+	})
+	require.NoError(t, err)
+
+	s, err := client.Logical().Read("sys/quotas/request-size/kv-rsq")
+	require.NoError(t, err)
+	require.Equal(t, "kv/", s.Data["path"])
+
+	_, err = client.Logical().Write("kv/small", map[string]interface{}{
+		"value": "foo",
+	})
+	require.NoError(t, err)
+
+	_, err = client.Logical().Write("kv/large", map[string]interface{}{
+		"value": strings.Repeat("a", 1024),
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), quotas.ErrRequestSizeQuotaExceeded.Error())
+
+	// requests to other mounts are not affected by the quota
+	_, err = client.Logical().Write("secret/large", map[string]interface{}{
+		"value": strings.Repeat("a", 1024),
+	})
+	require.NoError(t, err)
+
+	_, err = client.Logical().Delete("sys/quotas/request-size/kv-rsq")
+	require.NoError(t, err)
+
+	_, err = client.Logical().Write("kv/large", map[string]interface{}{
+		"value": strings.Repeat("a", 1024),
+	})
+	require.NoError(t, err)
+}
+
+func TestQuotas_ConcurrencyQuota(t *testing.T) {
+	conf, opts := teststorage.ClusterSetup(coreConfig, nil, nil)
+	opts.NoDefaultQuotas = true
+	cluster := vault.NewTestCluster(t, conf, opts)
+	cluster.Start()
+	defer cluster.Cleanup()
+	core := cluster.Cores[0].Core
+	client := cluster.Cores[0].Client
+	vault.TestWaitActive(t, core)
+
+	_, err := client.Logical().Write("sys/quotas/concurrency/global-cq", map[string]interface{}{
+		"max_requests": 0,
+	})
+	require.Error(t, err)
+
+	_, err = client.Logical().Write("sys/quotas/concurrency/global-cq", map[string]interface{}{
+		"max_requests": 1,
+		"per_client":   true,
+	})
+	require.NoError(t, err)
+
+	s, err := client.Logical().Read("sys/quotas/concurrency/global-cq")
+	require.NoError(t, err)
+	require.Equal(t, true, s.Data["per_client"])
+
+	// Sequential requests never exceed the quota as every request releases its
+	// in-flight slot once it completes.
+	for i := 0; i < 10; i++ {
+		_, err = client.Logical().Read("sys/quotas/concurrency/global-cq")
+		require.NoError(t, err)
+	}
+
+	s, err = client.Logical().List("sys/quotas/concurrency")
+	require.NoError(t, err)
+	require.Len(t, s.Data["keys"], 1)
+}
+
+func TestQuotas_RateLimitQuota_ClientKey(t *testing.T) {
+	conf, opts := teststorage.ClusterSetup(coreConfig, nil, nil)
+	opts.NoDefaultQuotas = true
+	cluster := vault.NewTestCluster(t, conf, opts)
+	cluster.Start()
+	defer cluster.Cleanup()
+	core := cluster.Cores[0].Core
+	client := cluster.Cores[0].Client
+	vault.TestWaitActive(t, core)
+
+	_, err := client.Logical().Write("sys/quotas/rate-limit/rlq", map[string]interface{}{
+		"rate":       1,
+		"interval":   "1m",
+		"path":       "auth/token/lookup-self",
+		"client_key": "bogus",
+	})
+	require.Error(t, err)
+
+	_, err = client.Logical().Write("sys/quotas/rate-limit/rlq", map[string]interface{}{
+		"rate":       1,
+		"interval":   "1m",
+		"path":       "auth/token/lookup-self",
+		"client_key": "token_accessor",
+	})
+	require.NoError(t, err)
+
+	// Updating the quota without a client key keeps the configured one
+	_, err = client.Logical().Write("sys/quotas/rate-limit/rlq", map[string]interface{}{
+		"rate":     1,
+		"interval": "1m",
+		"path":     "auth/token/lookup-self",
+	})
+	require.NoError(t, err)
+
+	s, err := client.Logical().Read("sys/quotas/rate-limit/rlq")
+	require.NoError(t, err)
+	require.Equal(t, "token_accessor", s.Data["client_key"])
+
+	secret, err := client.Auth().Token().Create(&api.TokenCreateRequest{})
+	require.NoError(t, err)
+	otherClient, err := client.Clone()
+	require.NoError(t, err)
+	otherClient.SetToken(secret.Auth.ClientToken)
+
+	// Both tokens come from the same address but each gets its own bucket
+	_, err = client.Auth().Token().LookupSelf()
+	require.NoError(t, err)
+	_, err = client.Auth().Token().LookupSelf()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), quotas.ErrRateLimitQuotaExceeded.Error())
+
+	_, err = otherClient.Auth().Token().LookupSelf()
+	require.NoError(t, err)
+
+	s, err = client.Logical().Read("sys/quotas/rate-limit/rlq/usage")
+	require.NoError(t, err)
+	clients := s.Data["clients"].(map[string]interface{})
+	require.Len(t, clients, 2)
+	require.Contains(t, clients, "token_accessor:"+secret.Auth.Accessor)
+
+	// The token is resolved again when the request is handled, so a token
+	// used up by its last request is rejected even though the quota
+	// identified the client
+	secret, err = client.Auth().Token().Create(&api.TokenCreateRequest{
+		NumUses: 1,
+	})
+	require.NoError(t, err)
+	otherClient.SetToken(secret.Auth.ClientToken)
+
+	_, err = otherClient.Auth().Token().LookupSelf()
+	require.NoError(t, err)
+	_, err = otherClient.Auth().Token().LookupSelf()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "permission denied")
+}
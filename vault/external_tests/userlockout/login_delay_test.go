@@ -0,0 +1,111 @@
+package userlockout
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/hashicorp/vault/builtin/credential/userpass"
+	vaulthttp "github.com/hashicorp/vault/http"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault"
+)
+
+func TestLoginDelay_Userpass(t *testing.T) {
+	cluster := vault.NewTestCluster(t, &vault.CoreConfig{
+		CredentialBackends: map[string]logical.Factory{
+			"userpass": userpass.Factory,
+		},
+	}, &vault.TestClusterOptions{
+		HandlerFunc: vaulthttp.Handler,
+	})
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	client := cluster.Cores[0].Client
+	vault.TestWaitActive(t, cluster.Cores[0].Core)
+
+	if err := client.Sys().EnableAuthWithOptions("userpass", &api.EnableAuthOptions{Type: "userpass"}); err != nil {
+		t.Fatal(err)
+	}
+	progressiveDelay := true
+	err := client.Sys().TuneMount("auth/userpass", api.MountConfigInput{
+		UserLockoutConfig: &api.UserLockoutConfigInput{
+			LockoutThreshold:     "2",
+			ProgressiveDelay:     &progressiveDelay,
+			ProgressiveDelayBase: "2s",
+			ProgressiveDelayMax:  "1m",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Logical().Write("auth/userpass/users/bob", map[string]interface{}{
+		"password": "secret",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	login := func(password string) error {
+		loginClient, err := client.Clone()
+		if err != nil {
+			t.Fatal(err)
+		}
+		loginClient.ClearToken()
+		_, err = loginClient.Logical().Write("auth/userpass/login/bob", map[string]interface{}{
+			"password": password,
+		})
+		return err
+	}
+
+	// The first failure is below the threshold and does not delay the next
+	// attempt; the second reaches it.
+	for i := 0; i < 2; i++ {
+		err := login("wrong")
+		if err == nil || strings.Contains(err.Error(), "too many failed login attempts") {
+			t.Fatalf("expected invalid credentials, got %v", err)
+		}
+	}
+
+	// Even the correct password is rejected while the delay is in effect
+	err = login("secret")
+	if err == nil || !strings.Contains(err.Error(), "too many failed login attempts") {
+		t.Fatalf("expected login to be delayed, got %v", err)
+	}
+	if respErr, ok := err.(*api.ResponseError); !ok || respErr.StatusCode != 429 {
+		t.Fatalf("expected a 429 response, got %v", err)
+	}
+
+	resp, err := client.Logical().Read("sys/delayed-users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mounts := resp.Data["by_mount_accessor"].([]interface{})
+	if len(mounts) != 1 {
+		t.Fatalf("expected one mount with delayed users, got %#v", resp.Data)
+	}
+	users := mounts[0].(map[string]interface{})["users"].([]interface{})
+	if len(users) != 1 || users[0].(map[string]interface{})["alias_name"] != "bob" {
+		t.Fatalf("expected bob to be delayed, got %#v", users)
+	}
+	if mounts[0].(map[string]interface{})["mount_path"] != "auth/userpass/" {
+		t.Fatalf("unexpected mount path: %#v", mounts[0])
+	}
+
+	// Once the delay has passed, a successful login resets the user's counter
+	time.Sleep(2 * time.Second)
+	if err := login("secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err = client.Logical().Read("sys/delayed-users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mount := range resp.Data["by_mount_accessor"].([]interface{}) {
+		if users := mount.(map[string]interface{})["users"].([]interface{}); len(users) != 0 {
+			t.Fatalf("expected no delayed users after a successful login, got %#v", users)
+		}
+	}
+}
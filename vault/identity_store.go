@@ -103,6 +103,9 @@ func NewIdentityStore(ctx context.Context, core *Core, config *logical.BackendCo
 				"oidc/.well-known/*",
 				"oidc/provider/+/.well-known/*",
 				"oidc/provider/+/token",
+				"oidc/provider/+/revoke",
+				"oidc/provider/+/introspect",
+				"oidc/provider/+/device_authorization",
 			},
 			LocalStorage: []string{
 				localAliasesBucketsPrefix,
@@ -110,6 +113,7 @@ func NewIdentityStore(ctx context.Context, core *Core, config *logical.BackendCo
 		},
 		PeriodicFunc: func(ctx context.Context, req *logical.Request) error {
 			iStore.oidcPeriodicFunc(ctx)
+			iStore.entityMergePeriodicFunc(ctx)
 
 			return nil
 		},
@@ -118,6 +122,9 @@ func NewIdentityStore(ctx context.Context, core *Core, config *logical.BackendCo
 
 	iStore.oidcCache = newOIDCCache(cache.NoExpiration, cache.NoExpiration)
 	iStore.oidcAuthCodeCache = newOIDCCache(5*time.Minute, 5*time.Minute)
+	// Device codes are cached past their expiry so that polling devices can be
+	// told that they expired
+	iStore.oidcDeviceCodeCache = newOIDCCache(2*deviceCodeTTL, deviceCodeTTL)
 
 	err = iStore.Setup(ctx, config)
 	if err != nil {
@@ -138,6 +145,8 @@ func (i *IdentityStore) paths() []*framework.Path {
 		oidcPaths(i),
 		oidcProviderPaths(i),
 		mfaPaths(i),
+		scimPaths(i),
+		entityMergeSuggestionPaths(i),
 	)
 }
 
@@ -447,6 +456,119 @@ func mfaPaths(i *IdentityStore) []*framework.Path {
 				},
 			},
 		},
+		{
+			Pattern: "mfa/method/webauthn" + genericOptionalUUIDRegex("method_id"),
+			Fields: map[string]*framework.FieldSchema{
+				"method_id": {
+					Type:        framework.TypeString,
+					Description: `The unique identifier for this MFA method.`,
+				},
+				"rp_id": {
+					Type:        framework.TypeString,
+					Description: `The relying party ID, which is the domain of the origins that credentials are used on.`,
+				},
+				"rp_name": {
+					Type:        framework.TypeString,
+					Default:     "Vault",
+					Description: `The relying party name displayed by authenticators.`,
+				},
+				"allowed_origins": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: `The origins, such as "https://vault.example.com:8200", that WebAuthn ceremonies are allowed from.`,
+				},
+				"attestation": {
+					Type:        framework.TypeString,
+					Default:     webAuthnAttestationNone,
+					Description: `The attestation required when registering credentials. Options include "none" and "direct". With "direct", the attestation must chain to a certificate in attestation_ca_pem.`,
+				},
+				"attestation_ca_pem": {
+					Type:        framework.TypeString,
+					Description: `PEM-encoded CA certificates that authenticator attestations must chain to. Required if attestation is "direct".`,
+				},
+				"user_verification": {
+					Type:        framework.TypeString,
+					Default:     webAuthnUserVerificationPreferred,
+					Description: `The user verification requirement. Options include "required", "preferred" and "discouraged". Only "required" is enforced.`,
+				},
+				"timeout": {
+					Type:        framework.TypeDurationSecond,
+					Default:     int(webAuthnDefaultTimeout.Seconds()),
+					Description: `The time allowed to complete a WebAuthn ceremony.`,
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: i.handleMFAMethodWebAuthnRead,
+					Summary:  "Read the current configuration for the given MFA method",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: i.handleMFAMethodWebAuthnUpdate,
+					Summary:  "Update or create a configuration for the given MFA method",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: i.handleMFAMethodWebAuthnDelete,
+					Summary:  "Delete a configuration for the given MFA method",
+				},
+			},
+		},
+		{
+			Pattern: "mfa/method/webauthn/?$",
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: i.handleMFAMethodListWebAuthn,
+					Summary:  "List MFA method configurations for the given MFA method",
+				},
+			},
+		},
+		{
+			Pattern: "mfa/method/webauthn/register$",
+			Fields: map[string]*framework.FieldSchema{
+				"method_id": {
+					Type:        framework.TypeString,
+					Description: `The unique identifier for this MFA method.`,
+					Required:    true,
+				},
+				"credential": {
+					Type:        framework.TypeString,
+					Description: `The JSON-serialized PublicKeyCredential created by the authenticator. If empty, a registration is started and the credential creation options are returned.`,
+				},
+				"name": {
+					Type:        framework.TypeString,
+					Description: `A name for the credential.`,
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: i.handleLoginMFAWebAuthnRegisterUpdate,
+					Summary:  "Register a WebAuthn credential for the given method ID on the entity of the token.",
+				},
+			},
+		},
+		{
+			Pattern: "mfa/method/webauthn/admin-destroy$",
+			Fields: map[string]*framework.FieldSchema{
+				"method_id": {
+					Type:        framework.TypeString,
+					Description: "The unique identifier for this MFA method.",
+					Required:    true,
+				},
+				"entity_id": {
+					Type:        framework.TypeString,
+					Description: "Identifier of the entity from which the WebAuthn credentials need to be removed.",
+					Required:    true,
+				},
+				"credential_id": {
+					Type:        framework.TypeString,
+					Description: "The base64url-encoded ID of the credential to remove. If empty, all credentials of the entity for the method are removed.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: i.handleLoginMFAWebAuthnAdminDestroyUpdate,
+					Summary:  "Destroys WebAuthn credentials for the given MFA method ID on the given entity",
+				},
+			},
+		},
 		{
 			Pattern: "mfa/login-enforcement/" + framework.GenericNameRegex("name"),
 			Fields: map[string]*framework.FieldSchema{
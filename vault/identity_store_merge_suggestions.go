@@ -0,0 +1,594 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"github.com/hashicorp/vault/helper/identity"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	entityMergeRulePrefix = "entity_merge_rule/"
+
+	// entityMergeAnalysisPath stores the result of the latest analysis, so
+	// that the suggestions and the automatic merge history survive restarts
+	// and leadership changes
+	entityMergeAnalysisPath = "entity_merge_analysis"
+
+	// entityMergeAnalysisInterval is how often the periodic func looks for
+	// entities to merge
+	entityMergeAnalysisInterval = 10 * time.Minute
+
+	// entityMergeHistoryLimit is the number of automatic merges that are
+	// remembered per namespace
+	entityMergeHistoryLimit = 100
+)
+
+// entityMergeRule declares entities to be the same person if aliases on the
+// given mounts carry the same value in the given metadata keys
+type entityMergeRule struct {
+	Name string `json:"name"`
+
+	// MetadataKeys maps mount accessors to the alias metadata key to compare
+	MetadataKeys  map[string]string `json:"metadata_keys"`
+	CaseSensitive bool              `json:"case_sensitive"`
+
+	// Trusted rules merge the entities they match without review. Anyone
+	// able to set the compared metadata on an alias of one of the mounts can
+	// take over the entity of another person, so this has to be acknowledged
+	// when the rule is written.
+	Trusted bool `json:"trusted"`
+}
+
+type entityMergeSuggestion struct {
+	Rule           string   `json:"rule"`
+	Value          string   `json:"value"`
+	ToEntityID     string   `json:"to_entity_id"`
+	FromEntityIDs  []string `json:"from_entity_ids"`
+	AutoMergeError string   `json:"auto_merge_error,omitempty"`
+}
+
+type entityMergeRecord struct {
+	Rule          string    `json:"rule"`
+	Value         string    `json:"value"`
+	ToEntityID    string    `json:"to_entity_id"`
+	FromEntityIDs []string  `json:"from_entity_ids"`
+	MergeTime     time.Time `json:"merge_time"`
+}
+
+type entityMergeAnalysis struct {
+	AnalysisTime time.Time                `json:"analysis_time"`
+	Suggestions  []*entityMergeSuggestion `json:"suggestions"`
+	AutoMerged   []*entityMergeRecord     `json:"auto_merged"`
+}
+
+func entityMergeSuggestionPaths(i *IdentityStore) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "entity/merge-rule/" + framework.GenericNameRegex("name"),
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the merge rule.",
+				},
+				"metadata_keys": {
+					Type: framework.TypeKVPairs,
+					Description: `Mount accessors mapped to the alias metadata key to compare on that mount.
+In CLI, this parameter can be repeated multiple times, and it all gets merged together.
+For example:
+vault <command> <path> metadata_keys=auth_oidc_1234=email metadata_keys=auth_ldap_5678=mail
+					`,
+				},
+				"case_sensitive": {
+					Type:        framework.TypeBool,
+					Description: "If set, metadata values are compared case sensitively.",
+				},
+				"trusted": {
+					Type: framework.TypeBool,
+					Description: `If set, entities matched by this rule are merged automatically instead of being suggested for a merge.
+WARNING: anyone who can set the compared metadata on an alias of one of the mounts can take over the entity,
+and with it the policies and group memberships, of another person. Requires acknowledge_takeover_risk.`,
+				},
+				"acknowledge_takeover_risk": {
+					Type:        framework.TypeBool,
+					Description: "Must be set to write a trusted rule, acknowledging that automatic merges allow entity takeover through the compared metadata.",
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.UpdateOperation: i.pathEntityMergeRuleCreateUpdate,
+				logical.ReadOperation:   i.pathEntityMergeRuleRead,
+				logical.DeleteOperation: i.pathEntityMergeRuleDelete,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(entityMergeSuggestionHelp["entity-merge-rule"][0]),
+			HelpDescription: strings.TrimSpace(entityMergeSuggestionHelp["entity-merge-rule"][1]),
+		},
+		{
+			Pattern: "entity/merge-rule/?$",
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: i.pathEntityMergeRuleList,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(entityMergeSuggestionHelp["entity-merge-rule-list"][0]),
+			HelpDescription: strings.TrimSpace(entityMergeSuggestionHelp["entity-merge-rule-list"][1]),
+		},
+		{
+			Pattern: "entity/merge-suggestions/?$",
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   i.pathEntityMergeSuggestionsRead,
+				logical.UpdateOperation: i.pathEntityMergeSuggestionsAnalyze,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(entityMergeSuggestionHelp["entity-merge-suggestions"][0]),
+			HelpDescription: strings.TrimSpace(entityMergeSuggestionHelp["entity-merge-suggestions"][1]),
+		},
+	}
+}
+
+func (i *IdentityStore) pathEntityMergeRuleCreateUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := d.Get("name").(string)
+	rule, err := i.entityMergeRule(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		rule = &entityMergeRule{
+			Name: name,
+		}
+	}
+
+	if metadataKeysRaw, ok := d.GetOk("metadata_keys"); ok {
+		rule.MetadataKeys = metadataKeysRaw.(map[string]string)
+	}
+	if caseSensitiveRaw, ok := d.GetOk("case_sensitive"); ok {
+		rule.CaseSensitive = caseSensitiveRaw.(bool)
+	}
+	if trustedRaw, ok := d.GetOk("trusted"); ok {
+		rule.Trusted = trustedRaw.(bool)
+	}
+
+	if len(rule.MetadataKeys) == 0 {
+		return logical.ErrorResponse("missing metadata_keys"), nil
+	}
+	if rule.Trusted && !d.Get("acknowledge_takeover_risk").(bool) {
+		return logical.ErrorResponse("trusted rules merge entities without review and allow an entity to be taken over by anyone who can set the compared metadata; set acknowledge_takeover_risk to confirm"), nil
+	}
+	for mountAccessor, key := range rule.MetadataKeys {
+		if key == "" {
+			return logical.ErrorResponse(fmt.Sprintf("missing metadata key for mount accessor %q", mountAccessor)), nil
+		}
+		mountEntry := i.router.MatchingMountByAccessor(mountAccessor)
+		switch {
+		case mountEntry == nil:
+			return logical.ErrorResponse(fmt.Sprintf("invalid mount accessor %q", mountAccessor)), nil
+		case mountEntry.NamespaceID != ns.ID:
+			return logical.ErrorResponse("matching mount is in a different namespace than request"), logical.ErrPermissionDenied
+		case mountEntry.Table != credentialTableType:
+			return logical.ErrorResponse(fmt.Sprintf("mount accessor %q does not belong to an auth mount", mountAccessor)), nil
+		}
+	}
+
+	entry, err := logical.StorageEntryJSON(entityMergeRulePrefix+name, rule)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (i *IdentityStore) pathEntityMergeRuleRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	rule, err := i.entityMergeRule(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"name":           rule.Name,
+			"metadata_keys":  rule.MetadataKeys,
+			"case_sensitive": rule.CaseSensitive,
+			"trusted":        rule.Trusted,
+		},
+	}, nil
+}
+
+func (i *IdentityStore) pathEntityMergeRuleDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := req.Storage.Delete(ctx, entityMergeRulePrefix+d.Get("name").(string)); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (i *IdentityStore) pathEntityMergeRuleList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	names, err := req.Storage.List(ctx, entityMergeRulePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(names), nil
+}
+
+func (i *IdentityStore) entityMergeRule(ctx context.Context, s logical.Storage, name string) (*entityMergeRule, error) {
+	entry, err := s.Get(ctx, entityMergeRulePrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var rule entityMergeRule
+	if err := entry.DecodeJSON(&rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// entityMergeRules returns all merge rules, trusted rules first
+func (i *IdentityStore) entityMergeRules(ctx context.Context, s logical.Storage) ([]*entityMergeRule, error) {
+	names, err := s.List(ctx, entityMergeRulePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*entityMergeRule
+	for _, name := range names {
+		rule, err := i.entityMergeRule(ctx, s, name)
+		if err != nil {
+			return nil, err
+		}
+		if rule != nil {
+			rules = append(rules, rule)
+		}
+	}
+
+	sort.SliceStable(rules, func(a, b int) bool {
+		return rules[a].Trusted && !rules[b].Trusted
+	})
+	return rules, nil
+}
+
+func (i *IdentityStore) pathEntityMergeSuggestionsRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	analysis, err := i.entityMergeAnalysis(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	return entityMergeAnalysisResponse(analysis), nil
+}
+
+// pathEntityMergeSuggestionsAnalyze looks for entities to merge right away
+// rather than waiting for the periodic func
+func (i *IdentityStore) pathEntityMergeSuggestionsAnalyze(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	analysis, err := i.analyzeEntityMerges(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	return entityMergeAnalysisResponse(analysis), nil
+}
+
+func (i *IdentityStore) entityMergeAnalysis(ctx context.Context, s logical.Storage) (*entityMergeAnalysis, error) {
+	entry, err := s.Get(ctx, entityMergeAnalysisPath)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var analysis entityMergeAnalysis
+	if err := entry.DecodeJSON(&analysis); err != nil {
+		return nil, err
+	}
+	return &analysis, nil
+}
+
+func entityMergeAnalysisResponse(analysis *entityMergeAnalysis) *logical.Response {
+	data := map[string]interface{}{
+		"last_analysis_time": "",
+		"suggestions":        []*entityMergeSuggestion{},
+		"auto_merged":        []*entityMergeRecord{},
+	}
+	if analysis != nil {
+		data["last_analysis_time"] = analysis.AnalysisTime.Format(time.RFC3339)
+		if len(analysis.Suggestions) > 0 {
+			data["suggestions"] = analysis.Suggestions
+		}
+		if len(analysis.AutoMerged) > 0 {
+			data["auto_merged"] = analysis.AutoMerged
+		}
+	}
+
+	return &logical.Response{
+		Data: data,
+	}
+}
+
+func (i *IdentityStore) entityMergePeriodicFunc(ctx context.Context) {
+	// Merging entities writes to storage, so only run this on the primary
+	// cluster. The periodic func does not run on perf standbys or DR
+	// secondaries.
+	if i.System().ReplicationState().HasState(consts.ReplicationPerformanceSecondary) {
+		return
+	}
+
+	i.mergeAnalysisLock.Lock()
+	if time.Since(i.mergeAnalysisLastRun) < entityMergeAnalysisInterval {
+		i.mergeAnalysisLock.Unlock()
+		return
+	}
+	i.mergeAnalysisLastRun = time.Now()
+	i.mergeAnalysisLock.Unlock()
+
+	for _, ns := range i.namespacer.ListNamespaces(true) {
+		s := i.router.MatchingStorageByAPIPath(ctx, ns.Path+"identity/entity")
+		if s == nil {
+			continue
+		}
+
+		if _, err := i.analyzeEntityMerges(namespace.ContextWithNamespace(ctx, ns), s); err != nil {
+			i.Logger().Warn("error analyzing entities for merges", "namespace", ns.Path, "err", err)
+		}
+	}
+}
+
+// analyzeEntityMerges applies the merge rules of the namespace to its
+// entities. Entities matched by trusted rules are merged; the others are stored
+// as suggestions until the next analysis.
+func (i *IdentityStore) analyzeEntityMerges(ctx context.Context, s logical.Storage) (*entityMergeAnalysis, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each analysis reads the auto-merge records of the previous one and
+	// stores them with its own, so analyses must not run concurrently or
+	// records would be lost
+	i.mergeAnalysisLock.Lock()
+	defer i.mergeAnalysisLock.Unlock()
+
+	rules, err := i.entityMergeRules(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := i.entityMergeAnalysis(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &entityMergeAnalysis{
+		AnalysisTime: time.Now(),
+	}
+	if previous != nil {
+		analysis.AutoMerged = previous.AutoMerged
+	}
+
+	for _, rule := range rules {
+		// Entities are looked up again for every rule, as trusted rules
+		// may have merged some of them
+		entities, err := i.namespaceEntities(ns)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, suggestion := range rule.suggestions(entities) {
+			if !rule.Trusted {
+				analysis.Suggestions = append(analysis.Suggestions, suggestion)
+				continue
+			}
+
+			record, err := i.autoMergeEntities(ctx, suggestion)
+			if err != nil {
+				i.Logger().Warn("failed to merge entities", "rule", rule.Name, "to_entity_id", suggestion.ToEntityID, "from_entity_ids", suggestion.FromEntityIDs, "err", err)
+				suggestion.AutoMergeError = err.Error()
+				analysis.Suggestions = append(analysis.Suggestions, suggestion)
+				continue
+			}
+			if record != nil {
+				i.Logger().Info("merged entities", "rule", rule.Name, "to_entity_id", record.ToEntityID, "from_entity_ids", record.FromEntityIDs)
+				analysis.AutoMerged = append(analysis.AutoMerged, record)
+			}
+		}
+	}
+
+	if len(analysis.AutoMerged) > entityMergeHistoryLimit {
+		analysis.AutoMerged = analysis.AutoMerged[len(analysis.AutoMerged)-entityMergeHistoryLimit:]
+	}
+
+	entry, err := logical.StorageEntryJSON(entityMergeAnalysisPath, analysis)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return analysis, nil
+}
+
+func (i *IdentityStore) namespaceEntities(ns *namespace.Namespace) ([]*identity.Entity, error) {
+	iter, err := i.db.Txn(false).Get(entitiesTable, "namespace_id", ns.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup entities using namespace ID: %w", err)
+	}
+
+	var entities []*identity.Entity
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		entities = append(entities, raw.(*identity.Entity))
+	}
+	return entities, nil
+}
+
+// suggestions groups the entities whose aliases carry the same metadata
+// value. Each group with more than one entity is merged into its oldest
+// entity.
+func (r *entityMergeRule) suggestions(entities []*identity.Entity) []*entityMergeSuggestion {
+	byValue := make(map[string][]*identity.Entity)
+	for _, entity := range entities {
+		seen := make(map[string]bool)
+		for _, alias := range entity.Aliases {
+			key, ok := r.MetadataKeys[alias.MountAccessor]
+			if !ok {
+				continue
+			}
+
+			value, ok := alias.Metadata[key]
+			if !ok {
+				value = alias.CustomMetadata[key]
+			}
+			value = strings.TrimSpace(value)
+			if !r.CaseSensitive {
+				value = strings.ToLower(value)
+			}
+			if value == "" || seen[value] {
+				continue
+			}
+
+			seen[value] = true
+			byValue[value] = append(byValue[value], entity)
+		}
+	}
+
+	values := make([]string, 0, len(byValue))
+	for value, matched := range byValue {
+		if len(matched) > 1 {
+			values = append(values, value)
+		}
+	}
+	sort.Strings(values)
+
+	suggestions := make([]*entityMergeSuggestion, 0, len(values))
+	for _, value := range values {
+		matched := byValue[value]
+		sort.Slice(matched, func(a, b int) bool {
+			ta, tb := matched[a].CreationTime.AsTime(), matched[b].CreationTime.AsTime()
+			if !ta.Equal(tb) {
+				return ta.Before(tb)
+			}
+			return matched[a].ID < matched[b].ID
+		})
+
+		suggestion := &entityMergeSuggestion{
+			Rule:       r.Name,
+			Value:      value,
+			ToEntityID: matched[0].ID,
+		}
+		for _, entity := range matched[1:] {
+			suggestion.FromEntityIDs = append(suggestion.FromEntityIDs, entity.ID)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions
+}
+
+// autoMergeEntities merges the entities of a suggestion. It returns a nil
+// record if there was nothing left to merge.
+func (i *IdentityStore) autoMergeEntities(ctx context.Context, suggestion *entityMergeSuggestion) (*entityMergeRecord, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	// Earlier merges may have merged some of the entities already
+	resolve := func(entityID string) (string, error) {
+		entity, err := i.MemDBEntityByID(entityID, false)
+		if err != nil || entity != nil {
+			return entityID, err
+		}
+		entity, err = i.MemDBEntityByMergedEntityID(entityID, false)
+		if err != nil || entity == nil {
+			return "", err
+		}
+		return entity.ID, nil
+	}
+
+	toEntityID, err := resolve(suggestion.ToEntityID)
+	if err != nil || toEntityID == "" {
+		return nil, err
+	}
+
+	var fromEntityIDs []string
+	for _, entityID := range suggestion.FromEntityIDs {
+		fromEntityID, err := resolve(entityID)
+		if err != nil {
+			return nil, err
+		}
+		if fromEntityID != "" && fromEntityID != toEntityID && !strutil.StrListContains(fromEntityIDs, fromEntityID) {
+			fromEntityIDs = append(fromEntityIDs, fromEntityID)
+		}
+	}
+	if len(fromEntityIDs) == 0 {
+		return nil, nil
+	}
+
+	txn := i.db.Txn(true)
+	defer txn.Abort()
+
+	toEntity, err := i.MemDBEntityByIDInTxn(txn, toEntityID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	userErr, intErr, _ := i.mergeEntity(ctx, txn, toEntity, fromEntityIDs, nil, false, false, false, true, false)
+	if userErr != nil {
+		return nil, userErr
+	}
+	if intErr != nil {
+		return nil, intErr
+	}
+
+	txn.Commit()
+
+	return &entityMergeRecord{
+		Rule:          suggestion.Rule,
+		Value:         suggestion.Value,
+		ToEntityID:    toEntityID,
+		FromEntityIDs: fromEntityIDs,
+		MergeTime:     time.Now(),
+	}, nil
+}
+
+var entityMergeSuggestionHelp = map[string][2]string{
+	"entity-merge-rule": {
+		"Create, update, read or delete a rule for finding duplicate entities",
+		`Entities are considered duplicates if aliases on the mounts of the rule
+carry the same value in the given alias metadata keys. Entities matched by
+trusted rules are merged automatically.
+
+WARNING: a trusted rule lets anyone who can set the compared metadata on an
+alias of one of the mounts take over the entity of another person, including
+its policies and group memberships. Only trust rules on metadata that users
+cannot choose, and set acknowledge_takeover_risk when writing them.`,
+	},
+	"entity-merge-rule-list": {
+		"List the rules for finding duplicate entities",
+		"",
+	},
+	"entity-merge-suggestions": {
+		"Read the entity merges suggested by the merge rules, or look for duplicate entities right away",
+		`Vault applies the merge rules to all entities periodically. Entities
+matched by untrusted rules are suggested for a merge with the entity/merge
+endpoint. Entities matched by trusted rules are merged into the oldest of
+them.`,
+	},
+}
@@ -0,0 +1,212 @@
+package vault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+func TestIdentityStore_EntityMergeSuggestions(t *testing.T) {
+	ctx := namespace.RootContext(nil)
+	is, ghAccessor, upAccessor, _ := testIdentityStoreWithGithubUserpassAuth(ctx, t)
+	storage := &logical.InmemStorage{}
+
+	createAlias := func(mountAccessor, name, key, value string) string {
+		t.Helper()
+		resp, err := is.HandleRequest(ctx, &logical.Request{
+			Path:      "entity-alias",
+			Operation: logical.UpdateOperation,
+			Data: map[string]interface{}{
+				"mount_accessor":  mountAccessor,
+				"name":            name,
+				"custom_metadata": map[string]string{key: value},
+			},
+		})
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+		}
+		return resp.Data["canonical_id"].(string)
+	}
+
+	aliceGithub := createAlias(ghAccessor, "alice-gh", "email", "Alice@example.com")
+	aliceUserpass := createAlias(upAccessor, "alice", "mail", "alice@example.com")
+	createAlias(upAccessor, "bob", "mail", "bob@example.com")
+
+	resp, err := is.HandleRequest(ctx, &logical.Request{
+		Path:      "entity/merge-rule/email",
+		Operation: logical.UpdateOperation,
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"metadata_keys": map[string]string{
+				ghAccessor: "email",
+				"invalid":  "mail",
+			},
+		},
+	})
+	if err != nil || resp == nil || !resp.IsError() {
+		t.Fatalf("expected an error for an invalid mount accessor: resp: %#v\nerr: %v", resp, err)
+	}
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Path:      "entity/merge-rule/email",
+		Operation: logical.UpdateOperation,
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"metadata_keys": map[string]string{
+				ghAccessor: "email",
+				upAccessor: "mail",
+			},
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Path:      "entity/merge-suggestions",
+		Operation: logical.UpdateOperation,
+		Storage:   storage,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+	suggestions := resp.Data["suggestions"].([]*entityMergeSuggestion)
+	if len(suggestions) != 1 {
+		t.Fatalf("expected one suggestion, got %#v", suggestions)
+	}
+	suggestion := suggestions[0]
+	if suggestion.Rule != "email" || suggestion.Value != "alice@example.com" {
+		t.Fatalf("unexpected suggestion: %#v", suggestion)
+	}
+	if suggestion.ToEntityID != aliceGithub || len(suggestion.FromEntityIDs) != 1 || suggestion.FromEntityIDs[0] != aliceUserpass {
+		t.Fatalf("expected the newer entity to be merged into the older one: %#v", suggestion)
+	}
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Path:      "entity/merge-suggestions",
+		Operation: logical.ReadOperation,
+		Storage:   storage,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+	if len(resp.Data["suggestions"].([]*entityMergeSuggestion)) != 1 || resp.Data["last_analysis_time"] == "" {
+		t.Fatalf("expected the last analysis to be returned: %#v", resp.Data)
+	}
+
+	// Entities are not merged until the rule is trusted
+	if entity, err := is.MemDBEntityByID(aliceUserpass, false); err != nil || entity == nil {
+		t.Fatalf("expected entity to still exist: %#v, %v", entity, err)
+	}
+
+	// Trusting a rule requires acknowledging the takeover risk
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Path:      "entity/merge-rule/email",
+		Operation: logical.UpdateOperation,
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"trusted": true,
+		},
+	})
+	if err != nil || resp == nil || !resp.IsError() {
+		t.Fatalf("expected an error without acknowledge_takeover_risk: resp: %#v\nerr: %v", resp, err)
+	}
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Path:      "entity/merge-rule/email",
+		Operation: logical.UpdateOperation,
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"trusted":                   true,
+			"acknowledge_takeover_risk": true,
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Path:      "entity/merge-suggestions",
+		Operation: logical.UpdateOperation,
+		Storage:   storage,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+	if suggestions := resp.Data["suggestions"].([]*entityMergeSuggestion); len(suggestions) != 0 {
+		t.Fatalf("expected no suggestions after merging, got %#v", suggestions)
+	}
+	merged := resp.Data["auto_merged"].([]*entityMergeRecord)
+	if len(merged) != 1 || merged[0].ToEntityID != aliceGithub || merged[0].FromEntityIDs[0] != aliceUserpass {
+		t.Fatalf("unexpected merges: %#v", merged)
+	}
+
+	// The analysis is kept in storage
+	analysis, err := is.entityMergeAnalysis(ctx, storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analysis == nil || len(analysis.AutoMerged) != 1 || analysis.AutoMerged[0].ToEntityID != aliceGithub {
+		t.Fatalf("expected the analysis to be stored: %#v", analysis)
+	}
+
+	entity, err := is.MemDBEntityByID(aliceGithub, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entity.Aliases) != 2 {
+		t.Fatalf("expected the aliases to be merged: %#v", entity.Aliases)
+	}
+	if entity, err := is.MemDBEntityByID(aliceUserpass, false); err != nil || entity != nil {
+		t.Fatalf("expected merged entity to be removed: %#v, %v", entity, err)
+	}
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Path:      "entity/merge-rule",
+		Operation: logical.ListOperation,
+		Storage:   storage,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+	if keys := resp.Data["keys"].([]string); len(keys) != 1 || keys[0] != "email" {
+		t.Fatalf("unexpected rules: %#v", keys)
+	}
+}
+
+func TestIdentityStore_EntityMergeAnalysisLock(t *testing.T) {
+	ctx := namespace.RootContext(nil)
+	is, _, _, _ := testIdentityStoreWithGithubUserpassAuth(ctx, t)
+	storage := &logical.InmemStorage{}
+
+	// An analysis waits for the one already running, so that it reads the
+	// auto-merge records that one stores
+	is.mergeAnalysisLock.Lock()
+	doneCh := make(chan error, 1)
+	go func() {
+		_, err := is.HandleRequest(ctx, &logical.Request{
+			Path:      "entity/merge-suggestions",
+			Operation: logical.UpdateOperation,
+			Storage:   storage,
+		})
+		doneCh <- err
+	}()
+
+	select {
+	case <-doneCh:
+		t.Fatal("expected the analysis to wait for the running one")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	is.mergeAnalysisLock.Unlock()
+	select {
+	case err := <-doneCh:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("analysis did not complete")
+	}
+}
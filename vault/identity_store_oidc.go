@@ -1935,6 +1935,10 @@ func (i *IdentityStore) oidcPeriodicFunc(ctx context.Context) {
 				i.Logger().Warn("error expiring OIDC public keys", "err", err)
 			}
 
+			if err := i.tidyOIDCProviderTokens(ctx, s); err != nil {
+				i.Logger().Warn("error tidying OIDC provider tokens", "err", err)
+			}
+
 			if err := i.oidcCache.Flush(ns); err != nil {
 				i.Logger().Error("error flushing oidc cache", "err", err)
 			}
@@ -2,8 +2,11 @@ package vault
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,11 +14,13 @@ import (
 	"net/url"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-secure-stdlib/base62"
 	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/vault/helper/identity"
 	"github.com/hashicorp/vault/helper/namespace"
 	"github.com/hashicorp/vault/sdk/framework"
@@ -33,12 +38,29 @@ const (
 	clientIDLength           = 32
 	clientSecretLength       = 64
 	clientSecretPrefix       = "hvo_secret_"
+	refreshTokenLength       = 64
+	refreshTokenPrefix       = "hvo_refresh_"
+	accessTokenFamilyMeta    = "refresh_token_family"
 	codeChallengeMethodPlain = "plain"
 	codeChallengeMethodS256  = "S256"
 	defaultProviderName      = "default"
 	defaultKeyName           = "default"
 	allowAllAssignmentName   = "allow_all"
 
+	// Grant types supported by the Token Endpoint
+	grantTypeAuthorizationCode = "authorization_code"
+	grantTypeRefreshToken      = "refresh_token"
+	grantTypeClientCredentials = "client_credentials"
+	grantTypeDeviceCode        = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// Device authorization grant constants. User codes use a base-20
+	// alphabet without vowels, as recommended by RFC 8628 section 6.1.
+	deviceCodeLength       = 32
+	userCodeLength         = 8
+	userCodeCharset        = "BCDFGHJKLMNPQRSTVWXZ"
+	deviceCodeTTL          = 10 * time.Minute
+	deviceCodePollInterval = 5 * time.Second
+
 	// Storage path constants
 	oidcProviderPrefix = "oidc_provider/"
 	assignmentPath     = oidcProviderPrefix + "assignment/"
@@ -46,6 +68,12 @@ const (
 	clientPath         = oidcProviderPrefix + "client/"
 	providerPath       = oidcProviderPrefix + "provider/"
 
+	// Storage paths of issued refresh tokens and revocations. Tokens are
+	// stored by the hex-encoded SHA-256 hash of their value.
+	refreshTokenPath       = oidcProviderPrefix + "refresh_token/"
+	refreshTokenFamilyPath = oidcProviderPrefix + "refresh_token_family/"
+	revokedAccessTokenPath = oidcProviderPrefix + "revoked_access_token/"
+
 	// Error constants used in the Authorization Endpoint. See details at
 	// https://openid.net/specs/openid-connect-core-1_0.html#AuthError.
 	ErrAuthUnsupportedResponseType = "unsupported_response_type"
@@ -62,8 +90,17 @@ const (
 	ErrTokenInvalidClient        = "invalid_client"
 	ErrTokenInvalidGrant         = "invalid_grant"
 	ErrTokenUnsupportedGrantType = "unsupported_grant_type"
+	ErrTokenUnauthorizedClient   = "unauthorized_client"
+	ErrTokenInvalidScope         = "invalid_scope"
 	ErrTokenServerError          = "server_error"
 
+	// Error constants used in the Token Endpoint for the device authorization
+	// grant. See details at https://datatracker.ietf.org/doc/html/rfc8628#section-3.5
+	ErrTokenAuthorizationPending = "authorization_pending"
+	ErrTokenSlowDown             = "slow_down"
+	ErrTokenAccessDenied         = "access_denied"
+	ErrTokenExpiredToken         = "expired_token"
+
 	// Error constants used in the UserInfo Endpoint. See details at
 	// https://openid.net/specs/openid-connect-core-1_0.html#UserInfoError
 	ErrUserInfoServerError    = "server_error"
@@ -102,6 +139,14 @@ type client struct {
 	AccessTokenTTL time.Duration `json:"access_token_ttl"`
 	Type           clientType    `json:"type"`
 
+	// RefreshTokenTTL is the time-to-live of refresh tokens obtained by the
+	// client. Refresh tokens are not issued if it's zero.
+	RefreshTokenTTL time.Duration `json:"refresh_token_ttl"`
+
+	// AllowClientCredentials allows a confidential client to use the
+	// client_credentials grant.
+	AllowClientCredentials bool `json:"allow_client_credentials"`
+
 	// Generated values that are used in OIDC endpoints
 	ClientID     string `json:"client_id"`
 	ClientSecret string `json:"client_secret"`
@@ -154,6 +199,9 @@ type providerDiscovery struct {
 	AuthorizationEndpoint string   `json:"authorization_endpoint"`
 	TokenEndpoint         string   `json:"token_endpoint"`
 	UserinfoEndpoint      string   `json:"userinfo_endpoint"`
+	RevocationEndpoint    string   `json:"revocation_endpoint"`
+	IntrospectionEndpoint string   `json:"introspection_endpoint"`
+	DeviceEndpoint        string   `json:"device_authorization_endpoint"`
 	RequestParameter      bool     `json:"request_parameter_supported"`
 	RequestURIParameter   bool     `json:"request_uri_parameter_supported"`
 	IDTokenAlgs           []string `json:"id_token_signing_alg_values_supported"`
@@ -177,6 +225,56 @@ type authCodeCacheEntry struct {
 	codeChallengeMethod string
 }
 
+// deviceCodeCacheEntry is the state of a device authorization request. It is
+// cached under both its device code and its user code.
+type deviceCodeCacheEntry struct {
+	lock sync.Mutex
+
+	provider   string
+	clientID   string
+	deviceCode string
+	userCode   string
+	scopes     []string
+	expiry     time.Time
+	interval   time.Duration
+	lastPoll   time.Time
+
+	// Set once the end-user has approved or denied the request
+	entityID string
+	authTime time.Time
+	denied   bool
+}
+
+// refreshTokenEntry is the stored state of a refresh token.
+type refreshTokenEntry struct {
+	Provider string    `json:"provider"`
+	ClientID string    `json:"client_id"`
+	EntityID string    `json:"entity_id"`
+	Scopes   []string  `json:"scopes"`
+	AuthTime time.Time `json:"auth_time"`
+	FamilyID string    `json:"family_id"`
+	IssuedAt time.Time `json:"issued_at"`
+	Expiry   time.Time `json:"expiry"`
+
+	// Rotated is set once the refresh token has been exchanged. The entry is
+	// kept until it expires so that its reuse can be detected.
+	Rotated bool `json:"rotated"`
+}
+
+// refreshTokenFamily tracks the refresh tokens, and the access tokens issued
+// along with them, that descend from a single authorization grant. Revoking
+// a family revokes all of its tokens.
+type refreshTokenFamily struct {
+	ClientID string    `json:"client_id"`
+	Expiry   time.Time `json:"expiry"`
+	Revoked  bool      `json:"revoked"`
+}
+
+// revokedAccessToken is stored for a revoked access token until it expires.
+type revokedAccessToken struct {
+	Expiry time.Time `json:"expiry"`
+}
+
 func oidcProviderPaths(i *IdentityStore) []*framework.Path {
 	return []*framework.Path{
 		{
@@ -302,6 +400,14 @@ func oidcProviderPaths(i *IdentityStore) []*framework.Path {
 					Description: "The client type based on its ability to maintain confidentiality of credentials. The following client types are supported: 'confidential', 'public'. Defaults to 'confidential'.",
 					Default:     "confidential",
 				},
+				"refresh_token_ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "The time-to-live for refresh tokens obtained by the client. Refresh tokens are not issued if set to 0. Defaults to 0.",
+				},
+				"allow_client_credentials": {
+					Type:        framework.TypeBool,
+					Description: "Allow the client to use the client_credentials grant. Only confidential clients may use the grant.",
+				},
 			},
 			Operations: map[logical.Operation]framework.OperationHandler{
 				logical.UpdateOperation: &framework.PathOperation{
@@ -493,18 +599,28 @@ func oidcProviderPaths(i *IdentityStore) []*framework.Path {
 				},
 				"code": {
 					Type:        framework.TypeString,
-					Description: "The authorization code received from the provider's authorization endpoint.",
-					Required:    true,
+					Description: "The authorization code received from the provider's authorization endpoint. Required for the 'authorization_code' grant type.",
 				},
 				"grant_type": {
 					Type:        framework.TypeString,
-					Description: "The authorization grant type. The following grant types are supported: 'authorization_code'.",
+					Description: "The authorization grant type. The following grant types are supported: 'authorization_code', 'refresh_token', 'client_credentials', 'urn:ietf:params:oauth:grant-type:device_code'.",
 					Required:    true,
 				},
 				"redirect_uri": {
 					Type:        framework.TypeString,
-					Description: "The callback location where the authentication response was sent.",
-					Required:    true,
+					Description: "The callback location where the authentication response was sent. Required for the 'authorization_code' grant type.",
+				},
+				"refresh_token": {
+					Type:        framework.TypeString,
+					Description: "The refresh token issued to the client. Required for the 'refresh_token' grant type.",
+				},
+				"device_code": {
+					Type:        framework.TypeString,
+					Description: "The device code received from the provider's device authorization endpoint. Required for the 'urn:ietf:params:oauth:grant-type:device_code' grant type.",
+				},
+				"scope": {
+					Type:        framework.TypeString,
+					Description: "A space-delimited, case-sensitive list of scopes to be requested. For the 'refresh_token' grant type, the scopes must have been granted by the original authorization.",
 				},
 				"code_verifier": {
 					Type:        framework.TypeString,
@@ -538,6 +654,93 @@ func oidcProviderPaths(i *IdentityStore) []*framework.Path {
 			HelpSynopsis:    "Provides the OIDC Token Endpoint.",
 			HelpDescription: "The OIDC Token Endpoint allows a client to exchange its Authorization Grant for an Access Token and ID Token.",
 		},
+		{
+			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/device_authorization",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the provider",
+				},
+				"scope": {
+					Type:        framework.TypeString,
+					Description: "A space-delimited, case-sensitive list of scopes to be requested. The 'openid' scope is required.",
+					Required:    true,
+				},
+				"client_id": {
+					Type:        framework.TypeString,
+					Description: "The ID of the requesting client.",
+				},
+				"client_secret": {
+					Type:        framework.TypeString,
+					Description: "The secret of the requesting client.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback:                    i.pathOIDCDeviceAuthorization,
+					ForwardPerformanceStandby:   true,
+					ForwardPerformanceSecondary: false,
+				},
+			},
+			HelpSynopsis:    "Provides the OAuth 2.0 Device Authorization Endpoint.",
+			HelpDescription: "The Device Authorization Endpoint allows a client on a device without a browser to start the device authorization grant, as defined in RFC 8628. The end-user approves the request by entering the returned user code at the device verification endpoint.",
+		},
+		{
+			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/device",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the provider",
+				},
+				"user_code": {
+					Type:        framework.TypeString,
+					Description: "The user code displayed by the device.",
+					Required:    true,
+				},
+				"deny": {
+					Type:        framework.TypeBool,
+					Description: "Deny the device authorization request instead of approving it.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback:                    i.pathOIDCReadDeviceRequest,
+					ForwardPerformanceStandby:   true,
+					ForwardPerformanceSecondary: false,
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback:                    i.pathOIDCVerifyDevice,
+					ForwardPerformanceStandby:   true,
+					ForwardPerformanceSecondary: false,
+				},
+			},
+			HelpSynopsis:    "Approves or denies OIDC device authorization requests.",
+			HelpDescription: "Read the device authorization request of a user code, or approve or deny it on behalf of the identity entity of the request.",
+		},
+		{
+			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/revoke",
+			Fields:  oidcTokenManagementFields(),
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback:                    i.pathOIDCProviderRevoke,
+					ForwardPerformanceStandby:   true,
+					ForwardPerformanceSecondary: false,
+				},
+			},
+			HelpSynopsis:    "Provides the OAuth 2.0 Token Revocation Endpoint.",
+			HelpDescription: "The Token Revocation Endpoint allows a client to revoke an access token or refresh token issued to it by the provider, as defined in RFC 7009. Revoking a refresh token also revokes the access tokens issued with it.",
+		},
+		{
+			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/introspect",
+			Fields:  oidcTokenManagementFields(),
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: i.pathOIDCProviderIntrospect,
+				},
+			},
+			HelpSynopsis:    "Provides the OAuth 2.0 Token Introspection Endpoint.",
+			HelpDescription: "The Token Introspection Endpoint allows a client to determine the state of an access token or refresh token issued by the provider, as defined in RFC 7662.",
+		},
 		{
 			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/userinfo",
 			Fields: map[string]*framework.FieldSchema{
@@ -560,6 +763,36 @@ func oidcProviderPaths(i *IdentityStore) []*framework.Path {
 	}
 }
 
+// oidcTokenManagementFields returns the fields of the revocation and
+// introspection endpoints.
+func oidcTokenManagementFields() map[string]*framework.FieldSchema {
+	return map[string]*framework.FieldSchema{
+		"name": {
+			Type:        framework.TypeString,
+			Description: "Name of the provider",
+		},
+		"token": {
+			Type:        framework.TypeString,
+			Description: "The access token or refresh token.",
+			Required:    true,
+		},
+		"token_type_hint": {
+			Type:        framework.TypeString,
+			Description: "A hint about the type of the token. The following hints are supported: 'access_token', 'refresh_token'.",
+		},
+		// Clients authenticate to the endpoints in the same way as to the
+		// token endpoint.
+		"client_id": {
+			Type:        framework.TypeString,
+			Description: "The ID of the requesting client.",
+		},
+		"client_secret": {
+			Type:        framework.TypeString,
+			Description: "The secret of the requesting client.",
+		},
+	}
+}
+
 // clientsReferencingTargetAssignmentName returns a map of client names to
 // clients referencing targetAssignmentName.
 func (i *IdentityStore) clientsReferencingTargetAssignmentName(ctx context.Context, req *logical.Request, targetAssignmentName string) (map[string]client, error) {
@@ -1086,6 +1319,20 @@ func (i *IdentityStore) pathOIDCCreateUpdateClient(ctx context.Context, req *log
 		}
 	}
 
+	if refreshTokenTTLRaw, ok := d.GetOk("refresh_token_ttl"); ok {
+		client.RefreshTokenTTL = time.Duration(refreshTokenTTLRaw.(int)) * time.Second
+	}
+	if client.RefreshTokenTTL < 0 {
+		return logical.ErrorResponse("refresh_token_ttl must not be negative"), nil
+	}
+
+	if allowClientCredentialsRaw, ok := d.GetOk("allow_client_credentials"); ok {
+		client.AllowClientCredentials = allowClientCredentialsRaw.(bool)
+	}
+	if client.AllowClientCredentials && client.Type != confidential {
+		return logical.ErrorResponse("only confidential clients may use the client_credentials grant"), nil
+	}
+
 	if client.ClientID == "" {
 		// generate client_id
 		clientID, err := base62.Random(clientIDLength)
@@ -1134,13 +1381,15 @@ func (i *IdentityStore) pathOIDCListClient(ctx context.Context, req *logical.Req
 	for _, client := range clients {
 		keys = append(keys, client.Name)
 		keyInfo[client.Name] = map[string]interface{}{
-			"redirect_uris":    client.RedirectURIs,
-			"assignments":      client.Assignments,
-			"key":              client.Key,
-			"id_token_ttl":     int64(client.IDTokenTTL.Seconds()),
-			"access_token_ttl": int64(client.AccessTokenTTL.Seconds()),
-			"client_type":      client.Type.String(),
-			"client_id":        client.ClientID,
+			"redirect_uris":            client.RedirectURIs,
+			"assignments":              client.Assignments,
+			"key":                      client.Key,
+			"id_token_ttl":             int64(client.IDTokenTTL.Seconds()),
+			"access_token_ttl":         int64(client.AccessTokenTTL.Seconds()),
+			"client_type":              client.Type.String(),
+			"client_id":                client.ClientID,
+			"refresh_token_ttl":        int64(client.RefreshTokenTTL.Seconds()),
+			"allow_client_credentials": client.AllowClientCredentials,
 			// client_secret is intentionally omitted
 		}
 	}
@@ -1162,13 +1411,15 @@ func (i *IdentityStore) pathOIDCReadClient(ctx context.Context, req *logical.Req
 
 	resp := &logical.Response{
 		Data: map[string]interface{}{
-			"redirect_uris":    client.RedirectURIs,
-			"assignments":      client.Assignments,
-			"key":              client.Key,
-			"id_token_ttl":     int64(client.IDTokenTTL.Seconds()),
-			"access_token_ttl": int64(client.AccessTokenTTL.Seconds()),
-			"client_id":        client.ClientID,
-			"client_type":      client.Type.String(),
+			"redirect_uris":            client.RedirectURIs,
+			"assignments":              client.Assignments,
+			"key":                      client.Key,
+			"id_token_ttl":             int64(client.IDTokenTTL.Seconds()),
+			"access_token_ttl":         int64(client.AccessTokenTTL.Seconds()),
+			"client_id":                client.ClientID,
+			"client_type":              client.Type.String(),
+			"refresh_token_ttl":        int64(client.RefreshTokenTTL.Seconds()),
+			"allow_client_credentials": client.AllowClientCredentials,
 		},
 	}
 
@@ -1477,6 +1728,9 @@ func (i *IdentityStore) pathOIDCProviderDiscovery(ctx context.Context, req *logi
 		AuthorizationEndpoint: strings.Replace(p.effectiveIssuer, "/v1/", "/ui/vault/", 1) + "/authorize",
 		TokenEndpoint:         p.effectiveIssuer + "/token",
 		UserinfoEndpoint:      p.effectiveIssuer + "/userinfo",
+		RevocationEndpoint:    p.effectiveIssuer + "/revoke",
+		IntrospectionEndpoint: p.effectiveIssuer + "/introspect",
+		DeviceEndpoint:        p.effectiveIssuer + "/device_authorization",
 		IDTokenAlgs:           supportedAlgs,
 		Scopes:                scopes,
 		Claims:                []string{},
@@ -1484,7 +1738,12 @@ func (i *IdentityStore) pathOIDCProviderDiscovery(ctx context.Context, req *logi
 		RequestURIParameter:   false,
 		ResponseTypes:         []string{"code"},
 		Subjects:              []string{"public"},
-		GrantTypes:            []string{"authorization_code"},
+		GrantTypes: []string{
+			grantTypeAuthorizationCode,
+			grantTypeRefreshToken,
+			grantTypeClientCredentials,
+			grantTypeDeviceCode,
+		},
 		AuthMethods: []string{
 			// PKCE is required for auth method "none"
 			"none",
@@ -1845,6 +2104,16 @@ func authResponse(code, state, errorCode, errorDescription string) (*logical.Res
 	}, nil
 }
 
+// oidcTokenRequest holds the state shared by the grant types of a token
+// request from an authenticated client.
+type oidcTokenRequest struct {
+	ns           *namespace.Namespace
+	providerName string
+	provider     *provider
+	client       *client
+	key          *namedKey
+}
+
 func (i *IdentityStore) pathOIDCToken(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	// Get the namespace
 	ns, err := namespace.FromContext(ctx)
@@ -1862,35 +2131,13 @@ func (i *IdentityStore) pathOIDCToken(ctx context.Context, req *logical.Request,
 		return tokenResponse(nil, ErrTokenInvalidRequest, "provider not found")
 	}
 
-	// client_secret_basic - Check for client credentials in the Authorization header
-	clientID, clientSecret, okBasicAuth := basicAuth(req)
-	if !okBasicAuth {
-		// client_secret_post - Check for client credentials in the request body
-		clientID = d.Get("client_id").(string)
-		if clientID == "" {
-			return tokenResponse(nil, ErrTokenInvalidRequest, "client_id parameter is required")
-		}
-		clientSecret = d.Get("client_secret").(string)
-	}
-	client, err := i.clientByID(ctx, req.Storage, clientID)
-	if err != nil {
-		return tokenResponse(nil, ErrTokenServerError, err.Error())
-	}
-	if client == nil {
-		i.Logger().Debug("client failed to authenticate with client not found", "client_id", clientID)
-		return tokenResponse(nil, ErrTokenInvalidClient, "client failed to authenticate")
-	}
-
-	// Authenticate the client if it's a confidential client type.
-	// Details at https://openid.net/specs/openid-connect-core-1_0.html#ClientAuthentication
-	if client.Type == confidential &&
-		subtle.ConstantTimeCompare([]byte(client.ClientSecret), []byte(clientSecret)) == 0 {
-		i.Logger().Debug("client failed to authenticate with invalid client secret", "client_id", clientID)
-		return tokenResponse(nil, ErrTokenInvalidClient, "client failed to authenticate")
+	client, errCode, errDescription := i.authenticateOIDCClient(ctx, req, d)
+	if errCode != "" {
+		return tokenResponse(nil, errCode, errDescription)
 	}
 
 	// Validate that the client is authorized to use the provider
-	if !provider.allowedClientID(clientID) {
+	if !provider.allowedClientID(client.ClientID) {
 		return tokenResponse(nil, ErrTokenInvalidClient, "client is not authorized to use the provider")
 	}
 
@@ -1905,18 +2152,74 @@ func (i *IdentityStore) pathOIDCToken(ctx context.Context, req *logical.Request,
 
 	// Validate that the client is authorized to use the key
 	if !strutil.StrListContains(key.AllowedClientIDs, "*") &&
-		!strutil.StrListContains(key.AllowedClientIDs, clientID) {
+		!strutil.StrListContains(key.AllowedClientIDs, client.ClientID) {
 		return tokenResponse(nil, ErrTokenInvalidClient, "client is not authorized to use the key")
 	}
 
+	tr := &oidcTokenRequest{
+		ns:           ns,
+		providerName: name,
+		provider:     provider,
+		client:       client,
+		key:          key,
+	}
+
 	// Validate the grant type
-	grantType := d.Get("grant_type").(string)
-	if grantType == "" {
+	switch grantType := d.Get("grant_type").(string); grantType {
+	case "":
 		return tokenResponse(nil, ErrTokenInvalidRequest, "grant_type parameter is required")
-	}
-	if grantType != "authorization_code" {
+	case grantTypeAuthorizationCode:
+		return i.oidcAuthorizationCodeGrant(ctx, req, d, tr)
+	case grantTypeRefreshToken:
+		return i.oidcRefreshTokenGrant(ctx, req, d, tr)
+	case grantTypeClientCredentials:
+		return i.oidcClientCredentialsGrant(ctx, req, d, tr)
+	case grantTypeDeviceCode:
+		return i.oidcDeviceCodeGrant(ctx, req, d, tr)
+	default:
 		return tokenResponse(nil, ErrTokenUnsupportedGrantType, "unsupported grant_type value")
 	}
+}
+
+// authenticateOIDCClient authenticates the client of a request to the token,
+// revocation or introspection endpoints. The returned error code and
+// description are set if the client failed to authenticate.
+func (i *IdentityStore) authenticateOIDCClient(ctx context.Context, req *logical.Request, d *framework.FieldData) (*client, string, string) {
+	// client_secret_basic - Check for client credentials in the Authorization header
+	clientID, clientSecret, okBasicAuth := basicAuth(req)
+	if !okBasicAuth {
+		// client_secret_post - Check for client credentials in the request body
+		clientID = d.Get("client_id").(string)
+		if clientID == "" {
+			return nil, ErrTokenInvalidRequest, "client_id parameter is required"
+		}
+		clientSecret = d.Get("client_secret").(string)
+	}
+	client, err := i.clientByID(ctx, req.Storage, clientID)
+	if err != nil {
+		return nil, ErrTokenServerError, err.Error()
+	}
+	if client == nil {
+		i.Logger().Debug("client failed to authenticate with client not found", "client_id", clientID)
+		return nil, ErrTokenInvalidClient, "client failed to authenticate"
+	}
+
+	// Authenticate the client if it's a confidential client type.
+	// Details at https://openid.net/specs/openid-connect-core-1_0.html#ClientAuthentication
+	if client.Type == confidential &&
+		subtle.ConstantTimeCompare([]byte(client.ClientSecret), []byte(clientSecret)) == 0 {
+		i.Logger().Debug("client failed to authenticate with invalid client secret", "client_id", clientID)
+		return nil, ErrTokenInvalidClient, "client failed to authenticate"
+	}
+
+	return client, "", ""
+}
+
+// oidcAuthorizationCodeGrant exchanges an authorization code for an access
+// token, an ID token and, if the client is configured for them, a refresh
+// token.
+func (i *IdentityStore) oidcAuthorizationCodeGrant(ctx context.Context, req *logical.Request, d *framework.FieldData, tr *oidcTokenRequest) (*logical.Response, error) {
+	client := tr.client
 
 	// Validate the authorization code
 	code := d.Get("code").(string)
@@ -1925,8 +2228,8 @@ func (i *IdentityStore) pathOIDCToken(ctx context.Context, req *logical.Request,
 	}
 
 	// Get the authorization code entry and defer its deletion (single use)
-	authCodeEntryRaw, ok, err := i.oidcAuthCodeCache.Get(ns, code)
-	defer i.oidcAuthCodeCache.Delete(ns, code)
+	authCodeEntryRaw, ok, err := i.oidcAuthCodeCache.Get(tr.ns, code)
+	defer i.oidcAuthCodeCache.Delete(tr.ns, code)
 	if err != nil {
 		return tokenResponse(nil, ErrTokenServerError, err.Error())
 	}
@@ -1939,12 +2242,12 @@ func (i *IdentityStore) pathOIDCToken(ctx context.Context, req *logical.Request,
 	}
 
 	// Ensure the authorization code was issued to the authenticated client
-	if authCodeEntry.clientID != clientID {
+	if authCodeEntry.clientID != client.ClientID {
 		return tokenResponse(nil, ErrTokenInvalidGrant, "authorization code was not issued to the client")
 	}
 
 	// Ensure the authorization code was issued by the provider
-	if authCodeEntry.provider != name {
+	if authCodeEntry.provider != tr.providerName {
 		return tokenResponse(nil, ErrTokenInvalidGrant, "authorization code was not issued by the provider")
 	}
 
@@ -1998,135 +2301,760 @@ func (i *IdentityStore) pathOIDCToken(ctx context.Context, req *logical.Request,
 		}
 	}
 
-	// The access token is a Vault batch token with a policy that only
-	// provides access to the issuing provider's userinfo endpoint.
-	accessTokenIssuedAt := time.Now()
-	accessTokenExpiry := accessTokenIssuedAt.Add(client.AccessTokenTTL)
-	accessToken := &logical.TokenEntry{
-		Type:               logical.TokenTypeBatch,
-		NamespaceID:        ns.ID,
-		Path:               req.Path,
-		TTL:                client.AccessTokenTTL,
-		CreationTime:       accessTokenIssuedAt.Unix(),
-		EntityID:           entity.ID,
-		NoIdentityPolicies: true,
-		Meta: map[string]string{
-			"oidc_token_type": "access token",
-		},
-		InternalMeta: map[string]string{
-			accessTokenClientIDMeta: client.ClientID,
-			accessTokenScopesMeta:   strings.Join(authCodeEntry.scopes, scopesDelimiter),
-		},
-		InlinePolicy: fmt.Sprintf(`
-			path "identity/oidc/provider/%s/userinfo" {
-				capabilities = ["read", "update"]
-			}
-		`, name),
+	// Refresh tokens issued for the authorization grant belong to a new family
+	var familyID string
+	if client.RefreshTokenTTL > 0 {
+		familyID, err = uuid.GenerateUUID()
+		if err != nil {
+			return tokenResponse(nil, ErrTokenServerError, err.Error())
+		}
 	}
-	err = i.tokenStorer.CreateToken(ctx, accessToken)
+
+	accessToken, err := i.createOIDCAccessToken(ctx, req, tr, entity.ID, authCodeEntry.scopes, familyID)
 	if err != nil {
 		return tokenResponse(nil, ErrTokenServerError, err.Error())
 	}
 
-	// Compute the access token hash claim (at_hash)
-	atHash, err := computeHashClaim(key.Algorithm, accessToken.ID)
+	// Compute the authorization code hash claim (c_hash)
+	cHash, err := computeHashClaim(tr.key.Algorithm, code)
 	if err != nil {
 		return tokenResponse(nil, ErrTokenServerError, err.Error())
 	}
 
-	// Compute the authorization code hash claim (c_hash)
-	cHash, err := computeHashClaim(key.Algorithm, code)
+	signedIDToken, errCode, err := i.signOIDCIDToken(ctx, req.Storage, tr, entity, accessToken, authCodeEntry.scopes, &idToken{
+		Nonce:    authCodeEntry.nonce,
+		CodeHash: cHash,
+	}, authCodeEntry.authTime)
 	if err != nil {
-		return tokenResponse(nil, ErrTokenServerError, err.Error())
+		return tokenResponse(nil, errCode, err.Error())
 	}
 
-	// Set the ID token claims
-	idTokenIssuedAt := time.Now()
-	idTokenExpiry := idTokenIssuedAt.Add(client.IDTokenTTL)
-	idToken := idToken{
-		Namespace:       ns.ID,
-		Issuer:          provider.effectiveIssuer,
-		Subject:         authCodeEntry.entityID,
-		Audience:        authCodeEntry.clientID,
-		Nonce:           authCodeEntry.nonce,
-		Expiry:          idTokenExpiry.Unix(),
-		IssuedAt:        idTokenIssuedAt.Unix(),
-		AccessTokenHash: atHash,
-		CodeHash:        cHash,
+	response := map[string]interface{}{
+		"token_type":   "Bearer",
+		"access_token": accessToken.ID,
+		"id_token":     signedIDToken,
+		"expires_in":   int64(client.AccessTokenTTL.Seconds()),
 	}
 
-	// Add the auth_time claim if it's not the zero time instant
-	if !authCodeEntry.authTime.IsZero() {
-		idToken.AuthTime = authCodeEntry.authTime.Unix()
+	if familyID != "" {
+		refreshToken, err := i.issueOIDCRefreshToken(ctx, req.Storage, tr, accessToken, &refreshTokenEntry{
+			EntityID: entity.ID,
+			Scopes:   authCodeEntry.scopes,
+			AuthTime: authCodeEntry.authTime,
+			FamilyID: familyID,
+		})
+		if err != nil {
+			return tokenResponse(nil, ErrTokenServerError, err.Error())
+		}
+		response["refresh_token"] = refreshToken
 	}
 
-	// Populate each of the requested scope templates
-	templates, conflict, err := i.populateScopeTemplates(ctx, req.Storage, ns, entity, authCodeEntry.scopes...)
-	if !conflict && err != nil {
-		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	return tokenResponse(response, "", "")
+}
+
+// oidcRefreshTokenGrant exchanges a refresh token for a new access token, ID
+// token and refresh token. Refresh tokens are single use. If a refresh token
+// is used twice, all of the tokens issued from its authorization grant are
+// revoked. See https://datatracker.ietf.org/doc/html/rfc6749#section-10.4.
+func (i *IdentityStore) oidcRefreshTokenGrant(ctx context.Context, req *logical.Request, d *framework.FieldData, tr *oidcTokenRequest) (*logical.Response, error) {
+	client := tr.client
+
+	refreshToken := d.Get("refresh_token").(string)
+	if refreshToken == "" {
+		return tokenResponse(nil, ErrTokenInvalidRequest, "refresh_token parameter is required")
 	}
-	if conflict && err != nil {
-		return tokenResponse(nil, ErrTokenInvalidRequest, err.Error())
+	if client.RefreshTokenTTL <= 0 {
+		return tokenResponse(nil, ErrTokenUnauthorizedClient, "client is not authorized to use refresh tokens")
 	}
 
-	// Generate the ID token payload
-	payload, err := idToken.generatePayload(i.Logger(), templates...)
+	i.oidcRefreshTokenLock.Lock()
+	defer i.oidcRefreshTokenLock.Unlock()
+
+	entry, err := i.getRefreshToken(ctx, req.Storage, refreshToken)
 	if err != nil {
 		return tokenResponse(nil, ErrTokenServerError, err.Error())
 	}
+	if entry == nil || time.Now().After(entry.Expiry) {
+		return tokenResponse(nil, ErrTokenInvalidGrant, "refresh token is invalid or expired")
+	}
 
-	// Sign the ID token using the client's key
-	signedIDToken, err := key.signPayload(payload)
-	if err != nil {
-		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	// Ensure the refresh token was issued to the authenticated client
+	if entry.ClientID != client.ClientID {
+		return tokenResponse(nil, ErrTokenInvalidGrant, "refresh token was not issued to the client")
 	}
 
-	return tokenResponse(map[string]interface{}{
-		"token_type":   "Bearer",
-		"access_token": accessToken.ID,
-		"id_token":     signedIDToken,
-		"expires_in":   int64(accessTokenExpiry.Sub(accessTokenIssuedAt).Seconds()),
-	}, "", "")
-}
+	// Ensure the refresh token was issued by the provider
+	if entry.Provider != tr.providerName {
+		return tokenResponse(nil, ErrTokenInvalidGrant, "refresh token was not issued by the provider")
+	}
 
-// tokenResponse returns the OIDC Token Response. An error response is
-// returned if the given error code is non-empty. For details, see spec at
-//   - https://openid.net/specs/openid-connect-core-1_0.html#TokenResponse
-//   - https://openid.net/specs/openid-connect-core-1_0.html#TokenErrorResponse
-func tokenResponse(response map[string]interface{}, errorCode, errorDescription string) (*logical.Response, error) {
-	statusCode := http.StatusOK
+	family, err := i.getRefreshTokenFamily(ctx, req.Storage, entry.FamilyID)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if family == nil || family.Revoked {
+		return tokenResponse(nil, ErrTokenInvalidGrant, "refresh token is revoked")
+	}
 
-	// Set the error response and status code if error code isn't empty
-	if errorCode != "" {
-		switch errorCode {
-		case ErrTokenInvalidClient:
-			statusCode = http.StatusUnauthorized
-		case ErrTokenServerError:
-			statusCode = http.StatusInternalServerError
-		default:
-			statusCode = http.StatusBadRequest
+	if entry.Rotated {
+		i.Logger().Warn("reuse of rotated refresh token detected, revoking its token family",
+			"client_id", client.ClientID, "family_id", entry.FamilyID)
+		if err := i.revokeRefreshTokenFamily(ctx, req.Storage, entry.FamilyID); err != nil {
+			return tokenResponse(nil, ErrTokenServerError, err.Error())
 		}
+		return tokenResponse(nil, ErrTokenInvalidGrant, "refresh token is revoked")
+	}
 
-		response = map[string]interface{}{
-			"error":             errorCode,
-			"error_description": errorDescription,
+	// The requested scopes must have been granted by the original
+	// authorization. The granted scopes are used if none are requested.
+	scopes := entry.Scopes
+	if scopeRaw := d.Get("scope").(string); scopeRaw != "" {
+		scopes = make([]string, 0)
+		for _, scope := range strutil.ParseDedupAndSortStrings(scopeRaw, scopesDelimiter) {
+			if scope == openIDScope {
+				continue
+			}
+			if !strutil.StrListContains(entry.Scopes, scope) {
+				return tokenResponse(nil, ErrTokenInvalidScope, fmt.Sprintf("scope %q was not granted by the original authorization", scope))
+			}
+			scopes = append(scopes, scope)
 		}
 	}
 
-	body, err := json.Marshal(response)
+	// Get the entity the refresh token was issued for
+	entity, err := i.MemDBEntityByID(entry.EntityID, true)
 	if err != nil {
-		return nil, err
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
 	}
-
-	data := map[string]interface{}{
-		logical.HTTPStatusCode:  statusCode,
-		logical.HTTPRawBody:     body,
-		logical.HTTPContentType: "application/json",
-
-		// Token responses must include the following HTTP response headers
-		// https://openid.net/specs/openid-connect-core-1_0.html#TokenResponse
-		logical.HTTPCacheControlHeader: "no-store",
-		logical.HTTPPragmaHeader:       "no-cache",
+	if entity == nil {
+		return tokenResponse(nil, ErrTokenInvalidGrant, "identity entity associated with the refresh token not found")
+	}
+
+	// Validate that the entity is still a member of the client's assignments
+	isMember, err := i.entityHasAssignment(ctx, req.Storage, entity, client.Assignments)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if !isMember {
+		return tokenResponse(nil, ErrTokenInvalidGrant, "identity entity not authorized by client assignment")
+	}
+
+	// Rotate the refresh token
+	entry.Rotated = true
+	if err := putOIDCStorageEntry(ctx, req.Storage, refreshTokenPath+oidcTokenHash(refreshToken), entry); err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	accessToken, err := i.createOIDCAccessToken(ctx, req, tr, entity.ID, scopes, entry.FamilyID)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	signedIDToken, errCode, err := i.signOIDCIDToken(ctx, req.Storage, tr, entity, accessToken, scopes, &idToken{}, entry.AuthTime)
+	if err != nil {
+		return tokenResponse(nil, errCode, err.Error())
+	}
+
+	newRefreshToken, err := i.issueOIDCRefreshToken(ctx, req.Storage, tr, accessToken, &refreshTokenEntry{
+		EntityID: entity.ID,
+		Scopes:   scopes,
+		AuthTime: entry.AuthTime,
+		FamilyID: entry.FamilyID,
+	})
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	return tokenResponse(map[string]interface{}{
+		"token_type":    "Bearer",
+		"access_token":  accessToken.ID,
+		"id_token":      signedIDToken,
+		"refresh_token": newRefreshToken,
+		"expires_in":    int64(client.AccessTokenTTL.Seconds()),
+	}, "", "")
+}
+
+// oidcClientCredentialsGrant issues an access token to a confidential client
+// acting on its own behalf. No ID token or refresh token is issued since
+// there is no end-user. See https://datatracker.ietf.org/doc/html/rfc6749#section-4.4.
+func (i *IdentityStore) oidcClientCredentialsGrant(ctx context.Context, req *logical.Request, d *framework.FieldData, tr *oidcTokenRequest) (*logical.Response, error) {
+	client := tr.client
+
+	if client.Type != confidential || !client.AllowClientCredentials {
+		return tokenResponse(nil, ErrTokenUnauthorizedClient, "client is not authorized to use the client_credentials grant")
+	}
+
+	// The requested scopes must be supported by the provider. The openid
+	// scope is not supported since there is no end-user.
+	scopes := make([]string, 0)
+	for _, scope := range strutil.ParseDedupAndSortStrings(d.Get("scope").(string), scopesDelimiter) {
+		if !strutil.StrListContains(tr.provider.ScopesSupported, scope) {
+			return tokenResponse(nil, ErrTokenInvalidScope, fmt.Sprintf("scope %q is not supported by the provider", scope))
+		}
+		scopes = append(scopes, scope)
+	}
+
+	accessToken, err := i.createOIDCAccessToken(ctx, req, tr, "", scopes, "")
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	response := map[string]interface{}{
+		"token_type":   "Bearer",
+		"access_token": accessToken.ID,
+		"expires_in":   int64(client.AccessTokenTTL.Seconds()),
+	}
+	if len(scopes) > 0 {
+		response["scope"] = strings.Join(scopes, scopesDelimiter)
+	}
+
+	return tokenResponse(response, "", "")
+}
+
+// oidcDeviceCodeGrant exchanges a device code for an access token, an ID token
+// and, if the client is configured for them, a refresh token once the
+// end-user has approved the device authorization request. Until then, the
+// device is told to keep polling. For details, see spec at
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.4.
+func (i *IdentityStore) oidcDeviceCodeGrant(ctx context.Context, req *logical.Request, d *framework.FieldData, tr *oidcTokenRequest) (*logical.Response, error) {
+	client := tr.client
+
+	deviceCode := d.Get("device_code").(string)
+	if deviceCode == "" {
+		return tokenResponse(nil, ErrTokenInvalidRequest, "device_code parameter is required")
+	}
+
+	entryRaw, ok, err := i.oidcDeviceCodeCache.Get(tr.ns, deviceCode)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if !ok {
+		return tokenResponse(nil, ErrTokenInvalidGrant, "device code is invalid")
+	}
+	entry, ok := entryRaw.(*deviceCodeCacheEntry)
+	if !ok {
+		return tokenResponse(nil, ErrTokenServerError, "device code is invalid")
+	}
+
+	entry.lock.Lock()
+	defer entry.lock.Unlock()
+
+	// Ensure the device code was issued to the authenticated client
+	if entry.clientID != client.ClientID {
+		return tokenResponse(nil, ErrTokenInvalidGrant, "device code was not issued to the client")
+	}
+
+	// Ensure the device code was issued by the provider
+	if entry.provider != tr.providerName {
+		return tokenResponse(nil, ErrTokenInvalidGrant, "device code was not issued by the provider")
+	}
+
+	now := time.Now()
+	if now.After(entry.expiry) {
+		i.deleteDeviceCodeEntry(tr.ns, entry)
+		return tokenResponse(nil, ErrTokenExpiredToken, "device code is expired")
+	}
+
+	if entry.denied {
+		i.deleteDeviceCodeEntry(tr.ns, entry)
+		return tokenResponse(nil, ErrTokenAccessDenied, "the end-user denied the authorization request")
+	}
+
+	if entry.entityID == "" {
+		// Devices polling faster than the interval must slow down for the
+		// rest of the flow
+		lastPoll := entry.lastPoll
+		entry.lastPoll = now
+		if now.Sub(lastPoll) < entry.interval {
+			entry.interval += deviceCodePollInterval
+			return tokenResponse(nil, ErrTokenSlowDown, "polling too frequently")
+		}
+		return tokenResponse(nil, ErrTokenAuthorizationPending, "the end-user has not yet approved the authorization request")
+	}
+
+	// The device code is single use once approved
+	i.deleteDeviceCodeEntry(tr.ns, entry)
+
+	// Get the entity that approved the request
+	entity, err := i.MemDBEntityByID(entry.entityID, true)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if entity == nil {
+		return tokenResponse(nil, ErrTokenInvalidGrant, "identity entity associated with the request not found")
+	}
+
+	// Validate that the entity is a member of the client's assignments
+	isMember, err := i.entityHasAssignment(ctx, req.Storage, entity, client.Assignments)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if !isMember {
+		return tokenResponse(nil, ErrTokenAccessDenied, "identity entity not authorized by client assignment")
+	}
+
+	var familyID string
+	if client.RefreshTokenTTL > 0 {
+		familyID, err = uuid.GenerateUUID()
+		if err != nil {
+			return tokenResponse(nil, ErrTokenServerError, err.Error())
+		}
+	}
+
+	accessToken, err := i.createOIDCAccessToken(ctx, req, tr, entity.ID, entry.scopes, familyID)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	signedIDToken, errCode, err := i.signOIDCIDToken(ctx, req.Storage, tr, entity, accessToken, entry.scopes, &idToken{}, entry.authTime)
+	if err != nil {
+		return tokenResponse(nil, errCode, err.Error())
+	}
+
+	response := map[string]interface{}{
+		"token_type":   "Bearer",
+		"access_token": accessToken.ID,
+		"id_token":     signedIDToken,
+		"expires_in":   int64(client.AccessTokenTTL.Seconds()),
+	}
+
+	if familyID != "" {
+		refreshToken, err := i.issueOIDCRefreshToken(ctx, req.Storage, tr, accessToken, &refreshTokenEntry{
+			EntityID: entity.ID,
+			Scopes:   entry.scopes,
+			AuthTime: entry.authTime,
+			FamilyID: familyID,
+		})
+		if err != nil {
+			return tokenResponse(nil, ErrTokenServerError, err.Error())
+		}
+		response["refresh_token"] = refreshToken
+	}
+
+	return tokenResponse(response, "", "")
+}
+
+// oidcProviderUIURL returns the URL of the provider's pages in the Vault UI.
+// It is built from the issuer URL, which may hold the path prefix that Vault
+// is served under.
+func (i *IdentityStore) oidcProviderUIURL(ns *namespace.Namespace, name string, p *provider) (string, error) {
+	issuer := p.Issuer
+	if issuer == "" {
+		issuer = i.redirectAddr
+	}
+
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return "", fmt.Errorf("invalid issuer %q: %w", issuer, err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/ui/vault/" + ns.Path + "identity/oidc/provider/" + name
+	u.RawPath = ""
+
+	return u.String(), nil
+}
+
+// pathOIDCDeviceAuthorization starts a device authorization grant for the
+// client. For details, see spec at
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.1.
+func (i *IdentityStore) pathOIDCDeviceAuthorization(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	name := d.Get("name").(string)
+	provider, err := i.getOIDCProvider(ctx, req.Storage, name)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if provider == nil {
+		return tokenResponse(nil, ErrTokenInvalidRequest, "provider not found")
+	}
+
+	client, errCode, errDescription := i.authenticateOIDCClient(ctx, req, d)
+	if errCode != "" {
+		return tokenResponse(nil, errCode, errDescription)
+	}
+	if !provider.allowedClientID(client.ClientID) {
+		return tokenResponse(nil, ErrTokenInvalidClient, "client is not authorized to use the provider")
+	}
+
+	// Validate that a scope parameter is present and contains the openid scope value
+	requestedScopes := strutil.ParseDedupAndSortStrings(d.Get("scope").(string), scopesDelimiter)
+	if len(requestedScopes) == 0 || !strutil.StrListContains(requestedScopes, openIDScope) {
+		return tokenResponse(nil, ErrTokenInvalidScope,
+			fmt.Sprintf("scope parameter must contain the %q value", openIDScope))
+	}
+
+	// Scope values that are not supported by the provider should be ignored
+	scopes := make([]string, 0)
+	for _, scope := range requestedScopes {
+		if strutil.StrListContains(provider.ScopesSupported, scope) && scope != openIDScope {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	deviceCode, err := base62.Random(deviceCodeLength)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	// Generate a user code that isn't in use
+	var userCode string
+	for {
+		userCode, err = generateUserCode()
+		if err != nil {
+			return tokenResponse(nil, ErrTokenServerError, err.Error())
+		}
+		_, ok, err := i.oidcDeviceCodeCache.Get(ns, userCodeCacheKey(userCode))
+		if err != nil {
+			return tokenResponse(nil, ErrTokenServerError, err.Error())
+		}
+		if !ok {
+			break
+		}
+	}
+
+	now := time.Now()
+	entry := &deviceCodeCacheEntry{
+		provider:   name,
+		clientID:   client.ClientID,
+		deviceCode: deviceCode,
+		userCode:   userCode,
+		scopes:     scopes,
+		expiry:     now.Add(deviceCodeTTL),
+		interval:   deviceCodePollInterval,
+	}
+	if err := i.oidcDeviceCodeCache.SetDefault(ns, deviceCode, entry); err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if err := i.oidcDeviceCodeCache.SetDefault(ns, userCodeCacheKey(userCode), entry); err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	providerUIURL, err := i.oidcProviderUIURL(ns, name, provider)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	verificationURI := providerUIURL + "/device"
+	displayedUserCode := userCode[:userCodeLength/2] + "-" + userCode[userCodeLength/2:]
+
+	return tokenResponse(map[string]interface{}{
+		"device_code":               deviceCode,
+		"user_code":                 displayedUserCode,
+		"verification_uri":          verificationURI,
+		"verification_uri_complete": verificationURI + "?user_code=" + displayedUserCode,
+		"expires_in":                int64(deviceCodeTTL.Seconds()),
+		"interval":                  int64(deviceCodePollInterval.Seconds()),
+	}, "", "")
+}
+
+// pathOIDCReadDeviceRequest returns the device authorization request of a
+// user code, so that the end-user can check it before approving it.
+func (i *IdentityStore) pathOIDCReadDeviceRequest(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	entry, client, resp, err := i.deviceRequestForEntity(ctx, req, d)
+	if resp != nil || err != nil {
+		return resp, err
+	}
+
+	entry.lock.Lock()
+	defer entry.lock.Unlock()
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"client_name": client.Name,
+			"client_id":   client.ClientID,
+			"scopes":      entry.scopes,
+			"expires_in":  int64(time.Until(entry.expiry).Seconds()),
+		},
+	}, nil
+}
+
+// pathOIDCVerifyDevice approves or denies the device authorization request
+// of a user code on behalf of the identity entity of the request.
+func (i *IdentityStore) pathOIDCVerifyDevice(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	entry, _, resp, err := i.deviceRequestForEntity(ctx, req, d)
+	if resp != nil || err != nil {
+		return resp, err
+	}
+
+	entry.lock.Lock()
+	defer entry.lock.Unlock()
+
+	if entry.entityID != "" || entry.denied {
+		return logical.ErrorResponse("user code has already been used"), nil
+	}
+
+	if d.Get("deny").(bool) {
+		entry.denied = true
+		return nil, nil
+	}
+
+	// Set the auth time to the time the end-user authenticated to Vault
+	te, err := i.tokenStorer.LookupToken(ctx, req.ClientToken)
+	if err != nil {
+		return nil, err
+	}
+	if te == nil {
+		return logical.ErrorResponse("token associated with request not found"), logical.ErrPermissionDenied
+	}
+	entry.authTime = time.Unix(te.CreationTime, 0).UTC()
+	entry.entityID = req.EntityID
+
+	return nil, nil
+}
+
+// deviceRequestForEntity returns the unexpired device authorization request
+// of the user code in the request, and its client, if the identity entity of
+// the request may approve it. An error response is returned otherwise.
+func (i *IdentityStore) deviceRequestForEntity(ctx context.Context, req *logical.Request, d *framework.FieldData) (*deviceCodeCacheEntry, *client, *logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	userCode := d.Get("user_code").(string)
+	if userCode == "" {
+		return nil, nil, logical.ErrorResponse("user_code parameter is required"), nil
+	}
+
+	// Validate that there is an identity entity associated with the request
+	if req.EntityID == "" {
+		return nil, nil, logical.ErrorResponse("identity entity must be associated with the request"), logical.ErrPermissionDenied
+	}
+	entity, err := i.MemDBEntityByID(req.EntityID, false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if entity == nil {
+		return nil, nil, logical.ErrorResponse("identity entity associated with the request not found"), logical.ErrPermissionDenied
+	}
+
+	entryRaw, ok, err := i.oidcDeviceCodeCache.Get(ns, userCodeCacheKey(userCode))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	entry, _ := entryRaw.(*deviceCodeCacheEntry)
+	if !ok || entry == nil || entry.provider != d.Get("name").(string) || time.Now().After(entry.expiry) {
+		return nil, nil, logical.ErrorResponse("user code is invalid or expired"), nil
+	}
+
+	client, err := i.clientByID(ctx, req.Storage, entry.clientID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if client == nil {
+		return nil, nil, logical.ErrorResponse("client not found"), nil
+	}
+
+	// Validate that the entity is a member of the client's assignments
+	isMember, err := i.entityHasAssignment(ctx, req.Storage, entity, client.Assignments)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !isMember {
+		return nil, nil, logical.ErrorResponse("identity entity not authorized by client assignment"), logical.ErrPermissionDenied
+	}
+
+	return entry, client, nil, nil
+}
+
+func (i *IdentityStore) deleteDeviceCodeEntry(ns *namespace.Namespace, entry *deviceCodeCacheEntry) {
+	i.oidcDeviceCodeCache.Delete(ns, entry.deviceCode)
+	i.oidcDeviceCodeCache.Delete(ns, userCodeCacheKey(entry.userCode))
+}
+
+// generateUserCode returns a random user code of userCodeLength characters.
+func generateUserCode() (string, error) {
+	code := make([]byte, 0, userCodeLength)
+	random := make([]byte, 1)
+	for len(code) < userCodeLength {
+		if _, err := rand.Read(random); err != nil {
+			return "", err
+		}
+		// Reject the bytes that would bias the distribution of characters
+		if int(random[0]) >= 256-256%len(userCodeCharset) {
+			continue
+		}
+		code = append(code, userCodeCharset[int(random[0])%len(userCodeCharset)])
+	}
+	return string(code), nil
+}
+
+// userCodeCacheKey returns the cache key of a user code. User codes are case
+// insensitive and their separators are ignored.
+func userCodeCacheKey(userCode string) string {
+	userCode = strings.ToUpper(userCode)
+	userCode = strings.NewReplacer("-", "", " ", "").Replace(userCode)
+	return "user_code/" + userCode
+}
+
+// createOIDCAccessToken creates an access token for the client. The access
+// token is a Vault batch token with a policy that only provides access to the
+// issuing provider's userinfo endpoint. The family ID is set for access
+// tokens issued along with refresh tokens.
+func (i *IdentityStore) createOIDCAccessToken(ctx context.Context, req *logical.Request, tr *oidcTokenRequest, entityID string, scopes []string, familyID string) (*logical.TokenEntry, error) {
+	accessToken := &logical.TokenEntry{
+		Type:               logical.TokenTypeBatch,
+		NamespaceID:        tr.ns.ID,
+		Path:               req.Path,
+		TTL:                tr.client.AccessTokenTTL,
+		CreationTime:       time.Now().Unix(),
+		EntityID:           entityID,
+		NoIdentityPolicies: true,
+		Meta: map[string]string{
+			"oidc_token_type": "access token",
+		},
+		InternalMeta: map[string]string{
+			accessTokenClientIDMeta: tr.client.ClientID,
+			accessTokenScopesMeta:   strings.Join(scopes, scopesDelimiter),
+		},
+		InlinePolicy: fmt.Sprintf(`
+			path "identity/oidc/provider/%s/userinfo" {
+				capabilities = ["read", "update"]
+			}
+		`, tr.providerName),
+	}
+	if familyID != "" {
+		accessToken.InternalMeta[accessTokenFamilyMeta] = familyID
+	}
+
+	if err := i.tokenStorer.CreateToken(ctx, accessToken); err != nil {
+		return nil, err
+	}
+
+	return accessToken, nil
+}
+
+// signOIDCIDToken completes the given ID token with the claims of the entity
+// and the scopes, and signs it with the client's key. The returned error code
+// is set if an error is returned.
+func (i *IdentityStore) signOIDCIDToken(ctx context.Context, s logical.Storage, tr *oidcTokenRequest, entity *identity.Entity, accessToken *logical.TokenEntry, scopes []string, idToken *idToken, authTime time.Time) (string, string, error) {
+	// Compute the access token hash claim (at_hash)
+	atHash, err := computeHashClaim(tr.key.Algorithm, accessToken.ID)
+	if err != nil {
+		return "", ErrTokenServerError, err
+	}
+
+	// Set the ID token claims
+	issuedAt := time.Now()
+	idToken.Namespace = tr.ns.ID
+	idToken.Issuer = tr.provider.effectiveIssuer
+	idToken.Subject = entity.ID
+	idToken.Audience = tr.client.ClientID
+	idToken.Expiry = issuedAt.Add(tr.client.IDTokenTTL).Unix()
+	idToken.IssuedAt = issuedAt.Unix()
+	idToken.AccessTokenHash = atHash
+
+	// Add the auth_time claim if it's not the zero time instant
+	if !authTime.IsZero() {
+		idToken.AuthTime = authTime.Unix()
+	}
+
+	// Populate each of the requested scope templates
+	templates, conflict, err := i.populateScopeTemplates(ctx, s, tr.ns, entity, scopes...)
+	if !conflict && err != nil {
+		return "", ErrTokenServerError, err
+	}
+	if conflict && err != nil {
+		return "", ErrTokenInvalidRequest, err
+	}
+
+	// Generate the ID token payload
+	payload, err := idToken.generatePayload(i.Logger(), templates...)
+	if err != nil {
+		return "", ErrTokenServerError, err
+	}
+
+	// Sign the ID token using the client's key
+	signedIDToken, err := tr.key.signPayload(payload)
+	if err != nil {
+		return "", ErrTokenServerError, err
+	}
+
+	return signedIDToken, "", nil
+}
+
+// issueOIDCRefreshToken generates and stores a refresh token completing the
+// given entry, and extends the lifetime of its family to cover it and the
+// access token issued along with it.
+func (i *IdentityStore) issueOIDCRefreshToken(ctx context.Context, s logical.Storage, tr *oidcTokenRequest, accessToken *logical.TokenEntry, entry *refreshTokenEntry) (string, error) {
+	random, err := base62.Random(refreshTokenLength)
+	if err != nil {
+		return "", err
+	}
+	refreshToken := refreshTokenPrefix + random
+
+	now := time.Now()
+	entry.Provider = tr.providerName
+	entry.ClientID = tr.client.ClientID
+	entry.IssuedAt = now
+	entry.Expiry = now.Add(tr.client.RefreshTokenTTL)
+
+	family, err := i.getRefreshTokenFamily(ctx, s, entry.FamilyID)
+	if err != nil {
+		return "", err
+	}
+	if family == nil {
+		family = &refreshTokenFamily{
+			ClientID: tr.client.ClientID,
+		}
+	}
+	accessTokenExpiry := time.Unix(accessToken.CreationTime, 0).Add(accessToken.TTL)
+	for _, expiry := range []time.Time{entry.Expiry, accessTokenExpiry} {
+		if expiry.After(family.Expiry) {
+			family.Expiry = expiry
+		}
+	}
+
+	if err := putOIDCStorageEntry(ctx, s, refreshTokenFamilyPath+entry.FamilyID, family); err != nil {
+		return "", err
+	}
+	if err := putOIDCStorageEntry(ctx, s, refreshTokenPath+oidcTokenHash(refreshToken), entry); err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+// tokenResponse returns the OIDC Token Response. An error response is
+// returned if the given error code is non-empty. For details, see spec at
+//   - https://openid.net/specs/openid-connect-core-1_0.html#TokenResponse
+//   - https://openid.net/specs/openid-connect-core-1_0.html#TokenErrorResponse
+func tokenResponse(response map[string]interface{}, errorCode, errorDescription string) (*logical.Response, error) {
+	statusCode := http.StatusOK
+
+	// Set the error response and status code if error code isn't empty
+	if errorCode != "" {
+		switch errorCode {
+		case ErrTokenInvalidClient:
+			statusCode = http.StatusUnauthorized
+		case ErrTokenServerError:
+			statusCode = http.StatusInternalServerError
+		default:
+			statusCode = http.StatusBadRequest
+		}
+
+		response = map[string]interface{}{
+			"error":             errorCode,
+			"error_description": errorDescription,
+		}
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		logical.HTTPStatusCode:  statusCode,
+		logical.HTTPRawBody:     body,
+		logical.HTTPContentType: "application/json",
+
+		// Token responses must include the following HTTP response headers
+		// https://openid.net/specs/openid-connect-core-1_0.html#TokenResponse
+		logical.HTTPCacheControlHeader: "no-store",
+		logical.HTTPPragmaHeader:       "no-cache",
 	}
 
 	// Set the WWW-Authenticate response header when returning the
@@ -2141,6 +3069,328 @@ func tokenResponse(response map[string]interface{}, errorCode, errorDescription
 	}, nil
 }
 
+// pathOIDCProviderRevoke revokes an access token or refresh token issued to the
+// client. Revoking a refresh token revokes all of the tokens issued from its
+// authorization grant. Invalid tokens are ignored. For details, see spec at
+// https://datatracker.ietf.org/doc/html/rfc7009#section-2.
+func (i *IdentityStore) pathOIDCProviderRevoke(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	name := d.Get("name").(string)
+	provider, err := i.getOIDCProvider(ctx, req.Storage, name)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if provider == nil {
+		return tokenResponse(nil, ErrTokenInvalidRequest, "provider not found")
+	}
+
+	client, errCode, errDescription := i.authenticateOIDCClient(ctx, req, d)
+	if errCode != "" {
+		return tokenResponse(nil, errCode, errDescription)
+	}
+	if !provider.allowedClientID(client.ClientID) {
+		return tokenResponse(nil, ErrTokenInvalidClient, "client is not authorized to use the provider")
+	}
+
+	token := d.Get("token").(string)
+	if token == "" {
+		return tokenResponse(nil, ErrTokenInvalidRequest, "token parameter is required")
+	}
+
+	i.oidcRefreshTokenLock.Lock()
+	defer i.oidcRefreshTokenLock.Unlock()
+
+	// The type of a token is known from its format, so token_type_hint is
+	// not needed to find it.
+	if strings.HasPrefix(token, refreshTokenPrefix) {
+		entry, err := i.getRefreshToken(ctx, req.Storage, token)
+		if err != nil {
+			return tokenResponse(nil, ErrTokenServerError, err.Error())
+		}
+		if entry == nil || entry.Provider != name {
+			return tokenResponse(map[string]interface{}{}, "", "")
+		}
+		if entry.ClientID != client.ClientID {
+			return tokenResponse(nil, ErrTokenUnauthorizedClient, "token was not issued to the client")
+		}
+		if err := i.revokeRefreshTokenFamily(ctx, req.Storage, entry.FamilyID); err != nil {
+			return tokenResponse(nil, ErrTokenServerError, err.Error())
+		}
+		return tokenResponse(map[string]interface{}{}, "", "")
+	}
+
+	te := i.lookupOIDCAccessToken(ctx, ns, name, token)
+	if te == nil {
+		return tokenResponse(map[string]interface{}{}, "", "")
+	}
+	if te.InternalMeta[accessTokenClientIDMeta] != client.ClientID {
+		return tokenResponse(nil, ErrTokenUnauthorizedClient, "token was not issued to the client")
+	}
+	if err := putOIDCStorageEntry(ctx, req.Storage, revokedAccessTokenPath+oidcTokenHash(token), &revokedAccessToken{
+		Expiry: time.Unix(te.CreationTime, 0).Add(te.TTL),
+	}); err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	return tokenResponse(map[string]interface{}{}, "", "")
+}
+
+// pathOIDCProviderIntrospect returns the state of an access token or refresh token
+// issued by the provider. For details, see spec at
+// https://datatracker.ietf.org/doc/html/rfc7662#section-2.
+func (i *IdentityStore) pathOIDCProviderIntrospect(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	name := d.Get("name").(string)
+	provider, err := i.getOIDCProvider(ctx, req.Storage, name)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if provider == nil {
+		return tokenResponse(nil, ErrTokenInvalidRequest, "provider not found")
+	}
+
+	client, errCode, errDescription := i.authenticateOIDCClient(ctx, req, d)
+	if errCode != "" {
+		return tokenResponse(nil, errCode, errDescription)
+	}
+	if !provider.allowedClientID(client.ClientID) {
+		return tokenResponse(nil, ErrTokenInvalidClient, "client is not authorized to use the provider")
+	}
+
+	// Public clients can't authenticate, so allowing them to introspect
+	// would let anyone who knows a client_id probe tokens. See
+	// https://datatracker.ietf.org/doc/html/rfc7662#section-4.
+	if client.Type == public {
+		return tokenResponse(nil, ErrTokenInvalidClient, "public clients are not allowed to introspect tokens")
+	}
+
+	token := d.Get("token").(string)
+	if token == "" {
+		return tokenResponse(nil, ErrTokenInvalidRequest, "token parameter is required")
+	}
+
+	// Tokens issued to other clients are reported as inactive so that a
+	// client can only learn about its own tokens.
+	inactive := map[string]interface{}{
+		"active": false,
+	}
+
+	if strings.HasPrefix(token, refreshTokenPrefix) {
+		entry, err := i.getRefreshToken(ctx, req.Storage, token)
+		if err != nil {
+			return tokenResponse(nil, ErrTokenServerError, err.Error())
+		}
+		if entry == nil || entry.Provider != name || entry.ClientID != client.ClientID ||
+			entry.Rotated || time.Now().After(entry.Expiry) {
+			return tokenResponse(inactive, "", "")
+		}
+		family, err := i.getRefreshTokenFamily(ctx, req.Storage, entry.FamilyID)
+		if err != nil {
+			return tokenResponse(nil, ErrTokenServerError, err.Error())
+		}
+		if family == nil || family.Revoked {
+			return tokenResponse(inactive, "", "")
+		}
+
+		return tokenResponse(map[string]interface{}{
+			"active":    true,
+			"scope":     strings.Join(entry.Scopes, scopesDelimiter),
+			"client_id": entry.ClientID,
+			"exp":       entry.Expiry.Unix(),
+			"iat":       entry.IssuedAt.Unix(),
+			"sub":       entry.EntityID,
+			"aud":       entry.ClientID,
+			"iss":       provider.effectiveIssuer,
+		}, "", "")
+	}
+
+	te := i.lookupOIDCAccessToken(ctx, ns, name, token)
+	if te == nil || te.InternalMeta[accessTokenClientIDMeta] != client.ClientID {
+		return tokenResponse(inactive, "", "")
+	}
+	revoked, err := i.oidcAccessTokenRevoked(ctx, req.Storage, te)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if revoked {
+		return tokenResponse(inactive, "", "")
+	}
+
+	clientID := te.InternalMeta[accessTokenClientIDMeta]
+	response := map[string]interface{}{
+		"active":     true,
+		"scope":      te.InternalMeta[accessTokenScopesMeta],
+		"client_id":  clientID,
+		"token_type": "Bearer",
+		"exp":        time.Unix(te.CreationTime, 0).Add(te.TTL).Unix(),
+		"iat":        te.CreationTime,
+		"aud":        clientID,
+		"iss":        provider.effectiveIssuer,
+	}
+	// Access tokens issued with the client_credentials grant have no subject
+	if te.EntityID != "" {
+		response["sub"] = te.EntityID
+	}
+
+	return tokenResponse(response, "", "")
+}
+
+// lookupOIDCAccessToken returns the token entry of an unexpired access token
+// issued by the named provider in the namespace, or nil if the token is not
+// such an access token.
+func (i *IdentityStore) lookupOIDCAccessToken(ctx context.Context, ns *namespace.Namespace, providerName, token string) *logical.TokenEntry {
+	// Only batch tokens are looked up so that the endpoints can't be used to
+	// learn about other Vault tokens
+	if !IsBatchToken(token) {
+		return nil
+	}
+
+	te, err := i.tokenStorer.LookupToken(ctx, token)
+	if err != nil {
+		i.Logger().Debug("failed to look up access token", "err", err)
+		return nil
+	}
+	if te == nil || te.Type != logical.TokenTypeBatch || te.NamespaceID != ns.ID ||
+		te.Path != "oidc/provider/"+providerName+"/token" {
+		return nil
+	}
+
+	return te
+}
+
+// oidcAccessTokenRevoked returns true if the access token, or the refresh
+// token family it was issued with, was revoked.
+func (i *IdentityStore) oidcAccessTokenRevoked(ctx context.Context, s logical.Storage, te *logical.TokenEntry) (bool, error) {
+	entry, err := s.Get(ctx, revokedAccessTokenPath+oidcTokenHash(te.ID))
+	if err != nil {
+		return false, err
+	}
+	if entry != nil {
+		return true, nil
+	}
+
+	familyID, ok := te.InternalMeta[accessTokenFamilyMeta]
+	if !ok {
+		return false, nil
+	}
+	family, err := i.getRefreshTokenFamily(ctx, s, familyID)
+	if err != nil {
+		return false, err
+	}
+
+	return family == nil || family.Revoked, nil
+}
+
+// getRefreshToken returns the stored entry of a refresh token, or nil if it
+// doesn't exist.
+func (i *IdentityStore) getRefreshToken(ctx context.Context, s logical.Storage, refreshToken string) (*refreshTokenEntry, error) {
+	entry, err := s.Get(ctx, refreshTokenPath+oidcTokenHash(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var refreshTokenEntry refreshTokenEntry
+	if err := entry.DecodeJSON(&refreshTokenEntry); err != nil {
+		return nil, err
+	}
+
+	return &refreshTokenEntry, nil
+}
+
+func (i *IdentityStore) getRefreshTokenFamily(ctx context.Context, s logical.Storage, familyID string) (*refreshTokenFamily, error) {
+	entry, err := s.Get(ctx, refreshTokenFamilyPath+familyID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var family refreshTokenFamily
+	if err := entry.DecodeJSON(&family); err != nil {
+		return nil, err
+	}
+
+	return &family, nil
+}
+
+// revokeRefreshTokenFamily revokes the refresh tokens of the family and the
+// access tokens issued along with them. The family is kept until it expires.
+func (i *IdentityStore) revokeRefreshTokenFamily(ctx context.Context, s logical.Storage, familyID string) error {
+	family, err := i.getRefreshTokenFamily(ctx, s, familyID)
+	if err != nil {
+		return err
+	}
+	if family == nil || family.Revoked {
+		return nil
+	}
+
+	family.Revoked = true
+	return putOIDCStorageEntry(ctx, s, refreshTokenFamilyPath+familyID, family)
+}
+
+// tidyOIDCProviderTokens deletes the expired refresh tokens, refresh token
+// families and access token revocations.
+func (i *IdentityStore) tidyOIDCProviderTokens(ctx context.Context, s logical.Storage) error {
+	now := time.Now()
+	for _, prefix := range []string{refreshTokenPath, refreshTokenFamilyPath, revokedAccessTokenPath} {
+		keys, err := s.List(ctx, prefix)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			entry, err := s.Get(ctx, prefix+key)
+			if err != nil {
+				return err
+			}
+			if entry == nil {
+				continue
+			}
+
+			// All of the entries have an expiry
+			var expiring struct {
+				Expiry time.Time `json:"expiry"`
+			}
+			if err := entry.DecodeJSON(&expiring); err != nil {
+				return err
+			}
+			if now.After(expiring.Expiry) {
+				if err := s.Delete(ctx, prefix+key); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func putOIDCStorageEntry(ctx context.Context, s logical.Storage, key string, v interface{}) error {
+	entry, err := logical.StorageEntryJSON(key, v)
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, entry)
+}
+
+// oidcTokenHash returns the key under which state about a token is stored.
+func oidcTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func (i *IdentityStore) pathOIDCUserInfo(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	// Get the namespace
 	ns, err := namespace.FromContext(ctx)
@@ -2174,6 +3424,13 @@ func (i *IdentityStore) pathOIDCUserInfo(ctx context.Context, req *logical.Reque
 	if te.Type != logical.TokenTypeBatch {
 		return userInfoResponse(nil, ErrUserInfoInvalidToken, "access token is malformed or invalid")
 	}
+	revoked, err := i.oidcAccessTokenRevoked(ctx, req.Storage, te)
+	if err != nil {
+		return userInfoResponse(nil, ErrUserInfoServerError, err.Error())
+	}
+	if revoked {
+		return userInfoResponse(nil, ErrUserInfoInvalidToken, "access token is revoked")
+	}
 
 	// Get the client ID that originated the request from the token metadata
 	clientID, ok := te.InternalMeta[accessTokenClientIDMeta]
@@ -603,6 +603,463 @@ func TestOIDC_Path_OIDC_Token(t *testing.T) {
 	}
 }
 
+// testOIDCTokenResponse holds the fields of token, revocation and
+// introspection responses used by the tests.
+type testOIDCTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	RefreshToken     string `json:"refresh_token"`
+	Scope            string `json:"scope"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Active           bool   `json:"active"`
+	ClientID         string `json:"client_id"`
+	Subject          string `json:"sub"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func testOIDCRequest(t *testing.T, c *Core, req *logical.Request) *testOIDCTokenResponse {
+	t.Helper()
+	resp, err := c.identityStore.HandleRequest(namespace.RootContext(nil), req)
+	require.NoError(t, err)
+
+	var res testOIDCTokenResponse
+	require.NoError(t, json.Unmarshal(resp.Data["http_raw_body"].([]byte), &res))
+	return &res
+}
+
+func testOIDCTokenManagementReq(s logical.Storage, endpoint, token, clientID, clientSecret string) *logical.Request {
+	return &logical.Request{
+		Storage:   s,
+		Path:      "oidc/provider/test-provider/" + endpoint,
+		Operation: logical.UpdateOperation,
+		Headers: map[string][]string{
+			"Authorization": {basicAuthHeader(clientID, clientSecret)},
+		},
+		Data: map[string]interface{}{
+			"token": token,
+		},
+	}
+}
+
+// testOIDCAuthorizationCodeGrant obtains tokens with the authorization code
+// grant.
+func testOIDCAuthorizationCodeGrant(t *testing.T, c *Core, s logical.Storage, entityID, clientID, clientSecret string) *testOIDCTokenResponse {
+	t.Helper()
+	var authRes struct {
+		Code string `json:"code"`
+	}
+	req := testAuthorizeReq(s, clientID)
+	req.EntityID = entityID
+	req.Data["scope"] = "openid test-scope"
+	resp, err := c.identityStore.HandleRequest(namespace.RootContext(nil), req)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(resp.Data["http_raw_body"].([]byte), &authRes))
+
+	res := testOIDCRequest(t, c, testTokenReq(s, authRes.Code, clientID, clientSecret))
+	require.Empty(t, res.Error, res.ErrorDescription)
+	return res
+}
+
+func TestOIDC_Path_OIDC_Token_RefreshToken(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	s := new(logical.InmemStorage)
+
+	entityID, _, _, clientID, clientSecret := setupOIDCCommon(t, c, s)
+
+	refreshReq := func(refreshToken string) *logical.Request {
+		req := testTokenReq(s, "", clientID, clientSecret)
+		req.Data = map[string]interface{}{
+			"grant_type":    "refresh_token",
+			"refresh_token": refreshToken,
+		}
+		return req
+	}
+
+	// Refresh tokens are not issued by default
+	res := testOIDCAuthorizationCodeGrant(t, c, s, entityID, clientID, clientSecret)
+	require.NotEmpty(t, res.AccessToken)
+	require.Empty(t, res.RefreshToken)
+	res = testOIDCRequest(t, c, refreshReq("hvo_refresh_unknown"))
+	require.Equal(t, ErrTokenUnauthorizedClient, res.Error)
+
+	req := testClientReq(s)
+	req.Operation = logical.UpdateOperation
+	req.Data["refresh_token_ttl"] = "1h"
+	resp, err := c.identityStore.HandleRequest(ctx, req)
+	expectSuccess(t, resp, err)
+
+	first := testOIDCAuthorizationCodeGrant(t, c, s, entityID, clientID, clientSecret)
+	require.True(t, strings.HasPrefix(first.RefreshToken, refreshTokenPrefix))
+
+	// Exchange the refresh token for new tokens
+	second := testOIDCRequest(t, c, refreshReq(first.RefreshToken))
+	require.Empty(t, second.Error, second.ErrorDescription)
+	require.NotEmpty(t, second.AccessToken)
+	require.NotEmpty(t, second.IDToken)
+	require.NotEmpty(t, second.RefreshToken)
+	require.NotEqual(t, first.RefreshToken, second.RefreshToken)
+	require.Equal(t, int64(86400), second.ExpiresIn)
+
+	// Scopes can only be narrowed
+	req = refreshReq(second.RefreshToken)
+	req.Data["scope"] = "openid conflict"
+	res = testOIDCRequest(t, c, req)
+	require.Equal(t, ErrTokenInvalidScope, res.Error)
+
+	// Other clients can't use the refresh token
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Storage:   s,
+		Path:      "oidc/client/other-client",
+		Operation: logical.CreateOperation,
+		Data: map[string]interface{}{
+			"key":               "test-key",
+			"refresh_token_ttl": "1h",
+		},
+	})
+	expectSuccess(t, resp, err)
+	otherClient, err := c.identityStore.clientByName(ctx, s, "other-client")
+	require.NoError(t, err)
+	req = testProviderReq(s, clientID)
+	req.Operation = logical.UpdateOperation
+	req.Data["allowed_client_ids"] = []string{clientID, otherClient.ClientID}
+	resp, err = c.identityStore.HandleRequest(ctx, req)
+	expectSuccess(t, resp, err)
+	req = refreshReq(second.RefreshToken)
+	req.Headers["Authorization"] = []string{basicAuthHeader(otherClient.ClientID, otherClient.ClientSecret)}
+	res = testOIDCRequest(t, c, req)
+	require.Equal(t, ErrTokenInvalidGrant, res.Error)
+	require.Equal(t, "refresh token was not issued to the client", res.ErrorDescription)
+
+	// Reusing a rotated refresh token revokes the whole family
+	res = testOIDCRequest(t, c, refreshReq(first.RefreshToken))
+	require.Equal(t, ErrTokenInvalidGrant, res.Error)
+	res = testOIDCRequest(t, c, refreshReq(second.RefreshToken))
+	require.Equal(t, ErrTokenInvalidGrant, res.Error)
+	require.Equal(t, "refresh token is revoked", res.ErrorDescription)
+	res = testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "introspect", second.AccessToken, clientID, clientSecret))
+	require.False(t, res.Active)
+
+	// Expired refresh tokens are tidied
+	third := testOIDCAuthorizationCodeGrant(t, c, s, entityID, clientID, clientSecret)
+	entry, err := c.identityStore.getRefreshToken(ctx, s, third.RefreshToken)
+	require.NoError(t, err)
+	entry.Expiry = time.Now().Add(-time.Minute)
+	require.NoError(t, putOIDCStorageEntry(ctx, s, refreshTokenPath+oidcTokenHash(third.RefreshToken), entry))
+	res = testOIDCRequest(t, c, refreshReq(third.RefreshToken))
+	require.Equal(t, ErrTokenInvalidGrant, res.Error)
+	require.Equal(t, "refresh token is invalid or expired", res.ErrorDescription)
+	require.NoError(t, c.identityStore.tidyOIDCProviderTokens(ctx, s))
+	entry, err = c.identityStore.getRefreshToken(ctx, s, third.RefreshToken)
+	require.NoError(t, err)
+	require.Nil(t, entry)
+}
+
+func TestOIDC_Path_OIDC_Token_ClientCredentials(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	s := new(logical.InmemStorage)
+
+	_, _, _, clientID, clientSecret := setupOIDCCommon(t, c, s)
+
+	clientCredentialsReq := func(scope string) *logical.Request {
+		req := testTokenReq(s, "", clientID, clientSecret)
+		req.Data = map[string]interface{}{
+			"grant_type": "client_credentials",
+			"scope":      scope,
+		}
+		return req
+	}
+
+	// The grant must be allowed for the client
+	res := testOIDCRequest(t, c, clientCredentialsReq(""))
+	require.Equal(t, ErrTokenUnauthorizedClient, res.Error)
+
+	req := testClientReq(s)
+	req.Operation = logical.UpdateOperation
+	req.Data["allow_client_credentials"] = true
+	resp, err := c.identityStore.HandleRequest(ctx, req)
+	expectSuccess(t, resp, err)
+
+	res = testOIDCRequest(t, c, clientCredentialsReq("test-scope"))
+	require.Empty(t, res.Error, res.ErrorDescription)
+	require.NotEmpty(t, res.AccessToken)
+	require.Empty(t, res.IDToken)
+	require.Empty(t, res.RefreshToken)
+	require.Equal(t, "test-scope", res.Scope)
+
+	introspection := testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "introspect", res.AccessToken, clientID, clientSecret))
+	require.True(t, introspection.Active)
+	require.Equal(t, clientID, introspection.ClientID)
+	require.Equal(t, "test-scope", introspection.Scope)
+	require.Empty(t, introspection.Subject)
+
+	// The openid scope requires an end-user
+	res = testOIDCRequest(t, c, clientCredentialsReq("openid"))
+	require.Equal(t, ErrTokenInvalidScope, res.Error)
+
+	// Public clients can't use the grant
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Storage:   s,
+		Path:      "oidc/client/public-client",
+		Operation: logical.CreateOperation,
+		Data: map[string]interface{}{
+			"key":                      "test-key",
+			"client_type":              "public",
+			"allow_client_credentials": true,
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, resp.IsError())
+}
+
+func TestOIDC_Path_OIDC_Token_DeviceCode(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	s := new(logical.InmemStorage)
+
+	entityID, _, _, clientID, clientSecret := setupOIDCCommon(t, c, s)
+
+	var deviceRes struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int64  `json:"expires_in"`
+		Interval                int64  `json:"interval"`
+	}
+	deviceAuthorization := func() {
+		t.Helper()
+		resp, err := c.identityStore.HandleRequest(ctx, &logical.Request{
+			Storage:   s,
+			Path:      "oidc/provider/test-provider/device_authorization",
+			Operation: logical.UpdateOperation,
+			Data: map[string]interface{}{
+				"client_id":     clientID,
+				"client_secret": clientSecret,
+				"scope":         "openid test-scope",
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(resp.Data["http_raw_body"].([]byte), &deviceRes))
+	}
+	tokenReq := func() *logical.Request {
+		req := testTokenReq(s, "", clientID, clientSecret)
+		req.Data = map[string]interface{}{
+			"grant_type":  "urn:ietf:params:oauth:grant-type:device_code",
+			"device_code": deviceRes.DeviceCode,
+		}
+		return req
+	}
+	deviceReq := func(operation logical.Operation, userCode string) *logical.Request {
+		return &logical.Request{
+			Storage:   s,
+			Path:      "oidc/provider/test-provider/device",
+			Operation: operation,
+			EntityID:  entityID,
+			Data: map[string]interface{}{
+				"user_code": userCode,
+			},
+		}
+	}
+
+	deviceAuthorization()
+	require.Regexp(t, "^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$", deviceRes.UserCode)
+	require.Equal(t, "/ui/vault/identity/oidc/provider/test-provider/device", deviceRes.VerificationURI)
+	require.Equal(t, deviceRes.VerificationURI+"?user_code="+deviceRes.UserCode, deviceRes.VerificationURIComplete)
+	require.Equal(t, int64(600), deviceRes.ExpiresIn)
+	require.Equal(t, int64(5), deviceRes.Interval)
+
+	// The device polls before the end-user approves the request
+	res := testOIDCRequest(t, c, tokenReq())
+	require.Equal(t, ErrTokenAuthorizationPending, res.Error)
+	res = testOIDCRequest(t, c, tokenReq())
+	require.Equal(t, ErrTokenSlowDown, res.Error)
+
+	// The end-user reads the request, with a differently formatted user code
+	resp, err := c.identityStore.HandleRequest(ctx, deviceReq(logical.ReadOperation,
+		strings.ToLower(strings.ReplaceAll(deviceRes.UserCode, "-", ""))))
+	expectSuccess(t, resp, err)
+	require.Equal(t, "test-client", resp.Data["client_name"])
+	require.Equal(t, []string{"test-scope"}, resp.Data["scopes"])
+
+	// Unknown user codes are rejected
+	resp, err = c.identityStore.HandleRequest(ctx, deviceReq(logical.UpdateOperation, "BCDF-GHJK"))
+	require.NoError(t, err)
+	require.True(t, resp.IsError())
+
+	// Requests need an entity allowed by the client assignments
+	req := deviceReq(logical.UpdateOperation, deviceRes.UserCode)
+	req.EntityID = ""
+	_, err = c.identityStore.HandleRequest(ctx, req)
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+
+	// The end-user approves the request
+	rootToken, err := c.tokenStore.rootToken(ctx)
+	require.NoError(t, err)
+	req = deviceReq(logical.UpdateOperation, deviceRes.UserCode)
+	req.ClientToken = rootToken.ID
+	resp, err = c.identityStore.HandleRequest(ctx, req)
+	expectSuccess(t, resp, err)
+
+	// The approved device code is exchanged once
+	res = testOIDCRequest(t, c, tokenReq())
+	require.Empty(t, res.Error, res.ErrorDescription)
+	require.NotEmpty(t, res.AccessToken)
+	require.NotEmpty(t, res.IDToken)
+	res = testOIDCRequest(t, c, tokenReq())
+	require.Equal(t, ErrTokenInvalidGrant, res.Error)
+
+	// The end-user denies a request
+	deviceAuthorization()
+	req = deviceReq(logical.UpdateOperation, deviceRes.UserCode)
+	req.Data["deny"] = true
+	resp, err = c.identityStore.HandleRequest(ctx, req)
+	expectSuccess(t, resp, err)
+	res = testOIDCRequest(t, c, tokenReq())
+	require.Equal(t, ErrTokenAccessDenied, res.Error)
+
+	// Device codes expire
+	deviceAuthorization()
+	entryRaw, ok, err := c.identityStore.oidcDeviceCodeCache.Get(namespace.RootNamespace, deviceRes.DeviceCode)
+	require.NoError(t, err)
+	require.True(t, ok)
+	entryRaw.(*deviceCodeCacheEntry).expiry = time.Now().Add(-time.Second)
+	res = testOIDCRequest(t, c, tokenReq())
+	require.Equal(t, ErrTokenExpiredToken, res.Error)
+
+	// The openid scope is required
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Storage:   s,
+		Path:      "oidc/provider/test-provider/device_authorization",
+		Operation: logical.UpdateOperation,
+		Data: map[string]interface{}{
+			"client_id":     clientID,
+			"client_secret": clientSecret,
+			"scope":         "test-scope",
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(resp.Data["http_raw_body"].([]byte), &res))
+	require.Equal(t, ErrTokenInvalidScope, res.Error)
+}
+
+func TestOIDC_Path_OIDC_Revoke_Introspect(t *testing.T) {
+	c, _, rootToken := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	s := new(logical.InmemStorage)
+
+	entityID, _, _, clientID, clientSecret := setupOIDCCommon(t, c, s)
+
+	req := testClientReq(s)
+	req.Operation = logical.UpdateOperation
+	req.Data["refresh_token_ttl"] = "1h"
+	resp, err := c.identityStore.HandleRequest(ctx, req)
+	expectSuccess(t, resp, err)
+
+	userInfoReq := func(accessToken string) *logical.Request {
+		return &logical.Request{
+			Storage:           s,
+			Path:              "oidc/provider/test-provider/userinfo",
+			Operation:         logical.ReadOperation,
+			ClientToken:       accessToken,
+			ClientTokenSource: logical.ClientTokenFromAuthzHeader,
+			EntityID:          entityID,
+		}
+	}
+
+	tokens := testOIDCAuthorizationCodeGrant(t, c, s, entityID, clientID, clientSecret)
+
+	// Introspect the tokens
+	res := testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "introspect", tokens.AccessToken, clientID, clientSecret))
+	require.True(t, res.Active)
+	require.Equal(t, clientID, res.ClientID)
+	require.Equal(t, entityID, res.Subject)
+	require.Equal(t, "test-scope", res.Scope)
+	res = testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "introspect", tokens.RefreshToken, clientID, clientSecret))
+	require.True(t, res.Active)
+	res = testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "introspect", "not-a-token", clientID, clientSecret))
+	require.False(t, res.Active)
+
+	// Clients must authenticate
+	res = testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "introspect", tokens.AccessToken, clientID, "wrong"))
+	require.Equal(t, ErrTokenInvalidClient, res.Error)
+
+	// Create another confidential client and a public client allowed on the provider
+	otherClient := func(name, clientType string) (string, string) {
+		t.Helper()
+		resp, err := c.identityStore.HandleRequest(ctx, &logical.Request{
+			Storage:   s,
+			Path:      "oidc/client/" + name,
+			Operation: logical.CreateOperation,
+			Data: map[string]interface{}{
+				"key":           "test-key",
+				"redirect_uris": []string{"https://localhost:8251/callback"},
+				"assignments":   []string{"test-assignment"},
+				"client_type":   clientType,
+			},
+		})
+		expectSuccess(t, resp, err)
+		resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+			Storage:   s,
+			Path:      "oidc/client/" + name,
+			Operation: logical.ReadOperation,
+		})
+		expectSuccess(t, resp, err)
+		secret, _ := resp.Data["client_secret"].(string)
+		return resp.Data["client_id"].(string), secret
+	}
+	otherClientID, otherClientSecret := otherClient("other-client", "confidential")
+	publicClientID, _ := otherClient("public-client", "public")
+	req = testProviderReq(s, clientID)
+	req.Operation = logical.UpdateOperation
+	req.Data["allowed_client_ids"] = []string{clientID, otherClientID, publicClientID}
+	resp, err = c.identityStore.HandleRequest(ctx, req)
+	expectSuccess(t, resp, err)
+
+	// Tokens issued to other clients are not active
+	res = testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "introspect", tokens.AccessToken, otherClientID, otherClientSecret))
+	require.Empty(t, res.Error, res.ErrorDescription)
+	require.False(t, res.Active)
+	require.Empty(t, res.Subject)
+	res = testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "introspect", tokens.RefreshToken, otherClientID, otherClientSecret))
+	require.Empty(t, res.Error, res.ErrorDescription)
+	require.False(t, res.Active)
+
+	// Public clients can't introspect tokens
+	res = testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "introspect", tokens.AccessToken, publicClientID, ""))
+	require.Equal(t, ErrTokenInvalidClient, res.Error)
+
+	// Vault tokens other than access tokens of the provider are not active
+	res = testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "introspect", rootToken, clientID, clientSecret))
+	require.False(t, res.Active)
+
+	// Revoke the access token
+	res = testOIDCRequest(t, c, userInfoReq(tokens.AccessToken))
+	require.Empty(t, res.Error, res.ErrorDescription)
+	res = testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "revoke", tokens.AccessToken, clientID, clientSecret))
+	require.Empty(t, res.Error, res.ErrorDescription)
+	res = testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "introspect", tokens.AccessToken, clientID, clientSecret))
+	require.False(t, res.Active)
+	res = testOIDCRequest(t, c, userInfoReq(tokens.AccessToken))
+	require.Equal(t, ErrUserInfoInvalidToken, res.Error)
+	require.Equal(t, "access token is revoked", res.ErrorDescription)
+
+	// Revoking a refresh token revokes the access tokens issued with it
+	tokens = testOIDCAuthorizationCodeGrant(t, c, s, entityID, clientID, clientSecret)
+	res = testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "revoke", tokens.RefreshToken, clientID, clientSecret))
+	require.Empty(t, res.Error, res.ErrorDescription)
+	res = testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "introspect", tokens.RefreshToken, clientID, clientSecret))
+	require.False(t, res.Active)
+	res = testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "introspect", tokens.AccessToken, clientID, clientSecret))
+	require.False(t, res.Active)
+
+	// Invalid tokens are ignored
+	res = testOIDCRequest(t, c, testOIDCTokenManagementReq(s, "revoke", "hvo_refresh_unknown", clientID, clientSecret))
+	require.Empty(t, res.Error, res.ErrorDescription)
+}
+
 func TestOIDC_Path_OIDC_Authorize(t *testing.T) {
 	c, _, _ := TestCoreUnsealed(t)
 	ctx := namespace.RootContext(nil)
@@ -1893,14 +2350,16 @@ func TestOIDC_Path_OIDC_ProviderClient(t *testing.T) {
 	})
 	expectSuccess(t, resp, err)
 	expected := map[string]interface{}{
-		"redirect_uris":    []string{},
-		"assignments":      []string{},
-		"key":              "test-key",
-		"id_token_ttl":     int64(60),
-		"access_token_ttl": int64(86400),
-		"client_id":        resp.Data["client_id"],
-		"client_secret":    resp.Data["client_secret"],
-		"client_type":      confidential.String(),
+		"redirect_uris":            []string{},
+		"assignments":              []string{},
+		"key":                      "test-key",
+		"id_token_ttl":             int64(60),
+		"access_token_ttl":         int64(86400),
+		"client_id":                resp.Data["client_id"],
+		"client_secret":            resp.Data["client_secret"],
+		"client_type":              confidential.String(),
+		"refresh_token_ttl":        int64(0),
+		"allow_client_credentials": false,
 	}
 	if diff := deep.Equal(expected, resp.Data); diff != nil {
 		t.Fatal(diff)
@@ -1948,14 +2407,16 @@ func TestOIDC_Path_OIDC_ProviderClient(t *testing.T) {
 	})
 	expectSuccess(t, resp, err)
 	expected = map[string]interface{}{
-		"redirect_uris":    []string{"http://localhost:3456/callback"},
-		"assignments":      []string{"my-assignment"},
-		"key":              "test-key",
-		"id_token_ttl":     int64(90),
-		"access_token_ttl": int64(60),
-		"client_id":        resp.Data["client_id"],
-		"client_secret":    resp.Data["client_secret"],
-		"client_type":      confidential.String(),
+		"redirect_uris":            []string{"http://localhost:3456/callback"},
+		"assignments":              []string{"my-assignment"},
+		"key":                      "test-key",
+		"id_token_ttl":             int64(90),
+		"access_token_ttl":         int64(60),
+		"client_id":                resp.Data["client_id"],
+		"client_secret":            resp.Data["client_secret"],
+		"client_type":              confidential.String(),
+		"refresh_token_ttl":        int64(0),
+		"allow_client_credentials": false,
 	}
 	if diff := deep.Equal(expected, resp.Data); diff != nil {
 		t.Fatal(diff)
@@ -2011,11 +2472,13 @@ func TestOIDC_Path_OIDC_ProviderClient_Deduplication(t *testing.T) {
 		Operation: logical.CreateOperation,
 		Storage:   storage,
 		Data: map[string]interface{}{
-			"key":           "test-key",
-			"id_token_ttl":  "1m",
-			"assignments":   []string{"test-assignment1", "test-assignment1"},
-			"redirect_uris": []string{"http://example.com", "http://notduplicate.com", "http://example.com"},
-			"client_type":   public.String(),
+			"key":                      "test-key",
+			"id_token_ttl":             "1m",
+			"assignments":              []string{"test-assignment1", "test-assignment1"},
+			"redirect_uris":            []string{"http://example.com", "http://notduplicate.com", "http://example.com"},
+			"client_type":              public.String(),
+			"refresh_token_ttl":        int64(0),
+			"allow_client_credentials": false,
 		},
 	})
 	expectSuccess(t, resp, err)
@@ -2028,13 +2491,15 @@ func TestOIDC_Path_OIDC_ProviderClient_Deduplication(t *testing.T) {
 	})
 	expectSuccess(t, resp, err)
 	expected := map[string]interface{}{
-		"redirect_uris":    []string{"http://example.com", "http://notduplicate.com"},
-		"assignments":      []string{"test-assignment1"},
-		"key":              "test-key",
-		"id_token_ttl":     int64(60),
-		"access_token_ttl": int64(86400),
-		"client_id":        resp.Data["client_id"],
-		"client_type":      public.String(),
+		"redirect_uris":            []string{"http://example.com", "http://notduplicate.com"},
+		"assignments":              []string{"test-assignment1"},
+		"key":                      "test-key",
+		"id_token_ttl":             int64(60),
+		"access_token_ttl":         int64(86400),
+		"client_id":                resp.Data["client_id"],
+		"client_type":              public.String(),
+		"refresh_token_ttl":        int64(0),
+		"allow_client_credentials": false,
 	}
 	if diff := deep.Equal(expected, resp.Data); diff != nil {
 		t.Fatal(diff)
@@ -2089,14 +2554,16 @@ func TestOIDC_Path_OIDC_ProviderClient_Update(t *testing.T) {
 	})
 	expectSuccess(t, resp, err)
 	expected := map[string]interface{}{
-		"redirect_uris":    []string{"http://localhost:3456/callback"},
-		"assignments":      []string{"my-assignment"},
-		"key":              "test-key",
-		"id_token_ttl":     int64(120),
-		"access_token_ttl": int64(3600),
-		"client_id":        resp.Data["client_id"],
-		"client_secret":    resp.Data["client_secret"],
-		"client_type":      confidential.String(),
+		"redirect_uris":            []string{"http://localhost:3456/callback"},
+		"assignments":              []string{"my-assignment"},
+		"key":                      "test-key",
+		"id_token_ttl":             int64(120),
+		"access_token_ttl":         int64(3600),
+		"client_id":                resp.Data["client_id"],
+		"client_secret":            resp.Data["client_secret"],
+		"client_type":              confidential.String(),
+		"refresh_token_ttl":        int64(0),
+		"allow_client_credentials": false,
 	}
 	if diff := deep.Equal(expected, resp.Data); diff != nil {
 		t.Fatal(diff)
@@ -2123,14 +2590,16 @@ func TestOIDC_Path_OIDC_ProviderClient_Update(t *testing.T) {
 	})
 	expectSuccess(t, resp, err)
 	expected = map[string]interface{}{
-		"redirect_uris":    []string{"http://localhost:3456/callback2"},
-		"assignments":      []string{"my-assignment"},
-		"key":              "test-key",
-		"id_token_ttl":     int64(30),
-		"access_token_ttl": int64(60),
-		"client_id":        resp.Data["client_id"],
-		"client_secret":    resp.Data["client_secret"],
-		"client_type":      confidential.String(),
+		"redirect_uris":            []string{"http://localhost:3456/callback2"},
+		"assignments":              []string{"my-assignment"},
+		"key":                      "test-key",
+		"id_token_ttl":             int64(30),
+		"access_token_ttl":         int64(60),
+		"client_id":                resp.Data["client_id"],
+		"client_secret":            resp.Data["client_secret"],
+		"client_type":              confidential.String(),
+		"refresh_token_ttl":        int64(0),
+		"allow_client_credentials": false,
 	}
 	if diff := deep.Equal(expected, resp.Data); diff != nil {
 		t.Fatal(diff)
@@ -3629,7 +4098,10 @@ func TestOIDC_Path_OpenIDProviderConfig(t *testing.T) {
 		AuthorizationEndpoint: "/ui/vault/identity/oidc/provider/test-provider/authorize",
 		TokenEndpoint:         basePath + "/token",
 		UserinfoEndpoint:      basePath + "/userinfo",
-		GrantTypes:            []string{"authorization_code"},
+		RevocationEndpoint:    basePath + "/revoke",
+		IntrospectionEndpoint: basePath + "/introspect",
+		DeviceEndpoint:        basePath + "/device_authorization",
+		GrantTypes:            []string{"authorization_code", "refresh_token", "client_credentials", "urn:ietf:params:oauth:grant-type:device_code"},
 		AuthMethods:           []string{"none", "client_secret_basic", "client_secret_post"},
 		RequestParameter:      false,
 		RequestURIParameter:   false,
@@ -3685,7 +4157,10 @@ func TestOIDC_Path_OpenIDProviderConfig(t *testing.T) {
 		AuthorizationEndpoint: testIssuer + "/ui/vault/identity/oidc/provider/test-provider/authorize",
 		TokenEndpoint:         basePath + "/token",
 		UserinfoEndpoint:      basePath + "/userinfo",
-		GrantTypes:            []string{"authorization_code"},
+		RevocationEndpoint:    basePath + "/revoke",
+		IntrospectionEndpoint: basePath + "/introspect",
+		DeviceEndpoint:        basePath + "/device_authorization",
+		GrantTypes:            []string{"authorization_code", "refresh_token", "client_credentials", "urn:ietf:params:oauth:grant-type:device_code"},
 		AuthMethods:           []string{"none", "client_secret_basic", "client_secret_post"},
 		RequestParameter:      false,
 		RequestURIParameter:   false,
@@ -3717,3 +4192,32 @@ func TestOIDC_Path_OpenIDProviderConfig_ProviderDoesNotExist(t *testing.T) {
 		t.Fatalf("expected empty response but got success; error:\n%v\nresp: %#v", err, resp)
 	}
 }
+
+func TestOIDC_ProviderUIURL(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	i := c.identityStore
+	i.redirectAddr = "https://vault.example.com:8200"
+
+	tests := []struct {
+		issuer string
+		ns     *namespace.Namespace
+		want   string
+	}{
+		{
+			issuer: "",
+			ns:     namespace.RootNamespace,
+			want:   "https://vault.example.com:8200/ui/vault/identity/oidc/provider/test-provider",
+		},
+		{
+			issuer: "https://example.com/v1/vault/",
+			ns:     &namespace.Namespace{ID: "ns1", Path: "ns1/"},
+			want:   "https://example.com/v1/vault/ui/vault/ns1/identity/oidc/provider/test-provider",
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := i.oidcProviderUIURL(tt.ns, "test-provider", &provider{Issuer: tt.issuer})
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got)
+	}
+}
@@ -0,0 +1,1100 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/vault/helper/identity"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	scimContentType       = "application/scim+json"
+	scimConfigStorageKey  = "scim/config"
+	scimDefaultCount      = 100
+	scimMaxCount          = 1000
+	scimResourceTypeUser  = "User"
+	scimResourceTypeGroup = "Group"
+
+	// SCIM attributes that have no counterpart on entities and groups are
+	// kept in their metadata under these keys
+	scimMetaExternalID  = "scim_external_id"
+	scimMetaDisplayName = "scim_display_name"
+	scimMetaFormatted   = "scim_formatted_name"
+	scimMetaGivenName   = "scim_given_name"
+	scimMetaFamilyName  = "scim_family_name"
+	scimMetaEmail       = "scim_email"
+
+	// scimMetaProvisioned marks the entities and groups created over SCIM,
+	// the only ones the SCIM endpoints expose and modify
+	scimMetaProvisioned = "scim_provisioned"
+)
+
+var scimUserMetadataKeys = []string{
+	scimMetaExternalID,
+	scimMetaDisplayName,
+	scimMetaFormatted,
+	scimMetaGivenName,
+	scimMetaFamilyName,
+	scimMetaEmail,
+}
+
+type scimConfig struct {
+	AliasMountAccessor string `json:"alias_mount_accessor"`
+}
+
+// scimPaths returns the SCIM 2.0 provisioning endpoints. Users map onto
+// entities and Groups onto internal groups.
+func scimPaths(i *IdentityStore) []*framework.Path {
+	listFields := map[string]*framework.FieldSchema{
+		"filter": {
+			Type:        framework.TypeString,
+			Description: "SCIM filter expression to select resources with.",
+		},
+		"startIndex": {
+			Type:        framework.TypeInt,
+			Description: "1-based index of the first result to return.",
+			Default:     1,
+		},
+		"count": {
+			Type:        framework.TypeInt,
+			Description: "Maximum number of results to return.",
+			Default:     scimDefaultCount,
+		},
+	}
+	idFields := map[string]*framework.FieldSchema{
+		"id": {
+			Type:        framework.TypeString,
+			Description: "ID of the resource.",
+		},
+	}
+
+	return []*framework.Path{
+		{
+			Pattern: "scim/config$",
+			Fields: map[string]*framework.FieldSchema{
+				"alias_mount_accessor": {
+					Type:        framework.TypeString,
+					Description: "Accessor of the auth mount on which an alias named after the userName is kept for each provisioned user.",
+				},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   i.pathSCIMConfigRead,
+				logical.UpdateOperation: i.pathSCIMConfigUpdate,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(scimHelp["scim-config"][0]),
+			HelpDescription: strings.TrimSpace(scimHelp["scim-config"][1]),
+		},
+		{
+			Pattern: "scim/v2/ServiceProviderConfig$",
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation: scimOperation(i.pathSCIMServiceProviderConfig),
+			},
+
+			HelpSynopsis:    strings.TrimSpace(scimHelp["scim-service-provider-config"][0]),
+			HelpDescription: strings.TrimSpace(scimHelp["scim-service-provider-config"][1]),
+		},
+		{
+			Pattern:             "scim/v2/Users$",
+			Fields:              listFields,
+			TakesArbitraryInput: true,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   scimOperation(i.pathSCIMUsersList),
+				logical.UpdateOperation: scimOperation(i.pathSCIMUserCreate),
+			},
+
+			HelpSynopsis:    strings.TrimSpace(scimHelp["scim-users"][0]),
+			HelpDescription: strings.TrimSpace(scimHelp["scim-users"][1]),
+		},
+		{
+			Pattern:             "scim/v2/Users/" + framework.GenericNameRegex("id") + "$",
+			Fields:              idFields,
+			TakesArbitraryInput: true,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   scimOperation(i.pathSCIMUserRead),
+				logical.UpdateOperation: scimOperation(i.pathSCIMUserReplace),
+				logical.PatchOperation:  scimOperation(i.pathSCIMUserPatch),
+				logical.DeleteOperation: scimOperation(i.pathSCIMUserDelete),
+			},
+
+			HelpSynopsis:    strings.TrimSpace(scimHelp["scim-user"][0]),
+			HelpDescription: strings.TrimSpace(scimHelp["scim-user"][1]),
+		},
+		{
+			Pattern:             "scim/v2/Groups$",
+			Fields:              listFields,
+			TakesArbitraryInput: true,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   scimOperation(i.pathSCIMGroupsList),
+				logical.UpdateOperation: scimOperation(i.pathSCIMGroupCreate),
+			},
+
+			HelpSynopsis:    strings.TrimSpace(scimHelp["scim-groups"][0]),
+			HelpDescription: strings.TrimSpace(scimHelp["scim-groups"][1]),
+		},
+		{
+			Pattern:             "scim/v2/Groups/" + framework.GenericNameRegex("id") + "$",
+			Fields:              idFields,
+			TakesArbitraryInput: true,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   scimOperation(i.pathSCIMGroupRead),
+				logical.UpdateOperation: scimOperation(i.pathSCIMGroupReplace),
+				logical.PatchOperation:  scimOperation(i.pathSCIMGroupPatch),
+				logical.DeleteOperation: scimOperation(i.pathSCIMGroupDelete),
+			},
+
+			HelpSynopsis:    strings.TrimSpace(scimHelp["scim-group"][0]),
+			HelpDescription: strings.TrimSpace(scimHelp["scim-group"][1]),
+		},
+	}
+}
+
+// scimOperation sends SCIM errors returned by a handler to the client in
+// the SCIM error format
+func scimOperation(f framework.OperationFunc) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		resp, err := f(ctx, req, d)
+		var scimErr *scimError
+		if errors.As(err, &scimErr) {
+			return scimResponse(scimErr.code, scimErr)
+		}
+		return resp, err
+	}
+}
+
+func scimResponse(status int, body interface{}) (*logical.Response, error) {
+	if body == nil {
+		return &logical.Response{
+			Data: map[string]interface{}{
+				logical.HTTPStatusCode: status,
+			},
+		}, nil
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			logical.HTTPStatusCode:  status,
+			logical.HTTPRawBody:     data,
+			logical.HTTPContentType: scimContentType,
+		},
+	}, nil
+}
+
+func (i *IdentityStore) pathSCIMConfigRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	config, err := i.getSCIMConfig(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"alias_mount_accessor": config.AliasMountAccessor,
+		},
+	}, nil
+}
+
+func (i *IdentityStore) pathSCIMConfigUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := i.getSCIMConfig(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	if mountAccessorRaw, ok := d.GetOk("alias_mount_accessor"); ok {
+		config.AliasMountAccessor = mountAccessorRaw.(string)
+	}
+
+	if config.AliasMountAccessor != "" {
+		mountEntry := i.router.MatchingMountByAccessor(config.AliasMountAccessor)
+		switch {
+		case mountEntry == nil:
+			return logical.ErrorResponse(fmt.Sprintf("invalid mount accessor %q", config.AliasMountAccessor)), nil
+		case mountEntry.NamespaceID != ns.ID:
+			return logical.ErrorResponse("matching mount is in a different namespace than request"), logical.ErrPermissionDenied
+		case mountEntry.Table != credentialTableType:
+			return logical.ErrorResponse(fmt.Sprintf("mount accessor %q does not belong to an auth mount", config.AliasMountAccessor)), nil
+		case mountEntry.Local:
+			return logical.ErrorResponse("local auth mounts are not supported"), nil
+		}
+	}
+
+	entry, err := logical.StorageEntryJSON(scimConfigStorageKey, config)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (i *IdentityStore) getSCIMConfig(ctx context.Context, s logical.Storage) (*scimConfig, error) {
+	var config scimConfig
+	entry, err := s.Get(ctx, scimConfigStorageKey)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		if err := entry.DecodeJSON(&config); err != nil {
+			return nil, err
+		}
+	}
+	return &config, nil
+}
+
+func (i *IdentityStore) pathSCIMServiceProviderConfig(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	supported := func(supported bool) map[string]interface{} {
+		return map[string]interface{}{"supported": supported}
+	}
+
+	return scimResponse(http.StatusOK, map[string]interface{}{
+		"schemas": []string{scimSchemaServiceProviderConfig},
+		"patch":   supported(true),
+		"bulk": map[string]interface{}{
+			"supported":      false,
+			"maxOperations":  0,
+			"maxPayloadSize": 0,
+		},
+		"filter": map[string]interface{}{
+			"supported":  true,
+			"maxResults": scimMaxCount,
+		},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]interface{}{
+			{
+				"type":        "oauthbearertoken",
+				"name":        "OAuth Bearer Token",
+				"description": "Authentication with a Vault token sent as a bearer token",
+				"primary":     true,
+			},
+		},
+	})
+}
+
+// scimLocation returns the URL of a SCIM resource
+func (i *IdentityStore) scimLocation(ctx context.Context, req *logical.Request, resourceType, id string) string {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/v1/%s%sscim/v2/%ss/%s", i.redirectAddr, ns.Path, req.MountPoint, resourceType, id)
+}
+
+// scimList pages through the resources matching a request
+func scimList(d *framework.FieldData, resources []interface{}) (*logical.Response, error) {
+	startIndex := d.Get("startIndex").(int)
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count := d.Get("count").(int)
+	switch {
+	case count < 0:
+		count = 0
+	case count > scimMaxCount:
+		count = scimMaxCount
+	}
+
+	total := len(resources)
+	page := []interface{}{}
+	if startIndex <= total {
+		end := startIndex - 1 + count
+		if end > total {
+			end = total
+		}
+		page = resources[startIndex-1 : end]
+	}
+
+	return scimResponse(http.StatusOK, &scimListResponse{
+		Schemas:      []string{scimSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	})
+}
+
+// scimFilterResources returns the resources that match the filter
+func scimFilterResources(filter scimFilter, resources []interface{}) ([]interface{}, error) {
+	if filter == nil {
+		return resources, nil
+	}
+
+	var matched []interface{}
+	for _, resource := range resources {
+		m, err := scimResourceMap(resource)
+		if err != nil {
+			return nil, err
+		}
+		if filter.matches(m) {
+			matched = append(matched, resource)
+		}
+	}
+	return matched, nil
+}
+
+// scimProvisioned returns whether the given entity or group metadata belongs
+// to an object created over SCIM
+func scimProvisioned(metadata map[string]string) bool {
+	return metadata[scimMetaProvisioned] == "true"
+}
+
+// scimEntity returns the entity with the given ID, provided that it is in
+// the request namespace and was provisioned over SCIM
+func (i *IdentityStore) scimEntity(ctx context.Context, id string, clone bool) (*identity.Entity, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := i.MemDBEntityByID(id, clone)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil || entity.NamespaceID != ns.ID || !scimProvisioned(entity.Metadata) {
+		return nil, scimNotFound(scimResourceTypeUser, id)
+	}
+	return entity, nil
+}
+
+// scimUserFromEntity returns the SCIM representation of an entity
+func (i *IdentityStore) scimUserFromEntity(ctx context.Context, req *logical.Request, entity *identity.Entity) (*scimUser, error) {
+	active := !entity.Disabled
+	user := &scimUser{
+		Schemas:     []string{scimSchemaUser},
+		ID:          entity.ID,
+		ExternalID:  entity.Metadata[scimMetaExternalID],
+		UserName:    entity.Name,
+		DisplayName: entity.Metadata[scimMetaDisplayName],
+		Active:      &active,
+		Meta: &scimMeta{
+			ResourceType: scimResourceTypeUser,
+			Created:      ptypes.TimestampString(entity.CreationTime),
+			LastModified: ptypes.TimestampString(entity.LastUpdateTime),
+			Location:     i.scimLocation(ctx, req, scimResourceTypeUser, entity.ID),
+		},
+	}
+
+	name := &scimName{
+		Formatted:  entity.Metadata[scimMetaFormatted],
+		FamilyName: entity.Metadata[scimMetaFamilyName],
+		GivenName:  entity.Metadata[scimMetaGivenName],
+	}
+	if *name != (scimName{}) {
+		user.Name = name
+	}
+
+	if email := entity.Metadata[scimMetaEmail]; email != "" {
+		user.Emails = []scimEmail{{Value: email, Primary: true}}
+	}
+
+	groups, err := i.MemDBGroupsByMemberEntityID(entity.ID, false, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		if group.Type != groupTypeInternal || !scimProvisioned(group.Metadata) {
+			continue
+		}
+		user.Groups = append(user.Groups, scimMember{
+			Value:   group.ID,
+			Ref:     i.scimLocation(ctx, req, scimResourceTypeGroup, group.ID),
+			Display: group.Name,
+			Type:    "direct",
+		})
+	}
+
+	return user, nil
+}
+
+func (i *IdentityStore) pathSCIMUsersList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := parseSCIMFilter(d.Get("filter").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []*identity.Entity
+	attr, value, indexed := "", "", false
+	if f, ok := filter.(*scimAttrFilter); ok {
+		attr, value, indexed = f.indexedAttr()
+	}
+	switch {
+	case indexed && attr == "username":
+		entity, err := i.MemDBEntityByName(ctx, value, false)
+		if err != nil {
+			return nil, err
+		}
+		if entity != nil {
+			entities = append(entities, entity)
+		}
+	case indexed && attr == "id":
+		entity, err := i.MemDBEntityByID(value, false)
+		if err != nil {
+			return nil, err
+		}
+		if entity != nil && entity.NamespaceID == ns.ID {
+			entities = append(entities, entity)
+		}
+	default:
+		iter, err := i.db.Txn(false).Get(entitiesTable, "namespace_id", ns.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lookup entities using namespace ID: %w", err)
+		}
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			entities = append(entities, raw.(*identity.Entity))
+		}
+	}
+
+	sort.Slice(entities, func(a, b int) bool {
+		return entities[a].ID < entities[b].ID
+	})
+
+	resources := make([]interface{}, 0, len(entities))
+	for _, entity := range entities {
+		if !scimProvisioned(entity.Metadata) {
+			continue
+		}
+		user, err := i.scimUserFromEntity(ctx, req, entity)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, user)
+	}
+
+	resources, err = scimFilterResources(filter, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	return scimList(d, resources)
+}
+
+func (i *IdentityStore) pathSCIMUserCreate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	var user scimUser
+	if err := scimDecode(req.Data, &user); err != nil {
+		return nil, err
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	entity, err := i.scimUpsertUser(ctx, req, nil, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.scimUserResponse(ctx, req, http.StatusCreated, entity)
+}
+
+func (i *IdentityStore) pathSCIMUserRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	entity, err := i.scimEntity(ctx, d.Get("id").(string), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.scimUserResponse(ctx, req, http.StatusOK, entity)
+}
+
+func (i *IdentityStore) pathSCIMUserReplace(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	var user scimUser
+	if err := scimDecode(req.Data, &user); err != nil {
+		return nil, err
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	entity, err := i.scimEntity(ctx, d.Get("id").(string), true)
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err = i.scimUpsertUser(ctx, req, entity, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.scimUserResponse(ctx, req, http.StatusOK, entity)
+}
+
+func (i *IdentityStore) pathSCIMUserPatch(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ops, err := scimPatchOperations(req.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	entity, err := i.scimEntity(ctx, d.Get("id").(string), true)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := i.scimUserFromEntity(ctx, req, entity)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		if err := applySCIMUserPatch(user, op); err != nil {
+			return nil, err
+		}
+	}
+
+	entity, err = i.scimUpsertUser(ctx, req, entity, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.scimUserResponse(ctx, req, http.StatusOK, entity)
+}
+
+func (i *IdentityStore) pathSCIMUserDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	txn := i.db.Txn(true)
+	defer txn.Abort()
+
+	entity, err := i.scimEntity(ctx, d.Get("id").(string), true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.handleEntityDeleteCommon(ctx, txn, entity, true); err != nil {
+		return nil, err
+	}
+
+	txn.Commit()
+
+	return scimResponse(http.StatusNoContent, nil)
+}
+
+func (i *IdentityStore) scimUserResponse(ctx context.Context, req *logical.Request, status int, entity *identity.Entity) (*logical.Response, error) {
+	user, err := i.scimUserFromEntity(ctx, req, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return scimResponse(status, user)
+}
+
+// scimUpsertUser creates or updates the entity for a SCIM user, along with
+// its alias on the configured mount. A nil entity creates a new one. Existing
+// entities and aliases that were not provisioned over SCIM are never taken
+// over, a conflict with them is rejected instead. The caller must hold the
+// identity store lock.
+func (i *IdentityStore) scimUpsertUser(ctx context.Context, req *logical.Request, entity *identity.Entity, user *scimUser) (*identity.Entity, error) {
+	if user.UserName == "" {
+		return nil, newSCIMError(http.StatusBadRequest, "invalidValue", "userName is required")
+	}
+
+	config, err := i.getSCIMConfig(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	var existingAlias *identity.Alias
+	if config.AliasMountAccessor != "" {
+		if i.router.MatchingMountByAccessor(config.AliasMountAccessor) == nil {
+			return nil, fmt.Errorf("configured alias mount accessor %q does not match any auth mount", config.AliasMountAccessor)
+		}
+
+		existingAlias, err = i.MemDBAliasByFactors(config.AliasMountAccessor, user.UserName, false, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if existingAlias != nil && (entity == nil || existingAlias.CanonicalID != entity.ID) {
+		return nil, newSCIMError(http.StatusConflict, "uniqueness", "userName %q is in use by another entity's alias", user.UserName)
+	}
+
+	entityByName, err := i.MemDBEntityByName(ctx, user.UserName, false)
+	if err != nil {
+		return nil, err
+	}
+	if entityByName != nil && (entity == nil || entityByName.ID != entity.ID) {
+		return nil, newSCIMError(http.StatusConflict, "uniqueness", "userName %q is already in use", user.UserName)
+	}
+
+	if entity == nil {
+		entity = new(identity.Entity)
+	}
+
+	entity.Name = user.UserName
+	if user.Active != nil {
+		entity.Disabled = !*user.Active
+	}
+
+	metadata := make(map[string]string, len(entity.Metadata))
+	for k, v := range entity.Metadata {
+		metadata[k] = v
+	}
+	for _, k := range scimUserMetadataKeys {
+		delete(metadata, k)
+	}
+	setMetadata := func(key, value string) {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+	setMetadata(scimMetaProvisioned, "true")
+	setMetadata(scimMetaExternalID, user.ExternalID)
+	setMetadata(scimMetaDisplayName, user.DisplayName)
+	if user.Name != nil {
+		setMetadata(scimMetaFormatted, user.Name.Formatted)
+		setMetadata(scimMetaGivenName, user.Name.GivenName)
+		setMetadata(scimMetaFamilyName, user.Name.FamilyName)
+	}
+	for idx, email := range user.Emails {
+		if email.Primary || idx == 0 {
+			setMetadata(scimMetaEmail, email.Value)
+		}
+	}
+	entity.Metadata = metadata
+
+	if err := i.sanitizeEntity(ctx, entity); err != nil {
+		return nil, newSCIMError(http.StatusBadRequest, "invalidValue", "%v", err)
+	}
+
+	if config.AliasMountAccessor != "" {
+		var alias *identity.Alias
+		for _, a := range entity.Aliases {
+			if a.MountAccessor == config.AliasMountAccessor {
+				alias = a
+			}
+		}
+
+		switch {
+		case alias == nil:
+			alias = &identity.Alias{
+				MountAccessor: config.AliasMountAccessor,
+				Name:          user.UserName,
+				CanonicalID:   entity.ID,
+			}
+			if err := i.sanitizeAlias(ctx, alias); err != nil {
+				return nil, err
+			}
+			entity.UpsertAlias(alias)
+		case alias.Name != user.UserName:
+			alias.Name = user.UserName
+			alias.LastUpdateTime = ptypes.TimestampNow()
+		}
+	}
+
+	if err := i.upsertEntity(ctx, entity, nil, true); err != nil {
+		return nil, err
+	}
+
+	return entity, nil
+}
+
+// scimGroup returns the internal group with the given ID, provided that it
+// is in the request namespace and was provisioned over SCIM
+func (i *IdentityStore) scimGroup(ctx context.Context, id string, clone bool) (*identity.Group, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := i.MemDBGroupByID(id, clone)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil || group.NamespaceID != ns.ID || group.Type != groupTypeInternal || !scimProvisioned(group.Metadata) {
+		return nil, scimNotFound(scimResourceTypeGroup, id)
+	}
+	return group, nil
+}
+
+// scimGroupFromGroup returns the SCIM representation of an internal group
+func (i *IdentityStore) scimGroupFromGroup(ctx context.Context, req *logical.Request, group *identity.Group) (*scimGroup, error) {
+	g := &scimGroup{
+		Schemas:     []string{scimSchemaGroup},
+		ID:          group.ID,
+		ExternalID:  group.Metadata[scimMetaExternalID],
+		DisplayName: group.Name,
+		Meta: &scimMeta{
+			ResourceType: scimResourceTypeGroup,
+			Created:      ptypes.TimestampString(group.CreationTime),
+			LastModified: ptypes.TimestampString(group.LastUpdateTime),
+			Location:     i.scimLocation(ctx, req, scimResourceTypeGroup, group.ID),
+		},
+	}
+
+	for _, entityID := range group.MemberEntityIDs {
+		entity, err := i.MemDBEntityByID(entityID, false)
+		if err != nil {
+			return nil, err
+		}
+		if entity == nil || !scimProvisioned(entity.Metadata) {
+			continue
+		}
+		g.Members = append(g.Members, scimMember{
+			Value:   entity.ID,
+			Ref:     i.scimLocation(ctx, req, scimResourceTypeUser, entity.ID),
+			Display: entity.Name,
+			Type:    scimResourceTypeUser,
+		})
+	}
+
+	memberGroups, err := i.MemDBGroupsByParentGroupID(group.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, memberGroup := range memberGroups {
+		if !scimProvisioned(memberGroup.Metadata) {
+			continue
+		}
+		g.Members = append(g.Members, scimMember{
+			Value:   memberGroup.ID,
+			Ref:     i.scimLocation(ctx, req, scimResourceTypeGroup, memberGroup.ID),
+			Display: memberGroup.Name,
+			Type:    scimResourceTypeGroup,
+		})
+	}
+
+	return g, nil
+}
+
+func (i *IdentityStore) pathSCIMGroupsList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := parseSCIMFilter(d.Get("filter").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []*identity.Group
+	attr, value, indexed := "", "", false
+	if f, ok := filter.(*scimAttrFilter); ok {
+		attr, value, indexed = f.indexedAttr()
+	}
+	switch {
+	case indexed && attr == "displayname":
+		group, err := i.MemDBGroupByName(ctx, value, false)
+		if err != nil {
+			return nil, err
+		}
+		if group != nil {
+			groups = append(groups, group)
+		}
+	case indexed && attr == "id":
+		group, err := i.MemDBGroupByID(value, false)
+		if err != nil {
+			return nil, err
+		}
+		if group != nil && group.NamespaceID == ns.ID {
+			groups = append(groups, group)
+		}
+	default:
+		iter, err := i.db.Txn(false).Get(groupsTable, "namespace_id", ns.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lookup groups using namespace ID: %w", err)
+		}
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			groups = append(groups, raw.(*identity.Group))
+		}
+	}
+
+	sort.Slice(groups, func(a, b int) bool {
+		return groups[a].ID < groups[b].ID
+	})
+
+	resources := make([]interface{}, 0, len(groups))
+	for _, group := range groups {
+		if group.Type != groupTypeInternal || !scimProvisioned(group.Metadata) {
+			continue
+		}
+		g, err := i.scimGroupFromGroup(ctx, req, group)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, g)
+	}
+
+	resources, err = scimFilterResources(filter, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	return scimList(d, resources)
+}
+
+func (i *IdentityStore) pathSCIMGroupCreate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	var g scimGroup
+	if err := scimDecode(req.Data, &g); err != nil {
+		return nil, err
+	}
+
+	i.groupLock.Lock()
+	defer i.groupLock.Unlock()
+
+	group, err := i.scimUpsertGroup(ctx, nil, &g)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.scimGroupResponse(ctx, req, http.StatusCreated, group)
+}
+
+func (i *IdentityStore) pathSCIMGroupRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	group, err := i.scimGroup(ctx, d.Get("id").(string), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.scimGroupResponse(ctx, req, http.StatusOK, group)
+}
+
+func (i *IdentityStore) pathSCIMGroupReplace(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	var g scimGroup
+	if err := scimDecode(req.Data, &g); err != nil {
+		return nil, err
+	}
+
+	i.groupLock.Lock()
+	defer i.groupLock.Unlock()
+
+	group, err := i.scimGroup(ctx, d.Get("id").(string), true)
+	if err != nil {
+		return nil, err
+	}
+
+	// A replace sets every attribute, so omitted members are removed
+	if g.Members == nil {
+		g.Members = []scimMember{}
+	}
+
+	group, err = i.scimUpsertGroup(ctx, group, &g)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.scimGroupResponse(ctx, req, http.StatusOK, group)
+}
+
+func (i *IdentityStore) pathSCIMGroupPatch(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ops, err := scimPatchOperations(req.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	i.groupLock.Lock()
+	defer i.groupLock.Unlock()
+
+	group, err := i.scimGroup(ctx, d.Get("id").(string), true)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := i.scimGroupFromGroup(ctx, req, group)
+	if err != nil {
+		return nil, err
+	}
+	// The patched member list is complete, so the memberships are replaced
+	// with it even if it ends up empty
+	if g.Members == nil {
+		g.Members = []scimMember{}
+	}
+	for _, op := range ops {
+		if err := applySCIMGroupPatch(g, op); err != nil {
+			return nil, err
+		}
+	}
+
+	group, err = i.scimUpsertGroup(ctx, group, g)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.scimGroupResponse(ctx, req, http.StatusOK, group)
+}
+
+func (i *IdentityStore) pathSCIMGroupDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	group, err := i.scimGroup(ctx, d.Get("id").(string), false)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := i.handleGroupDeleteCommon(ctx, group.ID, true)
+	if err != nil || resp != nil {
+		return resp, err
+	}
+
+	return scimResponse(http.StatusNoContent, nil)
+}
+
+func (i *IdentityStore) scimGroupResponse(ctx context.Context, req *logical.Request, status int, group *identity.Group) (*logical.Response, error) {
+	g, err := i.scimGroupFromGroup(ctx, req, group)
+	if err != nil {
+		return nil, err
+	}
+
+	return scimResponse(status, g)
+}
+
+// scimUpsertGroup creates or updates the internal group for a SCIM group. A
+// nil member list leaves the memberships of an existing group unchanged. The
+// caller must hold the group lock.
+func (i *IdentityStore) scimUpsertGroup(ctx context.Context, group *identity.Group, g *scimGroup) (*identity.Group, error) {
+	if g.DisplayName == "" {
+		return nil, newSCIMError(http.StatusBadRequest, "invalidValue", "displayName is required")
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groupByName, err := i.MemDBGroupByName(ctx, g.DisplayName, false)
+	if err != nil {
+		return nil, err
+	}
+	if groupByName != nil && (group == nil || groupByName.ID != group.ID) {
+		return nil, newSCIMError(http.StatusConflict, "uniqueness", "displayName %q is already in use", g.DisplayName)
+	}
+
+	if group == nil {
+		group = &identity.Group{
+			Type: groupTypeInternal,
+		}
+	}
+	group.Name = g.DisplayName
+
+	metadata := make(map[string]string, len(group.Metadata))
+	for k, v := range group.Metadata {
+		metadata[k] = v
+	}
+	delete(metadata, scimMetaExternalID)
+	if g.ExternalID != "" {
+		metadata[scimMetaExternalID] = g.ExternalID
+	}
+	metadata[scimMetaProvisioned] = "true"
+	group.Metadata = metadata
+
+	var memberGroupIDs []string
+	if g.Members != nil {
+		memberEntityIDs := []string{}
+		memberGroupIDs = []string{}
+
+		// Members that were not provisioned over SCIM are not exposed to the
+		// client, so they are kept as they are
+		for _, entityID := range group.MemberEntityIDs {
+			entity, err := i.MemDBEntityByID(entityID, false)
+			if err != nil {
+				return nil, err
+			}
+			if entity != nil && !scimProvisioned(entity.Metadata) {
+				memberEntityIDs = append(memberEntityIDs, entity.ID)
+			}
+		}
+		if group.ID != "" {
+			memberGroups, err := i.MemDBGroupsByParentGroupID(group.ID, false)
+			if err != nil {
+				return nil, err
+			}
+			for _, memberGroup := range memberGroups {
+				if !scimProvisioned(memberGroup.Metadata) {
+					memberGroupIDs = append(memberGroupIDs, memberGroup.ID)
+				}
+			}
+		}
+
+		for _, member := range g.Members {
+			memberType := strings.ToLower(member.Type)
+
+			if memberType == "" || memberType == "user" {
+				entity, err := i.MemDBEntityByID(member.Value, false)
+				if err != nil {
+					return nil, err
+				}
+				if entity != nil && entity.NamespaceID == ns.ID && scimProvisioned(entity.Metadata) {
+					memberEntityIDs = append(memberEntityIDs, entity.ID)
+					continue
+				}
+			}
+
+			if memberType == "" || memberType == "group" {
+				memberGroup, err := i.MemDBGroupByID(member.Value, false)
+				if err != nil {
+					return nil, err
+				}
+				if memberGroup != nil && memberGroup.NamespaceID == ns.ID && memberGroup.Type == groupTypeInternal && scimProvisioned(memberGroup.Metadata) {
+					memberGroupIDs = append(memberGroupIDs, memberGroup.ID)
+					continue
+				}
+			}
+
+			return nil, newSCIMError(http.StatusBadRequest, "invalidValue", "member %q not found", member.Value)
+		}
+		group.MemberEntityIDs = memberEntityIDs
+	}
+
+	if err := i.sanitizeAndUpsertGroup(ctx, group, nil, memberGroupIDs); err != nil {
+		if errStr := err.Error(); strings.HasPrefix(errStr, errCycleDetectedPrefix) || strings.HasPrefix(errStr, "invalid group metadata") {
+			return nil, newSCIMError(http.StatusBadRequest, "invalidValue", "%s", errStr)
+		}
+		return nil, err
+	}
+
+	return group, nil
+}
+
+var scimHelp = map[string][2]string{
+	"scim-config": {
+		"Configure SCIM provisioning",
+		`When an auth mount accessor is configured, every user provisioned over
+SCIM gets an alias on that mount named after its userName, so that the user
+logs in to the provisioned entity.`,
+	},
+	"scim-service-provider-config": {
+		"Read the SCIM service provider configuration",
+		"",
+	},
+	"scim-users": {
+		"List and filter SCIM users, or provision a new user",
+		"SCIM users are stored as entities.",
+	},
+	"scim-user": {
+		"Read, replace, patch or delete a SCIM user",
+		"SCIM users are stored as entities.",
+	},
+	"scim-groups": {
+		"List and filter SCIM groups, or provision a new group",
+		"SCIM groups are stored as internal groups.",
+	},
+	"scim-group": {
+		"Read, replace, patch or delete a SCIM group",
+		"SCIM groups are stored as internal groups.",
+	},
+}
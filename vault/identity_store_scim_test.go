@@ -0,0 +1,436 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+func scimTestRequest(t *testing.T, c *Core, storage logical.Storage, op logical.Operation, path string, data map[string]interface{}) (int, map[string]interface{}) {
+	t.Helper()
+
+	resp, err := c.identityStore.HandleRequest(namespace.RootContext(nil), &logical.Request{
+		Path:      path,
+		Operation: op,
+		Storage:   storage,
+		Data:      data,
+	})
+	if err != nil {
+		t.Fatalf("%s %s: %v", op, path, err)
+	}
+	if resp == nil {
+		t.Fatalf("%s %s: no response", op, path)
+	}
+
+	status := resp.Data[logical.HTTPStatusCode].(int)
+	var body map[string]interface{}
+	if raw, ok := resp.Data[logical.HTTPRawBody]; ok {
+		if err := json.Unmarshal(raw.([]byte), &body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return status, body
+}
+
+func TestIdentityStore_SCIMUsers(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	storage := &logical.InmemStorage{}
+
+	resp, err := c.systemBackend.HandleRequest(ctx, &logical.Request{
+		Path:      "auth",
+		Operation: logical.ReadOperation,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+	tokenMountAccessor := resp.Data["token/"].(map[string]interface{})["accessor"].(string)
+
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "scim/config",
+		Operation: logical.UpdateOperation,
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"alias_mount_accessor": tokenMountAccessor,
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+
+	status, body := scimTestRequest(t, c, storage, logical.UpdateOperation, "scim/v2/Users", map[string]interface{}{
+		"schemas":    []interface{}{scimSchemaUser},
+		"userName":   "alice@example.com",
+		"externalId": "00u1",
+		"name": map[string]interface{}{
+			"givenName":  "Alice",
+			"familyName": "Smith",
+		},
+		"emails": []interface{}{
+			map[string]interface{}{"value": "alice@example.com", "primary": true},
+		},
+		"active": true,
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %#v", status, body)
+	}
+	userID := body["id"].(string)
+
+	entity, err := c.identityStore.MemDBEntityByID(userID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entity.Name != "alice@example.com" || entity.Metadata[scimMetaGivenName] != "Alice" || entity.Metadata[scimMetaExternalID] != "00u1" {
+		t.Fatalf("unexpected entity: %#v", entity)
+	}
+	if len(entity.Aliases) != 1 || entity.Aliases[0].Name != "alice@example.com" || entity.Aliases[0].MountAccessor != tokenMountAccessor {
+		t.Fatalf("expected an alias on the configured mount: %#v", entity.Aliases)
+	}
+
+	// The userName must be unique
+	status, body = scimTestRequest(t, c, storage, logical.UpdateOperation, "scim/v2/Users", map[string]interface{}{
+		"userName": "alice@example.com",
+	})
+	if status != http.StatusConflict || body["scimType"] != "uniqueness" {
+		t.Fatalf("expected a uniqueness conflict, got %d: %#v", status, body)
+	}
+
+	status, body = scimTestRequest(t, c, storage, logical.ReadOperation, "scim/v2/Users", map[string]interface{}{
+		"filter": `userName eq "ALICE@example.com"`,
+	})
+	if status != http.StatusOK || body["totalResults"].(float64) != 1 {
+		t.Fatalf("expected one result, got %d: %#v", status, body)
+	}
+
+	status, body = scimTestRequest(t, c, storage, logical.ReadOperation, "scim/v2/Users", map[string]interface{}{
+		"filter": `name.familyName sw "Jo" or externalId eq "00u1"`,
+	})
+	if status != http.StatusOK || body["totalResults"].(float64) != 1 {
+		t.Fatalf("expected one result, got %d: %#v", status, body)
+	}
+
+	status, body = scimTestRequest(t, c, storage, logical.ReadOperation, "scim/v2/Users", map[string]interface{}{
+		"filter": `userName xx "alice"`,
+	})
+	if status != http.StatusBadRequest || body["scimType"] != "invalidFilter" {
+		t.Fatalf("expected an invalid filter error, got %d: %#v", status, body)
+	}
+
+	// Deactivate the user the way Azure AD does, with the boolean as a string
+	status, body = scimTestRequest(t, c, storage, logical.PatchOperation, "scim/v2/Users/"+userID, map[string]interface{}{
+		"schemas": []interface{}{scimSchemaPatchOp},
+		"Operations": []interface{}{
+			map[string]interface{}{"op": "Replace", "path": "active", "value": "False"},
+			map[string]interface{}{"op": "replace", "value": map[string]interface{}{"displayName": "Alice Smith"}},
+		},
+	})
+	if status != http.StatusOK || body["active"] != false || body["displayName"] != "Alice Smith" {
+		t.Fatalf("unexpected patch response %d: %#v", status, body)
+	}
+	entity, err = c.identityStore.MemDBEntityByID(userID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !entity.Disabled {
+		t.Fatal("expected entity to be disabled")
+	}
+
+	// Renaming the user renames its alias
+	status, body = scimTestRequest(t, c, storage, logical.UpdateOperation, "scim/v2/Users/"+userID, map[string]interface{}{
+		"userName": "alice.smith@example.com",
+		"active":   true,
+	})
+	if status != http.StatusOK || body["userName"] != "alice.smith@example.com" {
+		t.Fatalf("unexpected replace response %d: %#v", status, body)
+	}
+	if _, ok := body["name"]; ok {
+		t.Fatalf("expected replace to clear the name: %#v", body)
+	}
+	alias, err := c.identityStore.MemDBAliasByFactors(tokenMountAccessor, "alice.smith@example.com", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alias == nil || alias.CanonicalID != userID {
+		t.Fatalf("expected alias to be renamed: %#v", alias)
+	}
+
+	status, _ = scimTestRequest(t, c, storage, logical.DeleteOperation, "scim/v2/Users/"+userID, nil)
+	if status != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", status)
+	}
+	status, body = scimTestRequest(t, c, storage, logical.ReadOperation, "scim/v2/Users/"+userID, nil)
+	if status != http.StatusNotFound || body["status"] != "404" {
+		t.Fatalf("expected 404, got %d: %#v", status, body)
+	}
+}
+
+func TestIdentityStore_SCIMUsersExistingAlias(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	storage := &logical.InmemStorage{}
+
+	resp, err := c.systemBackend.HandleRequest(ctx, &logical.Request{
+		Path:      "auth",
+		Operation: logical.ReadOperation,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+	tokenMountAccessor := resp.Data["token/"].(map[string]interface{})["accessor"].(string)
+
+	// An entity created by an earlier login
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "entity-alias",
+		Operation: logical.UpdateOperation,
+		Data: map[string]interface{}{
+			"mount_accessor": tokenMountAccessor,
+			"name":           "bob",
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+	entityID := resp.Data["canonical_id"].(string)
+
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "scim/config",
+		Operation: logical.UpdateOperation,
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"alias_mount_accessor": tokenMountAccessor,
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+
+	status, body := scimTestRequest(t, c, storage, logical.UpdateOperation, "scim/v2/Users", map[string]interface{}{
+		"userName": "bob",
+	})
+	if status != http.StatusConflict || body["scimType"] != "uniqueness" {
+		t.Fatalf("expected a uniqueness conflict, got %d: %#v", status, body)
+	}
+
+	// Entities that were not provisioned over SCIM are not exposed
+	status, body = scimTestRequest(t, c, storage, logical.ReadOperation, "scim/v2/Users/"+entityID, nil)
+	if status != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %#v", status, body)
+	}
+	status, body = scimTestRequest(t, c, storage, logical.DeleteOperation, "scim/v2/Users/"+entityID, nil)
+	if status != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %#v", status, body)
+	}
+	status, body = scimTestRequest(t, c, storage, logical.ReadOperation, "scim/v2/Users", nil)
+	if status != http.StatusOK || body["totalResults"].(float64) != 0 {
+		t.Fatalf("expected no results, got %d: %#v", status, body)
+	}
+
+	entity, err := c.identityStore.MemDBEntityByID(entityID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entity == nil {
+		t.Fatal("expected the entity to be left in place")
+	}
+}
+
+func TestIdentityStore_SCIMGroups(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	storage := &logical.InmemStorage{}
+
+	var userIDs []string
+	for _, name := range []string{"alice", "bob"} {
+		status, body := scimTestRequest(t, c, storage, logical.UpdateOperation, "scim/v2/Users", map[string]interface{}{
+			"userName": name,
+		})
+		if status != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %#v", status, body)
+		}
+		userIDs = append(userIDs, body["id"].(string))
+	}
+
+	status, body := scimTestRequest(t, c, storage, logical.UpdateOperation, "scim/v2/Groups", map[string]interface{}{
+		"schemas":     []interface{}{scimSchemaGroup},
+		"displayName": "engineering",
+		"members": []interface{}{
+			map[string]interface{}{"value": userIDs[0]},
+		},
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %#v", status, body)
+	}
+	groupID := body["id"].(string)
+
+	status, body = scimTestRequest(t, c, storage, logical.UpdateOperation, "scim/v2/Groups", map[string]interface{}{
+		"displayName": "platform",
+		"members": []interface{}{
+			map[string]interface{}{"value": groupID, "type": "Group"},
+		},
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %#v", status, body)
+	}
+	parentID := body["id"].(string)
+
+	status, body = scimTestRequest(t, c, storage, logical.PatchOperation, "scim/v2/Groups/"+groupID, map[string]interface{}{
+		"schemas": []interface{}{scimSchemaPatchOp},
+		"Operations": []interface{}{
+			map[string]interface{}{
+				"op":    "add",
+				"path":  "members",
+				"value": []interface{}{map[string]interface{}{"value": userIDs[1]}},
+			},
+			map[string]interface{}{
+				"op":   "remove",
+				"path": fmt.Sprintf("members[value eq %q]", userIDs[0]),
+			},
+		},
+	})
+	if status != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %#v", status, body)
+	}
+	group, err := c.identityStore.MemDBGroupByID(groupID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(group.MemberEntityIDs) != 1 || group.MemberEntityIDs[0] != userIDs[1] {
+		t.Fatalf("unexpected members: %#v", group.MemberEntityIDs)
+	}
+	if len(group.ParentGroupIDs) != 1 || group.ParentGroupIDs[0] != parentID {
+		t.Fatalf("expected the group to stay a member of its parent: %#v", group.ParentGroupIDs)
+	}
+
+	status, body = scimTestRequest(t, c, storage, logical.ReadOperation, "scim/v2/Users/"+userIDs[1], nil)
+	if status != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %#v", status, body)
+	}
+	groups := body["groups"].([]interface{})
+	if len(groups) != 1 || groups[0].(map[string]interface{})["value"] != groupID {
+		t.Fatalf("unexpected groups: %#v", groups)
+	}
+
+	status, body = scimTestRequest(t, c, storage, logical.ReadOperation, "scim/v2/Groups", map[string]interface{}{
+		"filter": fmt.Sprintf("members[value eq %q]", groupID),
+	})
+	if status != http.StatusOK || body["totalResults"].(float64) != 1 {
+		t.Fatalf("expected one result, got %d: %#v", status, body)
+	}
+	if body["Resources"].([]interface{})[0].(map[string]interface{})["id"] != parentID {
+		t.Fatalf("expected the parent group, got %#v", body)
+	}
+
+	// Replacing the group without members removes them
+	status, body = scimTestRequest(t, c, storage, logical.UpdateOperation, "scim/v2/Groups/"+groupID, map[string]interface{}{
+		"displayName": "engineering",
+	})
+	if status != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %#v", status, body)
+	}
+	group, err = c.identityStore.MemDBGroupByID(groupID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(group.MemberEntityIDs) != 0 {
+		t.Fatalf("expected members to be removed: %#v", group.MemberEntityIDs)
+	}
+
+	// A member that does not exist
+	status, body = scimTestRequest(t, c, storage, logical.PatchOperation, "scim/v2/Groups/"+groupID, map[string]interface{}{
+		"Operations": []interface{}{
+			map[string]interface{}{"op": "add", "path": "members", "value": []interface{}{map[string]interface{}{"value": "missing"}}},
+		},
+	})
+	if status != http.StatusBadRequest || body["scimType"] != "invalidValue" {
+		t.Fatalf("expected an invalid value error, got %d: %#v", status, body)
+	}
+
+	status, _ = scimTestRequest(t, c, storage, logical.DeleteOperation, "scim/v2/Groups/"+groupID, nil)
+	if status != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", status)
+	}
+	group, err = c.identityStore.MemDBGroupByID(groupID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group != nil {
+		t.Fatal("expected group to be deleted")
+	}
+}
+
+func TestSCIMFilter(t *testing.T) {
+	t.Parallel()
+
+	resource := map[string]interface{}{
+		"userName": "Alice@example.com",
+		"active":   true,
+		"name": map[string]interface{}{
+			"givenName": "Alice",
+		},
+		"emails": []interface{}{
+			map[string]interface{}{"value": "alice@work.example.com", "type": "work"},
+			map[string]interface{}{"value": "alice@home.example.com", "type": "home"},
+		},
+		"meta": map[string]interface{}{
+			"lastModified": "2022-11-01T10:00:00Z",
+		},
+	}
+
+	cases := []struct {
+		filter  string
+		matches bool
+	}{
+		{`userName eq "alice@example.com"`, true},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "alice@example.com"`, true},
+		{`userName ne "alice@example.com"`, false},
+		{`userName co "example"`, true},
+		{`userName sw "bob"`, false},
+		{`userName ew ".com"`, true},
+		{`active eq true`, true},
+		{`name.givenName pr`, true},
+		{`name.familyName pr`, false},
+		{`emails eq "alice@home.example.com"`, true},
+		{`emails.type eq "home"`, true},
+		{`emails[type eq "work" and value co "work"]`, true},
+		{`emails[type eq "other"]`, false},
+		{`meta.lastModified gt "2022-10-01T00:00:00Z"`, true},
+		{`userName sw "bob" or (active eq true and not (name.givenName eq "Bob"))`, true},
+		{`title eq null`, true},
+	}
+
+	for _, tc := range cases {
+		f, err := parseSCIMFilter(tc.filter)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.filter, err)
+		}
+		if matches := f.matches(resource); matches != tc.matches {
+			t.Fatalf("%s: expected %t, got %t", tc.filter, tc.matches, matches)
+		}
+	}
+
+	for _, filter := range []string{
+		`userName eq`,
+		`userName eq "alice`,
+		`(userName eq "alice"`,
+		`userName foo "alice"`,
+		`active gt true`,
+		`userName eq "alice" extra`,
+		strings.Repeat("(", scimFilterMaxDepth) + `userName eq "alice"` + strings.Repeat(")", scimFilterMaxDepth),
+		`userName eq "` + strings.Repeat("a", scimFilterMaxLength) + `"`,
+	} {
+		_, err := parseSCIMFilter(filter)
+		if scimErr, ok := err.(*scimError); !ok || scimErr.ScimType != "invalidFilter" {
+			t.Fatalf("%s: expected an invalid filter error, got %v", filter, err)
+		}
+	}
+
+	// Nesting up to the maximum depth is accepted
+	filter := strings.Repeat("(", scimFilterMaxDepth-1) + `userName eq "alice@example.com"` + strings.Repeat(")", scimFilterMaxDepth-1)
+	if _, err := parseSCIMFilter(filter); err != nil {
+		t.Fatalf("%s: %v", filter, err)
+	}
+}
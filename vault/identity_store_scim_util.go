@@ -0,0 +1,928 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	scimSchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimSchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimSchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	scimSchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimSchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	scimSchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// scimUser is the SCIM representation of an entity
+type scimUser struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	UserName    string       `json:"userName"`
+	Name        *scimName    `json:"name,omitempty"`
+	DisplayName string       `json:"displayName,omitempty"`
+	Emails      []scimEmail  `json:"emails,omitempty"`
+	Active      *bool        `json:"active,omitempty"`
+	Groups      []scimMember `json:"groups,omitempty"`
+	Meta        *scimMeta    `json:"meta,omitempty"`
+}
+
+type scimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+}
+
+type scimEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// scimGroup is the SCIM representation of an internal group
+type scimGroup struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []scimMember `json:"members,omitempty"`
+	Meta        *scimMeta    `json:"meta,omitempty"`
+}
+
+// scimMember references a user or group, either as a member of a group or as
+// a group a user belongs to
+type scimMember struct {
+	Value   string `json:"value"`
+	Ref     string `json:"$ref,omitempty"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+type scimMeta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+type scimListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+type scimPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []scimPatchOperation `json:"Operations"`
+}
+
+type scimPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// scimError is both the SCIM error response body and an error that the SCIM
+// handlers return to have it sent to the client
+type scimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+
+	code int
+}
+
+func (e *scimError) Error() string {
+	return e.Detail
+}
+
+func newSCIMError(code int, scimType string, format string, args ...interface{}) *scimError {
+	return &scimError{
+		Schemas:  []string{scimSchemaError},
+		Status:   strconv.Itoa(code),
+		ScimType: scimType,
+		Detail:   fmt.Sprintf(format, args...),
+		code:     code,
+	}
+}
+
+func scimNotFound(resourceType, id string) *scimError {
+	return newSCIMError(http.StatusNotFound, "", "%s %q not found", resourceType, id)
+}
+
+// scimDecode decodes request data into one of the SCIM types. Attribute
+// names in SCIM are case insensitive, as are field names when decoding JSON.
+func scimDecode(data map[string]interface{}, out interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return newSCIMError(http.StatusBadRequest, "invalidSyntax", "failed to parse request: %v", err)
+	}
+	return nil
+}
+
+// scimDecodeValue decodes the value of a PATCH operation into out
+func scimDecodeValue(value interface{}, out interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return newSCIMError(http.StatusBadRequest, "invalidValue", "invalid value: %v", err)
+	}
+	return nil
+}
+
+// scimResourceMap returns the generic JSON form of a SCIM resource that
+// filters are evaluated against
+func scimResourceMap(resource interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// scimFilter is a parsed SCIM filter expression, see RFC 7644 section 3.4.2.2
+type scimFilter interface {
+	matches(resource map[string]interface{}) bool
+}
+
+type scimLogicalFilter struct {
+	and         bool
+	left, right scimFilter
+}
+
+func (f *scimLogicalFilter) matches(resource map[string]interface{}) bool {
+	if f.and {
+		return f.left.matches(resource) && f.right.matches(resource)
+	}
+	return f.left.matches(resource) || f.right.matches(resource)
+}
+
+type scimNotFilter struct {
+	filter scimFilter
+}
+
+func (f *scimNotFilter) matches(resource map[string]interface{}) bool {
+	return !f.filter.matches(resource)
+}
+
+// scimValuePathFilter matches if any value of a multi-valued attribute
+// matches the filter, e.g. emails[type eq "work"]
+type scimValuePathFilter struct {
+	path   []string
+	filter scimFilter
+}
+
+func (f *scimValuePathFilter) matches(resource map[string]interface{}) bool {
+	for _, v := range scimAttributeValues(resource, f.path) {
+		if m, ok := v.(map[string]interface{}); ok && f.filter.matches(m) {
+			return true
+		}
+	}
+	return false
+}
+
+type scimAttrFilter struct {
+	path  []string
+	op    string
+	value interface{}
+}
+
+func (f *scimAttrFilter) matches(resource map[string]interface{}) bool {
+	values := scimAttributeValues(resource, f.path)
+
+	switch f.op {
+	case "pr":
+		for _, v := range values {
+			if s, ok := v.(string); !ok || s != "" {
+				return true
+			}
+		}
+		return false
+	case "ne":
+		return !(&scimAttrFilter{path: f.path, op: "eq", value: f.value}).matches(resource)
+	}
+
+	if f.value == nil {
+		// Comparing against null is the same as checking that the attribute
+		// is absent
+		return f.op == "eq" && len(values) == 0
+	}
+
+	for _, v := range values {
+		// Complex multi-valued attributes are compared by their value
+		if m, ok := v.(map[string]interface{}); ok {
+			v = m["value"]
+		}
+		if scimCompare(f.op, v, f.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexedAttr returns the attribute name and value that can be looked up
+// directly instead of evaluating the filter against every resource
+func (f *scimAttrFilter) indexedAttr() (string, string, bool) {
+	if f.op != "eq" || len(f.path) != 1 {
+		return "", "", false
+	}
+	value, ok := f.value.(string)
+	if !ok {
+		return "", "", false
+	}
+	return strings.ToLower(f.path[0]), value, true
+}
+
+func scimCompare(op string, actual, expected interface{}) bool {
+	switch expected := expected.(type) {
+	case string:
+		a, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		a, e := strings.ToLower(a), strings.ToLower(expected)
+		switch op {
+		case "eq":
+			return a == e
+		case "co":
+			return strings.Contains(a, e)
+		case "sw":
+			return strings.HasPrefix(a, e)
+		case "ew":
+			return strings.HasSuffix(a, e)
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+	case bool:
+		a, ok := actual.(bool)
+		return ok && op == "eq" && a == expected
+	case float64:
+		a, ok := actual.(float64)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "eq":
+			return a == expected
+		case "gt":
+			return a > expected
+		case "ge":
+			return a >= expected
+		case "lt":
+			return a < expected
+		case "le":
+			return a <= expected
+		}
+	}
+	return false
+}
+
+// scimAttributeValues returns all values found at the given attribute path,
+// flattening multi-valued attributes along the way. Attribute names are
+// matched case insensitively.
+func scimAttributeValues(resource map[string]interface{}, path []string) []interface{} {
+	values := []interface{}{resource}
+	for _, name := range path {
+		var next []interface{}
+		for _, v := range values {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for key, child := range m {
+				if !strings.EqualFold(key, name) || child == nil {
+					continue
+				}
+				if list, ok := child.([]interface{}); ok {
+					next = append(next, list...)
+				} else {
+					next = append(next, child)
+				}
+			}
+		}
+		values = next
+	}
+	return values
+}
+
+// scimAttributePath splits an attribute path into its components, dropping
+// any schema URN prefix
+func scimAttributePath(attr string) []string {
+	if strings.HasPrefix(strings.ToLower(attr), "urn:") {
+		for _, schema := range []string{scimSchemaUser, scimSchemaGroup} {
+			if len(attr) > len(schema) && strings.EqualFold(attr[:len(schema)+1], schema+":") {
+				attr = attr[len(schema)+1:]
+				break
+			}
+		}
+	}
+	return strings.Split(attr, ".")
+}
+
+var scimCompareOps = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true,
+}
+
+const (
+	// scimFilterMaxLength is the maximum length of a filter expression
+	scimFilterMaxLength = 4096
+
+	// scimFilterMaxDepth is the maximum nesting of grouped, negated and value
+	// path expressions within a filter
+	scimFilterMaxDepth = 16
+)
+
+type scimFilterParser struct {
+	tokens []string
+	pos    int
+	depth  int
+}
+
+// parseSCIMFilter parses a SCIM filter expression. An empty filter parses to
+// a nil filter.
+func parseSCIMFilter(filter string) (scimFilter, error) {
+	if strings.TrimSpace(filter) == "" {
+		return nil, nil
+	}
+	if len(filter) > scimFilterMaxLength {
+		return nil, scimInvalidFilter("filter exceeds the maximum length of %d", scimFilterMaxLength)
+	}
+
+	tokens, err := scimFilterTokens(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &scimFilterParser{tokens: tokens}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, scimInvalidFilter("unexpected %q", p.tokens[p.pos])
+	}
+	return f, nil
+}
+
+func scimInvalidFilter(format string, args ...interface{}) *scimError {
+	return newSCIMError(http.StatusBadRequest, "invalidFilter", "invalid filter: "+format, args...)
+}
+
+// scimFilterTokens splits a filter into brackets, quoted strings and words.
+// Quoted strings keep their quotes so that they can be told apart from
+// keywords.
+func scimFilterTokens(filter string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(filter); {
+		c := filter[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')' || c == '[' || c == ']':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"':
+			j := i + 1
+			for ; j < len(filter) && filter[j] != '"'; j++ {
+				if filter[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(filter) {
+				return nil, scimInvalidFilter("unterminated string")
+			}
+			tokens = append(tokens, filter[i:j+1])
+			i = j + 1
+		default:
+			j := i
+			for ; j < len(filter) && !strings.ContainsRune(" \t()[]\"", rune(filter[j])); j++ {
+			}
+			tokens = append(tokens, filter[i:j])
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+func (p *scimFilterParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *scimFilterParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *scimFilterParser) expect(token string) error {
+	if t := p.next(); t != token {
+		return scimInvalidFilter("expected %q, got %q", token, t)
+	}
+	return nil
+}
+
+func (p *scimFilterParser) parseOr() (scimFilter, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > scimFilterMaxDepth {
+		return nil, scimInvalidFilter("filter exceeds the maximum nesting depth of %d", scimFilterMaxDepth)
+	}
+
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &scimLogicalFilter{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *scimFilterParser) parseAnd() (scimFilter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &scimLogicalFilter{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *scimFilterParser) parseUnary() (scimFilter, error) {
+	switch t := p.peek(); {
+	case t == "(":
+		p.next()
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return f, p.expect(")")
+	case strings.EqualFold(t, "not"):
+		p.next()
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return &scimNotFilter{filter: f}, p.expect(")")
+	}
+	return p.parseAttrExpr()
+}
+
+func (p *scimFilterParser) parseAttrExpr() (scimFilter, error) {
+	attr := p.next()
+	if attr == "" || strings.ContainsAny(attr[:1], "()[]\"") {
+		return nil, scimInvalidFilter("expected attribute name, got %q", attr)
+	}
+	path := scimAttributePath(attr)
+
+	if p.peek() == "[" {
+		p.next()
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return &scimValuePathFilter{path: path, filter: f}, nil
+	}
+
+	op := strings.ToLower(p.next())
+	if op == "pr" {
+		return &scimAttrFilter{path: path, op: op}, nil
+	}
+	if !scimCompareOps[op] {
+		return nil, scimInvalidFilter("unsupported operator %q", op)
+	}
+
+	value, err := parseSCIMFilterValue(p.next())
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := value.(bool); ok && op != "eq" && op != "ne" {
+		return nil, scimInvalidFilter("operator %q cannot be used with a boolean", op)
+	}
+	return &scimAttrFilter{path: path, op: op, value: value}, nil
+}
+
+func parseSCIMFilterValue(token string) (interface{}, error) {
+	switch {
+	case token == "":
+		return nil, scimInvalidFilter("missing comparison value")
+	case strings.HasPrefix(token, `"`):
+		var s string
+		if err := json.Unmarshal([]byte(token), &s); err != nil {
+			return nil, scimInvalidFilter("invalid string %s", token)
+		}
+		return s, nil
+	case strings.EqualFold(token, "true"):
+		return true, nil
+	case strings.EqualFold(token, "false"):
+		return false, nil
+	case strings.EqualFold(token, "null"):
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return nil, scimInvalidFilter("invalid comparison value %q", token)
+	}
+	return f, nil
+}
+
+// scimPatchPath is a parsed PATCH operation path such as
+// members[value eq "id"] or name.givenName
+type scimPatchPath struct {
+	attr   string
+	filter scimFilter
+	sub    string
+}
+
+func parseSCIMPatchPath(path string) (*scimPatchPath, error) {
+	p := &scimPatchPath{}
+
+	if open := strings.Index(path, "["); open >= 0 {
+		end := strings.LastIndex(path, "]")
+		if end < open {
+			return nil, newSCIMError(http.StatusBadRequest, "invalidPath", "invalid path %q", path)
+		}
+		filter, err := parseSCIMFilter(path[open+1 : end])
+		if err != nil {
+			return nil, err
+		}
+		p.filter = filter
+		p.sub = strings.TrimPrefix(path[end+1:], ".")
+		path = path[:open]
+	}
+
+	parts := scimAttributePath(path)
+	p.attr = strings.ToLower(parts[0])
+	if len(parts) > 1 {
+		if p.sub != "" {
+			return nil, newSCIMError(http.StatusBadRequest, "invalidPath", "invalid path %q", path)
+		}
+		p.sub = strings.Join(parts[1:], ".")
+	}
+	p.sub = strings.ToLower(p.sub)
+
+	if p.attr == "" || strings.IndexFunc(p.attr, unicode.IsSpace) >= 0 {
+		return nil, newSCIMError(http.StatusBadRequest, "invalidPath", "invalid path %q", path)
+	}
+	return p, nil
+}
+
+// scimPatchOperations validates a PatchOp request and returns its
+// operations, each with a lower-cased op
+func scimPatchOperations(data map[string]interface{}) ([]scimPatchOperation, error) {
+	var patch scimPatchRequest
+	if err := scimDecode(data, &patch); err != nil {
+		return nil, err
+	}
+	if len(patch.Operations) == 0 {
+		return nil, newSCIMError(http.StatusBadRequest, "invalidSyntax", "no operations given")
+	}
+	for idx := range patch.Operations {
+		op := strings.ToLower(patch.Operations[idx].Op)
+		switch op {
+		case "add", "replace", "remove":
+		default:
+			return nil, newSCIMError(http.StatusBadRequest, "invalidSyntax", "unsupported operation %q", patch.Operations[idx].Op)
+		}
+		if op != "remove" && patch.Operations[idx].Value == nil {
+			return nil, newSCIMError(http.StatusBadRequest, "invalidValue", "operation %q requires a value", op)
+		}
+		if op == "remove" && patch.Operations[idx].Path == "" {
+			return nil, newSCIMError(http.StatusBadRequest, "noTarget", "remove operations require a path")
+		}
+		patch.Operations[idx].Op = op
+	}
+	return patch.Operations, nil
+}
+
+// scimAttributeMapOperations turns an operation without a path, whose value
+// holds the attributes to modify, into one operation per attribute
+func scimAttributeMapOperations(op scimPatchOperation) ([]scimPatchOperation, error) {
+	attrs, ok := op.Value.(map[string]interface{})
+	if !ok {
+		return nil, newSCIMError(http.StatusBadRequest, "invalidValue", "operations without a path require an object value")
+	}
+
+	var ops []scimPatchOperation
+	for attr, value := range attrs {
+		switch strings.ToLower(attr) {
+		case "id", "schemas", "meta":
+			continue
+		}
+		ops = append(ops, scimPatchOperation{Op: op.Op, Path: attr, Value: value})
+	}
+	return ops, nil
+}
+
+func scimStringValue(attr string, value interface{}) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", newSCIMError(http.StatusBadRequest, "invalidValue", "%s must be a string", attr)
+	}
+	return s, nil
+}
+
+// scimBoolValue accepts booleans as well as their string forms, which some
+// identity providers send
+func scimBoolValue(attr string, value interface{}) (bool, error) {
+	switch value := value.(type) {
+	case bool:
+		return value, nil
+	case string:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b, nil
+		}
+	}
+	return false, newSCIMError(http.StatusBadRequest, "invalidValue", "%s must be a boolean", attr)
+}
+
+// applySCIMUserPatch applies a single PATCH operation to a user
+func applySCIMUserPatch(user *scimUser, op scimPatchOperation) error {
+	if op.Path == "" {
+		ops, err := scimAttributeMapOperations(op)
+		if err != nil {
+			return err
+		}
+		for _, op := range ops {
+			if err := applySCIMUserPatch(user, op); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	path, err := parseSCIMPatchPath(op.Path)
+	if err != nil {
+		return err
+	}
+	remove := op.Op == "remove"
+
+	switch path.attr {
+	case "username":
+		if remove {
+			return newSCIMError(http.StatusBadRequest, "mutability", "userName cannot be removed")
+		}
+		user.UserName, err = scimStringValue("userName", op.Value)
+		return err
+
+	case "displayname", "externalid":
+		value := ""
+		if !remove {
+			if value, err = scimStringValue(op.Path, op.Value); err != nil {
+				return err
+			}
+		}
+		if path.attr == "displayname" {
+			user.DisplayName = value
+		} else {
+			user.ExternalID = value
+		}
+		return nil
+
+	case "active":
+		if remove {
+			return newSCIMError(http.StatusBadRequest, "mutability", "active cannot be removed")
+		}
+		active, err := scimBoolValue("active", op.Value)
+		if err != nil {
+			return err
+		}
+		user.Active = &active
+		return nil
+
+	case "name":
+		if user.Name == nil {
+			user.Name = &scimName{}
+		}
+		if path.sub == "" {
+			if remove {
+				user.Name = nil
+				return nil
+			}
+			var name scimName
+			if err := scimDecodeValue(op.Value, &name); err != nil {
+				return err
+			}
+			if op.Op == "replace" {
+				*user.Name = name
+				return nil
+			}
+			for _, pair := range [][2]*string{
+				{&user.Name.Formatted, &name.Formatted},
+				{&user.Name.FamilyName, &name.FamilyName},
+				{&user.Name.GivenName, &name.GivenName},
+			} {
+				if *pair[1] != "" {
+					*pair[0] = *pair[1]
+				}
+			}
+			return nil
+		}
+
+		value := ""
+		if !remove {
+			if value, err = scimStringValue(op.Path, op.Value); err != nil {
+				return err
+			}
+		}
+		switch path.sub {
+		case "formatted":
+			user.Name.Formatted = value
+		case "familyname":
+			user.Name.FamilyName = value
+		case "givenname":
+			user.Name.GivenName = value
+		default:
+			return newSCIMError(http.StatusBadRequest, "invalidPath", "unsupported attribute %q", op.Path)
+		}
+		return nil
+
+	case "emails":
+		// Only the primary email address is kept, so any change to the
+		// emails replaces it
+		if remove {
+			user.Emails = nil
+			return nil
+		}
+		if path.sub == "value" {
+			value, err := scimStringValue(op.Path, op.Value)
+			if err != nil {
+				return err
+			}
+			user.Emails = []scimEmail{{Value: value, Primary: true}}
+			return nil
+		}
+		if path.sub != "" {
+			return newSCIMError(http.StatusBadRequest, "invalidPath", "unsupported attribute %q", op.Path)
+		}
+		var emails []scimEmail
+		if _, ok := op.Value.(map[string]interface{}); ok {
+			var email scimEmail
+			if err := scimDecodeValue(op.Value, &email); err != nil {
+				return err
+			}
+			emails = append(emails, email)
+		} else if err := scimDecodeValue(op.Value, &emails); err != nil {
+			return err
+		}
+		user.Emails = emails
+		return nil
+
+	case "groups":
+		return newSCIMError(http.StatusBadRequest, "mutability", "groups are managed through the Groups endpoint")
+	}
+
+	return newSCIMError(http.StatusBadRequest, "invalidPath", "unsupported attribute %q", op.Path)
+}
+
+// applySCIMGroupPatch applies a single PATCH operation to a group
+func applySCIMGroupPatch(group *scimGroup, op scimPatchOperation) error {
+	if op.Path == "" {
+		ops, err := scimAttributeMapOperations(op)
+		if err != nil {
+			return err
+		}
+		for _, op := range ops {
+			if err := applySCIMGroupPatch(group, op); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	path, err := parseSCIMPatchPath(op.Path)
+	if err != nil {
+		return err
+	}
+	remove := op.Op == "remove"
+
+	switch path.attr {
+	case "displayname":
+		if remove {
+			return newSCIMError(http.StatusBadRequest, "mutability", "displayName cannot be removed")
+		}
+		group.DisplayName, err = scimStringValue("displayName", op.Value)
+		return err
+
+	case "externalid":
+		group.ExternalID = ""
+		if !remove {
+			group.ExternalID, err = scimStringValue("externalId", op.Value)
+		}
+		return err
+
+	case "members":
+		if path.sub != "" {
+			return newSCIMError(http.StatusBadRequest, "invalidPath", "unsupported attribute %q", op.Path)
+		}
+
+		var members []scimMember
+		if op.Value != nil {
+			if _, ok := op.Value.(map[string]interface{}); ok {
+				var member scimMember
+				if err := scimDecodeValue(op.Value, &member); err != nil {
+					return err
+				}
+				members = append(members, member)
+			} else if err := scimDecodeValue(op.Value, &members); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case remove && path.filter != nil:
+			kept := make([]scimMember, 0, len(group.Members))
+			for _, member := range group.Members {
+				m, err := scimResourceMap(member)
+				if err != nil {
+					return err
+				}
+				if !path.filter.matches(m) {
+					kept = append(kept, member)
+				}
+			}
+			group.Members = kept
+
+		case remove && len(members) > 0:
+			removed := make(map[string]bool, len(members))
+			for _, member := range members {
+				removed[member.Value] = true
+			}
+			kept := make([]scimMember, 0, len(group.Members))
+			for _, member := range group.Members {
+				if !removed[member.Value] {
+					kept = append(kept, member)
+				}
+			}
+			group.Members = kept
+
+		case remove:
+			group.Members = []scimMember{}
+
+		case path.filter != nil:
+			return newSCIMError(http.StatusBadRequest, "invalidPath", "filters are only supported when removing members")
+
+		case op.Op == "replace":
+			group.Members = append([]scimMember{}, members...)
+
+		default:
+			existing := make(map[string]bool, len(group.Members))
+			for _, member := range group.Members {
+				existing[member.Value] = true
+			}
+			for _, member := range members {
+				if !existing[member.Value] {
+					group.Members = append(group.Members, member)
+					existing[member.Value] = true
+				}
+			}
+		}
+		return nil
+	}
+
+	return newSCIMError(http.StatusBadRequest, "invalidPath", "unsupported attribute %q", op.Path)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"regexp"
 	"sync"
+	"time"
 
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
@@ -57,6 +58,10 @@ type IdentityStore struct {
 	lock     sync.RWMutex
 	oidcLock sync.RWMutex
 
+	// oidcRefreshTokenLock serializes the rotation and revocation of OIDC
+	// provider refresh tokens.
+	oidcRefreshTokenLock sync.Mutex
+
 	// groupLock is used to protect modifications to group entries
 	groupLock sync.RWMutex
 
@@ -69,6 +74,16 @@ type IdentityStore struct {
 	// for an ID token during an authorization code flow.
 	oidcAuthCodeCache *oidcCache
 
+	// oidcDeviceCodeCache stores OIDC device authorization requests until
+	// they're approved by the end-user and polled by the device.
+	oidcDeviceCodeCache *oidcCache
+
+	// mergeAnalysisLastRun is the time the periodic entity merge analysis
+	// last ran, protected by mergeAnalysisLock. The lock is also held for
+	// the whole of each entity merge analysis.
+	mergeAnalysisLastRun time.Time
+	mergeAnalysisLock    sync.Mutex
+
 	// logger is the server logger copied over from core
 	logger log.Logger
 
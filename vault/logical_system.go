@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"hash"
 	"net/http"
+	"os"
 	"path"
 	"path/filepath"
 	"sort"
@@ -106,14 +107,22 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 				"config/auditing/*",
 				"config/ui/headers/*",
 				"plugins/catalog/*",
+				"plugins/install",
 				"revoke-prefix/*",
 				"revoke-force/*",
 				"leases/revoke-prefix/*",
 				"leases/revoke-force/*",
 				"leases/lookup/*",
 				"storage/raft/snapshot-auto/config/*",
+				"storage/migration",
+				"storage/migration/*",
 				"leases",
+				"leases/search",
+				"leases/revoke-filtered",
+				"leases/irrevocable",
+				"leases/irrevocable/*",
 				"internal/inspect/*",
+				"policy/simulate",
 			},
 
 			Unauthenticated: []string{
@@ -152,6 +161,7 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 				"rekey-recovery-key/update",
 				"rekey-recovery-key/verify",
 				"mfa/validate",
+				"mfa/webauthn/options",
 			},
 
 			LocalStorage: []string{
@@ -173,6 +183,7 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 	b.Backend.Paths = append(b.Backend.Paths, b.pluginsCatalogListPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.pluginsCatalogCRUDPath())
 	b.Backend.Paths = append(b.Backend.Paths, b.pluginsReloadPath())
+	b.Backend.Paths = append(b.Backend.Paths, b.pluginsInstallPath())
 	b.Backend.Paths = append(b.Backend.Paths, b.auditPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.mountPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.authPaths()...)
@@ -188,6 +199,7 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 	b.Backend.Paths = append(b.Backend.Paths, b.monitorPath())
 	b.Backend.Paths = append(b.Backend.Paths, b.inFlightRequestPath())
 	b.Backend.Paths = append(b.Backend.Paths, b.hostInfoPath())
+	b.Backend.Paths = append(b.Backend.Paths, b.delayedUsersPath())
 	b.Backend.Paths = append(b.Backend.Paths, b.quotasPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.rootActivityPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.loginMFAPaths()...)
@@ -200,6 +212,10 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 		b.Backend.Paths = append(b.Backend.Paths, b.raftStoragePaths()...)
 	}
 
+	if core.storageMigration != nil {
+		b.Backend.Paths = append(b.Backend.Paths, b.storageMigrationPaths()...)
+	}
+
 	// If the node is in a DR secondary cluster, gate some raft operations by
 	// the DR operation token.
 	if core.IsDRSecondary() {
@@ -393,6 +409,224 @@ func (b *SystemBackend) handleLeaseList(ctx context.Context, req *logical.Reques
 	return resp, nil
 }
 
+func (b *SystemBackend) handleIrrevocableLeaseList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	limit := d.Get("limit").(int)
+	if limit < 1 || limit > MaxLeaseSearchLimit {
+		return logical.ErrorResponse("limit must be between 1 and %d", MaxLeaseSearchLimit), logical.ErrInvalidRequest
+	}
+
+	leases, more, err := b.Core.expiration.listIrrevocableLeaseDetails(ctx, d.Get("include_child_namespaces").(bool), d.Get("after").(string), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"leases": leases,
+		},
+	}
+	if more {
+		resp.Data["next_after"] = leases[len(leases)-1].LeaseID
+	}
+	return resp, nil
+}
+
+func (b *SystemBackend) handleIrrevocableLeaseRetry(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	leaseID := d.Get("lease_id").(string)
+	if leaseID == "" {
+		return logical.ErrorResponse("lease_id must be specified"), logical.ErrInvalidRequest
+	}
+	attempts := d.Get("attempts").(int)
+	backoff := time.Duration(d.Get("backoff").(int)) * time.Second
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	revokeCtx := namespace.ContextWithNamespace(b.Core.activeContext, ns)
+
+	err = b.Core.expiration.RetryIrrevocableLease(revokeCtx, leaseID, attempts, backoff)
+	switch {
+	case err == nil:
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"revoked": true,
+			},
+		}, nil
+	case errors.Is(err, ErrLeaseNotIrrevocable):
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"revoked": false,
+			"error":   irrevocableErrorString(err),
+		},
+	}
+	if attempts > 1 {
+		resp.AddWarning(fmt.Sprintf("Revocation failed, %d more attempts were scheduled.", attempts-1))
+	}
+	return resp, nil
+}
+
+func (b *SystemBackend) handleIrrevocableLeaseResolve(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	leaseID := d.Get("lease_id").(string)
+	if leaseID == "" {
+		return logical.ErrorResponse("lease_id must be specified"), logical.ErrInvalidRequest
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolveCtx := namespace.ContextWithNamespace(b.Core.activeContext, ns)
+
+	if err := b.Core.expiration.ResolveIrrevocableLease(resolveCtx, leaseID); err != nil {
+		if errors.Is(err, ErrLeaseNotIrrevocable) {
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		}
+		return handleErrorNoReadOnlyForward(err)
+	}
+	return nil, nil
+}
+
+// leaseFilterFromFieldData parses the fields returned by leaseFilterFields
+func leaseFilterFromFieldData(d *framework.FieldData) (*leaseSearchFilter, error) {
+	filter := &leaseSearchFilter{
+		Prefix:                 d.Get("prefix").(string),
+		EntityID:               d.Get("entity_id").(string),
+		Role:                   d.Get("role").(string),
+		MinTTL:                 time.Duration(d.Get("ttl_min").(int)) * time.Second,
+		MaxTTL:                 time.Duration(d.Get("ttl_max").(int)) * time.Second,
+		IncludeChildNamespaces: d.Get("include_child_namespaces").(bool),
+	}
+	if issuedAfter, ok := d.GetOk("issued_after"); ok {
+		filter.IssuedAfter = issuedAfter.(time.Time)
+	}
+	if issuedBefore, ok := d.GetOk("issued_before"); ok {
+		filter.IssuedBefore = issuedBefore.(time.Time)
+	}
+
+	if filter.MinTTL < 0 || filter.MaxTTL < 0 {
+		return nil, errors.New("ttl_min and ttl_max must not be negative")
+	}
+	if filter.MaxTTL > 0 && filter.MinTTL > filter.MaxTTL {
+		return nil, errors.New("ttl_min must not be greater than ttl_max")
+	}
+	if !filter.IssuedAfter.IsZero() && !filter.IssuedBefore.IsZero() && !filter.IssuedAfter.Before(filter.IssuedBefore) {
+		return nil, errors.New("issued_after must be before issued_before")
+	}
+
+	return filter, nil
+}
+
+func (b *SystemBackend) handleLeaseSearch(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	filter, err := leaseFilterFromFieldData(d)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	limit := d.Get("limit").(int)
+	if limit < 1 || limit > MaxLeaseSearchLimit {
+		return logical.ErrorResponse("limit must be between 1 and %d", MaxLeaseSearchLimit), logical.ErrInvalidRequest
+	}
+
+	leases, more, err := b.Core.expiration.searchLeases(ctx, filter, d.Get("after").(string), limit)
+	if err != nil {
+		return handleError(err)
+	}
+
+	keys := make([]string, 0, len(leases))
+	keyInfo := make(map[string]interface{}, len(leases))
+	for _, lease := range leases {
+		info := map[string]interface{}{
+			"issue_time":  lease.IssueTime,
+			"expire_time": nil,
+			"ttl":         int64(0),
+			"role":        lease.Role,
+			"entity_id":   lease.EntityID,
+		}
+		if !lease.ExpireTime.IsZero() {
+			info["expire_time"] = lease.ExpireTime
+			info["ttl"] = int64(time.Until(lease.ExpireTime).Seconds())
+		}
+		keys = append(keys, lease.LeaseID)
+		keyInfo[lease.LeaseID] = info
+	}
+
+	resp := logical.ListResponseWithInfo(keys, keyInfo)
+	if more {
+		resp.Data["next_after"] = keys[len(keys)-1]
+	}
+	return resp, nil
+}
+
+func (b *SystemBackend) handleLeaseRevokeFiltered(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	filter, err := leaseFilterFromFieldData(d)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+	if *filter == (leaseSearchFilter{IncludeChildNamespaces: filter.IncludeChildNamespaces}) {
+		return logical.ErrorResponse("at least one filter must be specified"), logical.ErrInvalidRequest
+	}
+
+	status, err := b.Core.expiration.RevokeFiltered(ctx, filter)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	return logical.RespondWithStatusCode(leaseRevocationStatusResponse(status), req, http.StatusAccepted)
+}
+
+func (b *SystemBackend) handleLeaseRevokeFilteredStatus(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	status, err := b.Core.expiration.FilteredRevocationStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil {
+		return nil, nil
+	}
+	return leaseRevocationStatusResponse(status), nil
+}
+
+func leaseRevocationStatusResponse(status *leaseRevocationStatus) *logical.Response {
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	failedLeaseIDs := status.FailedLeaseIDs
+	if failedLeaseIDs == nil {
+		failedLeaseIDs = []string{}
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"state":            status.State,
+			"namespace_path":   status.RequestNSPath,
+			"start_time":       formatTime(status.StartTime),
+			"end_time":         formatTime(status.EndTime),
+			"leases_matched":   status.LeasesMatched,
+			"leases_revoked":   status.LeasesRevoked,
+			"leases_failed":    status.LeasesFailed,
+			"failed_lease_ids": failedLeaseIDs,
+			"last_error":       status.LastError,
+			"filter": map[string]interface{}{
+				"prefix":                   status.Filter.Prefix,
+				"entity_id":                status.Filter.EntityID,
+				"role":                     status.Filter.Role,
+				"issued_after":             formatTime(status.Filter.IssuedAfter),
+				"issued_before":            formatTime(status.Filter.IssuedBefore),
+				"ttl_min":                  int64(status.Filter.MinTTL.Seconds()),
+				"ttl_max":                  int64(status.Filter.MaxTTL.Seconds()),
+				"include_child_namespaces": status.Filter.IncludeChildNamespaces,
+			},
+		},
+	}
+}
+
 func (b *SystemBackend) handlePluginCatalogTypedList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	pluginType, err := consts.ParsePluginType(d.Get("type").(string))
 	if err != nil {
@@ -539,7 +773,12 @@ func (b *SystemBackend) handlePluginCatalogUpdate(ctx context.Context, _ *logica
 		return logical.ErrorResponse("Could not decode SHA-256 value from Hex"), err
 	}
 
-	err = b.Core.pluginCatalog.Set(ctx, pluginName, pluginType, pluginVersion, parts[0], args, env, sha256Bytes)
+	runtime, err := pluginRuntimeConfig(d)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	err = b.Core.pluginCatalog.Set(ctx, pluginName, pluginType, pluginVersion, parts[0], args, env, sha256Bytes, runtime)
 	if err != nil {
 		if errors.Is(err, ErrPluginNotFound) || strings.HasPrefix(err.Error(), "plugin version mismatch") {
 			return logical.ErrorResponse(err.Error()), nil
@@ -550,6 +789,33 @@ func (b *SystemBackend) handlePluginCatalogUpdate(ctx context.Context, _ *logica
 	return nil, nil
 }
 
+// pluginRuntimeConfig returns the runtime configuration of a plugin, or nil if
+// the plugin runs without resource limits and sandboxing.
+func pluginRuntimeConfig(d *framework.FieldData) (*pluginutil.PluginRuntimeConfig, error) {
+	runtime := &pluginutil.PluginRuntimeConfig{
+		CPUQuota:       int64(d.Get("cpu_quota").(int)),
+		UID:            d.Get("uid").(int),
+		GID:            d.Get("gid").(int),
+		RestrictEnv:    d.Get("restrict_env").(bool),
+		SeccompProfile: d.Get("seccomp_profile").(string),
+	}
+	if memoryLimitRaw := d.Get("memory_limit").(string); memoryLimitRaw != "" {
+		memoryLimit, err := parseutil.ParseCapacityString(memoryLimitRaw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory_limit: %w", err)
+		}
+		runtime.MemoryLimit = int64(memoryLimit)
+	}
+
+	if err := runtime.Validate(); err != nil {
+		return nil, err
+	}
+	if !runtime.Sandboxed() {
+		return nil, nil
+	}
+	return runtime, nil
+}
+
 func (b *SystemBackend) handlePluginCatalogRead(ctx context.Context, _ *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	pluginName := d.Get("name").(string)
 	if pluginName == "" {
@@ -606,6 +872,23 @@ func (b *SystemBackend) handlePluginCatalogRead(ctx context.Context, _ *logical.
 		data["deprecation_status"] = status.String()
 	}
 
+	if plugin.Runtime != nil {
+		seccompProfile := ""
+		if plugin.Runtime.SeccompProfile != "" {
+			seccompProfile, err = filepath.Rel(b.Core.pluginCatalog.directory, plugin.Runtime.SeccompProfile)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		data["memory_limit"] = plugin.Runtime.MemoryLimit
+		data["cpu_quota"] = plugin.Runtime.CPUQuota
+		data["uid"] = plugin.Runtime.UID
+		data["gid"] = plugin.Runtime.GID
+		data["restrict_env"] = plugin.Runtime.RestrictEnv
+		data["seccomp_profile"] = seccompProfile
+	}
+
 	return &logical.Response{
 		Data: data,
 	}, nil
@@ -664,6 +947,85 @@ func getVersion(d *framework.FieldData) (version string, builtin bool, err error
 	return version, versions.IsBuiltinVersion(version), nil
 }
 
+func (b *SystemBackend) handlePluginInstall(ctx context.Context, _ *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	bundle := d.Get("bundle").(string)
+	if bundle == "" {
+		return logical.ErrorResponse("missing bundle"), nil
+	}
+	if filepath.Base(bundle) != bundle || bundle == "." || bundle == ".." {
+		return logical.ErrorResponse("bundle must be the name of a file in the plugin bundle directory"), nil
+	}
+
+	if b.Core.pluginDirectory == "" {
+		return logical.ErrorResponse(ErrDirectoryNotConfigured.Error()), nil
+	}
+	if b.Core.pluginBundleDirectory == "" {
+		return logical.ErrorResponse("no plugin bundle directory is configured"), nil
+	}
+	if len(b.Core.pluginBundleTrustedKeys) == 0 {
+		return logical.ErrorResponse("no plugin bundle trusted keys are configured"), nil
+	}
+
+	bundlePath := filepath.Join(b.Core.pluginBundleDirectory, bundle)
+	manifest, pluginType, installs, err := b.Core.readPluginBundle(bundlePath)
+	if err != nil {
+		if errors.Is(err, errPluginBundleInvalid) || errors.Is(err, os.ErrNotExist) {
+			return logical.ErrorResponse(err.Error()), nil
+		}
+		return nil, err
+	}
+
+	written, err := b.Core.unpackPluginBundle(bundlePath, installs)
+	if err != nil {
+		if errors.Is(err, errPluginBundleInvalid) {
+			return logical.ErrorResponse(err.Error()), nil
+		}
+		return nil, err
+	}
+
+	// Either every version of the bundle is installed, or the catalog and the
+	// plugin directory are left as they were
+	previous, err := b.Core.pluginCatalogEntries(ctx, manifest.Name, pluginType, installs)
+	if err != nil {
+		removePluginBundleBinaries(written)
+		return nil, err
+	}
+	rollback := func() {
+		if err := b.Core.restorePluginCatalogEntries(ctx, manifest.Name, pluginType, previous); err != nil {
+			b.Core.logger.Error("failed to restore the plugin catalog after a failed plugin install", "plugin", manifest.Name, "error", err)
+		}
+		removePluginBundleBinaries(written)
+	}
+
+	for _, install := range installs {
+		if err := b.Core.CheckPluginPerms(install.command); err != nil {
+			removePluginBundleBinaries(written)
+			return nil, err
+		}
+	}
+
+	installed := make([]string, 0, len(installs))
+	for _, install := range installs {
+		err = b.Core.pluginCatalog.Set(ctx, manifest.Name, pluginType, install.version, install.command, install.args, install.env, install.sha256, nil)
+		if err != nil {
+			rollback()
+			if errors.Is(err, ErrPluginNotFound) || strings.HasPrefix(err.Error(), "plugin version mismatch") {
+				return logical.ErrorResponse(err.Error()), nil
+			}
+			return nil, err
+		}
+		installed = append(installed, install.version)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"name":     manifest.Name,
+			"type":     pluginType.String(),
+			"versions": installed,
+		},
+	}, nil
+}
+
 func (b *SystemBackend) handlePluginReloadUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	pluginName := d.Get("plugin").(string)
 	pluginMounts := d.Get("mounts").([]string)
@@ -792,6 +1154,71 @@ func (b *SystemBackend) handleCapabilitiesAccessor(ctx context.Context, req *log
 	return b.handleCapabilities(ctx, req, d)
 }
 
+// handlePolicySimulate explains whether a request would be allowed by the
+// policies of a token, an entity or a set of named policies.
+func (b *SystemBackend) handlePolicySimulate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	operation := logical.Operation(strings.ToLower(d.Get("operation").(string)))
+	switch operation {
+	case logical.CreateOperation, logical.ReadOperation, logical.UpdateOperation,
+		logical.PatchOperation, logical.DeleteOperation, logical.ListOperation:
+	default:
+		return logical.ErrorResponse("unsupported operation %q", operation), logical.ErrInvalidRequest
+	}
+
+	result, err := b.Core.SimulatePolicy(ctx, &PolicySimulationInput{
+		Token:      d.Get("token").(string),
+		Accessor:   d.Get("accessor").(string),
+		EntityID:   d.Get("entity_id").(string),
+		Policies:   d.Get("policies").([]string),
+		Path:       d.Get("path").(string),
+		Operation:  operation,
+		Parameters: d.Get("parameters").(map[string]interface{}),
+		WrapTTL:    time.Duration(d.Get("wrap_ttl").(int)) * time.Second,
+
+		RemoteAddress: d.Get("remote_address").(string),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	grantingPolicies := make([]map[string]interface{}, 0, len(result.GrantingPolicies))
+	for _, policy := range result.GrantingPolicies {
+		grantingPolicies = append(grantingPolicies, map[string]interface{}{
+			"name":         policy.Name,
+			"namespace_id": policy.NamespaceId,
+			"type":         policy.Type,
+		})
+	}
+
+	policies := make([]map[string]interface{}, 0, len(result.Policies))
+	for _, match := range result.Policies {
+		policies = append(policies, map[string]interface{}{
+			"name":             match.Name,
+			"namespace_path":   match.NamespacePath,
+			"matched_path":     match.MatchedPath,
+			"capabilities":     match.Capabilities,
+			"allowed":          match.Allowed,
+			"denial_reason":    string(match.DenialReason),
+			"denied_parameter": match.DeniedParameter,
+		})
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"allowed":           result.Allowed,
+			"root":              result.IsRoot,
+			"sudo_required":     result.SudoRequired,
+			"sudo":              result.HasSudo,
+			"matched_path":      result.MatchedPath,
+			"denial_reason":     string(result.DenialReason),
+			"denied_parameter":  result.DeniedParameter,
+			"capabilities":      result.Capabilities,
+			"granting_policies": grantingPolicies,
+			"policies":          policies,
+		},
+	}, nil
+}
+
 // handleCapabilities returns the ACL capabilities of the token for a given path
 func (b *SystemBackend) handleCapabilities(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	var token string
@@ -964,6 +1391,9 @@ func (b *SystemBackend) mountInfo(ctx context.Context, entry *MountEntry) map[st
 			"user_lockout_threshold":              entry.Config.UserLockoutConfig.LockoutThreshold,
 			"user_lockout_duration":               int64(entry.Config.UserLockoutConfig.LockoutDuration.Seconds()),
 			"user_lockout_disable":                entry.Config.UserLockoutConfig.DisableLockout,
+			"user_lockout_progressive_delay":      entry.Config.UserLockoutConfig.ProgressiveDelay,
+			"user_lockout_progressive_delay_base": int64(entry.Config.UserLockoutConfig.ProgressiveDelayBase.Seconds()),
+			"user_lockout_progressive_delay_max":  int64(entry.Config.UserLockoutConfig.ProgressiveDelayMax.Seconds()),
 		}
 		entryConfig["user_lockout_config"] = userLockoutConfig
 	}
@@ -1632,6 +2062,9 @@ func (b *SystemBackend) handleTuneReadCommon(ctx context.Context, path string) (
 		resp.Data["user_lockout_threshold"] = mountEntry.Config.UserLockoutConfig.LockoutThreshold
 		resp.Data["user_lockout_duration"] = int64(mountEntry.Config.UserLockoutConfig.LockoutDuration.Seconds())
 		resp.Data["user_lockout_disable"] = mountEntry.Config.UserLockoutConfig.DisableLockout
+		resp.Data["user_lockout_progressive_delay"] = mountEntry.Config.UserLockoutConfig.ProgressiveDelay
+		resp.Data["user_lockout_progressive_delay_base"] = int64(mountEntry.Config.UserLockoutConfig.ProgressiveDelayBase.Seconds())
+		resp.Data["user_lockout_progressive_delay_max"] = int64(mountEntry.Config.UserLockoutConfig.ProgressiveDelayMax.Seconds())
 	}
 
 	if len(mountEntry.Options) > 0 {
@@ -1767,9 +2200,9 @@ func (b *SystemBackend) handleTuneWriteCommon(ctx context.Context, path string,
 					logical.ErrInvalidRequest
 			}
 
-			// Supported auth methods for user lockout configuration: ldap, approle, userpass
+			// Supported auth methods for user lockout configuration: ldap, approle, userpass, okta
 			switch strings.ToLower(mountEntry.Type) {
-			case "ldap", "approle", "userpass":
+			case "ldap", "approle", "userpass", "okta":
 			default:
 				return logical.ErrorResponse("tuning of user lockout configuration for auth type %q not allowed", mountEntry.Type),
 					logical.ErrInvalidRequest
@@ -1785,6 +2218,8 @@ func (b *SystemBackend) handleTuneWriteCommon(ctx context.Context, path string,
 		var newUserLockoutDuration, oldUserLockoutDuration time.Duration
 		var newUserLockoutCounterReset, oldUserLockoutCounterReset time.Duration
 		var oldUserLockoutDisable bool
+		var oldProgressiveDelay bool
+		var oldProgressiveDelayBase, oldProgressiveDelayMax time.Duration
 
 		if apiuserLockoutConfig.LockoutThreshold != "" {
 			userLockoutThreshold, err := strconv.ParseUint(apiuserLockoutConfig.LockoutThreshold, 10, 64)
@@ -1837,6 +2272,41 @@ func (b *SystemBackend) handleTuneWriteCommon(ctx context.Context, path string,
 			mountEntry.Config.UserLockoutConfig.DisableLockout = *userLockoutDisable
 		}
 
+		if apiuserLockoutConfig.ProgressiveDelay != nil {
+			oldProgressiveDelay = mountEntry.Config.UserLockoutConfig.ProgressiveDelay
+			mountEntry.Config.UserLockoutConfig.ProgressiveDelay = *apiuserLockoutConfig.ProgressiveDelay
+		}
+
+		if apiuserLockoutConfig.ProgressiveDelayBase != "" {
+			oldProgressiveDelayBase = mountEntry.Config.UserLockoutConfig.ProgressiveDelayBase
+			var newProgressiveDelayBase time.Duration
+			if apiuserLockoutConfig.ProgressiveDelayBase != "system" {
+				newProgressiveDelayBase, err = parseutil.ParseDurationSecond(apiuserLockoutConfig.ProgressiveDelayBase)
+				if err != nil {
+					return handleError(err)
+				}
+				if newProgressiveDelayBase <= 0 {
+					return logical.ErrorResponse("progressive_delay_base must be positive"), logical.ErrInvalidRequest
+				}
+			}
+			mountEntry.Config.UserLockoutConfig.ProgressiveDelayBase = newProgressiveDelayBase
+		}
+
+		if apiuserLockoutConfig.ProgressiveDelayMax != "" {
+			oldProgressiveDelayMax = mountEntry.Config.UserLockoutConfig.ProgressiveDelayMax
+			var newProgressiveDelayMax time.Duration
+			if apiuserLockoutConfig.ProgressiveDelayMax != "system" {
+				newProgressiveDelayMax, err = parseutil.ParseDurationSecond(apiuserLockoutConfig.ProgressiveDelayMax)
+				if err != nil {
+					return handleError(err)
+				}
+				if newProgressiveDelayMax <= 0 {
+					return logical.ErrorResponse("progressive_delay_max must be positive"), logical.ErrInvalidRequest
+				}
+			}
+			mountEntry.Config.UserLockoutConfig.ProgressiveDelayMax = newProgressiveDelayMax
+		}
+
 		// Update the mount table
 		if len(userLockoutConfigMap) > 0 {
 			switch {
@@ -1850,6 +2320,9 @@ func (b *SystemBackend) handleTuneWriteCommon(ctx context.Context, path string,
 				mountEntry.Config.UserLockoutConfig.LockoutThreshold = oldUserLockoutThreshold
 				mountEntry.Config.UserLockoutConfig.LockoutDuration = oldUserLockoutDuration
 				mountEntry.Config.UserLockoutConfig.DisableLockout = oldUserLockoutDisable
+				mountEntry.Config.UserLockoutConfig.ProgressiveDelay = oldProgressiveDelay
+				mountEntry.Config.UserLockoutConfig.ProgressiveDelayBase = oldProgressiveDelayBase
+				mountEntry.Config.UserLockoutConfig.ProgressiveDelayMax = oldProgressiveDelayMax
 				return handleError(err)
 			}
 			if b.Core.logger.IsInfo() {
@@ -3557,12 +4030,14 @@ func (b *SystemBackend) responseWrappingUnwrap(ctx context.Context, te *logical.
 	tokenID := te.ID
 	if thirdParty {
 		// Use the token to decrement the use count to avoid a second operation on the token.
-		_, err := b.Core.tokenStore.UseTokenByID(ctx, tokenID)
+		usedTE, err := b.Core.tokenStore.UseTokenByID(ctx, tokenID)
 		if err != nil {
 			return "", fmt.Errorf("error decrementing wrapping token's use-count: %w", err)
 		}
-
-		defer b.Core.tokenStore.revokeOrphan(ctx, tokenID)
+		// Multi-use wrapping tokens are only revoked by their last unwrap
+		if usedTE.NumUses == tokenRevocationPending {
+			defer b.Core.tokenStore.revokeOrphan(ctx, tokenID)
+		}
 	}
 
 	cubbyReq := &logical.Request{
@@ -3627,6 +4102,72 @@ func (b *SystemBackend) handleInFlightRequestData(_ context.Context, req *logica
 	return resp, nil
 }
 
+// handleDelayedUsersRead lists the users and client addresses whose login
+// attempts are being delayed on auth mounts with progressive delays enabled
+func (b *SystemBackend) handleDelayedUsersRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mountAccessor := data.Get("mount_accessor").(string)
+
+	byMountAccessor := make(map[string]map[string]interface{})
+	var accessors []string
+	var total int
+	add := func(key string, login *delayedLogin, nameKey string) {
+		if mountAccessor != "" && login.mountAccessor != mountAccessor {
+			return
+		}
+		mountData, ok := byMountAccessor[login.mountAccessor]
+		if !ok {
+			entry := b.Core.router.MatchingMountByAccessor(login.mountAccessor)
+			if entry == nil {
+				return
+			}
+			if entry.Namespace().ID != ns.ID && !entry.Namespace().HasParent(ns) {
+				return
+			}
+			mountData = map[string]interface{}{
+				"mount_accessor":   login.mountAccessor,
+				"mount_path":       entry.APIPath(),
+				"mount_type":       entry.Type,
+				"users":            []map[string]interface{}{},
+				"source_addresses": []map[string]interface{}{},
+			}
+			byMountAccessor[login.mountAccessor] = mountData
+			accessors = append(accessors, login.mountAccessor)
+		}
+		mountData[key] = append(mountData[key].([]map[string]interface{}), map[string]interface{}{
+			nameKey:              login.name,
+			"failed_login_count": login.count,
+			"delay":              int64(login.delay.Seconds()),
+			"delayed_until":      login.delayedUntil.UTC().Format(time.RFC3339),
+		})
+	}
+
+	users, sources := b.Core.delayedLogins(time.Now())
+	for _, user := range users {
+		add("users", user, "alias_name")
+	}
+	for _, source := range sources {
+		add("source_addresses", source, "remote_address")
+	}
+
+	sort.Strings(accessors)
+	mounts := make([]map[string]interface{}, 0, len(accessors))
+	for _, accessor := range accessors {
+		mounts = append(mounts, byMountAccessor[accessor])
+		total += len(byMountAccessor[accessor]["users"].([]map[string]interface{}))
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"total":             total,
+			"by_mount_accessor": mounts,
+		},
+	}, nil
+}
+
 func (b *SystemBackend) handleMonitor(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	ll := data.Get("log_level").(string)
 	w := req.ResponseWriter
@@ -3851,6 +4392,29 @@ func (b *SystemBackend) handleWrappingLookup(ctx context.Context, req *logical.R
 	if creationPath != nil {
 		resp.Data["creation_path"] = cubbyResp.Data["creation_path"]
 	}
+	if maxUses := te.InternalMeta[wrappingMaxUsesMetaKey]; maxUses != "" {
+		uses, err := strconv.Atoi(maxUses)
+		if err != nil {
+			return nil, fmt.Errorf("error reading max_uses value from wrapping information: %w", err)
+		}
+		resp.Data["max_uses"] = uses
+		resp.Data["remaining_uses"] = 0
+		if te.NumUses > 0 {
+			resp.Data["remaining_uses"] = te.NumUses
+		}
+	}
+	if boundEntityID := te.InternalMeta[wrappingBoundEntityIDMetaKey]; boundEntityID != "" {
+		resp.Data["bound_entity_id"] = boundEntityID
+	}
+	if boundRole := te.InternalMeta[wrappingBoundAppRoleRoleMetaKey]; boundRole != "" {
+		resp.Data["bound_approle_role"] = boundRole
+	}
+	if boundCIDRs := te.InternalMeta[wrappingBoundCIDRsMetaKey]; boundCIDRs != "" {
+		resp.Data["bound_cidrs"] = strings.Split(boundCIDRs, ",")
+	}
+	if misuseAttempts := cubbyResp.Data["misuse_attempts"]; misuseAttempts != nil {
+		resp.Data["misuse_attempts"] = misuseAttempts
+	}
 
 	return resp, nil
 }
@@ -4052,18 +4616,19 @@ func hasMountAccess(ctx context.Context, acl *ACL, path string) bool {
 		}
 
 		perms := v.(*ACLPermissions)
+		capabilitiesBitmap := perms.allCapabilitiesBitmap()
 
 		switch {
-		case perms.CapabilitiesBitmap&DenyCapabilityInt > 0:
+		case capabilitiesBitmap&DenyCapabilityInt > 0:
 			return false
 
-		case perms.CapabilitiesBitmap&CreateCapabilityInt > 0,
-			perms.CapabilitiesBitmap&DeleteCapabilityInt > 0,
-			perms.CapabilitiesBitmap&ListCapabilityInt > 0,
-			perms.CapabilitiesBitmap&ReadCapabilityInt > 0,
-			perms.CapabilitiesBitmap&SudoCapabilityInt > 0,
-			perms.CapabilitiesBitmap&UpdateCapabilityInt > 0,
-			perms.CapabilitiesBitmap&PatchCapabilityInt > 0:
+		case capabilitiesBitmap&CreateCapabilityInt > 0,
+			capabilitiesBitmap&DeleteCapabilityInt > 0,
+			capabilitiesBitmap&ListCapabilityInt > 0,
+			capabilitiesBitmap&ReadCapabilityInt > 0,
+			capabilitiesBitmap&SudoCapabilityInt > 0,
+			capabilitiesBitmap&UpdateCapabilityInt > 0,
+			capabilitiesBitmap&PatchCapabilityInt > 0:
 
 			aclCapabilitiesGiven = true
 
@@ -4355,33 +4920,34 @@ func (b *SystemBackend) pathInternalUIResultantACL(ctx context.Context, req *log
 		}
 
 		perms := v.(*ACLPermissions)
+		capabilitiesBitmap := perms.allCapabilitiesBitmap()
 		capabilities := []string{}
 
-		if perms.CapabilitiesBitmap&CreateCapabilityInt > 0 {
+		if capabilitiesBitmap&CreateCapabilityInt > 0 {
 			capabilities = append(capabilities, CreateCapability)
 		}
-		if perms.CapabilitiesBitmap&DeleteCapabilityInt > 0 {
+		if capabilitiesBitmap&DeleteCapabilityInt > 0 {
 			capabilities = append(capabilities, DeleteCapability)
 		}
-		if perms.CapabilitiesBitmap&ListCapabilityInt > 0 {
+		if capabilitiesBitmap&ListCapabilityInt > 0 {
 			capabilities = append(capabilities, ListCapability)
 		}
-		if perms.CapabilitiesBitmap&ReadCapabilityInt > 0 {
+		if capabilitiesBitmap&ReadCapabilityInt > 0 {
 			capabilities = append(capabilities, ReadCapability)
 		}
-		if perms.CapabilitiesBitmap&SudoCapabilityInt > 0 {
+		if capabilitiesBitmap&SudoCapabilityInt > 0 {
 			capabilities = append(capabilities, SudoCapability)
 		}
-		if perms.CapabilitiesBitmap&UpdateCapabilityInt > 0 {
+		if capabilitiesBitmap&UpdateCapabilityInt > 0 {
 			capabilities = append(capabilities, UpdateCapability)
 		}
-		if perms.CapabilitiesBitmap&PatchCapabilityInt > 0 {
+		if capabilitiesBitmap&PatchCapabilityInt > 0 {
 			capabilities = append(capabilities, PatchCapability)
 		}
 
 		// If "deny" is explicitly set or if the path has no capabilities at all,
 		// set the path capabilities to "deny"
-		if perms.CapabilitiesBitmap&DenyCapabilityInt > 0 || len(capabilities) == 0 {
+		if capabilitiesBitmap&DenyCapabilityInt > 0 || len(capabilities) == 0 {
 			capabilities = []string{DenyCapability}
 		}
 
@@ -5458,6 +6024,23 @@ or delete a policy.
 		"",
 	},
 
+	"policy-simulate": {
+		"Explains whether a request would be allowed by a set of policies.",
+		`
+Evaluates a request path, operation and parameters against the ACL policies of
+a token, a token accessor, an entity or a set of named policies without
+performing the request. The response contains the decision, the path rule that
+matched and, for denied requests, the reason and any parameter constraint that
+caused the denial. Each policy that has a path rule matching the request is
+also evaluated on its own to show which policies contribute to the decision.
+
+The response discloses the policies and path rules of any token, accessor or
+entity of the namespace and its children, so the endpoint requires sudo. A
+policy named "simulate" can't be managed at this path; use
+sys/policies/acl/simulate instead.
+		`,
+	},
+
 	"policy-rules": {
 		`The rules of the policy.`,
 		"",
@@ -5703,6 +6286,34 @@ Each entry is of the form "key=value".`,
 		"The semantic version of the plugin to use.",
 		"",
 	},
+	"plugin-catalog_memory-limit": {
+		`The memory the processes of the plugin may use together, in bytes or
+with a unit such as "512MiB". Requires plugin_cgroup_parent to be configured.`,
+		"",
+	},
+	"plugin-catalog_cpu-quota": {
+		`The CPU time the processes of the plugin may use together, as a
+percentage of one CPU. Requires plugin_cgroup_parent to be configured.`,
+		"",
+	},
+	"plugin-catalog_uid": {
+		"The user ID the plugin runs as. Requires gid to be set.",
+		"",
+	},
+	"plugin-catalog_gid": {
+		"The group ID the plugin runs as.",
+		"",
+	},
+	"plugin-catalog_restrict-env": {
+		`If true, the plugin does not inherit the environment of Vault and only
+gets the variables in env.`,
+		"",
+	},
+	"plugin-catalog_seccomp-profile": {
+		`The path, relative to the plugin directory, of a compiled seccomp BPF
+program to load before running the plugin.`,
+		"",
+	},
 	"leases": {
 		`View or list lease metadata.`,
 		`
@@ -5727,6 +6338,21 @@ This path responds to the following HTTP methods.
 		case that the plugin name is provided, all mounted paths that use that plugin
 		backend will be reloaded.`,
 	},
+	"plugin-install": {
+		"Install a plugin from a signed plugin bundle.",
+		`Install a plugin from a signed plugin bundle in the configured plugin
+bundle directory. The bundle is a gzipped tarball holding a manifest, its
+signature and the plugin binaries of each version for each platform. Once the
+signature of the manifest is verified against the configured trusted keys,
+the binary of each version for the platform of Vault is unpacked into the
+plugin directory and registered in the plugin catalog. The binaries are only
+unpacked on the node handling the request, so the bundle must be installed on
+every node unless the plugin directory is shared.`,
+	},
+	"plugin-install_bundle": {
+		`The file name of the plugin bundle in the plugin bundle directory.`,
+		"",
+	},
 	"plugin-backend-reload-plugin": {
 		`The name of the plugin to reload, as registered in the plugin catalog.`,
 		"",
@@ -5785,6 +6411,17 @@ This path responds to the following HTTP methods.
 		"Export the metrics aggregated for telemetry purpose.",
 		"",
 	},
+	"delayed-users": {
+		"Lists users and client addresses whose login attempts are being delayed.",
+		`
+This path lists, for each auth mount with progressive login delays enabled,
+the users and client addresses that have reached the lockout threshold and
+how long their next login attempt has to wait.
+		`,
+	},
+	"delayed-users_mount_accessor": {
+		"Only list delayed logins for the auth mount with this accessor.",
+	},
 	"in-flight-req": {
 		"reports in-flight requests",
 		`
@@ -5843,6 +6480,39 @@ This path responds to the following HTTP methods.
 		"List leases associated with this Vault cluster",
 		"Requires sudo capability. List leases associated with this Vault cluster",
 	},
+	"irrevocable-leases": {
+		"List the irrevocable leases with their revocation errors.",
+		`Requires sudo capability. Returns the leases that failed to be revoked
+automatically, sorted by lease ID, with the errors of the last revocation
+attempts and the revocation attempts scheduled by operators.`,
+	},
+	"irrevocable-leases-retry": {
+		"Attempt to revoke an irrevocable lease again.",
+		`Requires sudo capability. The revocation is attempted immediately and, if it
+fails and more attempts are requested, retried in the background with an
+exponential backoff. Failed attempts are added to the error history of the
+lease.`,
+	},
+	"irrevocable-leases-resolve": {
+		"Remove an irrevocable lease that was revoked externally.",
+		`Requires sudo capability. The lease is removed without revoking it in its
+secrets engine or auth method, so the credentials must have been removed by
+other means first.`,
+	},
+	"search-leases": {
+		"Search the leases by prefix, entity, role, issue time or remaining TTL.",
+		`Requires sudo capability. Returns the IDs of the leases of this namespace
+matching all the given filters, sorted by lease ID, along with their issue
+time, expire time, login role and entity. Results are paginated with the
+"after" and "limit" parameters.`,
+	},
+	"revoke-filtered": {
+		"Revoke the leases matching the given filters in the background.",
+		`Requires sudo capability. Writing to this path starts revoking the leases
+of this namespace matching all the given filters, with the same filters as
+the lease search. Reading this path returns the progress of the last
+filtered revocation. Only one filtered revocation can run at a time.`,
+	},
 	"version-history": {
 		"List historical version changes sorted by installation time in ascending order.",
 		`
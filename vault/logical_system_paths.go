@@ -778,6 +778,30 @@ func (b *SystemBackend) pluginsCatalogCRUDPath() *framework.Path {
 				Type:        framework.TypeString,
 				Description: strings.TrimSpace(sysHelp["plugin-catalog_version"][0]),
 			},
+			"memory_limit": {
+				Type:        framework.TypeString,
+				Description: strings.TrimSpace(sysHelp["plugin-catalog_memory-limit"][0]),
+			},
+			"cpu_quota": {
+				Type:        framework.TypeInt,
+				Description: strings.TrimSpace(sysHelp["plugin-catalog_cpu-quota"][0]),
+			},
+			"uid": {
+				Type:        framework.TypeInt,
+				Description: strings.TrimSpace(sysHelp["plugin-catalog_uid"][0]),
+			},
+			"gid": {
+				Type:        framework.TypeInt,
+				Description: strings.TrimSpace(sysHelp["plugin-catalog_gid"][0]),
+			},
+			"restrict_env": {
+				Type:        framework.TypeBool,
+				Description: strings.TrimSpace(sysHelp["plugin-catalog_restrict-env"][0]),
+			},
+			"seccomp_profile": {
+				Type:        framework.TypeString,
+				Description: strings.TrimSpace(sysHelp["plugin-catalog_seccomp-profile"][0]),
+			},
 		},
 
 		Operations: map[logical.Operation]framework.OperationHandler{
@@ -867,6 +891,29 @@ func (b *SystemBackend) pluginsReloadPath() *framework.Path {
 	}
 }
 
+func (b *SystemBackend) pluginsInstallPath() *framework.Path {
+	return &framework.Path{
+		Pattern: "plugins/install$",
+
+		Fields: map[string]*framework.FieldSchema{
+			"bundle": {
+				Type:        framework.TypeString,
+				Description: strings.TrimSpace(sysHelp["plugin-install_bundle"][0]),
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.handlePluginInstall,
+				Summary:  "Install and register the versions of a plugin from a signed plugin bundle.",
+			},
+		},
+
+		HelpSynopsis:    strings.TrimSpace(sysHelp["plugin-install"][0]),
+		HelpDescription: strings.TrimSpace(sysHelp["plugin-install"][1]),
+	}
+}
+
 func (b *SystemBackend) toolsPaths() []*framework.Path {
 	return []*framework.Path{
 		{
@@ -1390,7 +1437,177 @@ func (b *SystemBackend) leasePaths() []*framework.Path {
 			HelpSynopsis:    strings.TrimSpace(sysHelp["list-leases"][0]),
 			HelpDescription: strings.TrimSpace(sysHelp["list-leases"][1]),
 		},
+
+		{
+			Pattern: "leases/irrevocable$",
+			Fields: map[string]*framework.FieldSchema{
+				"include_child_namespaces": {
+					Type:        framework.TypeBool,
+					Default:     false,
+					Description: "Set true if you want leases for this namespace and its children.",
+				},
+				"after": {
+					Type:        framework.TypeString,
+					Description: "Only return leases with an ID sorting after this lease ID. Set to the next_after value of the previous response to get the next page of results.",
+				},
+				"limit": {
+					Type:        framework.TypeInt,
+					Default:     DefaultLeaseSearchLimit,
+					Description: "Maximum number of leases to return, up to 10000.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleIrrevocableLeaseList,
+					Summary:  "Returns the irrevocable leases with their revocation errors.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["irrevocable-leases"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["irrevocable-leases"][1]),
+		},
+
+		{
+			Pattern: "leases/irrevocable/retry$",
+			Fields: map[string]*framework.FieldSchema{
+				"lease_id": {
+					Type:        framework.TypeString,
+					Required:    true,
+					Description: strings.TrimSpace(sysHelp["lease_id"][0]),
+				},
+				"attempts": {
+					Type:        framework.TypeInt,
+					Default:     1,
+					Description: "Number of revocation attempts, including the immediate one.",
+				},
+				"backoff": {
+					Type:        framework.TypeDurationSecond,
+					Default:     "1m",
+					Description: "Delay before the second attempt, doubled after each failed attempt up to 24 hours.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleIrrevocableLeaseRetry,
+					Summary:  "Attempts to revoke an irrevocable lease again.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["irrevocable-leases-retry"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["irrevocable-leases-retry"][1]),
+		},
+
+		{
+			Pattern: "leases/irrevocable/resolve$",
+			Fields: map[string]*framework.FieldSchema{
+				"lease_id": {
+					Type:        framework.TypeString,
+					Required:    true,
+					Description: strings.TrimSpace(sysHelp["lease_id"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleIrrevocableLeaseResolve,
+					Summary:  "Removes an irrevocable lease that was revoked externally.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["irrevocable-leases-resolve"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["irrevocable-leases-resolve"][1]),
+		},
+
+		{
+			Pattern: "leases/search$",
+			Fields: leaseFilterFields(map[string]*framework.FieldSchema{
+				"after": {
+					Type:        framework.TypeString,
+					Description: "Only return leases with an ID sorting after this lease ID. Set to the next_after value of the previous response to get the next page of results.",
+				},
+				"limit": {
+					Type:        framework.TypeInt,
+					Default:     DefaultLeaseSearchLimit,
+					Description: "Maximum number of leases to return, up to 10000.",
+				},
+			}),
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleLeaseSearch,
+					Summary:  "Returns the leases matching the given filters.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["search-leases"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["search-leases"][1]),
+		},
+
+		{
+			Pattern: "leases/revoke-filtered$",
+			Fields:  leaseFilterFields(nil),
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleLeaseRevokeFilteredStatus,
+					Summary:  "Returns the progress of the last filtered revocation of leases.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleLeaseRevokeFiltered,
+					Summary:  "Revokes the leases matching the given filters in the background.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["revoke-filtered"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["revoke-filtered"][1]),
+		},
+	}
+}
+
+// leaseFilterFields returns the fields used to filter leases, along with the
+// given additional fields
+func leaseFilterFields(fields map[string]*framework.FieldSchema) map[string]*framework.FieldSchema {
+	if fields == nil {
+		fields = make(map[string]*framework.FieldSchema)
+	}
+
+	fields["prefix"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Only match leases with an ID starting with this prefix, such as the path of a mount.",
+	}
+	fields["entity_id"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Only match leases issued to this entity: the leases of its tokens and of the secrets requested with them.",
+	}
+	fields["role"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Only match leases created by a login with this role. Only auth leases record the role of the login, so the leases of secrets never match.",
+	}
+	fields["issued_after"] = &framework.FieldSchema{
+		Type:        framework.TypeTime,
+		Description: "Only match leases issued after this time, in RFC3339 format or as seconds since the Unix epoch.",
 	}
+	fields["issued_before"] = &framework.FieldSchema{
+		Type:        framework.TypeTime,
+		Description: "Only match leases issued before this time, in RFC3339 format or as seconds since the Unix epoch.",
+	}
+	fields["ttl_min"] = &framework.FieldSchema{
+		Type:        framework.TypeDurationSecond,
+		Description: "Only match leases with at least this remaining TTL.",
+	}
+	fields["ttl_max"] = &framework.FieldSchema{
+		Type:        framework.TypeDurationSecond,
+		Description: "Only match leases with at most this remaining TTL. Non-expiring leases never match.",
+	}
+	fields["include_child_namespaces"] = &framework.FieldSchema{
+		Type:        framework.TypeBool,
+		Default:     false,
+		Description: "Set true to also match leases of the child namespaces.",
+	}
+
+	return fields
 }
 
 func (b *SystemBackend) remountPaths() []*framework.Path {
@@ -1510,6 +1727,28 @@ func (b *SystemBackend) hostInfoPath() *framework.Path {
 	}
 }
 
+func (b *SystemBackend) delayedUsersPath() *framework.Path {
+	return &framework.Path{
+		Pattern: "delayed-users",
+		Fields: map[string]*framework.FieldSchema{
+			"mount_accessor": {
+				Type:        framework.TypeString,
+				Description: strings.TrimSpace(sysHelp["delayed-users_mount_accessor"][0]),
+				Query:       true,
+			},
+		},
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback:    b.handleDelayedUsersRead,
+				Summary:     strings.TrimSpace(sysHelp["delayed-users"][0]),
+				Description: strings.TrimSpace(sysHelp["delayed-users"][1]),
+			},
+		},
+		HelpSynopsis:    strings.TrimSpace(sysHelp["delayed-users"][0]),
+		HelpDescription: strings.TrimSpace(sysHelp["delayed-users"][1]),
+	}
+}
+
 func (b *SystemBackend) authPaths() []*framework.Path {
 	return []*framework.Path{
 		{
@@ -1675,6 +1914,61 @@ func (b *SystemBackend) policyPaths() []*framework.Path {
 			HelpDescription: strings.TrimSpace(sysHelp["policy-list"][1]),
 		},
 
+		{
+			Pattern: "policy/simulate$",
+
+			Fields: map[string]*framework.FieldSchema{
+				"token": {
+					Type:        framework.TypeString,
+					Description: "Token whose policies should be evaluated.",
+				},
+				"accessor": {
+					Type:        framework.TypeString,
+					Description: "Accessor of the token whose policies should be evaluated.",
+				},
+				"entity_id": {
+					Type:        framework.TypeString,
+					Description: "ID of the entity whose identity policies should be evaluated.",
+				},
+				"policies": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Names of the policies in the request namespace that should be evaluated.",
+				},
+				"path": {
+					Type:        framework.TypeString,
+					Description: "Path of the simulated request.",
+				},
+				"operation": {
+					Type:          framework.TypeString,
+					Default:       string(logical.ReadOperation),
+					Description:   "Operation of the simulated request.",
+					AllowedValues: []interface{}{"create", "read", "update", "patch", "delete", "list"},
+				},
+				"parameters": {
+					Type:        framework.TypeMap,
+					Description: "Parameters of the simulated request.",
+				},
+				"wrap_ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Response wrapping TTL of the simulated request.",
+				},
+				"remote_address": {
+					Type:        framework.TypeString,
+					Description: "Client address of the simulated request.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handlePolicySimulate,
+					Summary:  "Explain whether a request would be allowed by a set of policies.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["policy-simulate"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["policy-simulate"][1]),
+		},
+
 		{
 			Pattern: "policy/(?P<name>.+)",
 
@@ -88,6 +88,13 @@ The 'rate' must be positive.`,
 					Description: `If set, when a client reaches a rate limit threshold, the client will be prohibited
 from any further requests until after the 'block_interval' has elapsed.`,
 				},
+				"client_key": {
+					Type: framework.TypeString,
+					Description: `The request property used to identify a client, each client being rate limited
+separately. One of 'ip_address', 'entity_id', 'role' or 'token_accessor'
+(default 'ip_address'). Requests for which the property cannot be determined are
+identified by client IP address.`,
+				},
 			},
 			Operations: map[logical.Operation]framework.OperationHandler{
 				logical.UpdateOperation: &framework.PathOperation{
@@ -103,6 +110,140 @@ from any further requests until after the 'block_interval' has elapsed.`,
 			HelpSynopsis:    strings.TrimSpace(quotasHelp["rate-limit"][0]),
 			HelpDescription: strings.TrimSpace(quotasHelp["rate-limit"][1]),
 		},
+		{
+			Pattern: "quotas/rate-limit/" + framework.GenericNameRegex("name") + "/usage$",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the quota rule.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleRateLimitQuotasUsageRead(),
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(quotasHelp["rate-limit-usage"][0]),
+			HelpDescription: strings.TrimSpace(quotasHelp["rate-limit-usage"][1]),
+		},
+		{
+			Pattern: "quotas/concurrency/?$",
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleConcurrencyQuotasList(),
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(quotasHelp["concurrency-list"][0]),
+			HelpDescription: strings.TrimSpace(quotasHelp["concurrency-list"][1]),
+		},
+		{
+			Pattern: "quotas/concurrency/" + framework.GenericNameRegex("name"),
+			Fields: map[string]*framework.FieldSchema{
+				"type": {
+					Type:        framework.TypeString,
+					Description: "Type of the quota rule.",
+				},
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the quota rule.",
+				},
+				"path": {
+					Type: framework.TypeString,
+					Description: `Path of the mount or namespace to apply the quota. A blank path configures a
+global quota. For example namespace1/ adds a quota to a full namespace,
+namespace1/auth/userpass adds a quota to userpass in namespace1.`,
+				},
+				"role": {
+					Type: framework.TypeString,
+					Description: `Login role to apply this quota to. Note that when set, path must be configured
+to a valid auth method with a concept of roles.`,
+				},
+				"max_requests": {
+					Type: framework.TypeInt,
+					Description: `The maximum number of requests allowed to be processed at the same time by the
+quota rule. The 'max_requests' must be positive.`,
+				},
+				"per_client": {
+					Type: framework.TypeBool,
+					Description: `If set, 'max_requests' is applied to each unique client IP address instead of
+to all the requests matching the quota rule.`,
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleConcurrencyQuotasUpdate(),
+				},
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleConcurrencyQuotasRead(),
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleQuotasDelete(quotas.TypeConcurrency),
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(quotasHelp["concurrency"][0]),
+			HelpDescription: strings.TrimSpace(quotasHelp["concurrency"][1]),
+		},
+		{
+			Pattern: "quotas/request-size/?$",
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleRequestSizeQuotasList(),
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(quotasHelp["request-size-list"][0]),
+			HelpDescription: strings.TrimSpace(quotasHelp["request-size-list"][1]),
+		},
+		{
+			Pattern: "quotas/request-size/" + framework.GenericNameRegex("name"),
+			Fields: map[string]*framework.FieldSchema{
+				"type": {
+					Type:        framework.TypeString,
+					Description: "Type of the quota rule.",
+				},
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the quota rule.",
+				},
+				"path": {
+					Type: framework.TypeString,
+					Description: `Path of the mount or namespace to apply the quota. A blank path configures a
+global quota. For example namespace1/ adds a quota to a full namespace,
+namespace1/auth/userpass adds a quota to userpass in namespace1.`,
+				},
+				"role": {
+					Type: framework.TypeString,
+					Description: `Login role to apply this quota to. Note that when set, path must be configured
+to a valid auth method with a concept of roles.`,
+				},
+				"max_request_size": {
+					Type: framework.TypeInt,
+					Description: `The maximum size, in bytes, of a single request body to be allowed by the quota
+rule. If zero, request bodies of any size are allowed.`,
+				},
+				"bandwidth": {
+					Type: framework.TypeInt,
+					Description: `The maximum number of request body bytes each client IP address is allowed to send
+in a given interval. If zero, bandwidth is not limited.`,
+				},
+				"interval": {
+					Type:        framework.TypeDurationSecond,
+					Description: "The duration to enforce bandwidth limiting for (default '1s').",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleRequestSizeQuotasUpdate(),
+				},
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleRequestSizeQuotasRead(),
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleQuotasDelete(quotas.TypeRequestSize),
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(quotasHelp["request-size"][0]),
+			HelpDescription: strings.TrimSpace(quotasHelp["request-size"][1]),
+		},
 	}
 }
 
@@ -185,87 +326,43 @@ func (b *SystemBackend) handleRateLimitQuotasUpdate() framework.OperationFunc {
 			return logical.ErrorResponse("'block' is invalid"), nil
 		}
 
-		mountPath := sanitizePath(d.Get("path").(string))
-		ns := b.Core.namespaceByPath(mountPath)
-		if ns.ID != namespace.RootNamespaceID {
-			mountPath = strings.TrimPrefix(mountPath, ns.Path)
-		}
-
-		var pathSuffix string
-		if mountPath != "" {
-			me := b.Core.router.MatchingMountEntry(namespace.ContextWithNamespace(ctx, ns), mountPath)
-			if me == nil {
-				return logical.ErrorResponse("invalid mount path %q", mountPath), nil
-			}
-
-			mountAPIPath := me.APIPathNoNamespace()
-			pathSuffix = strings.TrimSuffix(strings.TrimPrefix(mountPath, mountAPIPath), "/")
-			mountPath = mountAPIPath
-		}
-
-		role := d.Get("role").(string)
-		// If this is a quota with a role, ensure the backend supports role resolution
-		if role != "" {
-			if pathSuffix != "" {
-				return logical.ErrorResponse("Quotas cannot contain both a path suffix and a role. If a role is provided, path must be a valid auth mount with a concept of roles"), nil
-			}
-			authBackend := b.Core.router.MatchingBackend(namespace.ContextWithNamespace(ctx, ns), mountPath)
-			if authBackend == nil || authBackend.Type() != logical.TypeCredential {
-				return logical.ErrorResponse("Mount path %q is not a valid auth method and therefore unsuitable for use with role-based quotas", mountPath), nil
-			}
-			// We will always error as we aren't supplying real data, but we're looking for "unsupported operation" in particular
-			_, err := authBackend.HandleRequest(ctx, &logical.Request{
-				Path:      "login",
-				Operation: logical.ResolveRoleOperation,
-			})
-			if err != nil && (err == logical.ErrUnsupportedOperation || err == logical.ErrUnsupportedPath) {
-				return logical.ErrorResponse("Mount path %q does not support use with role-based quotas", mountPath), nil
-			}
-		}
-
-		// Disallow creation of new quota that has properties similar to an
-		// existing quota.
-		quotaByFactors, err := b.Core.quotaManager.QuotaByFactors(ctx, qType, ns.Path, mountPath, pathSuffix, role)
+		_, clientKeyOk := d.GetOk("client_key")
+		clientKey, err := quotas.ParseClientKeyType(d.Get("client_key").(string))
 		if err != nil {
-			return nil, err
-		}
-		if quotaByFactors != nil && quotaByFactors.QuotaName() != name {
-			return logical.ErrorResponse("quota rule with similar properties exists under the name %q", quotaByFactors.QuotaName()), nil
+			return logical.ErrorResponse(err.Error()), nil
 		}
 
-		// If a quota already exists, fetch and update it.
-		quota, err := b.Core.quotaManager.QuotaByName(qType, name)
+		factors, quota, errResp, err := b.quotaUpdateFactors(ctx, qType, name, d)
 		if err != nil {
 			return nil, err
 		}
+		if errResp != nil {
+			return errResp, nil
+		}
 
 		switch {
 		case quota == nil:
-			quota = quotas.NewRateLimitQuota(name, ns.Path, mountPath, pathSuffix, role, rate, interval, blockInterval)
+			rlq := quotas.NewRateLimitQuota(name, factors.ns.Path, factors.mountPath, factors.pathSuffix, factors.role, rate, interval, blockInterval)
+			rlq.ClientKey = clientKey
+			quota = rlq
 		default:
 			// Re-inserting the already indexed object in memdb might cause problems.
 			// So, clone the object. See https://github.com/hashicorp/go-memdb/issues/76.
 			clonedQuota := quota.Clone()
 			rlq := clonedQuota.(*quotas.RateLimitQuota)
-			rlq.NamespacePath = ns.Path
-			rlq.MountPath = mountPath
-			rlq.PathSuffix = pathSuffix
+			rlq.NamespacePath = factors.ns.Path
+			rlq.MountPath = factors.mountPath
+			rlq.PathSuffix = factors.pathSuffix
 			rlq.Rate = rate
 			rlq.Interval = interval
 			rlq.BlockInterval = blockInterval
+			if clientKeyOk {
+				rlq.ClientKey = clientKey
+			}
 			quota = rlq
 		}
 
-		entry, err := logical.StorageEntryJSON(quotas.QuotaStoragePath(qType, name), quota)
-		if err != nil {
-			return nil, err
-		}
-
-		if err := req.Storage.Put(ctx, entry); err != nil {
-			return nil, err
-		}
-
-		if err := b.Core.quotaManager.SetQuota(ctx, qType, quota, false); err != nil {
+		if err := b.storeQuota(ctx, req, qType, name, quota); err != nil {
 			return nil, err
 		}
 
@@ -301,6 +398,7 @@ func (b *SystemBackend) handleRateLimitQuotasRead() framework.OperationFunc {
 			"rate":           rlq.Rate,
 			"interval":       int(rlq.Interval.Seconds()),
 			"block_interval": int(rlq.BlockInterval.Seconds()),
+			"client_key":     string(rlq.ClientKey),
 		}
 
 		return &logical.Response{
@@ -309,6 +407,50 @@ func (b *SystemBackend) handleRateLimitQuotasRead() framework.OperationFunc {
 	}
 }
 
+func (b *SystemBackend) handleRateLimitQuotasUsageRead() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		name := d.Get("name").(string)
+		qType := quotas.TypeRateLimit.String()
+
+		quota, err := b.Core.quotaManager.QuotaByName(qType, name)
+		if err != nil {
+			return nil, err
+		}
+		if quota == nil {
+			return nil, nil
+		}
+
+		rlq := quota.(*quotas.RateLimitQuota)
+		usage, err := rlq.ClientUsage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		clients := make(map[string]interface{}, len(usage))
+		for _, cu := range usage {
+			clientData := map[string]interface{}{
+				"limit":     cu.Limit,
+				"remaining": cu.Remaining,
+				"allowed":   cu.Allowed,
+				"rejected":  cu.Rejected,
+				"last_seen": cu.LastSeen.Format(time.RFC3339),
+			}
+			if !cu.BlockedUntil.IsZero() {
+				clientData["blocked_until"] = cu.BlockedUntil.Format(time.RFC3339)
+			}
+			clients[cu.Key] = clientData
+		}
+
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"name":       rlq.Name,
+				"client_key": string(rlq.ClientKey),
+				"clients":    clients,
+			},
+		}, nil
+	}
+}
+
 func (b *SystemBackend) handleRateLimitQuotasDelete() framework.OperationFunc {
 	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 		name := d.Get("name").(string)
@@ -326,6 +468,310 @@ func (b *SystemBackend) handleRateLimitQuotasDelete() framework.OperationFunc {
 	}
 }
 
+func (b *SystemBackend) handleConcurrencyQuotasList() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		names, err := b.Core.quotaManager.QuotaNames(quotas.TypeConcurrency)
+		if err != nil {
+			return nil, err
+		}
+
+		return logical.ListResponse(names), nil
+	}
+}
+
+func (b *SystemBackend) handleConcurrencyQuotasUpdate() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		name := d.Get("name").(string)
+
+		qType := quotas.TypeConcurrency.String()
+		maxRequests := d.Get("max_requests").(int)
+		if maxRequests <= 0 {
+			return logical.ErrorResponse("'max_requests' is invalid"), nil
+		}
+		perClient := d.Get("per_client").(bool)
+
+		factors, quota, errResp, err := b.quotaUpdateFactors(ctx, qType, name, d)
+		if err != nil {
+			return nil, err
+		}
+		if errResp != nil {
+			return errResp, nil
+		}
+
+		switch {
+		case quota == nil:
+			quota = quotas.NewConcurrencyQuota(name, factors.ns.Path, factors.mountPath, factors.pathSuffix, factors.role, maxRequests, perClient)
+		default:
+			// Re-inserting the already indexed object in memdb might cause problems.
+			// So, clone the object. See https://github.com/hashicorp/go-memdb/issues/76.
+			cq := quota.Clone().(*quotas.ConcurrencyQuota)
+			cq.NamespacePath = factors.ns.Path
+			cq.MountPath = factors.mountPath
+			cq.PathSuffix = factors.pathSuffix
+			cq.Role = factors.role
+			cq.MaxRequests = maxRequests
+			cq.PerClient = perClient
+			quota = cq
+		}
+
+		if err := b.storeQuota(ctx, req, qType, name, quota); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+}
+
+func (b *SystemBackend) handleConcurrencyQuotasRead() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		name := d.Get("name").(string)
+		qType := quotas.TypeConcurrency.String()
+
+		quota, err := b.Core.quotaManager.QuotaByName(qType, name)
+		if err != nil {
+			return nil, err
+		}
+		if quota == nil {
+			return nil, nil
+		}
+
+		cq := quota.(*quotas.ConcurrencyQuota)
+
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"type":         qType,
+				"name":         cq.Name,
+				"path":         quotaPath(cq.NamespacePath, cq.MountPath, cq.PathSuffix),
+				"role":         cq.Role,
+				"max_requests": cq.MaxRequests,
+				"per_client":   cq.PerClient,
+			},
+		}, nil
+	}
+}
+
+func (b *SystemBackend) handleRequestSizeQuotasList() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		names, err := b.Core.quotaManager.QuotaNames(quotas.TypeRequestSize)
+		if err != nil {
+			return nil, err
+		}
+
+		return logical.ListResponse(names), nil
+	}
+}
+
+func (b *SystemBackend) handleRequestSizeQuotasUpdate() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		name := d.Get("name").(string)
+
+		qType := quotas.TypeRequestSize.String()
+		maxRequestSize := int64(d.Get("max_request_size").(int))
+		if maxRequestSize < 0 {
+			return logical.ErrorResponse("'max_request_size' is invalid"), nil
+		}
+
+		bandwidth := int64(d.Get("bandwidth").(int))
+		if bandwidth < 0 {
+			return logical.ErrorResponse("'bandwidth' is invalid"), nil
+		}
+
+		if maxRequestSize == 0 && bandwidth == 0 {
+			return logical.ErrorResponse("at least one of 'max_request_size' or 'bandwidth' must be set"), nil
+		}
+
+		interval := time.Second * time.Duration(d.Get("interval").(int))
+		if interval == 0 {
+			interval = time.Second
+		}
+
+		factors, quota, errResp, err := b.quotaUpdateFactors(ctx, qType, name, d)
+		if err != nil {
+			return nil, err
+		}
+		if errResp != nil {
+			return errResp, nil
+		}
+
+		switch {
+		case quota == nil:
+			quota = quotas.NewRequestSizeQuota(name, factors.ns.Path, factors.mountPath, factors.pathSuffix, factors.role, maxRequestSize, bandwidth, interval)
+		default:
+			// Re-inserting the already indexed object in memdb might cause problems.
+			// So, clone the object. See https://github.com/hashicorp/go-memdb/issues/76.
+			rsq := quota.Clone().(*quotas.RequestSizeQuota)
+			rsq.NamespacePath = factors.ns.Path
+			rsq.MountPath = factors.mountPath
+			rsq.PathSuffix = factors.pathSuffix
+			rsq.Role = factors.role
+			rsq.MaxRequestSize = maxRequestSize
+			rsq.Bandwidth = bandwidth
+			rsq.Interval = interval
+			quota = rsq
+		}
+
+		if err := b.storeQuota(ctx, req, qType, name, quota); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+}
+
+func (b *SystemBackend) handleRequestSizeQuotasRead() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		name := d.Get("name").(string)
+		qType := quotas.TypeRequestSize.String()
+
+		quota, err := b.Core.quotaManager.QuotaByName(qType, name)
+		if err != nil {
+			return nil, err
+		}
+		if quota == nil {
+			return nil, nil
+		}
+
+		rsq := quota.(*quotas.RequestSizeQuota)
+
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"type":             qType,
+				"name":             rsq.Name,
+				"path":             quotaPath(rsq.NamespacePath, rsq.MountPath, rsq.PathSuffix),
+				"role":             rsq.Role,
+				"max_request_size": rsq.MaxRequestSize,
+				"bandwidth":        rsq.Bandwidth,
+				"interval":         int(rsq.Interval.Seconds()),
+			},
+		}, nil
+	}
+}
+
+func (b *SystemBackend) handleQuotasDelete(qType quotas.Type) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		name := d.Get("name").(string)
+
+		if err := req.Storage.Delete(ctx, quotas.QuotaStoragePath(qType.String(), name)); err != nil {
+			return nil, err
+		}
+
+		if err := b.Core.quotaManager.DeleteQuota(ctx, qType.String(), name); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+}
+
+// quotaFactors are the namespace, mount path, path suffix and role that a
+// quota rule applies to
+type quotaFactors struct {
+	ns         *namespace.Namespace
+	mountPath  string
+	pathSuffix string
+	role       string
+}
+
+// quotaUpdateFactors validates the path and role of a quota rule written
+// under the given name, and returns them along with the existing rule of that
+// name, if any. A response is returned if the request is invalid.
+func (b *SystemBackend) quotaUpdateFactors(ctx context.Context, qType, name string, d *framework.FieldData) (*quotaFactors, quotas.Quota, *logical.Response, error) {
+	role := d.Get("role").(string)
+	ns, mountPath, pathSuffix, errResp := b.quotaPathFactors(ctx, d.Get("path").(string), role)
+	if errResp != nil {
+		return nil, nil, errResp, nil
+	}
+
+	// Disallow creation of new quota that has properties similar to an
+	// existing quota.
+	quotaByFactors, err := b.Core.quotaManager.QuotaByFactors(ctx, qType, ns.Path, mountPath, pathSuffix, role)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if quotaByFactors != nil && quotaByFactors.QuotaName() != name {
+		return nil, nil, logical.ErrorResponse("quota rule with similar properties exists under the name %q", quotaByFactors.QuotaName()), nil
+	}
+
+	// If a quota already exists, fetch it so that it gets updated.
+	quota, err := b.Core.quotaManager.QuotaByName(qType, name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return &quotaFactors{
+		ns:         ns,
+		mountPath:  mountPath,
+		pathSuffix: pathSuffix,
+		role:       role,
+	}, quota, nil, nil
+}
+
+// storeQuota persists a quota rule and applies it
+func (b *SystemBackend) storeQuota(ctx context.Context, req *logical.Request, qType, name string, quota quotas.Quota) error {
+	entry, err := logical.StorageEntryJSON(quotas.QuotaStoragePath(qType, name), quota)
+	if err != nil {
+		return err
+	}
+
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return err
+	}
+
+	return b.Core.quotaManager.SetQuota(ctx, qType, quota, false)
+}
+
+// quotaPathFactors resolves the namespace, mount path and path suffix that a
+// quota rule applies to from the user supplied path. If a role is given, the
+// path must point to an auth method that supports role resolution.
+func (b *SystemBackend) quotaPathFactors(ctx context.Context, rawPath, role string) (*namespace.Namespace, string, string, *logical.Response) {
+	mountPath := sanitizePath(rawPath)
+	ns := b.Core.namespaceByPath(mountPath)
+	if ns.ID != namespace.RootNamespaceID {
+		mountPath = strings.TrimPrefix(mountPath, ns.Path)
+	}
+
+	var pathSuffix string
+	if mountPath != "" {
+		me := b.Core.router.MatchingMountEntry(namespace.ContextWithNamespace(ctx, ns), mountPath)
+		if me == nil {
+			return nil, "", "", logical.ErrorResponse("invalid mount path %q", mountPath)
+		}
+
+		mountAPIPath := me.APIPathNoNamespace()
+		pathSuffix = strings.TrimSuffix(strings.TrimPrefix(mountPath, mountAPIPath), "/")
+		mountPath = mountAPIPath
+	}
+
+	// If this is a quota with a role, ensure the backend supports role resolution
+	if role != "" {
+		if pathSuffix != "" {
+			return nil, "", "", logical.ErrorResponse("Quotas cannot contain both a path suffix and a role. If a role is provided, path must be a valid auth mount with a concept of roles")
+		}
+		authBackend := b.Core.router.MatchingBackend(namespace.ContextWithNamespace(ctx, ns), mountPath)
+		if authBackend == nil || authBackend.Type() != logical.TypeCredential {
+			return nil, "", "", logical.ErrorResponse("Mount path %q is not a valid auth method and therefore unsuitable for use with role-based quotas", mountPath)
+		}
+		// We will always error as we aren't supplying real data, but we're looking for "unsupported operation" in particular
+		_, err := authBackend.HandleRequest(ctx, &logical.Request{
+			Path:      "login",
+			Operation: logical.ResolveRoleOperation,
+		})
+		if err != nil && (err == logical.ErrUnsupportedOperation || err == logical.ErrUnsupportedPath) {
+			return nil, "", "", logical.ErrorResponse("Mount path %q does not support use with role-based quotas", mountPath)
+		}
+	}
+
+	return ns, mountPath, pathSuffix, nil
+}
+
+// quotaPath returns the user facing path of a quota rule
+func quotaPath(nsPath, mountPath, pathSuffix string) string {
+	if nsPath == "root" {
+		nsPath = ""
+	}
+	return nsPath + mountPath + pathSuffix
+}
+
 var quotasHelp = map[string][2]string{
 	"quotas-config": {
 		"Create, update and read the quota configuration.",
@@ -336,11 +782,42 @@ var quotasHelp = map[string][2]string{
 mount.`,
 		`A rate limit quota will enforce API rate limiting in a specified interval. A
 rate limit quota can be created at the root level or defined on a namespace or
-mount by specifying a 'path'. The rate limiter is applied to each unique client
-IP address.`,
+mount by specifying a 'path'. The rate limiter is applied to each unique client,
+identified by IP address, entity ID, login role or token accessor depending on
+'client_key'.`,
+	},
+	"rate-limit-usage": {
+		"Read the per-client usage of a rate limit quota.",
+		`Returns the current usage of the rate limit bucket of every client that made a
+request recently. Clients that have not made a request within the stale age of
+the quota are evicted and no longer reported.`,
 	},
 	"rate-limit-list": {
 		"Lists the names of all the rate limit quotas.",
 		"This list contains quota definitions from all the namespaces.",
 	},
+	"concurrency": {
+		`Get, create or update concurrency resource quota for an optional namespace or
+mount.`,
+		`A concurrency quota will limit the number of requests being processed at the
+same time. A concurrency quota can be created at the root level or defined on a
+namespace or mount by specifying a 'path'. The limit is applied to all matching
+requests, or to each unique client IP address if 'per_client' is set.`,
+	},
+	"concurrency-list": {
+		"Lists the names of all the concurrency quotas.",
+		"This list contains quota definitions from all the namespaces.",
+	},
+	"request-size": {
+		`Get, create or update request size resource quota for an optional namespace or
+mount.`,
+		`A request size quota will reject requests whose body exceeds 'max_request_size'
+bytes, and limit the number of request body bytes each unique client IP address
+can send in a specified interval. A request size quota can be created at the
+root level or defined on a namespace or mount by specifying a 'path'.`,
+	},
+	"request-size-list": {
+		"Lists the names of all the request size quotas.",
+		"This list contains quota definitions from all the namespaces.",
+	},
 }
@@ -149,6 +149,88 @@ func (b *SystemBackend) raftStoragePaths() []*framework.Path {
 			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-snapshot-force"][0]),
 			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-snapshot-force"][1]),
 		},
+		{
+			Pattern: "storage/raft/snapshot-auto/config/?$",
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftSnapshotAutoConfigList(),
+					Summary:  "Lists the automated raft snapshot configurations.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-snapshot-auto-config-list"][0]),
+			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-snapshot-auto-config-list"][1]),
+		},
+		{
+			Pattern: "storage/raft/snapshot-auto/config/" + framework.GenericNameRegex("name"),
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the automated snapshot configuration.",
+				},
+				"interval": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Time between snapshots.",
+				},
+				"retain": {
+					Type:        framework.TypeInt,
+					Description: "Number of snapshots to keep. Older snapshots are removed after a snapshot is taken. Defaults to 1.",
+				},
+				"storage_type": {
+					Type:          framework.TypeString,
+					Description:   "Type of storage the snapshots are written to.",
+					AllowedValues: []interface{}{raftAutoSnapshotStorageLocal},
+				},
+				"path_prefix": {
+					Type:        framework.TypeString,
+					Description: "Absolute path of the local directory the snapshots are written to.",
+				},
+				"file_prefix": {
+					Type:        framework.TypeString,
+					Description: "Prefix of the snapshot file names. Defaults to \"vault-snapshot\".",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftSnapshotAutoConfigRead(),
+					Summary:  "Returns an automated raft snapshot configuration.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftSnapshotAutoConfigUpdate(),
+					Summary:  "Creates or updates an automated raft snapshot configuration.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftSnapshotAutoConfigDelete(),
+					Summary:  "Deletes an automated raft snapshot configuration.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-snapshot-auto-config"][0]),
+			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-snapshot-auto-config"][1]),
+		},
+		{
+			Pattern: "storage/raft/snapshot-auto/status/" + framework.GenericNameRegex("name"),
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the automated snapshot configuration.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftSnapshotAutoStatusRead(),
+					Summary:  "Returns the status of an automated raft snapshot configuration.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-snapshot-auto-status"][0]),
+			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-snapshot-auto-status"][1]),
+		},
 		{
 			Pattern: "storage/raft/autopilot/state",
 			Operations: map[logical.Operation]framework.OperationHandler{
@@ -409,6 +491,158 @@ func (b *SystemBackend) handleStorageRaftSnapshotRead() framework.OperationFunc
 	}
 }
 
+func (b *SystemBackend) raftAutoSnapshotManager() (*raftAutoSnapshotManager, *logical.Response, error) {
+	if _, ok := b.Core.underlyingPhysical.(*raft.RaftBackend); !ok {
+		return nil, logical.ErrorResponse("raft storage is not in use"), logical.ErrInvalidRequest
+	}
+	if b.Core.raftAutoSnapshots == nil {
+		return nil, logical.ErrorResponse("automated snapshots are not running on this node"), logical.ErrInvalidRequest
+	}
+	return b.Core.raftAutoSnapshots, nil, nil
+}
+
+func (b *SystemBackend) handleStorageRaftSnapshotAutoConfigList() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		manager, resp, err := b.raftAutoSnapshotManager()
+		if manager == nil {
+			return resp, err
+		}
+
+		names, err := manager.listConfigs(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return logical.ListResponse(names), nil
+	}
+}
+
+func (b *SystemBackend) handleStorageRaftSnapshotAutoConfigRead() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		manager, resp, err := b.raftAutoSnapshotManager()
+		if manager == nil {
+			return resp, err
+		}
+
+		config, err := manager.getConfig(ctx, d.Get("name").(string))
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			return nil, nil
+		}
+
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"interval":     int64(config.Interval.Seconds()),
+				"retain":       config.Retain,
+				"storage_type": config.StorageType,
+				"path_prefix":  config.PathPrefix,
+				"file_prefix":  config.FilePrefix,
+			},
+		}, nil
+	}
+}
+
+func (b *SystemBackend) handleStorageRaftSnapshotAutoConfigUpdate() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		manager, resp, err := b.raftAutoSnapshotManager()
+		if manager == nil {
+			return resp, err
+		}
+
+		name := d.Get("name").(string)
+		config, err := manager.getConfig(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			config = &raftAutoSnapshotConfig{
+				Name: name,
+			}
+		}
+
+		if interval, ok := d.GetOk("interval"); ok {
+			config.Interval = time.Duration(interval.(int)) * time.Second
+		}
+		if retain, ok := d.GetOk("retain"); ok {
+			config.Retain = retain.(int)
+		}
+		if storageType, ok := d.GetOk("storage_type"); ok {
+			config.StorageType = storageType.(string)
+		}
+		if pathPrefix, ok := d.GetOk("path_prefix"); ok {
+			config.PathPrefix = pathPrefix.(string)
+		}
+		if filePrefix, ok := d.GetOk("file_prefix"); ok {
+			config.FilePrefix = filePrefix.(string)
+		}
+
+		if err := config.validate(); err != nil {
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		}
+
+		if err := manager.putConfig(ctx, config); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+}
+
+func (b *SystemBackend) handleStorageRaftSnapshotAutoConfigDelete() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		manager, resp, err := b.raftAutoSnapshotManager()
+		if manager == nil {
+			return resp, err
+		}
+
+		if err := manager.deleteConfig(ctx, d.Get("name").(string)); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+}
+
+func (b *SystemBackend) handleStorageRaftSnapshotAutoStatusRead() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		manager, resp, err := b.raftAutoSnapshotManager()
+		if manager == nil {
+			return resp, err
+		}
+
+		status, err := manager.getStatus(ctx, d.Get("name").(string))
+		if err != nil {
+			return nil, err
+		}
+		if status == nil {
+			return nil, nil
+		}
+
+		formatTime := func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.UTC().Format(time.RFC3339)
+		}
+
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"consecutive_errors":  status.ConsecutiveErrors,
+				"last_snapshot_start": formatTime(status.LastSnapshotStart),
+				"last_snapshot_end":   formatTime(status.LastSnapshotEnd),
+				"last_snapshot_error": status.LastSnapshotError,
+				"last_success_time":   formatTime(status.LastSuccessTime),
+				"last_success_path":   status.LastSuccessPath,
+				"last_failure_time":   formatTime(status.LastFailureTime),
+				"next_snapshot_start": formatTime(status.NextSnapshotStart),
+				"retained_snapshots":  status.RetainedSnapshots,
+			},
+		}, nil
+	}
+}
+
 func (b *SystemBackend) handleStorageRaftAutopilotState() framework.OperationFunc {
 	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 		raftBackend := b.Core.getRaftBackend()
@@ -689,6 +923,20 @@ var sysRaftHelp = map[string][2]string{
 		"Force restore a raft cluster snapshot",
 		"",
 	},
+	"raft-snapshot-auto-config-list": {
+		"Lists the automated raft snapshot configurations.",
+		"",
+	},
+	"raft-snapshot-auto-config": {
+		"Manages automated raft snapshot configurations.",
+		`Each configuration takes a snapshot of the raft cluster every interval on
+		the active node, writes it to a local directory and removes the oldest
+		snapshots exceeding the retention.`,
+	},
+	"raft-snapshot-auto-status": {
+		"Returns the status of an automated raft snapshot configuration.",
+		"",
+	},
 	"raft-autopilot-state": {
 		"Returns the state of the raft cluster under integrated storage as seen by autopilot.",
 		"",
@@ -0,0 +1,154 @@
+package vault
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// storageMigrationPaths returns paths for use when an online storage migration
+// destination is configured.
+func (b *SystemBackend) storageMigrationPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "storage/migration$",
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleStorageMigrationStatusRead(),
+					Summary:  "Returns the status of the online storage migration.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysStorageMigrationHelp["storage-migration"][0]),
+			HelpDescription: strings.TrimSpace(sysStorageMigrationHelp["storage-migration"][1]),
+		},
+		{
+			Pattern: "storage/migration/copy$",
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleStorageMigrationStart(func(m *storageMigrationManager) error { return m.startCopy() }),
+					Summary:  "Starts copying the source storage to the destination storage.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysStorageMigrationHelp["storage-migration-copy"][0]),
+			HelpDescription: strings.TrimSpace(sysStorageMigrationHelp["storage-migration-copy"][1]),
+		},
+		{
+			Pattern: "storage/migration/verify$",
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleStorageMigrationStart(func(m *storageMigrationManager) error { return m.startVerify() }),
+					Summary:  "Starts comparing the source storage with the destination storage.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysStorageMigrationHelp["storage-migration-verify"][0]),
+			HelpDescription: strings.TrimSpace(sysStorageMigrationHelp["storage-migration-verify"][1]),
+		},
+		{
+			Pattern: "storage/migration/cutover$",
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleStorageMigrationCutover(),
+					Summary:  "Stops the writes to the source storage to complete the migration.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysStorageMigrationHelp["storage-migration-cutover"][0]),
+			HelpDescription: strings.TrimSpace(sysStorageMigrationHelp["storage-migration-cutover"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleStorageMigrationStatusRead() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		return storageMigrationStatusResponse(b.Core.storageMigration.getStatus()), nil
+	}
+}
+
+func (b *SystemBackend) handleStorageMigrationStart(start func(*storageMigrationManager) error) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		manager := b.Core.storageMigration
+		if err := start(manager); err != nil {
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		}
+		return storageMigrationStatusResponse(manager.getStatus()), nil
+	}
+}
+
+func (b *SystemBackend) handleStorageMigrationCutover() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		manager := b.Core.storageMigration
+		if err := manager.cutover(ctx); err != nil {
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		}
+		return storageMigrationStatusResponse(manager.getStatus()), nil
+	}
+}
+
+func storageMigrationStatusResponse(status *storageMigrationStatus) *logical.Response {
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	mismatchedKeys := make([]map[string]interface{}, 0, len(status.MismatchedKeys))
+	for _, mismatch := range status.MismatchedKeys {
+		mismatchedKeys = append(mismatchedKeys, map[string]interface{}{
+			"key":    mismatch.Key,
+			"reason": mismatch.Reason,
+		})
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"state":                    status.State,
+			"start_time":               formatTime(status.StartTime),
+			"end_time":                 formatTime(status.EndTime),
+			"keys_copied":              status.KeysCopied,
+			"keys_removed":             status.KeysRemoved,
+			"keys_verified":            status.KeysVerified,
+			"mismatched_keys_count":    status.MismatchedKeysCount,
+			"mismatched_keys":          mismatchedKeys,
+			"destination_write_errors": status.DestinationWriteErrors,
+			"last_error":               status.LastError,
+		},
+	}
+}
+
+var sysStorageMigrationHelp = map[string][2]string{
+	"storage-migration": {
+		"Returns the status of the online storage migration.",
+		`The status reports the state and progress of the last copy or
+		verification, and the number of writes that failed on the destination
+		storage.`,
+	},
+	"storage-migration-copy": {
+		"Starts copying the source storage to the destination storage.",
+		`The keys of the source storage are copied in the background, then the
+		keys of the destination storage that don't exist in the source storage
+		are removed. Writes made meanwhile are applied to both storage.`,
+	},
+	"storage-migration-verify": {
+		"Starts comparing the source storage with the destination storage.",
+		`The keys that are missing, extra or different in the destination
+		storage are reported in the status. A successful verification is
+		required for the cutover.`,
+	},
+	"storage-migration-cutover": {
+		"Stops the writes to the source storage to complete the migration.",
+		`Once the cutover completes, the storage of this node is read-only and
+		the source storage is locked against server startup. The server must
+		then be restarted with the destination storage.`,
+	},
+}
@@ -56,13 +56,20 @@ func TestSystemBackend_RootPaths(t *testing.T) {
 		"config/auditing/*",
 		"config/ui/headers/*",
 		"plugins/catalog/*",
+		"plugins/install",
 		"revoke-prefix/*",
 		"revoke-force/*",
 		"leases/revoke-prefix/*",
 		"leases/revoke-force/*",
 		"leases/lookup/*",
 		"storage/raft/snapshot-auto/config/*",
+		"storage/migration",
+		"storage/migration/*",
 		"leases",
+		"leases/search",
+		"leases/revoke-filtered",
+		"leases/irrevocable",
+		"leases/irrevocable/*",
 		"internal/inspect/*",
 	}
 
@@ -598,6 +605,183 @@ func TestSystemBackend_PathCapabilities(t *testing.T) {
 	nonRootCheckFunc(t, resp)
 }
 
+func TestSystemBackend_PolicySimulate(t *testing.T) {
+	core, b, rootToken := testCoreSystemBackend(t)
+
+	for name, rules := range map[string]string{
+		"simulate-read": `
+path "secret/*" {
+	capabilities = ["read", "list"]
+}
+`,
+		"simulate-write": `
+path "secret/app/*" {
+	capabilities = ["create", "update"]
+	denied_parameters = {
+		"admin" = []
+	}
+}
+`,
+	} {
+		policy, err := ParseACLPolicy(namespace.RootNamespace, rules)
+		if err != nil {
+			t.Fatal(err)
+		}
+		policy.Name = name
+		if err := core.policyStore.SetPolicy(namespace.RootContext(nil), policy); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	simulate := func(data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(namespace.RootContext(nil), &logical.Request{
+			Path:      "policy/simulate",
+			Operation: logical.UpdateOperation,
+			Data:      data,
+		})
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+		}
+		return resp
+	}
+
+	// Allowed by the more specific write policy
+	resp := simulate(map[string]interface{}{
+		"policies":  "simulate-read,simulate-write",
+		"path":      "secret/app/config",
+		"operation": "update",
+		"parameters": map[string]interface{}{
+			"value": "foo",
+		},
+	})
+	if !resp.Data["allowed"].(bool) || resp.Data["matched_path"] != "secret/app/*" {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+	matches := resp.Data["policies"].([]map[string]interface{})
+	if len(matches) != 2 {
+		t.Fatalf("expected both policies to match, got: %#v", matches)
+	}
+	for _, match := range matches {
+		switch match["name"] {
+		case "simulate-read":
+			if match["allowed"].(bool) || match["denial_reason"] != string(ACLDenialMissingCapability) {
+				t.Fatalf("bad: %#v", match)
+			}
+		case "simulate-write":
+			if !match["allowed"].(bool) || match["matched_path"] != "secret/app/*" {
+				t.Fatalf("bad: %#v", match)
+			}
+		default:
+			t.Fatalf("unexpected policy: %#v", match)
+		}
+	}
+
+	// Denied by a parameter constraint
+	resp = simulate(map[string]interface{}{
+		"policies":  "simulate-read,simulate-write",
+		"path":      "secret/app/config",
+		"operation": "update",
+		"parameters": map[string]interface{}{
+			"admin": true,
+		},
+	})
+	if resp.Data["allowed"].(bool) ||
+		resp.Data["denial_reason"] != string(ACLDenialDeniedParameter) ||
+		resp.Data["denied_parameter"] != "admin" {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// Evaluate the policies of a token
+	te := &logical.TokenEntry{
+		ID:       "simulatetoken",
+		Path:     "testpath",
+		Policies: []string{"simulate-read"},
+		TTL:      time.Hour,
+	}
+	testMakeTokenDirectly(t, core.tokenStore, te)
+
+	resp = simulate(map[string]interface{}{
+		"token": "simulatetoken",
+		"path":  "secret/foo",
+	})
+	if !resp.Data["allowed"].(bool) || resp.Data["matched_path"] != "secret/*" {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+	if !reflect.DeepEqual(resp.Data["capabilities"], []string{"list", "read"}) {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	resp = simulate(map[string]interface{}{
+		"token":     "simulatetoken",
+		"path":      "sys/policy/foo",
+		"operation": "update",
+	})
+	if resp.Data["allowed"].(bool) || resp.Data["denial_reason"] != string(ACLDenialNoMatchingPath) {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// The root token is allowed everywhere
+	resp = simulate(map[string]interface{}{
+		"token":     rootToken,
+		"path":      "sys/raw/foo",
+		"operation": "read",
+	})
+	if !resp.Data["allowed"].(bool) || !resp.Data["root"].(bool) {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// Exactly one subject must be given
+	resp, err := b.HandleRequest(namespace.RootContext(nil), &logical.Request{
+		Path:      "policy/simulate",
+		Operation: logical.UpdateOperation,
+		Data: map[string]interface{}{
+			"token":    "simulatetoken",
+			"policies": "simulate-read",
+			"path":     "secret/foo",
+		},
+	})
+	if err == nil {
+		t.Fatalf("expected an error, got: %#v", resp)
+	}
+
+	// A policy named simulate can be managed with the policies/acl paths
+	req := logical.TestRequest(t, logical.UpdateOperation, "policies/acl/simulate")
+	req.Data["policy"] = `path "secret/*" { capabilities = ["read"] }`
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+	resp, err = b.HandleRequest(namespace.RootContext(nil), logical.TestRequest(t, logical.ReadOperation, "policies/acl/simulate"))
+	if err != nil || resp == nil || resp.Data["name"] != "simulate" {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+
+	// The endpoint requires sudo
+	if !core.router.RootPath(namespace.RootContext(nil), "sys/policy/simulate") {
+		t.Fatal("expected sys/policy/simulate to require sudo")
+	}
+
+	// Tokens of a parent namespace can't be simulated from a child namespace
+	childCtx := namespace.ContextWithNamespace(namespace.RootContext(nil), &namespace.Namespace{
+		ID:   "child",
+		Path: "child/",
+	})
+	for _, data := range []map[string]interface{}{
+		{"token": rootToken, "path": "sys/raw/foo"},
+		{"token": "simulatetoken", "path": "secret/foo"},
+	} {
+		resp, err = b.HandleRequest(childCtx, &logical.Request{
+			Path:      "policy/simulate",
+			Operation: logical.UpdateOperation,
+			Data:      data,
+		})
+		if err != logical.ErrPermissionDenied {
+			t.Fatalf("expected permission denied, got resp: %#v\nerr: %v", resp, err)
+		}
+	}
+}
+
 func TestSystemBackend_Capabilities_BC(t *testing.T) {
 	testCapabilities(t, "capabilities")
 	testCapabilities(t, "capabilities-self")
@@ -2030,7 +2214,7 @@ func TestSystemBackend_tuneAuth(t *testing.T) {
 		if err := file.Close(); err != nil {
 			t.Fatal(err)
 		}
-		err = c.pluginCatalog.Set(context.Background(), "token", consts.PluginTypeCredential, "v1.0.0", "foo", []string{}, []string{}, []byte{})
+		err = c.pluginCatalog.Set(context.Background(), "token", consts.PluginTypeCredential, "v1.0.0", "foo", []string{}, []string{}, []byte{}, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -3185,6 +3369,103 @@ func TestSystemBackend_PluginCatalog_CannotRegisterBuiltinPlugins(t *testing.T)
 	}
 }
 
+func TestSystemBackend_PluginCatalog_RuntimeConfig(t *testing.T) {
+	c, b, _ := testCoreSystemBackend(t)
+	// Bootstrap the pluginCatalog
+	sym, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	c.pluginCatalog.directory = sym
+	c.pluginCatalog.launcher = &pluginutil.PluginLauncher{
+		Command: "vault",
+		Args:    []string{"plugin", "sandbox"},
+	}
+
+	file, err := ioutil.TempFile(sym, "temp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "plugins/catalog/database/test-plugin")
+	req.Data["sha256"] = hex.EncodeToString([]byte{'1'})
+	req.Data["command"] = filepath.Base(file.Name())
+	req.Data["memory_limit"] = "a lot"
+	resp, err := b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !strings.Contains(resp.Error().Error(), "invalid memory_limit") {
+		t.Fatalf("err: %v", resp.Error())
+	}
+
+	req.Data["memory_limit"] = "256MiB"
+	req.Data["cpu_quota"] = 50
+	req.Data["uid"] = 1500
+	req.Data["gid"] = 1500
+	req.Data["restrict_env"] = true
+	req.Data["seccomp_profile"] = "../profile.bpf"
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err == nil && !resp.IsError() {
+		t.Fatal("expected error for a seccomp profile outside of the plugin directory")
+	}
+
+	req.Data["seccomp_profile"] = "profile.bpf"
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || resp.Error() != nil {
+		t.Fatalf("err: %v %v", err, resp.Error())
+	}
+
+	req = logical.TestRequest(t, logical.ReadOperation, "plugins/catalog/database/test-plugin")
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	expectedRuntime := map[string]interface{}{
+		"memory_limit":    int64(256 * 1024 * 1024),
+		"cpu_quota":       int64(50),
+		"uid":             1500,
+		"gid":             1500,
+		"restrict_env":    true,
+		"seccomp_profile": "profile.bpf",
+	}
+	for k, v := range expectedRuntime {
+		if !reflect.DeepEqual(resp.Data[k], v) {
+			t.Fatalf("expected %s to be %#v, got %#v", k, v, resp.Data[k])
+		}
+	}
+
+	// The catalog resolves the seccomp profile and the launcher of the plugin
+	runner, err := c.pluginCatalog.Get(namespace.RootContext(nil), "test-plugin", consts.PluginTypeDatabase, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runner.Runtime.SeccompProfile != filepath.Join(sym, "profile.bpf") {
+		t.Fatalf("unexpected seccomp profile: %q", runner.Runtime.SeccompProfile)
+	}
+	if runner.Launcher != c.pluginCatalog.launcher {
+		t.Fatalf("unexpected launcher: %#v", runner.Launcher)
+	}
+
+	// Registering without runtime options clears them
+	req = logical.TestRequest(t, logical.UpdateOperation, "plugins/catalog/database/test-plugin")
+	req.Data["sha256"] = hex.EncodeToString([]byte{'1'})
+	req.Data["command"] = filepath.Base(file.Name())
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || resp.Error() != nil {
+		t.Fatalf("err: %v %v", err, resp.Error())
+	}
+	req = logical.TestRequest(t, logical.ReadOperation, "plugins/catalog/database/test-plugin")
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, ok := resp.Data["memory_limit"]; ok {
+		t.Fatalf("unexpected runtime configuration: %#v", resp.Data)
+	}
+}
+
 func TestSystemBackend_ToolsHash(t *testing.T) {
 	b := testSystemBackend(t)
 	req := logical.TestRequest(t, logical.UpdateOperation, "tools/hash")
@@ -5331,7 +5612,7 @@ func TestValidateVersion_HelpfulErrorWhenBuiltinOverridden(t *testing.T) {
 	defer file.Close()
 
 	command := filepath.Base(file.Name())
-	err = core.pluginCatalog.Set(context.Background(), "kubernetes", consts.PluginTypeCredential, "", command, nil, nil, nil)
+	err = core.pluginCatalog.Set(context.Background(), "kubernetes", consts.PluginTypeCredential, "", command, nil, nil, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,433 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/vault/command/server"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/internalshared/configutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// failedLoginPruneInterval is how often entries whose counters have been
+// reset are removed from the failed login maps.
+const failedLoginPruneInterval = time.Minute
+
+// loginDelayState carries what is needed to record the outcome of a login
+// request that is subject to progressive delays.
+type loginDelayState struct {
+	config UserLockoutConfig
+	user   *FailedLoginUser
+	source *FailedLoginSource
+
+	// The failures reserved for the attempt before it was routed, and the
+	// failed login information they replaced, so that the reservation can be
+	// undone if the attempt turns out not to be a failure.
+	reservedUser, prevUser     *FailedLoginInfo
+	reservedSource, prevSource *FailedLoginInfo
+}
+
+// delayedLogin describes a user or client address whose login attempts are
+// currently being delayed.
+type delayedLogin struct {
+	mountAccessor string
+	name          string
+	count         uint
+	delay         time.Duration
+	delayedUntil  time.Time
+}
+
+// userLockoutSupportedByAuthType returns whether user lockout configuration
+// applies to the given auth method type.
+func userLockoutSupportedByAuthType(mountType string) bool {
+	switch strings.ToLower(mountType) {
+	case "ldap", "approle", "userpass", "okta":
+		return true
+	default:
+		return false
+	}
+}
+
+// getUserLockoutConfiguration returns the user lockout configuration in
+// effect for the given auth mount. Values tuned on the mount take precedence
+// over the server configuration for the mount's type, which in turn falls
+// back to the "all" stanza and the built-in defaults.
+func (c *Core) getUserLockoutConfiguration(entry *MountEntry) UserLockoutConfig {
+	config := UserLockoutConfig{
+		LockoutThreshold:     configutil.UserLockoutThresholdDefault,
+		LockoutDuration:      configutil.UserLockoutDurationDefault,
+		LockoutCounterReset:  configutil.UserLockoutCounterResetDefault,
+		DisableLockout:       configutil.DisableUserLockoutDefault,
+		ProgressiveDelay:     configutil.ProgressiveDelayDefault,
+		ProgressiveDelayBase: configutil.ProgressiveDelayBaseDefault,
+		ProgressiveDelayMax:  configutil.ProgressiveDelayMaxDefault,
+	}
+
+	if conf, ok := c.rawConfig.Load().(*server.Config); ok && conf != nil && conf.SharedConfig != nil {
+		var serverConfig *configutil.UserLockout
+		for _, userLockout := range conf.UserLockouts {
+			if userLockout.Type == strings.ToLower(entry.Type) {
+				serverConfig = userLockout
+				break
+			}
+			if userLockout.Type == "all" {
+				serverConfig = userLockout
+			}
+		}
+		if serverConfig != nil {
+			config.LockoutThreshold = serverConfig.LockoutThreshold
+			config.LockoutDuration = serverConfig.LockoutDuration
+			config.LockoutCounterReset = serverConfig.LockoutCounterReset
+			config.DisableLockout = serverConfig.DisableLockout
+			config.ProgressiveDelay = serverConfig.ProgressiveDelay
+			config.ProgressiveDelayBase = serverConfig.ProgressiveDelayBase
+			config.ProgressiveDelayMax = serverConfig.ProgressiveDelayMax
+		}
+	}
+
+	if mountConfig := entry.Config.UserLockoutConfig; mountConfig != nil {
+		if mountConfig.LockoutThreshold != 0 {
+			config.LockoutThreshold = mountConfig.LockoutThreshold
+		}
+		if mountConfig.LockoutDuration != 0 {
+			config.LockoutDuration = mountConfig.LockoutDuration
+		}
+		if mountConfig.LockoutCounterReset != 0 {
+			config.LockoutCounterReset = mountConfig.LockoutCounterReset
+		}
+		if mountConfig.DisableLockout {
+			config.DisableLockout = true
+		}
+		if mountConfig.ProgressiveDelay {
+			config.ProgressiveDelay = true
+		}
+		if mountConfig.ProgressiveDelayBase != 0 {
+			config.ProgressiveDelayBase = mountConfig.ProgressiveDelayBase
+		}
+		if mountConfig.ProgressiveDelayMax != 0 {
+			config.ProgressiveDelayMax = mountConfig.ProgressiveDelayMax
+		}
+	}
+
+	if config.ProgressiveDelayMax < config.ProgressiveDelayBase {
+		config.ProgressiveDelayMax = config.ProgressiveDelayBase
+	}
+
+	return config
+}
+
+// progressiveLoginDelay returns how long the next login attempt must wait
+// given the failed login information, along with the time at which the wait
+// ends. Failures below the lockout threshold are not delayed; every failure
+// from the threshold on doubles the delay, up to the configured maximum.
+func progressiveLoginDelay(config UserLockoutConfig, info *FailedLoginInfo, now time.Time) (time.Duration, time.Time) {
+	if info == nil || info.count == 0 {
+		return 0, time.Time{}
+	}
+	if config.LockoutCounterReset > 0 && now.Sub(info.lastFailedLoginTime) > config.LockoutCounterReset {
+		return 0, time.Time{}
+	}
+
+	threshold := config.LockoutThreshold
+	if threshold == 0 {
+		threshold = 1
+	}
+	if uint64(info.count) < threshold {
+		return 0, time.Time{}
+	}
+
+	delay := config.ProgressiveDelayBase
+	for i := uint64(info.count) - threshold; i > 0 && delay < config.ProgressiveDelayMax; i-- {
+		delay *= 2
+	}
+	if delay > config.ProgressiveDelayMax {
+		delay = config.ProgressiveDelayMax
+	}
+
+	return delay, info.lastFailedLoginTime.Add(delay)
+}
+
+// loginAliasName asks the auth backend serving the login request for the
+// alias name the request would log in as, without authenticating it.
+func (c *Core) loginAliasName(ctx context.Context, req *logical.Request) (string, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.authLock.RLock()
+	defer c.authLock.RUnlock()
+
+	mountPoint := c.router.MatchingMount(ctx, req.Path)
+	backend := c.router.MatchingBackend(ctx, req.Path)
+	if mountPoint == "" || backend == nil || backend.Type() != logical.TypeCredential {
+		return "", nil
+	}
+
+	resp, err := backend.HandleRequest(ctx, &logical.Request{
+		MountPoint: mountPoint,
+		Path:       strings.TrimPrefix(ns.Path+req.Path, mountPoint),
+		Operation:  logical.AliasLookaheadOperation,
+		Data:       req.Data,
+		Connection: req.Connection,
+		Storage:    c.router.MatchingStorageByAPIPath(ctx, req.Path),
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp == nil || resp.Auth == nil || resp.Auth.Alias == nil {
+		return "", nil
+	}
+	return resp.Auth.Alias.Name, nil
+}
+
+// checkLoginDelay determines whether progressive login delays apply to the
+// login request and, if so, whether the user or the client address has to
+// wait before trying again. A nil state means delays do not apply; a non-nil
+// response means the request must be rejected.
+//
+// An attempt which is not delayed is counted as a failure right away, under
+// the same lock as the check, so that concurrent attempts can't all get past
+// the check before any of them is recorded. recordLoginAttempt undoes the
+// reservation once the attempt turns out not to be a failure.
+func (c *Core) checkLoginDelay(ctx context.Context, req *logical.Request, entry *MountEntry) (*loginDelayState, *logical.Response, error) {
+	if entry == nil || entry.Table != credentialTableType || !userLockoutSupportedByAuthType(entry.Type) {
+		return nil, nil, nil
+	}
+
+	config := c.getUserLockoutConfiguration(entry)
+	if config.DisableLockout || !config.ProgressiveDelay {
+		return nil, nil, nil
+	}
+
+	state := &loginDelayState{
+		config: config,
+	}
+
+	aliasName, err := c.loginAliasName(ctx, req)
+	if err != nil {
+		c.logger.Debug("unable to determine alias name for login delay", "request_path", req.Path, "error", err)
+	}
+	if aliasName != "" {
+		state.user = &FailedLoginUser{
+			aliasName:     aliasName,
+			mountAccessor: entry.Accessor,
+		}
+	}
+	if req.Connection != nil && req.Connection.RemoteAddr != "" {
+		state.source = &FailedLoginSource{
+			remoteAddr:    req.Connection.RemoteAddr,
+			mountAccessor: entry.Accessor,
+		}
+	}
+
+	c.userFailedLoginInfoLock.Lock()
+	defer c.userFailedLoginInfoLock.Unlock()
+
+	now := time.Now()
+	var delayedUntil time.Time
+	if state.user != nil {
+		if _, until := progressiveLoginDelay(config, c.userFailedLoginInfo[*state.user], now); until.After(delayedUntil) {
+			delayedUntil = until
+		}
+	}
+	if state.source != nil {
+		if _, until := progressiveLoginDelay(config, c.sourceFailedLoginInfo[*state.source], now); until.After(delayedUntil) {
+			delayedUntil = until
+		}
+	}
+
+	if now.Before(delayedUntil) {
+		retryAfter := delayedUntil.Sub(now).Round(time.Second)
+		if retryAfter < time.Second {
+			retryAfter = time.Second
+		}
+		return nil, logical.ErrorResponse(fmt.Sprintf("too many failed login attempts, retry after %s", retryAfter)), logical.ErrRateLimitQuotaExceeded
+	}
+
+	if state.user != nil {
+		state.prevUser = c.userFailedLoginInfo[*state.user]
+		state.reservedUser = nextFailedLoginInfo(config, state.prevUser, now)
+		c.userFailedLoginInfo[*state.user] = state.reservedUser
+	}
+	if state.source != nil {
+		state.prevSource = c.sourceFailedLoginInfo[*state.source]
+		state.reservedSource = nextFailedLoginInfo(config, state.prevSource, now)
+		c.sourceFailedLoginInfo[*state.source] = state.reservedSource
+	}
+
+	return state, nil, nil
+}
+
+// recordLoginAttempt settles the failures reserved by checkLoginDelay once
+// the login request has been routed. Rejected credentials keep the failures
+// of both the user and the client address. A successful login resets the
+// user's counter and releases the failure of the client address, and any
+// other outcome releases both.
+func (c *Core) recordLoginAttempt(ctx context.Context, state *loginDelayState, resp *logical.Response, routeErr error) {
+	if state == nil {
+		return
+	}
+
+	now := time.Now()
+	if errwrap.Contains(routeErr, logical.ErrInvalidCredentials.Error()) {
+		c.pruneFailedLoginInfo(now)
+		return
+	}
+
+	c.userFailedLoginInfoLock.Lock()
+	defer c.userFailedLoginInfoLock.Unlock()
+
+	if state.user != nil {
+		var info *FailedLoginInfo
+		if resp == nil || resp.Auth == nil {
+			info = releasedFailedLoginInfo(c.userFailedLoginInfo[*state.user], state.reservedUser, state.prevUser)
+		}
+		if info != nil {
+			c.userFailedLoginInfo[*state.user] = info
+		} else {
+			delete(c.userFailedLoginInfo, *state.user)
+		}
+	}
+	if state.source != nil {
+		if info := releasedFailedLoginInfo(c.sourceFailedLoginInfo[*state.source], state.reservedSource, state.prevSource); info != nil {
+			c.sourceFailedLoginInfo[*state.source] = info
+		} else {
+			delete(c.sourceFailedLoginInfo, *state.source)
+		}
+	}
+}
+
+// releasedFailedLoginInfo returns the failed login information once a failure
+// reserved by checkLoginDelay is undone, or nil if there are no failures
+// left. If the entry hasn't been updated since the reservation, the entry it
+// replaced is restored; otherwise the reserved failure is subtracted.
+func releasedFailedLoginInfo(current, reserved, prev *FailedLoginInfo) *FailedLoginInfo {
+	if current == reserved {
+		return prev
+	}
+	if current == nil || current.count <= 1 {
+		return nil
+	}
+	released := *current
+	released.count--
+	return &released
+}
+
+// nextFailedLoginInfo returns the failed login information after one more
+// failure at the given time.
+func nextFailedLoginInfo(config UserLockoutConfig, info *FailedLoginInfo, now time.Time) *FailedLoginInfo {
+	next := &FailedLoginInfo{
+		count:               1,
+		lastFailedLoginTime: now,
+	}
+	if info != nil && (config.LockoutCounterReset <= 0 || now.Sub(info.lastFailedLoginTime) <= config.LockoutCounterReset) {
+		next.count = info.count + 1
+	}
+	return next
+}
+
+// pruneFailedLoginInfo removes failed login entries whose counters have
+// been reset, or whose mount no longer exists, so that the maps do not grow
+// without bound. It does nothing if it ran less than
+// failedLoginPruneInterval ago.
+func (c *Core) pruneFailedLoginInfo(now time.Time) {
+	c.userFailedLoginInfoLock.Lock()
+	defer c.userFailedLoginInfoLock.Unlock()
+
+	if now.Sub(c.failedLoginInfoLastPruned) < failedLoginPruneInterval {
+		return
+	}
+	c.failedLoginInfoLastPruned = now
+
+	configs := make(map[string]*UserLockoutConfig)
+	expired := func(mountAccessor string, info *FailedLoginInfo) bool {
+		config, ok := configs[mountAccessor]
+		if !ok {
+			if entry := c.router.MatchingMountByAccessor(mountAccessor); entry != nil {
+				entryConfig := c.getUserLockoutConfiguration(entry)
+				config = &entryConfig
+			}
+			configs[mountAccessor] = config
+		}
+		if config == nil {
+			return true
+		}
+		return config.LockoutCounterReset > 0 && now.Sub(info.lastFailedLoginTime) > config.LockoutCounterReset
+	}
+
+	for key, info := range c.userFailedLoginInfo {
+		if expired(key.mountAccessor, info) {
+			delete(c.userFailedLoginInfo, key)
+		}
+	}
+	for key, info := range c.sourceFailedLoginInfo {
+		if expired(key.mountAccessor, info) {
+			delete(c.sourceFailedLoginInfo, key)
+		}
+	}
+}
+
+// delayedLogins returns the users and client addresses whose login attempts
+// on auth mounts with progressive delays enabled are currently delayed.
+func (c *Core) delayedLogins(now time.Time) (users []*delayedLogin, sources []*delayedLogin) {
+	c.userFailedLoginInfoLock.RLock()
+	defer c.userFailedLoginInfoLock.RUnlock()
+
+	configs := make(map[string]*UserLockoutConfig)
+	delayed := func(mountAccessor, name string, info *FailedLoginInfo) *delayedLogin {
+		config, ok := configs[mountAccessor]
+		if !ok {
+			if entry := c.router.MatchingMountByAccessor(mountAccessor); entry != nil {
+				entryConfig := c.getUserLockoutConfiguration(entry)
+				if entryConfig.ProgressiveDelay && !entryConfig.DisableLockout {
+					config = &entryConfig
+				}
+			}
+			configs[mountAccessor] = config
+		}
+		if config == nil {
+			return nil
+		}
+		delay, until := progressiveLoginDelay(*config, info, now)
+		if delay == 0 {
+			return nil
+		}
+		return &delayedLogin{
+			mountAccessor: mountAccessor,
+			name:          name,
+			count:         info.count,
+			delay:         delay,
+			delayedUntil:  until,
+		}
+	}
+
+	for key, info := range c.userFailedLoginInfo {
+		if d := delayed(key.mountAccessor, key.aliasName, info); d != nil {
+			users = append(users, d)
+		}
+	}
+	for key, info := range c.sourceFailedLoginInfo {
+		if d := delayed(key.mountAccessor, key.remoteAddr, info); d != nil {
+			sources = append(sources, d)
+		}
+	}
+
+	sortDelayedLogins := func(logins []*delayedLogin) {
+		sort.Slice(logins, func(i, j int) bool {
+			if logins[i].mountAccessor != logins[j].mountAccessor {
+				return logins[i].mountAccessor < logins[j].mountAccessor
+			}
+			return logins[i].name < logins[j].name
+		})
+	}
+	sortDelayedLogins(users)
+	sortDelayedLogins(sources)
+
+	return users, sources
+}
@@ -0,0 +1,177 @@
+package vault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+func TestProgressiveLoginDelay(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	config := UserLockoutConfig{
+		LockoutThreshold:     3,
+		LockoutCounterReset:  time.Hour,
+		ProgressiveDelay:     true,
+		ProgressiveDelayBase: time.Second,
+		ProgressiveDelayMax:  10 * time.Second,
+	}
+
+	cases := []struct {
+		name     string
+		config   UserLockoutConfig
+		info     *FailedLoginInfo
+		expected time.Duration
+	}{
+		{"no failures", config, nil, 0},
+		{"below threshold", config, &FailedLoginInfo{count: 2, lastFailedLoginTime: now}, 0},
+		{"at threshold", config, &FailedLoginInfo{count: 3, lastFailedLoginTime: now}, time.Second},
+		{"doubles", config, &FailedLoginInfo{count: 5, lastFailedLoginTime: now}, 4 * time.Second},
+		{"capped", config, &FailedLoginInfo{count: 100, lastFailedLoginTime: now}, 10 * time.Second},
+		{"counter reset", config, &FailedLoginInfo{count: 5, lastFailedLoginTime: now.Add(-2 * time.Hour)}, 0},
+		{"zero threshold", UserLockoutConfig{
+			ProgressiveDelayBase: time.Second,
+			ProgressiveDelayMax:  time.Minute,
+		}, &FailedLoginInfo{count: 1, lastFailedLoginTime: now}, time.Second},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			delay, until := progressiveLoginDelay(tc.config, tc.info, now)
+			if delay != tc.expected {
+				t.Fatalf("expected delay %s, got %s", tc.expected, delay)
+			}
+			if delay > 0 && !until.Equal(tc.info.lastFailedLoginTime.Add(delay)) {
+				t.Fatalf("unexpected delayed until time %s", until)
+			}
+		})
+	}
+}
+
+func TestNextFailedLoginInfo(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	config := UserLockoutConfig{LockoutCounterReset: time.Minute}
+
+	info := nextFailedLoginInfo(config, nil, now)
+	if info.count != 1 || !info.lastFailedLoginTime.Equal(now) {
+		t.Fatalf("unexpected failed login info: %#v", info)
+	}
+
+	info = nextFailedLoginInfo(config, &FailedLoginInfo{count: 4, lastFailedLoginTime: now.Add(-time.Second)}, now)
+	if info.count != 5 {
+		t.Fatalf("expected count 5, got %d", info.count)
+	}
+
+	info = nextFailedLoginInfo(config, &FailedLoginInfo{count: 4, lastFailedLoginTime: now.Add(-2 * time.Minute)}, now)
+	if info.count != 1 {
+		t.Fatalf("expected counter to be reset, got %d", info.count)
+	}
+}
+
+func TestCore_GetUserLockoutConfiguration(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+
+	entry := &MountEntry{
+		Table: credentialTableType,
+		Type:  "userpass",
+		Config: MountConfig{
+			UserLockoutConfig: &UserLockoutConfig{
+				LockoutThreshold:    2,
+				ProgressiveDelay:    true,
+				ProgressiveDelayMax: 30 * time.Second,
+			},
+		},
+	}
+
+	config := c.getUserLockoutConfiguration(entry)
+	if config.LockoutThreshold != 2 || !config.ProgressiveDelay {
+		t.Fatalf("expected mount tuning to take effect: %#v", config)
+	}
+	if config.ProgressiveDelayBase != time.Second || config.ProgressiveDelayMax != 30*time.Second {
+		t.Fatalf("unexpected progressive delay settings: %#v", config)
+	}
+	if config.LockoutCounterReset != 15*time.Minute {
+		t.Fatalf("expected default lockout counter reset, got %s", config.LockoutCounterReset)
+	}
+}
+
+func TestCore_LoginDelayReservation(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	entry := &MountEntry{
+		Table:    credentialTableType,
+		Type:     "userpass",
+		Accessor: "auth_userpass_test",
+		Config: MountConfig{
+			UserLockoutConfig: &UserLockoutConfig{
+				LockoutThreshold:     2,
+				ProgressiveDelay:     true,
+				ProgressiveDelayBase: time.Minute,
+				ProgressiveDelayMax:  time.Minute,
+			},
+		},
+	}
+	req := &logical.Request{
+		Path:       "auth/userpass/login/bob",
+		Connection: &logical.Connection{RemoteAddr: "127.0.0.1"},
+	}
+	source := FailedLoginSource{remoteAddr: "127.0.0.1", mountAccessor: entry.Accessor}
+	count := func() uint {
+		c.userFailedLoginInfoLock.RLock()
+		defer c.userFailedLoginInfoLock.RUnlock()
+		if info := c.sourceFailedLoginInfo[source]; info != nil {
+			return info.count
+		}
+		return 0
+	}
+
+	// The mount isn't registered with the router, so keep its entries from
+	// being pruned
+	c.userFailedLoginInfoLock.Lock()
+	c.failedLoginInfoLastPruned = time.Now()
+	c.userFailedLoginInfoLock.Unlock()
+
+	// Attempts in flight count as failures, so concurrent attempts can't all
+	// get past the check
+	first, resp, err := c.checkLoginDelay(ctx, req, entry)
+	if err != nil || resp != nil || first == nil {
+		t.Fatalf("expected the first attempt to be allowed: %#v, %v", resp, err)
+	}
+	second, resp, err := c.checkLoginDelay(ctx, req, entry)
+	if err != nil || resp != nil || second == nil {
+		t.Fatalf("expected the second attempt to be allowed: %#v, %v", resp, err)
+	}
+	if _, _, err := c.checkLoginDelay(ctx, req, entry); err != logical.ErrRateLimitQuotaExceeded {
+		t.Fatalf("expected the third attempt to be delayed, got %v", err)
+	}
+
+	// An error which isn't about the credentials releases the reservation
+	c.recordLoginAttempt(ctx, second, logical.ErrorResponse("missing password"), logical.ErrInvalidRequest)
+	if n := count(); n != 1 {
+		t.Fatalf("expected one failure after releasing the reservation, got %d", n)
+	}
+
+	// Rejected credentials keep it
+	c.recordLoginAttempt(ctx, first, logical.ErrorResponse("invalid username or password"), logical.ErrInvalidCredentials)
+	if n := count(); n != 1 {
+		t.Fatalf("expected the failure to be kept, got %d", n)
+	}
+
+	// A successful login releases the failure of the client address
+	state, resp, err := c.checkLoginDelay(ctx, req, entry)
+	if err != nil || resp != nil || state == nil {
+		t.Fatalf("expected the attempt to be allowed: %#v, %v", resp, err)
+	}
+	c.recordLoginAttempt(ctx, state, &logical.Response{Auth: &logical.Auth{}}, nil)
+	if n := count(); n != 1 {
+		t.Fatalf("expected the earlier failure to remain, got %d", n)
+	}
+}
@@ -47,6 +47,7 @@ const (
 	mfaMethodTypeDuo               = "duo"
 	mfaMethodTypeOkta              = "okta"
 	mfaMethodTypePingID            = "pingid"
+	mfaMethodTypeWebAuthn          = "webauthn"
 	memDBLoginMFAConfigsTable      = "login_mfa_configs"
 	memDBMFALoginEnforcementsTable = "login_enforcements"
 	mfaTOTPKeysPrefix              = systemBarrierPrefix + "mfa/totpkeys/"
@@ -89,6 +90,28 @@ func (b *SystemBackend) loginMFAPaths() []*framework.Path {
 				},
 			},
 		},
+		{
+			Pattern: "mfa/webauthn/options",
+			Fields: map[string]*framework.FieldSchema{
+				"mfa_request_id": {
+					Type:        framework.TypeString,
+					Description: "ID for this MFA request",
+					Required:    true,
+				},
+				"method_id": {
+					Type:        framework.TypeString,
+					Description: "The unique identifier of the WebAuthn MFA method",
+					Required:    true,
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback:                  b.Core.loginMFABackend.handleMFAWebAuthnOptions,
+					Summary:                   "Returns the WebAuthn assertion options for the given MFA request and method",
+					ForwardPerformanceStandby: true,
+				},
+			},
+		},
 	}
 }
 
@@ -341,6 +364,12 @@ func (i *IdentityStore) handleMFAMethodUpdateCommon(ctx context.Context, req *lo
 			return logical.ErrorResponse(err.Error()), nil
 		}
 
+	case mfaMethodTypeWebAuthn:
+		err = parseWebAuthnConfig(mConfig, d)
+		if err != nil {
+			return logical.ErrorResponse(err.Error()), nil
+		}
+
 	default:
 		return logical.ErrorResponse(fmt.Sprintf("unrecognized type %q", methodType)), nil
 	}
@@ -476,6 +505,14 @@ func (i *IdentityStore) handleLoginMFAGenerateCommon(ctx context.Context, req *l
 }
 
 func (i *IdentityStore) handleLoginMFAAdminDestroyUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return i.handleLoginMFAAdminDestroyCommon(ctx, req, d, mfaMethodTypeTOTP)
+}
+
+func (i *IdentityStore) handleLoginMFAWebAuthnAdminDestroyUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return i.handleLoginMFAAdminDestroyCommon(ctx, req, d, mfaMethodTypeWebAuthn)
+}
+
+func (i *IdentityStore) handleLoginMFAAdminDestroyCommon(ctx context.Context, req *logical.Request, d *framework.FieldData, methodType string) (*logical.Response, error) {
 	var entity *identity.Entity
 	var err error
 
@@ -511,8 +548,8 @@ func (i *IdentityStore) handleLoginMFAAdminDestroyUpdate(ctx context.Context, re
 		return nil, fmt.Errorf("configuration for method ID %q does not contain an identifier", methodID)
 	}
 
-	if mConfig.Type != mfaMethodTypeTOTP {
-		return nil, fmt.Errorf("method ID does not match TOTP type")
+	if mConfig.Type != methodType {
+		return nil, fmt.Errorf("method ID does not match %s type", methodType)
 	}
 
 	ns, err := namespace.FromContext(ctx)
@@ -537,8 +574,31 @@ func (i *IdentityStore) handleLoginMFAAdminDestroyUpdate(ctx context.Context, re
 		return logical.ErrorResponse(fmt.Sprintf("entity namespace %s outside of the current namespace %s", entityNS.Path, ns.Path)), nil
 	}
 
-	// destroying the secret on the entity
-	if entity.MFASecrets != nil {
+	// destroying the secret on the entity. For WebAuthn, a single credential
+	// may be removed instead.
+	credentialID := ""
+	if methodType == mfaMethodTypeWebAuthn {
+		credentialID = d.Get("credential_id").(string)
+	}
+	if entity.MFASecrets != nil && credentialID != "" {
+		secret := entity.MFASecrets[mConfig.ID].GetWebAuthnSecret()
+		if secret == nil {
+			return logical.ErrorResponse("entity has no WebAuthn credentials for the method"), nil
+		}
+		var credentials []*mfa.WebAuthnCredential
+		for _, credential := range secret.Credentials {
+			if base64.RawURLEncoding.EncodeToString(credential.ID) != strings.TrimRight(credentialID, "=") {
+				credentials = append(credentials, credential)
+			}
+		}
+		if len(credentials) == len(secret.Credentials) {
+			return logical.ErrorResponse("credential not found"), nil
+		}
+		secret.Credentials = credentials
+		if len(credentials) == 0 {
+			delete(entity.MFASecrets, mConfig.ID)
+		}
+	} else if entity.MFASecrets != nil {
 		delete(entity.MFASecrets, mConfig.ID)
 	}
 
@@ -709,7 +769,7 @@ func (b *LoginMFABackend) handleMFALoginValidate(ctx context.Context, req *logic
 	}
 
 	for _, eConfig := range matchedMfaEnforcementList {
-		err = b.Core.validateLoginMFA(ctx, eConfig, entity, req.Connection.RemoteAddr, mfaCreds)
+		err = b.Core.validateLoginMFA(ctx, eConfig, entity, req.Connection.RemoteAddr, mfaCreds, cachedResponseAuth)
 		if err != nil {
 			return logical.ErrorResponse(fmt.Sprintf("failed to satisfy enforcement %s. error: %s", eConfig.Name, err.Error())), logical.ErrPermissionDenied
 		}
@@ -1416,6 +1476,15 @@ func (b *MFABackend) mfaConfigToMap(mConfig *mfa.Config) (map[string]interface{}
 		respData["org_alias"] = pingConfig.OrgAlias
 		respData["admin_url"] = pingConfig.AdminURL
 		respData["authenticator_url"] = pingConfig.AuthenticatorURL
+	case *mfa.Config_WebAuthnConfig:
+		webAuthnConfig := mConfig.GetWebAuthnConfig()
+		respData["rp_id"] = webAuthnConfig.RpID
+		respData["rp_name"] = webAuthnConfig.RpName
+		respData["allowed_origins"] = append([]string{}, webAuthnConfig.AllowedOrigins...)
+		respData["attestation"] = webAuthnConfig.Attestation
+		respData["attestation_ca_pem"] = webAuthnConfig.AttestationCaPem
+		respData["user_verification"] = webAuthnConfig.UserVerification
+		respData["timeout"] = webAuthnConfig.Timeout
 	default:
 		return nil, fmt.Errorf("invalid method type %q was persisted, underlying type: %T", mConfig.Type, mConfig.Config)
 	}
@@ -1573,7 +1642,9 @@ func parseOktaConfig(mConfig *mfa.Config, d *framework.FieldData) error {
 	return nil
 }
 
-func (c *Core) validateLoginMFA(ctx context.Context, eConfig *mfa.MFAEnforcementConfig, entity *identity.Entity, requestConnRemoteAddr string, mfaCredsMap logical.MFACreds) error {
+// validateLoginMFA validates the MFA credentials against the enforcement. The
+// cached auth response is nil for single-phase login MFA.
+func (c *Core) validateLoginMFA(ctx context.Context, eConfig *mfa.MFAEnforcementConfig, entity *identity.Entity, requestConnRemoteAddr string, mfaCredsMap logical.MFACreds, cachedAuth *MFACachedAuthResponse) error {
 	var retErr error
 	for _, methodID := range eConfig.MFAMethodIDs {
 		// as configID is the same as methodID, and methodID is unique, we can
@@ -1583,7 +1654,7 @@ func (c *Core) validateLoginMFA(ctx context.Context, eConfig *mfa.MFAEnforcement
 			continue
 		}
 
-		err := c.validateLoginMFAInternal(ctx, methodID, entity, requestConnRemoteAddr, mfaCreds)
+		err := c.validateLoginMFAInternal(ctx, methodID, entity, requestConnRemoteAddr, mfaCreds, cachedAuth)
 		if err != nil {
 			retErr = multierror.Append(retErr, err)
 			continue
@@ -1594,7 +1665,7 @@ func (c *Core) validateLoginMFA(ctx context.Context, eConfig *mfa.MFAEnforcement
 	return multierror.Append(retErr, fmt.Errorf("login MFA validation failed for methodID: %v", eConfig.MFAMethodIDs))
 }
 
-func (c *Core) validateLoginMFAInternal(ctx context.Context, methodID string, entity *identity.Entity, reqConnectionRemoteAddress string, mfaCreds []string) (retErr error) {
+func (c *Core) validateLoginMFAInternal(ctx context.Context, methodID string, entity *identity.Entity, reqConnectionRemoteAddress string, mfaCreds []string, cachedAuth *MFACachedAuthResponse) (retErr error) {
 	if entity == nil {
 		return fmt.Errorf("entity is nil")
 	}
@@ -1660,6 +1731,9 @@ func (c *Core) validateLoginMFAInternal(ctx context.Context, methodID string, en
 	case mfaMethodTypePingID:
 		return c.validatePingID(ctx, mConfig, finalUsername)
 
+	case mfaMethodTypeWebAuthn:
+		return c.validateWebAuthn(ctx, mfaCreds, mConfig, entity, cachedAuth)
+
 	default:
 		return fmt.Errorf("unrecognized MFA type %q", mConfig.Type)
 	}
@@ -0,0 +1,904 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/hashicorp/vault/helper/identity"
+	"github.com/hashicorp/vault/helper/identity/mfa"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	webAuthnAttestationNone   = "none"
+	webAuthnAttestationDirect = "direct"
+
+	webAuthnUserVerificationRequired    = "required"
+	webAuthnUserVerificationPreferred   = "preferred"
+	webAuthnUserVerificationDiscouraged = "discouraged"
+
+	webAuthnDefaultTimeout = 60 * time.Second
+
+	// webAuthnRegistrationPrefix is the prefix of the registration challenges
+	// kept in the usedCodes cache until the credential is registered
+	webAuthnRegistrationPrefix = "webauthn_registration_"
+
+	// COSE algorithm identifiers of the supported credential public keys
+	coseAlgES256 = -7
+	coseAlgEdDSA = -8
+	coseAlgRS256 = -257
+
+	webAuthnFlagUserPresent            = 0x01
+	webAuthnFlagUserVerified           = 0x04
+	webAuthnFlagAttestedCredentialData = 0x40
+)
+
+var webAuthnSupportedAlgs = []int64{coseAlgES256, coseAlgEdDSA, coseAlgRS256}
+
+// webAuthnRegistration is the state of a credential registration that has
+// been started for an entity.
+type webAuthnRegistration struct {
+	methodID string
+	entityID string
+}
+
+// webAuthnCredentialJSON is the JSON serialization of a PublicKeyCredential,
+// as returned by PublicKeyCredential.toJSON() in browsers. Binary values are
+// base64url encoded. Registrations carry an attestation object while
+// assertions carry authenticator data and a signature.
+type webAuthnCredentialJSON struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AttestationObject string `json:"attestationObject"`
+		AuthenticatorData string `json:"authenticatorData"`
+		Signature         string `json:"signature"`
+		UserHandle        string `json:"userHandle"`
+	} `json:"response"`
+}
+
+type webAuthnClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+type webAuthnAttestationObject struct {
+	Format   string          `cbor:"fmt"`
+	AttStmt  cbor.RawMessage `cbor:"attStmt"`
+	AuthData []byte          `cbor:"authData"`
+}
+
+type webAuthnAttestationStatement struct {
+	Alg int64    `cbor:"alg"`
+	Sig []byte   `cbor:"sig"`
+	X5c [][]byte `cbor:"x5c"`
+}
+
+// webAuthnAuthenticatorData is the parsed authenticator data of a
+// registration or an assertion. The credential fields are only set if the
+// attested credential data flag is set.
+type webAuthnAuthenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte
+}
+
+func parseWebAuthnConfig(mConfig *mfa.Config, d *framework.FieldData) error {
+	rpID := d.Get("rp_id").(string)
+	if rpID == "" {
+		return fmt.Errorf("rp_id is empty")
+	}
+
+	allowedOrigins := d.Get("allowed_origins").([]string)
+	if len(allowedOrigins) == 0 {
+		return fmt.Errorf("allowed_origins is empty")
+	}
+	for _, origin := range allowedOrigins {
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			return fmt.Errorf("invalid origin %q", origin)
+		}
+	}
+
+	attestation := d.Get("attestation").(string)
+	attestationCAPEM := d.Get("attestation_ca_pem").(string)
+	switch attestation {
+	case webAuthnAttestationNone:
+	case webAuthnAttestationDirect:
+		if attestationCAPEM == "" {
+			return fmt.Errorf("attestation_ca_pem must be set for %q attestation", webAuthnAttestationDirect)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(attestationCAPEM)) {
+			return fmt.Errorf("attestation_ca_pem does not contain any certificates")
+		}
+	default:
+		return fmt.Errorf("attestation must be %q or %q", webAuthnAttestationNone, webAuthnAttestationDirect)
+	}
+
+	userVerification := d.Get("user_verification").(string)
+	switch userVerification {
+	case webAuthnUserVerificationRequired, webAuthnUserVerificationPreferred, webAuthnUserVerificationDiscouraged:
+	default:
+		return fmt.Errorf("user_verification must be %q, %q or %q", webAuthnUserVerificationRequired,
+			webAuthnUserVerificationPreferred, webAuthnUserVerificationDiscouraged)
+	}
+
+	timeout := d.Get("timeout").(int)
+	if timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than zero")
+	}
+
+	mConfig.Config = &mfa.Config_WebAuthnConfig{
+		WebAuthnConfig: &mfa.WebAuthnConfig{
+			RpID:             rpID,
+			RpName:           d.Get("rp_name").(string),
+			AllowedOrigins:   allowedOrigins,
+			Attestation:      attestation,
+			AttestationCaPem: attestationCAPEM,
+			UserVerification: userVerification,
+			Timeout:          uint32(timeout),
+		},
+	}
+
+	return nil
+}
+
+func (i *IdentityStore) handleMFAMethodListWebAuthn(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return i.handleMFAMethodList(ctx, req, d, mfaMethodTypeWebAuthn)
+}
+
+func (i *IdentityStore) handleMFAMethodWebAuthnRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return i.handleMFAMethodReadCommon(ctx, req, d, mfaMethodTypeWebAuthn)
+}
+
+func (i *IdentityStore) handleMFAMethodWebAuthnUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return i.handleMFAMethodUpdateCommon(ctx, req, d, mfaMethodTypeWebAuthn)
+}
+
+func (i *IdentityStore) handleMFAMethodWebAuthnDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return i.handleMFAMethodDeleteCommon(ctx, req, d, mfaMethodTypeWebAuthn)
+}
+
+// handleLoginMFAWebAuthnRegisterUpdate registers a WebAuthn credential for
+// the entity of the request. Without a credential, it starts a registration
+// and returns the options to pass to navigator.credentials.create(). The
+// credential it creates is then sent back to complete the registration.
+func (i *IdentityStore) handleLoginMFAWebAuthnRegisterUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	methodID := d.Get("method_id").(string)
+	if methodID == "" {
+		return logical.ErrorResponse("missing method ID"), nil
+	}
+
+	if req.EntityID == "" {
+		return logical.ErrorResponse("missing entityID"), nil
+	}
+
+	mConfig, err := i.mfaBackend.MemDBMFAConfigByID(methodID)
+	if err != nil {
+		return nil, err
+	}
+	if mConfig == nil {
+		return logical.ErrorResponse(fmt.Sprintf("configuration for method ID %q does not exist", methodID)), nil
+	}
+	if mConfig.Type != mfaMethodTypeWebAuthn {
+		return logical.ErrorResponse("method ID does not match WebAuthn type"), nil
+	}
+	webAuthnConfig := mConfig.GetWebAuthnConfig()
+	if webAuthnConfig == nil {
+		return nil, fmt.Errorf("configuration for method ID %q is not a WebAuthn configuration", methodID)
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := i.MemDBEntityByID(req.EntityID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find entity with ID %q: error: %w", req.EntityID, err)
+	}
+	if entity == nil {
+		return logical.ErrorResponse("invalid entity ID"), nil
+	}
+	if ns.ID != entity.NamespaceID {
+		return logical.ErrorResponse("entity namespace ID does not match the current namespace ID"), nil
+	}
+
+	configNS, err := i.namespacer.NamespaceByID(ctx, mConfig.NamespaceID)
+	if err != nil {
+		return logical.ErrorResponse("methodID namespace not found"), nil
+	}
+	if configNS.ID != ns.ID && !ns.HasParent(configNS) {
+		return logical.ErrorResponse(fmt.Sprintf("entity namespace %s outside of the config namespace %s", ns.Path, configNS.Path)), nil
+	}
+
+	usedCodes := i.mfaBackend.usedCodes
+	credentialRaw := d.Get("credential").(string)
+	if credentialRaw == "" {
+		challenge := make([]byte, 32)
+		if _, err := i.mfaBackend.Core.secureRandomReader.Read(challenge); err != nil {
+			return nil, err
+		}
+		encodedChallenge := base64.RawURLEncoding.EncodeToString(challenge)
+
+		timeout := time.Duration(webAuthnConfig.Timeout) * time.Second
+		usedCodes.Set(webAuthnRegistrationPrefix+encodedChallenge, &webAuthnRegistration{
+			methodID: mConfig.ID,
+			entityID: entity.ID,
+		}, timeout)
+
+		excludeCredentials := []map[string]interface{}{}
+		for _, credential := range webAuthnCredentials(entity, mConfig.ID) {
+			excludeCredentials = append(excludeCredentials, map[string]interface{}{
+				"type": "public-key",
+				"id":   base64.RawURLEncoding.EncodeToString(credential.ID),
+			})
+		}
+
+		pubKeyCredParams := []map[string]interface{}{}
+		for _, alg := range webAuthnSupportedAlgs {
+			pubKeyCredParams = append(pubKeyCredParams, map[string]interface{}{
+				"type": "public-key",
+				"alg":  alg,
+			})
+		}
+
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"options": map[string]interface{}{
+					"challenge": encodedChallenge,
+					"rp": map[string]interface{}{
+						"id":   webAuthnConfig.RpID,
+						"name": webAuthnConfig.RpName,
+					},
+					"user": map[string]interface{}{
+						"id":          base64.RawURLEncoding.EncodeToString([]byte(entity.ID)),
+						"name":        entity.Name,
+						"displayName": entity.Name,
+					},
+					"pubKeyCredParams":   pubKeyCredParams,
+					"timeout":            timeout.Milliseconds(),
+					"attestation":        webAuthnConfig.Attestation,
+					"excludeCredentials": excludeCredentials,
+					"authenticatorSelection": map[string]interface{}{
+						"userVerification": webAuthnConfig.UserVerification,
+					},
+				},
+			},
+		}, nil
+	}
+
+	var credentialJSON webAuthnCredentialJSON
+	if err := json.Unmarshal([]byte(credentialRaw), &credentialJSON); err != nil {
+		return logical.ErrorResponse("failed to parse credential: %s", err), nil
+	}
+
+	clientDataJSON, clientData, err := parseWebAuthnClientData(credentialJSON.Response.ClientDataJSON, "webauthn.create", webAuthnConfig)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	// The challenge must have been issued to the entity for the method. It
+	// can only be used once.
+	registrationKey := webAuthnRegistrationPrefix + clientData.Challenge
+	registrationRaw, ok := usedCodes.Get(registrationKey)
+	registration, _ := registrationRaw.(*webAuthnRegistration)
+	if !ok || registration == nil || registration.methodID != mConfig.ID || registration.entityID != entity.ID {
+		return logical.ErrorResponse("registration challenge is invalid or expired"), nil
+	}
+	usedCodes.Delete(registrationKey)
+
+	credential, err := verifyWebAuthnRegistration(&credentialJSON, clientDataJSON, webAuthnConfig)
+	if err != nil {
+		return logical.ErrorResponse("failed to verify credential: %s", err), nil
+	}
+	credential.Name = d.Get("name").(string)
+	credential.CreationTime = time.Now().Unix()
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	// Read the entity after acquiring the lock
+	entity, err = i.MemDBEntityByID(entity.ID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find entity with ID %q: error: %w", req.EntityID, err)
+	}
+	if entity == nil {
+		return logical.ErrorResponse("invalid entity ID"), nil
+	}
+
+	if entity.MFASecrets == nil {
+		entity.MFASecrets = make(map[string]*mfa.Secret)
+	}
+	secret := entity.MFASecrets[mConfig.ID].GetWebAuthnSecret()
+	if secret == nil {
+		secret = &mfa.WebAuthnSecret{}
+		entity.MFASecrets[mConfig.ID] = &mfa.Secret{
+			MethodName: mConfig.Name,
+			Value: &mfa.Secret_WebAuthnSecret{
+				WebAuthnSecret: secret,
+			},
+		}
+	}
+	for _, existing := range secret.Credentials {
+		if bytes.Equal(existing.ID, credential.ID) {
+			return logical.ErrorResponse("credential is already registered"), nil
+		}
+	}
+	secret.Credentials = append(secret.Credentials, credential)
+
+	if err := i.upsertEntity(ctx, entity, nil, true); err != nil {
+		return nil, fmt.Errorf("failed to persist MFA secret in entity, error: %w", err)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"credential_id":      base64.RawURLEncoding.EncodeToString(credential.ID),
+			"attestation_format": credential.AttestationFormat,
+		},
+	}, nil
+}
+
+// handleMFAWebAuthnOptions returns the options to pass to
+// navigator.credentials.get() to validate a login with a WebAuthn method. A
+// random challenge is generated on each call and stored with the MFA request,
+// replacing any previous challenge of the method. The request ID is only
+// handed out once the primary authentication succeeded, so the allowed
+// credentials are not disclosed to unauthenticated callers.
+func (b *LoginMFABackend) handleMFAWebAuthnOptions(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	mfaReqID := d.Get("mfa_request_id").(string)
+	if mfaReqID == "" {
+		return logical.ErrorResponse("missing request ID"), nil
+	}
+
+	methodID := d.Get("method_id").(string)
+	if methodID == "" {
+		return logical.ErrorResponse("missing method ID"), nil
+	}
+
+	cachedResponseAuth, err := b.Core.PeekMFAResponseAuthByID(mfaReqID)
+	if err != nil || cachedResponseAuth == nil {
+		return logical.ErrorResponse("invalid request ID"), nil
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ns.ID != cachedResponseAuth.RequestNSID {
+		return logical.ErrorResponse("original request was issued in a different namespace"), nil
+	}
+
+	mConfig, err := b.MemDBMFAConfigByID(methodID)
+	if err != nil {
+		return nil, err
+	}
+	if mConfig == nil || mConfig.Type != mfaMethodTypeWebAuthn {
+		return logical.ErrorResponse("invalid WebAuthn method ID"), nil
+	}
+	webAuthnConfig := mConfig.GetWebAuthnConfig()
+
+	entity, err := b.Core.identityStore.MemDBEntityByID(cachedResponseAuth.CachedAuth.EntityID, false)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil {
+		return logical.ErrorResponse("entity not found"), nil
+	}
+
+	allowCredentials := []map[string]interface{}{}
+	for _, credential := range webAuthnCredentials(entity, mConfig.ID) {
+		allowCredentials = append(allowCredentials, map[string]interface{}{
+			"type": "public-key",
+			"id":   base64.RawURLEncoding.EncodeToString(credential.ID),
+		})
+	}
+
+	challenge := make([]byte, 32)
+	if _, err := b.Core.secureRandomReader.Read(challenge); err != nil {
+		return nil, fmt.Errorf("failed to generate challenge: %w", err)
+	}
+	encodedChallenge := base64.RawURLEncoding.EncodeToString(challenge)
+	if err := b.Core.setMFAWebAuthnChallenge(mfaReqID, mConfig.ID, encodedChallenge); err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"options": map[string]interface{}{
+				"challenge":        encodedChallenge,
+				"rpId":             webAuthnConfig.RpID,
+				"allowCredentials": allowCredentials,
+				"userVerification": webAuthnConfig.UserVerification,
+				"timeout":          (time.Duration(webAuthnConfig.Timeout) * time.Second).Milliseconds(),
+			},
+		},
+	}, nil
+}
+
+// PeekMFAResponseAuthByID returns the cached auth response of the given ID
+// without removing it from the mfaResponseAuthQueue.
+func (c *Core) PeekMFAResponseAuthByID(reqID string) (*MFACachedAuthResponse, error) {
+	c.mfaResponseAuthQueueLock.Lock()
+	defer c.mfaResponseAuthQueueLock.Unlock()
+
+	respAuth, err := c.mfaResponseAuthQueue.PopByKey(reqID)
+	if err != nil || respAuth == nil {
+		return nil, err
+	}
+	if err := c.mfaResponseAuthQueue.Push(respAuth); err != nil {
+		return nil, err
+	}
+
+	return respAuth, nil
+}
+
+// setMFAWebAuthnChallenge stores the challenge of the WebAuthn method with
+// the cached auth response of the given ID. The entry is updated under the
+// queue lock since it may be popped concurrently by mfa/validate.
+func (c *Core) setMFAWebAuthnChallenge(reqID, methodID, challenge string) error {
+	c.mfaResponseAuthQueueLock.Lock()
+	defer c.mfaResponseAuthQueueLock.Unlock()
+
+	respAuth, err := c.mfaResponseAuthQueue.PopByKey(reqID)
+	if err != nil || respAuth == nil {
+		return fmt.Errorf("invalid request ID")
+	}
+	if respAuth.WebAuthnChallenges == nil {
+		respAuth.WebAuthnChallenges = make(map[string]string)
+	}
+	respAuth.WebAuthnChallenges[methodID] = challenge
+
+	return c.mfaResponseAuthQueue.Push(respAuth)
+}
+
+// validateWebAuthn validates a WebAuthn assertion for the MFA request against
+// the credentials registered by the entity, and updates the signature counter
+// of the credential that was used. The challenge issued for the method is
+// consumed, so a new one must be requested for another attempt.
+func (c *Core) validateWebAuthn(ctx context.Context, creds []string, mConfig *mfa.Config, entity *identity.Entity, cachedAuth *MFACachedAuthResponse) error {
+	if cachedAuth == nil {
+		return fmt.Errorf("WebAuthn can only be used with the mfa/validate endpoint")
+	}
+
+	// The cached auth response was popped from the queue by mfa/validate,
+	// so it is not modified concurrently
+	expectedChallenge, ok := cachedAuth.WebAuthnChallenges[mConfig.ID]
+	if !ok {
+		return fmt.Errorf("no WebAuthn challenge was issued for the MFA request")
+	}
+	delete(cachedAuth.WebAuthnChallenges, mConfig.ID)
+
+	if len(creds) == 0 {
+		return fmt.Errorf("missing WebAuthn assertion")
+	}
+	if len(creds) > 1 {
+		return fmt.Errorf("more than one WebAuthn assertion supplied")
+	}
+
+	webAuthnConfig := mConfig.GetWebAuthnConfig()
+	if webAuthnConfig == nil {
+		return fmt.Errorf("invalid MFA configuration type")
+	}
+
+	var assertion webAuthnCredentialJSON
+	if err := json.Unmarshal([]byte(creds[0]), &assertion); err != nil {
+		return fmt.Errorf("failed to parse WebAuthn assertion: %w", err)
+	}
+
+	credentialID, err := decodeWebAuthnBase64(assertion.RawID)
+	if err != nil {
+		return fmt.Errorf("invalid credential ID: %w", err)
+	}
+
+	var credential *mfa.WebAuthnCredential
+	for _, registered := range webAuthnCredentials(entity, mConfig.ID) {
+		if bytes.Equal(registered.ID, credentialID) {
+			credential = registered
+			break
+		}
+	}
+	if credential == nil {
+		return fmt.Errorf("credential is not registered for the entity")
+	}
+
+	if assertion.Response.UserHandle != "" {
+		userHandle, err := decodeWebAuthnBase64(assertion.Response.UserHandle)
+		if err != nil || string(userHandle) != entity.ID {
+			return fmt.Errorf("user handle does not match the entity")
+		}
+	}
+
+	clientDataJSON, clientData, err := parseWebAuthnClientData(assertion.Response.ClientDataJSON, "webauthn.get", webAuthnConfig)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(clientData.Challenge), []byte(expectedChallenge)) != 1 {
+		return fmt.Errorf("challenge does not match the MFA request")
+	}
+
+	rawAuthData, err := decodeWebAuthnBase64(assertion.Response.AuthenticatorData)
+	if err != nil {
+		return fmt.Errorf("invalid authenticator data: %w", err)
+	}
+	authData, err := parseWebAuthnAuthenticatorData(rawAuthData)
+	if err != nil {
+		return err
+	}
+	if err := verifyWebAuthnAuthenticatorData(authData, webAuthnConfig); err != nil {
+		return err
+	}
+
+	signature, err := decodeWebAuthnBase64(assertion.Response.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	alg, publicKey, err := parseCOSEKey(credential.PublicKey)
+	if err != nil {
+		return err
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err := verifyWebAuthnSignature(alg, publicKey, append(rawAuthData, clientDataHash[:]...), signature); err != nil {
+		return err
+	}
+
+	// Authenticators that don't implement a signature counter always return
+	// zero. Otherwise, the counter must increase, or the authenticator may
+	// have been cloned.
+	if authData.signCount == 0 && credential.SignCount == 0 {
+		return nil
+	}
+	if authData.signCount <= credential.SignCount {
+		return fmt.Errorf("signature counter did not increase, the authenticator may have been cloned")
+	}
+
+	c.identityStore.lock.Lock()
+	defer c.identityStore.lock.Unlock()
+
+	storedEntity, err := c.identityStore.MemDBEntityByID(entity.ID, true)
+	if err != nil {
+		return err
+	}
+	if storedEntity == nil {
+		return fmt.Errorf("entity not found")
+	}
+	for _, stored := range webAuthnCredentials(storedEntity, mConfig.ID) {
+		if bytes.Equal(stored.ID, credential.ID) {
+			// Check against the stored counter as well in case of a
+			// concurrent login with a clone
+			if authData.signCount <= stored.SignCount {
+				return fmt.Errorf("signature counter did not increase, the authenticator may have been cloned")
+			}
+			stored.SignCount = authData.signCount
+		}
+	}
+
+	return c.identityStore.upsertEntity(ctx, storedEntity, nil, true)
+}
+
+// webAuthnCredentials returns the WebAuthn credentials registered by the
+// entity for the MFA method.
+func webAuthnCredentials(entity *identity.Entity, methodID string) []*mfa.WebAuthnCredential {
+	if entity == nil || entity.MFASecrets == nil {
+		return nil
+	}
+	return entity.MFASecrets[methodID].GetWebAuthnSecret().GetCredentials()
+}
+
+// verifyWebAuthnRegistration verifies the attestation of a new credential and
+// returns the credential to store.
+func verifyWebAuthnRegistration(credentialJSON *webAuthnCredentialJSON, clientDataJSON []byte, config *mfa.WebAuthnConfig) (*mfa.WebAuthnCredential, error) {
+	rawAttestationObject, err := decodeWebAuthnBase64(credentialJSON.Response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attestation object: %w", err)
+	}
+
+	var attestationObject webAuthnAttestationObject
+	if err := cbor.Unmarshal(rawAttestationObject, &attestationObject); err != nil {
+		return nil, fmt.Errorf("invalid attestation object: %w", err)
+	}
+
+	authData, err := parseWebAuthnAuthenticatorData(attestationObject.AuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyWebAuthnAuthenticatorData(authData, config); err != nil {
+		return nil, err
+	}
+	if authData.flags&webAuthnFlagAttestedCredentialData == 0 {
+		return nil, fmt.Errorf("authenticator data does not contain a credential")
+	}
+
+	if credentialJSON.RawID != "" {
+		rawID, err := decodeWebAuthnBase64(credentialJSON.RawID)
+		if err != nil || !bytes.Equal(rawID, authData.credentialID) {
+			return nil, fmt.Errorf("credential ID does not match the authenticator data")
+		}
+	}
+
+	alg, publicKey, err := parseCOSEKey(authData.publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if config.Attestation == webAuthnAttestationDirect {
+		if err := verifyWebAuthnAttestation(&attestationObject, authData, alg, publicKey, clientDataHash[:], config); err != nil {
+			return nil, err
+		}
+	}
+
+	return &mfa.WebAuthnCredential{
+		ID:                authData.credentialID,
+		PublicKey:         authData.publicKey,
+		SignCount:         authData.signCount,
+		AttestationFormat: attestationObject.Format,
+		Aaguid:            authData.aaguid,
+	}, nil
+}
+
+// verifyWebAuthnAttestation verifies that the attestation statement was
+// signed by an authenticator whose attestation certificate chains to one of
+// the configured CAs. The packed and fido-u2f formats are supported.
+func verifyWebAuthnAttestation(attestationObject *webAuthnAttestationObject, authData *webAuthnAuthenticatorData, credentialAlg int64, credentialKey crypto.PublicKey, clientDataHash []byte, config *mfa.WebAuthnConfig) error {
+	var stmt webAuthnAttestationStatement
+	if err := cbor.Unmarshal(attestationObject.AttStmt, &stmt); err != nil {
+		return fmt.Errorf("invalid attestation statement: %w", err)
+	}
+	if len(stmt.X5c) == 0 {
+		return fmt.Errorf("attestation statement of format %q does not contain a certificate", attestationObject.Format)
+	}
+
+	certs := make([]*x509.Certificate, 0, len(stmt.X5c))
+	for _, der := range stmt.X5c {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return fmt.Errorf("invalid attestation certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM([]byte(config.AttestationCaPem))
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("attestation certificate is not trusted: %w", err)
+	}
+
+	switch attestationObject.Format {
+	case "packed":
+		signed := append(append([]byte{}, attestationObject.AuthData...), clientDataHash...)
+		return verifyWebAuthnSignature(stmt.Alg, certs[0].PublicKey, signed, stmt.Sig)
+
+	case "fido-u2f":
+		ecKey, ok := credentialKey.(*ecdsa.PublicKey)
+		if !ok || credentialAlg != coseAlgES256 {
+			return fmt.Errorf("fido-u2f credential must be an ES256 key")
+		}
+		// The signed data is defined in section 8.6 of the WebAuthn spec
+		signed := []byte{0x00}
+		signed = append(signed, authData.rpIDHash...)
+		signed = append(signed, clientDataHash...)
+		signed = append(signed, authData.credentialID...)
+		signed = append(signed, elliptic.Marshal(ecKey.Curve, ecKey.X, ecKey.Y)...)
+		return verifyWebAuthnSignature(coseAlgES256, certs[0].PublicKey, signed, stmt.Sig)
+
+	default:
+		return fmt.Errorf("unsupported attestation format %q", attestationObject.Format)
+	}
+}
+
+// parseWebAuthnClientData decodes the client data and verifies its type and
+// origin. The raw client data is returned as it is hashed for signatures.
+func parseWebAuthnClientData(encoded, expectedType string, config *mfa.WebAuthnConfig) ([]byte, *webAuthnClientData, error) {
+	clientDataJSON, err := decodeWebAuthnBase64(encoded)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid client data: %w", err)
+	}
+
+	var clientData webAuthnClientData
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return nil, nil, fmt.Errorf("invalid client data: %w", err)
+	}
+	if clientData.Type != expectedType {
+		return nil, nil, fmt.Errorf("client data type must be %q", expectedType)
+	}
+	if !strutil.StrListContains(config.AllowedOrigins, strings.TrimSuffix(clientData.Origin, "/")) &&
+		!strutil.StrListContains(config.AllowedOrigins, clientData.Origin) {
+		return nil, nil, fmt.Errorf("origin %q is not allowed", clientData.Origin)
+	}
+
+	return clientDataJSON, &clientData, nil
+}
+
+func parseWebAuthnAuthenticatorData(data []byte) (*webAuthnAuthenticatorData, error) {
+	if len(data) < 37 {
+		return nil, fmt.Errorf("authenticator data is too short")
+	}
+
+	authData := &webAuthnAuthenticatorData{
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	if authData.flags&webAuthnFlagAttestedCredentialData == 0 {
+		return authData, nil
+	}
+
+	rest := data[37:]
+	if len(rest) < 18 {
+		return nil, fmt.Errorf("attested credential data is too short")
+	}
+	authData.aaguid = rest[:16]
+	idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if len(rest) < idLength {
+		return nil, fmt.Errorf("attested credential data is too short")
+	}
+	authData.credentialID = rest[:idLength]
+	rest = rest[idLength:]
+
+	// The public key is followed by extensions if the extension data flag is
+	// set, so only the first CBOR item is read
+	var publicKey cbor.RawMessage
+	decoder := cbor.NewDecoder(bytes.NewReader(rest))
+	if err := decoder.Decode(&publicKey); err != nil {
+		return nil, fmt.Errorf("invalid credential public key: %w", err)
+	}
+	authData.publicKey = rest[:decoder.NumBytesRead()]
+
+	return authData, nil
+}
+
+func verifyWebAuthnAuthenticatorData(authData *webAuthnAuthenticatorData, config *mfa.WebAuthnConfig) error {
+	rpIDHash := sha256.Sum256([]byte(config.RpID))
+	if !bytes.Equal(authData.rpIDHash, rpIDHash[:]) {
+		return fmt.Errorf("relying party ID does not match")
+	}
+	if authData.flags&webAuthnFlagUserPresent == 0 {
+		return fmt.Errorf("user was not present")
+	}
+	if config.UserVerification == webAuthnUserVerificationRequired && authData.flags&webAuthnFlagUserVerified == 0 {
+		return fmt.Errorf("user was not verified")
+	}
+	return nil
+}
+
+// parseCOSEKey parses a credential public key in COSE_Key format, as defined
+// in RFC 8152 section 7, and returns its algorithm.
+func parseCOSEKey(data []byte) (int64, crypto.PublicKey, error) {
+	var key map[int64]interface{}
+	if err := cbor.Unmarshal(data, &key); err != nil {
+		return 0, nil, fmt.Errorf("invalid credential public key: %w", err)
+	}
+
+	intParam := func(label int64) (int64, bool) {
+		switch v := key[label].(type) {
+		case int64:
+			return v, true
+		case uint64:
+			return int64(v), true
+		}
+		return 0, false
+	}
+	bytesParam := func(label int64) []byte {
+		v, _ := key[label].([]byte)
+		return v
+	}
+
+	kty, _ := intParam(1)
+	alg, ok := intParam(3)
+	if !ok {
+		return 0, nil, fmt.Errorf("credential public key has no algorithm")
+	}
+
+	switch alg {
+	case coseAlgES256:
+		crv, _ := intParam(-1)
+		x, y := bytesParam(-2), bytesParam(-3)
+		if kty != 2 || crv != 1 || len(x) != 32 || len(y) != 32 {
+			return 0, nil, fmt.Errorf("invalid ES256 credential public key")
+		}
+		publicKey := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !publicKey.Curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return 0, nil, fmt.Errorf("invalid ES256 credential public key")
+		}
+		return alg, publicKey, nil
+
+	case coseAlgRS256:
+		n, e := bytesParam(-1), bytesParam(-2)
+		if kty != 3 || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return 0, nil, fmt.Errorf("invalid RS256 credential public key")
+		}
+		return alg, &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+
+	case coseAlgEdDSA:
+		crv, _ := intParam(-1)
+		x := bytesParam(-2)
+		if kty != 1 || crv != 6 || len(x) != ed25519.PublicKeySize {
+			return 0, nil, fmt.Errorf("invalid EdDSA credential public key")
+		}
+		return alg, ed25519.PublicKey(x), nil
+
+	default:
+		return 0, nil, fmt.Errorf("unsupported credential public key algorithm %d", alg)
+	}
+}
+
+func verifyWebAuthnSignature(alg int64, publicKey crypto.PublicKey, data, signature []byte) error {
+	errInvalidSignature := errors.New("invalid signature")
+	switch alg {
+	case coseAlgES256:
+		key, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return errInvalidSignature
+		}
+		digest := sha256.Sum256(data)
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return errInvalidSignature
+		}
+	case coseAlgRS256:
+		key, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return errInvalidSignature
+		}
+		digest := sha256.Sum256(data)
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return errInvalidSignature
+		}
+	case coseAlgEdDSA:
+		key, ok := publicKey.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(key, data, signature) {
+			return errInvalidSignature
+		}
+	default:
+		return fmt.Errorf("unsupported signature algorithm %d", alg)
+	}
+	return nil
+}
+
+// decodeWebAuthnBase64 decodes base64url values, with or without padding.
+func decodeWebAuthnBase64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -364,6 +364,13 @@ type UserLockoutConfig struct {
 	LockoutDuration     time.Duration `json:"lockout_duration,omitempty" structs:"lockout_duration" mapstructure:"lockout_duration"`
 	LockoutCounterReset time.Duration `json:"lockout_counter_reset,omitempty" structs:"lockout_counter_reset" mapstructure:"lockout_counter_reset"`
 	DisableLockout      bool          `json:"disable_lockout,omitempty" structs:"disable_lockout" mapstructure:"disable_lockout"`
+
+	// ProgressiveDelay, when set, delays login attempts exponentially
+	// instead of locking the user out. A zero base or max delay falls back
+	// to the server configuration.
+	ProgressiveDelay     bool          `json:"progressive_delay,omitempty" structs:"progressive_delay" mapstructure:"progressive_delay"`
+	ProgressiveDelayBase time.Duration `json:"progressive_delay_base,omitempty" structs:"progressive_delay_base" mapstructure:"progressive_delay_base"`
+	ProgressiveDelayMax  time.Duration `json:"progressive_delay_max,omitempty" structs:"progressive_delay_max" mapstructure:"progressive_delay_max"`
 }
 
 type APIUserLockoutConfig struct {
@@ -371,6 +378,9 @@ type APIUserLockoutConfig struct {
 	LockoutDuration             string `json:"lockout_duration,omitempty" structs:"lockout_duration" mapstructure:"lockout_duration"`
 	LockoutCounterResetDuration string `json:"lockout_counter_reset_duration,omitempty" structs:"lockout_counter_reset_duration" mapstructure:"lockout_counter_reset_duration"`
 	DisableLockout              *bool  `json:"lockout_disable,omitempty" structs:"lockout_disable" mapstructure:"lockout_disable"`
+	ProgressiveDelay            *bool  `json:"progressive_delay,omitempty" structs:"progressive_delay" mapstructure:"progressive_delay"`
+	ProgressiveDelayBase        string `json:"progressive_delay_base,omitempty" structs:"progressive_delay_base" mapstructure:"progressive_delay_base"`
+	ProgressiveDelayMax         string `json:"progressive_delay_max,omitempty" structs:"progressive_delay_max" mapstructure:"progressive_delay_max"`
 }
 
 // APIMountConfig is an embedded struct of api.MountConfigInput
@@ -399,9 +409,16 @@ type FailedLoginUser struct {
 	mountAccessor string
 }
 
+// FailedLoginSource identifies the failed login attempts made against an
+// auth mount from a single client address.
+type FailedLoginSource struct {
+	remoteAddr    string
+	mountAccessor string
+}
+
 type FailedLoginInfo struct {
 	count               uint
-	lastFailedLoginTime int
+	lastFailedLoginTime time.Time
 }
 
 // Clone returns a deep copy of the mount entry
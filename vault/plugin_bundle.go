@@ -0,0 +1,403 @@
+package vault
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	semver "github.com/hashicorp/go-version"
+	"github.com/hashicorp/vault/helper/versions"
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	// pluginBundleManifestName is the name of the manifest of a plugin bundle,
+	// describing the plugin binaries it contains
+	pluginBundleManifestName = "manifest.json"
+
+	// pluginBundleSignatureName is the name of the signature of the manifest
+	// of a plugin bundle
+	pluginBundleSignatureName = "manifest.json.sig"
+
+	// pluginBundleMaxManifestSize bounds the size of the manifest and of its
+	// signature, which are read in memory
+	pluginBundleMaxManifestSize = 1024 * 1024
+)
+
+// errPluginBundleInvalid is returned when a plugin bundle can't be installed
+// because of its contents
+var errPluginBundleInvalid = errors.New("invalid plugin bundle")
+
+// pluginBundleManifest describes the plugin binaries of a plugin bundle. It
+// holds the SHA-256 of each binary, so that signing it signs the binaries.
+type pluginBundleManifest struct {
+	Name     string                 `json:"name"`
+	Type     string                 `json:"type"`
+	Versions []*pluginBundleVersion `json:"versions"`
+}
+
+type pluginBundleVersion struct {
+	Version  string                `json:"version"`
+	Args     []string              `json:"args"`
+	Env      []string              `json:"env"`
+	Binaries []*pluginBundleBinary `json:"binaries"`
+}
+
+type pluginBundleBinary struct {
+	OS     string `json:"os"`
+	Arch   string `json:"arch"`
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// pluginBundleInstall is a version of the plugin of a bundle to unpack into
+// the plugin directory and register
+type pluginBundleInstall struct {
+	version string
+	args    []string
+	env     []string
+	path    string
+	command string
+	sha256  []byte
+}
+
+// loadPluginBundleTrustedKeys reads the PEM encoded public keys at the given
+// paths
+func loadPluginBundleTrustedKeys(paths []string) ([]crypto.PublicKey, error) {
+	keys := make([]crypto.PublicKey, 0, len(paths))
+	for _, keyPath := range paths {
+		keyPEM, err := os.ReadFile(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode(keyPEM)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM data found in %q", keyPath)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key in %q: %w", keyPath, err)
+		}
+		switch key.(type) {
+		case ed25519.PublicKey, *ecdsa.PublicKey, *rsa.PublicKey:
+		default:
+			return nil, fmt.Errorf("unsupported public key type %T in %q", key, keyPath)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// verifyPluginBundleSignature checks that the manifest of a plugin bundle is
+// signed by one of the trusted keys. ECDSA and RSA signatures are made over
+// the SHA-256 of the manifest.
+func verifyPluginBundleSignature(keys []crypto.PublicKey, manifest, signature []byte) error {
+	digest := sha256.Sum256(manifest)
+	for _, key := range keys {
+		switch key := key.(type) {
+		case ed25519.PublicKey:
+			if ed25519.Verify(key, manifest, signature) {
+				return nil
+			}
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(key, digest[:], signature) {
+				return nil
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: the manifest is not signed by a trusted key", errPluginBundleInvalid)
+}
+
+// walkPluginBundle calls fn with the name and the contents of each regular
+// file of the gzipped tarball of a plugin bundle
+func walkPluginBundle(bundlePath string, fn func(name string, r io.Reader) error) error {
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gzr, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errPluginBundleInvalid, err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s", errPluginBundleInvalid, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(path.Clean(strings.TrimPrefix(hdr.Name, "./")), tr); err != nil {
+			return err
+		}
+	}
+}
+
+// readPluginBundle verifies the signature of the manifest of the plugin bundle
+// at the given path and returns it, along with the versions of the plugin to
+// install on this platform
+func (c *Core) readPluginBundle(bundlePath string) (*pluginBundleManifest, consts.PluginType, []*pluginBundleInstall, error) {
+	var manifestBytes, signature []byte
+	err := walkPluginBundle(bundlePath, func(name string, r io.Reader) error {
+		var target *[]byte
+		switch name {
+		case pluginBundleManifestName:
+			target = &manifestBytes
+		case pluginBundleSignatureName:
+			target = &signature
+		default:
+			return nil
+		}
+		data, err := io.ReadAll(io.LimitReader(r, pluginBundleMaxManifestSize+1))
+		if err != nil {
+			return err
+		}
+		if len(data) > pluginBundleMaxManifestSize {
+			return fmt.Errorf("%w: %s is too large", errPluginBundleInvalid, name)
+		}
+		*target = data
+		return nil
+	})
+	if err != nil {
+		return nil, consts.PluginTypeUnknown, nil, err
+	}
+	if manifestBytes == nil || signature == nil {
+		return nil, consts.PluginTypeUnknown, nil, fmt.Errorf("%w: %s and %s are required", errPluginBundleInvalid, pluginBundleManifestName, pluginBundleSignatureName)
+	}
+
+	if err := verifyPluginBundleSignature(c.pluginBundleTrustedKeys, manifestBytes, signature); err != nil {
+		return nil, consts.PluginTypeUnknown, nil, err
+	}
+
+	var manifest pluginBundleManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, consts.PluginTypeUnknown, nil, fmt.Errorf("%w: failed to decode the manifest: %s", errPluginBundleInvalid, err)
+	}
+
+	if manifest.Name == "" || strings.ContainsAny(manifest.Name, `/\`) || strings.Contains(manifest.Name, "..") {
+		return nil, consts.PluginTypeUnknown, nil, fmt.Errorf("%w: invalid plugin name %q", errPluginBundleInvalid, manifest.Name)
+	}
+	pluginType, err := consts.ParsePluginType(manifest.Type)
+	if err != nil || pluginType == consts.PluginTypeUnknown {
+		return nil, consts.PluginTypeUnknown, nil, fmt.Errorf("%w: invalid plugin type %q", errPluginBundleInvalid, manifest.Type)
+	}
+	if len(manifest.Versions) == 0 {
+		return nil, consts.PluginTypeUnknown, nil, fmt.Errorf("%w: no plugin versions", errPluginBundleInvalid)
+	}
+
+	installs := make([]*pluginBundleInstall, 0, len(manifest.Versions))
+	paths := make(map[string]struct{}, len(manifest.Versions))
+	for _, v := range manifest.Versions {
+		semanticVersion, err := semver.NewSemver(v.Version)
+		if err != nil {
+			return nil, consts.PluginTypeUnknown, nil, fmt.Errorf("%w: version %q is not a valid semantic version", errPluginBundleInvalid, v.Version)
+		}
+		version := "v" + semanticVersion.String()
+		if versions.IsBuiltinVersion(version) {
+			return nil, consts.PluginTypeUnknown, nil, fmt.Errorf("%w: version %q is reserved for builtin plugins", errPluginBundleInvalid, version)
+		}
+
+		var binary *pluginBundleBinary
+		for _, b := range v.Binaries {
+			if b.OS == runtime.GOOS && b.Arch == runtime.GOARCH {
+				binary = b
+				break
+			}
+		}
+		if binary == nil {
+			return nil, consts.PluginTypeUnknown, nil, fmt.Errorf("%w: no binary for %s/%s in version %s", errPluginBundleInvalid, runtime.GOOS, runtime.GOARCH, version)
+		}
+		sum, err := hex.DecodeString(binary.SHA256)
+		if err != nil || len(sum) != sha256.Size {
+			return nil, consts.PluginTypeUnknown, nil, fmt.Errorf("%w: invalid SHA-256 for version %s", errPluginBundleInvalid, version)
+		}
+		binaryPath := path.Clean(strings.TrimPrefix(binary.Path, "./"))
+		if _, ok := paths[binaryPath]; ok {
+			return nil, consts.PluginTypeUnknown, nil, fmt.Errorf("%w: binary %q is used by several versions", errPluginBundleInvalid, binaryPath)
+		}
+		paths[binaryPath] = struct{}{}
+
+		installs = append(installs, &pluginBundleInstall{
+			version: version,
+			args:    v.Args,
+			env:     v.Env,
+			path:    binaryPath,
+			command: fmt.Sprintf("%s_%s", manifest.Name, version),
+			sha256:  sum,
+		})
+	}
+
+	return &manifest, pluginType, installs, nil
+}
+
+// unpackPluginBundle writes the binaries of the given versions of a plugin
+// bundle into the plugin directory, checking them against the SHA-256 of the
+// manifest. Binaries already in the plugin directory are left alone if they
+// are the same, and otherwise fail the install. It returns the paths of the
+// binaries it wrote, for removing them if the install fails later on.
+func (c *Core) unpackPluginBundle(bundlePath string, installs []*pluginBundleInstall) (written []string, retErr error) {
+	byPath := make(map[string]*pluginBundleInstall, len(installs))
+	for _, install := range installs {
+		byPath[install.path] = install
+	}
+
+	// Remove the binaries written by a failed install
+	defer func() {
+		if retErr != nil {
+			removePluginBundleBinaries(written)
+			written = nil
+		}
+	}()
+
+	mode := os.FileMode(0o755)
+	if c.pluginFilePermissions != 0 {
+		mode = os.FileMode(c.pluginFilePermissions)
+	}
+
+	unpacked := make(map[string]struct{}, len(installs))
+	err := walkPluginBundle(bundlePath, func(name string, r io.Reader) error {
+		install, ok := byPath[name]
+		if !ok {
+			return nil
+		}
+		if _, ok := unpacked[name]; ok {
+			return fmt.Errorf("%w: binary %q is in the bundle more than once", errPluginBundleInvalid, name)
+		}
+		unpacked[name] = struct{}{}
+
+		target := filepath.Join(c.pluginDirectory, install.command)
+		if existing, err := os.Open(target); err == nil {
+			hash := sha256.New()
+			_, err := io.Copy(hash, existing)
+			existing.Close()
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(hash.Sum(nil), install.sha256) {
+				return fmt.Errorf("%w: a different binary already exists for version %s", errPluginBundleInvalid, install.version)
+			}
+			return nil
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+
+		tmp, err := os.CreateTemp(c.pluginDirectory, "."+install.command+"-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+
+		hash := sha256.New()
+		_, err = io.Copy(io.MultiWriter(tmp, hash), r)
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(hash.Sum(nil), install.sha256) {
+			return fmt.Errorf("%w: the SHA-256 of the binary of version %s doesn't match the manifest", errPluginBundleInvalid, install.version)
+		}
+
+		if err := os.Chmod(tmp.Name(), mode); err != nil {
+			return err
+		}
+		if c.pluginFileUid != 0 {
+			if err := os.Chown(tmp.Name(), c.pluginFileUid, -1); err != nil {
+				return err
+			}
+		}
+		if err := os.Rename(tmp.Name(), target); err != nil {
+			return err
+		}
+		written = append(written, target)
+		return nil
+	})
+	if err != nil {
+		return written, err
+	}
+
+	for _, install := range installs {
+		if _, ok := unpacked[install.path]; !ok {
+			return written, fmt.Errorf("%w: binary %q of version %s is missing", errPluginBundleInvalid, install.path, install.version)
+		}
+	}
+	return written, nil
+}
+
+func removePluginBundleBinaries(paths []string) {
+	for _, name := range paths {
+		os.Remove(name)
+	}
+}
+
+// pluginCatalogEntries returns the catalog storage entries of the given
+// versions of a plugin, keyed by version, with a nil entry for the versions
+// that are not registered.
+func (c *Core) pluginCatalogEntries(ctx context.Context, name string, pluginType consts.PluginType, installs []*pluginBundleInstall) (map[string]*logical.StorageEntry, error) {
+	c.pluginCatalog.lock.RLock()
+	defer c.pluginCatalog.lock.RUnlock()
+
+	entries := make(map[string]*logical.StorageEntry, len(installs))
+	for _, install := range installs {
+		entry, err := c.pluginCatalog.catalogView.Get(ctx, path.Join(pluginType.String(), name, install.version))
+		if err != nil {
+			return nil, err
+		}
+		entries[install.version] = entry
+	}
+	return entries, nil
+}
+
+// restorePluginCatalogEntries puts back the catalog storage entries returned
+// by pluginCatalogEntries, removing the versions that were not registered.
+func (c *Core) restorePluginCatalogEntries(ctx context.Context, name string, pluginType consts.PluginType, entries map[string]*logical.StorageEntry) error {
+	c.pluginCatalog.lock.Lock()
+	defer c.pluginCatalog.lock.Unlock()
+
+	var retErr *multierror.Error
+	for version, entry := range entries {
+		var err error
+		if entry != nil {
+			err = c.pluginCatalog.catalogView.Put(ctx, entry)
+		} else {
+			err = c.pluginCatalog.catalogView.Delete(ctx, path.Join(pluginType.String(), name, version))
+		}
+		if err != nil {
+			retErr = multierror.Append(retErr, err)
+		}
+	}
+	return retErr.ErrorOrNil()
+}
@@ -0,0 +1,255 @@
+package vault
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
+)
+
+// testPluginBundle writes a plugin bundle signed with the given key, with a
+// binary for this platform and another one for each version
+func testPluginBundle(t *testing.T, dir, name string, key ed25519.PrivateKey, binaries map[string][]byte, tamper bool) {
+	t.Helper()
+
+	files := make(map[string][]byte)
+	manifest := &pluginBundleManifest{
+		Name: "my-plugin",
+		Type: "secret",
+	}
+	// The versions are in order in the manifest, so that they are installed
+	// in order
+	versions := make([]string, 0, len(binaries))
+	for version := range binaries {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	for _, version := range versions {
+		binary := binaries[version]
+		sum := sha256.Sum256(binary)
+		binaryPath := filepath.Join(version, runtime.GOOS+"_"+runtime.GOARCH, "my-plugin")
+		otherPath := filepath.Join(version, "plan9_arm", "my-plugin")
+		manifest.Versions = append(manifest.Versions, &pluginBundleVersion{
+			Version: version,
+			Binaries: []*pluginBundleBinary{
+				{OS: "plan9", Arch: "arm", Path: otherPath, SHA256: hex.EncodeToString(sum[:])},
+				{OS: runtime.GOOS, Arch: runtime.GOARCH, Path: binaryPath, SHA256: hex.EncodeToString(sum[:])},
+			},
+		})
+		files[otherPath] = binary
+		if tamper {
+			binary = append([]byte{}, binary...)
+			binary = append(binary, "tampered"...)
+		}
+		files[binaryPath] = binary
+	}
+	manifestBytes, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	files[pluginBundleManifestName] = manifestBytes
+	files[pluginBundleSignatureName] = ed25519.Sign(key, manifestBytes)
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for name, contents := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o755,
+			Size:     int64(len(contents)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write(contents)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0o644))
+}
+
+func TestSystemBackend_PluginInstall(t *testing.T) {
+	c, b, _ := testCoreSystemBackend(t)
+	ctx := namespace.RootContext(nil)
+
+	pluginDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	c.pluginDirectory = pluginDir
+	c.pluginCatalog.directory = pluginDir
+	c.pluginBundleDirectory = t.TempDir()
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, untrustedKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	c.pluginBundleTrustedKeys = []crypto.PublicKey{pub}
+
+	binaries := map[string][]byte{
+		"v1.0.0": []byte("#!/bin/sh\nexit 1\n"),
+		"v1.1.0": []byte("#!/bin/sh\nexit 2\n"),
+	}
+	testPluginBundle(t, c.pluginBundleDirectory, "my-plugin.tar.gz", key, binaries, false)
+	testPluginBundle(t, c.pluginBundleDirectory, "untrusted.tar.gz", untrustedKey, binaries, false)
+	testPluginBundle(t, c.pluginBundleDirectory, "tampered.tar.gz", key, map[string][]byte{"v2.0.0": []byte("#!/bin/sh\n")}, true)
+
+	install := func(bundle string) *logical.Response {
+		t.Helper()
+		req := logical.TestRequest(t, logical.UpdateOperation, "plugins/install")
+		req.Data["bundle"] = bundle
+		resp, err := b.HandleRequest(ctx, req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	// Installing is idempotent
+	for i := 0; i < 2; i++ {
+		resp := install("my-plugin.tar.gz")
+		require.False(t, resp.IsError(), resp.Error())
+		require.Equal(t, "my-plugin", resp.Data["name"])
+		require.Equal(t, "secret", resp.Data["type"])
+		require.ElementsMatch(t, []string{"v1.0.0", "v1.1.0"}, resp.Data["versions"])
+	}
+
+	for version, binary := range binaries {
+		command := "my-plugin_" + version
+		contents, err := os.ReadFile(filepath.Join(pluginDir, command))
+		require.NoError(t, err)
+		require.Equal(t, binary, contents)
+
+		runner, err := c.pluginCatalog.Get(ctx, "my-plugin", consts.PluginTypeSecrets, version)
+		require.NoError(t, err)
+		require.NotNil(t, runner)
+		require.Equal(t, filepath.Join(pluginDir, command), runner.Command)
+		sum := sha256.Sum256(binary)
+		require.Equal(t, sum[:], runner.Sha256)
+	}
+
+	for _, bundle := range []string{"untrusted.tar.gz", "tampered.tar.gz", "missing.tar.gz", "../my-plugin.tar.gz"} {
+		resp := install(bundle)
+		require.True(t, resp.IsError(), bundle)
+	}
+	_, err = os.Stat(filepath.Join(pluginDir, "my-plugin_v2.0.0"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestLoadPluginBundleTrustedKeys(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	keyPath := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644))
+	keys, err := loadPluginBundleTrustedKeys([]string{keyPath})
+	require.NoError(t, err)
+	require.Equal(t, []crypto.PublicKey{pub}, keys)
+
+	require.NoError(t, os.WriteFile(keyPath, []byte("not a key"), 0o644))
+	_, err = loadPluginBundleTrustedKeys([]string{keyPath})
+	require.Error(t, err)
+}
+
+func TestSystemBackend_PluginInstall_Rollback(t *testing.T) {
+	c, b, _ := testCoreSystemBackend(t)
+	ctx := namespace.RootContext(nil)
+
+	pluginDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	c.pluginDirectory = pluginDir
+	c.pluginCatalog.directory = pluginDir
+	c.pluginBundleDirectory = t.TempDir()
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	c.pluginBundleTrustedKeys = []crypto.PublicKey{pub}
+
+	binaries := map[string][]byte{
+		"v1.0.0": []byte("#!/bin/sh\nexit 1\n"),
+		"v1.1.0": []byte("#!/bin/sh\nexit 2\n"),
+		"v1.2.0": []byte("#!/bin/sh\nexit 3\n"),
+	}
+	testPluginBundle(t, c.pluginBundleDirectory, "v1.0.0.tar.gz", key, map[string][]byte{"v1.0.0": binaries["v1.0.0"]}, false)
+	testPluginBundle(t, c.pluginBundleDirectory, "my-plugin.tar.gz", key, binaries, false)
+
+	install := func(bundle string) (*logical.Response, error) {
+		req := logical.TestRequest(t, logical.UpdateOperation, "plugins/install")
+		req.Data["bundle"] = bundle
+		return b.HandleRequest(ctx, req)
+	}
+
+	resp, err := install("v1.0.0.tar.gz")
+	require.NoError(t, err)
+	require.False(t, resp.IsError(), resp.Error())
+
+	// The last version can't be registered, as its binary in the plugin
+	// directory is a symlink to a file outside of it
+	outside := filepath.Join(t.TempDir(), "my-plugin")
+	require.NoError(t, os.WriteFile(outside, binaries["v1.2.0"], 0o755))
+	require.NoError(t, os.Symlink(outside, filepath.Join(pluginDir, "my-plugin_v1.2.0")))
+
+	_, err = install("my-plugin.tar.gz")
+	require.Error(t, err)
+
+	// The version registered before the install is kept, and the others are
+	// neither registered nor unpacked
+	runner, err := c.pluginCatalog.Get(ctx, "my-plugin", consts.PluginTypeSecrets, "v1.0.0")
+	require.NoError(t, err)
+	require.NotNil(t, runner)
+	_, err = os.Stat(filepath.Join(pluginDir, "my-plugin_v1.0.0"))
+	require.NoError(t, err)
+	for _, version := range []string{"v1.1.0", "v1.2.0"} {
+		runner, err := c.pluginCatalog.Get(ctx, "my-plugin", consts.PluginTypeSecrets, version)
+		require.NoError(t, err)
+		require.Nil(t, runner, version)
+	}
+	_, err = os.Stat(filepath.Join(pluginDir, "my-plugin_v1.1.0"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestSystemBackend_PluginInstall_MissingBinary(t *testing.T) {
+	c, b, _ := testCoreSystemBackend(t)
+	ctx := namespace.RootContext(nil)
+
+	pluginDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	c.pluginDirectory = pluginDir
+	c.pluginCatalog.directory = pluginDir
+	c.pluginBundleDirectory = t.TempDir()
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	c.pluginBundleTrustedKeys = []crypto.PublicKey{pub}
+
+	testPluginBundle(t, c.pluginBundleDirectory, "my-plugin.tar.gz", key, map[string][]byte{"v1.0.0": []byte("#!/bin/sh\nexit 1\n")}, false)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "plugins/install")
+	req.Data["bundle"] = "my-plugin.tar.gz"
+	resp, err := b.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.False(t, resp.IsError(), resp.Error())
+
+	sys := dynamicSystemView{core: c}
+	_, err = sys.LookupPluginVersion(ctx, "my-plugin", consts.PluginTypeSecrets, "v1.0.0")
+	require.NoError(t, err)
+
+	// A node that didn't install the bundle has the catalog entry but not
+	// the binary
+	require.NoError(t, os.Remove(filepath.Join(pluginDir, "my-plugin_v1.0.0")))
+	_, err = sys.LookupPluginVersion(ctx, "my-plugin", consts.PluginTypeSecrets, "v1.0.0")
+	require.ErrorContains(t, err, "missing from the plugin directory of this node")
+}
@@ -55,6 +55,9 @@ type PluginCatalog struct {
 	externalPlugins map[externalPluginsKey]*externalPlugin
 	mlockPlugins    bool
 
+	// launcher runs the plugins with a runtime configuration
+	launcher *pluginutil.PluginLauncher
+
 	lock sync.RWMutex
 }
 
@@ -74,6 +77,7 @@ type externalPluginsKey struct {
 	env     string
 	sha256  string
 	builtin bool
+	runtime string
 }
 
 func makeExternalPluginsKey(p *pluginutil.PluginRunner) (externalPluginsKey, error) {
@@ -87,6 +91,11 @@ func makeExternalPluginsKey(p *pluginutil.PluginRunner) (externalPluginsKey, err
 		return externalPluginsKey{}, err
 	}
 
+	runtime, err := json.Marshal(p.Runtime)
+	if err != nil {
+		return externalPluginsKey{}, err
+	}
+
 	return externalPluginsKey{
 		name:    p.Name,
 		typ:     p.Type,
@@ -96,6 +105,7 @@ func makeExternalPluginsKey(p *pluginutil.PluginRunner) (externalPluginsKey, err
 		env:     string(env),
 		sha256:  hex.EncodeToString(p.Sha256),
 		builtin: p.Builtin,
+		runtime: string(runtime),
 	}, nil
 }
 
@@ -168,6 +178,7 @@ func (c *Core) setupPluginCatalog(ctx context.Context) error {
 		directory:       c.pluginDirectory,
 		logger:          c.logger,
 		mlockPlugins:    c.enableMlock,
+		launcher:        c.pluginLauncher,
 	}
 
 	// Run upgrade if untyped plugins exist
@@ -763,7 +774,7 @@ func (c *PluginCatalog) UpgradePlugins(ctx context.Context, logger log.Logger) e
 		plugin.Command = filepath.Join(c.directory, plugin.Command)
 
 		// Upgrade the storage. At this point we don't know what type of plugin this is so pass in the unknown type.
-		runner, err := c.setInternal(ctx, pluginName, consts.PluginTypeUnknown, plugin.Version, cmdOld, plugin.Args, plugin.Env, plugin.Sha256)
+		runner, err := c.setInternal(ctx, pluginName, consts.PluginTypeUnknown, plugin.Version, cmdOld, plugin.Args, plugin.Env, plugin.Sha256, plugin.Runtime)
 		if err != nil {
 			if errors.Is(err, ErrPluginBadType) {
 				retErr = multierror.Append(retErr, fmt.Errorf("could not upgrade plugin %s: plugin of unknown type", pluginName))
@@ -826,6 +837,10 @@ func (c *PluginCatalog) get(ctx context.Context, name string, pluginType consts.
 
 			// prepend the plugin directory to the command
 			entry.Command = filepath.Join(c.directory, entry.Command)
+			if entry.Runtime != nil && entry.Runtime.SeccompProfile != "" {
+				entry.Runtime.SeccompProfile = filepath.Join(c.directory, entry.Runtime.SeccompProfile)
+			}
+			entry.Launcher = c.launcher
 
 			return entry, nil
 		}
@@ -857,8 +872,9 @@ func (c *PluginCatalog) get(ctx context.Context, name string, pluginType consts.
 }
 
 // Set registers a new external plugin with the catalog, or updates an existing
-// external plugin. It takes the name, command and SHA256 of the plugin.
-func (c *PluginCatalog) Set(ctx context.Context, name string, pluginType consts.PluginType, version string, command string, args []string, env []string, sha256 []byte) error {
+// external plugin. It takes the name, command and SHA256 of the plugin, and
+// optionally the runtime configuration of its process.
+func (c *PluginCatalog) Set(ctx context.Context, name string, pluginType consts.PluginType, version string, command string, args []string, env []string, sha256 []byte, runtime *pluginutil.PluginRuntimeConfig) error {
 	if c.directory == "" {
 		return ErrDirectoryNotConfigured
 	}
@@ -870,14 +886,18 @@ func (c *PluginCatalog) Set(ctx context.Context, name string, pluginType consts.
 		return consts.ErrPathContainsParentReferences
 	}
 
+	if err := runtime.Validate(); err != nil {
+		return err
+	}
+
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	_, err := c.setInternal(ctx, name, pluginType, version, command, args, env, sha256)
+	_, err := c.setInternal(ctx, name, pluginType, version, command, args, env, sha256, runtime)
 	return err
 }
 
-func (c *PluginCatalog) setInternal(ctx context.Context, name string, pluginType consts.PluginType, version string, command string, args []string, env []string, sha256 []byte) (*pluginutil.PluginRunner, error) {
+func (c *PluginCatalog) setInternal(ctx context.Context, name string, pluginType consts.PluginType, version string, command string, args []string, env []string, sha256 []byte, runtime *pluginutil.PluginRuntimeConfig) (*pluginutil.PluginRunner, error) {
 	// Best effort check to make sure the command isn't breaking out of the
 	// configured plugin directory.
 	commandFull := filepath.Join(c.directory, command)
@@ -897,12 +917,20 @@ func (c *PluginCatalog) setInternal(ctx context.Context, name string, pluginType
 	// entryTmp should only be used for the below type and version checks, it uses the
 	// full command instead of the relative command.
 	entryTmp := &pluginutil.PluginRunner{
-		Name:    name,
-		Command: commandFull,
-		Args:    args,
-		Env:     env,
-		Sha256:  sha256,
-		Builtin: false,
+		Name:     name,
+		Command:  commandFull,
+		Args:     args,
+		Env:      env,
+		Sha256:   sha256,
+		Builtin:  false,
+		Launcher: c.launcher,
+	}
+	if runtime != nil {
+		runtimeFull := *runtime
+		if runtimeFull.SeccompProfile != "" {
+			runtimeFull.SeccompProfile = filepath.Join(c.directory, runtimeFull.SeccompProfile)
+		}
+		entryTmp.Runtime = &runtimeFull
 	}
 	// If the plugin type is unknown, we want to attempt to determine the type
 	if pluginType == consts.PluginTypeUnknown {
@@ -949,6 +977,7 @@ func (c *PluginCatalog) setInternal(ctx context.Context, name string, pluginType
 		Env:     env,
 		Sha256:  sha256,
 		Builtin: false,
+		Runtime: runtime,
 	}
 
 	buf, err := json.Marshal(entry)
@@ -137,6 +137,7 @@ type PathRules struct {
 	RequiredParametersHCL []string                 `hcl:"required_parameters"`
 	MFAMethodsHCL         []string                 `hcl:"mfa_methods"`
 	ControlGroupHCL       *ControlGroupHCL         `hcl:"control_group"`
+	ConditionsHCL         *PathConditionsHCL       `hcl:"conditions"`
 }
 
 type ControlGroupHCL struct {
@@ -182,6 +183,22 @@ type ACLPermissions struct {
 	MFAMethods          []string
 	ControlGroup        *ControlGroup
 	GrantingPoliciesMap map[uint32][]logical.PolicyInfo
+
+	// Conditions restricts the use of the capabilities of a single path rule.
+	// When path rules are merged into an ACL, the capabilities of rules with
+	// conditions are kept apart from CapabilitiesBitmap in ConditionalGrants.
+	Conditions        *PathConditions
+	ConditionalGrants []*ConditionalGrant
+}
+
+// allCapabilitiesBitmap returns the capabilities granted on the path, including
+// the ones that are subject to conditions
+func (p *ACLPermissions) allCapabilitiesBitmap() uint32 {
+	capabilities := p.CapabilitiesBitmap
+	for _, grant := range p.ConditionalGrants {
+		capabilities |= grant.CapabilitiesBitmap
+	}
+	return capabilities
 }
 
 func (p *ACLPermissions) Clone() (*ACLPermissions, error) {
@@ -190,6 +207,12 @@ func (p *ACLPermissions) Clone() (*ACLPermissions, error) {
 		MinWrappingTTL:     p.MinWrappingTTL,
 		MaxWrappingTTL:     p.MaxWrappingTTL,
 		RequiredParameters: p.RequiredParameters[:],
+		Conditions:         p.Conditions,
+	}
+
+	// Conditions are immutable once parsed, so only the slice is copied
+	if len(p.ConditionalGrants) > 0 {
+		ret.ConditionalGrants = append([]*ConditionalGrant(nil), p.ConditionalGrants...)
 	}
 
 	switch {
@@ -373,6 +396,7 @@ func parsePaths(result *Policy, list *ast.ObjectList, performTemplating bool, en
 			"max_wrapping_ttl",
 			"mfa_methods",
 			"control_group",
+			"conditions",
 		}
 		if err := hclutil.CheckHCLKeys(item.Val, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("path %q:", key))
@@ -437,6 +461,11 @@ func parsePaths(result *Policy, list *ast.ObjectList, performTemplating bool, en
 			switch cap {
 			// If it's deny, don't include any other capability
 			case DenyCapability:
+				// A deny must never depend on the request, so it can't have
+				// conditions
+				if pc.ConditionsHCL != nil {
+					return fmt.Errorf("path %q: conditions cannot be used with the %q capability", key, DenyCapability)
+				}
 				pc.Capabilities = []string{DenyCapability}
 				pc.Permissions.CapabilitiesBitmap = DenyCapabilityInt
 				goto PathFinished
@@ -538,6 +567,16 @@ func parsePaths(result *Policy, list *ast.ObjectList, performTemplating bool, en
 		if len(pc.RequiredParametersHCL) > 0 {
 			pc.Permissions.RequiredParameters = pc.RequiredParametersHCL[:]
 		}
+		if pc.ConditionsHCL != nil {
+			if err := checkPathConditionsKeys(item); err != nil {
+				return fmt.Errorf("path %q: error parsing conditions: %w", key, err)
+			}
+			conditions, err := parsePathConditions(pc.ConditionsHCL)
+			if err != nil {
+				return fmt.Errorf("path %q: error parsing conditions: %w", key, err)
+			}
+			pc.Permissions.Conditions = conditions
+		}
 
 	PathFinished:
 		paths = append(paths, &pc)
@@ -0,0 +1,230 @@
+package vault
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	sockaddr "github.com/hashicorp/go-sockaddr"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/vault/sdk/helper/cidrutil"
+	"github.com/hashicorp/vault/sdk/helper/hclutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// PathConditionsHCL is the HCL representation of the conditions block of a
+// path rule
+type PathConditionsHCL struct {
+	AllowedCIDRs  []string          `hcl:"allowed_cidrs"`
+	DaysOfWeek    []string          `hcl:"days_of_week"`
+	TimeOfDay     string            `hcl:"time_of_day"`
+	Timezone      string            `hcl:"timezone"`
+	TokenMetadata map[string]string `hcl:"token_metadata"`
+}
+
+// PathConditions restricts from where, when and with which tokens the
+// capabilities of a path rule can be used. All configured conditions must be
+// satisfied by a request.
+type PathConditions struct {
+	AllowedCIDRs  []*sockaddr.SockAddrMarshaler
+	DaysOfWeek    []time.Weekday
+	TimeOfDay     *TimeOfDayWindow
+	Location      *time.Location
+	TokenMetadata map[string]string
+}
+
+// TimeOfDayWindow is a window within a day, in minutes since midnight. If End
+// is before Start, the window wraps around midnight.
+type TimeOfDayWindow struct {
+	Start int
+	End   int
+}
+
+// ConditionalGrant holds the capabilities that a policy grants on a path
+// subject to the path rule's conditions. Constraints holds the wrapping TTL
+// and parameter constraints of the path rule, which likewise only apply when
+// the conditions are met.
+type ConditionalGrant struct {
+	CapabilitiesBitmap uint32
+	Conditions         *PathConditions
+	Constraints        *ACLPermissions
+	Policy             logical.PolicyInfo
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// checkPathConditionsKeys checks for invalid keys in the conditions block of a
+// path rule
+func checkPathConditionsKeys(item *ast.ObjectItem) error {
+	objType, ok := item.Val.(*ast.ObjectType)
+	if !ok {
+		return nil
+	}
+
+	valid := []string{
+		"allowed_cidrs",
+		"days_of_week",
+		"time_of_day",
+		"timezone",
+		"token_metadata",
+	}
+	for _, conditionsItem := range objType.List.Filter("conditions").Items {
+		if err := hclutil.CheckHCLKeys(conditionsItem.Val, valid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parsePathConditions parses the conditions block of a path rule
+func parsePathConditions(conditionsHCL *PathConditionsHCL) (*PathConditions, error) {
+	conditions := &PathConditions{
+		Location: time.UTC,
+	}
+
+	if len(conditionsHCL.AllowedCIDRs) > 0 {
+		cidrs, err := parseutil.ParseAddrs(conditionsHCL.AllowedCIDRs)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing allowed_cidrs: %w", err)
+		}
+		conditions.AllowedCIDRs = cidrs
+	}
+
+	for _, day := range conditionsHCL.DaysOfWeek {
+		// Accept both abbreviated and full day names
+		name := strings.ToLower(strings.TrimSpace(day))
+		var weekday time.Weekday
+		var ok bool
+		if len(name) >= 3 {
+			weekday, ok = weekdays[name[:3]]
+		}
+		if !ok || (len(name) > 3 && name != strings.ToLower(weekday.String())) {
+			return nil, fmt.Errorf("invalid day of week %q", day)
+		}
+		conditions.DaysOfWeek = append(conditions.DaysOfWeek, weekday)
+	}
+
+	if conditionsHCL.TimeOfDay != "" {
+		window, err := parseTimeOfDayWindow(conditionsHCL.TimeOfDay)
+		if err != nil {
+			return nil, err
+		}
+		conditions.TimeOfDay = window
+	}
+
+	if conditionsHCL.Timezone != "" {
+		location, err := time.LoadLocation(conditionsHCL.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing timezone: %w", err)
+		}
+		conditions.Location = location
+	}
+
+	if len(conditionsHCL.TokenMetadata) > 0 {
+		conditions.TokenMetadata = make(map[string]string, len(conditionsHCL.TokenMetadata))
+		for k, v := range conditionsHCL.TokenMetadata {
+			conditions.TokenMetadata[k] = v
+		}
+	}
+
+	if len(conditions.AllowedCIDRs) == 0 && len(conditions.DaysOfWeek) == 0 &&
+		conditions.TimeOfDay == nil && len(conditions.TokenMetadata) == 0 {
+		return nil, errors.New("conditions block must set at least one condition")
+	}
+
+	return conditions, nil
+}
+
+// parseTimeOfDayWindow parses a window in the form of "HH:MM-HH:MM"
+func parseTimeOfDayWindow(raw string) (*TimeOfDayWindow, error) {
+	parts := strings.Split(raw, "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid time_of_day %q, expected \"HH:MM-HH:MM\"", raw)
+	}
+
+	var minutes [2]int
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid time_of_day %q, expected \"HH:MM-HH:MM\"", raw)
+		}
+		minutes[i] = t.Hour()*60 + t.Minute()
+	}
+
+	if minutes[0] == minutes[1] {
+		return nil, fmt.Errorf("invalid time_of_day %q, start and end must differ", raw)
+	}
+
+	return &TimeOfDayWindow{
+		Start: minutes[0],
+		End:   minutes[1],
+	}, nil
+}
+
+// contains returns whether the given time of day falls in the window. The start
+// of the window is inclusive and the end is exclusive.
+func (w *TimeOfDayWindow) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End
+	}
+	return minute >= w.Start || minute < w.End
+}
+
+// satisfied returns whether the request, made at the given time, satisfies all
+// the conditions. Conditions that cannot be verified, such as a missing remote
+// address or token entry, are not satisfied.
+func (c *PathConditions) satisfied(now time.Time, req *logical.Request) bool {
+	if len(c.AllowedCIDRs) > 0 {
+		if req.Connection == nil || req.Connection.RemoteAddr == "" {
+			return false
+		}
+		if !cidrutil.RemoteAddrIsOk(req.Connection.RemoteAddr, c.AllowedCIDRs) {
+			return false
+		}
+	}
+
+	now = now.In(c.Location)
+
+	if len(c.DaysOfWeek) > 0 {
+		var found bool
+		for _, day := range c.DaysOfWeek {
+			if now.Weekday() == day {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if c.TimeOfDay != nil && !c.TimeOfDay.contains(now) {
+		return false
+	}
+
+	if len(c.TokenMetadata) > 0 {
+		te := req.TokenEntry()
+		if te == nil {
+			return false
+		}
+		for k, v := range c.TokenMetadata {
+			if actual, ok := te.Meta[k]; !ok || actual != v {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,69 @@
+package vault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+func TestPathConditions_Satisfied(t *testing.T) {
+	conditions, err := parsePathConditions(&PathConditionsHCL{
+		AllowedCIDRs: []string{"10.0.0.0/8"},
+		DaysOfWeek:   []string{"sat", "sun"},
+		TimeOfDay:    "22:00-06:00",
+		Timezone:     "Europe/Paris",
+		TokenMetadata: map[string]string{
+			"team": "ops",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newRequest := func(remoteAddr string, meta map[string]string) *logical.Request {
+		req := &logical.Request{
+			Connection: &logical.Connection{
+				RemoteAddr: remoteAddr,
+			},
+		}
+		if meta != nil {
+			req.SetTokenEntry(&logical.TokenEntry{Meta: meta})
+		}
+		return req
+	}
+
+	// Saturday 2022-01-01 23:30 in Paris
+	inWindow := time.Date(2022, 1, 1, 23, 30, 0, 0, paris)
+
+	tcases := []struct {
+		name     string
+		now      time.Time
+		req      *logical.Request
+		expected bool
+	}{
+		{"all met", inWindow, newRequest("10.1.2.3", map[string]string{"team": "ops"}), true},
+		{"after midnight", time.Date(2022, 1, 2, 5, 59, 0, 0, paris), newRequest("10.1.2.3", map[string]string{"team": "ops"}), true},
+		{"utc equivalent", inWindow.UTC(), newRequest("10.1.2.3", map[string]string{"team": "ops"}), true},
+		{"outside cidr", inWindow, newRequest("192.168.1.1", map[string]string{"team": "ops"}), false},
+		{"no connection", inWindow, &logical.Request{}, false},
+		{"outside time of day", time.Date(2022, 1, 1, 21, 59, 0, 0, paris), newRequest("10.1.2.3", map[string]string{"team": "ops"}), false},
+		{"end is exclusive", time.Date(2022, 1, 2, 6, 0, 0, 0, paris), newRequest("10.1.2.3", map[string]string{"team": "ops"}), false},
+		{"outside days of week", time.Date(2022, 1, 3, 23, 30, 0, 0, paris), newRequest("10.1.2.3", map[string]string{"team": "ops"}), false},
+		{"metadata mismatch", inWindow, newRequest("10.1.2.3", map[string]string{"team": "dev"}), false},
+		{"no token entry", inWindow, newRequest("10.1.2.3", nil), false},
+	}
+
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := conditions.satisfied(tc.now, tc.req); actual != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package vault
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/vault/helper/identity"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// PolicySimulationInput describes the subject and the request that should be
+// evaluated by a policy simulation. Exactly one of Token, Accessor, EntityID or
+// Policies should be set.
+type PolicySimulationInput struct {
+	Token      string
+	Accessor   string
+	EntityID   string
+	Policies   []string
+	Path       string
+	Operation  logical.Operation
+	Parameters map[string]interface{}
+	WrapTTL    time.Duration
+
+	// RemoteAddress is the client address of the simulated request, used to
+	// evaluate CIDR conditions of path rules
+	RemoteAddress string
+}
+
+// PolicySimulationResult explains the ACL decision for a simulated request
+type PolicySimulationResult struct {
+	Allowed          bool
+	IsRoot           bool
+	SudoRequired     bool
+	HasSudo          bool
+	MatchedPath      string
+	DenialReason     ACLDenialReason
+	DeniedParameter  string
+	Capabilities     []string
+	GrantingPolicies []logical.PolicyInfo
+	Policies         []*PolicySimulationMatch
+}
+
+// PolicySimulationMatch explains how a single policy, evaluated on its own,
+// treats the simulated request. Only policies with a path rule matching the
+// request path are reported.
+type PolicySimulationMatch struct {
+	Name            string
+	NamespacePath   string
+	MatchedPath     string
+	Capabilities    []string
+	Allowed         bool
+	DenialReason    ACLDenialReason
+	DeniedParameter string
+}
+
+// SimulatePolicy evaluates the given request against the ACL of a token, an
+// entity or a set of policies and explains the resulting decision without
+// performing the request.
+func (c *Core) SimulatePolicy(ctx context.Context, input *PolicySimulationInput) (*PolicySimulationResult, error) {
+	if input.Path == "" {
+		return nil, &logical.StatusBadRequest{Err: "missing path"}
+	}
+
+	var subjects int
+	for _, set := range []bool{input.Token != "", input.Accessor != "", input.EntityID != "", len(input.Policies) > 0} {
+		if set {
+			subjects++
+		}
+	}
+	if subjects != 1 {
+		return nil, &logical.StatusBadRequest{Err: "exactly one of token, accessor, entity_id or policies must be provided"}
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policyNames := make(map[string][]string)
+	var entity *identity.Entity
+	var te *logical.TokenEntry
+	var additionalPolicies []*Policy
+
+	// ACL construction should be performed on the namespace of the subject
+	evalCtx := ctx
+
+	switch {
+	case input.Token != "" || input.Accessor != "":
+		token := input.Token
+		if input.Accessor != "" {
+			aEntry, err := c.tokenStore.lookupByAccessor(ctx, input.Accessor, false, false)
+			if err != nil {
+				return nil, err
+			}
+			if aEntry == nil {
+				return nil, &logical.StatusBadRequest{Err: "invalid accessor"}
+			}
+			token = aEntry.TokenID
+		}
+
+		te, err = c.tokenStore.Lookup(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if te == nil {
+			return nil, &logical.StatusBadRequest{Err: "invalid token"}
+		}
+
+		tokenNS, err := NamespaceByID(ctx, te.NamespaceID, c)
+		if err != nil {
+			return nil, err
+		}
+		if tokenNS == nil {
+			return nil, namespace.ErrNoNamespace
+		}
+		// Only tokens of the request namespace and of its children can be
+		// simulated, as for token lookups by accessor
+		if tokenNS.ID != ns.ID && !tokenNS.HasParent(ns) {
+			return nil, logical.ErrPermissionDenied
+		}
+		evalCtx = namespace.ContextWithNamespace(ctx, tokenNS)
+		policyNames[tokenNS.ID] = te.Policies
+
+		var identityPolicies map[string][]string
+		entity, identityPolicies, err = c.fetchEntityAndDerivedPolicies(ctx, tokenNS, te.EntityID, te.NoIdentityPolicies)
+		if err != nil {
+			return nil, err
+		}
+		if te.EntityID != "" && (entity == nil || entity.Disabled) {
+			return nil, &logical.StatusBadRequest{Err: "the entity on the token is invalid or disabled"}
+		}
+		for nsID, nsPolicies := range identityPolicies {
+			policyNames[nsID] = append(policyNames[nsID], nsPolicies...)
+		}
+
+		if te.InlinePolicy != "" {
+			inlinePolicy, err := ParseACLPolicy(tokenNS, te.InlinePolicy)
+			if err != nil {
+				return nil, err
+			}
+			additionalPolicies = append(additionalPolicies, inlinePolicy)
+		}
+
+	case input.EntityID != "":
+		var identityPolicies map[string][]string
+		entity, identityPolicies, err = c.fetchEntityAndDerivedPolicies(ctx, ns, input.EntityID, false)
+		if err != nil {
+			return nil, err
+		}
+		if entity == nil {
+			return nil, &logical.StatusBadRequest{Err: "invalid entity_id"}
+		}
+		for nsID, nsPolicies := range identityPolicies {
+			policyNames[nsID] = append(policyNames[nsID], nsPolicies...)
+		}
+
+	default:
+		policyNames[ns.ID] = input.Policies
+	}
+
+	allPolicies, err := c.policyStore.resolvePolicies(evalCtx, entity, policyNames, additionalPolicies...)
+	if err != nil {
+		return nil, err
+	}
+
+	acl, err := NewACL(evalCtx, allPolicies)
+	if err != nil {
+		return nil, &logical.StatusBadRequest{Err: err.Error()}
+	}
+	if te != nil {
+		tokenNS, err := namespace.FromContext(evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.tokenStore.applyTokenPathRestrictions(evalCtx, acl, tokenNS, te); err != nil {
+			return nil, err
+		}
+	}
+
+	req := &logical.Request{
+		Operation: input.Operation,
+		Path:      input.Path,
+		Data:      input.Parameters,
+	}
+	if input.WrapTTL > 0 {
+		req.WrapInfo = &logical.RequestWrapInfo{
+			TTL: input.WrapTTL,
+		}
+	}
+	if input.RemoteAddress != "" {
+		req.Connection = &logical.Connection{
+			RemoteAddr: input.RemoteAddress,
+		}
+	}
+	if te != nil {
+		req.SetTokenEntry(te)
+	}
+
+	res := acl.AllowOperation(ctx, req, false)
+	result := &PolicySimulationResult{
+		Allowed:          res.Allowed,
+		IsRoot:           res.IsRoot,
+		SudoRequired:     c.router.RootPath(evalCtx, input.Path),
+		HasSudo:          res.RootPrivs,
+		MatchedPath:      res.MatchedPath,
+		DenialReason:     res.DenialReason,
+		DeniedParameter:  res.DeniedParameter,
+		Capabilities:     acl.Capabilities(ctx, input.Path),
+		GrantingPolicies: res.GrantingPolicies,
+	}
+	sort.Strings(result.Capabilities)
+
+	// A request against a path requiring sudo is denied without the sudo
+	// capability, regardless of the operation being allowed
+	if result.Allowed && result.SudoRequired && !result.HasSudo {
+		result.Allowed = false
+		result.DenialReason = ACLDenialSudoRequired
+	}
+
+	// Evaluate each policy on its own to attribute the decision
+	seen := make(map[string]struct{}, len(allPolicies))
+	for _, policy := range allPolicies {
+		if policy == nil || policy.Type != PolicyTypeACL {
+			continue
+		}
+
+		var nsPath string
+		if policy.namespace != nil {
+			nsPath = policy.namespace.Path
+		}
+		key := nsPath + policy.Name
+		if _, ok := seen[key]; ok && policy.Name != "" {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		policyACL, err := NewACL(evalCtx, []*Policy{policy})
+		if err != nil {
+			return nil, err
+		}
+		policyRes := policyACL.AllowOperation(ctx, req, false)
+		if policyRes.MatchedPath == "" && !policyRes.IsRoot {
+			continue
+		}
+
+		match := &PolicySimulationMatch{
+			Name:            policy.Name,
+			NamespacePath:   nsPath,
+			MatchedPath:     policyRes.MatchedPath,
+			Capabilities:    policyACL.Capabilities(ctx, input.Path),
+			Allowed:         policyRes.Allowed,
+			DenialReason:    policyRes.DenialReason,
+			DeniedParameter: policyRes.DeniedParameter,
+		}
+		sort.Strings(match.Capabilities)
+		result.Policies = append(result.Policies, match)
+	}
+
+	return result, nil
+}
@@ -155,6 +155,11 @@ path "sys/control-group/request" {
 path "identity/oidc/provider/+/authorize" {
 	capabilities = ["read", "update"]
 }
+
+# Allow a token to approve device authorization requests for OIDC providers.
+path "identity/oidc/provider/+/device" {
+	capabilities = ["read", "update"]
+}
 `
 )
 
@@ -760,6 +765,23 @@ func (t *TemplateError) Error() string {
 // ACL is used to return an ACL which is built using the
 // named policies and pre-fetched policies if given.
 func (ps *PolicyStore) ACL(ctx context.Context, entity *identity.Entity, policyNames map[string][]string, additionalPolicies ...*Policy) (*ACL, error) {
+	allPolicies, err := ps.resolvePolicies(ctx, entity, policyNames, additionalPolicies...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct the ACL
+	acl, err := NewACL(ctx, allPolicies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct ACL: %w", err)
+	}
+
+	return acl, nil
+}
+
+// resolvePolicies fetches the named policies, appends the given additional
+// policies and renders any templated policies for the given entity.
+func (ps *PolicyStore) resolvePolicies(ctx context.Context, entity *identity.Entity, policyNames map[string][]string, additionalPolicies ...*Policy) ([]*Policy, error) {
 	var allPolicies []*Policy
 
 	// Fetch the named policies
@@ -809,13 +831,7 @@ func (ps *PolicyStore) ACL(ctx context.Context, entity *identity.Entity, policyN
 		}
 	}
 
-	// Construct the ACL
-	acl, err := NewACL(ctx, allPolicies)
-	if err != nil {
-		return nil, fmt.Errorf("failed to construct ACL: %w", err)
-	}
-
-	return acl, nil
+	return allPolicies, nil
 }
 
 // loadACLPolicy is used to load default ACL policies. The default policies will
@@ -1,6 +1,8 @@
 package vault
 
 import (
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -494,3 +496,85 @@ path "foo/+*" {
 		t.Errorf("bad error: %s", err)
 	}
 }
+
+func TestPolicy_ParseConditions(t *testing.T) {
+	p, err := ParseACLPolicy(namespace.RootNamespace, strings.TrimSpace(`
+name = "break-glass"
+path "secret/*" {
+	capabilities = ["read"]
+	conditions {
+		allowed_cidrs  = ["10.0.0.0/8"]
+		days_of_week   = ["Monday", "fri"]
+		time_of_day    = "22:00-06:00"
+		timezone       = "America/New_York"
+		token_metadata = {
+			team = "ops"
+		}
+	}
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conditions := p.Paths[0].Permissions.Conditions
+	if conditions == nil {
+		t.Fatal("expected conditions to be parsed")
+	}
+	if len(conditions.AllowedCIDRs) != 1 || conditions.AllowedCIDRs[0].String() != "10.0.0.0/8" {
+		t.Fatalf("bad: allowed cidrs: %v", conditions.AllowedCIDRs)
+	}
+	if !reflect.DeepEqual(conditions.DaysOfWeek, []time.Weekday{time.Monday, time.Friday}) {
+		t.Fatalf("bad: days of week: %v", conditions.DaysOfWeek)
+	}
+	if !reflect.DeepEqual(conditions.TimeOfDay, &TimeOfDayWindow{Start: 22 * 60, End: 6 * 60}) {
+		t.Fatalf("bad: time of day: %#v", conditions.TimeOfDay)
+	}
+	if conditions.Location.String() != "America/New_York" {
+		t.Fatalf("bad: location: %v", conditions.Location)
+	}
+	if !reflect.DeepEqual(conditions.TokenMetadata, map[string]string{"team": "ops"}) {
+		t.Fatalf("bad: token metadata: %v", conditions.TokenMetadata)
+	}
+
+	for _, tc := range []struct {
+		conditions string
+		err        string
+	}{
+		{`allowed_cidrs = ["nope"]`, "error parsing allowed_cidrs"},
+		{`days_of_week = ["someday"]`, "invalid day of week"},
+		{`days_of_week = ["monkey"]`, "invalid day of week"},
+		{`time_of_day = "9-5"`, "invalid time_of_day"},
+		{`time_of_day = "09:00-09:00"`, "start and end must differ"},
+		{`timezone = "Nowhere/Nothing"`, "error parsing timezone"},
+		{`timezone = "UTC"`, "at least one condition"},
+		{`hours = "09:00-17:00"`, `invalid key "hours"`},
+	} {
+		_, err := ParseACLPolicy(namespace.RootNamespace, fmt.Sprintf(`
+path "secret/*" {
+	capabilities = ["read"]
+	conditions {
+		%s
+	}
+}
+`, tc.conditions))
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Fatalf("expected error containing %q for %q, got: %v", tc.err, tc.conditions, err)
+		}
+	}
+
+	// A deny can't be made conditional
+	for _, rule := range []string{`capabilities = ["deny"]`, `policy = "deny"`} {
+		_, err := ParseACLPolicy(namespace.RootNamespace, fmt.Sprintf(`
+path "secret/*" {
+	%s
+	conditions {
+		allowed_cidrs = ["10.0.0.0/8"]
+	}
+}
+`, rule))
+		if err == nil || !strings.Contains(err.Error(), "conditions cannot be used") {
+			t.Fatalf("expected error for conditional deny %q, got: %v", rule, err)
+		}
+	}
+}
@@ -25,6 +25,13 @@ const (
 
 	// TypeLeaseCount represents the lease count limiting quota type
 	TypeLeaseCount Type = "lease-count"
+
+	// TypeConcurrency represents the in-flight request limiting quota type
+	TypeConcurrency Type = "concurrency"
+
+	// TypeRequestSize represents the request size and bandwidth limiting quota
+	// type
+	TypeRequestSize Type = "request-size"
 )
 
 // LeaseAction is the action taken by the expiration manager on the lease. The
@@ -76,6 +83,10 @@ func (q Type) String() string {
 		return "lease-count"
 	case TypeRateLimit:
 		return "rate-limit"
+	case TypeConcurrency:
+		return "concurrency"
+	case TypeRequestSize:
+		return "request-size"
 	}
 	return "unknown"
 }
@@ -114,6 +125,14 @@ var (
 	// ErrRateLimitQuotaExceeded is returned when a request is rejected due to a
 	// rate limit quota being exceeded.
 	ErrRateLimitQuotaExceeded = errors.New("rate limit quota exceeded")
+
+	// ErrConcurrencyQuotaExceeded is returned when a request is rejected due to
+	// a concurrency quota being exceeded.
+	ErrConcurrencyQuotaExceeded = errors.New("concurrency quota exceeded")
+
+	// ErrRequestSizeQuotaExceeded is returned when a request is rejected due to
+	// a request size quota being exceeded.
+	ErrRequestSizeQuotaExceeded = errors.New("request size quota exceeded")
 )
 
 var defaultExemptPaths = []string{
@@ -265,6 +284,18 @@ type Request struct {
 	// ClientAddress is client unique addressable string (e.g. IP address). It can
 	// be empty if the quota type does not need it.
 	ClientAddress string
+
+	// EntityID is the identity entity of the client token. It can be empty if
+	// the quota type does not need it or if the request is unauthenticated.
+	EntityID string
+
+	// ClientTokenAccessor is the accessor of the client token. It can be empty
+	// if the quota type does not need it or if the request is unauthenticated.
+	ClientTokenAccessor string
+
+	// RequestSize is the size of the request body in bytes. It is only used by
+	// the request size quota type.
+	RequestSize int64
 }
 
 // NewManager creates and initializes a new quota manager to hold all the quota
@@ -663,6 +694,19 @@ func (m *Manager) ApplyQuota(ctx context.Context, req *Request) (Response, error
 	return quota.allow(ctx, req)
 }
 
+// ReleaseConcurrencyQuota frees up the in-flight request slot held by the given
+// access. It must be called once a request that was allowed by a concurrency
+// quota has finished processing. Accesses issued by other quota types are
+// ignored.
+func (m *Manager) ReleaseConcurrencyQuota(access Access) {
+	ca, ok := access.(*concurrencyAccess)
+	if !ok {
+		return
+	}
+
+	ca.quota.release(ca.key)
+}
+
 // SetEnableRateLimitAuditLogging updates the operator preference regarding the
 // audit logging behavior.
 func (m *Manager) SetEnableRateLimitAuditLogging(val bool) {
@@ -771,21 +815,21 @@ func (m *Manager) Reset() error {
 
 // Must be called with the lock held
 func (m *Manager) resetCache() error {
-	names, err := m.quotaNamesLocked(TypeRateLimit)
-	if err != nil {
-		return err
-	}
-	for _, name := range names {
-		quota, err := m.quotaByNameLocked(TypeRateLimit.String(), name)
+	for _, qType := range []Type{TypeRateLimit, TypeConcurrency, TypeRequestSize} {
+		names, err := m.quotaNamesLocked(qType)
 		if err != nil {
 			return err
 		}
-		if quota != nil {
-			rlq := quota.(*RateLimitQuota)
-			err = rlq.store.Close(context.Background())
+		for _, name := range names {
+			quota, err := m.quotaByNameLocked(qType.String(), name)
 			if err != nil {
 				return err
 			}
+			if quota != nil {
+				if err := quota.close(context.Background()); err != nil {
+					return err
+				}
+			}
 		}
 	}
 	db, err := memdb.NewMemDB(dbSchema())
@@ -1016,6 +1060,10 @@ func Load(ctx context.Context, storage logical.Storage, qType, name string) (Quo
 		quota = &RateLimitQuota{}
 	case TypeLeaseCount.String():
 		quota = &LeaseCountQuota{}
+	case TypeConcurrency.String():
+		quota = &ConcurrencyQuota{}
+	case TypeRequestSize.String():
+		quota = &RequestSizeQuota{}
 	default:
 		return nil, fmt.Errorf("unsupported type: %v", qType)
 	}
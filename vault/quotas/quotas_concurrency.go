@@ -0,0 +1,218 @@
+package quotas
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/helper/metricsutil"
+	"github.com/hashicorp/vault/sdk/helper/cryptoutil"
+)
+
+// Ensure that ConcurrencyQuota implements the Quota interface
+var _ Quota = (*ConcurrencyQuota)(nil)
+
+// ConcurrencyQuota represents the quota rule properties that is used to limit
+// the number of requests that are being processed at the same time for a
+// namespace or mount.
+type ConcurrencyQuota struct {
+	// ID is the identifier of the quota
+	ID string `json:"id"`
+
+	// Type of quota this represents
+	Type Type `json:"type"`
+
+	// Name of the quota rule
+	Name string `json:"name"`
+
+	// NamespacePath is the path of the namespace to which this quota is
+	// applicable.
+	NamespacePath string `json:"namespace_path"`
+
+	// MountPath is the path of the mount to which this quota is applicable
+	MountPath string `json:"mount_path"`
+
+	// Role is the role on an auth mount to apply the quota to upon /login requests
+	// Not applicable for use with path suffixes
+	Role string `json:"role"`
+
+	// PathSuffix is the path suffix to which this quota is applicable
+	PathSuffix string `json:"path_suffix"`
+
+	// MaxRequests defines the number of requests allowed to be in flight at the
+	// same time.
+	MaxRequests int `json:"max_requests"`
+
+	// PerClient, if set, applies MaxRequests to each unique client address
+	// instead of to all the requests matching the quota.
+	PerClient bool `json:"per_client"`
+
+	lock       *sync.Mutex
+	inFlight   map[string]int
+	logger     log.Logger
+	metricSink *metricsutil.ClusterMetricSink
+}
+
+// concurrencyAccess is handed out by a ConcurrencyQuota for every request it
+// admits. It is used to release the in-flight slot once the request is done.
+type concurrencyAccess struct {
+	quota *ConcurrencyQuota
+	key   string
+}
+
+// QuotaID returns the identifier of the quota rule to which this access refers
+// to.
+func (a *concurrencyAccess) QuotaID() string {
+	return a.quota.ID
+}
+
+// NewConcurrencyQuota creates a quota checker for imposing limits on the
+// number of requests being processed at the same time. If perClient is set,
+// the limit is applied to each unique client address.
+func NewConcurrencyQuota(name, nsPath, mountPath, pathSuffix, role string, maxRequests int, perClient bool) *ConcurrencyQuota {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		// Fall back to generating with a hash of the name, later in initialize
+		id = ""
+	}
+	return &ConcurrencyQuota{
+		Name:          name,
+		ID:            id,
+		Type:          TypeConcurrency,
+		NamespacePath: nsPath,
+		MountPath:     mountPath,
+		Role:          role,
+		PathSuffix:    pathSuffix,
+		MaxRequests:   maxRequests,
+		PerClient:     perClient,
+	}
+}
+
+func (cq *ConcurrencyQuota) Clone() Quota {
+	return &ConcurrencyQuota{
+		ID:            cq.ID,
+		Name:          cq.Name,
+		MountPath:     cq.MountPath,
+		Role:          cq.Role,
+		Type:          cq.Type,
+		NamespacePath: cq.NamespacePath,
+		PathSuffix:    cq.PathSuffix,
+		MaxRequests:   cq.MaxRequests,
+		PerClient:     cq.PerClient,
+	}
+}
+
+// initialize ensures the namespace and max requests are initialized and sets
+// the ID if it's currently empty. Note, initialize will reset the in-flight
+// request counters.
+func (cq *ConcurrencyQuota) initialize(logger log.Logger, ms *metricsutil.ClusterMetricSink) error {
+	if cq.lock == nil {
+		cq.lock = new(sync.Mutex)
+	}
+
+	cq.lock.Lock()
+	defer cq.lock.Unlock()
+
+	// Memdb requires a non-empty value for indexing
+	if cq.NamespacePath == "" {
+		cq.NamespacePath = "root"
+	}
+
+	if cq.MaxRequests <= 0 {
+		return fmt.Errorf("invalid max requests: %v", cq.MaxRequests)
+	}
+
+	if logger != nil {
+		cq.logger = logger
+	}
+
+	if cq.metricSink == nil {
+		cq.metricSink = ms
+	}
+
+	if cq.ID == "" {
+		cq.ID = hex.EncodeToString(cryptoutil.Blake2b256Hash(cq.Name))
+	}
+
+	cq.inFlight = make(map[string]int)
+
+	return nil
+}
+
+// quotaID returns the identifier of the quota rule
+func (cq *ConcurrencyQuota) quotaID() string {
+	return cq.ID
+}
+
+// QuotaName returns the name of the quota rule
+func (cq *ConcurrencyQuota) QuotaName() string {
+	return cq.Name
+}
+
+// allow decides if the request is allowed by the quota. An error will be
+// returned if the quota is applied per client and the client address is empty.
+// Every allowed request holds an in-flight slot until the returned access is
+// passed to Manager.ReleaseConcurrencyQuota.
+func (cq *ConcurrencyQuota) allow(_ context.Context, req *Request) (Response, error) {
+	var resp Response
+
+	var key string
+	if cq.PerClient {
+		if req.ClientAddress == "" {
+			return resp, fmt.Errorf("missing request client address in quota request")
+		}
+		key = req.ClientAddress
+	}
+
+	cq.lock.Lock()
+	defer cq.lock.Unlock()
+
+	if cq.inFlight[key] >= cq.MaxRequests {
+		cq.metricSink.IncrCounterWithLabels([]string{"quota", "concurrency", "violation"}, 1, []metrics.Label{{Name: "name", Value: cq.Name}})
+		return resp, nil
+	}
+
+	cq.inFlight[key]++
+
+	resp.Allowed = true
+	resp.Access = &concurrencyAccess{
+		quota: cq,
+		key:   key,
+	}
+
+	return resp, nil
+}
+
+// release frees up an in-flight slot held by the given key.
+func (cq *ConcurrencyQuota) release(key string) {
+	cq.lock.Lock()
+	defer cq.lock.Unlock()
+
+	switch n := cq.inFlight[key]; {
+	case n > 1:
+		cq.inFlight[key] = n - 1
+	default:
+		delete(cq.inFlight, key)
+	}
+}
+
+// numInFlight returns the number of in-flight requests held by the given key.
+func (cq *ConcurrencyQuota) numInFlight(key string) int {
+	cq.lock.Lock()
+	defer cq.lock.Unlock()
+	return cq.inFlight[key]
+}
+
+// close is a no-op as the concurrency quota holds no background resources.
+func (cq *ConcurrencyQuota) close(_ context.Context) error {
+	return nil
+}
+
+func (cq *ConcurrencyQuota) handleRemount(mountpath, nspath string) {
+	cq.MountPath = mountpath
+	cq.NamespacePath = nspath
+}
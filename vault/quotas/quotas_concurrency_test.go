@@ -0,0 +1,86 @@
+package quotas
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/helper/metricsutil"
+	"github.com/hashicorp/vault/sdk/helper/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConcurrencyQuota(t *testing.T) {
+	testCases := []struct {
+		name      string
+		cq        *ConcurrencyQuota
+		expectErr bool
+	}{
+		{"valid max requests", NewConcurrencyQuota("test-concurrency", "qa", "/foo/bar", "", "", 5, false), false},
+		{"zero max requests", NewConcurrencyQuota("test-concurrency", "qa", "/foo/bar", "", "", 0, false), true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cq.initialize(logging.NewVaultLogger(log.Trace), metricsutil.BlackholeSink())
+			require.Equal(t, tc.expectErr, err != nil, err)
+		})
+	}
+}
+
+func TestConcurrencyQuota_Allow(t *testing.T) {
+	qm, err := NewManager(logging.NewVaultLogger(log.Trace), nil, metricsutil.BlackholeSink())
+	require.NoError(t, err)
+
+	cq := NewConcurrencyQuota("test-concurrency", "", "kv/", "", "", 2, false)
+	require.NoError(t, qm.SetQuota(context.Background(), TypeConcurrency.String(), cq, false))
+
+	req := &Request{Type: TypeConcurrency, MountPath: "kv/", ClientAddress: "127.0.0.1"}
+
+	var accesses []Access
+	for i := 0; i < 2; i++ {
+		resp, err := qm.ApplyQuota(context.Background(), req)
+		require.NoError(t, err)
+		require.True(t, resp.Allowed)
+		accesses = append(accesses, resp.Access)
+	}
+
+	resp, err := qm.ApplyQuota(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, resp.Allowed)
+
+	qm.ReleaseConcurrencyQuota(accesses[0])
+	require.Equal(t, 1, cq.numInFlight(""))
+
+	resp, err = qm.ApplyQuota(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+
+	qm.ReleaseConcurrencyQuota(accesses[1])
+	qm.ReleaseConcurrencyQuota(resp.Access)
+	require.Equal(t, 0, cq.numInFlight(""))
+}
+
+func TestConcurrencyQuota_AllowPerClient(t *testing.T) {
+	cq := NewConcurrencyQuota("test-concurrency", "", "kv/", "", "", 1, true)
+	require.NoError(t, cq.initialize(logging.NewVaultLogger(log.Trace), metricsutil.BlackholeSink()))
+
+	_, err := cq.allow(context.Background(), &Request{})
+	require.Error(t, err)
+
+	resp, err := cq.allow(context.Background(), &Request{ClientAddress: "127.0.0.1"})
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+
+	resp, err = cq.allow(context.Background(), &Request{ClientAddress: "127.0.0.1"})
+	require.NoError(t, err)
+	require.False(t, resp.Allowed)
+
+	resp, err = cq.allow(context.Background(), &Request{ClientAddress: "127.0.0.2"})
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+	require.Equal(t, 1, cq.numInFlight("127.0.0.1"))
+	require.Equal(t, 1, cq.numInFlight("127.0.0.2"))
+}
@@ -5,8 +5,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/armon/go-metrics"
@@ -32,6 +35,44 @@ const (
 	EnvVaultEnableRateLimitAuditLogging = "VAULT_ENABLE_RATE_LIMIT_AUDIT_LOGGING"
 )
 
+// ClientKeyType defines the request property used to identify the client to
+// which a rate limit bucket belongs.
+type ClientKeyType string
+
+const (
+	// ClientKeyIPAddress buckets requests by client IP address
+	ClientKeyIPAddress ClientKeyType = "ip_address"
+
+	// ClientKeyEntityID buckets requests by the identity entity of the client
+	// token
+	ClientKeyEntityID ClientKeyType = "entity_id"
+
+	// ClientKeyRole buckets login requests by the auth role they log in with
+	ClientKeyRole ClientKeyType = "role"
+
+	// ClientKeyTokenAccessor buckets requests by the accessor of the client
+	// token
+	ClientKeyTokenAccessor ClientKeyType = "token_accessor"
+)
+
+// ParseClientKeyType validates the given value and returns it as a
+// ClientKeyType. An empty value defaults to ClientKeyIPAddress.
+func ParseClientKeyType(val string) (ClientKeyType, error) {
+	switch ClientKeyType(val) {
+	case "", ClientKeyIPAddress:
+		return ClientKeyIPAddress, nil
+	case ClientKeyEntityID, ClientKeyRole, ClientKeyTokenAccessor:
+		return ClientKeyType(val), nil
+	}
+	return "", fmt.Errorf("invalid client key type: %q", val)
+}
+
+// RequiresToken returns if the client key can only be determined from the
+// client token of the request.
+func (k ClientKeyType) RequiresToken() bool {
+	return k == ClientKeyEntityID || k == ClientKeyTokenAccessor
+}
+
 // Ensure that RateLimitQuota implements the Quota interface
 var _ Quota = (*RateLimitQuota)(nil)
 
@@ -72,6 +113,12 @@ type RateLimitQuota struct {
 	// reaches the rate limit.
 	BlockInterval time.Duration `json:"block_interval"`
 
+	// ClientKey defines the request property used to identify a client. Each
+	// client is given its own rate limit bucket. Requests for which the client
+	// key cannot be determined, such as unauthenticated requests when keying
+	// on the entity ID, are bucketed by client IP address.
+	ClientKey ClientKeyType `json:"client_key"`
+
 	lock                *sync.RWMutex
 	store               limiter.Store
 	logger              log.Logger
@@ -79,8 +126,43 @@ type RateLimitQuota struct {
 	purgeInterval       time.Duration
 	staleAge            time.Duration
 	blockedClients      sync.Map
-	purgeBlocked        bool
-	closePurgeBlockedCh chan struct{}
+	clientUsage         sync.Map
+	purgeClients        bool
+	closePurgeClientsCh chan struct{}
+}
+
+// clientUsage tracks the requests made by a single client of a rate limit
+// quota.
+type clientUsage struct {
+	lastSeen atomic.Int64
+	allowed  atomic.Uint64
+	rejected atomic.Uint64
+}
+
+// RateLimitClientUsage describes the current usage of the rate limit bucket of
+// a single client.
+type RateLimitClientUsage struct {
+	// Key identifies the client, prefixed with the type of the client key
+	Key string `json:"key"`
+
+	// Limit is the number of requests allowed per interval
+	Limit uint64 `json:"limit"`
+
+	// Remaining is the number of requests left in the current interval
+	Remaining uint64 `json:"remaining"`
+
+	// Allowed is the number of requests allowed since the client was first seen
+	Allowed uint64 `json:"allowed"`
+
+	// Rejected is the number of requests rejected since the client was first
+	// seen
+	Rejected uint64 `json:"rejected"`
+
+	// LastSeen is the time of the last request made by the client
+	LastSeen time.Time `json:"last_seen"`
+
+	// BlockedUntil is set if the client is currently blocked
+	BlockedUntil time.Time `json:"blocked_until,omitempty"`
 }
 
 // NewRateLimitQuota creates a quota checker for imposing limits on the number
@@ -122,14 +204,15 @@ func (q *RateLimitQuota) Clone() Quota {
 		BlockInterval: q.BlockInterval,
 		Rate:          q.Rate,
 		Interval:      q.Interval,
+		ClientKey:     q.ClientKey,
 	}
 	return rlq
 }
 
 // initialize ensures the namespace and max requests are initialized, sets the ID
 // if it's currently empty, sets the purge interval and stale age to default
-// values, and finally starts the client purge go routine if it has been started
-// already. Note, initialize will reset the internal rateQuotas mapping.
+// values, and finally starts the client purge go routine if it has not been
+// started already. Note, initialize will reset the internal rateQuotas mapping.
 func (rlq *RateLimitQuota) initialize(logger log.Logger, ms *metricsutil.ClusterMetricSink) error {
 	if rlq.lock == nil {
 		rlq.lock = new(sync.RWMutex)
@@ -155,6 +238,12 @@ func (rlq *RateLimitQuota) initialize(logger log.Logger, ms *metricsutil.Cluster
 		return fmt.Errorf("invalid block interval: %v", rlq.BlockInterval)
 	}
 
+	clientKey, err := ParseClientKeyType(string(rlq.ClientKey))
+	if err != nil {
+		return err
+	}
+	rlq.ClientKey = clientKey
+
 	if logger != nil {
 		rlq.logger = logger
 	}
@@ -196,25 +285,28 @@ func (rlq *RateLimitQuota) initialize(logger log.Logger, ms *metricsutil.Cluster
 
 	rlq.store = rlStore
 	rlq.blockedClients = sync.Map{}
+	rlq.clientUsage = sync.Map{}
 
-	if rlq.BlockInterval > 0 && !rlq.purgeBlocked {
-		rlq.purgeBlocked = true
-		rlq.closePurgeBlockedCh = make(chan struct{})
-		go rlq.purgeBlockedClients()
+	if !rlq.purgeClients {
+		rlq.purgeClients = true
+		rlq.closePurgeClientsCh = make(chan struct{})
+		go rlq.purgeClientsLoop(rlq.closePurgeClientsCh)
 	}
 
 	return nil
 }
 
-// purgeBlockedClients performs a blocking process where every purgeInterval
+// purgeClientsLoop performs a blocking process where every purgeInterval
 // duration, we look at all blocked clients to potentially remove from the blocked
-// clients map.
+// clients map, and at all tracked clients to remove the stale ones.
 //
 // A blocked client will only be removed if the current time minus the time the
-// client was blocked at is greater than or equal to the block duration. The loop
-// will continue to run indefinitely until a value is	sent on the closePurgeBlockedCh
-// in which we stop the ticker and return.
-func (rlq *RateLimitQuota) purgeBlockedClients() {
+// client was blocked at is greater than or equal to the block duration. A
+// tracked client is stale if it has not made a request for at least the stale
+// age, which matches the age at which its limiter is removed from the store.
+// The loop will continue to run indefinitely until a value is sent on the
+// given close channel in which we stop the ticker and return.
+func (rlq *RateLimitQuota) purgeClientsLoop(closeCh chan struct{}) {
 	rlq.lock.RLock()
 	ticker := time.NewTicker(rlq.purgeInterval)
 	rlq.lock.RUnlock()
@@ -231,22 +323,26 @@ func (rlq *RateLimitQuota) purgeBlockedClients() {
 				return true
 			})
 
-		case <-rlq.closePurgeBlockedCh:
-			ticker.Stop()
+			rlq.clientUsage.Range(func(key, value interface{}) bool {
+				lastSeen := time.Unix(0, value.(*clientUsage).lastSeen.Load())
+				if t.Sub(lastSeen) >= rlq.staleAge {
+					rlq.clientUsage.Delete(key)
+				}
 
-			rlq.lock.Lock()
-			rlq.purgeBlocked = false
-			rlq.lock.Unlock()
+				return true
+			})
 
+		case <-closeCh:
+			ticker.Stop()
 			return
 		}
 	}
 }
 
-func (rlq *RateLimitQuota) getPurgeBlocked() bool {
+func (rlq *RateLimitQuota) getPurgeClients() bool {
 	rlq.lock.RLock()
 	defer rlq.lock.RUnlock()
-	return rlq.purgeBlocked
+	return rlq.purgeClients
 }
 
 func (rlq *RateLimitQuota) numBlockedClients() int {
@@ -262,6 +358,79 @@ func (rlq *RateLimitQuota) numBlockedClients() int {
 	return size
 }
 
+func (rlq *RateLimitQuota) numTrackedClients() int {
+	size := 0
+	rlq.clientUsage.Range(func(_, _ interface{}) bool {
+		size++
+		return true
+	})
+
+	return size
+}
+
+// clientKey returns the key identifying the rate limit bucket of the client
+// making the request. The key is prefixed with the type of client key used so
+// that a client falling back to its IP address can't share a bucket with
+// another client.
+func (rlq *RateLimitQuota) clientKey(req *Request) string {
+	var key string
+	switch rlq.ClientKey {
+	case ClientKeyEntityID:
+		key = req.EntityID
+	case ClientKeyRole:
+		key = req.Role
+	case ClientKeyTokenAccessor:
+		key = req.ClientTokenAccessor
+	}
+
+	if key == "" {
+		return string(ClientKeyIPAddress) + ":" + req.ClientAddress
+	}
+
+	return string(rlq.ClientKey) + ":" + key
+}
+
+// ClientUsage returns the usage of the rate limit bucket of every client that
+// made a request within the stale age of the quota, sorted by key.
+func (rlq *RateLimitQuota) ClientUsage(ctx context.Context) ([]*RateLimitClientUsage, error) {
+	var ret []*RateLimitClientUsage
+	var err error
+	rlq.clientUsage.Range(func(key, value interface{}) bool {
+		usage := value.(*clientUsage)
+		var limit, remaining uint64
+		limit, remaining, err = rlq.store.Get(ctx, key.(string))
+		if err != nil {
+			return false
+		}
+
+		cu := &RateLimitClientUsage{
+			Key:       key.(string),
+			Limit:     limit,
+			Remaining: remaining,
+			Allowed:   usage.allowed.Load(),
+			Rejected:  usage.rejected.Load(),
+			LastSeen:  time.Unix(0, usage.lastSeen.Load()),
+		}
+		if v, ok := rlq.blockedClients.Load(key); ok {
+			if blockedUntil := v.(time.Time).Add(rlq.BlockInterval); time.Now().Before(blockedUntil) {
+				cu.BlockedUntil = blockedUntil
+			}
+		}
+		ret = append(ret, cu)
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Key < ret[j].Key
+	})
+
+	return ret, nil
+}
+
 // quotaID returns the identifier of the quota rule
 func (rlq *RateLimitQuota) quotaID() string {
 	return rlq.ID
@@ -273,25 +442,34 @@ func (rlq *RateLimitQuota) QuotaName() string {
 }
 
 // allow decides if the request is allowed by the quota. An error will be
-// returned if the request ID or address is empty. If the path is exempt, the
-// quota will not be evaluated. Otherwise, the client rate limiter is retrieved
-// by address and the rate limit quota is checked against that limiter.
+// returned if the client key and address are both empty. If the path is exempt,
+// the quota will not be evaluated. Otherwise, the client rate limiter is
+// retrieved by client key and the rate limit quota is checked against that
+// limiter.
 func (rlq *RateLimitQuota) allow(ctx context.Context, req *Request) (Response, error) {
 	resp := Response{
 		Headers: make(map[string]string),
 	}
 
-	if req.ClientAddress == "" {
+	key := rlq.clientKey(req)
+	if req.ClientAddress == "" && strings.HasPrefix(key, string(ClientKeyIPAddress)+":") {
 		return resp, fmt.Errorf("missing request client address in quota request")
 	}
 
 	var retryAfter string
 
+	usageRaw, _ := rlq.clientUsage.LoadOrStore(key, new(clientUsage))
+	usage := usageRaw.(*clientUsage)
+	usage.lastSeen.Store(time.Now().UnixNano())
+
 	defer func() {
 		if !resp.Allowed {
+			usage.rejected.Add(1)
 			resp.Headers[httplimit.HeaderRetryAfter] = retryAfter
 			rlq.metricSink.IncrCounterWithLabels([]string{"quota", "rate_limit", "violation"}, 1, []metrics.Label{{"name", rlq.Name}})
+			return
 		}
+		usage.allowed.Add(1)
 	}()
 
 	// Check if the client is currently blocked and if so, deny the request. Note,
@@ -299,11 +477,11 @@ func (rlq *RateLimitQuota) allow(ctx context.Context, req *Request) (Response, e
 	// of purging blocked clients may not yield a false negative. In other words,
 	// a client may no longer be considered blocked whereas the purging interval
 	// has yet to run.
-	if v, ok := rlq.blockedClients.Load(req.ClientAddress); ok {
+	if v, ok := rlq.blockedClients.Load(key); ok {
 		blockedAt := v.(time.Time)
 		if time.Since(blockedAt) >= rlq.BlockInterval {
 			// allow the request and remove the blocked client
-			rlq.blockedClients.Delete(req.ClientAddress)
+			rlq.blockedClients.Delete(key)
 		} else {
 			// deny the request and return early
 			resp.Allowed = false
@@ -312,7 +490,7 @@ func (rlq *RateLimitQuota) allow(ctx context.Context, req *Request) (Response, e
 		}
 	}
 
-	limit, remaining, reset, allow, err := rlq.store.Take(ctx, req.ClientAddress)
+	limit, remaining, reset, allow, err := rlq.store.Take(ctx, key)
 	if err != nil {
 		return resp, err
 	}
@@ -325,20 +503,26 @@ func (rlq *RateLimitQuota) allow(ctx context.Context, req *Request) (Response, e
 
 	// If the request is not allowed (i.e. rate limit threshold reached) and blocking
 	// is enabled, we add the client to the set of blocked clients.
-	if !resp.Allowed && rlq.purgeBlocked {
+	if !resp.Allowed && rlq.BlockInterval > 0 {
 		blockedAt := time.Now()
 		retryAfter = strconv.Itoa(int(time.Until(blockedAt.Add(rlq.BlockInterval)).Seconds()))
-		rlq.blockedClients.Store(req.ClientAddress, blockedAt)
+		rlq.blockedClients.Store(key, blockedAt)
 	}
 
 	return resp, nil
 }
 
-// close stops the current running client purge loop.
-// It should be called with the write lock held.
+// close stops the current running client purge loop and closes the rate
+// limiter store. It takes the quota's lock itself, so it must not be called
+// with the lock held.
 func (rlq *RateLimitQuota) close(ctx context.Context) error {
-	if rlq.purgeBlocked {
-		close(rlq.closePurgeBlockedCh)
+	if rlq.lock != nil {
+		rlq.lock.Lock()
+		if rlq.purgeClients {
+			close(rlq.closePurgeClientsCh)
+			rlq.purgeClients = false
+		}
+		rlq.lock.Unlock()
 	}
 
 	if rlq.store != nil {
@@ -49,7 +49,7 @@ func TestRateLimitQuota_Close(t *testing.T) {
 	require.NoError(t, rlq.close(context.Background()))
 
 	time.Sleep(time.Second) // allow enough time for purgeClientsLoop to receive on closeCh
-	require.False(t, rlq.getPurgeBlocked(), "expected blocked client purging to be disabled after explicit close")
+	require.False(t, rlq.getPurgeClients(), "expected blocked client purging to be disabled after explicit close")
 }
 
 func TestRateLimitQuota_Allow(t *testing.T) {
@@ -144,7 +144,7 @@ func TestRateLimitQuota_Allow_WithBlock(t *testing.T) {
 
 	require.NoError(t, rlq.initialize(logging.NewVaultLogger(log.Trace), metricsutil.BlackholeSink()))
 	defer rlq.close(context.Background())
-	require.True(t, rlq.getPurgeBlocked())
+	require.True(t, rlq.getPurgeClients())
 
 	var wg sync.WaitGroup
 
@@ -224,3 +224,85 @@ func TestRateLimitQuota_Update(t *testing.T) {
 
 	require.Nil(t, quota.close(context.Background()))
 }
+
+func TestRateLimitQuota_ClientKey(t *testing.T) {
+	testCases := []struct {
+		clientKey ClientKeyType
+		req       *Request
+		expected  string
+	}{
+		{"", &Request{ClientAddress: "127.0.0.1", EntityID: "e1"}, "ip_address:127.0.0.1"},
+		{ClientKeyEntityID, &Request{ClientAddress: "127.0.0.1", EntityID: "e1"}, "entity_id:e1"},
+		{ClientKeyEntityID, &Request{ClientAddress: "127.0.0.1"}, "ip_address:127.0.0.1"},
+		{ClientKeyRole, &Request{ClientAddress: "127.0.0.1", Role: "r1"}, "role:r1"},
+		{ClientKeyTokenAccessor, &Request{ClientAddress: "127.0.0.1", ClientTokenAccessor: "a1"}, "token_accessor:a1"},
+	}
+
+	for _, tc := range testCases {
+		rlq := NewRateLimitQuota("test-rate-limiter", "qa", "/foo/bar", "", "", 10, time.Second, 0)
+		rlq.ClientKey = tc.clientKey
+		require.NoError(t, rlq.initialize(logging.NewVaultLogger(log.Trace), metricsutil.BlackholeSink()))
+		require.Equal(t, tc.expected, rlq.clientKey(tc.req))
+		require.NoError(t, rlq.close(context.Background()))
+	}
+
+	rlq := NewRateLimitQuota("test-rate-limiter", "qa", "/foo/bar", "", "", 10, time.Second, 0)
+	rlq.ClientKey = "bogus"
+	require.Error(t, rlq.initialize(logging.NewVaultLogger(log.Trace), metricsutil.BlackholeSink()))
+}
+
+func TestRateLimitQuota_ClientUsage(t *testing.T) {
+	rlq := &RateLimitQuota{
+		Name:          "test-rate-limiter",
+		Type:          TypeRateLimit,
+		NamespacePath: "qa",
+		MountPath:     "/foo/bar",
+		Rate:          2,
+		Interval:      time.Minute,
+		ClientKey:     ClientKeyEntityID,
+
+		// override values to lower durations for testing purposes
+		purgeInterval: time.Second,
+		staleAge:      2 * time.Second,
+	}
+
+	require.NoError(t, rlq.initialize(logging.NewVaultLogger(log.Trace), metricsutil.BlackholeSink()))
+	defer rlq.close(context.Background())
+
+	// Two entities sharing the same address are rate limited separately
+	for i := 0; i < 3; i++ {
+		_, err := rlq.allow(context.Background(), &Request{ClientAddress: "127.0.0.1", EntityID: "e1"})
+		require.NoError(t, err)
+	}
+	resp, err := rlq.allow(context.Background(), &Request{ClientAddress: "127.0.0.1", EntityID: "e2"})
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+
+	usage, err := rlq.ClientUsage(context.Background())
+	require.NoError(t, err)
+	require.Len(t, usage, 2)
+	require.Equal(t, "entity_id:e1", usage[0].Key)
+	require.Equal(t, uint64(2), usage[0].Allowed)
+	require.Equal(t, uint64(1), usage[0].Rejected)
+	require.Equal(t, uint64(0), usage[0].Remaining)
+	require.Equal(t, "entity_id:e2", usage[1].Key)
+	require.Equal(t, uint64(1), usage[1].Allowed)
+	require.Equal(t, uint64(1), usage[1].Remaining)
+
+	// Stale clients are evicted by the purge loop
+	func() {
+		timeout := time.After(10 * time.Second)
+		ticker := time.Tick(time.Second)
+		for {
+			select {
+			case <-timeout:
+				require.Failf(t, "timeout exceeded waiting for stale clients to be purged", "num tracked: %d", rlq.numTrackedClients())
+
+			case <-ticker:
+				if rlq.numTrackedClients() == 0 {
+					return
+				}
+			}
+		}
+	}()
+}
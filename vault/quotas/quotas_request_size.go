@@ -0,0 +1,289 @@
+package quotas
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/helper/metricsutil"
+	"github.com/hashicorp/vault/sdk/helper/cryptoutil"
+	"github.com/sethvargo/go-limiter/httplimit"
+)
+
+// Ensure that RequestSizeQuota implements the Quota interface
+var _ Quota = (*RequestSizeQuota)(nil)
+
+// RequestSizeQuota represents the quota rule properties that is used to limit
+// the size of individual requests and the number of request bytes a client can
+// send in a given interval for a namespace or mount.
+type RequestSizeQuota struct {
+	// ID is the identifier of the quota
+	ID string `json:"id"`
+
+	// Type of quota this represents
+	Type Type `json:"type"`
+
+	// Name of the quota rule
+	Name string `json:"name"`
+
+	// NamespacePath is the path of the namespace to which this quota is
+	// applicable.
+	NamespacePath string `json:"namespace_path"`
+
+	// MountPath is the path of the mount to which this quota is applicable
+	MountPath string `json:"mount_path"`
+
+	// Role is the role on an auth mount to apply the quota to upon /login requests
+	// Not applicable for use with path suffixes
+	Role string `json:"role"`
+
+	// PathSuffix is the path suffix to which this quota is applicable
+	PathSuffix string `json:"path_suffix"`
+
+	// MaxRequestSize defines the maximum number of bytes allowed in a single
+	// request body. A value of zero disables the check.
+	MaxRequestSize int64 `json:"max_request_size"`
+
+	// Bandwidth defines the number of request body bytes each client is allowed
+	// to send per Interval. A value of zero disables the check.
+	Bandwidth int64 `json:"bandwidth"`
+
+	// Interval defines the duration to which bandwidth limiting is applied.
+	Interval time.Duration `json:"interval"`
+
+	lock          *sync.Mutex
+	clients       map[string]*bandwidthWindow
+	logger        log.Logger
+	metricSink    *metricsutil.ClusterMetricSink
+	purgeInterval time.Duration
+	purgeClients  bool
+	closePurgeCh  chan struct{}
+}
+
+// bandwidthWindow tracks the number of bytes sent by a client in the current
+// interval.
+type bandwidthWindow struct {
+	start time.Time
+	used  int64
+}
+
+// NewRequestSizeQuota creates a quota checker for imposing limits on the size
+// of requests. An interval time duration of zero may be provided, which will
+// default to 1s when initialized.
+func NewRequestSizeQuota(name, nsPath, mountPath, pathSuffix, role string, maxRequestSize, bandwidth int64, interval time.Duration) *RequestSizeQuota {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		// Fall back to generating with a hash of the name, later in initialize
+		id = ""
+	}
+	return &RequestSizeQuota{
+		Name:           name,
+		ID:             id,
+		Type:           TypeRequestSize,
+		NamespacePath:  nsPath,
+		MountPath:      mountPath,
+		Role:           role,
+		PathSuffix:     pathSuffix,
+		MaxRequestSize: maxRequestSize,
+		Bandwidth:      bandwidth,
+		Interval:       interval,
+		purgeInterval:  DefaultRateLimitPurgeInterval,
+	}
+}
+
+func (rsq *RequestSizeQuota) Clone() Quota {
+	return &RequestSizeQuota{
+		ID:             rsq.ID,
+		Name:           rsq.Name,
+		MountPath:      rsq.MountPath,
+		Role:           rsq.Role,
+		Type:           rsq.Type,
+		NamespacePath:  rsq.NamespacePath,
+		PathSuffix:     rsq.PathSuffix,
+		MaxRequestSize: rsq.MaxRequestSize,
+		Bandwidth:      rsq.Bandwidth,
+		Interval:       rsq.Interval,
+	}
+}
+
+// initialize ensures the namespace, interval and limits are initialized, sets
+// the ID if it's currently empty and starts the client purge go routine if
+// bandwidth limiting is enabled. Note, initialize will reset the client
+// bandwidth windows.
+func (rsq *RequestSizeQuota) initialize(logger log.Logger, ms *metricsutil.ClusterMetricSink) error {
+	if rsq.lock == nil {
+		rsq.lock = new(sync.Mutex)
+	}
+
+	rsq.lock.Lock()
+	defer rsq.lock.Unlock()
+
+	// Memdb requires a non-empty value for indexing
+	if rsq.NamespacePath == "" {
+		rsq.NamespacePath = "root"
+	}
+
+	if rsq.Interval == 0 {
+		rsq.Interval = time.Second
+	}
+
+	if rsq.MaxRequestSize < 0 {
+		return fmt.Errorf("invalid max request size: %v", rsq.MaxRequestSize)
+	}
+
+	if rsq.Bandwidth < 0 {
+		return fmt.Errorf("invalid bandwidth: %v", rsq.Bandwidth)
+	}
+
+	if rsq.MaxRequestSize == 0 && rsq.Bandwidth == 0 {
+		return fmt.Errorf("at least one of max request size or bandwidth must be set")
+	}
+
+	if logger != nil {
+		rsq.logger = logger
+	}
+
+	if rsq.metricSink == nil {
+		rsq.metricSink = ms
+	}
+
+	if rsq.ID == "" {
+		rsq.ID = hex.EncodeToString(cryptoutil.Blake2b256Hash(rsq.Name))
+	}
+
+	if rsq.purgeInterval == 0 {
+		rsq.purgeInterval = DefaultRateLimitPurgeInterval
+	}
+
+	rsq.clients = make(map[string]*bandwidthWindow)
+
+	if rsq.Bandwidth > 0 && !rsq.purgeClients {
+		rsq.purgeClients = true
+		rsq.closePurgeCh = make(chan struct{})
+		go rsq.purgeStaleClients(rsq.closePurgeCh)
+	}
+
+	return nil
+}
+
+// purgeStaleClients performs a blocking process where every purgeInterval
+// duration, we remove all clients whose bandwidth window has elapsed. The loop
+// will continue to run indefinitely until a value is sent on the given close
+// channel in which we stop the ticker and return.
+func (rsq *RequestSizeQuota) purgeStaleClients(closeCh chan struct{}) {
+	rsq.lock.Lock()
+	ticker := time.NewTicker(rsq.purgeInterval)
+	rsq.lock.Unlock()
+
+	for {
+		select {
+		case t := <-ticker.C:
+			rsq.lock.Lock()
+			for key, window := range rsq.clients {
+				if t.Sub(window.start) >= rsq.Interval {
+					delete(rsq.clients, key)
+				}
+			}
+			rsq.lock.Unlock()
+
+		case <-closeCh:
+			ticker.Stop()
+			return
+		}
+	}
+}
+
+func (rsq *RequestSizeQuota) getPurgeClients() bool {
+	rsq.lock.Lock()
+	defer rsq.lock.Unlock()
+	return rsq.purgeClients
+}
+
+// quotaID returns the identifier of the quota rule
+func (rsq *RequestSizeQuota) quotaID() string {
+	return rsq.ID
+}
+
+// QuotaName returns the name of the quota rule
+func (rsq *RequestSizeQuota) QuotaName() string {
+	return rsq.Name
+}
+
+// allow decides if the request is allowed by the quota. Requests larger than
+// the maximum request size are always rejected. If bandwidth limiting is
+// enabled, the request size is added to the client's usage for the current
+// interval, and the request is rejected if that exceeds the bandwidth. An error
+// will be returned if bandwidth limiting is enabled and the client address is
+// empty.
+func (rsq *RequestSizeQuota) allow(_ context.Context, req *Request) (Response, error) {
+	resp := Response{
+		Headers: make(map[string]string),
+	}
+
+	defer func() {
+		if !resp.Allowed {
+			rsq.metricSink.IncrCounterWithLabels([]string{"quota", "request_size", "violation"}, 1, []metrics.Label{{Name: "name", Value: rsq.Name}})
+		}
+	}()
+
+	if rsq.MaxRequestSize > 0 && req.RequestSize > rsq.MaxRequestSize {
+		return resp, nil
+	}
+
+	if rsq.Bandwidth == 0 {
+		resp.Allowed = true
+		return resp, nil
+	}
+
+	if req.ClientAddress == "" {
+		return resp, fmt.Errorf("missing request client address in quota request")
+	}
+
+	rsq.lock.Lock()
+	defer rsq.lock.Unlock()
+
+	now := time.Now()
+	window, ok := rsq.clients[req.ClientAddress]
+	if !ok || now.Sub(window.start) >= rsq.Interval {
+		window = &bandwidthWindow{start: now}
+		rsq.clients[req.ClientAddress] = window
+	}
+
+	if window.used+req.RequestSize > rsq.Bandwidth {
+		// Round up so that the client never retries before the window ends
+		resp.Headers[httplimit.HeaderRetryAfter] = strconv.Itoa(int(math.Ceil(time.Until(window.start.Add(rsq.Interval)).Seconds())))
+		return resp, nil
+	}
+
+	window.used += req.RequestSize
+	resp.Allowed = true
+
+	return resp, nil
+}
+
+// close stops the current running client purge loop.
+// It should be called with the write lock held.
+func (rsq *RequestSizeQuota) close(_ context.Context) error {
+	if rsq.lock != nil {
+		rsq.lock.Lock()
+		if rsq.purgeClients {
+			close(rsq.closePurgeCh)
+			rsq.purgeClients = false
+		}
+		rsq.lock.Unlock()
+	}
+
+	return nil
+}
+
+func (rsq *RequestSizeQuota) handleRemount(mountpath, nspath string) {
+	rsq.MountPath = mountpath
+	rsq.NamespacePath = nspath
+}
@@ -0,0 +1,90 @@
+package quotas
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/helper/metricsutil"
+	"github.com/hashicorp/vault/sdk/helper/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRequestSizeQuota(t *testing.T) {
+	testCases := []struct {
+		name      string
+		rsq       *RequestSizeQuota
+		expectErr bool
+	}{
+		{"valid max request size", NewRequestSizeQuota("test-request-size", "qa", "/foo/bar", "", "", 1024, 0, 0), false},
+		{"valid bandwidth", NewRequestSizeQuota("test-request-size", "qa", "/foo/bar", "", "", 0, 1024, time.Second), false},
+		{"no limits", NewRequestSizeQuota("test-request-size", "qa", "/foo/bar", "", "", 0, 0, time.Second), true},
+		{"negative bandwidth", NewRequestSizeQuota("test-request-size", "qa", "/foo/bar", "", "", 0, -1, time.Second), true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rsq.initialize(logging.NewVaultLogger(log.Trace), metricsutil.BlackholeSink())
+			require.Equal(t, tc.expectErr, err != nil, err)
+			if err == nil {
+				require.Nil(t, tc.rsq.close(context.Background()))
+			}
+		})
+	}
+}
+
+func TestRequestSizeQuota_Close(t *testing.T) {
+	rsq := NewRequestSizeQuota("test-request-size", "qa", "/foo/bar", "", "", 0, 1024, time.Second)
+	require.NoError(t, rsq.initialize(logging.NewVaultLogger(log.Trace), metricsutil.BlackholeSink()))
+	require.True(t, rsq.getPurgeClients())
+	require.NoError(t, rsq.close(context.Background()))
+
+	time.Sleep(time.Second) // allow enough time for purgeStaleClients to receive on closePurgeCh
+	require.False(t, rsq.getPurgeClients(), "expected client purging to be disabled after explicit close")
+}
+
+func TestRequestSizeQuota_AllowMaxRequestSize(t *testing.T) {
+	rsq := NewRequestSizeQuota("test-request-size", "qa", "/foo/bar", "", "", 100, 0, 0)
+	require.NoError(t, rsq.initialize(logging.NewVaultLogger(log.Trace), metricsutil.BlackholeSink()))
+	defer rsq.close(context.Background())
+
+	resp, err := rsq.allow(context.Background(), &Request{RequestSize: 100})
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+
+	resp, err = rsq.allow(context.Background(), &Request{RequestSize: 101})
+	require.NoError(t, err)
+	require.False(t, resp.Allowed)
+}
+
+func TestRequestSizeQuota_AllowBandwidth(t *testing.T) {
+	rsq := NewRequestSizeQuota("test-request-size", "qa", "/foo/bar", "", "", 0, 100, 2*time.Second)
+	require.NoError(t, rsq.initialize(logging.NewVaultLogger(log.Trace), metricsutil.BlackholeSink()))
+	defer rsq.close(context.Background())
+
+	_, err := rsq.allow(context.Background(), &Request{RequestSize: 10})
+	require.Error(t, err)
+
+	resp, err := rsq.allow(context.Background(), &Request{ClientAddress: "127.0.0.1", RequestSize: 60})
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+
+	resp, err = rsq.allow(context.Background(), &Request{ClientAddress: "127.0.0.1", RequestSize: 60})
+	require.NoError(t, err)
+	require.False(t, resp.Allowed)
+	require.Equal(t, "2", resp.Headers["Retry-After"])
+
+	// A different client has its own bandwidth budget
+	resp, err = rsq.allow(context.Background(), &Request{ClientAddress: "127.0.0.2", RequestSize: 60})
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+
+	time.Sleep(2 * time.Second)
+
+	resp, err = rsq.allow(context.Background(), &Request{ClientAddress: "127.0.0.1", RequestSize: 60})
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+}
@@ -14,6 +14,8 @@ import (
 func quotaTypes() []string {
 	return []string{
 		TypeRateLimit.String(),
+		TypeConcurrency.String(),
+		TypeRequestSize.String(),
 	}
 }
 
@@ -320,6 +320,11 @@ func (c *Core) setupRaftActiveNode(ctx context.Context) error {
 			return err
 		}
 	}
+
+	if err := c.setupRaftAutoSnapshots(ctx); err != nil {
+		return err
+	}
+
 	return c.startPeriodicRaftTLSRotate(ctx)
 }
 
@@ -337,6 +342,7 @@ func (c *Core) stopRaftActiveNode() {
 
 	c.pendingRaftPeers = nil
 	c.stopPeriodicRaftTLSRotate()
+	c.stopRaftAutoSnapshots()
 }
 
 func (c *Core) startPeriodicRaftTLSRotate(ctx context.Context) error {
@@ -0,0 +1,467 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/physical/raft"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	// raftAutoSnapshotConfigPath is the barrier path prefix under which the
+	// automated snapshot configurations are stored
+	raftAutoSnapshotConfigPath = "core/raft/snapshot-auto/config/"
+
+	// raftAutoSnapshotStatusPath is the barrier path prefix under which the
+	// status of the automated snapshot configurations is stored
+	raftAutoSnapshotStatusPath = "core/raft/snapshot-auto/status/"
+
+	raftAutoSnapshotStorageLocal     = "local"
+	raftAutoSnapshotDefaultPrefix    = "vault-snapshot"
+	raftAutoSnapshotFileSuffix       = ".snap"
+	raftAutoSnapshotMinimumInterval  = time.Second
+	raftAutoSnapshotDirectoryPerms   = 0o700
+	raftAutoSnapshotDefaultRetention = 1
+)
+
+// raftAutoSnapshotConfig describes a schedule of raft snapshots and where the
+// snapshots are stored
+type raftAutoSnapshotConfig struct {
+	Name        string        `json:"name"`
+	Interval    time.Duration `json:"interval"`
+	Retain      int           `json:"retain"`
+	StorageType string        `json:"storage_type"`
+	PathPrefix  string        `json:"path_prefix"`
+	FilePrefix  string        `json:"file_prefix"`
+}
+
+// raftAutoSnapshotStatus reports the outcome of the snapshots taken for an
+// automated snapshot configuration
+type raftAutoSnapshotStatus struct {
+	ConsecutiveErrors int       `json:"consecutive_errors"`
+	LastSnapshotStart time.Time `json:"last_snapshot_start"`
+	LastSnapshotEnd   time.Time `json:"last_snapshot_end"`
+	LastSnapshotError string    `json:"last_snapshot_error"`
+	LastSuccessTime   time.Time `json:"last_success_time"`
+	LastSuccessPath   string    `json:"last_success_path"`
+	LastFailureTime   time.Time `json:"last_failure_time"`
+	NextSnapshotStart time.Time `json:"next_snapshot_start"`
+	RetainedSnapshots []string  `json:"retained_snapshots"`
+}
+
+// raftAutoSnapshotManager takes the snapshots of the automated snapshot
+// configurations. It only runs on the active node.
+type raftAutoSnapshotManager struct {
+	l        sync.Mutex
+	storage  logical.Storage
+	snapshot func(io.Writer) error
+	logger   hclog.Logger
+	runners  map[string]*raftAutoSnapshotRunner
+}
+
+type raftAutoSnapshotRunner struct {
+	config *raftAutoSnapshotConfig
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+func newRaftAutoSnapshotManager(storage logical.Storage, snapshot func(io.Writer) error, logger hclog.Logger) *raftAutoSnapshotManager {
+	return &raftAutoSnapshotManager{
+		storage:  storage,
+		snapshot: snapshot,
+		logger:   logger,
+		runners:  make(map[string]*raftAutoSnapshotRunner),
+	}
+}
+
+// validate checks the configuration and sets the defaults
+func (c *raftAutoSnapshotConfig) validate() error {
+	if c.Interval < raftAutoSnapshotMinimumInterval {
+		return fmt.Errorf("interval must be at least %s", raftAutoSnapshotMinimumInterval)
+	}
+	if c.Retain == 0 {
+		c.Retain = raftAutoSnapshotDefaultRetention
+	}
+	if c.Retain < 0 {
+		return errors.New("retain must be a positive number")
+	}
+	if c.StorageType == "" {
+		c.StorageType = raftAutoSnapshotStorageLocal
+	}
+	if c.StorageType != raftAutoSnapshotStorageLocal {
+		return fmt.Errorf("unsupported storage_type %q", c.StorageType)
+	}
+	if c.PathPrefix == "" {
+		return errors.New("path_prefix is required")
+	}
+	if !filepath.IsAbs(c.PathPrefix) {
+		return errors.New("path_prefix must be an absolute path")
+	}
+	if c.FilePrefix == "" {
+		c.FilePrefix = raftAutoSnapshotDefaultPrefix
+	}
+	if strings.ContainsRune(c.FilePrefix, filepath.Separator) {
+		return errors.New("file_prefix must not contain a path separator")
+	}
+	return nil
+}
+
+func (m *raftAutoSnapshotManager) getConfig(ctx context.Context, name string) (*raftAutoSnapshotConfig, error) {
+	entry, err := m.storage.Get(ctx, raftAutoSnapshotConfigPath+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var config raftAutoSnapshotConfig
+	if err := entry.DecodeJSON(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (m *raftAutoSnapshotManager) listConfigs(ctx context.Context) ([]string, error) {
+	return m.storage.List(ctx, raftAutoSnapshotConfigPath)
+}
+
+// putConfig persists the configuration and (re)starts its schedule
+func (m *raftAutoSnapshotManager) putConfig(ctx context.Context, config *raftAutoSnapshotConfig) error {
+	entry, err := logical.StorageEntryJSON(raftAutoSnapshotConfigPath+config.Name, config)
+	if err != nil {
+		return err
+	}
+
+	m.lockStopped(config.Name)
+	defer m.l.Unlock()
+
+	if err := m.storage.Put(ctx, entry); err != nil {
+		return err
+	}
+	return m.startRunnerLocked(ctx, config)
+}
+
+// deleteConfig stops the schedule of the configuration and removes it along
+// with its status. Snapshots that were taken are left in place.
+func (m *raftAutoSnapshotManager) deleteConfig(ctx context.Context, name string) error {
+	m.lockStopped(name)
+	defer m.l.Unlock()
+
+	if err := m.storage.Delete(ctx, raftAutoSnapshotConfigPath+name); err != nil {
+		return err
+	}
+	return m.storage.Delete(ctx, raftAutoSnapshotStatusPath+name)
+}
+
+func (m *raftAutoSnapshotManager) getStatus(ctx context.Context, name string) (*raftAutoSnapshotStatus, error) {
+	entry, err := m.storage.Get(ctx, raftAutoSnapshotStatusPath+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var status raftAutoSnapshotStatus
+	if err := entry.DecodeJSON(&status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (m *raftAutoSnapshotManager) putStatus(ctx context.Context, name string, status *raftAutoSnapshotStatus) error {
+	entry, err := logical.StorageEntryJSON(raftAutoSnapshotStatusPath+name, status)
+	if err != nil {
+		return err
+	}
+	return m.storage.Put(ctx, entry)
+}
+
+// start loads all the configurations and starts their schedules
+func (m *raftAutoSnapshotManager) start(ctx context.Context) error {
+	names, err := m.listConfigs(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	for _, name := range names {
+		config, err := m.getConfig(ctx, name)
+		if err != nil {
+			return err
+		}
+		if config == nil {
+			continue
+		}
+		if err := m.startRunnerLocked(ctx, config); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// stop stops all the schedules and waits for running snapshots to finish. The
+// lock is held while waiting so that the schedules can't be started again
+// before then.
+func (m *raftAutoSnapshotManager) stop() {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	for name := range m.runners {
+		<-m.stopRunnerLocked(name)
+	}
+}
+
+// lockStopped takes the lock once the named configuration has no schedule and
+// no running snapshot, so that its status is not written afterwards. The lock
+// is released while waiting for a running snapshot to finish.
+func (m *raftAutoSnapshotManager) lockStopped(name string) {
+	for {
+		m.l.Lock()
+		doneCh := m.stopRunnerLocked(name)
+		if doneCh == nil {
+			return
+		}
+		m.l.Unlock()
+		<-doneCh
+	}
+}
+
+// stopRunnerLocked stops the schedule of the named configuration. It returns
+// a channel that is closed once a running snapshot has finished, or nil if
+// the configuration has no schedule.
+func (m *raftAutoSnapshotManager) stopRunnerLocked(name string) chan struct{} {
+	runner, ok := m.runners[name]
+	if !ok {
+		return nil
+	}
+	close(runner.stopCh)
+	delete(m.runners, name)
+	return runner.doneCh
+}
+
+func (m *raftAutoSnapshotManager) startRunnerLocked(ctx context.Context, config *raftAutoSnapshotConfig) error {
+	status, err := m.getStatus(ctx, config.Name)
+	if err != nil {
+		return err
+	}
+	if status == nil {
+		status = &raftAutoSnapshotStatus{}
+	}
+
+	// Resume the schedule from the last snapshot, which may have been taken by
+	// a previously active node
+	next := time.Now()
+	if !status.LastSnapshotStart.IsZero() {
+		if scheduled := status.LastSnapshotStart.Add(config.Interval); scheduled.After(next) {
+			next = scheduled
+		}
+	}
+
+	status.NextSnapshotStart = next
+	if err := m.putStatus(ctx, config.Name, status); err != nil {
+		return err
+	}
+
+	runner := &raftAutoSnapshotRunner{
+		config: config,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	m.runners[config.Name] = runner
+
+	go m.run(runner, status, next)
+
+	return nil
+}
+
+func (m *raftAutoSnapshotManager) run(runner *raftAutoSnapshotRunner, status *raftAutoSnapshotStatus, next time.Time) {
+	defer close(runner.doneCh)
+
+	logger := m.logger.With("name", runner.config.Name)
+	for {
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-runner.stopCh:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		m.takeSnapshot(logger, runner.config, status)
+		next = status.LastSnapshotStart.Add(runner.config.Interval)
+		status.NextSnapshotStart = next
+
+		if err := m.putStatus(context.Background(), runner.config.Name, status); err != nil {
+			logger.Error("failed to persist automated snapshot status", "error", err)
+		}
+	}
+}
+
+// takeSnapshot writes a snapshot to the configured directory, prunes the
+// snapshots exceeding the retention and records the outcome in the status
+func (m *raftAutoSnapshotManager) takeSnapshot(logger hclog.Logger, config *raftAutoSnapshotConfig, status *raftAutoSnapshotStatus) {
+	status.LastSnapshotStart = time.Now()
+
+	path, err := m.writeSnapshot(config, status.LastSnapshotStart)
+	status.LastSnapshotEnd = time.Now()
+	if err != nil {
+		logger.Error("failed to take automated snapshot", "error", err)
+		status.ConsecutiveErrors++
+		status.LastSnapshotError = err.Error()
+		status.LastFailureTime = status.LastSnapshotEnd
+		return
+	}
+
+	logger.Info("took automated snapshot", "path", path)
+	status.ConsecutiveErrors = 0
+	status.LastSnapshotError = ""
+	status.LastSuccessTime = status.LastSnapshotEnd
+	status.LastSuccessPath = path
+
+	retained, err := pruneRaftAutoSnapshots(config)
+	if err != nil {
+		logger.Error("failed to remove automated snapshots exceeding retention", "error", err)
+	}
+	status.RetainedSnapshots = retained
+}
+
+func (m *raftAutoSnapshotManager) writeSnapshot(config *raftAutoSnapshotConfig, now time.Time) (string, error) {
+	if err := os.MkdirAll(config.PathPrefix, raftAutoSnapshotDirectoryPerms); err != nil {
+		return "", fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	// Write to a temporary file first so that a partially written snapshot
+	// is never mistaken for a complete one
+	f, err := os.CreateTemp(config.PathPrefix, config.FilePrefix+"-*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := m.snapshot(f); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to sync snapshot file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to close snapshot file: %w", err)
+	}
+
+	path := filepath.Join(config.PathPrefix, fmt.Sprintf("%s-%d%s", config.FilePrefix, now.UnixNano(), raftAutoSnapshotFileSuffix))
+	if err := os.Rename(f.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to rename snapshot file: %w", err)
+	}
+
+	return path, nil
+}
+
+// pruneRaftAutoSnapshots removes the oldest snapshots of the configuration
+// exceeding its retention and returns the paths of the retained snapshots
+func pruneRaftAutoSnapshots(config *raftAutoSnapshotConfig) ([]string, error) {
+	entries, err := os.ReadDir(config.PathPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	type snapshotFile struct {
+		path string
+		time int64
+	}
+	var files []snapshotFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		// Configurations can share a directory, and the file prefix of one
+		// can be a prefix of another's, so only the files named exactly like
+		// the snapshots of this configuration are considered.
+		t, ok := raftAutoSnapshotFileTime(config.FilePrefix, entry.Name())
+		if !ok {
+			continue
+		}
+		files = append(files, snapshotFile{path: filepath.Join(config.PathPrefix, entry.Name()), time: t})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].time < files[j].time
+	})
+	snapshots := make([]string, 0, len(files))
+	for _, file := range files {
+		snapshots = append(snapshots, file.path)
+	}
+
+	var retErr error
+	for len(snapshots) > config.Retain {
+		if err := os.Remove(snapshots[0]); err != nil && !os.IsNotExist(err) {
+			retErr = err
+			break
+		}
+		snapshots = snapshots[1:]
+	}
+
+	return snapshots, retErr
+}
+
+// raftAutoSnapshotFileTime returns the snapshot time embedded in the name of
+// a snapshot file written by writeSnapshot, and false if the file is not a
+// snapshot with the given file prefix.
+func raftAutoSnapshotFileTime(filePrefix, name string) (int64, bool) {
+	if !strings.HasPrefix(name, filePrefix+"-") || !strings.HasSuffix(name, raftAutoSnapshotFileSuffix) {
+		return 0, false
+	}
+	timestamp := strings.TrimSuffix(strings.TrimPrefix(name, filePrefix+"-"), raftAutoSnapshotFileSuffix)
+	if timestamp == "" || strings.TrimLeft(timestamp, "0123456789") != "" {
+		return 0, false
+	}
+	t, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return t, true
+}
+
+// setupRaftAutoSnapshots starts the automated snapshots when raft is used as
+// storage. It should only be called on the active node.
+func (c *Core) setupRaftAutoSnapshots(ctx context.Context) error {
+	raftStorage, ok := c.underlyingPhysical.(*raft.RaftBackend)
+	if !ok {
+		return nil
+	}
+
+	logger := c.logger.Named("raft-snapshot-auto")
+	c.AddLogger(logger)
+
+	c.raftAutoSnapshots = newRaftAutoSnapshotManager(c.barrier, func(w io.Writer) error {
+		return raftStorage.Snapshot(w, c.seal.GetAccess())
+	}, logger)
+
+	return c.raftAutoSnapshots.start(ctx)
+}
+
+// stopRaftAutoSnapshots stops the automated snapshots
+func (c *Core) stopRaftAutoSnapshots() {
+	if c.raftAutoSnapshots == nil {
+		return
+	}
+	c.raftAutoSnapshots.stop()
+	c.raftAutoSnapshots = nil
+}
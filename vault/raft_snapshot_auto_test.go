@@ -0,0 +1,216 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaftAutoSnapshotConfig_Validate(t *testing.T) {
+	config := &raftAutoSnapshotConfig{
+		Name:       "test",
+		Interval:   time.Hour,
+		PathPrefix: "/tmp/snapshots",
+	}
+	require.NoError(t, config.validate())
+	require.Equal(t, raftAutoSnapshotDefaultRetention, config.Retain)
+	require.Equal(t, raftAutoSnapshotStorageLocal, config.StorageType)
+	require.Equal(t, raftAutoSnapshotDefaultPrefix, config.FilePrefix)
+
+	for _, invalid := range []*raftAutoSnapshotConfig{
+		{Interval: time.Millisecond, PathPrefix: "/tmp/snapshots"},
+		{Interval: time.Hour, Retain: -1, PathPrefix: "/tmp/snapshots"},
+		{Interval: time.Hour, StorageType: "aws-s3", PathPrefix: "/tmp/snapshots"},
+		{Interval: time.Hour},
+		{Interval: time.Hour, PathPrefix: "snapshots"},
+		{Interval: time.Hour, PathPrefix: "/tmp/snapshots", FilePrefix: "a/b"},
+	} {
+		require.Error(t, invalid.validate(), "config: %#v", invalid)
+	}
+}
+
+func TestRaftAutoSnapshotManager(t *testing.T) {
+	ctx := context.Background()
+	storage := &logical.InmemStorage{}
+	dir := t.TempDir()
+
+	var fail atomic.Bool
+	var taken atomic.Int64
+	manager := newRaftAutoSnapshotManager(storage, func(w io.Writer) error {
+		if fail.Load() {
+			return errors.New("snapshot failed")
+		}
+		taken.Add(1)
+		_, err := w.Write([]byte("snapshot"))
+		return err
+	}, hclog.NewNullLogger())
+	require.NoError(t, manager.start(ctx))
+	defer manager.stop()
+
+	config := &raftAutoSnapshotConfig{
+		Name:       "test",
+		Interval:   time.Second,
+		Retain:     2,
+		PathPrefix: dir,
+	}
+	require.NoError(t, config.validate())
+	require.NoError(t, manager.putConfig(ctx, config))
+
+	listSnapshots := func() []string {
+		t.Helper()
+		matches, err := filepath.Glob(filepath.Join(dir, raftAutoSnapshotDefaultPrefix+"-*"))
+		require.NoError(t, err)
+		return matches
+	}
+
+	// Snapshots are taken every interval and only the retained ones are kept
+	require.Eventually(t, func() bool {
+		status, err := manager.getStatus(ctx, "test")
+		require.NoError(t, err)
+		return status != nil && taken.Load() > 2 && len(status.RetainedSnapshots) == 2
+	}, 10*time.Second, 100*time.Millisecond)
+
+	snapshots := listSnapshots()
+	require.Len(t, snapshots, 2)
+	for _, snapshot := range snapshots {
+		require.True(t, strings.HasSuffix(snapshot, raftAutoSnapshotFileSuffix))
+		data, err := os.ReadFile(snapshot)
+		require.NoError(t, err)
+		require.Equal(t, "snapshot", string(data))
+	}
+
+	// Failures are reported in the status and leave no partial files behind
+	fail.Store(true)
+	require.Eventually(t, func() bool {
+		status, err := manager.getStatus(ctx, "test")
+		require.NoError(t, err)
+		return status.ConsecutiveErrors > 0
+	}, 5*time.Second, 100*time.Millisecond)
+
+	status, err := manager.getStatus(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, "failed to write snapshot: snapshot failed", status.LastSnapshotError)
+	require.False(t, status.LastFailureTime.IsZero())
+	require.False(t, status.LastSuccessTime.IsZero())
+	require.Len(t, listSnapshots(), 2)
+
+	// Deleting the configuration stops the schedule and removes the status
+	require.NoError(t, manager.deleteConfig(ctx, "test"))
+	status, err = manager.getStatus(ctx, "test")
+	require.NoError(t, err)
+	require.Nil(t, status)
+	config, err = manager.getConfig(ctx, "test")
+	require.NoError(t, err)
+	require.Nil(t, config)
+	require.Empty(t, manager.runners)
+}
+
+func TestPruneRaftAutoSnapshots_SharedPrefix(t *testing.T) {
+	dir := t.TempDir()
+	vault := &raftAutoSnapshotConfig{Name: "vault", Interval: time.Hour, Retain: 1, PathPrefix: dir, FilePrefix: "vault"}
+	require.NoError(t, vault.validate())
+	vaultProd := &raftAutoSnapshotConfig{Name: "vault-prod", Interval: time.Hour, Retain: 2, PathPrefix: dir, FilePrefix: "vault-prod"}
+	require.NoError(t, vaultProd.validate())
+
+	manager := newRaftAutoSnapshotManager(&logical.InmemStorage{}, func(w io.Writer) error {
+		_, err := w.Write([]byte("snapshot"))
+		return err
+	}, hclog.NewNullLogger())
+
+	now := time.Now()
+	var vaultProdSnapshots []string
+	for i := 0; i < 2; i++ {
+		path, err := manager.writeSnapshot(vaultProd, now.Add(time.Duration(i)*time.Second))
+		require.NoError(t, err)
+		vaultProdSnapshots = append(vaultProdSnapshots, path)
+	}
+	var vaultSnapshots []string
+	for i := 0; i < 3; i++ {
+		path, err := manager.writeSnapshot(vault, now.Add(time.Duration(i)*time.Second))
+		require.NoError(t, err)
+		vaultSnapshots = append(vaultSnapshots, path)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vault-backup.snap"), nil, 0o600))
+
+	// Pruning one configuration leaves the snapshots of the other alone
+	retained, err := pruneRaftAutoSnapshots(vault)
+	require.NoError(t, err)
+	require.Equal(t, vaultSnapshots[2:], retained)
+	retained, err = pruneRaftAutoSnapshots(vaultProd)
+	require.NoError(t, err)
+	require.Equal(t, vaultProdSnapshots, retained)
+	require.FileExists(t, filepath.Join(dir, "vault-backup.snap"))
+}
+
+func TestRaftAutoSnapshotManager_ConfigDuringSnapshot(t *testing.T) {
+	ctx := context.Background()
+	storage := &logical.InmemStorage{}
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	manager := newRaftAutoSnapshotManager(storage, func(w io.Writer) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		_, err := w.Write([]byte("snapshot"))
+		return err
+	}, hclog.NewNullLogger())
+	defer manager.stop()
+
+	slow := &raftAutoSnapshotConfig{
+		Name:       "slow",
+		Interval:   time.Hour,
+		PathPrefix: t.TempDir(),
+	}
+	require.NoError(t, slow.validate())
+	require.NoError(t, manager.putConfig(ctx, slow))
+	<-started
+
+	// Other configurations can be managed while a snapshot is running
+	other := &raftAutoSnapshotConfig{
+		Name:       "other",
+		Interval:   time.Hour,
+		PathPrefix: t.TempDir(),
+	}
+	require.NoError(t, other.validate())
+	putDone := make(chan error, 1)
+	go func() {
+		putDone <- manager.putConfig(ctx, other)
+	}()
+	select {
+	case err := <-putDone:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("updating a configuration was blocked by a running snapshot")
+	}
+
+	// Deleting the configuration of the running snapshot waits for it to
+	// finish
+	deleteDone := make(chan error, 1)
+	go func() {
+		deleteDone <- manager.deleteConfig(ctx, "slow")
+	}()
+	select {
+	case <-deleteDone:
+		t.Fatal("expected delete to wait for the running snapshot")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+	require.NoError(t, <-deleteDone)
+
+	status, err := manager.getStatus(ctx, "slow")
+	require.NoError(t, err)
+	require.Nil(t, status)
+}
@@ -246,6 +246,10 @@ func (c *Core) fetchACLTokenEntryAndEntity(ctx context.Context, req *logical.Req
 		c.logger.Error("failed to construct ACL", "error", err)
 		return nil, nil, nil, nil, ErrInternalError
 	}
+	if err := c.tokenStore.applyTokenPathRestrictions(ctx, acl, tokenNS, te); err != nil {
+		c.logger.Error("failed to apply token path restrictions", "error", err)
+		return nil, nil, nil, nil, ErrInternalError
+	}
 
 	return acl, te, entity, identityPolicies, nil
 }
@@ -855,6 +859,12 @@ func (c *Core) handleRequest(ctx context.Context, req *logical.Request) (retResp
 		return nil, nil, ctErr
 	}
 
+	// Wrapping tokens bound to a recipient can only be unwrapped through
+	// sys/wrapping/unwrap, which checks the recipient
+	if te != nil && req.Path == "cubbyhole/response" && IsWrappingToken(te) && isBoundWrappingToken(te) {
+		return nil, nil, logical.ErrPermissionDenied
+	}
+
 	// Updating in-flight request data with client/entity ID
 	inFlightReqID, ok := ctx.Value(logical.CtxKeyInFlightRequestID{}).(string)
 	if ok && req.ClientID != "" {
@@ -1012,6 +1022,7 @@ func (c *Core) handleRequest(ctx context.Context, req *logical.Request) (retResp
 		var wrapTTL time.Duration
 		var wrapFormat, creationPath string
 		var sealWrap bool
+		var boundCIDRs []string
 
 		// Ensure no wrap info information is set other than, possibly, the TTL
 		if resp.WrapInfo != nil {
@@ -1021,6 +1032,7 @@ func (c *Core) handleRequest(ctx context.Context, req *logical.Request) (retResp
 			wrapFormat = resp.WrapInfo.Format
 			creationPath = resp.WrapInfo.CreationPath
 			sealWrap = resp.WrapInfo.SealWrap
+			boundCIDRs = resp.WrapInfo.BoundCIDRs
 			resp.WrapInfo = nil
 		}
 
@@ -1046,6 +1058,7 @@ func (c *Core) handleRequest(ctx context.Context, req *logical.Request) (retResp
 				Format:       wrapFormat,
 				CreationPath: creationPath,
 				SealWrap:     sealWrap,
+				BoundCIDRs:   boundCIDRs,
 			}
 		}
 	}
@@ -1325,8 +1338,17 @@ func (c *Core) handleLoginRequest(ctx context.Context, req *logical.Request) (re
 		return nil, nil, ErrInternalError
 	}
 
+	// Apply progressive login delays before the request reaches the auth
+	// method, so that delayed attempts are never evaluated.
+	loginDelay, delayResp, delayErr := c.checkLoginDelay(ctx, req, entry)
+	if delayErr != nil {
+		retErr = multierror.Append(retErr, delayErr)
+		return delayResp, nil, retErr
+	}
+
 	// Route the request
 	resp, routeErr := c.doRouting(ctx, req)
+	c.recordLoginAttempt(ctx, loginDelay, resp, routeErr)
 	if resp != nil {
 		// If wrapping is used, use the shortest between the request and response
 		var wrapTTL time.Duration
@@ -1534,7 +1556,7 @@ func (c *Core) handleLoginRequest(ctx context.Context, req *logical.Request) (re
 			// run single-phase login MFA check, else run two-phase login MFA check
 			if len(matchedMfaEnforcementList) > 0 && len(req.MFACreds) > 0 {
 				for _, eConfig := range matchedMfaEnforcementList {
-					err = c.validateLoginMFA(ctx, eConfig, entity, req.Connection.RemoteAddr, req.MFACreds)
+					err = c.validateLoginMFA(ctx, eConfig, entity, req.Connection.RemoteAddr, req.MFACreds, nil)
 					if err != nil {
 						return nil, nil, logical.ErrPermissionDenied
 					}
@@ -1817,12 +1839,16 @@ func (c *Core) RegisterAuth(ctx context.Context, tokenTTL time.Duration, path st
 
 // GetUserFailedLoginInfo gets the failed login information for a user based on alias name and mountAccessor
 func (c *Core) GetUserFailedLoginInfo(ctx context.Context, userKey FailedLoginUser) *FailedLoginInfo {
+	c.userFailedLoginInfoLock.RLock()
+	defer c.userFailedLoginInfoLock.RUnlock()
 	return c.userFailedLoginInfo[userKey]
 }
 
 // UpdateUserFailedLoginInfo updates the failed login information for a user based on alias name and mountAccessor
 func (c *Core) UpdateUserFailedLoginInfo(ctx context.Context, userKey FailedLoginUser, failedLoginInfo FailedLoginInfo) error {
+	c.userFailedLoginInfoLock.Lock()
 	c.userFailedLoginInfo[userKey] = &failedLoginInfo
+	c.userFailedLoginInfoLock.Unlock()
 
 	// check if the update worked
 	failedLoginResp := c.GetUserFailedLoginInfo(ctx, userKey)
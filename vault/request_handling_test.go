@@ -283,6 +283,7 @@ func TestRequestHandling_Login_PeriodicToken(t *testing.T) {
 		"renewable":        true,
 		"ttl":              int64(5),
 		"type":             "service",
+		"use_count":        uint64(0),
 	}
 
 	if diff := deep.Equal(resp.Data, exp); diff != nil {
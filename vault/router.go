@@ -710,9 +710,13 @@ func (r *Router) routeCommon(ctx context.Context, req *logical.Request, existenc
 	var wrapInfo *logical.RequestWrapInfo
 	if req.WrapInfo != nil {
 		wrapInfo = &logical.RequestWrapInfo{
-			TTL:      req.WrapInfo.TTL,
-			Format:   req.WrapInfo.Format,
-			SealWrap: req.WrapInfo.SealWrap,
+			TTL:              req.WrapInfo.TTL,
+			Format:           req.WrapInfo.Format,
+			SealWrap:         req.WrapInfo.SealWrap,
+			Uses:             req.WrapInfo.Uses,
+			BoundEntityID:    req.WrapInfo.BoundEntityID,
+			BoundAppRoleRole: req.WrapInfo.BoundAppRoleRole,
+			BoundCIDRs:       req.WrapInfo.BoundCIDRs,
 		}
 	}
 
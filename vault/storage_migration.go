@@ -0,0 +1,611 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/helper/locksutil"
+	"github.com/hashicorp/vault/sdk/physical"
+)
+
+const (
+	// storageMigrationLockPath is the key used by "vault operator migrate" to
+	// prevent servers from starting on storage that is being migrated. It is
+	// written to the source storage on cutover.
+	storageMigrationLockPath = "core/migration"
+
+	// storageMigrationMaxReportedKeys caps the number of mismatched keys kept
+	// in the status
+	storageMigrationMaxReportedKeys = 100
+
+	storageMigrationStateIdle      = "idle"
+	storageMigrationStateCopying   = "copying"
+	storageMigrationStateCopied    = "copied"
+	storageMigrationStateVerifying = "verifying"
+	storageMigrationStateVerified  = "verified"
+	storageMigrationStateFailed    = "failed"
+	storageMigrationStateCutover   = "cutover"
+)
+
+// ErrStorageMigrationCutover is returned for writes to the storage after the
+// cutover of an online storage migration
+var ErrStorageMigrationCutover = errors.New("storage migration cutover completed, storage is read-only until the server is restarted with the destination storage")
+
+var (
+	_ physical.Backend       = (*storageMigrationBackend)(nil)
+	_ physical.Transactional = (*transactionalStorageMigrationBackend)(nil)
+)
+
+// storageMigrationBackend is the physical backend used during an online
+// storage migration. Reads are served by the source storage and writes are
+// applied to the source storage, then to the destination storage. Writes and
+// the copy of a key by the migration hold the same per-key lock, so that the
+// destination never ends up with a stale value.
+type storageMigrationBackend struct {
+	source      physical.Backend
+	destination physical.Backend
+	logger      hclog.Logger
+	locks       []*locksutil.LockEntry
+
+	// writeLock is held for reading by the writes and for writing by the
+	// cutover, so that no write is in flight when the cutover happens
+	writeLock sync.RWMutex
+	cutover   bool
+
+	// destinationErrors counts the writes that failed on the destination
+	destinationErrors uint64
+}
+
+// transactionalStorageMigrationBackend is a storage migration backend with a
+// transactional source storage
+type transactionalStorageMigrationBackend struct {
+	*storageMigrationBackend
+}
+
+func newStorageMigrationBackend(source, destination physical.Backend, logger hclog.Logger) *storageMigrationBackend {
+	return &storageMigrationBackend{
+		source:      source,
+		destination: destination,
+		logger:      logger,
+		locks:       locksutil.CreateLocks(),
+	}
+}
+
+// backend returns the physical backend to use as the storage of the core,
+// which is transactional if the source storage is
+func (b *storageMigrationBackend) backend() physical.Backend {
+	if _, ok := b.source.(physical.Transactional); ok {
+		return &transactionalStorageMigrationBackend{b}
+	}
+	return b
+}
+
+func (b *storageMigrationBackend) Get(ctx context.Context, key string) (*physical.Entry, error) {
+	return b.source.Get(ctx, key)
+}
+
+func (b *storageMigrationBackend) List(ctx context.Context, prefix string) ([]string, error) {
+	return b.source.List(ctx, prefix)
+}
+
+func (b *storageMigrationBackend) Put(ctx context.Context, entry *physical.Entry) error {
+	b.writeLock.RLock()
+	defer b.writeLock.RUnlock()
+	if b.cutover {
+		return ErrStorageMigrationCutover
+	}
+
+	lock := locksutil.LockForKey(b.locks, entry.Key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := b.source.Put(ctx, entry); err != nil {
+		return err
+	}
+	if err := b.destination.Put(ctx, entry); err != nil {
+		b.destinationError("put", entry.Key, err)
+	}
+	return nil
+}
+
+func (b *storageMigrationBackend) Delete(ctx context.Context, key string) error {
+	b.writeLock.RLock()
+	defer b.writeLock.RUnlock()
+	if b.cutover {
+		return ErrStorageMigrationCutover
+	}
+
+	lock := locksutil.LockForKey(b.locks, key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := b.source.Delete(ctx, key); err != nil {
+		return err
+	}
+	if err := b.destination.Delete(ctx, key); err != nil {
+		b.destinationError("delete", key, err)
+	}
+	return nil
+}
+
+func (b *transactionalStorageMigrationBackend) Transaction(ctx context.Context, txns []*physical.TxnEntry) error {
+	b.writeLock.RLock()
+	defer b.writeLock.RUnlock()
+	if b.cutover {
+		return ErrStorageMigrationCutover
+	}
+
+	keys := make([]string, 0, len(txns))
+	for _, txn := range txns {
+		keys = append(keys, txn.Entry.Key)
+	}
+	// The locks are returned in order, which prevents deadlocks
+	for _, lock := range locksutil.LocksForKeys(b.locks, keys) {
+		lock.Lock()
+		defer lock.Unlock()
+	}
+
+	if err := b.source.(physical.Transactional).Transaction(ctx, txns); err != nil {
+		return err
+	}
+
+	// Only the writes of the transaction are applied to the destination
+	writes := make([]*physical.TxnEntry, 0, len(txns))
+	for _, txn := range txns {
+		if txn.Operation == physical.PutOperation || txn.Operation == physical.DeleteOperation {
+			writes = append(writes, txn)
+		}
+	}
+
+	if destTxn, ok := b.destination.(physical.Transactional); ok {
+		if err := destTxn.Transaction(ctx, writes); err != nil {
+			b.destinationError("transaction", "", err)
+		}
+		return nil
+	}
+
+	for _, txn := range writes {
+		var err error
+		switch txn.Operation {
+		case physical.PutOperation:
+			err = b.destination.Put(ctx, txn.Entry)
+		case physical.DeleteOperation:
+			err = b.destination.Delete(ctx, txn.Entry.Key)
+		}
+		if err != nil {
+			b.destinationError(string(txn.Operation), txn.Entry.Key, err)
+		}
+	}
+	return nil
+}
+
+// destinationError records a failed write to the destination storage. The
+// write to the source storage has succeeded at this point, so the request is
+// not failed; instead the migration can't be cut over until the destination
+// is copied and verified again.
+func (b *storageMigrationBackend) destinationError(op, key string, err error) {
+	b.logger.Error("failed to write to the storage migration destination", "operation", op, "key", key, "error", err)
+	atomic.AddUint64(&b.destinationErrors, 1)
+}
+
+func (b *storageMigrationBackend) destinationErrorCount() uint64 {
+	return atomic.LoadUint64(&b.destinationErrors)
+}
+
+// copyKey copies a key of the source storage to the destination storage and
+// reports whether the key still existed
+func (b *storageMigrationBackend) copyKey(ctx context.Context, key string) (bool, error) {
+	lock := locksutil.LockForKey(b.locks, key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	entry, err := b.source.Get(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("error reading entry: %w", err)
+	}
+	if entry == nil {
+		return false, nil
+	}
+
+	if err := b.destination.Put(ctx, entry); err != nil {
+		return false, fmt.Errorf("error writing entry: %w", err)
+	}
+	return true, nil
+}
+
+// removeStaleKey removes a key of the destination storage if it doesn't exist
+// in the source storage and reports whether it was removed
+func (b *storageMigrationBackend) removeStaleKey(ctx context.Context, key string) (bool, error) {
+	lock := locksutil.LockForKey(b.locks, key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	entry, err := b.source.Get(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("error reading entry: %w", err)
+	}
+	if entry != nil {
+		return false, nil
+	}
+
+	if err := b.destination.Delete(ctx, key); err != nil {
+		return false, fmt.Errorf("error deleting entry: %w", err)
+	}
+	return true, nil
+}
+
+// compareKey compares a key between the source and destination storage and
+// returns the reason of the mismatch, if any
+func (b *storageMigrationBackend) compareKey(ctx context.Context, key string) (string, error) {
+	lock := locksutil.LockForKey(b.locks, key)
+	lock.RLock()
+	defer lock.RUnlock()
+
+	sourceEntry, err := b.source.Get(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("error reading source entry: %w", err)
+	}
+	destEntry, err := b.destination.Get(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("error reading destination entry: %w", err)
+	}
+
+	switch {
+	case sourceEntry == nil && destEntry == nil:
+		return "", nil
+	case sourceEntry == nil:
+		return "extra", nil
+	case destEntry == nil:
+		return "missing", nil
+	case !bytes.Equal(sourceEntry.Value, destEntry.Value):
+		return "different", nil
+	}
+	return "", nil
+}
+
+// storageMigrationSkipKey returns whether a key is local to the storage and
+// must not be migrated
+func storageMigrationSkipKey(key string) bool {
+	return key == CoreLockPath || key == storageMigrationLockPath
+}
+
+// storageMigrationScan invokes cb with every key of the storage that is
+// migrated, in lexicographic order
+func storageMigrationScan(ctx context.Context, storage physical.Backend, prefix string, cb func(key string) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	keys, err := storage.List(ctx, prefix)
+	if err != nil {
+		return fmt.Errorf("failed to scan for children: %w", err)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		key = prefix + key
+		if strings.HasSuffix(key, "/") {
+			if err := storageMigrationScan(ctx, storage, key, cb); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if storageMigrationSkipKey(key) {
+			continue
+		}
+		if err := cb(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// storageMigrationMismatch is a key that differs between the source and the
+// destination storage
+type storageMigrationMismatch struct {
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+}
+
+// storageMigrationStatus reports the progress of the last operation of an
+// online storage migration
+type storageMigrationStatus struct {
+	State                  string                      `json:"state"`
+	StartTime              time.Time                   `json:"start_time"`
+	EndTime                time.Time                   `json:"end_time"`
+	KeysCopied             int                         `json:"keys_copied"`
+	KeysRemoved            int                         `json:"keys_removed"`
+	KeysVerified           int                         `json:"keys_verified"`
+	MismatchedKeysCount    int                         `json:"mismatched_keys_count"`
+	MismatchedKeys         []*storageMigrationMismatch `json:"mismatched_keys"`
+	DestinationWriteErrors uint64                      `json:"destination_write_errors"`
+	LastError              string                      `json:"last_error"`
+}
+
+// storageMigrationManager runs the copy, verification and cutover of an
+// online storage migration. The operations only run on the active node, and
+// their state is reset when the node steps down, since the writes made by
+// another active node are not applied to the destination.
+type storageMigrationManager struct {
+	backend *storageMigrationBackend
+	logger  hclog.Logger
+
+	l      sync.Mutex
+	status *storageMigrationStatus
+	cancel context.CancelFunc
+	doneCh chan struct{}
+
+	// verifiedErrors is the number of failed destination writes when the
+	// last successful verification started
+	verifiedErrors uint64
+}
+
+func newStorageMigrationManager(backend *storageMigrationBackend, logger hclog.Logger) *storageMigrationManager {
+	return &storageMigrationManager{
+		backend: backend,
+		logger:  logger,
+		status: &storageMigrationStatus{
+			State: storageMigrationStateIdle,
+		},
+	}
+}
+
+// getStatus returns a copy of the status of the migration
+func (m *storageMigrationManager) getStatus() *storageMigrationStatus {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	status := *m.status
+	status.MismatchedKeys = append([]*storageMigrationMismatch(nil), m.status.MismatchedKeys...)
+	status.DestinationWriteErrors = m.backend.destinationErrorCount()
+	return &status
+}
+
+func (m *storageMigrationManager) update(fn func(status *storageMigrationStatus)) {
+	m.l.Lock()
+	fn(m.status)
+	m.l.Unlock()
+}
+
+// startCopy starts copying the keys of the source storage to the destination
+// storage in the background
+func (m *storageMigrationManager) startCopy() error {
+	return m.start(storageMigrationStateCopying, m.copy)
+}
+
+// startVerify starts comparing the keys of the source and destination storage
+// in the background
+func (m *storageMigrationManager) startVerify() error {
+	return m.start(storageMigrationStateVerifying, m.verify)
+}
+
+func (m *storageMigrationManager) start(state string, run func(context.Context) (string, error)) error {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	switch m.status.State {
+	case storageMigrationStateCopying, storageMigrationStateVerifying:
+		return fmt.Errorf("storage migration is already %s", m.status.State)
+	case storageMigrationStateCutover:
+		return errors.New("storage migration cutover already completed")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	doneCh := make(chan struct{})
+	m.cancel = cancel
+	m.doneCh = doneCh
+	m.status = &storageMigrationStatus{
+		State:     state,
+		StartTime: time.Now(),
+	}
+
+	m.logger.Info("storage migration operation started", "state", state)
+
+	go func() {
+		defer close(doneCh)
+
+		next, err := run(ctx)
+
+		m.l.Lock()
+		defer m.l.Unlock()
+
+		m.status.EndTime = time.Now()
+		if err != nil {
+			m.logger.Error("storage migration operation failed", "state", state, "error", err)
+			m.status.State = storageMigrationStateFailed
+			m.status.LastError = err.Error()
+			return
+		}
+		m.logger.Info("storage migration operation completed", "state", next)
+		m.status.State = next
+	}()
+
+	return nil
+}
+
+// copy copies all the keys of the source storage to the destination storage,
+// then removes the keys of the destination storage that don't exist in the
+// source storage, such as keys deleted while another node was active
+func (m *storageMigrationManager) copy(ctx context.Context) (string, error) {
+	b := m.backend
+
+	err := storageMigrationScan(ctx, b.source, "", func(key string) error {
+		copied, err := b.copyKey(ctx, key)
+		if err != nil {
+			return fmt.Errorf("error copying %q: %w", key, err)
+		}
+		if copied {
+			m.update(func(status *storageMigrationStatus) { status.KeysCopied++ })
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	err = storageMigrationScan(ctx, b.destination, "", func(key string) error {
+		removed, err := b.removeStaleKey(ctx, key)
+		if err != nil {
+			return fmt.Errorf("error removing %q: %w", key, err)
+		}
+		if removed {
+			m.update(func(status *storageMigrationStatus) { status.KeysRemoved++ })
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return storageMigrationStateCopied, nil
+}
+
+// verify compares all the keys of the source and destination storage
+func (m *storageMigrationManager) verify(ctx context.Context) (string, error) {
+	b := m.backend
+	destinationErrors := b.destinationErrorCount()
+
+	mismatch := func(key, reason string) {
+		m.update(func(status *storageMigrationStatus) {
+			status.MismatchedKeysCount++
+			if len(status.MismatchedKeys) < storageMigrationMaxReportedKeys {
+				status.MismatchedKeys = append(status.MismatchedKeys, &storageMigrationMismatch{
+					Key:    key,
+					Reason: reason,
+				})
+			}
+		})
+	}
+
+	err := storageMigrationScan(ctx, b.source, "", func(key string) error {
+		reason, err := b.compareKey(ctx, key)
+		if err != nil {
+			return fmt.Errorf("error comparing %q: %w", key, err)
+		}
+		m.update(func(status *storageMigrationStatus) { status.KeysVerified++ })
+		if reason != "" {
+			mismatch(key, reason)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Keys that exist in both storage have been compared already, so only
+	// the keys missing from the source storage are reported
+	err = storageMigrationScan(ctx, b.destination, "", func(key string) error {
+		reason, err := b.compareKey(ctx, key)
+		if err != nil {
+			return fmt.Errorf("error comparing %q: %w", key, err)
+		}
+		if reason == "extra" {
+			mismatch(key, reason)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	status := m.getStatus()
+	if status.MismatchedKeysCount > 0 {
+		return "", fmt.Errorf("%d keys differ between the source and destination storage", status.MismatchedKeysCount)
+	}
+
+	m.l.Lock()
+	m.verifiedErrors = destinationErrors
+	m.l.Unlock()
+
+	return storageMigrationStateVerified, nil
+}
+
+// cutover stops the writes to the storage and writes the storage migration
+// lock to the source storage, which prevents servers from starting on it. The
+// migration must have been verified, with no failed destination writes since.
+func (m *storageMigrationManager) cutover(ctx context.Context) error {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	switch m.status.State {
+	case storageMigrationStateVerified:
+	case storageMigrationStateCutover:
+		return errors.New("storage migration cutover already completed")
+	default:
+		return fmt.Errorf("storage migration must be verified before the cutover, current state is %q", m.status.State)
+	}
+
+	b := m.backend
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	if b.destinationErrorCount() != m.verifiedErrors {
+		return errors.New("writes to the destination storage failed since the verification, the migration must be copied and verified again")
+	}
+
+	lock := struct {
+		Start time.Time `json:"start"`
+	}{
+		Start: time.Now(),
+	}
+	enc, err := jsonutil.EncodeJSON(lock)
+	if err != nil {
+		return err
+	}
+	if err := b.source.Put(ctx, &physical.Entry{Key: storageMigrationLockPath, Value: enc}); err != nil {
+		return fmt.Errorf("failed to write storage migration lock: %w", err)
+	}
+
+	b.cutover = true
+	m.status.State = storageMigrationStateCutover
+	m.status.EndTime = lock.Start
+
+	m.logger.Info("storage migration cutover completed, restart the server with the destination storage")
+	return nil
+}
+
+// stop cancels a running operation and resets the state of the migration,
+// unless it has been cut over
+func (m *storageMigrationManager) stop() {
+	m.l.Lock()
+	cancel, doneCh := m.cancel, m.doneCh
+	m.cancel, m.doneCh = nil, nil
+	m.l.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-doneCh
+	}
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	if m.status.State != storageMigrationStateCutover {
+		m.status = &storageMigrationStatus{
+			State: storageMigrationStateIdle,
+		}
+	}
+}
+
+// stopStorageMigration stops the operations of an online storage migration.
+// It's called when the node seals or steps down.
+func (c *Core) stopStorageMigration() {
+	if c.storageMigration != nil {
+		c.storageMigration.stop()
+	}
+}
@@ -0,0 +1,221 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/sdk/physical"
+	"github.com/hashicorp/vault/sdk/physical/inmem"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForStorageMigration waits for the running operation of the migration to
+// complete and returns the status
+func waitForStorageMigration(t *testing.T, m *storageMigrationManager) *storageMigrationStatus {
+	t.Helper()
+
+	var status *storageMigrationStatus
+	require.Eventually(t, func() bool {
+		status = m.getStatus()
+		return status.State != storageMigrationStateCopying && status.State != storageMigrationStateVerifying
+	}, 10*time.Second, 10*time.Millisecond)
+	return status
+}
+
+func TestStorageMigration_CopyVerifyCutover(t *testing.T) {
+	ctx := context.Background()
+	logger := hclog.NewNullLogger()
+
+	source, err := inmem.NewTransactionalInmem(nil, logger)
+	require.NoError(t, err)
+	dest, err := inmem.NewInmem(nil, logger)
+	require.NoError(t, err)
+
+	for i := 0; i < 20; i++ {
+		require.NoError(t, source.Put(ctx, &physical.Entry{
+			Key:   fmt.Sprintf("logical/%d/key-%d", i%4, i),
+			Value: []byte(fmt.Sprintf("value-%d", i)),
+		}))
+	}
+	// Keys local to the source storage are not migrated
+	require.NoError(t, source.Put(ctx, &physical.Entry{Key: CoreLockPath, Value: []byte("lock")}))
+
+	migrationBackend := newStorageMigrationBackend(source, dest, logger)
+	manager := newStorageMigrationManager(migrationBackend, logger)
+	backend := migrationBackend.backend()
+	_, ok := backend.(physical.Transactional)
+	require.True(t, ok, "expected transactional backend for a transactional source")
+
+	// The cutover requires a verification
+	require.Error(t, manager.cutover(ctx))
+
+	// Writes are applied to both storage
+	require.NoError(t, backend.Put(ctx, &physical.Entry{Key: "core/written", Value: []byte("foo")}))
+	require.NoError(t, backend.(physical.Transactional).Transaction(ctx, []*physical.TxnEntry{
+		{Operation: physical.PutOperation, Entry: &physical.Entry{Key: "core/txn", Value: []byte("bar")}},
+		{Operation: physical.DeleteOperation, Entry: &physical.Entry{Key: "logical/0/key-0"}},
+	}))
+	for _, storage := range []physical.Backend{source, dest} {
+		entry, err := storage.Get(ctx, "core/txn")
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+	}
+
+	// A key only in the destination is reported and removed by the copy
+	require.NoError(t, dest.Put(ctx, &physical.Entry{Key: "logical/stale", Value: []byte("stale")}))
+
+	require.NoError(t, manager.startVerify())
+	status := waitForStorageMigration(t, manager)
+	require.Equal(t, storageMigrationStateFailed, status.State)
+	require.Equal(t, 20, status.MismatchedKeysCount)
+	require.Contains(t, status.MismatchedKeys, &storageMigrationMismatch{Key: "logical/stale", Reason: "extra"})
+	require.Contains(t, status.MismatchedKeys, &storageMigrationMismatch{Key: "logical/1/key-1", Reason: "missing"})
+
+	require.NoError(t, manager.startCopy())
+	status = waitForStorageMigration(t, manager)
+	require.Equal(t, storageMigrationStateCopied, status.State, status.LastError)
+	require.Equal(t, 21, status.KeysCopied)
+	require.Equal(t, 1, status.KeysRemoved)
+
+	entry, err := dest.Get(ctx, CoreLockPath)
+	require.NoError(t, err)
+	require.Nil(t, entry)
+
+	require.NoError(t, manager.startVerify())
+	status = waitForStorageMigration(t, manager)
+	require.Equal(t, storageMigrationStateVerified, status.State, status.LastError)
+	require.Equal(t, 21, status.KeysVerified)
+	require.Zero(t, status.MismatchedKeysCount)
+
+	require.NoError(t, manager.cutover(ctx))
+	require.Equal(t, storageMigrationStateCutover, manager.getStatus().State)
+
+	// Writes are rejected after the cutover and the source storage is locked
+	require.ErrorIs(t, backend.Put(ctx, &physical.Entry{Key: "core/written", Value: []byte("baz")}), ErrStorageMigrationCutover)
+	require.ErrorIs(t, backend.Delete(ctx, "core/written"), ErrStorageMigrationCutover)
+	entry, err = source.Get(ctx, storageMigrationLockPath)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	entry, err = dest.Get(ctx, storageMigrationLockPath)
+	require.NoError(t, err)
+	require.Nil(t, entry)
+
+	require.Error(t, manager.startCopy())
+
+	// Stopping the operations keeps the cutover
+	manager.stop()
+	require.Equal(t, storageMigrationStateCutover, manager.getStatus().State)
+}
+
+func TestStorageMigration_DestinationErrors(t *testing.T) {
+	ctx := context.Background()
+	logger := hclog.NewNullLogger()
+
+	source, err := inmem.NewInmem(nil, logger)
+	require.NoError(t, err)
+	destInmem, err := inmem.NewInmem(nil, logger)
+	require.NoError(t, err)
+	dest := physical.NewErrorInjector(destInmem, 0, logger)
+
+	migrationBackend := newStorageMigrationBackend(source, dest, logger)
+	manager := newStorageMigrationManager(migrationBackend, logger)
+	backend := migrationBackend.backend()
+
+	require.NoError(t, backend.Put(ctx, &physical.Entry{Key: "foo", Value: []byte("bar")}))
+	require.NoError(t, manager.startVerify())
+	require.Equal(t, storageMigrationStateVerified, waitForStorageMigration(t, manager).State)
+
+	// Failed writes to the destination don't fail the request, but prevent
+	// the cutover until the migration is verified again
+	dest.SetErrorPercentage(100)
+	require.NoError(t, backend.Put(ctx, &physical.Entry{Key: "foo", Value: []byte("baz")}))
+	require.EqualValues(t, 1, manager.getStatus().DestinationWriteErrors)
+	require.Error(t, manager.cutover(ctx))
+
+	entry, err := source.Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, []byte("baz"), entry.Value)
+
+	dest.SetErrorPercentage(0)
+	require.NoError(t, manager.startVerify())
+	status := waitForStorageMigration(t, manager)
+	require.Equal(t, storageMigrationStateFailed, status.State)
+	require.Equal(t, []*storageMigrationMismatch{{Key: "foo", Reason: "different"}}, status.MismatchedKeys)
+
+	require.NoError(t, manager.startCopy())
+	require.Equal(t, storageMigrationStateCopied, waitForStorageMigration(t, manager).State)
+	require.NoError(t, manager.startVerify())
+	require.Equal(t, storageMigrationStateVerified, waitForStorageMigration(t, manager).State)
+	require.NoError(t, manager.cutover(ctx))
+}
+
+func TestSystemBackend_StorageMigration(t *testing.T) {
+	dest, err := inmem.NewInmem(nil, hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	c, _, root := TestCoreUnsealedWithConfig(t, &CoreConfig{
+		StorageMigrationDestination: dest,
+	})
+	ctx := namespace.RootContext(nil)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "secret/foo")
+	req.Data["bar"] = "baz"
+	req.ClientToken = root
+	resp, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	for _, path := range []string{"sys/storage/migration/copy", "sys/storage/migration/verify"} {
+		req = logical.TestRequest(t, logical.UpdateOperation, path)
+		req.ClientToken = root
+		resp, err = c.HandleRequest(ctx, req)
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		waitForStorageMigration(t, c.storageMigration)
+	}
+
+	req = logical.TestRequest(t, logical.ReadOperation, "sys/storage/migration")
+	req.ClientToken = root
+	resp, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, storageMigrationStateVerified, resp.Data["state"], resp.Data["last_error"])
+	require.NotZero(t, resp.Data["keys_verified"])
+
+	// The barrier is copied as is, so the destination can be unsealed with
+	// the same keys
+	entry, err := dest.Get(context.Background(), keyringPath)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/storage/migration/cutover")
+	req.ClientToken = root
+	resp, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, storageMigrationStateCutover, resp.Data["state"])
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "secret/foo")
+	req.Data["bar"] = "qux"
+	req.ClientToken = root
+	_, err = c.HandleRequest(ctx, req)
+	require.Error(t, err)
+
+	req = logical.TestRequest(t, logical.ReadOperation, "sys/storage/migration")
+	req.ClientToken = root
+	resp, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, storageMigrationStateCutover, resp.Data["state"])
+}
+
+func TestSystemBackend_StorageMigration_NotConfigured(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+
+	req := logical.TestRequest(t, logical.ReadOperation, "sys/storage/migration")
+	req.ClientToken = root
+	_, err := c.HandleRequest(namespace.RootContext(nil), req)
+	require.Error(t, err)
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"regexp"
 	"strings"
 	"sync"
@@ -25,6 +26,7 @@ import (
 	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/hashicorp/go-sockaddr"
 	"github.com/hashicorp/go-version"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/vault/helper/identity"
 	"github.com/hashicorp/vault/helper/metricsutil"
 	"github.com/hashicorp/vault/helper/namespace"
@@ -92,6 +94,10 @@ const (
 	// IgnoreForBilling used for HCP Link batch tokens and inserted into the InternalMeta
 	// Tokens created for the purpose of HCP Link should bypass counting for billing purposes
 	IgnoreForBilling = "ignore_for_billing"
+
+	// tokenPathRestrictionsCacheSize is the number of ACLs of token path
+	// restrictions that are kept cached
+	tokenPathRestrictionsCacheSize = 1024
 )
 
 var (
@@ -158,6 +164,26 @@ func (ts *TokenStore) paths() []*framework.Path {
 			HelpDescription: tokenListAccessorsHelp,
 		},
 
+		{
+			Pattern: "accessors/stale$",
+
+			Fields: map[string]*framework.FieldSchema{
+				"unused_for": {
+					Type:        framework.TypeDurationSecond,
+					Default:     int(defaultStaleTokenUnusedFor.Seconds()),
+					Description: "List the tokens that haven't been used for this duration. Defaults to 720h.",
+					Query:       true,
+				},
+			},
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation: ts.handleStaleAccessors,
+			},
+
+			HelpSynopsis:    "List the accessors of tokens that haven't been used recently",
+			HelpDescription: strings.TrimSpace(tokenStaleAccessorsHelp),
+		},
+
 		{
 			Pattern: "create-orphan$",
 
@@ -218,6 +244,10 @@ func (ts *TokenStore) paths() []*framework.Path {
 					Type:        framework.TypeStringSlice,
 					Description: "List of policies for the token",
 				},
+				"path_restrictions": {
+					Type:        framework.TypeMap,
+					Description: "Map of request paths to the capabilities the token is limited to on them, in addition to its policies",
+				},
 				"format": {
 					Type:        framework.TypeString,
 					Query:       true,
@@ -293,6 +323,10 @@ func (ts *TokenStore) paths() []*framework.Path {
 					Type:        framework.TypeStringSlice,
 					Description: "List of policies for the token",
 				},
+				"path_restrictions": {
+					Type:        framework.TypeMap,
+					Description: "Map of request paths to the capabilities the token is limited to on them, in addition to its policies",
+				},
 				"format": {
 					Type:        framework.TypeString,
 					Query:       true,
@@ -364,6 +398,10 @@ func (ts *TokenStore) paths() []*framework.Path {
 					Type:        framework.TypeStringSlice,
 					Description: "List of policies for the token",
 				},
+				"path_restrictions": {
+					Type:        framework.TypeMap,
+					Description: "Map of request paths to the capabilities the token is limited to on them, in addition to its policies",
+				},
 				"format": {
 					Type:        framework.TypeString,
 					Query:       true,
@@ -745,6 +783,17 @@ type TokenStore struct {
 	// number of times all nodes in the cluster have stepped down. Currently the only sync
 	// point is a DR cluster promoting to the primary.
 	sscTokensGenerationCounter SSCTokenGenerationCounter
+
+	// pendingUsage holds the usage of tokens by accessor recorded since it was
+	// last written to storage
+	usageLock    sync.Mutex
+	pendingUsage map[string]*tokenUsage
+	usageCancel  context.CancelFunc
+	usageDoneCh  chan struct{}
+
+	// pathRestrictionsCache holds the ACLs of the path restrictions of tokens
+	// by token ID
+	pathRestrictionsCache *lru.TwoQueueCache
 }
 
 // NewTokenStore is used to construct a token store that is
@@ -753,6 +802,11 @@ func NewTokenStore(ctx context.Context, logger log.Logger, core *Core, config *l
 	// Create a sub-view
 	view := core.systemBarrierView.SubView(tokenSubPath)
 
+	pathRestrictionsCache, err := lru.New2Q(tokenPathRestrictionsCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize the store
 	t := &TokenStore{
 		activeContext:         ctx,
@@ -771,6 +825,8 @@ func NewTokenStore(ctx context.Context, logger log.Logger, core *Core, config *l
 		tidyLock:              new(uint32),
 		quitContext:           core.activeContext,
 		salts:                 make(map[string]*salt.Salt),
+		pendingUsage:          make(map[string]*tokenUsage),
+		pathRestrictionsCache: pathRestrictionsCache,
 	}
 
 	// Setup the framework endpoints
@@ -793,6 +849,7 @@ func NewTokenStore(ctx context.Context, logger log.Logger, core *Core, config *l
 			},
 		},
 		BackendType: logical.TypeCredential,
+		Clean:       t.stopTokenUsageFlusher,
 	}
 
 	t.Backend.Paths = append(t.Backend.Paths, t.paths()...)
@@ -803,6 +860,13 @@ func NewTokenStore(ctx context.Context, logger log.Logger, core *Core, config *l
 		return t, err
 	}
 
+	if t.quitContext != nil {
+		var usageCtx context.Context
+		usageCtx, t.usageCancel = context.WithCancel(t.quitContext)
+		t.usageDoneCh = make(chan struct{})
+		go t.runTokenUsageFlusher(usageCtx, t.usageDoneCh)
+	}
+
 	return t, nil
 }
 
@@ -893,6 +957,11 @@ type accessorEntry struct {
 	TokenID     string `json:"token_id"`
 	AccessorID  string `json:"accessor_id"`
 	NamespaceID string `json:"namespace_id"`
+
+	// LastUsedTime and UseCount are updated asynchronously from the usage of
+	// the token recorded in memory, see recordTokenUse
+	LastUsedTime int64  `json:"last_used_time,omitempty"`
+	UseCount     uint64 `json:"use_count,omitempty"`
 }
 
 // SetExpirationManager is used to provide the token store with
@@ -1060,6 +1129,17 @@ func (ts *TokenStore) create(ctx context.Context, entry *logical.TokenEntry) err
 		}
 	}
 
+	// Validate the path restrictions if they're set. They aren't part of the
+	// batch token format, so batch tokens cannot be restricted.
+	if len(entry.PathRestrictions) > 0 {
+		if entry.Type == logical.TokenTypeBatch {
+			return errors.New("batch tokens cannot have path restrictions")
+		}
+		if _, err := tokenPathRestrictionsPolicy(tokenNS, entry.PathRestrictions); err != nil {
+			return fmt.Errorf("failed to parse path restrictions for token entry: %v", err)
+		}
+	}
+
 	switch entry.Type {
 	case logical.TokenTypeDefault, logical.TokenTypeService:
 		// In case it was default, force to service
@@ -1386,6 +1466,8 @@ func (ts *TokenStore) UseToken(ctx context.Context, te *logical.TokenEntry) (*lo
 		return nil, fmt.Errorf("invalid token entry provided for use count decrementing")
 	}
 
+	ts.recordTokenUse(te)
+
 	// This case won't be hit with a token with restricted uses because we go
 	// from 1 to -1. So it's a nice optimization to check this without a read
 	// lock.
@@ -2647,6 +2729,8 @@ func (ts *TokenStore) handleCreateCommon(ctx context.Context, req *logical.Reque
 		Period          string
 		Type            string `mapstructure:"type"`
 		EntityAlias     string `mapstructure:"entity_alias"`
+
+		PathRestrictions map[string]interface{} `mapstructure:"path_restrictions"`
 	}
 	if err := mapstructure.WeakDecode(req.Data, &data); err != nil {
 		return logical.ErrorResponse(fmt.Sprintf(
@@ -3003,6 +3087,24 @@ func (ts *TokenStore) handleCreateCommon(ctx context.Context, req *logical.Reque
 		}
 	}
 
+	// Children of a token with path restrictions always inherit them, whether
+	// they are orphans or created from a role, so they can't be escaped
+	pathRestrictions, err := parseTokenPathRestrictions(data.PathRestrictions)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+	switch {
+	case len(parent.PathRestrictions) == 0:
+		te.PathRestrictions = pathRestrictions
+	case len(pathRestrictions) > 0 && !reflect.DeepEqual(pathRestrictions, parent.PathRestrictions):
+		return logical.ErrorResponse("tokens with path restrictions cannot create tokens with different path restrictions"), logical.ErrInvalidRequest
+	default:
+		te.PathRestrictions = parent.PathRestrictions
+	}
+	if len(te.PathRestrictions) > 0 && strutil.StrListContains(te.Policies, "root") {
+		return logical.ErrorResponse("root tokens cannot have path restrictions"), logical.ErrInvalidRequest
+	}
+
 	//
 	// NOTE: Do not modify policies below this line. We need the checks above
 	// to be the last checks as they must look at the final policy set.
@@ -3389,6 +3491,24 @@ func (ts *TokenStore) handleLookup(ctx context.Context, req *logical.Request, da
 		resp.Data["bound_cidrs"] = out.BoundCIDRs
 	}
 
+	if len(out.PathRestrictions) > 0 {
+		resp.Data["path_restrictions"] = out.PathRestrictions
+	}
+
+	if out.Accessor != "" {
+		aEntry, err := ts.lookupByAccessor(ctx, out.Accessor, false, true)
+		if err != nil {
+			return nil, err
+		}
+		if aEntry != nil {
+			lastUsed, uses := ts.tokenUsageInfo(aEntry)
+			resp.Data["use_count"] = uses
+			if !lastUsed.IsZero() {
+				resp.Data["last_used_time"] = lastUsed.Unix()
+			}
+		}
+	}
+
 	tokenNS, err := NamespaceByID(ctx, out.NamespaceID, ts.core)
 	if err != nil {
 		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
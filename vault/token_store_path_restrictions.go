@@ -0,0 +1,128 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// tokenPathRestrictionsSelfPolicy is always allowed on top of the path
+// restrictions of a token so that it can still look up, renew and revoke
+// itself, as long as its policies allow it too
+const tokenPathRestrictionsSelfPolicy = `
+path "auth/token/lookup-self" {
+    capabilities = ["read"]
+}
+
+path "auth/token/renew-self" {
+    capabilities = ["update"]
+}
+
+path "auth/token/revoke-self" {
+    capabilities = ["update"]
+}
+
+path "sys/capabilities-self" {
+    capabilities = ["update"]
+}
+`
+
+// parseTokenPathRestrictions parses the path_restrictions parameter of a token
+// creation request, mapping each request path to either a list or a comma
+// separated string of capabilities
+func parseTokenPathRestrictions(raw map[string]interface{}) (map[string][]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	restrictions := make(map[string][]string, len(raw))
+	for path, rawCapabilities := range raw {
+		path = strings.TrimPrefix(strings.TrimSpace(path), "/")
+		if path == "" {
+			return nil, errors.New("path restrictions cannot contain an empty path")
+		}
+
+		capabilities, err := parseutil.ParseCommaStringSlice(rawCapabilities)
+		if err != nil {
+			return nil, fmt.Errorf("invalid capabilities for path %q: %w", path, err)
+		}
+		if len(capabilities) == 0 {
+			return nil, fmt.Errorf("no capabilities given for path %q", path)
+		}
+		for i, capability := range capabilities {
+			capabilities[i] = strings.ToLower(strings.TrimSpace(capability))
+		}
+		sort.Strings(capabilities)
+
+		restrictions[path] = append(restrictions[path], capabilities...)
+	}
+
+	return restrictions, nil
+}
+
+// tokenPathRestrictionsPolicy returns the ACL policy granting the capabilities
+// of the given path restrictions in the given namespace
+func tokenPathRestrictionsPolicy(ns *namespace.Namespace, restrictions map[string][]string) (*Policy, error) {
+	paths := make(map[string]interface{}, len(restrictions))
+	for path, capabilities := range restrictions {
+		paths[path] = map[string]interface{}{
+			"capabilities": capabilities,
+		}
+	}
+
+	rules, err := json.Marshal(map[string]interface{}{
+		"path": paths,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := ParseACLPolicy(ns, string(rules))
+	if err != nil {
+		return nil, err
+	}
+	if policy.Templated {
+		return nil, errors.New("path restrictions cannot be templated")
+	}
+
+	return policy, nil
+}
+
+// applyTokenPathRestrictions limits the operations allowed by the ACL to the
+// ones allowed by the path restrictions of the token, if it has any. The path
+// restrictions of a token never change, so their ACL is cached by token ID.
+func (ts *TokenStore) applyTokenPathRestrictions(ctx context.Context, acl *ACL, tokenNS *namespace.Namespace, te *logical.TokenEntry) error {
+	if len(te.PathRestrictions) == 0 {
+		return nil
+	}
+
+	if raw, ok := ts.pathRestrictionsCache.Get(te.ID); ok {
+		acl.restrictions = raw.(*ACL)
+		return nil
+	}
+
+	policy, err := tokenPathRestrictionsPolicy(tokenNS, te.PathRestrictions)
+	if err != nil {
+		return fmt.Errorf("failed to parse the path restrictions of the token: %w", err)
+	}
+	selfPolicy, err := ParseACLPolicy(tokenNS, tokenPathRestrictionsSelfPolicy)
+	if err != nil {
+		return err
+	}
+
+	restrictions, err := NewACL(namespace.ContextWithNamespace(ctx, tokenNS), []*Policy{policy, selfPolicy})
+	if err != nil {
+		return err
+	}
+	acl.restrictions = restrictions
+	ts.pathRestrictionsCache.Add(te.ID, restrictions)
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenStore_PathRestrictions(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	policy, err := ParseACLPolicy(namespace.RootNamespace, `
+path "secret/*" {
+    capabilities = ["create", "read", "update", "list"]
+}
+path "auth/token/create" {
+    capabilities = ["update"]
+}`)
+	require.NoError(t, err)
+	policy.Name = "ci"
+	require.NoError(t, c.policyStore.SetPolicy(ctx, policy))
+
+	create := func(token string, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		req := logical.TestRequest(t, logical.UpdateOperation, "auth/token/create")
+		req.ClientToken = token
+		req.Data = data
+		return c.HandleRequest(ctx, req)
+	}
+
+	resp, err := create(root, map[string]interface{}{
+		"policies": []string{"default", "ci"},
+		"path_restrictions": map[string]interface{}{
+			"secret/data/ci/app1/*": []interface{}{"read"},
+			"cubbyhole/app1/*":      "create,read,update",
+			"auth/token/create":     "update",
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, resp.IsError(), resp.Error())
+	token := resp.Auth.ClientToken
+
+	te, err := c.tokenStore.Lookup(ctx, token)
+	require.NoError(t, err)
+	require.Equal(t, []string{"read"}, te.PathRestrictions["secret/data/ci/app1/*"])
+	require.Equal(t, []string{"create", "read", "update"}, te.PathRestrictions["cubbyhole/app1/*"])
+
+	// The capabilities are the intersection of the policies and the
+	// restrictions
+	for path, expected := range map[string][]string{
+		"secret/data/ci/app1/config": {"read"},
+		"secret/data/ci/app2/config": {"deny"},
+		"cubbyhole/app1/foo":         {"create", "read", "update"},
+		"cubbyhole/app2/foo":         {"deny"},
+		"sys/mounts":                 {"deny"},
+	} {
+		capabilities, err := c.Capabilities(ctx, token, path)
+		require.NoError(t, err)
+		require.Equal(t, expected, capabilities, path)
+	}
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "cubbyhole/app1/foo")
+	req.ClientToken = token
+	req.Data["value"] = "bar"
+	_, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "cubbyhole/app2/foo")
+	req.ClientToken = token
+	req.Data["value"] = "bar"
+	_, err = c.HandleRequest(ctx, req)
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+
+	// The token can still look itself up
+	req = logical.TestRequest(t, logical.ReadOperation, "auth/token/lookup-self")
+	req.ClientToken = token
+	resp, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, te.PathRestrictions, resp.Data["path_restrictions"])
+
+	// Children inherit the restrictions and can't change them
+	resp, err = create(token, map[string]interface{}{
+		"policies": []string{"ci"},
+	})
+	require.NoError(t, err)
+	child, err := c.tokenStore.Lookup(ctx, resp.Auth.ClientToken)
+	require.NoError(t, err)
+	require.Equal(t, te.PathRestrictions, child.PathRestrictions)
+
+	resp, err = create(token, map[string]interface{}{
+		"path_restrictions": map[string]interface{}{
+			"secret/*": "read",
+		},
+	})
+	require.Error(t, err)
+	require.True(t, resp.IsError())
+
+	// Batch and root tokens can't be restricted
+	for _, data := range []map[string]interface{}{
+		{"type": "batch", "policies": []string{"ci"}},
+		{"policies": []string{"root"}},
+		{"policies": []string{"ci"}, "path_restrictions": map[string]interface{}{"secret/*": "fly"}},
+	} {
+		if _, ok := data["path_restrictions"]; !ok {
+			data["path_restrictions"] = map[string]interface{}{"secret/*": "read"}
+		}
+		resp, err = create(root, data)
+		require.Error(t, err)
+		require.True(t, resp.IsError())
+	}
+}
+
+func TestACL_PathRestrictions(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	policy, err := ParseACLPolicy(namespace.RootNamespace, `
+path "secret/*" {
+    capabilities = ["read", "update", "sudo"]
+}`)
+	require.NoError(t, err)
+	acl, err := NewACL(ctx, []*Policy{policy})
+	require.NoError(t, err)
+
+	te := &logical.TokenEntry{
+		ID: "restricted",
+		PathRestrictions: map[string][]string{
+			"secret/app1/*": {"read", "update"},
+			"secret/app2/*": {"deny"},
+			"other/*":       {"read"},
+		},
+	}
+	require.NoError(t, c.tokenStore.applyTokenPathRestrictions(ctx, acl, namespace.RootNamespace, te))
+
+	// The ACL of the path restrictions is built once per token
+	other, err := NewACL(ctx, []*Policy{policy})
+	require.NoError(t, err)
+	require.NoError(t, c.tokenStore.applyTokenPathRestrictions(ctx, other, namespace.RootNamespace, te))
+	require.Same(t, acl.restrictions, other.restrictions)
+
+	for path, expected := range map[string][]string{
+		"secret/app1/foo": {"read", "update"},
+		"secret/app2/foo": {"deny"},
+		"secret/app3/foo": {"deny"},
+		"other/foo":       {"deny"},
+	} {
+		require.Equal(t, expected, acl.Capabilities(ctx, path), path)
+	}
+
+	req := &logical.Request{Operation: logical.UpdateOperation, Path: "secret/app1/foo"}
+	res := acl.AllowOperation(ctx, req, false)
+	require.True(t, res.Allowed)
+	require.False(t, res.RootPrivs)
+
+	req.Path = "secret/app3/foo"
+	res = acl.AllowOperation(ctx, req, false)
+	require.False(t, res.Allowed)
+	require.Equal(t, ACLDenialPathRestriction, res.DenialReason)
+}
@@ -2435,12 +2435,15 @@ func testTokenStoreHandleRequestLookup(t *testing.T, batch, periodic bool) {
 		"expire_time":      nil,
 		"entity_id":        "",
 		"type":             "service",
+		"use_count":        resp.Data["use_count"],
 	}
 
 	if resp.Data["creation_time"].(int64) == 0 {
 		t.Fatalf("creation time was zero")
 	}
 	delete(resp.Data, "creation_time")
+	// The root token may have been used while setting up the core
+	delete(resp.Data, "last_used_time")
 
 	if !reflect.DeepEqual(resp.Data, exp) {
 		t.Fatalf("bad: expected:%#v\nactual:%#v", exp, resp.Data)
@@ -2493,6 +2496,9 @@ func testTokenStoreHandleRequestLookup(t *testing.T, batch, periodic bool) {
 	if periodic {
 		exp["period"] = int64(3600)
 	}
+	if !batch {
+		exp["use_count"] = uint64(0)
+	}
 
 	if resp.Data["creation_time"].(int64) == 0 {
 		t.Fatalf("creation time was zero")
@@ -2580,6 +2586,7 @@ func TestTokenStore_HandleRequest_LookupSelf(t *testing.T) {
 		"explicit_max_ttl": int64(0),
 		"entity_id":        "",
 		"type":             "service",
+		"use_count":        uint64(0),
 	}
 
 	if resp.Data["creation_time"].(int64) == 0 {
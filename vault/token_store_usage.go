@@ -0,0 +1,312 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/helper/locksutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	// tokenUsageFlushInterval is how often the usage of tokens recorded in
+	// memory is written to their accessor entries. The last used time of a
+	// token is only as precise as this interval.
+	tokenUsageFlushInterval = time.Minute
+
+	// tokenUsageMaxPending bounds the number of accessors whose usage is kept
+	// in memory between two flushes. The usage of other tokens is dropped
+	// until the next flush.
+	tokenUsageMaxPending = 100000
+
+	// defaultStaleTokenUnusedFor is the time a token must not have been used
+	// for to be reported as stale when no duration is given
+	defaultStaleTokenUnusedFor = 30 * 24 * time.Hour
+)
+
+// tokenUsage is the usage of a token recorded since the last flush
+type tokenUsage struct {
+	namespaceID string
+	lastUsed    time.Time
+	uses        uint64
+}
+
+// recordTokenUse records the use of the token in memory. It is written to the
+// accessor entry of the token by the next flush, so that using a token
+// doesn't require a storage write.
+func (ts *TokenStore) recordTokenUse(te *logical.TokenEntry) {
+	// Batch tokens have no accessor to record their usage against
+	if te.Accessor == "" || te.Type == logical.TokenTypeBatch {
+		return
+	}
+
+	ts.usageLock.Lock()
+	defer ts.usageLock.Unlock()
+
+	usage, ok := ts.pendingUsage[te.Accessor]
+	if !ok {
+		if len(ts.pendingUsage) >= tokenUsageMaxPending {
+			return
+		}
+		usage = &tokenUsage{namespaceID: te.NamespaceID}
+		ts.pendingUsage[te.Accessor] = usage
+	}
+	usage.lastUsed = time.Now()
+	usage.uses++
+}
+
+// pendingTokenUsage returns a copy of the usage of the token recorded since
+// the last flush, or nil if there is none
+func (ts *TokenStore) pendingTokenUsage(accessor string) *tokenUsage {
+	ts.usageLock.Lock()
+	defer ts.usageLock.Unlock()
+
+	usage, ok := ts.pendingUsage[accessor]
+	if !ok {
+		return nil
+	}
+	ret := *usage
+	return &ret
+}
+
+// runTokenUsageFlusher periodically writes the recorded usage of tokens until
+// the context is canceled
+func (ts *TokenStore) runTokenUsageFlusher(ctx context.Context, doneCh chan struct{}) {
+	defer close(doneCh)
+
+	ticker := time.NewTicker(tokenUsageFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ts.flushTokenUsage(ctx)
+		}
+	}
+}
+
+// stopTokenUsageFlusher stops the periodic flush of the usage of tokens and
+// writes the usage recorded since the last one
+func (ts *TokenStore) stopTokenUsageFlusher(ctx context.Context) {
+	if ts.usageCancel == nil {
+		return
+	}
+	ts.usageCancel()
+	<-ts.usageDoneCh
+	ts.usageCancel = nil
+
+	ts.flushTokenUsage(ctx)
+}
+
+// flushTokenUsage writes the usage of tokens recorded since the last flush to
+// their accessor entries
+func (ts *TokenStore) flushTokenUsage(ctx context.Context) {
+	ts.usageLock.Lock()
+	pending := ts.pendingUsage
+	ts.pendingUsage = make(map[string]*tokenUsage, len(pending))
+	ts.usageLock.Unlock()
+
+	for accessor, usage := range pending {
+		if err := ts.writeTokenUsage(ctx, accessor, usage); err != nil {
+			ts.logger.Warn("failed to record token usage", "accessor", accessor, "error", err)
+		}
+	}
+}
+
+// writeTokenUsage adds the given usage to the accessor entry of a token
+func (ts *TokenStore) writeTokenUsage(ctx context.Context, accessor string, usage *tokenUsage) error {
+	tokenNS, err := NamespaceByID(ctx, usage.namespaceID, ts.core)
+	if err != nil {
+		return err
+	}
+	if tokenNS == nil {
+		return namespace.ErrNoNamespace
+	}
+	nsCtx := namespace.ContextWithNamespace(ctx, tokenNS)
+
+	saltID, err := ts.SaltID(nsCtx, accessor)
+	if err != nil {
+		return err
+	}
+
+	readAccessorEntry := func() (*accessorEntry, error) {
+		entry, err := ts.accessorView(tokenNS).Get(nsCtx, saltID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read accessor index entry: %w", err)
+		}
+		// The token has been revoked since it was used
+		if entry == nil {
+			return nil, nil
+		}
+
+		var aEntry accessorEntry
+		if err := jsonutil.DecodeJSON(entry.Value, &aEntry); err != nil {
+			// Pre-struct accessor entries only hold the token ID, so leave
+			// them alone
+			return nil, nil
+		}
+		return &aEntry, nil
+	}
+
+	aEntry, err := readAccessorEntry()
+	if err != nil || aEntry == nil {
+		return err
+	}
+
+	// Hold the token lock while updating the accessor entry, so that it
+	// isn't brought back while the token is being revoked
+	lock := locksutil.LockForKey(ts.tokenLocks, aEntry.TokenID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	aEntry, err = readAccessorEntry()
+	if err != nil || aEntry == nil {
+		return err
+	}
+
+	te, err := ts.lookupInternal(nsCtx, aEntry.TokenID, false, false)
+	if err != nil {
+		return err
+	}
+	if te == nil {
+		return nil
+	}
+
+	if lastUsed := usage.lastUsed.Unix(); lastUsed > aEntry.LastUsedTime {
+		aEntry.LastUsedTime = lastUsed
+	}
+	aEntry.UseCount += usage.uses
+
+	aEntryBytes, err := jsonutil.EncodeJSON(aEntry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal accessor index entry: %w", err)
+	}
+	if err := ts.accessorView(tokenNS).Put(nsCtx, &logical.StorageEntry{Key: saltID, Value: aEntryBytes}); err != nil {
+		return fmt.Errorf("failed to persist accessor index entry: %w", err)
+	}
+	return nil
+}
+
+// tokenUsageInfo returns the last used time and the use count of the token
+// with the given accessor entry, including the usage not flushed yet
+func (ts *TokenStore) tokenUsageInfo(aEntry *accessorEntry) (time.Time, uint64) {
+	var lastUsed time.Time
+	if aEntry.LastUsedTime != 0 {
+		lastUsed = time.Unix(aEntry.LastUsedTime, 0)
+	}
+	uses := aEntry.UseCount
+
+	if usage := ts.pendingTokenUsage(aEntry.AccessorID); usage != nil {
+		if usage.lastUsed.After(lastUsed) {
+			lastUsed = usage.lastUsed
+		}
+		uses += usage.uses
+	}
+
+	return lastUsed, uses
+}
+
+// handleStaleAccessors lists the accessors of the tokens of the namespace
+// that haven't been used for the given duration, or since their creation if
+// they were never used
+func (ts *TokenStore) handleStaleAccessors(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unusedFor := time.Duration(d.Get("unused_for").(int)) * time.Second
+	if unusedFor <= 0 {
+		return logical.ErrorResponse("unused_for must be positive"), logical.ErrInvalidRequest
+	}
+	cutoff := time.Now().Add(-unusedFor)
+
+	entries, err := ts.accessorView(ns).List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &logical.Response{}
+	type staleToken struct {
+		accessor string
+		since    time.Time
+		info     map[string]interface{}
+	}
+	stale := make([]*staleToken, 0)
+	for _, entry := range entries {
+		aEntry, err := ts.lookupByAccessor(ctx, entry, true, false)
+		if err != nil {
+			resp.AddWarning(fmt.Sprintf("Found an accessor entry that could not be successfully decoded; associated error is %q", err.Error()))
+			continue
+		}
+		if aEntry == nil || aEntry.TokenID == "" || aEntry.NamespaceID != ns.ID {
+			continue
+		}
+
+		te, err := ts.lookupInternal(ctx, aEntry.TokenID, false, false)
+		if err != nil {
+			return nil, err
+		}
+		if te == nil {
+			continue
+		}
+
+		lastUsed, uses := ts.tokenUsageInfo(aEntry)
+		since := lastUsed
+		if since.IsZero() {
+			since = time.Unix(te.CreationTime, 0)
+		}
+		if !since.Before(cutoff) {
+			continue
+		}
+
+		info := map[string]interface{}{
+			"display_name":   te.DisplayName,
+			"policies":       te.Policies,
+			"creation_time":  te.CreationTime,
+			"last_used_time": nil,
+			"use_count":      uses,
+			"orphan":         te.Parent == "",
+		}
+		if !lastUsed.IsZero() {
+			info["last_used_time"] = lastUsed.Unix()
+		}
+		stale = append(stale, &staleToken{
+			accessor: aEntry.AccessorID,
+			since:    since,
+			info:     info,
+		})
+	}
+
+	// Report the tokens unused for the longest time first
+	sort.SliceStable(stale, func(i, j int) bool {
+		return stale[i].since.Before(stale[j].since)
+	})
+
+	keys := make([]string, 0, len(stale))
+	keyInfo := make(map[string]interface{}, len(stale))
+	for _, token := range stale {
+		keys = append(keys, token.accessor)
+		keyInfo[token.accessor] = token.info
+	}
+	resp.Data = map[string]interface{}{
+		"keys":     keys,
+		"key_info": keyInfo,
+	}
+	return resp, nil
+}
+
+const tokenStaleAccessorsHelp = `
+This endpoint lists the accessors of the tokens that haven't been used for
+the given duration, or since their creation if they were never used, along
+with their last used time and use count. The usage of tokens is recorded
+asynchronously, so it is only precise to about a minute. This requires sudo
+capability.
+`
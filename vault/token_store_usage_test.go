@@ -0,0 +1,109 @@
+package vault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenStore_TokenUsage(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ts := c.tokenStore
+	ctx := namespace.RootContext(nil)
+
+	testMakeServiceTokenViaCore(t, c, root, "client", "", []string{"default"})
+	for i := 0; i < 3; i++ {
+		req := logical.TestRequest(t, logical.ReadOperation, "auth/token/lookup-self")
+		req.ClientToken = "client"
+		_, err := c.HandleRequest(ctx, req)
+		require.NoError(t, err)
+	}
+
+	lookup := func() *logical.Response {
+		t.Helper()
+		req := logical.TestRequest(t, logical.UpdateOperation, "auth/token/lookup")
+		req.ClientToken = root
+		req.Data["token"] = "client"
+		resp, err := c.HandleRequest(ctx, req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	// The usage that isn't flushed yet is reported
+	resp := lookup()
+	require.Equal(t, uint64(3), resp.Data["use_count"])
+	require.NotZero(t, resp.Data["last_used_time"])
+	accessor := resp.Data["accessor"].(string)
+
+	ts.flushTokenUsage(ctx)
+	require.Nil(t, ts.pendingTokenUsage(accessor))
+
+	aEntry, err := ts.lookupByAccessor(ctx, accessor, false, false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), aEntry.UseCount)
+	require.NotZero(t, aEntry.LastUsedTime)
+	require.Equal(t, uint64(3), lookup().Data["use_count"])
+
+	// Recording the usage of a revoked token doesn't bring back its accessor
+	te, err := ts.Lookup(ctx, "client")
+	require.NoError(t, err)
+	ts.recordTokenUse(te)
+	require.NoError(t, ts.revokeOrphan(ctx, "client"))
+	ts.flushTokenUsage(ctx)
+	aEntry, err = ts.lookupByAccessor(ctx, accessor, false, false)
+	require.NoError(t, err)
+	require.Nil(t, aEntry)
+}
+
+func TestTokenStore_StaleAccessors(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ts := c.tokenStore
+	ctx := namespace.RootContext(nil)
+
+	oldToken := &logical.TokenEntry{
+		ID:           "old-unused",
+		Path:         "auth/token/create",
+		Policies:     []string{"default"},
+		TTL:          72 * time.Hour,
+		CreationTime: time.Now().Add(-48 * time.Hour).Unix(),
+	}
+	testMakeTokenDirectly(t, ts, oldToken)
+	oldUsedToken := &logical.TokenEntry{
+		ID:           "old-used",
+		Path:         "auth/token/create",
+		Policies:     []string{"default"},
+		TTL:          72 * time.Hour,
+		CreationTime: time.Now().Add(-48 * time.Hour).Unix(),
+	}
+	testMakeTokenDirectly(t, ts, oldUsedToken)
+	newToken := &logical.TokenEntry{
+		ID:       "new-unused",
+		Path:     "auth/token/create",
+		Policies: []string{"default"},
+		TTL:      72 * time.Hour,
+	}
+	testMakeTokenDirectly(t, ts, newToken)
+
+	ts.recordTokenUse(oldUsedToken)
+	ts.flushTokenUsage(ctx)
+
+	req := logical.TestRequest(t, logical.ReadOperation, "auth/token/accessors/stale")
+	req.ClientToken = root
+	req.Data["unused_for"] = "24h"
+	resp, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, []string{oldToken.Accessor}, resp.Data["keys"])
+
+	info := resp.Data["key_info"].(map[string]interface{})[oldToken.Accessor].(map[string]interface{})
+	require.Equal(t, oldToken.CreationTime, info["creation_time"])
+	require.Nil(t, info["last_used_time"])
+	require.Equal(t, uint64(0), info["use_count"])
+	require.Equal(t, true, info["orphan"])
+
+	req.Data["unused_for"] = "0s"
+	_, err = c.HandleRequest(ctx, req)
+	require.Error(t, err)
+}
@@ -7,14 +7,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
 	"github.com/hashicorp/vault/helper/metricsutil"
 	"github.com/hashicorp/vault/helper/namespace"
 	"github.com/hashicorp/vault/sdk/helper/certutil"
+	"github.com/hashicorp/vault/sdk/helper/cidrutil"
 	"github.com/hashicorp/vault/sdk/helper/consts"
 	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/helper/locksutil"
 	"github.com/hashicorp/vault/sdk/logical"
 	"gopkg.in/square/go-jose.v2"
 	squarejwt "gopkg.in/square/go-jose.v2/jwt"
@@ -23,6 +28,17 @@ import (
 const (
 	// The location of the key used to generate response-wrapping JWTs
 	coreWrappingJWTKeyPath = "core/wrapping/jwtkey"
+
+	// The keys of the internal metadata of wrapping tokens holding the uses
+	// and bindings requested when wrapping
+	wrappingMaxUsesMetaKey          = "wrapping_max_uses"
+	wrappingBoundEntityIDMetaKey    = "wrapping_bound_entity_id"
+	wrappingBoundAppRoleRoleMetaKey = "wrapping_bound_approle_role"
+	wrappingBoundCIDRsMetaKey       = "wrapping_bound_cidrs"
+
+	// maxWrappingMisuseAttempts is the number of the latest misuse attempts
+	// kept in the wrapping information of a wrapping token
+	maxWrappingMisuseAttempts = 10
 )
 
 func (c *Core) ensureWrappingKey(ctx context.Context) error {
@@ -140,6 +156,27 @@ DONELISTHANDLING:
 		ExplicitMaxTTL: resp.WrapInfo.TTL,
 		NamespaceID:    ns.ID,
 	}
+	wrapInfo := req.WrapInfo
+	if len(resp.WrapInfo.BoundCIDRs) > 0 {
+		// The backend restricts where the response can be unwrapped from, and
+		// the CIDRs requested by the caller can only narrow that down
+		bound := &logical.RequestWrapInfo{}
+		if wrapInfo != nil {
+			*bound = *wrapInfo
+		}
+		if len(bound.BoundCIDRs) == 0 {
+			bound.BoundCIDRs = resp.WrapInfo.BoundCIDRs
+		} else if subset, err := cidrutil.SubsetBlocks(resp.WrapInfo.BoundCIDRs, bound.BoundCIDRs); err != nil || !subset {
+			return logical.ErrorResponse("requested wrapping bound CIDRs are not within the CIDRs the response is bound to"), logical.ErrInvalidRequest
+		}
+		wrapInfo = bound
+	}
+	if wrapInfo != nil {
+		if wrapInfo.Uses > 1 {
+			te.NumUses = wrapInfo.Uses
+		}
+		te.InternalMeta = wrappingTokenOptions(wrapInfo)
+	}
 
 	if err := c.CreateToken(ctx, &te); err != nil {
 		c.logger.Error("failed to create wrapping token", "error", err)
@@ -449,6 +486,28 @@ func (c *Core) validateWrappingToken(ctx context.Context, req *logical.Request)
 		return false, nil
 	}
 
+	switch req.Path {
+	case "sys/wrapping/rewrap":
+		// Rewrapping doesn't carry the uses and bindings of the token over to
+		// the new one
+		if te.InternalMeta[wrappingMaxUsesMetaKey] != "" || isBoundWrappingToken(te) {
+			return false, fmt.Errorf("%w: wrapping tokens with multiple uses or bound to a recipient cannot be rewrapped", logical.ErrInvalidRequest)
+		}
+	case "sys/wrapping/unwrap":
+		// Check the recipient before the token is used, so that misuse
+		// attempts don't consume the unwraps of the intended recipients
+		reason, err := c.checkWrappingTokenBindings(ctx, req, te, thirdParty)
+		if err != nil {
+			return false, err
+		}
+		if reason != "" {
+			if err := c.recordWrappingTokenMisuse(ctx, req, te, reason); err != nil {
+				c.logger.Error("failed to record wrapping token misuse", "error", err)
+			}
+			return false, nil
+		}
+	}
+
 	if !thirdParty {
 		req.ClientTokenAccessor = te.Accessor
 		req.ClientTokenRemainingUses = te.NumUses
@@ -458,6 +517,154 @@ func (c *Core) validateWrappingToken(ctx context.Context, req *logical.Request)
 	return true, nil
 }
 
+// wrappingTokenOptions returns the internal metadata of a wrapping token
+// holding the uses and bindings requested in the given wrap info
+func wrappingTokenOptions(wrapInfo *logical.RequestWrapInfo) map[string]string {
+	options := make(map[string]string)
+	if wrapInfo.Uses > 1 {
+		options[wrappingMaxUsesMetaKey] = strconv.Itoa(wrapInfo.Uses)
+	}
+	if wrapInfo.BoundEntityID != "" {
+		options[wrappingBoundEntityIDMetaKey] = wrapInfo.BoundEntityID
+	}
+	if wrapInfo.BoundAppRoleRole != "" {
+		options[wrappingBoundAppRoleRoleMetaKey] = wrapInfo.BoundAppRoleRole
+	}
+	if len(wrapInfo.BoundCIDRs) > 0 {
+		options[wrappingBoundCIDRsMetaKey] = strings.Join(wrapInfo.BoundCIDRs, ",")
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	return options
+}
+
+// isBoundWrappingToken returns whether the wrapping token can only be
+// unwrapped by a given recipient
+func isBoundWrappingToken(te *logical.TokenEntry) bool {
+	return te.InternalMeta[wrappingBoundEntityIDMetaKey] != "" ||
+		te.InternalMeta[wrappingBoundAppRoleRoleMetaKey] != "" ||
+		te.InternalMeta[wrappingBoundCIDRsMetaKey] != ""
+}
+
+// checkWrappingTokenBindings checks that the request is allowed to unwrap the
+// wrapping token, returning the reason why it isn't if so
+func (c *Core) checkWrappingTokenBindings(ctx context.Context, req *logical.Request, te *logical.TokenEntry, thirdParty bool) (string, error) {
+	if !isBoundWrappingToken(te) {
+		return "", nil
+	}
+
+	if boundCIDRs := te.InternalMeta[wrappingBoundCIDRsMetaKey]; boundCIDRs != "" {
+		cidrs, err := parseutil.ParseAddrs(strings.Split(boundCIDRs, ","))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse the bound CIDRs of the wrapping token: %w", err)
+		}
+		if req.Connection == nil || !cidrutil.RemoteAddrIsOk(req.Connection.RemoteAddr, cidrs) {
+			return "remote address is not in the bound CIDRs", nil
+		}
+	}
+
+	boundEntityID := te.InternalMeta[wrappingBoundEntityIDMetaKey]
+	boundRole := te.InternalMeta[wrappingBoundAppRoleRoleMetaKey]
+	if boundEntityID == "" && boundRole == "" {
+		return "", nil
+	}
+
+	// The recipient is identified by its own token, so the wrapping token has
+	// to be given in the request body
+	if !thirdParty || req.ClientToken == "" {
+		return "wrapping token bound to a recipient was not unwrapped with a token of the recipient", nil
+	}
+	callerTE, err := c.tokenStore.Lookup(ctx, req.ClientToken)
+	if err != nil {
+		return "", err
+	}
+	if callerTE == nil {
+		return "token of the recipient is invalid", nil
+	}
+
+	if boundEntityID != "" && callerTE.EntityID != boundEntityID {
+		return "token does not belong to the bound entity", nil
+	}
+
+	if boundRole != "" {
+		if callerTE.Meta["role_name"] != boundRole {
+			return "token was not issued for the bound AppRole role", nil
+		}
+		callerNS, err := NamespaceByID(ctx, callerTE.NamespaceID, c)
+		if err != nil {
+			return "", err
+		}
+		if callerNS == nil {
+			return "token of the recipient is invalid", nil
+		}
+		mountEntry := c.router.MatchingMountEntry(namespace.ContextWithNamespace(ctx, callerNS), callerTE.Path)
+		if mountEntry == nil || mountEntry.Type != "approle" {
+			return "token was not issued by an AppRole auth method", nil
+		}
+	}
+
+	return "", nil
+}
+
+// recordWrappingTokenMisuse adds an attempt to unwrap the wrapping token by
+// someone other than its recipient to its wrapping information, so that it
+// is reported by sys/wrapping/lookup
+func (c *Core) recordWrappingTokenMisuse(ctx context.Context, req *logical.Request, te *logical.TokenEntry, reason string) error {
+	tokenNS, err := NamespaceByID(ctx, te.NamespaceID, c)
+	if err != nil {
+		return err
+	}
+	if tokenNS == nil {
+		return namespace.ErrNoNamespace
+	}
+	tokenCtx := namespace.ContextWithNamespace(ctx, tokenNS)
+
+	attempt := map[string]interface{}{
+		"time":   time.Now().Format(time.RFC3339Nano),
+		"reason": reason,
+	}
+	if req.Connection != nil {
+		attempt["remote_address"] = req.Connection.RemoteAddr
+	}
+	if req.ClientToken != "" && req.ClientToken != te.ID {
+		if callerTE, err := c.tokenStore.Lookup(ctx, req.ClientToken); err == nil && callerTE != nil {
+			attempt["entity_id"] = callerTE.EntityID
+			attempt["accessor"] = callerTE.Accessor
+		}
+	}
+
+	lock := locksutil.LockForKey(c.tokenStore.tokenLocks, te.ID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	cubbyReq := &logical.Request{
+		Operation:   logical.ReadOperation,
+		Path:        "cubbyhole/wrapinfo",
+		ClientToken: te.ID,
+	}
+	cubbyReq.SetTokenEntry(te)
+	cubbyResp, err := c.router.Route(tokenCtx, cubbyReq)
+	if err != nil {
+		return err
+	}
+	if cubbyResp == nil || cubbyResp.Data == nil {
+		return errors.New("no wrapping information found")
+	}
+
+	attempts, _ := cubbyResp.Data["misuse_attempts"].([]interface{})
+	attempts = append(attempts, attempt)
+	if len(attempts) > maxWrappingMisuseAttempts {
+		attempts = attempts[len(attempts)-maxWrappingMisuseAttempts:]
+	}
+	cubbyResp.Data["misuse_attempts"] = attempts
+
+	cubbyReq.Operation = logical.UpdateOperation
+	cubbyReq.Data = cubbyResp.Data
+	_, err = c.router.Route(tokenCtx, cubbyReq)
+	return err
+}
+
 func IsWrappingToken(te *logical.TokenEntry) bool {
 	if len(te.Policies) != 1 {
 		return false